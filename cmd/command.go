@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/apache/answer/internal/base/conf"
+	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/path"
 	"github.com/apache/answer/internal/cli"
 	"github.com/apache/answer/internal/install"
@@ -40,6 +41,8 @@ var (
 	dataDirPath string
 	// dumpDataPath dump data path
 	dumpDataPath string
+	// exportDataPath export archive path
+	exportDataPath string
 	// place to build new answer
 	buildDir string
 	// plugins needed to build in answer application
@@ -68,6 +71,8 @@ func init() {
 
 	dumpCmd.Flags().StringVarP(&dumpDataPath, "path", "p", "./", "dump data path, eg: -p ./dump/data/")
 
+	exportCmd.Flags().StringVarP(&exportDataPath, "path", "p", "./", "export archive path, eg: -p ./export/")
+
 	buildCmd.Flags().StringSliceVarP(&buildWithPlugins, "with", "w", []string{}, "plugins needed to build")
 
 	buildCmd.Flags().StringVarP(&buildOutput, "output", "o", "", "build output path")
@@ -85,7 +90,9 @@ func init() {
 	resetPasswordCmd.Flags().StringVarP(&resetPasswordEmail, "email", "e", "", "user email address")
 	resetPasswordCmd.Flags().StringVarP(&resetPasswordPassword, "password", "p", "", "new password (not recommended, will be recorded in shell history)")
 
-	for _, cmd := range []*cobra.Command{initCmd, checkCmd, runCmd, dumpCmd, upgradeCmd, buildCmd, pluginCmd, configCmd, i18nCmd, resetPasswordCmd} {
+	importCmd.AddCommand(importStackExchangeCmd)
+
+	for _, cmd := range []*cobra.Command{initCmd, checkCmd, runCmd, dumpCmd, exportCmd, importCmd, upgradeCmd, buildCmd, pluginCmd, configCmd, i18nCmd, resetPasswordCmd} {
 		rootCmd.AddCommand(cmd)
 	}
 }
@@ -183,6 +190,85 @@ To run answer, use:
 		},
 	}
 
+	exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export site",
+		Long:  `Export the database and uploaded files into a portable archive, which can be imported into any supported database`,
+		Run: func(_ *cobra.Command, _ []string) {
+			fmt.Println("Answer is exporting the site")
+			constant.Version = Version
+			path.FormatAllPath(dataDirPath)
+			c, err := conf.ReadConfig(path.GetConfigFilePath())
+			if err != nil {
+				fmt.Println("read config failed: ", err.Error())
+				return
+			}
+			name, err := cli.ExportSite(c.Data.Database, c.ServiceConfig.UploadPath, exportDataPath)
+			if err != nil {
+				fmt.Println("export failed: ", err.Error())
+				return
+			}
+			fmt.Println("Answer exported the site successfully: ", name)
+		},
+	}
+
+	importCmd = &cobra.Command{
+		Use:   "import [archive]",
+		Short: "Import data",
+		Long: `Restore the site from an archive created by 'answer export', or import data from other Q&A platforms.
+The archive must be imported into an empty database configured in the config file, the pending migrations run after restoring.`,
+		Example: `  answer import -C ./answer-data ./answer_export_2024-01-01.zip`,
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				_ = cmd.Help()
+				return
+			}
+			log.SetLogger(log.NewStdLogger(os.Stdout))
+			path.FormatAllPath(dataDirPath)
+			c, err := conf.ReadConfig(path.GetConfigFilePath())
+			if err != nil {
+				fmt.Println("read config failed: ", err.Error())
+				return
+			}
+			err = cli.ImportSite(c.Debug, c.Data.Database, c.Data.Cache, c.ServiceConfig.UploadPath, args[0])
+			if err != nil {
+				fmt.Println("import failed: ", err.Error())
+				return
+			}
+			fmt.Println("Answer imported the site successfully.")
+		},
+	}
+
+	importStackExchangeCmd = &cobra.Command{
+		Use:   "stackexchange <dir>",
+		Short: "Import Stack Exchange data dump",
+		Long: `Import users, tags, questions, answers, comments and votes from the XML files of the Stack Exchange data dump.
+The import can be run again to resume after interruption, the imported data is skipped.
+The progress and the sorted post history are kept in the cache directory of the data directory, the dump directory is only read.`,
+		Example: `  answer import stackexchange -C ./answer-data ./dump/`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			path.FormatAllPath(dataDirPath)
+			c, err := conf.ReadConfig(path.GetConfigFilePath())
+			if err != nil {
+				fmt.Println("read config failed: ", err.Error())
+				return
+			}
+			importerService, cleanup, err := initImporter(c.Debug, c.Data.Database, c.Data.Cache, c.ServiceConfig)
+			if err != nil {
+				fmt.Println("init importer failed: ", err.Error())
+				return
+			}
+			defer cleanup()
+			err = cli.ImportStackExchange(context.Background(), importerService.NewImporterFunc("stackexchange"), args[0], path.CacheDir)
+			if err != nil {
+				fmt.Println("import failed: ", err.Error())
+				return
+			}
+		},
+	}
+
 	checkCmd = &cobra.Command{
 		Use:   "check",
 		Short: "Check the required environment",
@@ -36,6 +36,7 @@ import (
 	"github.com/apache/answer/internal/repo"
 	"github.com/apache/answer/internal/router"
 	"github.com/apache/answer/internal/service"
+	"github.com/apache/answer/internal/service/importer"
 	"github.com/apache/answer/internal/service/service_config"
 	"github.com/google/wire"
 	"github.com/segmentfault/pacman"
@@ -67,3 +68,15 @@ func initApplication(
 		newApplication,
 	))
 }
+
+// initImporter init the importer service for the import command.
+func initImporter(
+	debug bool,
+	dbConf *data.Database,
+	cacheConf *data.CacheConf,
+	serviceConf *service_config.ServiceConfig) (*importer.ImporterService, func(), error) {
+	panic(wire.Build(
+		service.ProviderSetService,
+		repo.ProviderSetRepo,
+	))
+}
@@ -45,12 +45,17 @@ import (
 	"github.com/apache/answer/internal/repo/badge"
 	"github.com/apache/answer/internal/repo/badge_award"
 	"github.com/apache/answer/internal/repo/badge_group"
+	"github.com/apache/answer/internal/repo/bounty"
 	"github.com/apache/answer/internal/repo/captcha"
+	"github.com/apache/answer/internal/repo/close_vote"
 	"github.com/apache/answer/internal/repo/collection"
 	"github.com/apache/answer/internal/repo/comment"
 	"github.com/apache/answer/internal/repo/config"
+	"github.com/apache/answer/internal/repo/draft"
 	"github.com/apache/answer/internal/repo/export"
+	"github.com/apache/answer/internal/repo/feed"
 	"github.com/apache/answer/internal/repo/file_record"
+	"github.com/apache/answer/internal/repo/importer"
 	"github.com/apache/answer/internal/repo/limit"
 	"github.com/apache/answer/internal/repo/meta"
 	notification2 "github.com/apache/answer/internal/repo/notification"
@@ -62,12 +67,21 @@ import (
 	"github.com/apache/answer/internal/repo/review"
 	"github.com/apache/answer/internal/repo/revision"
 	"github.com/apache/answer/internal/repo/role"
+	"github.com/apache/answer/internal/repo/saved_search"
+	"github.com/apache/answer/internal/repo/scheduled_post"
+	"github.com/apache/answer/internal/repo/scim"
 	"github.com/apache/answer/internal/repo/search_common"
 	"github.com/apache/answer/internal/repo/site_info"
+	"github.com/apache/answer/internal/repo/space"
+	"github.com/apache/answer/internal/repo/sso"
 	"github.com/apache/answer/internal/repo/tag"
 	"github.com/apache/answer/internal/repo/tag_common"
+	"github.com/apache/answer/internal/repo/tag_suggestion"
+	"github.com/apache/answer/internal/repo/tenant"
+	"github.com/apache/answer/internal/repo/two_factor"
 	"github.com/apache/answer/internal/repo/unique"
 	"github.com/apache/answer/internal/repo/user"
+	"github.com/apache/answer/internal/repo/user_data"
 	"github.com/apache/answer/internal/repo/user_external_login"
 	"github.com/apache/answer/internal/repo/user_notification_config"
 	"github.com/apache/answer/internal/router"
@@ -80,6 +94,8 @@ import (
 	"github.com/apache/answer/internal/service/apikey"
 	auth2 "github.com/apache/answer/internal/service/auth"
 	badge2 "github.com/apache/answer/internal/service/badge"
+	bounty2 "github.com/apache/answer/internal/service/bounty"
+	close_vote2 "github.com/apache/answer/internal/service/close_vote"
 	collection2 "github.com/apache/answer/internal/service/collection"
 	"github.com/apache/answer/internal/service/collection_common"
 	comment2 "github.com/apache/answer/internal/service/comment"
@@ -87,13 +103,15 @@ import (
 	config2 "github.com/apache/answer/internal/service/config"
 	"github.com/apache/answer/internal/service/content"
 	"github.com/apache/answer/internal/service/dashboard"
+	draft2 "github.com/apache/answer/internal/service/draft"
 	"github.com/apache/answer/internal/service/embedding"
 	"github.com/apache/answer/internal/service/eventqueue"
 	export2 "github.com/apache/answer/internal/service/export"
 	"github.com/apache/answer/internal/service/feature_toggle"
+	feed2 "github.com/apache/answer/internal/service/feed"
 	file_record2 "github.com/apache/answer/internal/service/file_record"
 	"github.com/apache/answer/internal/service/follow"
-	"github.com/apache/answer/internal/service/importer"
+	importer2 "github.com/apache/answer/internal/service/importer"
 	meta2 "github.com/apache/answer/internal/service/meta"
 	"github.com/apache/answer/internal/service/meta_common"
 	"github.com/apache/answer/internal/service/noticequeue"
@@ -109,17 +127,29 @@ import (
 	review2 "github.com/apache/answer/internal/service/review"
 	"github.com/apache/answer/internal/service/revision_common"
 	role2 "github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/role_admin"
+	saved_search2 "github.com/apache/answer/internal/service/saved_search"
+	scheduled_post2 "github.com/apache/answer/internal/service/scheduled_post"
+	scim2 "github.com/apache/answer/internal/service/scim"
 	"github.com/apache/answer/internal/service/search_parser"
 	"github.com/apache/answer/internal/service/service_config"
 	"github.com/apache/answer/internal/service/siteinfo"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	space2 "github.com/apache/answer/internal/service/space"
+	"github.com/apache/answer/internal/service/space_common"
+	sso2 "github.com/apache/answer/internal/service/sso"
 	tag2 "github.com/apache/answer/internal/service/tag"
 	tag_common2 "github.com/apache/answer/internal/service/tag_common"
+	tag_suggestion2 "github.com/apache/answer/internal/service/tag_suggestion"
+	tenant2 "github.com/apache/answer/internal/service/tenant"
+	two_factor2 "github.com/apache/answer/internal/service/two_factor"
 	"github.com/apache/answer/internal/service/uploader"
 	"github.com/apache/answer/internal/service/user_admin"
 	"github.com/apache/answer/internal/service/user_common"
+	user_data2 "github.com/apache/answer/internal/service/user_data"
 	user_external_login2 "github.com/apache/answer/internal/service/user_external_login"
 	user_notification_config2 "github.com/apache/answer/internal/service/user_notification_config"
+	"github.com/apache/answer/internal/service/user_session"
 	"github.com/apache/answer/internal/service/vector_sync"
 	"github.com/segmentfault/pacman"
 	"github.com/segmentfault/pacman/log"
@@ -138,15 +168,21 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	if err != nil {
 		return nil, nil, err
 	}
-	cache, cleanup, err := data.NewCache(cacheConf)
+	invalidationBus, cleanup, err := data.NewInvalidationBus(cacheConf)
 	if err != nil {
 		return nil, nil, err
 	}
-	dataData, cleanup2, err := data.NewData(engine, cache)
+	cache, cleanup2, err := data.NewCacheWithBus(cacheConf, invalidationBus)
 	if err != nil {
 		cleanup()
 		return nil, nil, err
 	}
+	dataData, cleanup3, err := data.NewData(engine, cache, invalidationBus)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
 	siteInfoRepo := site_info.NewSiteInfo(dataData)
 	siteInfoCommonService := siteinfo_common.NewSiteInfoCommonService(siteInfoRepo)
 	langController := controller.NewLangController(i18nTranslator, siteInfoCommonService)
@@ -170,7 +206,9 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	userExternalLoginRepo := user_external_login.NewUserExternalLoginRepo(dataData)
 	userNotificationConfigRepo := user_notification_config.NewUserNotificationConfigRepo(dataData)
 	userNotificationConfigService := user_notification_config2.NewUserNotificationConfigService(userRepo, userNotificationConfigRepo)
-	userExternalLoginService := user_external_login2.NewUserExternalLoginService(userRepo, userCommon, userExternalLoginRepo, emailService, siteInfoCommonService, userActiveActivityRepo, userNotificationConfigService)
+	twoFactorRepo := two_factor.NewTwoFactorRepo(dataData)
+	twoFactorService := two_factor2.NewTwoFactorService(twoFactorRepo, userRepo, userRoleRelService, siteInfoCommonService)
+	userExternalLoginService := user_external_login2.NewUserExternalLoginService(userRepo, userCommon, userExternalLoginRepo, emailService, siteInfoCommonService, userActiveActivityRepo, userNotificationConfigService, userRoleRelService, twoFactorService)
 	questionRepo := question.NewQuestionRepo(dataData, uniqueIDRepo)
 	answerRepo := answer.NewAnswerRepo(dataData, uniqueIDRepo, userRankRepo, activityRepo)
 	voteRepo := activity_common.NewVoteRepo(dataData, activityRepo)
@@ -178,10 +216,14 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	tagCommonRepo := tag_common.NewTagCommonRepo(dataData, uniqueIDRepo)
 	tagRelRepo := tag.NewTagRelRepo(dataData, uniqueIDRepo)
 	tagRepo := tag.NewTagRepo(dataData, uniqueIDRepo)
+	tagOwnerRepo := tag.NewTagOwnerRepo(dataData)
+	tagSettingRepo := tag.NewTagSettingRepo(dataData)
 	revisionRepo := revision.NewRevisionRepo(dataData, uniqueIDRepo)
 	revisionService := revision_common.NewRevisionService(revisionRepo, userRepo)
 	service := activityqueue.NewService()
-	tagCommonService := tag_common2.NewTagCommonService(tagCommonRepo, tagRelRepo, tagRepo, revisionService, siteInfoCommonService, service)
+	spaceRepo := space.NewSpaceRepo(dataData)
+	spaceCommon := space_common.NewSpaceCommon(spaceRepo, userRoleRelService)
+	tagCommonService := tag_common2.NewTagCommonService(tagCommonRepo, tagRelRepo, tagRepo, tagOwnerRepo, tagSettingRepo, revisionService, siteInfoCommonService, service, spaceCommon)
 	collectionRepo := collection.NewCollectionRepo(dataData, uniqueIDRepo)
 	collectionCommon := collectioncommon.NewCollectionCommon(collectionRepo)
 	answerCommon := answercommon.NewAnswerCommon(answerRepo)
@@ -191,13 +233,13 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	eventqueueService := eventqueue.NewService()
 	fileRecordRepo := file_record.NewFileRecordRepo(dataData)
 	fileRecordService := file_record2.NewFileRecordService(fileRecordRepo, revisionRepo, serviceConf, siteInfoCommonService, userCommon)
-	userService := content.NewUserService(userRepo, userActiveActivityRepo, activityRepo, emailService, authService, siteInfoCommonService, userRoleRelService, userCommon, userExternalLoginService, userNotificationConfigRepo, userNotificationConfigService, questionCommon, eventqueueService, fileRecordService)
+	userService := content.NewUserService(userRepo, userActiveActivityRepo, activityRepo, emailService, authService, siteInfoCommonService, userRoleRelService, userCommon, userExternalLoginService, userNotificationConfigRepo, userNotificationConfigService, questionCommon, eventqueueService, fileRecordService, twoFactorService)
 	captchaRepo := captcha.NewCaptchaRepo(dataData)
 	captchaService := action.NewCaptchaService(captchaRepo)
-	userController := controller.NewUserController(authService, userService, captchaService, emailService, siteInfoCommonService, userNotificationConfigService)
+	userController := controller.NewUserController(authService, userService, captchaService, emailService, siteInfoCommonService, userNotificationConfigService, twoFactorService)
 	commentRepo := comment.NewCommentRepo(dataData, uniqueIDRepo)
 	commentCommonRepo := comment.NewCommentCommonRepo(dataData, uniqueIDRepo)
-	objService := object_info.NewObjService(answerRepo, questionRepo, commentCommonRepo, tagCommonRepo, tagCommonService)
+	objService := object_info.NewObjService(answerRepo, questionRepo, commentCommonRepo, tagCommonRepo, tagCommonService, spaceCommon)
 	noticequeueService := noticequeue.NewService()
 	externalService := noticequeue.NewExternalService()
 	reviewRepo := review.NewReviewRepo(dataData)
@@ -205,53 +247,66 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	reviewService := review2.NewReviewService(reviewRepo, objService, userCommon, userRepo, questionRepo, answerRepo, userRoleRelService, externalService, tagCommonService, questionCommon, noticequeueService, siteInfoCommonService, commentCommonRepo, vector_syncService)
 	commentService := comment2.NewCommentService(commentRepo, commentCommonRepo, userCommon, objService, voteRepo, emailService, userRepo, noticequeueService, externalService, service, eventqueueService, reviewService, vector_syncService)
 	rolePowerRelRepo := role.NewRolePowerRelRepo(dataData)
-	rolePowerRelService := role2.NewRolePowerRelService(rolePowerRelRepo, userRoleRelService)
+	roleTagRelRepo := role.NewRoleTagRelRepo(dataData)
+	rolePowerRelService := role2.NewRolePowerRelService(rolePowerRelRepo, roleTagRelRepo, userRoleRelService)
 	rankService := rank2.NewRankService(userCommon, userRankRepo, objService, userRoleRelService, rolePowerRelService, configService)
 	limitRepo := limit.NewRateLimitRepo(dataData)
 	rateLimitMiddleware := middleware.NewRateLimitMiddleware(limitRepo)
 	commentController := controller.NewCommentController(commentService, rankService, captchaService, rateLimitMiddleware)
 	reportRepo := report.NewReportRepo(dataData, uniqueIDRepo)
-	tagService := tag2.NewTagService(tagRepo, tagCommonService, revisionService, followRepo, siteInfoCommonService, service)
+	tagService := tag2.NewTagService(tagRepo, tagCommonService, revisionService, followRepo, siteInfoCommonService, service, userCommon)
 	answerActivityRepo := activity.NewAnswerActivityRepo(dataData, activityRepo, userRankRepo, noticequeueService)
 	answerActivityService := activity2.NewAnswerActivityService(answerActivityRepo, configService)
-	externalNotificationService := notification.NewExternalNotificationService(dataData, userNotificationConfigRepo, followRepo, emailService, userRepo, externalService, userExternalLoginRepo, siteInfoCommonService)
-	questionService := content.NewQuestionService(activityRepo, questionRepo, answerRepo, tagCommonService, tagService, questionCommon, userCommon, userRepo, userRoleRelService, revisionService, metaCommonService, collectionCommon, answerActivityService, emailService, noticequeueService, externalService, service, siteInfoCommonService, externalNotificationService, reviewService, configService, eventqueueService, reviewRepo, vector_syncService)
-	answerService := content.NewAnswerService(answerRepo, questionRepo, questionCommon, userCommon, collectionCommon, userRepo, revisionService, answerActivityService, answerCommon, voteRepo, emailService, userRoleRelService, noticequeueService, externalService, service, reviewService, eventqueueService, vector_syncService)
+	externalNotificationService := notification.NewExternalNotificationService(dataData, userNotificationConfigRepo, followRepo, emailService, userRepo, externalService, userExternalLoginRepo, siteInfoCommonService, objService)
+	questionService := content.NewQuestionService(activityRepo, questionRepo, answerRepo, tagCommonService, tagService, questionCommon, userCommon, userRepo, userRoleRelService, revisionService, metaCommonService, collectionCommon, answerActivityService, emailService, noticequeueService, externalService, service, siteInfoCommonService, externalNotificationService, reviewService, configService, eventqueueService, reviewRepo, vector_syncService, spaceCommon)
+	answerService := content.NewAnswerService(answerRepo, questionRepo, questionCommon, userCommon, collectionCommon, userRepo, revisionService, answerActivityService, answerCommon, voteRepo, emailService, userRoleRelService, noticequeueService, externalService, service, reviewService, eventqueueService, vector_syncService, spaceCommon)
 	reportHandle := report_handle.NewReportHandle(questionService, answerService, commentService)
 	reportService := report2.NewReportService(reportRepo, objService, userCommon, answerRepo, questionRepo, commentCommonRepo, reportHandle, configService, eventqueueService)
 	reportController := controller.NewReportController(reportService, rankService, captchaService)
 	contentVoteRepo := activity.NewVoteRepo(dataData, activityRepo, userRankRepo, noticequeueService)
 	voteService := content.NewVoteService(contentVoteRepo, configService, questionRepo, answerRepo, commentCommonRepo, objService, eventqueueService)
 	voteController := controller.NewVoteController(voteService, rankService, captchaService)
-	tagController := controller.NewTagController(tagService, tagCommonService, rankService)
+	tagSuggestionRepo := tag_suggestion.NewTagSuggestionRepo(dataData)
+	embeddingService := embedding.NewEmbeddingService()
+	tagSuggestionService := tag_suggestion2.NewTagSuggestionService(tagSuggestionRepo, tagCommonService, embeddingService)
+	tagController := controller.NewTagController(tagService, tagCommonService, rankService, tagSuggestionService)
 	followFollowRepo := activity.NewFollowRepo(dataData, uniqueIDRepo, activityRepo)
 	followService := follow.NewFollowService(followFollowRepo, followRepo, tagCommonRepo)
 	followController := controller.NewFollowController(followService)
 	collectionGroupRepo := collection.NewCollectionGroupRepo(dataData)
 	collectionService := collection2.NewCollectionService(collectionRepo, collectionGroupRepo, questionCommon)
 	collectionController := controller.NewCollectionController(collectionService)
-	questionController := controller.NewQuestionController(questionService, answerService, rankService, siteInfoCommonService, captchaService, rateLimitMiddleware)
-	answerController := controller.NewAnswerController(answerService, rankService, captchaService, siteInfoCommonService, rateLimitMiddleware)
+	feedRepo := feed.NewFeedRepo(dataData)
+	feedService := feed2.NewFeedService(feedRepo, followRepo, questionRepo, questionCommon, embeddingService)
+	draftRepo := draft.NewDraftRepo(dataData)
+	draftService := draft2.NewDraftService(draftRepo, siteInfoCommonService)
+	questionController := controller.NewQuestionController(questionService, answerService, rankService, siteInfoCommonService, captchaService, rateLimitMiddleware, feedService, draftService)
+	answerController := controller.NewAnswerController(answerService, rankService, captchaService, siteInfoCommonService, rateLimitMiddleware, draftService)
 	searchParser := search_parser.NewSearchParser(tagCommonService, userCommon)
 	searchRepo := search_common.NewSearchRepo(dataData, uniqueIDRepo, userCommon, tagCommonService)
-	searchService := content.NewSearchService(searchParser, searchRepo)
-	searchController := controller.NewSearchController(searchService, captchaService)
+	searchService := content.NewSearchService(searchParser, searchRepo, siteInfoCommonService, embeddingService, tagCommonService, userCommon, spaceCommon)
+	savedSearchRepo := saved_search.NewSavedSearchRepo(dataData)
+	savedSearchService := saved_search2.NewSavedSearchService(savedSearchRepo, searchService, noticequeueService)
+	searchController := controller.NewSearchController(searchService, captchaService, savedSearchService)
 	reviewActivityRepo := activity.NewReviewActivityRepo(dataData, activityRepo, userRankRepo, configService)
 	contentRevisionService := content.NewRevisionService(revisionRepo, userCommon, questionCommon, answerService, objService, questionRepo, answerRepo, tagRepo, tagCommonService, noticequeueService, service, reportRepo, reviewService, reviewActivityRepo)
-	revisionController := controller.NewRevisionController(contentRevisionService, rankService)
+	closeVoteRepo := close_vote.NewCloseVoteRepo(dataData)
+	closeVoteService := close_vote2.NewCloseVoteService(closeVoteRepo, questionRepo, questionCommon, questionService, configService, siteInfoCommonService, service)
+	revisionController := controller.NewRevisionController(contentRevisionService, rankService, questionService, answerService, tagService, closeVoteService, captchaService)
 	rankController := controller.NewRankController(rankService)
 	userAdminRepo := user.NewUserAdminRepo(dataData, authRepo)
 	notificationRepo := notification2.NewNotificationRepo(dataData)
 	pluginUserConfigRepo := plugin_config.NewPluginUserConfigRepo(dataData)
 	badgeAwardRepo := badge_award.NewBadgeAwardRepo(dataData, uniqueIDRepo)
-	userAdminService := user_admin.NewUserAdminService(userAdminRepo, userRoleRelService, authService, userCommon, userActiveActivityRepo, siteInfoCommonService, emailService, questionRepo, answerRepo, commentCommonRepo, userExternalLoginRepo, notificationRepo, pluginUserConfigRepo, badgeAwardRepo, apiKeyRepo)
-	userAdminController := controller_admin.NewUserAdminController(userAdminService)
+	userAdminService := user_admin.NewUserAdminService(userAdminRepo, userRoleRelService, roleService, authService, userCommon, userActiveActivityRepo, siteInfoCommonService, emailService, questionRepo, answerRepo, commentCommonRepo, userExternalLoginRepo, notificationRepo, pluginUserConfigRepo, badgeAwardRepo, apiKeyRepo)
+	userSessionService := user_session.NewUserSessionService(authService, userRepo, siteInfoCommonService)
+	userAdminController := controller_admin.NewUserAdminController(userAdminService, twoFactorService, userSessionService)
 	reasonRepo := reason.NewReasonRepo(configService)
 	reasonService := reason2.NewReasonService(reasonRepo)
 	reasonController := controller.NewReasonController(reasonService)
 	themeController := controller_admin.NewThemeController()
 	siteInfoService := siteinfo.NewSiteInfoService(siteInfoRepo, siteInfoCommonService, emailService, tagCommonService, configService, questionCommon, fileRecordService)
-	siteInfoController := controller_admin.NewSiteInfoController(siteInfoService)
+	siteInfoController := controller_admin.NewSiteInfoController(siteInfoService, twoFactorService)
 	controllerSiteInfoController := controller.NewSiteInfoController(siteInfoCommonService)
 	notificationCommon := notificationcommon.NewNotificationCommon(dataData, notificationRepo, userCommon, activityRepo, followRepo, objService, noticequeueService, userExternalLoginRepo, siteInfoCommonService)
 	badgeRepo := badge.NewBadgeRepo(dataData, uniqueIDRepo)
@@ -266,9 +321,12 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	commentCommonService := comment_common.NewCommentCommonService(commentCommonRepo)
 	activityService := activity2.NewActivityService(activityActivityRepo, userCommon, activityCommon, tagCommonService, objService, commentCommonService, revisionService, metaCommonService, configService)
 	activityController := controller.NewActivityController(activityService)
-	roleController := controller_admin.NewRoleController(roleService)
+	powerRepo := role.NewPowerRepo(dataData)
+	roleAdminService := role_admin.NewRoleAdminService(dataData, roleRepo, rolePowerRelRepo, roleTagRelRepo, powerRepo, roleService, userRoleRelService, tagCommonService)
+	roleController := controller_admin.NewRoleController(roleService, roleAdminService)
 	pluginConfigRepo := plugin_config.NewPluginConfigRepo(dataData)
-	importerService := importer.NewImporterService(questionService, rankService, userCommon)
+	importerRepo := importer.NewImporterRepo(dataData)
+	importerService := importer2.NewImporterService(importerRepo, questionRepo, questionCommon, answerRepo, commentRepo, revisionService, tagCommonService, userRepo, userCommon, activityRepo, uploaderService, dataData)
 	pluginCommonService := plugin_common.NewPluginCommonService(pluginConfigRepo, pluginUserConfigRepo, configService, dataData, importerService)
 	pluginController := controller_admin.NewPluginController(pluginCommonService)
 	permissionController := controller.NewPermissionController(rankService)
@@ -286,34 +344,131 @@ func initApplication(debug bool, serverConf *conf.Server, dbConf *data.Database,
 	apiKeyService := apikey.NewAPIKeyService(apiKeyRepo)
 	adminAPIKeyController := controller_admin.NewAdminAPIKeyController(apiKeyService)
 	featureToggleService := feature_toggle.NewFeatureToggleService(siteInfoRepo)
-	embeddingService := embedding.NewEmbeddingService()
 	mcpController := controller.NewMCPController(searchService, siteInfoCommonService, tagCommonService, questionCommon, commentRepo, userCommon, answerRepo, featureToggleService, embeddingService)
 	aiConversationRepo := ai_conversation.NewAIConversationRepo(dataData)
 	aiConversationService := ai_conversation2.NewAIConversationService(aiConversationRepo, userCommon)
 	aiController := controller.NewAIController(searchService, siteInfoCommonService, tagCommonService, questionCommon, commentRepo, userCommon, answerRepo, mcpController, aiConversationService, featureToggleService)
 	aiConversationController := controller.NewAIConversationController(aiConversationService, featureToggleService)
 	aiConversationAdminController := controller_admin.NewAIConversationAdminController(aiConversationService, featureToggleService)
-	answerAPIRouter := router.NewAnswerAPIRouter(langController, userController, commentController, reportController, voteController, tagController, followController, collectionController, questionController, answerController, searchController, revisionController, rankController, userAdminController, reasonController, themeController, siteInfoController, controllerSiteInfoController, notificationController, dashboardController, uploadController, activityController, roleController, pluginController, permissionController, userPluginController, reviewController, metaController, badgeController, controller_adminBadgeController, adminAPIKeyController, aiController, aiConversationController, aiConversationAdminController, mcpController)
+	closeVoteController := controller.NewCloseVoteController(closeVoteService, rankService)
+	bountyRepo := bounty.NewBountyRepo(dataData, userRankRepo)
+	bountyService := bounty2.NewBountyService(bountyRepo, questionRepo, answerRepo, userCommon, configService, siteInfoCommonService, noticequeueService, spaceCommon)
+	bountyController := controller.NewBountyController(bountyService, rankService)
+	draftController := controller.NewDraftController(draftService)
+	scheduledPostRepo := scheduled_post.NewScheduledPostRepo(dataData)
+	scheduledPostService := scheduled_post2.NewScheduledPostService(scheduledPostRepo, questionRepo, answerRepo, questionService, answerService)
+	scheduledPostController := controller.NewScheduledPostController(scheduledPostService)
+	spaceService := space2.NewSpaceService(spaceRepo, spaceCommon, userCommon)
+	spaceController := controller.NewSpaceController(spaceService)
+	tenantRepo := tenant.NewTenantRepo(dataData)
+	tenantService := tenant2.NewTenantService(dataData, tenantRepo, featureToggleService)
+	tenantController := controller_admin.NewTenantController(tenantService)
+	userDataRepo := user_data.NewUserDataRepo(dataData)
+	userDataService := user_data2.NewUserDataService(userDataRepo, userRepo, userAdminService, userRoleRelService, configService, emailService, siteInfoCommonService, serviceConf)
+	userDataController := controller.NewUserDataController(userDataService)
+	ssoProviderRepo := sso.NewSSOProviderRepo(dataData)
+	ssoService := sso2.NewSSOService(ssoProviderRepo, userExternalLoginRepo, userRepo, userRoleRelService, roleService, userAdminService, userExternalLoginService, siteInfoCommonService)
+	ssoController := controller.NewSSOController(ssoService, siteInfoCommonService, userExternalLoginService)
+	ssoProviderController := controller_admin.NewSSOProviderController(ssoService)
+	scimRepo := scim.NewSCIMRepo(dataData)
+	scimService := scim2.NewSCIMService(scimRepo, userRepo, userExternalLoginRepo, userAdminService, userRoleRelService, roleService, siteInfoCommonService)
+	scimController := controller.NewSCIMController(scimService)
+	twoFactorController := controller.NewTwoFactorController(twoFactorService)
+	userSessionController := controller.NewUserSessionController(userSessionService)
+	authUserMiddleware := middleware.NewAuthUserMiddleware(authService, siteInfoCommonService, userRepo, userRoleRelService)
+	answerAPIRouter := router.NewAnswerAPIRouter(langController, userController, commentController, reportController, voteController, tagController, followController, collectionController, questionController, answerController, searchController, revisionController, rankController, userAdminController, reasonController, themeController, siteInfoController, controllerSiteInfoController, notificationController, dashboardController, uploadController, activityController, roleController, pluginController, permissionController, userPluginController, reviewController, metaController, badgeController, controller_adminBadgeController, adminAPIKeyController, aiController, aiConversationController, aiConversationAdminController, mcpController, closeVoteController, bountyController, draftController, scheduledPostController, spaceController, tenantController, userDataController, ssoController, ssoProviderController, scimController, twoFactorController, userSessionController, authUserMiddleware)
 	swaggerRouter := router.NewSwaggerRouter(swaggerConf)
 	uiRouter := router.NewUIRouter(controllerSiteInfoController, siteInfoCommonService)
-	authUserMiddleware := middleware.NewAuthUserMiddleware(authService, siteInfoCommonService)
 	avatarMiddleware := middleware.NewAvatarMiddleware(serviceConf, uploaderService)
 	shortIDMiddleware := middleware.NewShortIDMiddleware(siteInfoCommonService)
+	tenantMiddleware := middleware.NewTenantMiddleware(tenantService)
 	templateRenderController := templaterender.NewTemplateRenderController(questionService, userService, tagService, answerService, commentService, siteInfoCommonService, questionRepo)
 	templateController := controller.NewTemplateController(templateRenderController, siteInfoCommonService, eventqueueService, userService, questionService)
 	templateRouter := router.NewTemplateRouter(templateController, templateRenderController, siteInfoController, authUserMiddleware)
-	connectorController := controller.NewConnectorController(siteInfoCommonService, emailService, userExternalLoginService)
-	userCenterLoginService := user_external_login2.NewUserCenterLoginService(userRepo, userCommon, userExternalLoginRepo, userActiveActivityRepo, siteInfoCommonService)
+	connectorController := controller.NewConnectorController(siteInfoCommonService, emailService, userExternalLoginService, ssoService)
+	userCenterLoginService := user_external_login2.NewUserCenterLoginService(userRepo, userCommon, userExternalLoginRepo, userActiveActivityRepo, siteInfoCommonService, twoFactorService)
 	userCenterController := controller.NewUserCenterController(userCenterLoginService, siteInfoCommonService)
 	captchaController := controller.NewCaptchaController()
 	embedController := controller.NewEmbedController()
 	renderController := controller.NewRenderController()
 	sidebarController := controller.NewSidebarController()
 	pluginAPIRouter := router.NewPluginAPIRouter(connectorController, userCenterController, captchaController, embedController, renderController, sidebarController)
-	ginEngine := server.NewHTTPServer(debug, staticRouter, answerAPIRouter, swaggerRouter, uiRouter, authUserMiddleware, avatarMiddleware, shortIDMiddleware, templateRouter, pluginAPIRouter, uiConf)
-	scheduledTaskManager := cron.NewScheduledTaskManager(siteInfoCommonService, questionService, fileRecordService, userAdminService, serviceConf)
+	ginEngine := server.NewHTTPServer(debug, staticRouter, answerAPIRouter, swaggerRouter, uiRouter, authUserMiddleware, avatarMiddleware, shortIDMiddleware, tenantMiddleware, templateRouter, pluginAPIRouter, uiConf)
+	scheduledTaskManager := cron.NewScheduledTaskManager(siteInfoCommonService, questionService, fileRecordService, userAdminService, serviceConf, savedSearchService, tagSuggestionService, closeVoteService, bountyService, draftService, scheduledPostService, tenantService, userDataService)
 	application := newApplication(serverConf, ginEngine, scheduledTaskManager)
 	return application, func() {
+		cleanup3()
+		cleanup2()
+		cleanup()
+	}, nil
+}
+
+// initImporter init the importer service for the import command.
+func initImporter(debug bool, dbConf *data.Database, cacheConf *data.CacheConf, serviceConf *service_config.ServiceConfig) (*importer2.ImporterService, func(), error) {
+	engine, err := data.NewDB(debug, dbConf)
+	if err != nil {
+		return nil, nil, err
+	}
+	invalidationBus, cleanup, err := data.NewInvalidationBus(cacheConf)
+	if err != nil {
+		return nil, nil, err
+	}
+	cache, cleanup2, err := data.NewCacheWithBus(cacheConf, invalidationBus)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	dataData, cleanup3, err := data.NewData(engine, cache, invalidationBus)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	importerRepo := importer.NewImporterRepo(dataData)
+	uniqueIDRepo := unique.NewUniqueIDRepo(dataData)
+	questionRepo := question.NewQuestionRepo(dataData, uniqueIDRepo)
+	configRepo := config.NewConfigRepo(dataData)
+	configService := config2.NewConfigService(configRepo)
+	userRankRepo := rank.NewUserRankRepo(dataData, configService)
+	activityRepo := activity_common.NewActivityRepo(dataData, uniqueIDRepo, configService)
+	answerRepo := answer.NewAnswerRepo(dataData, uniqueIDRepo, userRankRepo, activityRepo)
+	voteRepo := activity_common.NewVoteRepo(dataData, activityRepo)
+	followRepo := activity_common.NewFollowRepo(dataData, uniqueIDRepo, activityRepo)
+	tagCommonRepo := tag_common.NewTagCommonRepo(dataData, uniqueIDRepo)
+	tagRelRepo := tag.NewTagRelRepo(dataData, uniqueIDRepo)
+	tagRepo := tag.NewTagRepo(dataData, uniqueIDRepo)
+	tagOwnerRepo := tag.NewTagOwnerRepo(dataData)
+	tagSettingRepo := tag.NewTagSettingRepo(dataData)
+	revisionRepo := revision.NewRevisionRepo(dataData, uniqueIDRepo)
+	userRepo := user.NewUserRepo(dataData)
+	revisionService := revision_common.NewRevisionService(revisionRepo, userRepo)
+	siteInfoRepo := site_info.NewSiteInfo(dataData)
+	siteInfoCommonService := siteinfo_common.NewSiteInfoCommonService(siteInfoRepo)
+	service := activityqueue.NewService()
+	spaceRepo := space.NewSpaceRepo(dataData)
+	userRoleRelRepo := role.NewUserRoleRelRepo(dataData)
+	roleRepo := role.NewRoleRepo(dataData)
+	roleService := role2.NewRoleService(roleRepo)
+	userRoleRelService := role2.NewUserRoleRelService(userRoleRelRepo, roleService)
+	spaceCommon := space_common.NewSpaceCommon(spaceRepo, userRoleRelService)
+	tagCommonService := tag_common2.NewTagCommonService(tagCommonRepo, tagRelRepo, tagRepo, tagOwnerRepo, tagSettingRepo, revisionService, siteInfoCommonService, service, spaceCommon)
+	authRepo := auth.NewAuthRepo(dataData)
+	apiKeyRepo := api_key.NewAPIKeyRepo(dataData)
+	authService := auth2.NewAuthService(authRepo, apiKeyRepo)
+	userCommon := usercommon.NewUserCommon(userRepo, userRoleRelService, authService, siteInfoCommonService)
+	collectionRepo := collection.NewCollectionRepo(dataData, uniqueIDRepo)
+	collectionCommon := collectioncommon.NewCollectionCommon(collectionRepo)
+	answerCommon := answercommon.NewAnswerCommon(answerRepo)
+	metaRepo := meta.NewMetaRepo(dataData)
+	metaCommonService := metacommon.NewMetaCommonService(metaRepo)
+	questionCommon := questioncommon.NewQuestionCommon(questionRepo, answerRepo, voteRepo, followRepo, tagCommonService, userCommon, collectionCommon, answerCommon, metaCommonService, configService, service, revisionRepo, siteInfoCommonService, dataData)
+	commentRepo := comment.NewCommentRepo(dataData, uniqueIDRepo)
+	fileRecordRepo := file_record.NewFileRecordRepo(dataData)
+	fileRecordService := file_record2.NewFileRecordService(fileRecordRepo, revisionRepo, serviceConf, siteInfoCommonService, userCommon)
+	uploaderService := uploader.NewUploaderService(serviceConf, siteInfoCommonService, fileRecordService)
+	importerService := importer2.NewImporterService(importerRepo, questionRepo, questionCommon, answerRepo, commentRepo, revisionService, tagCommonService, userRepo, userCommon, activityRepo, uploaderService, dataData)
+	return importerService, func() {
+		cleanup3()
 		cleanup2()
 		cleanup()
 	}, nil
@@ -58,6 +58,16 @@ const (
 	ActQuestionShow      ActivityTypeKey = "question.show"
 )
 
+const (
+	ActQuestionCloseVoted  ActivityTypeKey = "question.close_voted"
+	ActQuestionReopenVoted ActivityTypeKey = "question.reopen_voted"
+)
+
+const (
+	ActQuestionBountyOffered ActivityTypeKey = "question.bounty_offered"
+	ActAnswerBountyAwarded   ActivityTypeKey = "answer.bounty_awarded"
+)
+
 const (
 	ActAnswerAnswered  ActivityTypeKey = "answer.answered"
 	ActAnswerCommented ActivityTypeKey = "answer.commented"
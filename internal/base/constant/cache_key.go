@@ -55,4 +55,17 @@ const (
 	RateLimitCacheTime                         = 5 * time.Minute
 	RedDotCacheKey                             = "answer:red-dot:%s:%s"
 	RedDotCacheTime                            = 30 * 24 * time.Hour
+	QuestionFeedCacheKeyPrefix                 = "answer:question-feed:"
+	QuestionFeedCacheTime                      = 10 * time.Minute
+	QuestionRecentViewedCacheKeyPrefix         = "answer:question-recent-viewed:"
+	QuestionRecentViewedCacheTime              = 30 * 24 * time.Hour
+	TenantHostCacheKey                         = "answer:tenant:host:"
+	TenantHostCacheTime                        = 5 * time.Minute
+	UserSessionCacheKey                        = "answer:user:session:"
+	UserRecentAuthCacheKey                     = "answer:user:recent-auth:"
+	UserRecentAuthCacheTime                    = 10 * time.Minute
+	TwoFactorLoginChallengeCacheKey            = "answer:two-factor:login:"
+	TwoFactorLoginChallengeCacheTime           = 5 * time.Minute
+	TwoFactorSetupCacheKey                     = "answer:two-factor:setup:"
+	TwoFactorSetupCacheTime                    = 10 * time.Minute
 )
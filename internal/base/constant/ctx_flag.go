@@ -22,6 +22,7 @@ package constant
 const (
 	AcceptLanguageFlag = "Accept-Language"
 	ShortIDFlag        = "Short-ID-Enabled"
+	TenantIDFlag       = "Tenant-ID"
 )
 
 type ContextKey string
@@ -29,4 +30,5 @@ type ContextKey string
 const (
 	AcceptLanguageContextKey ContextKey = ContextKey(AcceptLanguageFlag)
 	ShortIDContextKey        ContextKey = ContextKey(ShortIDFlag)
+	TenantIDContextKey       ContextKey = ContextKey(TenantIDFlag)
 )
@@ -43,4 +43,10 @@ const (
 
 	EmailTplKeyNewQuestionTitle = "email_tpl.new_question.title"
 	EmailTplKeyNewQuestionBody  = "email_tpl.new_question.body"
+
+	EmailTplKeyUserDataExportTitle = "email_tpl.user_data_export.title"
+	EmailTplKeyUserDataExportBody  = "email_tpl.user_data_export.body"
+
+	EmailTplKeyUserErasureTitle = "email_tpl.user_erasure.title"
+	EmailTplKeyUserErasureBody  = "email_tpl.user_erasure.body"
 )
@@ -58,6 +58,14 @@ const (
 	NotificationInvitedYouToAnswer = "notification.action.invited_you_to_answer"
 	// NotificationEarnedBadge earned badge
 	NotificationEarnedBadge = "notification.action.earned_badge"
+	// NotificationSavedSearchMatched new content matched your saved search
+	NotificationSavedSearchMatched = "notification.action.saved_search_matched"
+	// NotificationNewQuestionInOwnedTag new question in the tag you own
+	NotificationNewQuestionInOwnedTag = "notification.action.new_question_in_owned_tag"
+	// NotificationBountyAwarded the bounty is awarded to your answer
+	NotificationBountyAwarded = "notification.action.bounty_awarded"
+	// NotificationYourBountyExpired your bounty expired without an eligible answer
+	NotificationYourBountyExpired = "notification.action.your_bounty_expired"
 )
 
 type NotificationChannelKey string
@@ -99,5 +107,9 @@ var (
 		NotificationYourAnswerWasDeleted:   1,
 		NotificationYourCommentWasDeleted:  1,
 		NotificationInvitedYouToAnswer:     3,
+		NotificationSavedSearchMatched:     1,
+		NotificationNewQuestionInOwnedTag:  1,
+		NotificationBountyAwarded:          1,
+		NotificationYourBountyExpired:      1,
 	}
 )
@@ -61,6 +61,8 @@ const (
 	RankQuestionCloseKey             = "rank.question.close"
 	RankQuestionReopenKey            = "rank.question.reopen"
 	RankTagUseReservedTagKey         = "rank.tag.use_reserved_tag"
+	RankQuestionCloseVoteKey         = "rank.question.close_vote"
+	RankQuestionBountyKey            = "rank.question.bounty"
 )
 
 var (
@@ -87,5 +89,7 @@ var (
 		{Label: reason.RankTagAuditLabel, Key: RankTagAuditKey},
 		{Label: reason.RankTagEditWithoutReviewLabel, Key: RankTagEditWithoutReviewKey},
 		{Label: reason.RankTagSynonymLabel, Key: RankTagSynonymKey},
+		{Label: reason.RankQuestionCloseVoteLabel, Key: RankQuestionCloseVoteKey},
+		{Label: reason.RankQuestionBountyLabel, Key: RankQuestionBountyKey},
 	}
 )
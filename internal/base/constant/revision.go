@@ -27,6 +27,7 @@ const (
 	FlaggedPost       ReviewingType = "flagged_post"
 	FlaggedUser       ReviewingType = "flagged_user"
 	SuggestedPostEdit ReviewingType = "suggested_post_edit"
+	CloseVote         ReviewingType = "close_vote"
 )
 
 const (
@@ -41,4 +42,5 @@ const (
 	ReviewQueuedPostLabel        = "review.queued_post"
 	ReviewFlaggedPostLabel       = "review.flagged_post"
 	ReviewSuggestedPostEditLabel = "review.suggested_post_edit"
+	ReviewCloseVoteLabel         = "review.close_vote"
 )
@@ -26,4 +26,5 @@ const (
 	BrandingSubPath    = "branding"
 	FilesPostSubPath   = "files/post"
 	DeletedSubPath     = "deleted"
+	UserDataSubPath    = "user_data"
 )
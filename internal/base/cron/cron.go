@@ -23,22 +23,39 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/service/bounty"
+	"github.com/apache/answer/internal/service/close_vote"
 	"github.com/apache/answer/internal/service/content"
+	"github.com/apache/answer/internal/service/draft"
 	"github.com/apache/answer/internal/service/file_record"
+	"github.com/apache/answer/internal/service/saved_search"
+	"github.com/apache/answer/internal/service/scheduled_post"
 	"github.com/apache/answer/internal/service/service_config"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/tag_suggestion"
+	"github.com/apache/answer/internal/service/tenant"
 	"github.com/apache/answer/internal/service/user_admin"
+	"github.com/apache/answer/internal/service/user_data"
 	"github.com/robfig/cron/v3"
 	"github.com/segmentfault/pacman/log"
 )
 
 // ScheduledTaskManager scheduled task manager
 type ScheduledTaskManager struct {
-	siteInfoService   siteinfo_common.SiteInfoCommonService
-	questionService   *content.QuestionService
-	fileRecordService *file_record.FileRecordService
-	userAdminService  *user_admin.UserAdminService
-	serviceConfig     *service_config.ServiceConfig
+	siteInfoService    siteinfo_common.SiteInfoCommonService
+	questionService    *content.QuestionService
+	fileRecordService  *file_record.FileRecordService
+	userAdminService   *user_admin.UserAdminService
+	serviceConfig      *service_config.ServiceConfig
+	savedSearchService *saved_search.SavedSearchService
+	tagSuggestion      *tag_suggestion.TagSuggestionService
+	closeVoteService   *close_vote.CloseVoteService
+	bountyService      *bounty.BountyService
+	draftService       *draft.DraftService
+	scheduledPost      *scheduled_post.ScheduledPostService
+	tenantService      *tenant.TenantService
+	userDataService    *user_data.UserDataService
 }
 
 // NewScheduledTaskManager new scheduled task manager
@@ -48,13 +65,29 @@ func NewScheduledTaskManager(
 	fileRecordService *file_record.FileRecordService,
 	userAdminService *user_admin.UserAdminService,
 	serviceConfig *service_config.ServiceConfig,
+	savedSearchService *saved_search.SavedSearchService,
+	tagSuggestion *tag_suggestion.TagSuggestionService,
+	closeVoteService *close_vote.CloseVoteService,
+	bountyService *bounty.BountyService,
+	draftService *draft.DraftService,
+	scheduledPost *scheduled_post.ScheduledPostService,
+	tenantService *tenant.TenantService,
+	userDataService *user_data.UserDataService,
 ) *ScheduledTaskManager {
 	manager := &ScheduledTaskManager{
-		siteInfoService:   siteInfoService,
-		questionService:   questionService,
-		fileRecordService: fileRecordService,
-		userAdminService:  userAdminService,
-		serviceConfig:     serviceConfig,
+		siteInfoService:    siteInfoService,
+		questionService:    questionService,
+		fileRecordService:  fileRecordService,
+		userAdminService:   userAdminService,
+		serviceConfig:      serviceConfig,
+		savedSearchService: savedSearchService,
+		tagSuggestion:      tagSuggestion,
+		closeVoteService:   closeVoteService,
+		bountyService:      bountyService,
+		draftService:       draftService,
+		scheduledPost:      scheduledPost,
+		tenantService:      tenantService,
+		userDataService:    userDataService,
 	}
 	return manager
 }
@@ -62,21 +95,23 @@ func NewScheduledTaskManager(
 func (s *ScheduledTaskManager) Run() {
 	log.Infof("cron job manager start")
 
-	s.questionService.SitemapCron(context.Background())
+	s.forEachTenant(s.questionService.SitemapCron)
 	c := cron.New()
 	_, err := c.AddFunc("0 */1 * * *", func() {
-		ctx := context.Background()
-		log.Infof("sitemap cron execution")
-		s.questionService.SitemapCron(ctx)
+		s.forEachTenant(func(ctx context.Context) {
+			log.Infof("sitemap cron execution")
+			s.questionService.SitemapCron(ctx)
+		})
 	})
 	if err != nil {
 		log.Error(err)
 	}
 
 	_, err = c.AddFunc("0 */1 * * *", func() {
-		ctx := context.Background()
-		log.Infof("refresh hottest cron execution")
-		s.questionService.RefreshHottestCron(ctx)
+		s.forEachTenant(func(ctx context.Context) {
+			log.Infof("refresh hottest cron execution")
+			s.questionService.RefreshHottestCron(ctx)
+		})
 	})
 	if err != nil {
 		log.Error(err)
@@ -84,12 +119,83 @@ func (s *ScheduledTaskManager) Run() {
 
 	// Check for expired user suspensions every 10 minutes
 	_, err = c.AddFunc("*/10 * * * *", func() {
-		ctx := context.Background()
-		log.Infof("checking expired user suspensions")
-		err := s.userAdminService.CheckAndUnsuspendExpiredUsers(ctx)
-		if err != nil {
-			log.Errorf("failed to check expired user suspensions: %v", err)
-		}
+		s.forEachTenant(func(ctx context.Context) {
+			log.Infof("checking expired user suspensions")
+			err := s.userAdminService.CheckAndUnsuspendExpiredUsers(ctx)
+			if err != nil {
+				log.Errorf("failed to check expired user suspensions: %v", err)
+			}
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	_, err = c.AddFunc("30 */1 * * *", func() {
+		s.forEachTenant(func(ctx context.Context) {
+			log.Infof("saved search notification cron execution")
+			s.savedSearchService.NotifyNewMatchesCron(ctx)
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	_, err = c.AddFunc("0 3 * * 0", func() {
+		s.forEachTenant(func(ctx context.Context) {
+			log.Infof("tag suggestion training cron execution")
+			s.tagSuggestion.RetrainCron(ctx)
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	_, err = c.AddFunc("15 */1 * * *", func() {
+		s.forEachTenant(func(ctx context.Context) {
+			log.Infof("expire close votes cron execution")
+			s.closeVoteService.ExpireCloseVotesCron(ctx)
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	_, err = c.AddFunc("45 */1 * * *", func() {
+		s.forEachTenant(func(ctx context.Context) {
+			log.Infof("expire bounty cron execution")
+			s.bountyService.ExpireBountyCron(ctx)
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	_, err = c.AddFunc("20 4 * * *", func() {
+		s.forEachTenant(func(ctx context.Context) {
+			log.Infof("purge expired drafts cron execution")
+			s.draftService.PurgeExpiredDraftsCron(ctx)
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	_, err = c.AddFunc("* * * * *", func() {
+		s.forEachTenant(func(ctx context.Context) {
+			log.Debugf("publish scheduled posts cron execution")
+			s.scheduledPost.PublishScheduledPostsCron(ctx)
+		})
+	})
+	if err != nil {
+		log.Error(err)
+	}
+
+	_, err = c.AddFunc("* * * * *", func() {
+		s.forEachTenant(func(ctx context.Context) {
+			log.Debugf("process user data requests cron execution")
+			s.userDataService.ProcessUserDataRequestsCron(ctx)
+		})
 	})
 	if err != nil {
 		log.Error(err)
@@ -101,7 +207,7 @@ func (s *ScheduledTaskManager) Run() {
 		conf := s.serviceConfig
 		_, err = c.AddFunc(fmt.Sprintf("0 */%d * * *", conf.CleanOrphanUploadsPeriodHours), func() {
 			log.Infof("clean orphan upload files cron execution")
-			s.fileRecordService.CleanOrphanUploadFiles(context.Background())
+			s.forEachTenant(s.fileRecordService.CleanOrphanUploadFiles)
 		})
 		if err != nil {
 			log.Error(err)
@@ -109,7 +215,7 @@ func (s *ScheduledTaskManager) Run() {
 
 		_, err = c.AddFunc(fmt.Sprintf("0 0 */%d * *", conf.PurgeDeletedFilesPeriodDays), func() {
 			log.Infof("purge deleted files cron execution")
-			s.fileRecordService.PurgeDeletedFiles(context.Background())
+			s.forEachTenant(s.fileRecordService.PurgeDeletedFiles)
 		})
 		if err != nil {
 			log.Error(err)
@@ -117,3 +223,17 @@ func (s *ScheduledTaskManager) Run() {
 	}
 	c.Start()
 }
+
+// forEachTenant run the job for the primary site and then every available tenant
+func (s *ScheduledTaskManager) forEachTenant(job func(ctx context.Context)) {
+	ctx := context.Background()
+	job(ctx)
+	tenantIDs, err := s.tenantService.GetAvailableTenantIDs(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, tenantID := range tenantIDs {
+		job(handler.WithTenantID(ctx, tenantID))
+	}
+}
@@ -19,6 +19,12 @@
 
 package data
 
+import (
+	"time"
+
+	"github.com/apache/answer/pkg/redis"
+)
+
 // Database database config
 type Database struct {
 	Driver          string `json:"driver" mapstructure:"driver" yaml:"driver"`
@@ -28,7 +34,56 @@ type Database struct {
 	MaxIdleConn     int    `json:"max_idle_conn" mapstructure:"max_idle_conn" yaml:"max_idle_conn,omitempty"`
 }
 
+const (
+	// CacheTypeMemory the in-process memory cache persisted to the file, it is the default
+	CacheTypeMemory = "memory"
+	// CacheTypeRedis the cache on a redis compatible server shared by all instances
+	CacheTypeRedis = "redis"
+)
+
 // CacheConf cache
 type CacheConf struct {
-	FilePath string `json:"file_path" mapstructure:"file_path" yaml:"file_path"`
+	Type     string     `json:"type" mapstructure:"type" yaml:"type,omitempty"`
+	FilePath string     `json:"file_path" mapstructure:"file_path" yaml:"file_path"`
+	Redis    *RedisConf `json:"redis" mapstructure:"redis" yaml:"redis,omitempty"`
+}
+
+// RedisConf redis cache config
+type RedisConf struct {
+	Addr     string `json:"addr" mapstructure:"addr" yaml:"addr"`
+	Username string `json:"username" mapstructure:"username" yaml:"username,omitempty"`
+	Password string `json:"password" mapstructure:"password" yaml:"password,omitempty"`
+	DB       int    `json:"db" mapstructure:"db" yaml:"db,omitempty"`
+	// KeyPrefix the prefix of all keys and the invalidation channel, so that several sites can share a server
+	KeyPrefix string `json:"key_prefix" mapstructure:"key_prefix" yaml:"key_prefix,omitempty"`
+	// NearCacheTTL the seconds of the values kept in the memory of the instance, 0 is the default, negative disables it
+	NearCacheTTL int `json:"near_cache_ttl" mapstructure:"near_cache_ttl" yaml:"near_cache_ttl,omitempty"`
+}
+
+// IsRedis whether the cache is on the redis server
+func (c *CacheConf) IsRedis() bool {
+	return c != nil && c.Type == CacheTypeRedis
+}
+
+func (c *RedisConf) keyPrefix() string {
+	if len(c.KeyPrefix) == 0 {
+		return "answer:"
+	}
+	return c.KeyPrefix
+}
+
+func (c *RedisConf) nearCacheTTL() time.Duration {
+	if c.NearCacheTTL == 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.NearCacheTTL) * time.Second
+}
+
+func (c *RedisConf) newClient() *redis.Client {
+	return redis.NewClient(redis.Options{
+		Addr:     c.Addr,
+		Username: c.Username,
+		Password: c.Password,
+		DB:       c.DB,
+	})
 }
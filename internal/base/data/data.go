@@ -20,6 +20,8 @@
 package data
 
 import (
+	"context"
+	"fmt"
 	"path/filepath"
 	"time"
 
@@ -39,17 +41,22 @@ import (
 
 // Data data
 type Data struct {
-	DB    *xorm.Engine
+	DB    *DB
 	Cache cache.Cache
+	// Bus notify the other instances of the site that the in-process state is changed
+	Bus *InvalidationBus
 }
 
-// NewData new data instance
-func NewData(db *xorm.Engine, cache cache.Cache) (*Data, func(), error) {
+// NewData new data instance, the bus of the single instance is used when it is nil
+func NewData(db *xorm.Engine, cache cache.Cache, bus *InvalidationBus) (*Data, func(), error) {
+	if bus == nil {
+		bus = NewLocalInvalidationBus()
+	}
 	cleanup := func() {
 		log.Info("closing the data resources")
 		_ = db.Close()
 	}
-	return &Data{DB: db, Cache: cache}, cleanup, nil
+	return &Data{DB: WrapEngine(db), Cache: NewTenantCache(cache), Bus: bus}, cleanup, nil
 }
 
 // NewDB new database instance
@@ -94,8 +101,26 @@ func NewDB(debug bool, dataConf *Database) (*xorm.Engine, error) {
 	return engine, nil
 }
 
-// NewCache new cache instance
+// NewCache new cache instance with its own invalidation bus, it is used by the commands out of the application
 func NewCache(c *CacheConf) (cache.Cache, func(), error) {
+	bus, busCleanup, err := NewInvalidationBus(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	ca, cacheCleanup, err := NewCacheWithBus(c, bus)
+	if err != nil {
+		busCleanup()
+		return nil, nil, err
+	}
+	return ca, func() {
+		cacheCleanup()
+		busCleanup()
+	}, nil
+}
+
+// NewCacheWithBus new cache instance, the cache plugin is preferred, then the type in the config.
+// The redis cache keeps a near cache in the memory which is invalidated by the bus.
+func NewCacheWithBus(c *CacheConf, bus *InvalidationBus) (cache.Cache, func(), error) {
 	var pluginCache plugin.Cache
 	_ = plugin.CallCache(func(fn plugin.Cache) error {
 		pluginCache = fn
@@ -105,7 +130,38 @@ func NewCache(c *CacheConf) (cache.Cache, func(), error) {
 		return pluginCache, func() {}, nil
 	}
 
-	// TODO What cache type should be initialized according to the configuration file
+	switch c.Type {
+	case "", CacheTypeMemory:
+		return newMemoryCache(c)
+	case CacheTypeRedis:
+		return newRedisCache(c, bus)
+	default:
+		return nil, nil, fmt.Errorf("unknown cache type %s", c.Type)
+	}
+}
+
+func newRedisCache(c *CacheConf, bus *InvalidationBus) (cache.Cache, func(), error) {
+	if c.Redis == nil || len(c.Redis.Addr) == 0 {
+		return nil, nil, fmt.Errorf("the address of the redis cache is required")
+	}
+	client := c.Redis.newClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("connect redis %s failed: %w", c.Redis.Addr, err)
+	}
+	log.Infof("use the redis cache on %s", c.Redis.Addr)
+	var ca = NewRedisCache(client, c.Redis.keyPrefix())
+	if ttl := c.Redis.nearCacheTTL(); ttl > 0 && bus != nil {
+		ca = NewNearCache(ca, bus, ttl)
+	}
+	return ca, func() {
+		_ = client.Close()
+	}, nil
+}
+
+func newMemoryCache(c *CacheConf) (cache.Cache, func(), error) {
 	memCache := memory.NewCache()
 
 	if len(c.FilePath) > 0 {
@@ -0,0 +1,203 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/pkg/redis"
+	"github.com/apache/answer/pkg/token"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// TopicCacheKey the key of the near cache is changed, the payload is the key
+	TopicCacheKey = "cache_key"
+	// TopicPluginStatus the status of the plugins is changed
+	TopicPluginStatus = "plugin_status"
+	// TopicPluginConfig the config of the plugin is changed, the payload is the slug name of the plugin
+	TopicPluginConfig = "plugin_config"
+
+	// InvalidateAll the payload delivered to every topic after the bus reconnected,
+	// the messages may be lost while disconnected so that everything of all tenants must be reloaded
+	InvalidateAll = "*"
+)
+
+// InvalidationHandler handle the invalidation published by the other instances
+type InvalidationHandler func(ctx context.Context, payload string)
+
+type invalidationMessage struct {
+	Source   string `json:"source"`
+	TenantID int    `json:"tenant_id"`
+	Topic    string `json:"topic"`
+	Payload  string `json:"payload"`
+}
+
+// InvalidationBus notify the other instances sharing the redis server that the in-process state is changed.
+// The instance applies the change locally by itself, the message is only delivered to the other instances.
+// Without the redis cache there is only one instance, the bus does nothing.
+type InvalidationBus struct {
+	client   *redis.Client
+	channel  string
+	instance string
+
+	mu        sync.RWMutex
+	handlers  map[string][]InvalidationHandler
+	startOnce sync.Once
+	ctx       context.Context
+	cancel    context.CancelFunc
+	pubSub    *redis.PubSub
+}
+
+// NewInvalidationBus new invalidation bus on the redis server of the cache
+func NewInvalidationBus(c *CacheConf) (*InvalidationBus, func(), error) {
+	if !c.IsRedis() || c.Redis == nil {
+		return NewLocalInvalidationBus(), func() {}, nil
+	}
+	bus := newInvalidationBus(c.Redis.newClient(), c.Redis.keyPrefix()+"invalidation")
+	return bus, func() {
+		bus.Close()
+	}, nil
+}
+
+// NewLocalInvalidationBus new invalidation bus of the single instance
+func NewLocalInvalidationBus() *InvalidationBus {
+	return newInvalidationBus(nil, "")
+}
+
+func newInvalidationBus(client *redis.Client, channel string) *InvalidationBus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &InvalidationBus{
+		client:   client,
+		channel:  channel,
+		instance: token.GenerateToken(),
+		handlers: make(map[string][]InvalidationHandler),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Publish notify the other instances, the failure is only logged as the change is already applied locally.
+// The handlers of the other instances receive the context of the tenant who publishes.
+func (b *InvalidationBus) Publish(ctx context.Context, topic, payload string) {
+	if b.client == nil {
+		return
+	}
+	content, _ := json.Marshal(&invalidationMessage{
+		Source: b.instance, TenantID: handler.GetTenantID(ctx), Topic: topic, Payload: payload})
+	if _, err := b.client.Publish(ctx, b.channel, string(content)); err != nil {
+		log.Warnf("publish invalidation %s %s failed: %v", topic, payload, err)
+	}
+}
+
+// Subscribe handle the invalidation of the topic published by the other instances
+func (b *InvalidationBus) Subscribe(topic string, handler InvalidationHandler) {
+	if b.client == nil {
+		return
+	}
+	b.mu.Lock()
+	b.handlers[topic] = append(b.handlers[topic], handler)
+	b.mu.Unlock()
+	b.startOnce.Do(func() {
+		go b.receive()
+	})
+}
+
+// Close stop receiving the invalidation
+func (b *InvalidationBus) Close() {
+	b.cancel()
+	b.mu.Lock()
+	if b.pubSub != nil {
+		_ = b.pubSub.Close()
+	}
+	b.mu.Unlock()
+	if b.client != nil {
+		_ = b.client.Close()
+	}
+}
+
+func (b *InvalidationBus) receive() {
+	backoff := time.Second
+	connected := false
+	for b.ctx.Err() == nil {
+		pubSub, err := b.client.Subscribe(b.ctx, b.channel)
+		if err != nil {
+			log.Warnf("subscribe the invalidation channel failed, retry in %s: %v", backoff, err)
+			select {
+			case <-b.ctx.Done():
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, time.Minute)
+			continue
+		}
+		b.mu.Lock()
+		b.pubSub = pubSub
+		b.mu.Unlock()
+		backoff = time.Second
+		if connected {
+			b.dispatchAll()
+		}
+		connected = true
+
+		for {
+			msg, err := pubSub.Receive()
+			if err != nil {
+				if b.ctx.Err() == nil {
+					log.Warnf("receive the invalidation failed: %v", err)
+				}
+				break
+			}
+			b.dispatch(msg.Payload)
+		}
+		_ = pubSub.Close()
+	}
+}
+
+func (b *InvalidationBus) dispatch(content string) {
+	msg := &invalidationMessage{}
+	if err := json.Unmarshal([]byte(content), msg); err != nil {
+		log.Warnf("bad invalidation message %s: %v", content, err)
+		return
+	}
+	if msg.Source == b.instance {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers[msg.Topic]
+	b.mu.RUnlock()
+	ctx := handler.WithTenantID(b.ctx, msg.TenantID)
+	for _, h := range handlers {
+		h(ctx, msg.Payload)
+	}
+}
+
+func (b *InvalidationBus) dispatchAll() {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handlers := range b.handlers {
+		for _, h := range handlers {
+			h(b.ctx, InvalidateAll)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package data
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentfault/pacman/cache"
+	"github.com/segmentfault/pacman/contrib/cache/memory"
+)
+
+// nearCache keep the strings read from the shared cache in the memory of the instance for a short time,
+// the changes are published on the bus so that the copies in the other instances are dropped immediately.
+// The counters are always read from the shared cache.
+type nearCache struct {
+	cache.Cache
+	local *memory.Cache
+	ttl   time.Duration
+	bus   *InvalidationBus
+}
+
+// NewNearCache wrap the shared cache with the memory of the instance
+func NewNearCache(shared cache.Cache, bus *InvalidationBus, ttl time.Duration) cache.Cache {
+	nc := &nearCache{Cache: shared, local: memory.NewCache(), ttl: ttl, bus: bus}
+	bus.Subscribe(TopicCacheKey, func(ctx context.Context, key string) {
+		if key == InvalidateAll {
+			_ = nc.local.Flush(ctx)
+			return
+		}
+		_ = nc.local.Del(ctx, key)
+	})
+	return nc
+}
+
+func (nc *nearCache) GetString(ctx context.Context, key string) (string, bool, error) {
+	if data, exist, _ := nc.local.GetString(ctx, key); exist {
+		return data, true, nil
+	}
+	data, exist, err := nc.Cache.GetString(ctx, key)
+	if err != nil || !exist {
+		return data, exist, err
+	}
+	_ = nc.local.SetString(ctx, key, data, nc.ttl)
+	return data, true, nil
+}
+
+func (nc *nearCache) SetString(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := nc.Cache.SetString(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	nc.invalidate(ctx, key)
+	return nil
+}
+
+func (nc *nearCache) SetInt64(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	if err := nc.Cache.SetInt64(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	nc.invalidate(ctx, key)
+	return nil
+}
+
+func (nc *nearCache) Del(ctx context.Context, key string) error {
+	if err := nc.Cache.Del(ctx, key); err != nil {
+		return err
+	}
+	nc.invalidate(ctx, key)
+	return nil
+}
+
+func (nc *nearCache) Flush(ctx context.Context) error {
+	if err := nc.Cache.Flush(ctx); err != nil {
+		return err
+	}
+	nc.invalidate(ctx, InvalidateAll)
+	return nil
+}
+
+func (nc *nearCache) invalidate(ctx context.Context, key string) {
+	if key == InvalidateAll {
+		_ = nc.local.Flush(ctx)
+	} else {
+		_ = nc.local.Del(ctx, key)
+	}
+	nc.bus.Publish(ctx, TopicCacheKey, key)
+}
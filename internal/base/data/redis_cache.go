@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package data
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/apache/answer/pkg/redis"
+	"github.com/segmentfault/pacman/cache"
+)
+
+// redisCache the cache on the redis server, all keys are prefixed so that several sites can share a server
+type redisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCache new cache on the redis server
+func NewRedisCache(client *redis.Client, prefix string) cache.Cache {
+	return &redisCache{client: client, prefix: prefix}
+}
+
+func (rc *redisCache) GetString(ctx context.Context, key string) (string, bool, error) {
+	data, err := redis.String(rc.client.Do(ctx, "GET", rc.prefix+key))
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return data, true, nil
+}
+
+func (rc *redisCache) SetString(ctx context.Context, key, value string, ttl time.Duration) error {
+	args := []any{"SET", rc.prefix + key, value}
+	if ttl > 0 {
+		args = append(args, "PX", max(ttl.Milliseconds(), 1))
+	}
+	_, err := rc.client.Do(ctx, args...)
+	return err
+}
+
+func (rc *redisCache) GetInt64(ctx context.Context, key string) (int64, bool, error) {
+	data, exist, err := rc.GetString(ctx, key)
+	if err != nil || !exist {
+		return 0, exist, err
+	}
+	value, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return value, true, nil
+}
+
+func (rc *redisCache) SetInt64(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	return rc.SetString(ctx, key, strconv.FormatInt(value, 10), ttl)
+}
+
+func (rc *redisCache) Increase(ctx context.Context, key string, value int64) (int64, error) {
+	return redis.Int64(rc.client.Do(ctx, "INCRBY", rc.prefix+key, value))
+}
+
+func (rc *redisCache) Decrease(ctx context.Context, key string, value int64) (int64, error) {
+	return redis.Int64(rc.client.Do(ctx, "DECRBY", rc.prefix+key, value))
+}
+
+func (rc *redisCache) Del(ctx context.Context, key string) error {
+	_, err := rc.client.Do(ctx, "DEL", rc.prefix+key)
+	return err
+}
+
+// Flush delete the keys of the prefix only, the other data on the server is kept
+func (rc *redisCache) Flush(ctx context.Context) error {
+	cursor := "0"
+	for {
+		reply, err := rc.client.Do(ctx, "SCAN", cursor, "MATCH", rc.prefix+"*", "COUNT", 500)
+		if err != nil {
+			return err
+		}
+		items, ok := reply.([]any)
+		if !ok || len(items) != 2 {
+			return errors.New("unexpected reply of scan")
+		}
+		cursor, _ = items[0].(string)
+		keys, _ := items[1].([]any)
+		if len(keys) > 0 {
+			if _, err = rc.client.Do(ctx, append([]any{"DEL"}, keys...)...); err != nil {
+				return err
+			}
+		}
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
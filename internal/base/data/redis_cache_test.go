@@ -0,0 +1,153 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/pkg/redis/redistest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisConf(t *testing.T, nearCacheTTL int) (*redistest.Server, *CacheConf) {
+	server, err := redistest.Run("secret")
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+	return server, &CacheConf{
+		Type: CacheTypeRedis,
+		Redis: &RedisConf{
+			Addr:         server.Addr(),
+			Password:     "secret",
+			KeyPrefix:    "test:",
+			NearCacheTTL: nearCacheTTL,
+		},
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	server, conf := newTestRedisConf(t, -1)
+	ctx := context.Background()
+	c, cleanup, err := NewCache(conf)
+	require.NoError(t, err)
+	defer cleanup()
+
+	_, exist, err := c.GetString(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	require.NoError(t, c.SetString(ctx, "name", "answer", time.Minute))
+	value, exist, err := c.GetString(ctx, "name")
+	require.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, "answer", value)
+	_, exist = server.Get("test:name")
+	assert.True(t, exist)
+
+	require.NoError(t, c.SetString(ctx, "expired", "answer", time.Second))
+	server.FastForward(2 * time.Second)
+	_, exist, err = c.GetString(ctx, "expired")
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	require.NoError(t, c.SetInt64(ctx, "count", 10, 0))
+	n, err := c.Increase(ctx, "count", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), n)
+	n, err = c.Decrease(ctx, "count", 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), n)
+	n, exist, err = c.GetInt64(ctx, "count")
+	require.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, int64(12), n)
+
+	require.NoError(t, c.Del(ctx, "count"))
+	_, exist, err = c.GetInt64(ctx, "count")
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	// the keys out of the prefix are kept by flush
+	other, otherCleanup, err := NewCache(&CacheConf{Type: CacheTypeRedis, Redis: &RedisConf{
+		Addr: conf.Redis.Addr, Password: "secret", KeyPrefix: "other:", NearCacheTTL: -1}})
+	require.NoError(t, err)
+	defer otherCleanup()
+	require.NoError(t, other.SetString(ctx, "name", "other", 0))
+	require.NoError(t, c.Flush(ctx))
+	_, exist = server.Get("test:name")
+	assert.False(t, exist)
+	_, exist = server.Get("other:name")
+	assert.True(t, exist)
+}
+
+func TestNearCacheInvalidation(t *testing.T) {
+	_, conf := newTestRedisConf(t, 60)
+	ctx := context.Background()
+
+	busA, cleanupBusA, err := NewInvalidationBus(conf)
+	require.NoError(t, err)
+	defer cleanupBusA()
+	cacheA, cleanupA, err := NewCacheWithBus(conf, busA)
+	require.NoError(t, err)
+	defer cleanupA()
+	busB, cleanupBusB, err := NewInvalidationBus(conf)
+	require.NoError(t, err)
+	defer cleanupBusB()
+	cacheB, cleanupB, err := NewCacheWithBus(conf, busB)
+	require.NoError(t, err)
+	defer cleanupB()
+
+	received := make(chan string, 1)
+	busB.Subscribe(TopicPluginConfig, func(ctx context.Context, payload string) {
+		received <- payload
+	})
+	// the subscription of the bus is asynchronous, wait until the messages are delivered
+	require.Eventually(t, func() bool {
+		busA.Publish(ctx, TopicPluginConfig, "2")
+		select {
+		case payload := <-received:
+			return payload == "2"
+		default:
+			return false
+		}
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, cacheA.SetString(ctx, "token", "user1", time.Hour))
+	value, _, err := cacheB.GetString(ctx, "token")
+	require.NoError(t, err)
+	assert.Equal(t, "user1", value)
+
+	// the near cache of B is dropped by the invalidation from A
+	require.NoError(t, cacheA.Del(ctx, "token"))
+	assert.Eventually(t, func() bool {
+		_, exist, err := cacheB.GetString(ctx, "token")
+		return err == nil && !exist
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, cacheA.SetString(ctx, "site", "v1", time.Hour))
+	_, _, _ = cacheB.GetString(ctx, "site")
+	require.NoError(t, cacheA.SetString(ctx, "site", "v2", time.Hour))
+	assert.Eventually(t, func() bool {
+		value, _, err := cacheB.GetString(ctx, "site")
+		return err == nil && value == "v2"
+	}, 5*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,131 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/entity"
+	"github.com/segmentfault/pacman/cache"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// DB the database shared by the primary site and the tenants, the rows of the tenants are told apart by the tenant_id
+type DB struct {
+	*xorm.Engine
+}
+
+// WrapEngine wrap the engine as the database of the site
+func WrapEngine(engine *xorm.Engine) *DB {
+	return &DB{Engine: engine}
+}
+
+// transactionKey the key of the session of the transaction in the context
+type transactionKey struct{}
+
+// Context opens a session with the context, the session of the transaction is returned if the context is in one
+func (db *DB) Context(ctx context.Context) *xorm.Session {
+	if session, ok := ctx.Value(transactionKey{}).(*xorm.Session); ok {
+		return session
+	}
+	return db.Engine.Context(ctx)
+}
+
+// Transaction executes the function in a transaction, the function joins the transaction of the context if there is one
+func (db *DB) Transaction(ctx context.Context, f func(*xorm.Session) (any, error)) (any, error) {
+	if session, ok := ctx.Value(transactionKey{}).(*xorm.Session); ok {
+		return f(session)
+	}
+	return db.Engine.Transaction(func(session *xorm.Session) (any, error) {
+		return f(session.Context(ctx))
+	})
+}
+
+// TransactionContext executes the function in a transaction, the repositories called with the context
+// passed to the function run their queries in the transaction. The context must not be used after the function returns.
+func (db *DB) TransactionContext(ctx context.Context, f func(ctx context.Context) error) error {
+	_, err := db.Transaction(ctx, func(session *xorm.Session) (any, error) {
+		return nil, f(context.WithValue(ctx, transactionKey{}, session))
+	})
+	return err
+}
+
+// TenantCond the condition of the rows of the tenant in the context.
+// The column is qualified by the table if it is given, which is required when the query joins the other tables.
+func TenantCond(ctx context.Context, table ...string) builder.Cond {
+	column := "tenant_id"
+	if len(table) > 0 {
+		column = "`" + table[0] + "`.tenant_id"
+	}
+	return builder.Eq{column: handler.GetTenantID(ctx)}
+}
+
+// tenantCache the cache keys are prefixed by the tenant in the context, so that the tenants never share the cache
+type tenantCache struct {
+	cache.Cache
+}
+
+// NewTenantCache wrap the cache with the tenant key prefix
+func NewTenantCache(c cache.Cache) cache.Cache {
+	if tc, ok := c.(*tenantCache); ok {
+		return tc
+	}
+	return &tenantCache{Cache: c}
+}
+
+func tenantCacheKey(ctx context.Context, key string) string {
+	tenantID := handler.GetTenantID(ctx)
+	if tenantID == entity.DefaultTenantID {
+		return key
+	}
+	return fmt.Sprintf("tenant:%d:%s", tenantID, key)
+}
+
+func (tc *tenantCache) GetString(ctx context.Context, key string) (string, bool, error) {
+	return tc.Cache.GetString(ctx, tenantCacheKey(ctx, key))
+}
+
+func (tc *tenantCache) SetString(ctx context.Context, key, value string, ttl time.Duration) error {
+	return tc.Cache.SetString(ctx, tenantCacheKey(ctx, key), value, ttl)
+}
+
+func (tc *tenantCache) GetInt64(ctx context.Context, key string) (int64, bool, error) {
+	return tc.Cache.GetInt64(ctx, tenantCacheKey(ctx, key))
+}
+
+func (tc *tenantCache) SetInt64(ctx context.Context, key string, value int64, ttl time.Duration) error {
+	return tc.Cache.SetInt64(ctx, tenantCacheKey(ctx, key), value, ttl)
+}
+
+func (tc *tenantCache) Increase(ctx context.Context, key string, value int64) (int64, error) {
+	return tc.Cache.Increase(ctx, tenantCacheKey(ctx, key), value)
+}
+
+func (tc *tenantCache) Decrease(ctx context.Context, key string, value int64) (int64, error) {
+	return tc.Cache.Decrease(ctx, tenantCacheKey(ctx, key), value)
+}
+
+func (tc *tenantCache) Del(ctx context.Context, key string) error {
+	return tc.Cache.Del(ctx, tenantCacheKey(ctx, key))
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package handler
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/entity"
+)
+
+// GetTenantID get the tenant id from context, the primary site is returned if no tenant is resolved
+func GetTenantID(ctx context.Context) int {
+	if tenantID, ok := ctx.Value(constant.TenantIDContextKey).(int); ok {
+		return tenantID
+	}
+	// The gin context returns the value set by TenantMiddleware via ctx.Set
+	if tenantID, ok := ctx.Value(constant.TenantIDFlag).(int); ok {
+		return tenantID
+	}
+	return entity.DefaultTenantID
+}
+
+// WithTenantID returns the context for the tenant, it is used by the jobs running outside of requests
+func WithTenantID(ctx context.Context, tenantID int) context.Context {
+	return context.WithValue(ctx, constant.TenantIDContextKey, tenantID)
+}
+
+// IsPrimarySite whether the context is of the primary site
+func IsPrimarySite(ctx context.Context) bool {
+	return GetTenantID(ctx) == entity.DefaultTenantID
+}
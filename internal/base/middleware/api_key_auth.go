@@ -22,30 +22,73 @@ package middleware
 import (
 	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/role"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
 )
 
 // AuthAPIKey middleware to authenticate API key
 func (am *AuthUserMiddleware) AuthAPIKey() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		token := ExtractToken(ctx)
-		if len(token) == 0 {
-			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
-			ctx.Abort()
-			return
-		}
-		pass, err := am.authService.AuthAPIKey(ctx, ctx.Request.Method == "GET", token)
-		if err != nil {
+		if _, pass := am.authAPIKeyOwner(ctx); !pass {
 			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
 			ctx.Abort()
 			return
 		}
+		ctx.Next()
+	}
+}
+
+// AuthAPIKeyAsOwner middleware to authenticate API key, the request is made on behalf of the administrator
+// who created the key, so the login user is the owner of the key
+func (am *AuthUserMiddleware) AuthAPIKeyAsOwner() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		owner, pass := am.authAPIKeyOwner(ctx)
 		if !pass {
 			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
 			ctx.Abort()
 			return
 		}
+		ctx.Set(ctxUUIDKey, owner)
 		ctx.Next()
 	}
 }
+
+// authAPIKeyOwner authenticate the API key of the request, the key only works while its owner is still
+// an available administrator, so that the key is revoked along with the role or the account of the owner
+func (am *AuthUserMiddleware) authAPIKeyOwner(ctx *gin.Context) (owner *entity.UserCacheInfo, pass bool) {
+	token := ExtractToken(ctx)
+	if len(token) == 0 {
+		return nil, false
+	}
+	ownerID, pass, err := am.authService.AuthAPIKeyOwner(ctx, ctx.Request.Method == "GET", token)
+	if err != nil || !pass {
+		return nil, false
+	}
+	userInfo, exist, err := am.userRepo.GetByUserID(ctx, ownerID)
+	if err != nil {
+		log.Error(err)
+		return nil, false
+	}
+	if !exist || userInfo.Status != entity.UserStatusAvailable || !userInfo.DeletedAt.IsZero() {
+		log.Warnf("the owner %s of the API key is not available", ownerID)
+		return nil, false
+	}
+	roleID, err := am.userRoleRelService.GetUserRole(ctx, ownerID)
+	if err != nil {
+		log.Error(err)
+		return nil, false
+	}
+	if roleID != role.RoleAdminID {
+		log.Warnf("the owner %s of the API key is not an administrator", ownerID)
+		return nil, false
+	}
+	return &entity.UserCacheInfo{
+		UserID:      userInfo.ID,
+		UserStatus:  userInfo.Status,
+		EmailStatus: userInfo.MailStatus,
+		RoleID:      roleID,
+	}, true
+}
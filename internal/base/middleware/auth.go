@@ -22,10 +22,12 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/role"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	usercommon "github.com/apache/answer/internal/service/user_common"
 	"github.com/apache/answer/ui"
 	"github.com/gin-gonic/gin"
 
@@ -44,15 +46,21 @@ var ctxUUIDKey = "ctxUuidKey"
 type AuthUserMiddleware struct {
 	authService           *auth.AuthService
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService
+	userRepo              usercommon.UserRepo
+	userRoleRelService    *role.UserRoleRelService
 }
 
 // NewAuthUserMiddleware new auth user middleware
 func NewAuthUserMiddleware(
 	authService *auth.AuthService,
-	siteInfoCommonService siteinfo_common.SiteInfoCommonService) *AuthUserMiddleware {
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+	userRepo usercommon.UserRepo,
+	userRoleRelService *role.UserRoleRelService) *AuthUserMiddleware {
 	return &AuthUserMiddleware{
 		authService:           authService,
 		siteInfoCommonService: siteInfoCommonService,
+		userRepo:              userRepo,
+		userRoleRelService:    userRoleRelService,
 	}
 }
 
@@ -69,7 +77,7 @@ func (am *AuthUserMiddleware) Auth() gin.HandlerFunc {
 			ctx.Next()
 			return
 		}
-		if userInfo != nil {
+		if userInfo != nil && am.checkUserSession(ctx, token) {
 			ctx.Set(ctxUUIDKey, userInfo)
 		}
 		ctx.Next()
@@ -121,7 +129,7 @@ func (am *AuthUserMiddleware) MustAuthWithoutAccountAvailable() gin.HandlerFunc
 			return
 		}
 		userInfo, err := am.authService.GetUserCacheInfo(ctx, token)
-		if err != nil || userInfo == nil {
+		if err != nil || userInfo == nil || !am.checkUserSession(ctx, token) {
 			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
 			ctx.Abort()
 			return
@@ -150,7 +158,7 @@ func (am *AuthUserMiddleware) MustAuthAndAccountAvailable() gin.HandlerFunc {
 			return
 		}
 		userInfo, err := am.authService.GetUserCacheInfo(ctx, token)
-		if err != nil || userInfo == nil {
+		if err != nil || userInfo == nil || !am.checkUserSession(ctx, token) {
 			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
 			ctx.Abort()
 			return
@@ -191,6 +199,11 @@ func (am *AuthUserMiddleware) AdminAuth() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
+		if !am.checkUserSession(ctx, token) {
+			handler.HandleResponse(ctx, errors.Unauthorized(reason.UnauthorizedError), nil)
+			ctx.Abort()
+			return
+		}
 		if userInfo != nil {
 			if userInfo.EmailStatus == entity.EmailStatusToBeVerified {
 				_ = am.authService.RemoveAdminUserCacheInfo(ctx, token)
@@ -218,6 +231,34 @@ func (am *AuthUserMiddleware) AdminAuth() gin.HandlerFunc {
 	}
 }
 
+// MustRecentAuth the sensitive actions require the user has confirmed their identity recently,
+// either by signing in or by the reauthentication, a session alone is not enough.
+func (am *AuthUserMiddleware) MustRecentAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !am.authService.CheckUserRecentAuth(ctx, ExtractToken(ctx)) {
+			handler.HandleResponse(ctx, errors.Forbidden(reason.RecentAuthRequired),
+				&schema.ForbiddenResp{Type: schema.ForbiddenReasonTypeRecentAuth})
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// checkUserSession check the idle and absolute timeouts of the session configured in the site security settings
+func (am *AuthUserMiddleware) checkUserSession(ctx *gin.Context, token string) (valid bool) {
+	siteSecurity, err := am.siteInfoCommonService.GetSiteSecurity(ctx)
+	if err != nil {
+		// without the settings the timeouts can not be checked, the session is rejected rather than let live forever
+		log.Error(err)
+		return false
+	}
+	idleTimeout := time.Duration(siteSecurity.SessionIdleTimeout) * time.Minute
+	absoluteTimeout := time.Duration(siteSecurity.SessionAbsoluteTimeout) * time.Minute
+	return am.authService.CheckUserSession(ctx, token, ctx.ClientIP(), ctx.Request.UserAgent(),
+		idleTimeout, absoluteTimeout)
+}
+
 func (am *AuthUserMiddleware) CheckPrivateMode() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		resp, err := am.siteInfoCommonService.GetSiteSecurity(ctx)
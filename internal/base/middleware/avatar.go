@@ -57,7 +57,7 @@ func (am *AvatarMiddleware) AvatarThumb() gin.HandlerFunc {
 			size := converter.StringToInt(ctx.Query("s"))
 			uriWithoutQuery, _ := url.Parse(uri)
 			filename := filepath.Base(uriWithoutQuery.Path)
-			filePath := fmt.Sprintf("%s/avatar/%s", am.serviceConfig.UploadPath, filename)
+			filePath := fmt.Sprintf("%s/avatar/%s", am.serviceConfig.GetUploadPath(ctx), filename)
 			var err error
 			if size != 0 {
 				filePath, err = am.uploaderService.AvatarThumbFile(ctx, filename, size)
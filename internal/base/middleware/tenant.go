@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package middleware
+
+import (
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+type TenantMiddleware struct {
+	tenantService *tenant.TenantService
+}
+
+func NewTenantMiddleware(tenantService *tenant.TenantService) *TenantMiddleware {
+	return &TenantMiddleware{
+		tenantService: tenantService,
+	}
+}
+
+// ResolveTenant resolve the tenant from the request host, all the following handlers work on its data
+func (tm *TenantMiddleware) ResolveTenant() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tenantID, err := tm.tenantService.ResolveTenantID(ctx, ctx.Request.Host)
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			ctx.Abort()
+			return
+		}
+		if tenantID != entity.DefaultTenantID {
+			ctx.Set(constant.TenantIDFlag, tenantID)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/segmentfault/pacman/log"
 )
 
@@ -41,18 +42,24 @@ type Service[T any] interface {
 // It is thread-safe and supports graceful shutdown.
 type Queue[T any] struct {
 	name    string
-	queue   chan T
+	queue   chan message[T]
 	handler func(ctx context.Context, msg T) error
 	mu      sync.RWMutex
 	closed  bool
 	wg      sync.WaitGroup
 }
 
+// message the queued message and the tenant of the context it was sent from
+type message[T any] struct {
+	tenantID int
+	msg      T
+}
+
 // New creates a new queue with the given name and buffer size.
 func New[T any](name string, bufferSize int) *Queue[T] {
 	q := &Queue[T]{
 		name:  name,
-		queue: make(chan T, bufferSize),
+		queue: make(chan message[T], bufferSize),
 	}
 	q.startWorker()
 	return q
@@ -70,7 +77,7 @@ func (q *Queue[T]) Send(ctx context.Context, msg T) {
 	}
 
 	select {
-	case q.queue <- msg:
+	case q.queue <- message[T]{tenantID: handler.GetTenantID(ctx), msg: msg}:
 		log.Debugf("[%s] enqueued message: %+v", q.name, msg)
 	case <-ctx.Done():
 		log.Warnf("[%s] context cancelled while sending message", q.name)
@@ -103,14 +110,18 @@ func (q *Queue[T]) Close() {
 // startWorker starts the background goroutine that processes messages.
 func (q *Queue[T]) startWorker() {
 	q.wg.Go(func() {
-		for msg := range q.queue {
-			q.processMessage(msg)
+		for m := range q.queue {
+			q.processMessage(m.tenantID, m.msg)
 		}
 	})
 }
 
 // processMessage handles a single message with proper synchronization.
-func (q *Queue[T]) processMessage(msg T) {
+func (q *Queue[T]) processMessage(tenantID int, msg T) {
+	// Use background context for async processing, the message is handled for the tenant it was sent from
+	// TODO: Consider adding timeout or using a derived context
+	ctx := handler.WithTenantID(context.TODO(), tenantID)
+
 	q.mu.RLock()
 	handler := q.handler
 	q.mu.RUnlock()
@@ -120,9 +131,7 @@ func (q *Queue[T]) processMessage(msg T) {
 		return
 	}
 
-	// Use background context for async processing
-	// TODO: Consider adding timeout or using a derived context
-	if err := handler(context.TODO(), msg); err != nil {
+	if err := handler(ctx, msg); err != nil {
 		log.Errorf("[%s] handler error: %v", q.name, err)
 	}
 }
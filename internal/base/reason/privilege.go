@@ -47,4 +47,6 @@ const (
 	RankTagAuditLabel                  = "privilege.rank_tag_audit_label"
 	RankTagEditWithoutReviewLabel      = "privilege.rank_tag_edit_without_review_label"
 	RankTagSynonymLabel                = "privilege.rank_tag_synonym_label"
+	RankQuestionCloseVoteLabel         = "privilege.rank_question_close_vote_label"
+	RankQuestionBountyLabel            = "privilege.rank_question_bounty_label"
 )
@@ -46,8 +46,13 @@ const (
 	QuestionCannotUpdate             = "error.question.cannot_update"
 	QuestionAlreadyDeleted           = "error.question.already_deleted"
 	QuestionUnderReview              = "error.question.under_review"
+	QuestionScheduled                = "error.question.scheduled"
 	QuestionContentCannotEmpty       = "error.question.content_cannot_empty"
 	QuestionContentLessThanMinimum   = "error.question.content_less_than_minimum"
+	QuestionDuplicateInvalid         = "error.question.duplicate_invalid"
+	QuestionAlreadyClosed            = "error.question.already_closed"
+	QuestionAlreadyVotedToClose      = "error.question.already_voted_to_close"
+	QuestionNotClosed                = "error.question.not_closed"
 	AnswerNotFound                   = "error.answer.not_found"
 	AnswerCannotDeleted              = "error.answer.cannot_deleted"
 	AnswerCannotUpdate               = "error.answer.cannot_update"
@@ -79,10 +84,75 @@ const (
 	TagIsUsedCannotDelete            = "error.tag.is_used_cannot_delete"
 	TagAlreadyExist                  = "error.tag.already_exist"
 	TagMinCount                      = "error.tag.minimum_count"
+	TagParentInvalid                 = "error.tag.parent_invalid"
+	TagHierarchyTooDeep              = "error.tag.hierarchy_too_deep"
+	TagCompanionRequired             = "error.tag.companion_required"
+	TagOwnerLimitExceeded            = "error.tag.owner_limit_exceeded"
+	TagSuggestionTraining            = "error.tag.suggestion_training"
 	RankFailToMeetTheCondition       = "error.rank.fail_to_meet_the_condition"
 	VoteRankFailToMeetTheCondition   = "error.rank.vote_fail_to_meet_the_condition"
 	NoEnoughRankToOperate            = "error.rank.no_enough_rank_to_operate"
 	ThemeNotFound                    = "error.theme.not_found"
+	SavedSearchNotFound              = "error.saved_search.not_found"
+	BountyNotFound                   = "error.bounty.not_found"
+	BountyAlreadyOpen                = "error.bounty.already_open"
+	BountyAmountInvalid              = "error.bounty.amount_invalid"
+	BountyRankNotEnough              = "error.bounty.rank_not_enough"
+	BountyAnswerNotEligible          = "error.bounty.answer_not_eligible"
+	DraftNotFound                    = "error.draft.not_found"
+	DraftLimitExceeded               = "error.draft.limit_exceeded"
+	ScheduledPostNotFound            = "error.scheduled_post.not_found"
+	ScheduledPostTimeInvalid         = "error.scheduled_post.time_invalid"
+	SpaceNotFound                    = "error.space.not_found"
+	SpaceSlugNameExists              = "error.space.slug_name_exists"
+	SpaceNoPermission                = "error.space.no_permission"
+	SpaceMemberNotFound              = "error.space.member_not_found"
+	SpaceOwnerRequired               = "error.space.owner_required"
+	TagNotInSpace                    = "error.tag.not_in_space"
+	TenantNotFound                   = "error.tenant.not_found"
+	TenantHostExists                 = "error.tenant.host_exists"
+	TenantUnavailable                = "error.tenant.unavailable"
+	TenantPrimarySiteOnly            = "error.tenant.primary_site_only"
+	TenantPluginConfigUnsupported    = "error.tenant.plugin_config_unsupported"
+	TenantPluginDisabled             = "error.tenant.plugin_disabled"
+	UserDataExportInProgress         = "error.user_data.export_in_progress"
+	UserDataExportTooFrequent        = "error.user_data.export_too_frequent"
+	UserDataExportNotFound           = "error.user_data.export_not_found"
+	UserErasureAlreadyRequested      = "error.user_data.erasure_already_requested"
+	UserErasureNotFound              = "error.user_data.erasure_not_found"
+	UserErasureAdminForbidden        = "error.user_data.erasure_admin_forbidden"
+	UserErasurePasswordWrong         = "error.user_data.erasure_password_wrong"
+	SSOProviderNotFound              = "error.sso.provider_not_found"
+	SSOProviderSlugExists            = "error.sso.slug_exists"
+	SSOProviderSlugInvalid           = "error.sso.slug_invalid"
+	SSOProviderConfigInvalid         = "error.sso.config_invalid"
+	SSOUserDeactivated               = "error.sso.user_deactivated"
+	SCIMFilterInvalid                = "error.scim.filter_invalid"
+	SCIMPatchInvalid                 = "error.scim.patch_invalid"
+	SCIMUserNameExists               = "error.scim.user_name_exists"
+	SCIMEmailRequired                = "error.scim.email_required"
+	SCIMGroupNotFound                = "error.scim.group_not_found"
+	SCIMGroupReadOnly                = "error.scim.group_read_only"
+	SCIMLastAdmin                    = "error.scim.last_admin"
+	TwoFactorCodeInvalid             = "error.two_factor.code_invalid"
+	TwoFactorChallengeInvalid        = "error.two_factor.challenge_invalid"
+	TwoFactorSetupExpired            = "error.two_factor.setup_expired"
+	TwoFactorAlreadyEnabled          = "error.two_factor.already_enabled"
+	TwoFactorNotEnabled              = "error.two_factor.not_enabled"
+	TwoFactorRequired                = "error.two_factor.required"
+	TwoFactorEnrollFirst             = "error.two_factor.enroll_first"
+	WebAuthnVerifyFailed             = "error.two_factor.webauthn_verify_failed"
+	WebAuthnCredentialNotFound       = "error.two_factor.webauthn_credential_not_found"
+	RecentAuthRequired               = "error.two_factor.recent_auth_required"
+	RecentAuthPasswordWrong          = "error.two_factor.password_wrong"
+	RecentAuthSecondFactorRequired   = "error.two_factor.second_factor_required"
+	UserSessionNotFound              = "error.user_session.not_found"
+	RoleNotFound                     = "error.role.not_found"
+	RoleNameDuplicate                = "error.role.name_duplicate"
+	RoleBuiltInCannotModify          = "error.role.built_in_cannot_modify"
+	RoleInUse                        = "error.role.in_use"
+	RolePowerInvalid                 = "error.role.power_invalid"
+	SavedSearchLimitExceeded         = "error.saved_search.limit_exceeded"
 	LangNotFound                     = "error.lang.not_found"
 	ReportHandleFailed               = "error.report.handle_failed"
 	ReportNotFound                   = "error.report.not_found"
@@ -42,6 +42,7 @@ func NewHTTPServer(debug bool,
 	authUserMiddleware *middleware.AuthUserMiddleware,
 	avatarMiddleware *middleware.AvatarMiddleware,
 	shortIDMiddleware *middleware.ShortIDMiddleware,
+	tenantMiddleware *middleware.TenantMiddleware,
 	templateRouter *router.TemplateRouter,
 	pluginAPIRouter *router.PluginAPIRouter,
 	uiConf *UI,
@@ -56,6 +57,7 @@ func NewHTTPServer(debug bool,
 		uiConf.APIBaseURL+"/answer/api/v1",
 		uiConf.APIBaseURL+"/answer/admin/api",
 	))
+	r.Use(tenantMiddleware.ResolveTenant())
 	r.Use(func(ctx *gin.Context) {
 		if strings.Contains(ctx.Request.URL.Path, "/chat/completions") {
 			return
@@ -122,5 +124,10 @@ func NewHTTPServer(debug bool,
 	mcpAPIGroup := r.Group(uiConf.APIBaseURL + "/answer/api/v1")
 	mcpAPIGroup.Use(authUserMiddleware.AuthMcpEnable(), authUserMiddleware.AuthAPIKey())
 	answerRouter.RegisterMCPRouter(mcpAPIGroup)
+
+	// scim
+	scimGroup := r.Group(uiConf.APIBaseURL + "/scim/v2")
+	scimGroup.Use(authUserMiddleware.AuthAPIKeyAsOwner())
+	answerRouter.RegisterSCIMRouter(scimGroup)
 	return r
 }
@@ -0,0 +1,438 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package cli
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/migrations"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+const (
+	// exportFormatVersion the version of the archive format, increase it when the layout of the archive changes
+	exportFormatVersion  = 1
+	exportManifestFile   = "manifest.json"
+	exportTablesDir      = "tables/"
+	exportUploadsDir     = "uploads/"
+	exportTableExtension = ".jsonl"
+)
+
+// exportManifest describes the content of the exported archive
+type exportManifest struct {
+	FormatVersion int                   `json:"format_version"`
+	AnswerVersion string                `json:"answer_version"`
+	DBVersion     int64                 `json:"db_version"`
+	Driver        string                `json:"driver"`
+	CreatedAt     time.Time             `json:"created_at"`
+	Tables        []exportManifestTable `json:"tables"`
+	Files         int                   `json:"files"`
+}
+
+type exportManifestTable struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+// exportSkipTable the tables are not exported. The version is kept in the manifest.
+// The tenants are exported with the site as their data is in the same database.
+func exportSkipTable(bean any) bool {
+	switch bean.(type) {
+	case *entity.Version:
+		return true
+	}
+	return false
+}
+
+// ExportSite export all the data and the uploaded files of the site to a database-agnostic archive.
+// Each table is written as JSON Lines keyed by column name, so it can be restored into any supported database.
+func ExportSite(dataConf *data.Database, uploadPath, exportPath string) (string, error) {
+	engine, err := data.NewDB(false, dataConf)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = engine.Close()
+	}()
+	if err = engine.Ping(); err != nil {
+		return "", err
+	}
+	dbVersion, err := migrations.GetCurrentDBVersion(engine)
+	if err != nil {
+		return "", err
+	}
+	if dbVersion != migrations.ExpectedVersion() {
+		return "", fmt.Errorf("db version %d is not the latest version %d, run upgrade first", dbVersion, migrations.ExpectedVersion())
+	}
+
+	name := filepath.Join(exportPath, fmt.Sprintf("answer_export_%s.zip", time.Now().Format("2006-01-02")))
+	file, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	zw := zip.NewWriter(file)
+
+	manifest := &exportManifest{
+		FormatVersion: exportFormatVersion,
+		AnswerVersion: constant.Version,
+		DBVersion:     dbVersion,
+		Driver:        dataConf.Driver,
+		CreatedAt:     time.Now(),
+	}
+	for _, bean := range migrations.Tables() {
+		if exportSkipTable(bean) {
+			continue
+		}
+		table, err := engine.TableInfo(bean)
+		if err != nil {
+			return "", err
+		}
+		w, err := createArchiveFile(zw, exportTablesDir+table.Name+exportTableExtension, time.Now())
+		if err != nil {
+			return "", err
+		}
+		rows, err := exportTable(engine, table, bean, w)
+		if err != nil {
+			return "", fmt.Errorf("export table %s failed: %w", table.Name, err)
+		}
+		fmt.Printf("[export] table %s: %d rows\n", table.Name, rows)
+		manifest.Tables = append(manifest.Tables, exportManifestTable{Name: table.Name, Rows: rows})
+	}
+
+	manifest.Files, err = exportUploads(zw, uploadPath)
+	if err != nil {
+		return "", fmt.Errorf("export uploaded files failed: %w", err)
+	}
+	fmt.Printf("[export] uploaded files: %d\n", manifest.Files)
+
+	w, err := createArchiveFile(zw, exportManifestFile, manifest.CreatedAt)
+	if err != nil {
+		return "", err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(manifest); err != nil {
+		return "", err
+	}
+	if err = zw.Close(); err != nil {
+		return "", err
+	}
+	return name, file.Close()
+}
+
+// exportTable write all rows of the table to w, one JSON object per line
+func exportTable(engine *xorm.Engine, table *schemas.Table, bean any, w io.Writer) (count int64, err error) {
+	beanType := reflect.TypeOf(bean).Elem()
+	session := engine.NewSession()
+	defer session.Close()
+	for _, pk := range table.PrimaryKeys {
+		session.Asc(pk)
+	}
+	rows, err := session.Rows(reflect.New(beanType).Interface())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	encoder := json.NewEncoder(w)
+	for rows.Next() {
+		row := reflect.New(beanType).Interface()
+		if err = rows.Scan(row); err != nil {
+			return count, err
+		}
+		record := make(map[string]any, len(table.Columns()))
+		for _, col := range table.Columns() {
+			value, err := col.ValueOf(row)
+			if err != nil {
+				return count, err
+			}
+			record[col.Name] = value.Interface()
+		}
+		if err = encoder.Encode(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// exportUploads add all the uploaded files to the archive
+func exportUploads(zw *zip.Writer, uploadPath string) (count int, err error) {
+	err = filepath.WalkDir(uploadPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(uploadPath, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		w, err := createArchiveFile(zw, exportUploadsDir+filepath.ToSlash(rel), info.ModTime())
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = src.Close()
+		}()
+		if _, err = io.Copy(w, src); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+func createArchiveFile(zw *zip.Writer, name string, modified time.Time) (io.Writer, error) {
+	return zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+}
+
+// ImportSite restore the archive exported by ExportSite into an empty database of any supported driver.
+// The data exported from an older version is upgraded by the pending migrations after restoring.
+func ImportSite(debug bool, dataConf *data.Database, cacheConf *data.CacheConf, uploadPath, archivePath string) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = zr.Close()
+	}()
+	manifest, err := readExportManifest(&zr.Reader)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[import] archive exported by Answer %s from %s at %s\n",
+		manifest.AnswerVersion, manifest.Driver, manifest.CreatedAt.Format(time.RFC3339))
+
+	engine, err := data.NewDB(debug, dataConf)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = engine.Close()
+	}()
+	if err = engine.Ping(); err != nil {
+		return err
+	}
+	exist, err := engine.IsTableExist(&entity.Version{})
+	if err != nil {
+		return err
+	}
+	if exist {
+		return fmt.Errorf("the database is already initialized, the archive can only be imported into an empty database")
+	}
+
+	ctx := context.Background()
+	if err = migrations.InitSchema(ctx, engine, manifest.DBVersion); err != nil {
+		return err
+	}
+	beans := make(map[string]any)
+	for _, bean := range migrations.Tables() {
+		table, err := engine.TableInfo(bean)
+		if err != nil {
+			return err
+		}
+		beans[table.Name] = bean
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	for _, t := range manifest.Tables {
+		bean, ok := beans[t.Name]
+		if !ok || exportSkipTable(bean) {
+			fmt.Printf("[import] skip unknown table %s\n", t.Name)
+			continue
+		}
+		f, ok := files[exportTablesDir+t.Name+exportTableExtension]
+		if !ok {
+			return fmt.Errorf("table %s is missing in the archive", t.Name)
+		}
+		rows, err := importTable(ctx, engine, bean, f)
+		if err != nil {
+			return fmt.Errorf("import table %s failed: %w", t.Name, err)
+		}
+		fmt.Printf("[import] table %s: %d rows\n", t.Name, rows)
+	}
+
+	count, err := importUploads(&zr.Reader, uploadPath)
+	if err != nil {
+		return fmt.Errorf("import uploaded files failed: %w", err)
+	}
+	fmt.Printf("[import] uploaded files: %d\n", count)
+	_ = engine.Close()
+
+	return migrations.Migrate(debug, dataConf, cacheConf, "")
+}
+
+func readExportManifest(zr *zip.Reader) (*exportManifest, error) {
+	f, err := zr.Open(exportManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest failed, the file is not an Answer export archive: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	manifest := &exportManifest{}
+	if err = json.NewDecoder(f).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest failed: %w", err)
+	}
+	if manifest.FormatVersion > exportFormatVersion {
+		return nil, fmt.Errorf("archive format version %d is not supported, upgrade Answer first", manifest.FormatVersion)
+	}
+	return manifest, nil
+}
+
+// importTable insert all rows of the table file in one transaction
+func importTable(ctx context.Context, engine *xorm.Engine, bean any, f *zip.File) (count int64, err error) {
+	table, err := engine.TableInfo(bean)
+	if err != nil {
+		return 0, err
+	}
+	r, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	beanType := reflect.TypeOf(bean).Elem()
+	_, err = engine.Transaction(func(session *xorm.Session) (any, error) {
+		session = session.Context(ctx)
+		decoder := json.NewDecoder(r)
+		for {
+			record := make(map[string]json.RawMessage)
+			if err := decoder.Decode(&record); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil, nil
+				}
+				return nil, err
+			}
+			row := reflect.New(beanType).Interface()
+			for _, col := range table.Columns() {
+				raw, ok := record[col.Name]
+				if !ok {
+					continue
+				}
+				value, err := col.ValueOf(row)
+				if err != nil {
+					return nil, err
+				}
+				if err := json.Unmarshal(raw, value.Addr().Interface()); err != nil {
+					return nil, fmt.Errorf("parse column %s failed: %w", col.Name, err)
+				}
+			}
+			if _, err := session.NoAutoTime().Insert(row); err != nil {
+				return nil, err
+			}
+			count++
+		}
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, resetSequence(engine, table)
+}
+
+// resetSequence move the sequence of the auto increment column after the restored rows,
+// only postgres doesn't adjust it when the ids are inserted explicitly.
+func resetSequence(engine *xorm.Engine, table *schemas.Table) error {
+	if engine.Dialect().URI().DBType != schemas.POSTGRES || len(table.AutoIncrement) == 0 {
+		return nil
+	}
+	_, err := engine.Exec(fmt.Sprintf(
+		`SELECT setval(pg_get_serial_sequence('"%[1]s"', '%[2]s'), (SELECT COALESCE(MAX("%[2]s"), 0) + 1 FROM "%[1]s"), false)`,
+		table.Name, table.AutoIncrement))
+	return err
+}
+
+// importUploads extract the uploaded files in the archive to the upload path
+func importUploads(zr *zip.Reader, uploadPath string) (count int, err error) {
+	for _, f := range zr.File {
+		name, ok := strings.CutPrefix(f.Name, exportUploadsDir)
+		if !ok || f.FileInfo().IsDir() {
+			continue
+		}
+		if !filepath.IsLocal(name) {
+			return count, fmt.Errorf("invalid file path %s", f.Name)
+		}
+		if err = extractFile(f, filepath.Join(uploadPath, filepath.FromSlash(name))); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func extractFile(f *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	dst, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+// openTestSite open the sqlite database of the site in the directory
+func openTestSite(t *testing.T, dir string) (*data.Database, *xorm.Engine) {
+	conf := &data.Database{Driver: string(schemas.SQLITE), Connection: filepath.Join(dir, "answer.db")}
+	engine, err := data.NewDB(false, conf)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = engine.Close()
+	})
+	return conf, engine
+}
+
+func TestExportImportSite(t *testing.T) {
+	ctx := context.TODO()
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	srcConf, src := openTestSite(t, srcDir)
+	require.NoError(t, migrations.InitSchema(ctx, src, migrations.ExpectedVersion()))
+
+	// the rows of the primary site and a tenant, the full text index is filled by the triggers
+	_, err := src.Insert(
+		&entity.Tenant{ID: 1, Host: "t1.local", Name: "t1", Status: entity.TenantStatusAvailable},
+		&entity.User{ID: "4301", Username: "admin", EMail: "admin@example.com"},
+		&entity.User{ID: "4302", TenantID: 1, Username: "admin", EMail: "admin@t1.example.com"},
+		&entity.Question{ID: "10010000000004301", UserID: "4301", Title: "primary question",
+			OriginalText: "restored from the portable archive", LastAnswerID: "10020000000004302"},
+		&entity.Question{ID: "10010000000004302", TenantID: 1, UserID: "4302", Title: "tenant question",
+			OriginalText: "tenant content"},
+		&entity.Answer{ID: "10020000000004302", QuestionID: "10010000000004301", UserID: "4302",
+			OriginalText: "portable answer"},
+		&entity.PluginKVStorage{PluginSlugName: "demo", Group: "g", Key: "k", Value: "primary"},
+		&entity.PluginKVStorage{TenantID: 1, PluginSlugName: "demo", Group: "g", Key: "k", Value: "tenant"},
+	)
+	require.NoError(t, err)
+	uploadPath := filepath.Join(srcDir, "uploads")
+	require.NoError(t, os.MkdirAll(filepath.Join(uploadPath, "post"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(uploadPath, "post", "a.png"), []byte("png"), 0644))
+
+	archive, err := ExportSite(srcConf, uploadPath, srcDir)
+	require.NoError(t, err)
+
+	dstConf := &data.Database{Driver: string(schemas.SQLITE), Connection: filepath.Join(dstDir, "answer.db")}
+	dstUploadPath := filepath.Join(dstDir, "uploads")
+	require.NoError(t, ImportSite(false, dstConf, &data.CacheConf{FilePath: filepath.Join(dstDir, "cache.db")},
+		dstUploadPath, archive))
+	_, dst := openTestSite(t, dstDir)
+
+	// every exported table is restored with the same rows
+	for _, bean := range migrations.Tables() {
+		if exportSkipTable(bean) {
+			continue
+		}
+		table, err := src.TableInfo(bean)
+		require.NoError(t, err)
+		srcCount, err := src.Count(bean)
+		require.NoError(t, err)
+		dstCount, err := dst.Count(bean)
+		require.NoError(t, err)
+		assert.Equal(t, srcCount, dstCount, table.Name)
+	}
+
+	// the ids and the tenants of the rows are kept
+	var questions []*entity.Question
+	require.NoError(t, dst.Asc("id").Find(&questions))
+	require.Len(t, questions, 2)
+	assert.Equal(t, "10010000000004301", questions[0].ID)
+	assert.Equal(t, 0, questions[0].TenantID)
+	assert.Equal(t, "10020000000004302", questions[0].LastAnswerID)
+	assert.Equal(t, "10010000000004302", questions[1].ID)
+	assert.Equal(t, 1, questions[1].TenantID)
+	var users []*entity.User
+	require.NoError(t, dst.Asc("id").Find(&users))
+	require.Len(t, users, 2)
+	assert.Equal(t, []int{0, 1}, []int{users[0].TenantID, users[1].TenantID})
+	tenant := &entity.Tenant{}
+	exist, err := dst.ID(1).Get(tenant)
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "t1.local", tenant.Host)
+	var values []*entity.PluginKVStorage
+	require.NoError(t, dst.Asc("tenant_id").Find(&values))
+	require.Len(t, values, 2)
+	assert.Equal(t, 0, values[0].TenantID)
+	assert.Equal(t, "primary", values[0].Value)
+	assert.Equal(t, 1, values[1].TenantID)
+	assert.Equal(t, "tenant", values[1].Value)
+
+	// the full text index is rebuilt from the restored questions and answers
+	var ids []string
+	require.NoError(t, dst.SQL(`SELECT rowid FROM "`+entity.SearchFullTextTableName+`" WHERE "`+
+		entity.SearchFullTextTableName+`" MATCH ? ORDER BY rowid`, "portable").Find(&ids))
+	assert.Equal(t, []string{"10010000000004301", "10020000000004302"}, ids)
+
+	content, err := os.ReadFile(filepath.Join(dstUploadPath, "post", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(content))
+
+	// the archive is only restored into an empty database
+	assert.Error(t, ImportSite(false, dstConf, &data.CacheConf{FilePath: filepath.Join(dstDir, "cache.db")},
+		dstUploadPath, archive))
+}
@@ -88,7 +88,7 @@ func ResetPassword(ctx context.Context, dataDirPath string, opts *ResetPasswordO
 	}
 	defer cacheCleanup()
 
-	dataData, dataCleanup, err := data.NewData(db, cache)
+	dataData, dataCleanup, err := data.NewData(db, cache, nil)
 	if err != nil {
 		return fmt.Errorf("initialize data layer failed: %w", err)
 	}
@@ -0,0 +1,472 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apache/answer/plugin"
+)
+
+const (
+	// stackExchangeProgressFile records the rows which have been imported of each file,
+	// so that the import can be resumed after interruption. It is kept in the work directory for each dump.
+	stackExchangeProgressFile = "stackexchange-progress-%s.json"
+	stackExchangeTimeLayout   = "2006-01-02T15:04:05.999"
+	// stackExchangeCommunityUserID is the user who owns the posts without an owner
+	stackExchangeCommunityUserID = "-1"
+	progressSaveInterval         = 100
+	progressReportInterval       = 1000
+)
+
+type stackExchangeUser struct {
+	ID          string `xml:"Id,attr"`
+	Reputation  int    `xml:"Reputation,attr"`
+	DisplayName string `xml:"DisplayName,attr"`
+	WebsiteURL  string `xml:"WebsiteUrl,attr"`
+	Location    string `xml:"Location,attr"`
+	AboutMe     string `xml:"AboutMe,attr"`
+}
+
+type stackExchangePost struct {
+	ID               string `xml:"Id,attr"`
+	PostTypeID       string `xml:"PostTypeId,attr"`
+	AcceptedAnswerID string `xml:"AcceptedAnswerId,attr"`
+	ParentID         string `xml:"ParentId,attr"`
+	CreationDate     string `xml:"CreationDate,attr"`
+	LastEditDate     string `xml:"LastEditDate,attr"`
+	ViewCount        int    `xml:"ViewCount,attr"`
+	Score            int    `xml:"Score,attr"`
+	Body             string `xml:"Body,attr"`
+	OwnerUserID      string `xml:"OwnerUserId,attr"`
+	OwnerDisplayName string `xml:"OwnerDisplayName,attr"`
+	Title            string `xml:"Title,attr"`
+	Tags             string `xml:"Tags,attr"`
+}
+
+type stackExchangePostHistory struct {
+	PostHistoryTypeID int    `xml:"PostHistoryTypeId,attr"`
+	PostID            string `xml:"PostId,attr"`
+	RevisionGUID      string `xml:"RevisionGUID,attr"`
+	CreationDate      string `xml:"CreationDate,attr"`
+	UserID            string `xml:"UserId,attr"`
+	UserDisplayName   string `xml:"UserDisplayName,attr"`
+	Comment           string `xml:"Comment,attr"`
+	Text              string `xml:"Text,attr"`
+}
+
+type stackExchangeComment struct {
+	ID              string `xml:"Id,attr"`
+	PostID          string `xml:"PostId,attr"`
+	Text            string `xml:"Text,attr"`
+	CreationDate    string `xml:"CreationDate,attr"`
+	UserID          string `xml:"UserId,attr"`
+	UserDisplayName string `xml:"UserDisplayName,attr"`
+}
+
+type stackExchangeVote struct {
+	PostID       string `xml:"PostId,attr"`
+	VoteTypeID   string `xml:"VoteTypeId,attr"`
+	UserID       string `xml:"UserId,attr"`
+	CreationDate string `xml:"CreationDate,attr"`
+}
+
+type stackExchangeTag struct {
+	TagName       string `xml:"TagName,attr"`
+	ExcerptPostID string `xml:"ExcerptPostId,attr"`
+}
+
+// stackExchangeRevision is a version of the post rebuilt from the post history
+type stackExchangeRevision struct {
+	guid            string
+	Title           string
+	Body            string
+	Tags            []string
+	UserID          string
+	UserDisplayName string
+	Comment         string
+	CreatedAt       time.Time
+}
+
+type stackExchangeImporter struct {
+	importer plugin.ImporterFunc
+	dir      string
+	// workDir keeps the progress and the sorted post history, the dump directory may be read-only
+	workDir      string
+	progressFile string
+	progress     map[string]int
+	// postTypes the type of the questions and answers, used for the comments and votes
+	postTypes       map[string]plugin.ImportObjectType
+	acceptedAnswers map[string]bool
+	tagExcerpts     map[string]string
+	history         *stackExchangeHistory
+}
+
+// ImportStackExchange import the Stack Exchange data dump in the directory.
+// The files are streamed in the order of users, tags, posts, comments and votes.
+// The progress of the import is kept in the work directory.
+func ImportStackExchange(ctx context.Context, importer plugin.ImporterFunc, dir, workDir string) (err error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(workDir, os.ModePerm); err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte(absDir))
+	si := &stackExchangeImporter{
+		importer: importer,
+		dir:      dir,
+		workDir:  workDir,
+		// the progress of each dump is kept apart
+		progressFile:    filepath.Join(workDir, fmt.Sprintf(stackExchangeProgressFile, hex.EncodeToString(sum[:6]))),
+		progress:        make(map[string]int),
+		postTypes:       make(map[string]plugin.ImportObjectType),
+		acceptedAnswers: make(map[string]bool),
+		tagExcerpts:     make(map[string]string),
+	}
+	if err = si.loadProgress(); err != nil {
+		return err
+	}
+
+	fmt.Println("scanning Posts.xml")
+	if err = si.scanPosts(); err != nil {
+		return err
+	}
+	fmt.Println("sorting PostHistory.xml")
+	if err = si.scanPostHistory(); err != nil {
+		return err
+	}
+	defer si.history.Close()
+
+	if err = importRows(si, "Users.xml", func(row *stackExchangeUser) error {
+		return si.importer.AddUser(ctx, plugin.ImportUser{
+			ExternalID:  row.ID,
+			DisplayName: row.DisplayName,
+			Bio:         row.AboutMe,
+			Website:     row.WebsiteURL,
+			Location:    row.Location,
+			Reputation:  row.Reputation,
+		})
+	}); err != nil {
+		return err
+	}
+	if err = importRows(si, "Tags.xml", func(row *stackExchangeTag) error {
+		return si.importer.AddTag(ctx, plugin.TagImporterInfo{
+			SlugName:    row.TagName,
+			DisplayName: row.TagName,
+			Description: si.tagExcerpts[row.ExcerptPostID],
+		})
+	}); err != nil {
+		return err
+	}
+	if err = importRows(si, "Posts.xml", func(row *stackExchangePost) error {
+		return si.importPost(ctx, row)
+	}); err != nil {
+		return err
+	}
+	if err = importRows(si, "Comments.xml", func(row *stackExchangeComment) error {
+		objectType, ok := si.postTypes[row.PostID]
+		if !ok {
+			return nil
+		}
+		return si.importer.AddComment(ctx, plugin.CommentImporterInfo{
+			ExternalID:       row.ID,
+			ObjectExternalID: row.PostID,
+			ObjectType:       objectType,
+			Content:          row.Text,
+			User:             stackExchangeImportUser(row.UserID, row.UserDisplayName),
+			CreatedAt:        parseStackExchangeTime(row.CreationDate),
+		})
+	}); err != nil {
+		return err
+	}
+	// the voters are anonymous in the public dumps, only the votes with the user are imported,
+	// the vote count of the post is its score otherwise
+	if err = importRows(si, "Votes.xml", func(row *stackExchangeVote) error {
+		objectType, ok := si.postTypes[row.PostID]
+		if !ok || len(row.UserID) == 0 || (row.VoteTypeID != "2" && row.VoteTypeID != "3") {
+			return nil
+		}
+		return si.importer.AddVote(ctx, plugin.VoteImporterInfo{
+			ObjectExternalID: row.PostID,
+			ObjectType:       objectType,
+			User:             &plugin.ImportUser{ExternalID: row.UserID},
+			VoteUp:           row.VoteTypeID == "2",
+			CreatedAt:        parseStackExchangeTime(row.CreationDate),
+		})
+	}); err != nil {
+		return err
+	}
+	fmt.Println("Stack Exchange data dump imported successfully")
+	return nil
+}
+
+// scanPosts collect the types of the posts, the accepted answers and the excerpts of the tags
+func (si *stackExchangeImporter) scanPosts() error {
+	return eachRow(filepath.Join(si.dir, "Posts.xml"), func(row *stackExchangePost) error {
+		switch row.PostTypeID {
+		case "1":
+			si.postTypes[row.ID] = plugin.ImportObjectTypeQuestion
+			if len(row.AcceptedAnswerID) > 0 {
+				si.acceptedAnswers[row.AcceptedAnswerID] = true
+			}
+		case "2":
+			si.postTypes[row.ID] = plugin.ImportObjectTypeAnswer
+		case "4":
+			si.tagExcerpts[row.ID] = row.Body
+		}
+		return nil
+	})
+}
+
+// scanPostHistory sort the post history by the post, so that it is read along with the posts,
+// the posts of the dumps are ordered by the id
+func (si *stackExchangeImporter) scanPostHistory() (err error) {
+	filePath := filepath.Join(si.dir, "PostHistory.xml")
+	if _, err = os.Stat(filePath); errors.Is(err, os.ErrNotExist) {
+		fmt.Println("PostHistory.xml not found, the posts are imported without revisions")
+	}
+	si.history, err = sortPostHistory(filePath, si.workDir, func(row *stackExchangePostHistory) bool {
+		_, ok := si.postTypes[row.PostID]
+		return ok && row.PostHistoryTypeID >= 1 && row.PostHistoryTypeID <= 9
+	})
+	return err
+}
+
+// buildStackExchangeRevisions rebuild the versions of the post from its history rows,
+// the markdown source of the posts is only kept in the history
+func buildStackExchangeRevisions(rows []*stackExchangePostHistory) (history []*stackExchangeRevision) {
+	var current *stackExchangeRevision
+	for _, row := range rows {
+		if current == nil || current.guid != row.RevisionGUID {
+			next := &stackExchangeRevision{}
+			if current != nil {
+				*next = *current
+			}
+			next.guid = row.RevisionGUID
+			next.UserID = row.UserID
+			next.UserDisplayName = row.UserDisplayName
+			next.Comment = row.Comment
+			next.CreatedAt = parseStackExchangeTime(row.CreationDate)
+			history = append(history, next)
+			current = next
+		}
+		// initial, edit and rollback of the title, body and tags
+		switch (row.PostHistoryTypeID - 1) % 3 {
+		case 0:
+			current.Title = row.Text
+		case 1:
+			current.Body = row.Text
+		case 2:
+			current.Tags = parseStackExchangeTags(row.Text)
+		}
+	}
+	return history
+}
+
+func (si *stackExchangeImporter) importPost(ctx context.Context, row *stackExchangePost) error {
+	var revisions []plugin.ImportRevision
+	var lastEditUser *plugin.ImportUser
+	content := row.Body
+	rows, err := si.history.Take(row.ID)
+	if err != nil {
+		return err
+	}
+	history := buildStackExchangeRevisions(rows)
+	if len(history) > 0 && len(history[len(history)-1].Body) > 0 {
+		current := history[len(history)-1]
+		content = current.Body
+		lastEditUser = stackExchangeImportUser(current.UserID, current.UserDisplayName)
+		for _, version := range history[:len(history)-1] {
+			revisions = append(revisions, plugin.ImportRevision{
+				Title:     version.Title,
+				Content:   version.Body,
+				Tags:      version.Tags,
+				Log:       version.Comment,
+				User:      stackExchangeImportUser(version.UserID, version.UserDisplayName),
+				CreatedAt: version.CreatedAt,
+			})
+		}
+	}
+	user := stackExchangeImportUser(row.OwnerUserID, row.OwnerDisplayName)
+	createdAt := parseStackExchangeTime(row.CreationDate)
+	updatedAt := parseStackExchangeTime(row.LastEditDate)
+
+	switch row.PostTypeID {
+	case "1":
+		return si.importer.AddQuestion(ctx, plugin.QuestionImporterInfo{
+			ExternalID:   row.ID,
+			Title:        row.Title,
+			Content:      content,
+			HTML:         row.Body,
+			Tags:         parseStackExchangeTags(row.Tags),
+			User:         user,
+			LastEditUser: lastEditUser,
+			ViewCount:    row.ViewCount,
+			VoteCount:    row.Score,
+			CreatedAt:    createdAt,
+			UpdatedAt:    updatedAt,
+			Revisions:    revisions,
+		})
+	case "2":
+		return si.importer.AddAnswer(ctx, plugin.AnswerImporterInfo{
+			ExternalID:         row.ID,
+			QuestionExternalID: row.ParentID,
+			Content:            content,
+			HTML:               row.Body,
+			User:               user,
+			LastEditUser:       lastEditUser,
+			Accepted:           si.acceptedAnswers[row.ID],
+			VoteCount:          row.Score,
+			CreatedAt:          createdAt,
+			UpdatedAt:          updatedAt,
+			Revisions:          revisions,
+		})
+	}
+	return nil
+}
+
+func (si *stackExchangeImporter) loadProgress() error {
+	content, err := os.ReadFile(si.progressFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(content, &si.progress); err != nil {
+		return fmt.Errorf("parse import progress failed: %w", err)
+	}
+	fmt.Println("resume the import from the last progress")
+	return nil
+}
+
+func (si *stackExchangeImporter) saveProgress() error {
+	content, _ := json.Marshal(si.progress)
+	return os.WriteFile(si.progressFile, content, 0644)
+}
+
+// importRows import the rows of the file, the rows imported before are skipped.
+// The failed row is reported and skipped, so that one broken row does not stop the whole import.
+func importRows[T any](si *stackExchangeImporter, fileName string, importRow func(row *T) error) error {
+	imported := si.progress[fileName]
+	rowNum, failed := 0, 0
+	err := eachRow(filepath.Join(si.dir, fileName), func(row *T) error {
+		rowNum++
+		if rowNum <= imported {
+			return nil
+		}
+		if err := importRow(row); err != nil {
+			failed++
+			fmt.Printf("%s: import row %d failed: %v\n", fileName, rowNum, err)
+		}
+		si.progress[fileName] = rowNum
+		if rowNum%progressSaveInterval == 0 {
+			if err := si.saveProgress(); err != nil {
+				return err
+			}
+		}
+		if rowNum%progressReportInterval == 0 {
+			fmt.Printf("%s: %d rows imported, %d failed\n", fileName, rowNum, failed)
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("%s not found, skipped\n", fileName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: all %d rows imported, %d failed\n", fileName, rowNum, failed)
+	return si.saveProgress()
+}
+
+// eachRow stream the row elements of the Stack Exchange XML file
+func eachRow[T any](filePath string, handle func(row *T) error) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parse %s failed: %w", filepath.Base(filePath), err)
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "row" {
+			continue
+		}
+		row := new(T)
+		if err = decoder.DecodeElement(row, &element); err != nil {
+			return fmt.Errorf("parse %s failed: %w", filepath.Base(filePath), err)
+		}
+		if err = handle(row); err != nil {
+			return err
+		}
+	}
+}
+
+// stackExchangeImportUser the user is matched by the id, the deleted user is matched by the display name
+func stackExchangeImportUser(userID, displayName string) *plugin.ImportUser {
+	if len(userID) > 0 {
+		return &plugin.ImportUser{ExternalID: userID, DisplayName: displayName}
+	}
+	if len(displayName) > 0 {
+		return &plugin.ImportUser{ExternalID: "name:" + displayName, DisplayName: displayName}
+	}
+	return &plugin.ImportUser{ExternalID: stackExchangeCommunityUserID}
+}
+
+// parseStackExchangeTags the tags are formatted as <a><b> in the old dumps and |a|b| in the new ones
+func parseStackExchangeTags(tags string) []string {
+	tags = strings.NewReplacer("><", "|", "<", "", ">", "").Replace(tags)
+	return strings.FieldsFunc(tags, func(r rune) bool {
+		return r == '|'
+	})
+}
+
+func parseStackExchangeTime(value string) time.Time {
+	t, err := time.ParseInLocation(stackExchangeTimeLayout, value, time.UTC)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
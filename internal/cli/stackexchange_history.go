@@ -0,0 +1,317 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package cli
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+const (
+	// stackExchangeHistoryChunkSize the text size of the history rows sorted in memory at a time
+	stackExchangeHistoryChunkSize = 64 << 20
+	// stackExchangeHistoryMergeWidth the number of the sorted chunks merged at a time
+	stackExchangeHistoryMergeWidth = 64
+)
+
+// stackExchangeHistoryEntry the post history row with its position, the rows of a post are kept in the file order
+type stackExchangeHistoryEntry struct {
+	PostID int64                     `json:"p"`
+	Seq    int64                     `json:"s"`
+	Row    *stackExchangePostHistory `json:"r"`
+}
+
+func (e *stackExchangeHistoryEntry) less(other *stackExchangeHistoryEntry) bool {
+	if e.PostID != other.PostID {
+		return e.PostID < other.PostID
+	}
+	return e.Seq < other.Seq
+}
+
+// stackExchangeHistory the post history sorted by the post on the disk, it is read along with the posts
+// which are in the order of their ids, so that only the history of the current post is in memory.
+type stackExchangeHistory struct {
+	dir    string
+	reader *stackExchangeChunkReader
+}
+
+// sortPostHistory sort the rows of PostHistory.xml by the post in the chunks of bounded size, then merge them.
+// The history is empty if the file is not found.
+func sortPostHistory(filePath, workDir string, keep func(row *stackExchangePostHistory) bool) (
+	history *stackExchangeHistory, err error) {
+	dir, err := os.MkdirTemp(workDir, "stackexchange-history-")
+	if err != nil {
+		return nil, err
+	}
+	history = &stackExchangeHistory{dir: dir}
+	defer func() {
+		if err != nil {
+			history.Close()
+		}
+	}()
+
+	var (
+		chunks []string
+		buffer []*stackExchangeHistoryEntry
+		size   int
+		seq    int64
+	)
+	flush := func() error {
+		if len(buffer) == 0 {
+			return nil
+		}
+		sort.Slice(buffer, func(i, j int) bool {
+			return buffer[i].less(buffer[j])
+		})
+		chunk, err := writeHistoryChunk(dir, len(chunks), func(write func(entry *stackExchangeHistoryEntry) error) error {
+			for _, entry := range buffer {
+				if err := write(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		chunks = append(chunks, chunk)
+		buffer, size = nil, 0
+		return nil
+	}
+	err = eachRow(filePath, func(row *stackExchangePostHistory) error {
+		seq++
+		postID, err := strconv.ParseInt(row.PostID, 10, 64)
+		if err != nil || !keep(row) {
+			return nil
+		}
+		buffer = append(buffer, &stackExchangeHistoryEntry{PostID: postID, Seq: seq, Row: row})
+		size += len(row.Text) + len(row.Comment)
+		if size >= stackExchangeHistoryChunkSize {
+			return flush()
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = flush(); err != nil {
+		return nil, err
+	}
+
+	// merge the chunks level by level, so that the number of the open files is bounded
+	index := len(chunks)
+	for len(chunks) > stackExchangeHistoryMergeWidth {
+		var merged []string
+		for i := 0; i < len(chunks); i += stackExchangeHistoryMergeWidth {
+			group := chunks[i:min(i+stackExchangeHistoryMergeWidth, len(chunks))]
+			chunk, err := mergeHistoryChunks(dir, index, group)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, chunk)
+			index++
+		}
+		chunks = merged
+	}
+	history.reader, err = newStackExchangeChunkReader(chunks)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Take returns the history rows of the post in the file order, the rows of the posts before it are dropped
+func (h *stackExchangeHistory) Take(postID string) (rows []*stackExchangePostHistory, err error) {
+	id, err := strconv.ParseInt(postID, 10, 64)
+	if err != nil || h.reader == nil {
+		return nil, nil
+	}
+	for {
+		entry := h.reader.Peek()
+		if entry == nil || entry.PostID > id {
+			return rows, nil
+		}
+		if entry.PostID == id {
+			rows = append(rows, entry.Row)
+		}
+		if err = h.reader.Next(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close remove the sorted history
+func (h *stackExchangeHistory) Close() {
+	if h.reader != nil {
+		h.reader.Close()
+	}
+	_ = os.RemoveAll(h.dir)
+}
+
+func writeHistoryChunk(dir string, index int,
+	writeAll func(write func(entry *stackExchangeHistoryEntry) error) error) (chunk string, err error) {
+	chunk = filepath.Join(dir, fmt.Sprintf("chunk-%d.jsonl", index))
+	file, err := os.Create(chunk)
+	if err != nil {
+		return "", err
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	if err = writeAll(func(entry *stackExchangeHistoryEntry) error {
+		return encoder.Encode(entry)
+	}); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+	if err = writer.Flush(); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+	return chunk, file.Close()
+}
+
+func mergeHistoryChunks(dir string, index int, chunks []string) (string, error) {
+	reader, err := newStackExchangeChunkReader(chunks)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	merged, err := writeHistoryChunk(dir, index, func(write func(entry *stackExchangeHistoryEntry) error) error {
+		for entry := reader.Peek(); entry != nil; entry = reader.Peek() {
+			if err := write(entry); err != nil {
+				return err
+			}
+			if err := reader.Next(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, chunk := range chunks {
+		_ = os.Remove(chunk)
+	}
+	return merged, nil
+}
+
+// stackExchangeChunkReader read the entries of the sorted chunks in order
+type stackExchangeChunkReader struct {
+	files  []*os.File
+	cursor stackExchangeChunkHeap
+}
+
+type stackExchangeChunkCursor struct {
+	decoder *json.Decoder
+	entry   *stackExchangeHistoryEntry
+}
+
+func (c *stackExchangeChunkCursor) next() (ok bool, err error) {
+	entry := &stackExchangeHistoryEntry{}
+	if err = c.decoder.Decode(entry); err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read the sorted post history failed: %w", err)
+	}
+	c.entry = entry
+	return true, nil
+}
+
+func newStackExchangeChunkReader(chunks []string) (reader *stackExchangeChunkReader, err error) {
+	reader = &stackExchangeChunkReader{}
+	for _, chunk := range chunks {
+		file, err := os.Open(chunk)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+		reader.files = append(reader.files, file)
+		cursor := &stackExchangeChunkCursor{decoder: json.NewDecoder(bufio.NewReader(file))}
+		ok, err := cursor.next()
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+		if ok {
+			reader.cursor = append(reader.cursor, cursor)
+		}
+	}
+	heap.Init(&reader.cursor)
+	return reader, nil
+}
+
+// Peek returns the current entry, nil if all entries are read
+func (r *stackExchangeChunkReader) Peek() *stackExchangeHistoryEntry {
+	if len(r.cursor) == 0 {
+		return nil
+	}
+	return r.cursor[0].entry
+}
+
+// Next move to the next entry
+func (r *stackExchangeChunkReader) Next() error {
+	if len(r.cursor) == 0 {
+		return nil
+	}
+	ok, err := r.cursor[0].next()
+	if err != nil {
+		return err
+	}
+	if ok {
+		heap.Fix(&r.cursor, 0)
+	} else {
+		heap.Pop(&r.cursor)
+	}
+	return nil
+}
+
+func (r *stackExchangeChunkReader) Close() {
+	for _, file := range r.files {
+		_ = file.Close()
+	}
+	r.files, r.cursor = nil, nil
+}
+
+// stackExchangeChunkHeap the cursors of the chunks ordered by their current entries
+type stackExchangeChunkHeap []*stackExchangeChunkCursor
+
+func (h stackExchangeChunkHeap) Len() int           { return len(h) }
+func (h stackExchangeChunkHeap) Less(i, j int) bool { return h[i].entry.less(h[j].entry) }
+func (h stackExchangeChunkHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *stackExchangeChunkHeap) Push(x any)        { *h = append(*h, x.(*stackExchangeChunkCursor)) }
+func (h *stackExchangeChunkHeap) Pop() any {
+	old := *h
+	cursor := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return cursor
+}
@@ -0,0 +1,271 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/answer/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stackExchangeTestDump = "testdata/stackexchange"
+
+// recordImporter records the imported data instead of saving it
+type recordImporter struct {
+	users     []plugin.ImportUser
+	tags      []plugin.TagImporterInfo
+	questions []plugin.QuestionImporterInfo
+	answers   []plugin.AnswerImporterInfo
+	comments  []plugin.CommentImporterInfo
+	votes     []plugin.VoteImporterInfo
+}
+
+func (r *recordImporter) AddUser(_ context.Context, userInfo plugin.ImportUser) error {
+	r.users = append(r.users, userInfo)
+	return nil
+}
+
+func (r *recordImporter) AddTag(_ context.Context, tagInfo plugin.TagImporterInfo) error {
+	r.tags = append(r.tags, tagInfo)
+	return nil
+}
+
+func (r *recordImporter) AddQuestion(_ context.Context, questionInfo plugin.QuestionImporterInfo) error {
+	r.questions = append(r.questions, questionInfo)
+	return nil
+}
+
+func (r *recordImporter) AddAnswer(_ context.Context, answerInfo plugin.AnswerImporterInfo) error {
+	r.answers = append(r.answers, answerInfo)
+	return nil
+}
+
+func (r *recordImporter) AddComment(_ context.Context, commentInfo plugin.CommentImporterInfo) error {
+	r.comments = append(r.comments, commentInfo)
+	return nil
+}
+
+func (r *recordImporter) AddVote(_ context.Context, voteInfo plugin.VoteImporterInfo) error {
+	r.votes = append(r.votes, voteInfo)
+	return nil
+}
+
+func TestImportStackExchange(t *testing.T) {
+	importer := &recordImporter{}
+	require.NoError(t, ImportStackExchange(context.TODO(), importer, stackExchangeTestDump, t.TempDir()))
+
+	require.Len(t, importer.users, 2)
+	assert.Equal(t, plugin.ImportUser{ExternalID: "1", DisplayName: "alice", Bio: "<p>hi</p>",
+		Website: "https://alice.example.com", Location: "Earth", Reputation: 100}, importer.users[0])
+
+	require.Len(t, importer.tags, 2)
+	assert.Equal(t, "Go language", importer.tags[0].Description)
+	assert.Empty(t, importer.tags[1].Description)
+
+	require.Len(t, importer.questions, 2)
+	edited := importer.questions[0]
+	assert.Equal(t, "1", edited.ExternalID)
+	assert.Equal(t, "second body", edited.Content)
+	assert.Equal(t, "<p>second body</p>", edited.HTML)
+	assert.Equal(t, []string{"go"}, edited.Tags)
+	assert.Equal(t, 10, edited.ViewCount)
+	assert.Equal(t, 5, edited.VoteCount)
+	assert.Equal(t, "2", edited.LastEditUser.ExternalID)
+	require.Len(t, edited.Revisions, 1)
+	assert.Equal(t, plugin.ImportRevision{Title: "How to sort?", Content: "first body", Tags: []string{"go"},
+		User: &plugin.ImportUser{ExternalID: "1"}, CreatedAt: parseStackExchangeTime("2020-02-01T10:00:00.000")},
+		edited.Revisions[0])
+	// the post without history keeps its html body, the deleted owner is matched by the name
+	plain := importer.questions[1]
+	assert.Equal(t, "<p>plain</p>", plain.Content)
+	assert.Equal(t, []string{"go", "sql"}, plain.Tags)
+	assert.Equal(t, -1, plain.VoteCount)
+	assert.Equal(t, "name:Gone", plain.User.ExternalID)
+	assert.Nil(t, plain.LastEditUser)
+	assert.Empty(t, plain.Revisions)
+
+	require.Len(t, importer.answers, 2)
+	assert.Equal(t, "3", importer.answers[0].ExternalID)
+	assert.Equal(t, "1", importer.answers[0].QuestionExternalID)
+	assert.Equal(t, "answer body", importer.answers[0].Content)
+	assert.True(t, importer.answers[0].Accepted)
+	assert.Equal(t, 2, importer.answers[0].VoteCount)
+	assert.Equal(t, "4", importer.answers[1].QuestionExternalID)
+	assert.Equal(t, "late answer", importer.answers[1].Content)
+	assert.False(t, importer.answers[1].Accepted)
+
+	// the comments of the posts not in the dump are skipped
+	require.Len(t, importer.comments, 2)
+	assert.Equal(t, plugin.ImportObjectTypeQuestion, importer.comments[0].ObjectType)
+	assert.Equal(t, "1", importer.comments[0].ObjectExternalID)
+	assert.Equal(t, plugin.ImportObjectTypeAnswer, importer.comments[1].ObjectType)
+	assert.Equal(t, "name:Gone", importer.comments[1].User.ExternalID)
+
+	// only the up and down votes with the voter are imported
+	require.Len(t, importer.votes, 2)
+	assert.Equal(t, "3", importer.votes[0].ObjectExternalID)
+	assert.True(t, importer.votes[0].VoteUp)
+	assert.Equal(t, plugin.ImportObjectTypeQuestion, importer.votes[1].ObjectType)
+	assert.False(t, importer.votes[1].VoteUp)
+}
+
+func TestImportStackExchange_Resume(t *testing.T) {
+	workDir := t.TempDir()
+	require.NoError(t, ImportStackExchange(context.TODO(), &recordImporter{}, stackExchangeTestDump, workDir))
+
+	// the progress is kept in the work directory, the dump is only read
+	progressFiles, err := filepath.Glob(filepath.Join(workDir, "stackexchange-progress-*.json"))
+	require.NoError(t, err)
+	require.Len(t, progressFiles, 1)
+	entries, err := os.ReadDir(stackExchangeTestDump)
+	require.NoError(t, err)
+	assert.Len(t, entries, 6)
+
+	// nothing is imported again once the import is finished
+	importer := &recordImporter{}
+	require.NoError(t, ImportStackExchange(context.TODO(), importer, stackExchangeTestDump, workDir))
+	assert.Empty(t, importer.users)
+	assert.Empty(t, importer.questions)
+	assert.Empty(t, importer.votes)
+
+	// the interrupted import continues from the row after the last saved one, with the history of the posts
+	require.NoError(t, os.WriteFile(progressFiles[0],
+		[]byte(`{"Users.xml":2,"Tags.xml":2,"Posts.xml":3,"Comments.xml":3,"Votes.xml":4}`), 0644))
+	importer = &recordImporter{}
+	require.NoError(t, ImportStackExchange(context.TODO(), importer, stackExchangeTestDump, workDir))
+	assert.Empty(t, importer.users)
+	require.Len(t, importer.questions, 1)
+	assert.Equal(t, "4", importer.questions[0].ExternalID)
+	require.Len(t, importer.answers, 1)
+	assert.Equal(t, "late answer", importer.answers[0].Content)
+	assert.Empty(t, importer.comments)
+
+	// each dump has its own progress in the work directory
+	otherDump := t.TempDir()
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(stackExchangeTestDump, entry.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(otherDump, entry.Name()), content, 0644))
+	}
+	importer = &recordImporter{}
+	require.NoError(t, ImportStackExchange(context.TODO(), importer, otherDump, workDir))
+	assert.Len(t, importer.questions, 2)
+}
+
+func TestBuildStackExchangeRevisions(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []*stackExchangePostHistory
+		want []*stackExchangeRevision
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "the rows of a revision are merged",
+			rows: []*stackExchangePostHistory{
+				{PostHistoryTypeID: 1, RevisionGUID: "a", UserID: "1", Text: "title"},
+				{PostHistoryTypeID: 2, RevisionGUID: "a", UserID: "1", Text: "body"},
+				{PostHistoryTypeID: 3, RevisionGUID: "a", UserID: "1", Text: "<go><sql>"},
+			},
+			want: []*stackExchangeRevision{
+				{guid: "a", Title: "title", Body: "body", Tags: []string{"go", "sql"}, UserID: "1"},
+			},
+		},
+		{
+			name: "the edit keeps the fields not changed",
+			rows: []*stackExchangePostHistory{
+				{PostHistoryTypeID: 1, RevisionGUID: "a", UserID: "1", Text: "title"},
+				{PostHistoryTypeID: 2, RevisionGUID: "a", UserID: "1", Text: "body"},
+				{PostHistoryTypeID: 5, RevisionGUID: "b", UserID: "2", Comment: "edited", Text: "new body"},
+				{PostHistoryTypeID: 8, RevisionGUID: "c", UserDisplayName: "gone", Text: "body"},
+			},
+			want: []*stackExchangeRevision{
+				{guid: "a", Title: "title", Body: "body", UserID: "1"},
+				{guid: "b", Title: "title", Body: "new body", UserID: "2", Comment: "edited"},
+				{guid: "c", Title: "title", Body: "body", UserDisplayName: "gone"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildStackExchangeRevisions(tt.rows))
+		})
+	}
+}
+
+func TestSortPostHistory(t *testing.T) {
+	history, err := sortPostHistory(filepath.Join(stackExchangeTestDump, "PostHistory.xml"), t.TempDir(),
+		func(row *stackExchangePostHistory) bool {
+			return row.PostID != "99"
+		})
+	require.NoError(t, err)
+	defer history.Close()
+
+	texts := func(postID string) (texts []string) {
+		rows, err := history.Take(postID)
+		require.NoError(t, err)
+		for _, row := range rows {
+			texts = append(texts, row.Text)
+		}
+		return texts
+	}
+	// the rows of each post are kept in the file order, the rows of the posts passed are dropped
+	assert.Equal(t, []string{"How to sort?", "first body", "|go|", "second body"}, texts("1"))
+	assert.Equal(t, []string{"closed"}, texts("4"))
+	assert.Nil(t, texts("3"))
+	assert.Equal(t, []string{"late answer"}, texts("5"))
+	assert.Nil(t, texts("99"))
+
+	// the dump without the history
+	empty, err := sortPostHistory(filepath.Join(t.TempDir(), "PostHistory.xml"), t.TempDir(),
+		func(*stackExchangePostHistory) bool { return true })
+	require.NoError(t, err)
+	defer empty.Close()
+	rows, err := empty.Take("1")
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
+func TestParseStackExchangeTags(t *testing.T) {
+	tests := []struct {
+		tags string
+		want []string
+	}{
+		{"", []string{}},
+		{"<go>", []string{"go"}},
+		{"<go><sql>", []string{"go", "sql"}},
+		{"|go|sql|", []string{"go", "sql"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, parseStackExchangeTags(tt.tags), tt.tags)
+	}
+}
+
+func TestStackExchangeImportUser(t *testing.T) {
+	assert.Equal(t, &plugin.ImportUser{ExternalID: "1", DisplayName: "alice"}, stackExchangeImportUser("1", "alice"))
+	assert.Equal(t, &plugin.ImportUser{ExternalID: "name:gone", DisplayName: "gone"}, stackExchangeImportUser("", "gone"))
+	assert.Equal(t, &plugin.ImportUser{ExternalID: stackExchangeCommunityUserID}, stackExchangeImportUser("", ""))
+}
@@ -191,7 +191,7 @@ func (c *AIController) ChatCompletions(ctx *gin.Context) {
 	if !c.ensureAIChatEnabled(ctx) {
 		return
 	}
-	aiConfig, err := c.siteInfoService.GetSiteAI(context.Background())
+	aiConfig, err := c.siteInfoService.GetSiteAI(ctx)
 	if err != nil {
 		log.Errorf("Failed to get AI config: %v", err)
 		handler.HandleResponse(ctx, errors.BadRequest("AI service configuration error"), nil)
@@ -270,17 +270,17 @@ func (c *AIController) ChatCompletions(ctx *gin.Context) {
 }
 
 func (c *AIController) redirectRequestToAI(ctx *gin.Context, w http.ResponseWriter, id string, conversationCtx *ConversationContext) {
-	client := c.createOpenAIClient()
+	client := c.createOpenAIClient(ctx)
 
 	c.handleAIConversation(ctx, w, id, client, conversationCtx)
 }
 
 // createOpenAIClient
-func (c *AIController) createOpenAIClient() *openai.Client {
+func (c *AIController) createOpenAIClient(ctx context.Context) *openai.Client {
 	config := openai.DefaultConfig("")
 	config.BaseURL = ""
 
-	aiConfig, err := c.siteInfoService.GetSiteAI(context.Background())
+	aiConfig, err := c.siteInfoService.GetSiteAI(ctx)
 	if err != nil {
 		log.Errorf("Failed to get AI config: %v", err)
 		return openai.NewClientWithConfig(config)
@@ -302,8 +302,8 @@ func (c *AIController) createOpenAIClient() *openai.Client {
 }
 
 // getPromptByLanguage
-func (c *AIController) getPromptByLanguage(language i18n.Language, question string) string {
-	aiConfig, err := c.siteInfoService.GetSiteAI(context.Background())
+func (c *AIController) getPromptByLanguage(ctx context.Context, language i18n.Language, question string) string {
+	aiConfig, err := c.siteInfoService.GetSiteAI(ctx)
 	if err != nil {
 		log.Errorf("Failed to get AI config: %v", err)
 		return c.getDefaultPrompt(language, question)
@@ -399,7 +399,7 @@ func (c *AIController) buildInitialMessages(ctx *gin.Context, req *ChatCompletio
 
 	currentLang := handler.GetLangByCtx(ctx)
 
-	prompt := c.getPromptByLanguage(currentLang, question)
+	prompt := c.getPromptByLanguage(ctx, currentLang, question)
 
 	return []*ai_conversation.ConversationMessage{{Role: openai.ChatMessageRoleUser, Content: prompt}}
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/action"
 	"github.com/apache/answer/internal/service/content"
+	"github.com/apache/answer/internal/service/draft"
 	"github.com/apache/answer/internal/service/permission"
 	"github.com/apache/answer/internal/service/rank"
 	"github.com/apache/answer/internal/service/siteinfo_common"
@@ -47,6 +48,7 @@ type AnswerController struct {
 	actionService         *action.CaptchaService
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService
 	rateLimitMiddleware   *middleware.RateLimitMiddleware
+	draftService          *draft.DraftService
 }
 
 // NewAnswerController new controller
@@ -56,6 +58,7 @@ func NewAnswerController(
 	actionService *action.CaptchaService,
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
 	rateLimitMiddleware *middleware.RateLimitMiddleware,
+	draftService *draft.DraftService,
 ) *AnswerController {
 	return &AnswerController{
 		answerService:         answerService,
@@ -63,6 +66,7 @@ func NewAnswerController(
 		actionService:         actionService,
 		siteInfoCommonService: siteInfoCommonService,
 		rateLimitMiddleware:   rateLimitMiddleware,
+		draftService:          draftService,
 	}
 }
 
@@ -97,7 +101,7 @@ func (ac *AnswerController) RemoveAnswer(ctx *gin.Context) {
 	}
 
 	objectOwner := ac.rankService.CheckOperationObjectOwner(ctx, req.UserID, req.ID)
-	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, req.ID, []string{
 		permission.AnswerDelete,
 	})
 	if err != nil {
@@ -135,7 +139,7 @@ func (ac *AnswerController) RecoverAnswer(ctx *gin.Context) {
 	req.AnswerID = uid.DeShortID(req.AnswerID)
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
-	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, req.AnswerID, []string{
 		permission.AnswerUnDelete,
 	})
 	if err != nil {
@@ -208,8 +212,12 @@ func (ac *AnswerController) AddAnswer(ctx *gin.Context) {
 	}()
 	req.QuestionID = uid.DeShortID(req.QuestionID)
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	if err := checkPublishAt(ctx, req.PublishAt); err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
 
-	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, req.QuestionID, []string{
 		permission.AnswerEdit,
 		permission.AnswerDelete,
 		permission.LinkUrlLimit,
@@ -272,6 +280,7 @@ func (ac *AnswerController) AddAnswer(ctx *gin.Context) {
 	if !isAdmin || !linkUrlLimitUser {
 		ac.actionService.ActionRecordAdd(ctx, entity.CaptchaActionAnswer, req.UserID)
 	}
+	ac.draftService.DiscardDraft(ctx, req.UserID, entity.DraftTypeNewAnswer, req.QuestionID)
 	info, questionInfo, has, err := ac.answerService.Get(ctx, answerID, req.UserID, isAdmin)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
@@ -310,7 +319,7 @@ func (ac *AnswerController) UpdateAnswer(ctx *gin.Context) {
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
-	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, uid.DeShortID(req.ID), []string{
 		permission.AnswerEdit,
 		permission.AnswerEditWithoutReview,
 		permission.LinkUrlLimit,
@@ -349,6 +358,7 @@ func (ac *AnswerController) UpdateAnswer(ctx *gin.Context) {
 	if !isAdmin || !linkUrlLimitUser {
 		ac.actionService.ActionRecordAdd(ctx, entity.CaptchaActionEdit, req.UserID)
 	}
+	ac.draftService.DiscardDraft(ctx, req.UserID, entity.DraftTypeEditAnswer, req.ID)
 	_, _, _, err = ac.answerService.Get(ctx, req.ID, req.UserID, isAdmin)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
@@ -379,7 +389,7 @@ func (ac *AnswerController) AnswerList(ctx *gin.Context) {
 	req.QuestionID = uid.DeShortID(req.QuestionID)
 	req.IsAdminModerator = middleware.GetUserIsAdminModerator(ctx)
 
-	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := ac.rankService.CheckOperationPermissions(ctx, req.UserID, req.QuestionID, []string{
 		permission.AnswerEdit,
 		permission.AnswerDelete,
 		permission.AnswerUnDelete,
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/bounty"
+	"github.com/apache/answer/internal/service/permission"
+	"github.com/apache/answer/internal/service/rank"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// BountyController bounty controller
+type BountyController struct {
+	bountyService *bounty.BountyService
+	rankService   *rank.RankService
+}
+
+// NewBountyController new controller
+func NewBountyController(
+	bountyService *bounty.BountyService,
+	rankService *rank.RankService,
+) *BountyController {
+	return &BountyController{
+		bountyService: bountyService,
+		rankService:   rankService,
+	}
+}
+
+// GetBountyList get the bounties of the question
+// @Summary get the bounties of the question
+// @Description get the bounties of the question, the latest first
+// @Tags Question
+// @Produce json
+// @Param question_id query string true "question id"
+// @Success 200 {object} handler.RespBody{data=[]schema.BountyResp}
+// @Router /answer/api/v1/question/bounty [get]
+func (bc *BountyController) GetBountyList(ctx *gin.Context) {
+	req := &schema.GetBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.QuestionID = uid.DeShortID(req.QuestionID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := bc.bountyService.GetBountyList(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddBounty offer a bounty on the question
+// @Summary offer reputation as a bounty on the question
+// @Description offer reputation as a bounty on the question, the question is featured until the bounty ends
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddBountyReq true "bounty"
+// @Success 200 {object} handler.RespBody{data=schema.BountyResp}
+// @Router /answer/api/v1/question/bounty [post]
+func (bc *BountyController) AddBounty(ctx *gin.Context) {
+	req := &schema.AddBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.QuestionID = uid.DeShortID(req.QuestionID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := bc.rankService.CheckOperationPermission(ctx, req.UserID, permission.QuestionBounty, req.QuestionID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	resp, err := bc.bountyService.AddBounty(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AwardBounty award the bounty to an answer
+// @Summary award the open bounty of the question to an answer
+// @Description award the open bounty of the question to an answer, only the user who offered it can award it
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AwardBountyReq true "award"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/bounty/award [post]
+func (bc *BountyController) AwardBounty(ctx *gin.Context) {
+	req := &schema.AwardBountyReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.QuestionID = uid.DeShortID(req.QuestionID)
+	req.AnswerID = uid.DeShortID(req.AnswerID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := bc.bountyService.AwardBounty(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
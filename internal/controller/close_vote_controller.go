@@ -0,0 +1,226 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/close_vote"
+	"github.com/apache/answer/internal/service/permission"
+	"github.com/apache/answer/internal/service/rank"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// CloseVoteController close vote controller
+type CloseVoteController struct {
+	closeVoteService *close_vote.CloseVoteService
+	rankService      *rank.RankService
+}
+
+// NewCloseVoteController new controller
+func NewCloseVoteController(
+	closeVoteService *close_vote.CloseVoteService,
+	rankService *rank.RankService,
+) *CloseVoteController {
+	return &CloseVoteController{
+		closeVoteService: closeVoteService,
+		rankService:      rankService,
+	}
+}
+
+// GetCloseVotes get the close votes of the question
+// @Summary get the close votes of the question
+// @Description get the close votes of the question
+// @Tags Question
+// @Produce json
+// @Security ApiKeyAuth
+// @Param question_id query string true "question id"
+// @Success 200 {object} handler.RespBody{data=schema.GetCloseVoteResp}
+// @Router /answer/api/v1/question/close/vote [get]
+func (cc *CloseVoteController) GetCloseVotes(ctx *gin.Context) {
+	req := &schema.GetCloseVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.QuestionID = uid.DeShortID(req.QuestionID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := cc.closeVoteService.GetCloseVotes(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddDuplicateVote vote to close the question as a duplicate
+// @Summary vote to close the question as a duplicate, the question is closed when the votes reach the threshold
+// @Description vote to close the question as a duplicate, the question is closed when the votes reach the threshold
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddDuplicateVoteReq true "vote"
+// @Success 200 {object} handler.RespBody{data=schema.GetCloseVoteResp}
+// @Router /answer/api/v1/question/close/vote/duplicate [post]
+func (cc *CloseVoteController) AddDuplicateVote(ctx *gin.Context) {
+	req := &schema.AddDuplicateVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.QuestionID = uid.DeShortID(req.QuestionID)
+	req.DuplicateQuestionID = uid.DeShortID(req.DuplicateQuestionID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	binding, err := cc.checkVotePermission(ctx, req.UserID, req.QuestionID, permission.QuestionClose)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.Binding = binding
+
+	resp, err := cc.closeVoteService.AddDuplicateVote(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddCloseVote vote to close the question
+// @Summary vote to close the question, the question is closed with the most voted reason when the votes reach the threshold
+// @Description vote to close the question, the question is closed with the most voted reason when the votes reach the threshold
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddCloseVoteReq true "vote"
+// @Success 200 {object} handler.RespBody{data=schema.GetCloseVoteResp}
+// @Router /answer/api/v1/question/close/vote [post]
+func (cc *CloseVoteController) AddCloseVote(ctx *gin.Context) {
+	req := &schema.AddCloseVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.QuestionID = uid.DeShortID(req.QuestionID)
+	req.DuplicateQuestionID = uid.DeShortID(req.DuplicateQuestionID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	binding, err := cc.checkVotePermission(ctx, req.UserID, req.QuestionID, permission.QuestionClose)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.Binding = binding
+
+	resp, err := cc.closeVoteService.AddCloseVote(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddReopenVote vote to reopen the closed question
+// @Summary vote to reopen the closed question, the question is reopened when the votes reach the threshold
+// @Description vote to reopen the closed question, the question is reopened when the votes reach the threshold
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddReopenVoteReq true "vote"
+// @Success 200 {object} handler.RespBody{data=schema.GetCloseVoteResp}
+// @Router /answer/api/v1/question/reopen/vote [post]
+func (cc *CloseVoteController) AddReopenVote(ctx *gin.Context) {
+	req := &schema.AddReopenVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.QuestionID = uid.DeShortID(req.QuestionID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	binding, err := cc.checkVotePermission(ctx, req.UserID, req.QuestionID, permission.QuestionReopen)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	req.Binding = binding
+
+	resp, err := cc.closeVoteService.AddReopenVote(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RemoveCloseVote retract the close vote of the question
+// @Summary retract the close vote of the question
+// @Description retract the close vote of the question
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RemoveCloseVoteReq true "vote"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/question/close/vote [delete]
+func (cc *CloseVoteController) RemoveCloseVote(ctx *gin.Context) {
+	req := &schema.RemoveCloseVoteReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.QuestionID = uid.DeShortID(req.QuestionID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := cc.closeVoteService.RemoveCloseVote(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetCloseVoteQuestionPage get the questions with pending close or reopen votes
+// @Summary get the questions with pending close or reopen votes for the review queue
+// @Description get the questions with pending close or reopen votes for the review queue
+// @Tags Question
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.CloseVoteQuestionResp}}
+// @Router /answer/api/v1/question/close/vote/page [get]
+func (cc *CloseVoteController) GetCloseVoteQuestionPage(ctx *gin.Context) {
+	req := &schema.GetCloseVoteQuestionPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, "", []string{
+		permission.QuestionClose,
+		permission.QuestionCloseVote,
+	})
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !canList[0] && !canList[1] {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	resp, err := cc.closeVoteService.GetCloseVoteQuestionPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// checkVotePermission check the user can vote, the vote is binding when the user has the moderator permission
+func (cc *CloseVoteController) checkVotePermission(ctx *gin.Context, userID, questionID, bindingPermission string) (
+	binding bool, err error) {
+	canList, err := cc.rankService.CheckOperationPermissions(ctx, userID, questionID, []string{
+		bindingPermission,
+		permission.QuestionCloseVote,
+	})
+	if err != nil {
+		return false, err
+	}
+	if !canList[0] && !canList[1] {
+		return false, errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+	return canList[0], nil
+}
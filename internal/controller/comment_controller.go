@@ -89,7 +89,7 @@ func (cc *CommentController) AddComment(ctx *gin.Context) {
 	req.ObjectID = uid.DeShortID(req.ObjectID)
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
-	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, req.ObjectID, []string{
 		permission.CommentAdd,
 		permission.CommentEdit,
 		permission.CommentDelete,
@@ -195,7 +195,7 @@ func (cc *CommentController) UpdateComment(ctx *gin.Context) {
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 	req.IsAdmin = middleware.GetIsAdminFromContext(ctx)
-	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, req.CommentID, []string{
 		permission.CommentEdit,
 		permission.LinkUrlLimit,
 	})
@@ -249,7 +249,7 @@ func (cc *CommentController) GetCommentWithPage(ctx *gin.Context) {
 	req.CommentID = uid.DeShortID(req.CommentID)
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 	req.IsAdminModerator = middleware.GetUserIsAdminModerator(ctx)
-	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, req.ObjectID, []string{
 		permission.CommentEdit,
 		permission.CommentDelete,
 	})
@@ -281,6 +281,7 @@ func (cc *CommentController) GetCommentPersonalWithPage(ctx *gin.Context) {
 	}
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.LoginUserID = req.UserID
 
 	resp, err := cc.commentService.GetCommentPersonalWithPage(ctx, req)
 	handler.HandleResponse(ctx, err, resp)
@@ -302,7 +303,7 @@ func (cc *CommentController) GetComment(ctx *gin.Context) {
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 	req.IsAdminModerator = middleware.GetUserIsAdminModerator(ctx)
-	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := cc.rankService.CheckOperationPermissions(ctx, req.UserID, req.ID, []string{
 		permission.CommentEdit,
 		permission.CommentDelete,
 	})
@@ -29,6 +29,7 @@ import (
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/export"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/sso"
 	"github.com/apache/answer/internal/service/user_external_login"
 	"github.com/apache/answer/plugin"
 	"github.com/gin-gonic/gin"
@@ -46,6 +47,7 @@ type ConnectorController struct {
 	siteInfoService     siteinfo_common.SiteInfoCommonService
 	userExternalService *user_external_login.UserExternalLoginService
 	emailService        *export.EmailService
+	ssoService          *sso.SSOService
 }
 
 // NewConnectorController new controller
@@ -53,11 +55,13 @@ func NewConnectorController(
 	siteInfoService siteinfo_common.SiteInfoCommonService,
 	emailService *export.EmailService,
 	userExternalService *user_external_login.UserExternalLoginService,
+	ssoService *sso.SSOService,
 ) *ConnectorController {
 	return &ConnectorController{
 		siteInfoService:     siteInfoService,
 		userExternalService: userExternalService,
 		emailService:        emailService,
+		ssoService:          ssoService,
 	}
 }
 
@@ -175,35 +179,49 @@ func (cc *ConnectorController) ConnectorRedirect(connector plugin.Connector) (fn
 			ctx.Redirect(http.StatusFound, "/50x")
 			return
 		}
-		if stateInfo != nil && stateInfo.Intent == schema.ExternalLoginOAuthStateBindIntent {
-			if err = cc.userExternalService.BindExternalLoginToUser(ctx, stateInfo.UserID, u); err != nil {
-				log.Errorf("bind external login failed: %v", err)
-				ctx.Redirect(http.StatusFound, "/50x")
-				return
-			}
-			ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/settings/account", siteGeneral.SiteUrl))
-			return
-		}
-		resp, err := cc.userExternalService.ExternalLogin(ctx, u)
-		if err != nil {
-			log.Errorf("external login failed: %v", err)
+		finishExternalLogin(ctx, cc.userExternalService, siteGeneral.SiteUrl, stateInfo, u)
+	}
+}
+
+// finishExternalLogin bind the external account to the user of the binding intent, otherwise log the user in,
+// and redirect to the page of the result. It is shared by the connectors and the single sign-on.
+func finishExternalLogin(ctx *gin.Context, userExternalService *user_external_login.UserExternalLoginService,
+	siteURL string, stateInfo *schema.ExternalLoginOAuthState, u *schema.ExternalLoginUserInfoCache) {
+	if stateInfo != nil && stateInfo.Intent == schema.ExternalLoginOAuthStateBindIntent {
+		if err := userExternalService.BindExternalLoginToUser(ctx, stateInfo.UserID, u); err != nil {
+			log.Errorf("bind external login failed: %v", err)
 			ctx.Redirect(http.StatusFound, "/50x")
 			return
 		}
-		if len(resp.ErrMsg) > 0 {
-			ctx.Redirect(http.StatusFound, fmt.Sprintf("/50x?title=%s&msg=%s", resp.ErrTitle, resp.ErrMsg))
-			return
-		}
-		if len(resp.AccessToken) > 0 {
-			ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/auth-landing?access_token=%s",
-				siteGeneral.SiteUrl, resp.AccessToken))
-		} else {
-			ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/confirm-email?binding_key=%s",
-				siteGeneral.SiteUrl, resp.BindingKey))
-		}
+		ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/settings/account", siteURL))
+		return
+	}
+	resp, err := userExternalService.ExternalLogin(ctx, u)
+	if err != nil {
+		log.Errorf("external login failed: %v", err)
+		ctx.Redirect(http.StatusFound, "/50x")
+		return
+	}
+	if len(resp.ErrMsg) > 0 {
+		ctx.Redirect(http.StatusFound, fmt.Sprintf("/50x?title=%s&msg=%s", resp.ErrTitle, resp.ErrMsg))
+		return
+	}
+	if resp.TwoFactor != nil {
+		ctx.Redirect(http.StatusFound, twoFactorLoginURL(siteURL, resp.TwoFactor))
+	} else if len(resp.AccessToken) > 0 {
+		ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/auth-landing?access_token=%s",
+			siteURL, resp.AccessToken))
+	} else {
+		ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/confirm-email?binding_key=%s",
+			siteURL, resp.BindingKey))
 	}
 }
 
+// twoFactorLoginURL the page where the user passes the second factor to finish the external login
+func twoFactorLoginURL(siteURL string, challenge *schema.TwoFactorChallengeResp) string {
+	return fmt.Sprintf("%s/users/login/2fa?challenge_token=%s", siteURL, challenge.ChallengeToken)
+}
+
 // ConnectorsInfo get all enabled connectors
 // @Summary get all enabled connectors
 // @Description get all enabled connectors
@@ -230,6 +248,14 @@ func (cc *ConnectorController) ConnectorsInfo(ctx *gin.Context) {
 		})
 		return nil
 	})
+
+	// the single sign-on providers are listed along with the connectors
+	ssoConnectors, err := cc.ssoService.ConnectorsInfo(ctx, general.SiteUrl)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	resp = append(resp, ssoConnectors...)
 	handler.HandleResponse(ctx, nil, resp)
 }
 
@@ -306,6 +332,13 @@ func (cc *ConnectorController) ConnectorsUserInfo(ctx *gin.Context) {
 		handler.HandleResponse(ctx, err, nil)
 		return
 	}
+
+	ssoConnectors, err := cc.ssoService.ConnectorsUserInfo(ctx, general.SiteUrl, userID, userExternalLoginMapping)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	resp = append(resp, ssoConnectors...)
 	handler.HandleResponse(ctx, nil, resp)
 }
 
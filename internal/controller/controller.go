@@ -57,4 +57,14 @@ var ProviderSetController = wire.NewSet(
 	NewMCPController,
 	NewAIController,
 	NewAIConversationController,
+	NewCloseVoteController,
+	NewBountyController,
+	NewDraftController,
+	NewScheduledPostController,
+	NewSpaceController,
+	NewUserDataController,
+	NewSSOController,
+	NewSCIMController,
+	NewTwoFactorController,
+	NewUserSessionController,
 )
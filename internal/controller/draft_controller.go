@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/draft"
+	"github.com/gin-gonic/gin"
+)
+
+// DraftController draft controller
+type DraftController struct {
+	draftService *draft.DraftService
+}
+
+// NewDraftController new controller
+func NewDraftController(draftService *draft.DraftService) *DraftController {
+	return &DraftController{draftService: draftService}
+}
+
+// GetDraftPage get the drafts of the login user
+// @Summary get the drafts of the login user
+// @Description get the drafts of the login user, the latest updated first
+// @Tags Draft
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.DraftResp}}
+// @Router /answer/api/v1/drafts [get]
+func (dc *DraftController) GetDraftPage(ctx *gin.Context) {
+	req := &schema.GetDraftPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := dc.draftService.GetDraftPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetDraft get the draft of the login user for the post being written or edited
+// @Summary get the draft of the login user for the post being written or edited
+// @Description get the draft of the login user for the draft type and object to resume editing
+// @Tags Draft
+// @Produce json
+// @Security ApiKeyAuth
+// @Param draft_type query string true "new_question, new_answer, edit_question or edit_answer"
+// @Param object_id query string false "the question id of the new answer, the id of the edited question or answer"
+// @Success 200 {object} handler.RespBody{data=schema.DraftResp}
+// @Router /answer/api/v1/draft [get]
+func (dc *DraftController) GetDraft(ctx *gin.Context) {
+	req := &schema.GetDraftReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := dc.draftService.GetDraft(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// SaveDraft create or update the draft of the login user
+// @Summary create or update the draft of the login user
+// @Description save the draft of the post being written or edited, the existing draft of the same object is replaced
+// @Tags Draft
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.SaveDraftReq true "draft"
+// @Success 200 {object} handler.RespBody{data=schema.DraftResp}
+// @Router /answer/api/v1/draft [post]
+func (dc *DraftController) SaveDraft(ctx *gin.Context) {
+	req := &schema.SaveDraftReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := dc.draftService.SaveDraft(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateDraft update the draft of the login user
+// @Summary update the draft of the login user
+// @Description update the draft of the login user by id
+// @Tags Draft
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateDraftReq true "draft"
+// @Success 200 {object} handler.RespBody{data=schema.DraftResp}
+// @Router /answer/api/v1/draft [put]
+func (dc *DraftController) UpdateDraft(ctx *gin.Context) {
+	req := &schema.UpdateDraftReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := dc.draftService.UpdateDraft(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// DeleteDraft delete the draft of the login user
+// @Summary delete the draft of the login user
+// @Description delete the draft of the login user by id
+// @Tags Draft
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.DeleteDraftReq true "draft"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/draft [delete]
+func (dc *DraftController) DeleteDraft(ctx *gin.Context) {
+	req := &schema.DeleteDraftReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := dc.draftService.DeleteDraft(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
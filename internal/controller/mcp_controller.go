@@ -86,6 +86,12 @@ func (c *MCPController) ensureMCPEnabled(ctx context.Context) error {
 	return c.featureToggleSvc.EnsureEnabled(ctx, feature_toggle.FeatureMCP)
 }
 
+// isSpaceQuestion the contents of the spaces are never exposed by the MCP tools
+func (c *MCPController) isSpaceQuestion(ctx context.Context, questionID string) bool {
+	question, err := c.questioncommon.Info(ctx, questionID, "")
+	return err == nil && question.SpaceID != entity.PublicSpaceID
+}
+
 func (c *MCPController) MCPQuestionsHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		if err := c.ensureMCPEnabled(ctx); err != nil {
@@ -143,6 +149,9 @@ func (c *MCPController) MCPQuestionDetailHandler() func(ctx context.Context, req
 			log.Errorf("get question failed: %v", err)
 			return mcp.NewToolResultText("No question found."), nil
 		}
+		if question.SpaceID != entity.PublicSpaceID {
+			return mcp.NewToolResultText("No question found."), nil
+		}
 
 		resp := &schema.MCPSearchQuestionInfoResp{
 			QuestionID: question.ID,
@@ -169,6 +178,9 @@ func (c *MCPController) MCPAnswersHandler() func(ctx context.Context, request mc
 		}
 
 		if len(cond.QuestionID) > 0 {
+			if c.isSpaceQuestion(ctx, cond.QuestionID) {
+				return mcp.NewToolResultText("[]"), nil
+			}
 			answerList, err := c.answerRepo.GetAnswerList(ctx, &entity.Answer{QuestionID: cond.QuestionID})
 			if err != nil {
 				log.Errorf("get answers failed: %v", err)
@@ -198,7 +210,7 @@ func (c *MCPController) MCPAnswersHandler() func(ctx context.Context, request mc
 		}
 		resp := make([]*schema.MCPSearchAnswerInfoResp, 0)
 		for _, answer := range answerList {
-			if answer.Status != entity.AnswerStatusAvailable {
+			if answer.Status != entity.AnswerStatusAvailable || answer.SpaceID != entity.PublicSpaceID {
 				continue
 			}
 			t := &schema.MCPSearchAnswerInfoResp{
@@ -236,7 +248,7 @@ func (c *MCPController) MCPCommentsHandler() func(ctx context.Context, request m
 		if err != nil {
 			return nil, err
 		}
-		if total == 0 {
+		if total == 0 || (len(commentList[0].QuestionID) > 0 && c.isSpaceQuestion(ctx, commentList[0].QuestionID)) {
 			return mcp.NewToolResultText("No comments found."), nil
 		}
 
@@ -313,7 +325,7 @@ func (c *MCPController) MCPTagDetailsHandler() func(ctx context.Context, request
 			log.Errorf("get tag failed: %v", err)
 			return nil, err
 		}
-		if !exist {
+		if !exist || tag.SpaceID != entity.PublicSpaceID {
 			return mcp.NewToolResultText("Tag not found."), nil
 		}
 
@@ -390,6 +402,9 @@ func (c *MCPController) MCPSemanticSearchHandler() func(ctx context.Context, req
 		for _, r := range results {
 			var meta plugin.VectorSearchMetadata
 			_ = json.Unmarshal([]byte(r.Metadata), &meta)
+			if c.isSpaceQuestion(ctx, meta.QuestionID) {
+				continue
+			}
 
 			item := &schema.MCPSemanticSearchResp{
 				ObjectID:   r.ObjectID,
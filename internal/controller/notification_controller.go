@@ -58,7 +58,7 @@ func NewNotificationController(
 func (nc *NotificationController) GetRedDot(ctx *gin.Context) {
 	req := &schema.GetRedDot{}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := nc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := nc.rankService.CheckOperationPermissions(ctx, req.UserID, "", []string{
 		permission.QuestionAudit,
 		permission.AnswerAudit,
 		permission.TagAudit,
@@ -92,7 +92,7 @@ func (nc *NotificationController) ClearRedDot(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := nc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := nc.rankService.CheckOperationPermissions(ctx, req.UserID, "", []string{
 		permission.QuestionAudit,
 		permission.AnswerAudit,
 		permission.TagAudit,
@@ -44,6 +44,7 @@ func NewPermissionController(rankService *rank.RankService) *PermissionControlle
 // @Param Authorization header string true "access-token"
 // @Produce json
 // @Param action query string true "permission key" Enums(question.add, question.edit, question.edit_without_review, question.delete, question.close, question.reopen, question.vote_up, question.vote_down, question.pin, question.unpin, question.hide, question.show, answer.add, answer.edit, answer.edit_without_review, answer.delete, answer.accept, answer.vote_up, answer.vote_down, answer.invite_someone_to_answer, comment.add, comment.edit, comment.delete, comment.vote_up, comment.vote_down, report.add, tag.add, tag.edit, tag.edit_slug_name, tag.edit_without_review, tag.delete, tag.synonym, link.url_limit, vote.detail, answer.audit, question.audit, tag.audit, tag.use_reserved_tag)
+// @Param object_id query string false "the object which is operated"
 // @Success 200 {object} handler.RespBody{data=map[string]bool}
 // @Router /answer/api/v1/permission [get]
 func (u *PermissionController) GetPermission(ctx *gin.Context) {
@@ -53,7 +54,7 @@ func (u *PermissionController) GetPermission(ctx *gin.Context) {
 	}
 
 	userID := middleware.GetLoginUserIDFromContext(ctx)
-	ops, requireRanks, err := u.rankService.CheckOperationPermissionsForRanks(ctx, userID, req.Actions)
+	ops, requireRanks, err := u.rankService.CheckOperationPermissionsForRanks(ctx, userID, req.ObjectID, req.Actions)
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
 		return
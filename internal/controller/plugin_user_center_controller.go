@@ -161,6 +161,10 @@ func (uc *UserCenterController) UserCenterLoginCallback(ctx *gin.Context) {
 		ctx.Redirect(http.StatusFound, fmt.Sprintf("/50x?title=%s&msg=%s", resp.ErrTitle, resp.ErrMsg))
 		return
 	}
+	if resp.TwoFactor != nil {
+		ctx.Redirect(http.StatusFound, twoFactorLoginURL(siteGeneral.SiteUrl, resp.TwoFactor))
+		return
+	}
 	userCenter.AfterLogin(userInfo.ExternalID, resp.AccessToken)
 	ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/auth-landing?access_token=%s",
 		siteGeneral.SiteUrl, resp.AccessToken))
@@ -196,6 +200,10 @@ func (uc *UserCenterController) UserCenterSignUpCallback(ctx *gin.Context) {
 		ctx.Redirect(http.StatusFound, fmt.Sprintf("/50x?title=%s&msg=%s", resp.ErrTitle, resp.ErrMsg))
 		return
 	}
+	if resp.TwoFactor != nil {
+		ctx.Redirect(http.StatusFound, twoFactorLoginURL(siteGeneral.SiteUrl, resp.TwoFactor))
+		return
+	}
 	userCenter.AfterLogin(userInfo.ExternalID, resp.AccessToken)
 	ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/users/auth-landing?access_token=%s",
 		siteGeneral.SiteUrl, resp.AccessToken))
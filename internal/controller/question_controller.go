@@ -32,6 +32,8 @@ import (
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/action"
 	"github.com/apache/answer/internal/service/content"
+	"github.com/apache/answer/internal/service/draft"
+	"github.com/apache/answer/internal/service/feed"
 	"github.com/apache/answer/internal/service/permission"
 	"github.com/apache/answer/internal/service/rank"
 	"github.com/apache/answer/internal/service/siteinfo_common"
@@ -49,6 +51,8 @@ type QuestionController struct {
 	siteInfoService     siteinfo_common.SiteInfoCommonService
 	actionService       *action.CaptchaService
 	rateLimitMiddleware *middleware.RateLimitMiddleware
+	feedService         *feed.FeedService
+	draftService        *draft.DraftService
 }
 
 // NewQuestionController new controller
@@ -59,6 +63,8 @@ func NewQuestionController(
 	siteInfoService siteinfo_common.SiteInfoCommonService,
 	actionService *action.CaptchaService,
 	rateLimitMiddleware *middleware.RateLimitMiddleware,
+	feedService *feed.FeedService,
+	draftService *draft.DraftService,
 ) *QuestionController {
 	return &QuestionController{
 		questionService:     questionService,
@@ -67,6 +73,8 @@ func NewQuestionController(
 		siteInfoService:     siteInfoService,
 		actionService:       actionService,
 		rateLimitMiddleware: rateLimitMiddleware,
+		feedService:         feedService,
+		draftService:        draftService,
 	}
 }
 
@@ -134,7 +142,7 @@ func (qc *QuestionController) OperationQuestion(ctx *gin.Context) {
 	}
 	req.ID = uid.DeShortID(req.ID)
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, req.ID, []string{
 		permission.QuestionPin,
 		permission.QuestionUnPin,
 		permission.QuestionHide,
@@ -174,6 +182,7 @@ func (qc *QuestionController) CloseQuestion(ctx *gin.Context) {
 		return
 	}
 	req.ID = uid.DeShortID(req.ID)
+	req.DuplicateQuestionID = uid.DeShortID(req.DuplicateQuestionID)
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 	can, err := qc.rankService.CheckOperationPermission(ctx, req.UserID, permission.QuestionClose, "")
 	if err != nil {
@@ -235,7 +244,7 @@ func (qc *QuestionController) GetQuestion(ctx *gin.Context) {
 	userID := middleware.GetLoginUserIDFromContext(ctx)
 	req := schema.QuestionPermission{}
 	req.IsAdminModerator = middleware.GetUserIsAdminModerator(ctx)
-	canList, err := qc.rankService.CheckOperationPermissions(ctx, userID, []string{
+	canList, err := qc.rankService.CheckOperationPermissions(ctx, userID, id, []string{
 		permission.QuestionEdit,
 		permission.QuestionDelete,
 		permission.QuestionClose,
@@ -269,6 +278,7 @@ func (qc *QuestionController) GetQuestion(ctx *gin.Context) {
 		handler.HandleResponse(ctx, err, nil)
 		return
 	}
+	qc.feedService.RecordQuestionView(ctx, userID, id)
 	if handler.GetEnableShortID(ctx) {
 		info.ID = uid.EnShortID(info.ID)
 	}
@@ -371,6 +381,28 @@ func (qc *QuestionController) QuestionRecommendPage(ctx *gin.Context) {
 	handler.HandleResponse(ctx, nil, pager.NewPageModel(total, questions))
 }
 
+// QuestionFeed get personalized question feed
+// @Summary get personalized question feed
+// @Description get the questions ranked by followed tags, followed users, answering history and similarity to recently viewed questions
+// @Tags Question
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param cursor query string false "cursor returned by the previous page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=schema.QuestionFeedResp}
+// @Router /answer/api/v1/question/feed [get]
+func (qc *QuestionController) QuestionFeed(ctx *gin.Context) {
+	req := &schema.QuestionFeedReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.LoginUserID = middleware.GetLoginUserIDFromContext(ctx)
+
+	resp, err := qc.feedService.GetQuestionFeed(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
 // AddQuestion add question
 // @Summary add question
 // @Description add question
@@ -399,7 +431,11 @@ func (qc *QuestionController) AddQuestion(ctx *gin.Context) {
 	}()
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, requireRanks, err := qc.rankService.CheckOperationPermissionsForRanks(ctx, req.UserID, []string{
+	if err := checkPublishAt(ctx, req.PublishAt); err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	canList, requireRanks, err := qc.rankService.CheckOperationPermissionsForRanks(ctx, req.UserID, "", []string{
 		permission.QuestionAdd,
 		permission.QuestionEdit,
 		permission.QuestionDelete,
@@ -483,6 +519,9 @@ func (qc *QuestionController) AddQuestion(ctx *gin.Context) {
 	if !isAdmin || !linkUrlLimitUser {
 		qc.actionService.ActionRecordAdd(ctx, entity.CaptchaActionQuestion, req.UserID)
 	}
+	if err == nil {
+		qc.draftService.DiscardDraft(ctx, req.UserID, entity.DraftTypeNewQuestion, "")
+	}
 	handler.HandleResponse(ctx, err, resp)
 }
 
@@ -503,8 +542,12 @@ func (qc *QuestionController) AddQuestionByAnswer(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	if err := checkPublishAt(ctx, req.PublishAt); err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
 
-	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, "", []string{
 		permission.QuestionAdd,
 		permission.QuestionEdit,
 		permission.QuestionDelete,
@@ -578,6 +621,9 @@ func (qc *QuestionController) AddQuestionByAnswer(ctx *gin.Context) {
 		handler.HandleResponse(ctx, errors.BadRequest(reason.RequestFormatError), errFields)
 		return
 	}
+	if err == nil {
+		qc.draftService.DiscardDraft(ctx, req.UserID, entity.DraftTypeNewQuestion, "")
+	}
 	// add the question id to the answer
 	questionInfo, ok := resp.(*schema.QuestionInfoResp)
 	if ok {
@@ -586,6 +632,7 @@ func (qc *QuestionController) AddQuestionByAnswer(ctx *gin.Context) {
 		answerReq.UserID = middleware.GetLoginUserIDFromContext(ctx)
 		answerReq.Content = req.AnswerContent
 		answerReq.HTML = req.AnswerHTML
+		answerReq.PublishAt = req.PublishAt
 		answerID, err := qc.answerService.Insert(ctx, answerReq)
 		if err != nil {
 			handler.HandleResponse(ctx, err, nil)
@@ -628,7 +675,7 @@ func (qc *QuestionController) UpdateQuestion(ctx *gin.Context) {
 	}
 	req.ID = uid.DeShortID(req.ID)
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, requireRanks, err := qc.rankService.CheckOperationPermissionsForRanks(ctx, req.UserID, []string{
+	canList, requireRanks, err := qc.rankService.CheckOperationPermissionsForRanks(ctx, req.UserID, req.ID, []string{
 		permission.QuestionEdit,
 		permission.QuestionDelete,
 		permission.QuestionEditWithoutReview,
@@ -701,6 +748,7 @@ func (qc *QuestionController) UpdateQuestion(ctx *gin.Context) {
 	if !isAdmin || !linkUrlLimitUser {
 		qc.actionService.ActionRecordAdd(ctx, entity.CaptchaActionEdit, req.UserID)
 	}
+	qc.draftService.DiscardDraft(ctx, req.UserID, entity.DraftTypeEditQuestion, req.ID)
 	handler.HandleResponse(ctx, nil, &schema.UpdateQuestionResp{UrlTitle: respInfo.UrlTitle, WaitForReview: !req.NoNeedReview})
 }
 
@@ -722,7 +770,7 @@ func (qc *QuestionController) QuestionRecover(ctx *gin.Context) {
 	req.QuestionID = uid.DeShortID(req.QuestionID)
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
-	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, req.QuestionID, []string{
 		permission.QuestionUnDelete,
 	})
 	if err != nil {
@@ -773,7 +821,7 @@ func (qc *QuestionController) UpdateQuestionInviteUser(ctx *gin.Context) {
 		}
 	}
 
-	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := qc.rankService.CheckOperationPermissions(ctx, req.UserID, req.ID, []string{
 		permission.AnswerInviteSomeoneToAnswer,
 	})
 	if err != nil {
@@ -55,6 +55,7 @@ func (cc *RankController) GetRankPersonalWithPage(ctx *gin.Context) {
 	}
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.LoginUserID = req.UserID
 
 	resp, err := cc.rankService.GetRankPersonalPage(ctx, req)
 	handler.HandleResponse(ctx, err, resp)
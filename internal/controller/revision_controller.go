@@ -24,31 +24,52 @@ import (
 	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/middleware"
 	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/base/validator"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/action"
+	"github.com/apache/answer/internal/service/close_vote"
 	"github.com/apache/answer/internal/service/content"
 	"github.com/apache/answer/internal/service/permission"
 	"github.com/apache/answer/internal/service/rank"
+	"github.com/apache/answer/internal/service/tag"
 	"github.com/apache/answer/pkg/obj"
 	"github.com/apache/answer/pkg/uid"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
 )
 
 // RevisionController revision controller
 type RevisionController struct {
 	revisionListService *content.RevisionService
 	rankService         *rank.RankService
+	questionService     *content.QuestionService
+	answerService       *content.AnswerService
+	tagService          *tag.TagService
+	closeVoteService    *close_vote.CloseVoteService
+	actionService       *action.CaptchaService
 }
 
 // NewRevisionController new controller
 func NewRevisionController(
 	revisionListService *content.RevisionService,
 	rankService *rank.RankService,
+	questionService *content.QuestionService,
+	answerService *content.AnswerService,
+	tagService *tag.TagService,
+	closeVoteService *close_vote.CloseVoteService,
+	actionService *action.CaptchaService,
 ) *RevisionController {
 	return &RevisionController{
 		revisionListService: revisionListService,
 		rankService:         rankService,
+		questionService:     questionService,
+		answerService:       answerService,
+		tagService:          tagService,
+		closeVoteService:    closeVoteService,
+		actionService:       actionService,
 	}
 }
 
@@ -99,7 +120,7 @@ func (rc *RevisionController) GetUnreviewedRevisionList(ctx *gin.Context) {
 	}
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, "", []string{
 		permission.QuestionAudit,
 		permission.AnswerAudit,
 		permission.TagAudit,
@@ -131,7 +152,7 @@ func (rc *RevisionController) RevisionAudit(ctx *gin.Context) {
 		return
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, "", []string{
 		permission.QuestionAudit,
 		permission.AnswerAudit,
 		permission.TagAudit,
@@ -205,10 +226,12 @@ func (rc *RevisionController) CheckCanUpdateRevision(ctx *gin.Context) {
 func (rc *RevisionController) GetReviewingType(ctx *gin.Context) {
 	req := &schema.GetReviewingTypeReq{}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, "", []string{
 		permission.QuestionAudit,
 		permission.AnswerAudit,
 		permission.TagAudit,
+		permission.QuestionClose,
+		permission.QuestionCloseVote,
 	})
 	if err != nil {
 		handler.HandleResponse(ctx, err, nil)
@@ -220,5 +243,198 @@ func (rc *RevisionController) GetReviewingType(ctx *gin.Context) {
 	req.IsAdmin = middleware.GetUserIsAdminModerator(ctx)
 
 	resp, err := rc.revisionListService.GetReviewingType(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+
+	// the close votes are reviewed by the users who can vote to close
+	if canList[3] || canList[4] {
+		closeVoteType, err := rc.closeVoteService.GetReviewingType(ctx)
+		if err != nil {
+			log.Errorf("get close vote count failed: %v", err)
+		} else {
+			resp = append(resp, closeVoteType)
+		}
+	}
+	handler.HandleResponse(ctx, nil, resp)
+}
+
+// GetRevisionDiff godoc
+// @Summary get the word level diff between two revisions
+// @Description get the word level diff of title, content and tags between two revisions of the same object
+// @Tags Revision
+// @Produce json
+// @Security ApiKeyAuth
+// @Param source_id query string true "the old revision id"
+// @Param target_id query string true "the new revision id"
+// @Success 200 {object} handler.RespBody{data=schema.GetRevisionDiffResp}
+// @Router /answer/api/v1/revisions/diff [get]
+func (rc *RevisionController) GetRevisionDiff(ctx *gin.Context) {
+	req := &schema.GetRevisionDiffReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.IsAdmin = middleware.GetUserIsAdminModerator(ctx)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+
+	resp, err := rc.revisionListService.GetRevisionDiff(ctx, req)
 	handler.HandleResponse(ctx, err, resp)
 }
+
+// RollbackRevision godoc
+// @Summary roll back to the revision
+// @Description restore the question, answer or tag to the revision by a new edit, it may need to be reviewed
+// @Tags Revision
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RevisionRollbackReq true "rollback"
+// @Success 200 {object} handler.RespBody{data=schema.RevisionRollbackResp}
+// @Router /answer/api/v1/revisions/rollback [post]
+func (rc *RevisionController) RollbackRevision(ctx *gin.Context) {
+	req := &schema.RevisionRollbackReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+
+	dto, err := rc.revisionListService.GetRevisionRollback(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+
+	// rolling back a question or an answer is an edit, so it is limited by the same captcha as the edit
+	var needCaptcha bool
+	if dto.ObjectType == constant.QuestionObjectType || dto.ObjectType == constant.AnswerObjectType {
+		canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, dto.ObjectID, []string{
+			permission.LinkUrlLimit,
+		})
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			return
+		}
+		needCaptcha = !middleware.GetUserIsAdminModerator(ctx) || !canList[0]
+	}
+	if needCaptcha {
+		captchaPass := rc.actionService.ActionRecordVerifyCaptcha(ctx, entity.CaptchaActionEdit, req.UserID, req.CaptchaID, req.CaptchaCode)
+		if !captchaPass {
+			errFields := append([]*validator.FormErrorField{}, &validator.FormErrorField{
+				ErrorField: "captcha_code",
+				ErrorMsg:   translator.Tr(handler.GetLangByCtx(ctx), reason.CaptchaVerificationFailed),
+			})
+			handler.HandleResponse(ctx, errors.BadRequest(reason.CaptchaVerificationFailed), errFields)
+			return
+		}
+	}
+
+	var noNeedReview bool
+	switch dto.ObjectType {
+	case constant.QuestionObjectType:
+		noNeedReview, err = rc.rollbackQuestion(ctx, dto.Question)
+	case constant.AnswerObjectType:
+		noNeedReview, err = rc.rollbackAnswer(ctx, dto.Answer)
+	case constant.TagObjectType:
+		noNeedReview, err = rc.rollbackTag(ctx, dto.Tag)
+	}
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if needCaptcha {
+		rc.actionService.ActionRecordAdd(ctx, entity.CaptchaActionEdit, req.UserID)
+	}
+	resp := &schema.RevisionRollbackResp{
+		ObjectID:      dto.ObjectID,
+		ObjectType:    dto.ObjectType,
+		WaitForReview: !noNeedReview,
+	}
+	if handler.GetEnableShortID(ctx) {
+		resp.ObjectID = uid.EnShortID(resp.ObjectID)
+	}
+	handler.HandleResponse(ctx, nil, resp)
+}
+
+func (rc *RevisionController) rollbackQuestion(ctx *gin.Context, req *schema.QuestionUpdate) (noNeedReview bool, err error) {
+	canList, requireRanks, err := rc.rankService.CheckOperationPermissionsForRanks(ctx, req.UserID, req.ID, []string{
+		permission.QuestionEdit,
+		permission.QuestionDelete,
+		permission.QuestionEditWithoutReview,
+		permission.TagUseReservedTag,
+		permission.TagAdd,
+	})
+	if err != nil {
+		return false, err
+	}
+	objectOwner := rc.rankService.CheckOperationObjectOwner(ctx, req.UserID, req.ID)
+	req.CanEdit = canList[0] || objectOwner
+	req.CanDelete = canList[1]
+	req.NoNeedReview = canList[2] || objectOwner
+	req.CanUseReservedTag = canList[3]
+	req.CanAddTag = canList[4]
+	if !req.CanEdit {
+		return false, errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+
+	if _, err = rc.questionService.UpdateQuestionCheckTags(ctx, req); err != nil {
+		return false, err
+	}
+	// the tags of the revision may have been deleted
+	hasNewTag, err := rc.questionService.HasNewTag(ctx, req.Tags)
+	if err != nil {
+		return false, err
+	}
+	if !req.CanAddTag && hasNewTag {
+		lang := handler.GetLangByCtx(ctx)
+		msg := translator.TrWithData(lang, reason.NoEnoughRankToOperate, &schema.PermissionTrTplData{Rank: requireRanks[4]})
+		return false, errors.Forbidden(reason.NoEnoughRankToOperate).WithMsg(msg)
+	}
+	if _, err = rc.questionService.UpdateQuestion(ctx, req); err != nil {
+		return false, err
+	}
+	return req.NoNeedReview, nil
+}
+
+func (rc *RevisionController) rollbackAnswer(ctx *gin.Context, req *schema.AnswerUpdateReq) (noNeedReview bool, err error) {
+	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, req.ID, []string{
+		permission.AnswerEdit,
+		permission.AnswerEditWithoutReview,
+	})
+	if err != nil {
+		return false, err
+	}
+	objectOwner := rc.rankService.CheckOperationObjectOwner(ctx, req.UserID, req.ID)
+	req.CanEdit = canList[0] || objectOwner
+	req.NoNeedReview = canList[1] || objectOwner
+	if !req.CanEdit {
+		return false, errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+	if _, err = rc.answerService.Update(ctx, req); err != nil {
+		return false, err
+	}
+	return req.NoNeedReview, nil
+}
+
+func (rc *RevisionController) rollbackTag(ctx *gin.Context, req *schema.UpdateTagReq) (noNeedReview bool, err error) {
+	canList, err := rc.rankService.CheckOperationPermissions(ctx, req.UserID, req.TagID, []string{
+		permission.TagEdit,
+		permission.TagEditWithoutReview,
+	})
+	if err != nil {
+		return false, err
+	}
+	// the owners of the tag can edit the tag wiki without reputation
+	isOwner, err := rc.tagService.IsTagOwner(ctx, req.TagID, req.UserID)
+	if err != nil {
+		return false, err
+	}
+	if !canList[0] && !isOwner {
+		return false, errors.Forbidden(reason.RankFailToMeetTheCondition)
+	}
+	req.NoNeedReview = canList[1] || isOwner
+	if err = rc.tagService.UpdateTag(ctx, req); err != nil {
+		return false, err
+	}
+	return req.NoNeedReview, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"time"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/scheduled_post"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/gin-gonic/gin"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// ScheduledPostController scheduled post controller
+type ScheduledPostController struct {
+	scheduledPostService *scheduled_post.ScheduledPostService
+}
+
+// NewScheduledPostController new controller
+func NewScheduledPostController(scheduledPostService *scheduled_post.ScheduledPostService) *ScheduledPostController {
+	return &ScheduledPostController{scheduledPostService: scheduledPostService}
+}
+
+// GetScheduledPostList get the scheduled posts of the login user
+// @Summary get the scheduled posts of the login user
+// @Description get the scheduled questions and answers of the login user, the earliest published first
+// @Tags Post
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.ScheduledPostResp}
+// @Router /answer/api/v1/post/scheduled [get]
+func (sc *ScheduledPostController) GetScheduledPostList(ctx *gin.Context) {
+	req := &schema.GetScheduledPostListReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.scheduledPostService.GetScheduledPostList(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// ReschedulePost change the publish time of the scheduled post
+// @Summary change the publish time of the scheduled post
+// @Description change the publish time of the scheduled question or answer, only the author and the moderators can do it
+// @Tags Post
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.ReschedulePostReq true "scheduled post"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/post/scheduled [put]
+func (sc *ScheduledPostController) ReschedulePost(ctx *gin.Context) {
+	req := &schema.ReschedulePostReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.ObjectID = uid.DeShortID(req.ObjectID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IsAdminModerator = middleware.GetUserIsAdminModerator(ctx)
+	err := sc.scheduledPostService.ReschedulePost(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// CancelScheduledPost cancel the scheduled post
+// @Summary cancel the scheduled post
+// @Description delete the scheduled question or answer before it is published, only the author and the moderators can do it
+// @Tags Post
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.CancelScheduledPostReq true "scheduled post"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/post/scheduled [delete]
+func (sc *ScheduledPostController) CancelScheduledPost(ctx *gin.Context) {
+	req := &schema.CancelScheduledPostReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.ObjectID = uid.DeShortID(req.ObjectID)
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.IsAdminModerator = middleware.GetUserIsAdminModerator(ctx)
+	err := sc.scheduledPostService.CancelScheduledPost(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// checkPublishAt only the moderators can schedule a post, and the publish time must be in the future
+func checkPublishAt(ctx *gin.Context, publishAt int64) error {
+	if publishAt == 0 {
+		return nil
+	}
+	if !middleware.GetUserIsAdminModerator(ctx) {
+		return errors.Forbidden(reason.ForbiddenError)
+	}
+	if publishAt <= time.Now().Unix() {
+		return errors.BadRequest(reason.ScheduledPostTimeInvalid)
+	}
+	return nil
+}
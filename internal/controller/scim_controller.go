@@ -0,0 +1,313 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/scim"
+	"github.com/gin-gonic/gin"
+	myErrors "github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// scimErrorTypes the scimType of the errors, see RFC 7644 3.12
+var scimErrorTypes = map[string]string{
+	reason.SCIMFilterInvalid:  "invalidFilter",
+	reason.SCIMPatchInvalid:   "invalidValue",
+	reason.SCIMEmailRequired:  "invalidValue",
+	reason.SCIMUserNameExists: "uniqueness",
+	reason.EmailDuplicate:     "uniqueness",
+	reason.SCIMGroupReadOnly:  "mutability",
+	reason.RequestFormatError: "invalidSyntax",
+}
+
+// SCIMController SCIM 2.0 provisioning controller
+type SCIMController struct {
+	scimService *scim.SCIMService
+}
+
+// NewSCIMController new controller
+func NewSCIMController(scimService *scim.SCIMService) *SCIMController {
+	return &SCIMController{scimService: scimService}
+}
+
+// GetServiceProviderConfig get the service provider config
+// @Summary get the SCIM service provider config
+// @Description get the features supported by the SCIM service
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Produce json
+// @Success 200 {object} map[string]any
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (sc *SCIMController) GetServiceProviderConfig(ctx *gin.Context) {
+	resp, err := sc.scimService.GetServiceProviderConfig(ctx)
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// GetUsers get the users
+// @Summary get the SCIM users
+// @Description get the users by page, or the one matched by the filter on userName or emails
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Produce json
+// @Param startIndex query int false "1-based start index"
+// @Param count query int false "count"
+// @Param filter query string false "filter such as userName eq \"alice@example.com\""
+// @Success 200 {object} schema.SCIMListResp
+// @Router /scim/v2/Users [get]
+func (sc *SCIMController) GetUsers(ctx *gin.Context) {
+	req := &schema.SCIMListReq{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		handleSCIMResponse(ctx, myErrors.BadRequest(reason.RequestFormatError), 0, nil)
+		return
+	}
+	resp, err := sc.scimService.GetUsers(ctx, req)
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// GetUser get the user
+// @Summary get the SCIM user
+// @Description get the user
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Produce json
+// @Param id path string true "user id"
+// @Success 200 {object} schema.SCIMUser
+// @Router /scim/v2/Users/{id} [get]
+func (sc *SCIMController) GetUser(ctx *gin.Context) {
+	resp, err := sc.scimService.GetUser(ctx, ctx.Param("id"))
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// AddUser create the user
+// @Summary create the SCIM user
+// @Description create the user, the user logs in with the single sign-on or resets the password
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Param data body schema.SCIMUser true "user"
+// @Success 201 {object} schema.SCIMUser
+// @Router /scim/v2/Users [post]
+func (sc *SCIMController) AddUser(ctx *gin.Context) {
+	req := &schema.SCIMUser{}
+	if !bindSCIM(ctx, req) {
+		return
+	}
+	resp, err := sc.scimService.AddUser(ctx, middleware.GetLoginUserIDFromContext(ctx), req)
+	handleSCIMResponse(ctx, err, http.StatusCreated, resp)
+}
+
+// UpdateUser replace the user
+// @Summary replace the SCIM user
+// @Description replace the attributes of the user, the inactive user is suspended
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Param id path string true "user id"
+// @Param data body schema.SCIMUser true "user"
+// @Success 200 {object} schema.SCIMUser
+// @Router /scim/v2/Users/{id} [put]
+func (sc *SCIMController) UpdateUser(ctx *gin.Context) {
+	req := &schema.SCIMUser{}
+	if !bindSCIM(ctx, req) {
+		return
+	}
+	resp, err := sc.scimService.UpdateUser(ctx, middleware.GetLoginUserIDFromContext(ctx), ctx.Param("id"), req)
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// PatchUser patch the user
+// @Summary patch the SCIM user
+// @Description patch the attributes of the user, the inactive user is suspended
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Param id path string true "user id"
+// @Param data body schema.SCIMPatchReq true "patch"
+// @Success 200 {object} schema.SCIMUser
+// @Router /scim/v2/Users/{id} [patch]
+func (sc *SCIMController) PatchUser(ctx *gin.Context) {
+	req := &schema.SCIMPatchReq{}
+	if !bindSCIM(ctx, req) {
+		return
+	}
+	resp, err := sc.scimService.PatchUser(ctx, middleware.GetLoginUserIDFromContext(ctx), ctx.Param("id"), req)
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// DeleteUser delete the user
+// @Summary delete the SCIM user
+// @Description delete the user, the content created by the user is kept
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Param id path string true "user id"
+// @Success 204
+// @Router /scim/v2/Users/{id} [delete]
+func (sc *SCIMController) DeleteUser(ctx *gin.Context) {
+	err := sc.scimService.DeleteUser(ctx, middleware.GetLoginUserIDFromContext(ctx), ctx.Param("id"))
+	handleSCIMResponse(ctx, err, http.StatusNoContent, nil)
+}
+
+// GetGroups get the groups
+// @Summary get the SCIM groups
+// @Description get the groups, which are the roles of the site
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Produce json
+// @Param filter query string false "filter such as displayName eq \"Moderator\""
+// @Success 200 {object} schema.SCIMListResp
+// @Router /scim/v2/Groups [get]
+func (sc *SCIMController) GetGroups(ctx *gin.Context) {
+	req := &schema.SCIMListReq{}
+	if err := ctx.ShouldBindQuery(req); err != nil {
+		handleSCIMResponse(ctx, myErrors.BadRequest(reason.RequestFormatError), 0, nil)
+		return
+	}
+	resp, err := sc.scimService.GetGroups(ctx, req)
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// GetGroup get the group
+// @Summary get the SCIM group
+// @Description get the group with its members, the members of the user role are not listed
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Produce json
+// @Param id path string true "role id"
+// @Success 200 {object} schema.SCIMGroup
+// @Router /scim/v2/Groups/{id} [get]
+func (sc *SCIMController) GetGroup(ctx *gin.Context) {
+	resp, err := sc.scimService.GetGroup(ctx, ctx.Param("id"))
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// AddGroup the groups are the roles of the site and can not be created
+// @Summary create the SCIM group
+// @Description the groups are the roles of the site, so it always fails
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Router /scim/v2/Groups [post]
+func (sc *SCIMController) AddGroup(ctx *gin.Context) {
+	handleSCIMResponse(ctx, myErrors.BadRequest(reason.SCIMGroupReadOnly), 0, nil)
+}
+
+// UpdateGroup replace the members of the group
+// @Summary replace the SCIM group
+// @Description replace the members of the group, the roles of the users are changed accordingly
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Param id path string true "role id"
+// @Param data body schema.SCIMGroup true "group"
+// @Success 200 {object} schema.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+func (sc *SCIMController) UpdateGroup(ctx *gin.Context) {
+	req := &schema.SCIMGroup{}
+	if !bindSCIM(ctx, req) {
+		return
+	}
+	resp, err := sc.scimService.UpdateGroup(ctx, middleware.GetLoginUserIDFromContext(ctx), ctx.Param("id"), req)
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// PatchGroup add or remove the members of the group
+// @Summary patch the SCIM group
+// @Description add or remove the members of the group, the roles of the users are changed accordingly
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Param id path string true "role id"
+// @Param data body schema.SCIMPatchReq true "patch"
+// @Success 200 {object} schema.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (sc *SCIMController) PatchGroup(ctx *gin.Context) {
+	req := &schema.SCIMPatchReq{}
+	if !bindSCIM(ctx, req) {
+		return
+	}
+	resp, err := sc.scimService.PatchGroup(ctx, middleware.GetLoginUserIDFromContext(ctx), ctx.Param("id"), req)
+	handleSCIMResponse(ctx, err, http.StatusOK, resp)
+}
+
+// DeleteGroup the groups are the roles of the site and can not be deleted
+// @Summary delete the SCIM group
+// @Description the groups are the roles of the site, so it always fails
+// @Security ApiKeyAuth
+// @Tags SCIM
+// @Router /scim/v2/Groups/{id} [delete]
+func (sc *SCIMController) DeleteGroup(ctx *gin.Context) {
+	handleSCIMResponse(ctx, myErrors.BadRequest(reason.SCIMGroupReadOnly), 0, nil)
+}
+
+// bindSCIM bind the body, which is JSON though the content type is application/scim+json
+func bindSCIM(ctx *gin.Context, req any) bool {
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		log.Errorf("bind scim request failed: %v", err)
+		handleSCIMResponse(ctx, myErrors.BadRequest(reason.RequestFormatError), 0, nil)
+		return false
+	}
+	return true
+}
+
+// handleSCIMResponse write the resource, or the error in the format of SCIM
+func handleSCIMResponse(ctx *gin.Context, err error, status int, data any) {
+	if err == nil {
+		if data == nil {
+			ctx.Status(status)
+			return
+		}
+		body, _ := json.Marshal(data)
+		ctx.Data(status, schema.SCIMContentType, body)
+		return
+	}
+
+	var myErr *myErrors.Error
+	if !errors.As(err, &myErr) {
+		log.Error(err)
+		myErr = myErrors.InternalServer(reason.UnknownError)
+	}
+	if myErrors.IsInternalServer(myErr) {
+		log.Error(myErr)
+	}
+	detail := myErr.Message
+	if len(detail) == 0 {
+		detail = translator.Tr(handler.GetLangByCtx(ctx), myErr.Reason)
+	}
+	body, _ := json.Marshal(&schema.SCIMErrorResp{
+		Schemas:  []string{schema.SCIMSchemaError},
+		Status:   strconv.Itoa(myErr.Code),
+		ScimType: scimErrorTypes[myErr.Reason],
+		Detail:   detail,
+	})
+	ctx.Data(myErr.Code, schema.SCIMContentType, body)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/action"
 	"github.com/apache/answer/internal/service/content"
+	"github.com/apache/answer/internal/service/saved_search"
 	"github.com/apache/answer/plugin"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
@@ -36,18 +37,21 @@ import (
 
 // SearchController tag controller
 type SearchController struct {
-	searchService *content.SearchService
-	actionService *action.CaptchaService
+	searchService      *content.SearchService
+	actionService      *action.CaptchaService
+	savedSearchService *saved_search.SavedSearchService
 }
 
 // NewSearchController new controller
 func NewSearchController(
 	searchService *content.SearchService,
 	actionService *action.CaptchaService,
+	savedSearchService *saved_search.SavedSearchService,
 ) *SearchController {
 	return &SearchController{
-		searchService: searchService,
-		actionService: actionService,
+		searchService:      searchService,
+		actionService:      actionService,
+		savedSearchService: savedSearchService,
 	}
 }
 
@@ -59,6 +63,8 @@ func NewSearchController(
 // @Security ApiKeyAuth
 // @Param q query string true "query string"
 // @Param order query string true "order" Enums(newest,active,score,relevance)
+// @Param facets query bool false "return the facets of all matched contents"
+// @Param space_id query int false "search in the space"
 // @Success 200 {object} handler.RespBody{data=schema.SearchResp}
 // @Router /answer/api/v1/search [get]
 func (sc *SearchController) Search(ctx *gin.Context) {
@@ -106,10 +112,104 @@ func (sc *SearchController) SearchDesc(ctx *gin.Context) {
 		return nil
 	})
 	resp := &schema.SearchDescResp{}
-	if finder != nil {
+	if finder != nil && plugin.StatusManager.IsEnabledFor(ctx, finder.Info().SlugName) {
 		resp.Name = finder.Info().Name.Translate(ctx)
 		resp.Icon = finder.Description().Icon
 		resp.Link = finder.Description().Link
 	}
 	handler.HandleResponse(ctx, nil, resp)
 }
+
+// GetSavedSearchList get saved search list
+// @Summary get the saved searches of current user
+// @Description get the saved searches of current user
+// @Tags Search
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.SavedSearchResp}
+// @Router /answer/api/v1/search/saved [get]
+func (sc *SearchController) GetSavedSearchList(ctx *gin.Context) {
+	req := &schema.GetSavedSearchListReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.savedSearchService.GetSavedSearchList(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddSavedSearch add saved search
+// @Summary save a search, the new matched contents will be notified if enabled
+// @Description save a search, the new matched contents will be notified if enabled
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddSavedSearchReq true "saved search"
+// @Success 200 {object} handler.RespBody{data=schema.SavedSearchResp}
+// @Router /answer/api/v1/search/saved [post]
+func (sc *SearchController) AddSavedSearch(ctx *gin.Context) {
+	req := &schema.AddSavedSearchReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.savedSearchService.AddSavedSearch(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateSavedSearch update saved search
+// @Summary update saved search
+// @Description update saved search
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateSavedSearchReq true "saved search"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/api/v1/search/saved [put]
+func (sc *SearchController) UpdateSavedSearch(ctx *gin.Context) {
+	req := &schema.UpdateSavedSearchReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := sc.savedSearchService.UpdateSavedSearch(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// DeleteSavedSearch delete saved search
+// @Summary delete saved search
+// @Description delete saved search
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.DeleteSavedSearchReq true "saved search"
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/api/v1/search/saved [delete]
+func (sc *SearchController) DeleteSavedSearch(ctx *gin.Context) {
+	req := &schema.DeleteSavedSearchReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := sc.savedSearchService.DeleteSavedSearch(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// CheckDuplicateQuestions check whether the question draft duplicates the existing questions
+// @Summary find the existing questions that the question draft may duplicate, with the confidence scores
+// @Description find the existing questions that the question draft may duplicate, with the confidence scores
+// @Tags Question
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.CheckDuplicateQuestionReq true "question draft"
+// @Success 200 {object} handler.RespBody{data=[]schema.DuplicateQuestionResp}
+// @Router /answer/api/v1/question/duplicates [post]
+func (sc *SearchController) CheckDuplicateQuestions(ctx *gin.Context) {
+	req := &schema.CheckDuplicateQuestionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := sc.searchService.FindDuplicateQuestions(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
@@ -0,0 +1,231 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/space"
+	"github.com/gin-gonic/gin"
+)
+
+// SpaceController space controller
+type SpaceController struct {
+	spaceService *space.SpaceService
+}
+
+// NewSpaceController new controller
+func NewSpaceController(spaceService *space.SpaceService) *SpaceController {
+	return &SpaceController{spaceService: spaceService}
+}
+
+// GetUserSpaces get the spaces of the login user
+// @Summary get the spaces of the login user
+// @Description get the spaces that the login user is a member of
+// @Tags Space
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.SpaceResp}
+// @Router /answer/api/v1/spaces [get]
+func (sc *SpaceController) GetUserSpaces(ctx *gin.Context) {
+	userID := middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.spaceService.GetUserSpaces(ctx, userID)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// GetSpace get the space
+// @Summary get the space
+// @Description get the space, only the members can read it
+// @Tags Space
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id query int true "space id"
+// @Success 200 {object} handler.RespBody{data=schema.SpaceResp}
+// @Router /answer/api/v1/space [get]
+func (sc *SpaceController) GetSpace(ctx *gin.Context) {
+	req := &schema.GetSpaceReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.spaceService.GetSpace(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateSpace update the space
+// @Summary update the space
+// @Description update the name and description of the space, only the owners can do it
+// @Tags Space
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateSpaceReq true "space"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/space [put]
+func (sc *SpaceController) UpdateSpace(ctx *gin.Context) {
+	req := &schema.UpdateSpaceReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := sc.spaceService.UpdateSpace(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetSpaceMemberPage get the members of the space
+// @Summary get the members of the space
+// @Description get the members of the space, the owners first
+// @Tags Space
+// @Produce json
+// @Security ApiKeyAuth
+// @Param space_id query int true "space id"
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.SpaceMemberResp}}
+// @Router /answer/api/v1/space/members [get]
+func (sc *SpaceController) GetSpaceMemberPage(ctx *gin.Context) {
+	req := &schema.GetSpaceMemberPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.spaceService.GetSpaceMemberPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddSpaceMember add the member to the space
+// @Summary add the member to the space
+// @Description add the user to the space with the role, only the owners can do it
+// @Tags Space
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.AddSpaceMemberReq true "member"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/space/member [post]
+func (sc *SpaceController) AddSpaceMember(ctx *gin.Context) {
+	req := &schema.AddSpaceMemberReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := sc.spaceService.AddSpaceMember(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// UpdateSpaceMember change the role of the member
+// @Summary change the role of the member
+// @Description change the role of the member, only the owners can do it
+// @Tags Space
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UpdateSpaceMemberReq true "member"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/space/member [put]
+func (sc *SpaceController) UpdateSpaceMember(ctx *gin.Context) {
+	req := &schema.UpdateSpaceMemberReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := sc.spaceService.UpdateSpaceMember(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveSpaceMember remove the member from the space
+// @Summary remove the member from the space
+// @Description remove the member from the space by the owners, or leave the space by the member itself
+// @Tags Space
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RemoveSpaceMemberReq true "member"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/space/member [delete]
+func (sc *SpaceController) RemoveSpaceMember(ctx *gin.Context) {
+	req := &schema.RemoveSpaceMemberReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := sc.spaceService.RemoveSpaceMember(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// AdminGetSpacePage get all spaces
+// @Summary get all spaces
+// @Description get all available spaces by page
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Param query query string false "slug name or display name prefix"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.SpaceResp}}
+// @Router /answer/admin/api/spaces [get]
+func (sc *SpaceController) AdminGetSpacePage(ctx *gin.Context) {
+	req := &schema.GetSpacePageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := sc.spaceService.GetSpacePage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AdminAddSpace create the space
+// @Summary create the space
+// @Description create the space with its first owner
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddSpaceReq true "space"
+// @Success 200 {object} handler.RespBody{data=schema.AddSpaceResp}
+// @Router /answer/admin/api/space [post]
+func (sc *SpaceController) AdminAddSpace(ctx *gin.Context) {
+	req := &schema.AddSpaceReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := sc.spaceService.AddSpace(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AdminDeleteSpace delete the space
+// @Summary delete the space
+// @Description delete the space, its contents are only visible to the administrators and moderators
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.DeleteSpaceReq true "space"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/space [delete]
+func (sc *SpaceController) AdminDeleteSpace(ctx *gin.Context) {
+	req := &schema.DeleteSpaceReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.spaceService.DeleteSpace(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
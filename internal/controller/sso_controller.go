@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/sso"
+	"github.com/apache/answer/internal/service/user_external_login"
+	"github.com/gin-gonic/gin"
+	myErrors "github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// SSOController single sign-on controller
+type SSOController struct {
+	ssoService          *sso.SSOService
+	siteInfoService     siteinfo_common.SiteInfoCommonService
+	userExternalService *user_external_login.UserExternalLoginService
+}
+
+// NewSSOController new controller
+func NewSSOController(
+	ssoService *sso.SSOService,
+	siteInfoService siteinfo_common.SiteInfoCommonService,
+	userExternalService *user_external_login.UserExternalLoginService,
+) *SSOController {
+	return &SSOController{
+		ssoService:          ssoService,
+		siteInfoService:     siteInfoService,
+		userExternalService: userExternalService,
+	}
+}
+
+// SSOLogin redirect the user to the identity provider
+func (sc *SSOController) SSOLogin(ctx *gin.Context) {
+	redirectURL, err := sc.ssoService.AuthRedirectURL(ctx, ctx.Param("name"), ctx.Query("state"))
+	if err != nil {
+		log.Errorf("sso login failed: %v", err)
+		ctx.Redirect(http.StatusFound, "/50x")
+		return
+	}
+	ctx.Redirect(http.StatusFound, redirectURL)
+}
+
+// SSOOIDCCallback receive the authorization response of the OpenID Connect provider
+func (sc *SSOController) SSOOIDCCallback(ctx *gin.Context) {
+	stateInfo, userInfo, err := sc.ssoService.OIDCCallback(ctx, ctx.Param("name"), ctx.Query("state"), ctx.Query("code"))
+	sc.finishLogin(ctx, stateInfo, userInfo, err)
+}
+
+// SSOSAMLACS receive the response posted by the SAML provider
+func (sc *SSOController) SSOSAMLACS(ctx *gin.Context) {
+	req := &schema.SSOSAMLResponseReq{}
+	if err := ctx.ShouldBind(req); err != nil {
+		log.Errorf("bind saml response failed: %v", err)
+		ctx.Redirect(http.StatusFound, "/50x")
+		return
+	}
+	stateInfo, userInfo, err := sc.ssoService.SAMLCallback(ctx, ctx.Param("name"), req)
+	sc.finishLogin(ctx, stateInfo, userInfo, err)
+}
+
+// SSOSAMLMetadata get the metadata of the SAML service provider
+func (sc *SSOController) SSOSAMLMetadata(ctx *gin.Context) {
+	metadata, err := sc.ssoService.SAMLMetadata(ctx, ctx.Param("name"))
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	ctx.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+func (sc *SSOController) finishLogin(ctx *gin.Context, stateInfo *schema.ExternalLoginOAuthState,
+	userInfo *schema.ExternalLoginUserInfoCache, err error) {
+	if err != nil {
+		log.Errorf("sso callback failed: %v", err)
+		var pacmanErr *myErrors.Error
+		if errors.As(err, &pacmanErr) && pacmanErr.Reason == reason.SSOUserDeactivated {
+			msg := translator.Tr(handler.GetLangByCtx(ctx), reason.SSOUserDeactivated)
+			ctx.Redirect(http.StatusFound, fmt.Sprintf("/50x?title=%s&msg=%s",
+				url.QueryEscape(http.StatusText(http.StatusForbidden)), url.QueryEscape(msg)))
+			return
+		}
+		ctx.Redirect(http.StatusFound, "/50x")
+		return
+	}
+	siteGeneral, err := sc.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		log.Errorf("get site info failed: %v", err)
+		ctx.Redirect(http.StatusFound, "/50x")
+		return
+	}
+	finishExternalLogin(ctx, sc.userExternalService, siteGeneral.SiteUrl, stateInfo, userInfo)
+}
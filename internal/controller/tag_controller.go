@@ -29,15 +29,17 @@ import (
 	"github.com/apache/answer/internal/service/rank"
 	"github.com/apache/answer/internal/service/tag"
 	"github.com/apache/answer/internal/service/tag_common"
+	"github.com/apache/answer/internal/service/tag_suggestion"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
 )
 
 // TagController tag controller
 type TagController struct {
-	tagService       *tag.TagService
-	tagCommonService *tag_common.TagCommonService
-	rankService      *rank.RankService
+	tagService           *tag.TagService
+	tagCommonService     *tag_common.TagCommonService
+	rankService          *rank.RankService
+	tagSuggestionService *tag_suggestion.TagSuggestionService
 }
 
 // NewTagController new controller
@@ -45,8 +47,14 @@ func NewTagController(
 	tagService *tag.TagService,
 	tagCommonService *tag_common.TagCommonService,
 	rankService *rank.RankService,
+	tagSuggestionService *tag_suggestion.TagSuggestionService,
 ) *TagController {
-	return &TagController{tagService: tagService, tagCommonService: tagCommonService, rankService: rankService}
+	return &TagController{
+		tagService:           tagService,
+		tagCommonService:     tagCommonService,
+		rankService:          rankService,
+		tagSuggestionService: tagSuggestionService,
+	}
 }
 
 // SearchTagLike get tag list
@@ -56,6 +64,7 @@ func NewTagController(
 // @Produce json
 // @Security ApiKeyAuth
 // @Param tag query string false "tag"
+// @Param space_id query int false "space id"
 // @Success 200 {object} handler.RespBody{data=[]schema.GetTagBasicResp}
 // @Router /answer/api/v1/question/tags [get]
 func (tc *TagController) SearchTagLike(ctx *gin.Context) {
@@ -63,6 +72,7 @@ func (tc *TagController) SearchTagLike(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 	resp, err := tc.tagCommonService.SearchTagLike(ctx, req)
 	handler.HandleResponse(ctx, err, resp)
 }
@@ -132,7 +142,7 @@ func (tc *TagController) AddTag(ctx *gin.Context) {
 	}
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, "", []string{
 		permission.TagAdd,
 	})
 	if err != nil {
@@ -165,7 +175,7 @@ func (tc *TagController) UpdateTag(ctx *gin.Context) {
 	}
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, req.TagID, []string{
 		permission.TagEdit,
 		permission.TagEditWithoutReview,
 	})
@@ -173,11 +183,17 @@ func (tc *TagController) UpdateTag(ctx *gin.Context) {
 		handler.HandleResponse(ctx, err, nil)
 		return
 	}
-	if !canList[0] {
+	// the owners of the tag can edit the tag wiki without reputation
+	isOwner, err := tc.tagCommonService.IsTagOwner(ctx, req.TagID, req.UserID)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !canList[0] && !isOwner {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
 		return
 	}
-	req.NoNeedReview = canList[1]
+	req.NoNeedReview = canList[1] || isOwner
 
 	err = tc.tagService.UpdateTag(ctx, req)
 	if err != nil {
@@ -204,7 +220,7 @@ func (tc *TagController) RecoverTag(ctx *gin.Context) {
 	}
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
 
-	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, req.TagID, []string{
 		permission.TagUnDelete,
 	})
 	if err != nil {
@@ -237,7 +253,7 @@ func (tc *TagController) GetTagInfo(ctx *gin.Context) {
 	}
 
 	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
-	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, []string{
+	canList, err := tc.rankService.CheckOperationPermissions(ctx, req.UserID, req.ID, []string{
 		permission.TagEdit,
 		permission.TagDelete,
 		permission.TagUnDelete,
@@ -264,6 +280,7 @@ func (tc *TagController) GetTagInfo(ctx *gin.Context) {
 // @Param page_size query int false "page size"
 // @Param slug_name query string false "slug_name"
 // @Param query_cond query string false "query condition" Enums(popular, name, newest)
+// @Param space_id query int false "space id"
 // @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.GetTagPageResp}}
 // @Router /answer/api/v1/tags/page [get]
 func (tc *TagController) GetTagWithPage(ctx *gin.Context) {
@@ -384,3 +401,185 @@ func (tc *TagController) MergeTag(ctx *gin.Context) {
 
 	handler.HandleResponse(ctx, err, nil)
 }
+
+// UpdateTagParent update the parent of the tag
+// @Summary update the parent of the tag
+// @Description update the parent of the tag, the questions of the child tags can also be found by the parent tag
+// @Security ApiKeyAuth
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateTagParentReq true "tag"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/parent [put]
+func (tc *TagController) UpdateTagParent(ctx *gin.Context) {
+	req := &schema.UpdateTagParentReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := tc.rankService.CheckOperationPermission(ctx, req.UserID, permission.TagSynonym, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	err = tc.tagService.UpdateTagParent(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetTagOwners get the owners of the tag
+// @Summary get the owners of the tag
+// @Description get the owners of the tag
+// @Tags Tag
+// @Produce json
+// @Param tag_id query string true "tag id"
+// @Success 200 {object} handler.RespBody{data=[]schema.UserBasicInfo}
+// @Router /answer/api/v1/tag/owners [get]
+func (tc *TagController) GetTagOwners(ctx *gin.Context) {
+	req := &schema.GetTagOwnersReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := tc.tagService.GetTagOwners(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateTagOwners update the owners of the tag
+// @Summary update the owners of the tag
+// @Description update the owners of the tag, the owners are notified of the new questions and can edit the tag wiki
+// @Security ApiKeyAuth
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateTagOwnersReq true "tag owners"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/owners [put]
+func (tc *TagController) UpdateTagOwners(ctx *gin.Context) {
+	req := &schema.UpdateTagOwnersReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := tc.rankService.CheckOperationPermission(ctx, req.UserID, permission.TagSynonym, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	err = tc.tagService.UpdateTagOwners(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetTagSetting get the setting of the tag
+// @Summary get the setting of the tag
+// @Description get the question template and the companion tags of the tag
+// @Tags Tag
+// @Produce json
+// @Param tag_id query string true "tag id"
+// @Success 200 {object} handler.RespBody{data=schema.GetTagSettingResp}
+// @Router /answer/api/v1/tag/setting [get]
+func (tc *TagController) GetTagSetting(ctx *gin.Context) {
+	req := &schema.GetTagSettingReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := tc.tagService.GetTagSetting(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateTagSetting update the setting of the tag
+// @Summary update the setting of the tag
+// @Description update the question template and the companion tags of the tag
+// @Security ApiKeyAuth
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateTagSettingReq true "tag setting"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/tag/setting [put]
+func (tc *TagController) UpdateTagSetting(ctx *gin.Context) {
+	req := &schema.UpdateTagSettingReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	can, err := tc.rankService.CheckOperationPermission(ctx, req.UserID, permission.TagSynonym, "")
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if !can {
+		can, err = tc.tagCommonService.IsTagOwner(ctx, req.TagID, req.UserID)
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			return
+		}
+	}
+	if !can {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.RankFailToMeetTheCondition), nil)
+		return
+	}
+
+	err = tc.tagService.UpdateTagSetting(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// SuggestTags suggest tags for the draft question
+// @Summary suggest tags for the draft question
+// @Description suggest tags from the title and content of the draft question
+// @Security ApiKeyAuth
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param data body schema.SuggestTagsReq true "draft question"
+// @Success 200 {object} handler.RespBody{data=[]schema.GetTagBasicResp}
+// @Router /answer/api/v1/question/tags/suggest [post]
+func (tc *TagController) SuggestTags(ctx *gin.Context) {
+	req := &schema.SuggestTagsReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	resp, err := tc.tagSuggestionService.SuggestTags(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AdminGetTagSuggestionStatus get the status of the tag suggestion
+// @Summary get the status of the tag suggestion
+// @Description get the status of the tag suggestion
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.GetTagSuggestionStatusResp}
+// @Router /answer/admin/api/tag/suggestion [get]
+func (tc *TagController) AdminGetTagSuggestionStatus(ctx *gin.Context) {
+	resp, err := tc.tagSuggestionService.GetStatus(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AdminRetrainTagSuggestion retrain the tag suggestion
+// @Summary retrain the tag suggestion
+// @Description rebuild the term statistics of the tag suggestion from all the tagged questions in the background
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/tag/suggestion/retrain [post]
+func (tc *TagController) AdminRetrainTagSuggestion(ctx *gin.Context) {
+	err := tc.tagSuggestionService.Retrain(ctx)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -334,6 +334,12 @@ func (tc *TemplateController) QuestionInfo(ctx *gin.Context) {
 	}
 
 	siteInfo := tc.SiteInfo(ctx)
+	// the duplicate question is redirected to its canonical question, unless a specific answer
+	// is requested or the visitor wants to see the duplicate question itself by noredirect
+	if len(detail.DuplicateQuestionID) > 0 && len(answerid) == 0 && len(ctx.Query("noredirect")) == 0 {
+		ctx.Redirect(http.StatusFound, fmt.Sprintf("%s/questions/%s", siteInfo.General.SiteUrl, detail.DuplicateQuestionID))
+		return
+	}
 	jump, jumpurl := tc.QuestionInfoRedirect(ctx, siteInfo, correctTitle)
 	if jump {
 		ctx.Redirect(http.StatusFound, jumpurl)
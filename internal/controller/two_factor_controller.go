@@ -0,0 +1,178 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/two_factor"
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorController the second factors of the login user
+type TwoFactorController struct {
+	twoFactorService *two_factor.TwoFactorService
+}
+
+// NewTwoFactorController new controller
+func NewTwoFactorController(twoFactorService *two_factor.TwoFactorService) *TwoFactorController {
+	return &TwoFactorController{twoFactorService: twoFactorService}
+}
+
+// GetTwoFactor get the second factors
+// @Summary get the second factors
+// @Description get the authenticator app status, the security keys and the number of the unused recovery codes
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.GetTwoFactorResp}
+// @Router /answer/api/v1/user/2fa [get]
+func (tc *TwoFactorController) GetTwoFactor(ctx *gin.Context) {
+	resp, err := tc.twoFactorService.GetTwoFactor(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// SetupTOTP set up the authenticator app
+// @Summary set up the authenticator app
+// @Description generate the secret to be added to the authenticator app, it is enabled by its first passcode
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.TOTPSetupResp}
+// @Router /answer/api/v1/user/2fa/totp/setup [post]
+func (tc *TwoFactorController) SetupTOTP(ctx *gin.Context) {
+	resp, err := tc.twoFactorService.SetupTOTP(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// EnableTOTP enable the authenticator app
+// @Summary enable the authenticator app
+// @Description enable the authenticator app with its first passcode, the recovery codes are returned only once
+// @Description if it is the first second factor
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.EnableTOTPReq true "passcode"
+// @Success 200 {object} handler.RespBody{data=schema.RecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/totp [post]
+func (tc *TwoFactorController) EnableTOTP(ctx *gin.Context) {
+	req := &schema.EnableTOTPReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.twoFactorService.EnableTOTP(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// DisableTOTP disable the authenticator app
+// @Summary disable the authenticator app
+// @Description disable the authenticator app, the recovery codes are removed with the last second factor
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/2fa/totp [delete]
+func (tc *TwoFactorController) DisableTOTP(ctx *gin.Context) {
+	err := tc.twoFactorService.DisableTOTP(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RegenerateRecoveryCodes regenerate the recovery codes
+// @Summary regenerate the recovery codes
+// @Description replace all the recovery codes, the new ones are returned only once
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.RecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/recovery-codes [post]
+func (tc *TwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	resp, err := tc.twoFactorService.RegenerateRecoveryCodes(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// BeginWebAuthnRegistration begin the registration of a security key
+// @Summary begin the registration of a security key
+// @Description get the options for navigator.credentials.create() to create a security key or passkey
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=webauthn.CreationOptions}
+// @Router /answer/api/v1/user/2fa/webauthn/registration [get]
+func (tc *TwoFactorController) BeginWebAuthnRegistration(ctx *gin.Context) {
+	resp, err := tc.twoFactorService.BeginWebAuthnRegistration(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// FinishWebAuthnRegistration finish the registration of a security key
+// @Summary finish the registration of a security key
+// @Description save the security key created by the browser, the recovery codes are returned only once
+// @Description if it is the first second factor
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.FinishWebAuthnRegistrationReq true "credential"
+// @Success 200 {object} handler.RespBody{data=schema.RecoveryCodesResp}
+// @Router /answer/api/v1/user/2fa/webauthn/registration [post]
+func (tc *TwoFactorController) FinishWebAuthnRegistration(ctx *gin.Context) {
+	req := &schema.FinishWebAuthnRegistrationReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := tc.twoFactorService.FinishWebAuthnRegistration(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RemoveWebAuthnCredential remove the security key
+// @Summary remove the security key
+// @Description remove the security key, the recovery codes are removed with the last second factor
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RemoveWebAuthnCredentialReq true "security key"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/2fa/webauthn [delete]
+func (tc *TwoFactorController) RemoveWebAuthnCredential(ctx *gin.Context) {
+	req := &schema.RemoveWebAuthnCredentialReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := tc.twoFactorService.RemoveWebAuthnCredential(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// BeginWebAuthnReauth begin the reauthentication with a security key
+// @Summary begin the reauthentication with a security key
+// @Description get the options for navigator.credentials.get(), the assertion is sent to the reauthentication
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=webauthn.RequestOptions}
+// @Router /answer/api/v1/user/reauth/webauthn [get]
+func (tc *TwoFactorController) BeginWebAuthnReauth(ctx *gin.Context) {
+	resp, err := tc.twoFactorService.BeginWebAuthnReauth(ctx, middleware.GetLoginUserIDFromContext(ctx))
+	handler.HandleResponse(ctx, err, resp)
+}
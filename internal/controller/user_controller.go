@@ -35,6 +35,7 @@ import (
 	"github.com/apache/answer/internal/service/content"
 	"github.com/apache/answer/internal/service/export"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/two_factor"
 	"github.com/apache/answer/internal/service/user_notification_config"
 	"github.com/apache/answer/pkg/checker"
 	"github.com/gin-gonic/gin"
@@ -50,6 +51,7 @@ type UserController struct {
 	emailService                  *export.EmailService
 	siteInfoCommonService         siteinfo_common.SiteInfoCommonService
 	userNotificationConfigService *user_notification_config.UserNotificationConfigService
+	twoFactorService              *two_factor.TwoFactorService
 }
 
 // NewUserController new controller
@@ -60,6 +62,7 @@ func NewUserController(
 	emailService *export.EmailService,
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
 	userNotificationConfigService *user_notification_config.UserNotificationConfigService,
+	twoFactorService *two_factor.TwoFactorService,
 ) *UserController {
 	return &UserController{
 		authService:                   authService,
@@ -68,6 +71,7 @@ func NewUserController(
 		emailService:                  emailService,
 		siteInfoCommonService:         siteInfoCommonService,
 		userNotificationConfigService: userNotificationConfigService,
+		twoFactorService:              twoFactorService,
 	}
 }
 
@@ -87,8 +91,8 @@ func (uc *UserController) GetUserInfoByUserID(ctx *gin.Context) {
 		return
 	}
 
-	// if user is no login return null in data
-	userInfo, _ := uc.authService.GetUserCacheInfo(ctx, token)
+	// if user is no login or the session has timed out return null in data
+	userInfo := middleware.GetUserInfoFromContext(ctx)
 	if userInfo == nil {
 		handler.HandleResponse(ctx, nil, nil)
 		return
@@ -162,6 +166,10 @@ func (uc *UserController) UserEmailLogin(ctx *gin.Context) {
 	if !isAdmin {
 		uc.actionService.ActionRecordDel(ctx, entity.CaptchaActionPassword, ctx.ClientIP())
 	}
+	if resp.TwoFactor != nil {
+		handler.HandleResponse(ctx, nil, resp)
+		return
+	}
 	if resp.Status == constant.UserSuspended {
 		handler.HandleResponse(ctx, errors.Forbidden(reason.UserSuspended),
 			&schema.ForbiddenResp{Type: schema.ForbiddenReasonTypeUserSuspended})
@@ -171,6 +179,120 @@ func (uc *UserController) UserEmailLogin(ctx *gin.Context) {
 	handler.HandleResponse(ctx, nil, resp)
 }
 
+// UserTwoFactorLogin godoc
+// @Summary finish the login with the second factor
+// @Description finish the login with the passcode of the authenticator app, a recovery code or the security key,
+// @Description when the email login returns the two factor challenge
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorLoginReq true "second factor"
+// @Success 200 {object} handler.RespBody{data=schema.UserLoginResp}
+// @Router /answer/api/v1/user/login/2fa [post]
+func (uc *UserController) UserTwoFactorLogin(ctx *gin.Context) {
+	req := &schema.TwoFactorLoginReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := uc.userService.TwoFactorLogin(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if resp.Status == constant.UserSuspended {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.UserSuspended),
+			&schema.ForbiddenResp{Type: schema.ForbiddenReasonTypeUserSuspended})
+		return
+	}
+	uc.setVisitCookies(ctx, resp.VisitToken, true)
+	handler.HandleResponse(ctx, nil, resp)
+}
+
+// GetUserTwoFactorChallenge godoc
+// @Summary get the login waiting for the second factor
+// @Description get the second factors to finish the login with, when the external login redirects with the challenge token
+// @Tags User
+// @Produce json
+// @Param challenge_token query string true "challenge token"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorChallengeResp}
+// @Router /answer/api/v1/user/login/2fa [get]
+func (uc *UserController) GetUserTwoFactorChallenge(ctx *gin.Context) {
+	req := &schema.GetTwoFactorChallengeReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := uc.twoFactorService.GetLoginChallenge(ctx, req.ChallengeToken)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UserTwoFactorEnrollSetup godoc
+// @Summary set up the authenticator app required to login
+// @Description set up the authenticator app of the user whose role requires a second factor to login
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorEnrollSetupReq true "challenge"
+// @Success 200 {object} handler.RespBody{data=schema.TOTPSetupResp}
+// @Router /answer/api/v1/user/login/2fa/totp/setup [post]
+func (uc *UserController) UserTwoFactorEnrollSetup(ctx *gin.Context) {
+	req := &schema.TwoFactorEnrollSetupReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := uc.twoFactorService.SetupTOTPForLogin(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UserTwoFactorEnrollLogin godoc
+// @Summary enable the authenticator app required to login and finish the login
+// @Description enable the authenticator app with its first passcode, the recovery codes are returned only once
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body schema.TwoFactorEnrollLoginReq true "passcode"
+// @Success 200 {object} handler.RespBody{data=schema.TwoFactorEnrollLoginResp}
+// @Router /answer/api/v1/user/login/2fa/totp [post]
+func (uc *UserController) UserTwoFactorEnrollLogin(ctx *gin.Context) {
+	req := &schema.TwoFactorEnrollLoginReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := uc.userService.TwoFactorEnrollLogin(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	if resp.Status == constant.UserSuspended {
+		handler.HandleResponse(ctx, errors.Forbidden(reason.UserSuspended),
+			&schema.ForbiddenResp{Type: schema.ForbiddenReasonTypeUserSuspended})
+		return
+	}
+	uc.setVisitCookies(ctx, resp.VisitToken, true)
+	handler.HandleResponse(ctx, nil, resp)
+}
+
+// UserReauth godoc
+// @Summary confirm the identity before a sensitive action
+// @Description confirm the identity with the second factor, or with the password if the user has no second factor.
+// @Description Changing the email or the password and the other sensitive actions are allowed for a while after it.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.UserReauthReq true "identity"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/reauth [post]
+func (uc *UserController) UserReauth(ctx *gin.Context) {
+	req := &schema.UserReauthReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.AccessToken = middleware.ExtractToken(ctx)
+	err := uc.userService.Reauth(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
 // RetrievePassWord godoc
 // @Summary RetrievePassWord
 // @Description RetrievePassWord
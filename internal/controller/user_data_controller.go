@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/user_data"
+	"github.com/gin-gonic/gin"
+)
+
+// UserDataController user data controller
+type UserDataController struct {
+	userDataService *user_data.UserDataService
+}
+
+// NewUserDataController new controller
+func NewUserDataController(userDataService *user_data.UserDataService) *UserDataController {
+	return &UserDataController{userDataService: userDataService}
+}
+
+// GetUserDataRequests get the data export and erasure requests of the login user
+// @Summary get the data export and erasure requests of the login user
+// @Description get the recent data export and account erasure requests of the login user, the latest first
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.UserDataRequestResp}
+// @Router /answer/api/v1/user/data/requests [get]
+func (uc *UserDataController) GetUserDataRequests(ctx *gin.Context) {
+	req := &schema.GetUserDataRequestsReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userDataService.GetUserDataRequests(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RequestUserDataExport request to export the personal data of the login user
+// @Summary request to export the personal data of the login user
+// @Description the archive of the profile, posts, votes, collections, notifications, AI conversations and uploaded files is prepared in background, the download link is sent by email when it is ready
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=schema.UserDataRequestResp}
+// @Router /answer/api/v1/user/data/export [post]
+func (uc *UserDataController) RequestUserDataExport(ctx *gin.Context) {
+	req := &schema.RequestUserDataExportReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userDataService.RequestUserDataExport(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// DownloadUserDataExport download the data export
+// @Summary download the data export
+// @Description download the data export archive by the code in the email
+// @Tags User
+// @Produce application/zip
+// @Param code query string true "download code"
+// @Success 200 {file} file
+// @Router /answer/api/v1/user/data/export/download [get]
+func (uc *UserDataController) DownloadUserDataExport(ctx *gin.Context) {
+	req := &schema.DownloadUserDataExportReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	filePath, fileName, err := uc.userDataService.GetUserDataExportFile(ctx, req)
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
+	ctx.FileAttachment(filePath, fileName)
+}
+
+// RequestUserErasure request to delete the account of the login user
+// @Summary request to delete the account of the login user
+// @Description the account is deleted after the cooling-off period unless the request is cancelled, the content is anonymized or deleted by the mode
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RequestUserErasureReq true "erasure request"
+// @Success 200 {object} handler.RespBody{data=schema.UserDataRequestResp}
+// @Router /answer/api/v1/user/data/erasure [post]
+func (uc *UserDataController) RequestUserErasure(ctx *gin.Context) {
+	req := &schema.RequestUserErasureReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	resp, err := uc.userDataService.RequestUserErasure(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// CancelUserErasure cancel the pending account deletion of the login user
+// @Summary cancel the pending account deletion of the login user
+// @Description cancel the account deletion during the cooling-off period
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/api/v1/user/data/erasure [delete]
+func (uc *UserDataController) CancelUserErasure(ctx *gin.Context) {
+	req := &schema.CancelUserErasureReq{}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := uc.userDataService.CancelUserErasure(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -57,7 +57,7 @@ func (pc *UserPluginController) GetUserPluginList(ctx *gin.Context) {
 	resp := make([]*schema.GetUserPluginListResp, 0)
 	_ = plugin.CallUserConfig(func(base plugin.UserConfig) error {
 		info := base.Info()
-		if plugin.StatusManager.IsEnabled(info.SlugName) {
+		if plugin.StatusManager.IsEnabledFor(ctx, info.SlugName) {
 			resp = append(resp, &schema.GetUserPluginListResp{
 				Name:     info.Name.Translate(ctx),
 				SlugName: info.SlugName,
@@ -130,7 +130,7 @@ func (pc *UserPluginController) UpdatePluginUserConfig(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
-	if !plugin.StatusManager.IsEnabled(req.PluginSlugName) {
+	if !plugin.StatusManager.IsEnabledFor(ctx, req.PluginSlugName) {
 		handler.HandleResponse(ctx, errors.New(http.StatusBadRequest, reason.RequestFormatError), nil)
 		return
 	}
@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/middleware"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/user_session"
+	"github.com/gin-gonic/gin"
+)
+
+// UserSessionController the login sessions of the login user
+type UserSessionController struct {
+	userSessionService *user_session.UserSessionService
+}
+
+// NewUserSessionController new controller
+func NewUserSessionController(userSessionService *user_session.UserSessionService) *UserSessionController {
+	return &UserSessionController{userSessionService: userSessionService}
+}
+
+// GetUserSessions get the sessions
+// @Summary get the sessions
+// @Description get the devices the user is signed in on, the latest active one first
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody{data=[]schema.UserSessionResp}
+// @Router /answer/api/v1/user/sessions [get]
+func (uc *UserSessionController) GetUserSessions(ctx *gin.Context) {
+	req := &schema.GetUserSessionsReq{
+		UserID:      middleware.GetLoginUserIDFromContext(ctx),
+		AccessToken: middleware.ExtractToken(ctx),
+	}
+	resp, err := uc.userSessionService.GetUserSessions(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RemoveUserSession sign out the session
+// @Summary sign out the session
+// @Description sign out the session on another device, or the current one
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body schema.RemoveUserSessionReq true "session"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/session [delete]
+func (uc *UserSessionController) RemoveUserSession(ctx *gin.Context) {
+	req := &schema.RemoveUserSessionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.UserID = middleware.GetLoginUserIDFromContext(ctx)
+	req.AccessToken = middleware.ExtractToken(ctx)
+	err := uc.userSessionService.RemoveUserSession(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveOtherUserSessions sign out the other sessions
+// @Summary sign out the other sessions
+// @Description sign out all the sessions but the current one
+// @Tags User
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} handler.RespBody
+// @Router /answer/api/v1/user/sessions [delete]
+func (uc *UserSessionController) RemoveOtherUserSessions(ctx *gin.Context) {
+	req := &schema.RemoveOtherUserSessionsReq{
+		UserID:      middleware.GetLoginUserIDFromContext(ctx),
+		AccessToken: middleware.ExtractToken(ctx),
+	}
+	err := uc.userSessionService.RemoveOtherUserSessions(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
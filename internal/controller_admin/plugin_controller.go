@@ -20,8 +20,6 @@
 package controller_admin
 
 import (
-	"encoding/json"
-
 	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/plugin_common"
@@ -53,7 +51,7 @@ func (pc *PluginController) GetAllPluginStatus(ctx *gin.Context) {
 		info := base.Info()
 		resp = append(resp, &schema.GetAllPluginStatusResp{
 			SlugName: info.SlugName,
-			Enabled:  plugin.StatusManager.IsEnabled(info.SlugName),
+			Enabled:  plugin.StatusManager.IsEnabledFor(ctx, info.SlugName),
 		})
 		return nil
 	})
@@ -79,7 +77,7 @@ func (pc *PluginController) GetPluginList(ctx *gin.Context) {
 
 	pluginConfigMapping := make(map[string]bool)
 	_ = plugin.CallConfig(func(fn plugin.Config) error {
-		if len(fn.ConfigFields()) > 0 {
+		if fields, err := plugin.GetConfigFields(handler.GetTenantID(ctx), fn); err == nil && len(fields) > 0 {
 			pluginConfigMapping[fn.Info().SlugName] = true
 		}
 		return nil
@@ -93,7 +91,7 @@ func (pc *PluginController) GetPluginList(ctx *gin.Context) {
 			SlugName:    info.SlugName,
 			Description: info.Description.Translate(ctx),
 			Version:     info.Version,
-			Enabled:     plugin.StatusManager.IsEnabled(info.SlugName),
+			Enabled:     plugin.StatusManager.IsEnabledFor(ctx, info.SlugName),
 			HaveConfig:  pluginConfigMapping[info.SlugName],
 			Link:        info.Link,
 		})
@@ -148,8 +146,7 @@ func (pc *PluginController) UpdatePluginStatus(ctx *gin.Context) {
 		return
 	}
 
-	plugin.StatusManager.Enable(req.PluginSlugName, req.Enabled)
-	err := pc.pluginCommonService.UpdatePluginStatus(ctx)
+	err := pc.pluginCommonService.UpdatePluginStatus(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
 
@@ -181,13 +178,21 @@ func (pc *PluginController) GetPluginConfig(ctx *gin.Context) {
 		return nil
 	})
 
-	_ = plugin.CallConfig(func(fn plugin.Config) error {
+	err := plugin.CallConfig(func(fn plugin.Config) error {
 		if fn.Info().SlugName != req.PluginSlugName {
 			return nil
 		}
-		resp.SetConfigFields(ctx, fn.ConfigFields())
+		fields, err := pc.pluginCommonService.GetPluginConfigFields(ctx, fn)
+		if err != nil {
+			return err
+		}
+		resp.SetConfigFields(ctx, fields)
 		return nil
 	})
+	if err != nil {
+		handler.HandleResponse(ctx, err, nil)
+		return
+	}
 	handler.HandleResponse(ctx, nil, resp)
 }
 
@@ -207,18 +212,6 @@ func (pc *PluginController) UpdatePluginConfig(ctx *gin.Context) {
 		return
 	}
 
-	configFields, _ := json.Marshal(req.ConfigFields)
-	err := plugin.CallConfig(func(fn plugin.Config) error {
-		if fn.Info().SlugName == req.PluginSlugName {
-			return fn.ConfigReceiver(configFields)
-		}
-		return nil
-	})
-	if err != nil {
-		handler.HandleResponse(ctx, err, nil)
-		return
-	}
-
-	err = pc.pluginCommonService.UpdatePluginConfig(ctx, req)
+	err := pc.pluginCommonService.UpdatePluginConfig(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
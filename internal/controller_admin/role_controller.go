@@ -23,17 +23,19 @@ import (
 	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/schema"
 	service "github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/role_admin"
 	"github.com/gin-gonic/gin"
 )
 
 // RoleController role controller
 type RoleController struct {
-	roleService *service.RoleService
+	roleService      *service.RoleService
+	roleAdminService *role_admin.RoleAdminService
 }
 
 // NewRoleController new controller
-func NewRoleController(roleService *service.RoleService) *RoleController {
-	return &RoleController{roleService: roleService}
+func NewRoleController(roleService *service.RoleService, roleAdminService *role_admin.RoleAdminService) *RoleController {
+	return &RoleController{roleService: roleService, roleAdminService: roleAdminService}
 }
 
 // GetRoleList get role list
@@ -52,3 +54,110 @@ func (rc *RoleController) GetRoleList(ctx *gin.Context) {
 	resp, err := rc.roleService.GetRoleList(ctx)
 	handler.HandleResponse(ctx, err, resp)
 }
+
+// GetRole get role
+// @Summary get role
+// @Description get the role with its powers and the tags they are scoped to
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param id query int true "role id"
+// @Success 200 {object} handler.RespBody{data=schema.GetRoleDetailResp}
+// @Router /answer/admin/api/role [get]
+func (rc *RoleController) GetRole(ctx *gin.Context) {
+	req := &schema.GetRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := rc.roleAdminService.GetRole(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddRole add role
+// @Summary add role
+// @Description add the custom role, its powers are set by the role powers
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddRoleReq true "role"
+// @Success 200 {object} handler.RespBody{data=schema.AddRoleResp}
+// @Router /answer/admin/api/role [post]
+func (rc *RoleController) AddRole(ctx *gin.Context) {
+	req := &schema.AddRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := rc.roleAdminService.AddRole(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateRole update role
+// @Summary update role
+// @Description update the name, the description and the tags of the custom role
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateRoleReq true "role"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/role [put]
+func (rc *RoleController) UpdateRole(ctx *gin.Context) {
+	req := &schema.UpdateRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := rc.roleAdminService.UpdateRole(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// DeleteRole delete role
+// @Summary delete role
+// @Description delete the custom role which is not assigned to any user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.DeleteRoleReq true "role"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/role [delete]
+func (rc *RoleController) DeleteRole(ctx *gin.Context) {
+	req := &schema.DeleteRoleReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := rc.roleAdminService.DeleteRole(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetPowerList get power list
+// @Summary get power list
+// @Description get the powers that can be granted to the custom roles
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.GetPowerResp}
+// @Router /answer/admin/api/powers [get]
+func (rc *RoleController) GetPowerList(ctx *gin.Context) {
+	resp, err := rc.roleAdminService.GetPowerList(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateRolePowers update role powers
+// @Summary update role powers
+// @Description replace the powers of the custom role
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateRolePowersReq true "powers"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/role/powers [put]
+func (rc *RoleController) UpdateRolePowers(ctx *gin.Context) {
+	req := &schema.UpdateRolePowersReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := rc.roleAdminService.UpdateRolePowers(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
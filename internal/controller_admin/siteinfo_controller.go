@@ -27,19 +27,25 @@ import (
 	"github.com/apache/answer/internal/base/middleware"
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/siteinfo"
+	"github.com/apache/answer/internal/service/two_factor"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/log"
 )
 
 // SiteInfoController site info controller
 type SiteInfoController struct {
-	siteInfoService *siteinfo.SiteInfoService
+	siteInfoService  *siteinfo.SiteInfoService
+	twoFactorService *two_factor.TwoFactorService
 }
 
 // NewSiteInfoController new site info controller
-func NewSiteInfoController(siteInfoService *siteinfo.SiteInfoService) *SiteInfoController {
+func NewSiteInfoController(
+	siteInfoService *siteinfo.SiteInfoService,
+	twoFactorService *two_factor.TwoFactorService,
+) *SiteInfoController {
 	return &SiteInfoController{
-		siteInfoService: siteInfoService,
+		siteInfoService:  siteInfoService,
+		twoFactorService: twoFactorService,
 	}
 }
 
@@ -448,6 +454,13 @@ func (sc *SiteInfoController) UpdateSiteSecurity(ctx *gin.Context) {
 	if handler.BindAndCheck(ctx, req) {
 		return
 	}
+	if req.RequireStaffTwoFactor {
+		err := sc.twoFactorService.CheckCanRequireStaff(ctx, middleware.GetLoginUserIDFromContext(ctx))
+		if err != nil {
+			handler.HandleResponse(ctx, err, nil)
+			return
+		}
+	}
 	err := sc.siteInfoService.SaveSiteSecurity(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
@@ -687,3 +700,35 @@ func (sc *SiteInfoController) UpdateMCPConfig(ctx *gin.Context) {
 	err := sc.siteInfoService.SaveSiteMCP(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
+
+// GetSearchConfig get search configuration
+// @Summary get search configuration
+// @Description get search configuration
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=schema.SiteSearchResp}
+// @Router /answer/admin/api/search-config [get]
+func (sc *SiteInfoController) GetSearchConfig(ctx *gin.Context) {
+	resp, err := sc.siteInfoService.GetSiteSearch(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateSearchConfig update search configuration
+// @Summary update search configuration
+// @Description update search configuration
+// @Security ApiKeyAuth
+// @Tags admin
+// @Param data body schema.SiteSearchReq true "search config"
+// @Produce json
+// @Success 200 {object} handler.RespBody{}
+// @Router /answer/admin/api/search-config [put]
+func (sc *SiteInfoController) UpdateSearchConfig(ctx *gin.Context) {
+	req := &schema.SiteSearchReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+
+	err := sc.siteInfoService.SaveSiteSearch(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package controller_admin
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/sso"
+	"github.com/gin-gonic/gin"
+)
+
+// SSOProviderController single sign-on provider controller
+type SSOProviderController struct {
+	ssoService *sso.SSOService
+}
+
+// NewSSOProviderController new controller
+func NewSSOProviderController(ssoService *sso.SSOService) *SSOProviderController {
+	return &SSOProviderController{ssoService: ssoService}
+}
+
+// GetSSOProviders get the sso providers
+// @Summary get the sso providers
+// @Description get all the OpenID Connect and SAML providers, the client secret is not returned
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handler.RespBody{data=[]schema.SSOProviderResp}
+// @Router /answer/admin/api/sso/providers [get]
+func (sc *SSOProviderController) GetSSOProviders(ctx *gin.Context) {
+	resp, err := sc.ssoService.GetSSOProviders(ctx)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddSSOProvider add the sso provider
+// @Summary add the sso provider
+// @Description add the OpenID Connect or SAML provider the users can log in with
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddSSOProviderReq true "sso provider"
+// @Success 200 {object} handler.RespBody{data=schema.AddSSOProviderResp}
+// @Router /answer/admin/api/sso/provider [post]
+func (sc *SSOProviderController) AddSSOProvider(ctx *gin.Context) {
+	req := &schema.AddSSOProviderReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := sc.ssoService.AddSSOProvider(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateSSOProvider update the sso provider
+// @Summary update the sso provider
+// @Description update the sso provider, the client secret is kept if it is empty
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateSSOProviderReq true "sso provider"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/sso/provider [put]
+func (sc *SSOProviderController) UpdateSSOProvider(ctx *gin.Context) {
+	req := &schema.UpdateSSOProviderReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.ssoService.UpdateSSOProvider(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// DeleteSSOProvider delete the sso provider
+// @Summary delete the sso provider
+// @Description delete the sso provider, the users bound to it can not log in with it anymore
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.DeleteSSOProviderReq true "sso provider"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/sso/provider [delete]
+func (sc *SSOProviderController) DeleteSSOProvider(ctx *gin.Context) {
+	req := &schema.DeleteSSOProviderReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := sc.ssoService.DeleteSSOProvider(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package controller_admin
+
+import (
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantController tenant controller
+type TenantController struct {
+	tenantService *tenant.TenantService
+}
+
+// NewTenantController new controller
+func NewTenantController(tenantService *tenant.TenantService) *TenantController {
+	return &TenantController{tenantService: tenantService}
+}
+
+// GetTenantPage get the tenants
+// @Summary get the tenants
+// @Description get the communities hosted on this deployment by page, only on the primary site
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param page query int false "page"
+// @Param page_size query int false "page size"
+// @Success 200 {object} handler.RespBody{data=pager.PageModel{list=[]schema.TenantResp}}
+// @Router /answer/admin/api/tenants [get]
+func (tc *TenantController) GetTenantPage(ctx *gin.Context) {
+	req := &schema.GetTenantPageReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := tc.tenantService.GetTenantPage(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// AddTenant add the tenant
+// @Summary add the tenant
+// @Description add the community served on the host, its database is initialized if it is empty
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AddTenantReq true "tenant"
+// @Success 200 {object} handler.RespBody{data=schema.AddTenantResp}
+// @Router /answer/admin/api/tenant [post]
+func (tc *TenantController) AddTenant(ctx *gin.Context) {
+	req := &schema.AddTenantReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := tc.tenantService.AddTenant(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// UpdateTenant update the tenant
+// @Summary update the tenant
+// @Description update the host, name or status of the tenant, the disabled tenant is not served
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.UpdateTenantReq true "tenant"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/tenant [put]
+func (tc *TenantController) UpdateTenant(ctx *gin.Context) {
+	req := &schema.UpdateTenantReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := tc.tenantService.UpdateTenant(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
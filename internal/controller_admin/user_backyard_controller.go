@@ -25,7 +25,9 @@ import (
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/base/translator"
 	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/two_factor"
 	"github.com/apache/answer/internal/service/user_admin"
+	"github.com/apache/answer/internal/service/user_session"
 	"github.com/apache/answer/plugin"
 	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
@@ -33,12 +35,22 @@ import (
 
 // UserAdminController user controller
 type UserAdminController struct {
-	userService *user_admin.UserAdminService
+	userService        *user_admin.UserAdminService
+	twoFactorService   *two_factor.TwoFactorService
+	userSessionService *user_session.UserSessionService
 }
 
 // NewUserAdminController new controller
-func NewUserAdminController(userService *user_admin.UserAdminService) *UserAdminController {
-	return &UserAdminController{userService: userService}
+func NewUserAdminController(
+	userService *user_admin.UserAdminService,
+	twoFactorService *two_factor.TwoFactorService,
+	userSessionService *user_session.UserSessionService,
+) *UserAdminController {
+	return &UserAdminController{
+		userService:        userService,
+		twoFactorService:   twoFactorService,
+		userSessionService: userSessionService,
+	}
 }
 
 // UpdateUserStatus update user
@@ -262,3 +274,79 @@ func (uc *UserAdminController) DeletePermanently(ctx *gin.Context) {
 	err := uc.userService.DeletePermanently(ctx, req)
 	handler.HandleResponse(ctx, err, nil)
 }
+
+// ResetUserTwoFactor reset the second factors of the user
+// @Summary reset the second factors of the user
+// @Description remove the authenticator app, the security keys and the recovery codes of the user who lost them
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.ResetUserTwoFactorReq true "user"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/2fa [delete]
+func (uc *UserAdminController) ResetUserTwoFactor(ctx *gin.Context) {
+	req := &schema.ResetUserTwoFactorReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	req.LoginUserID = middleware.GetLoginUserIDFromContext(ctx)
+	err := uc.twoFactorService.ResetUserTwoFactor(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// GetUserSessions get the sessions of the user
+// @Summary get the sessions of the user
+// @Description get the devices the user is signed in on, the latest active one first
+// @Security ApiKeyAuth
+// @Tags admin
+// @Produce json
+// @Param user_id query string true "user id"
+// @Success 200 {object} handler.RespBody{data=[]schema.UserSessionResp}
+// @Router /answer/admin/api/user/sessions [get]
+func (uc *UserAdminController) GetUserSessions(ctx *gin.Context) {
+	req := &schema.AdminGetUserSessionsReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	resp, err := uc.userSessionService.AdminGetUserSessions(ctx, req)
+	handler.HandleResponse(ctx, err, resp)
+}
+
+// RemoveUserSession sign out the session of the user
+// @Summary sign out the session of the user
+// @Description sign out the session of the user
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AdminRemoveUserSessionReq true "session"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/session [delete]
+func (uc *UserAdminController) RemoveUserSession(ctx *gin.Context) {
+	req := &schema.AdminRemoveUserSessionReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := uc.userSessionService.AdminRemoveUserSession(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
+
+// RemoveUserAllSessions sign out all the sessions of the user
+// @Summary sign out all the sessions of the user
+// @Description sign out the user on all the devices
+// @Security ApiKeyAuth
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param data body schema.AdminRemoveUserAllSessionsReq true "user"
+// @Success 200 {object} handler.RespBody
+// @Router /answer/admin/api/user/sessions [delete]
+func (uc *UserAdminController) RemoveUserAllSessions(ctx *gin.Context) {
+	req := &schema.AdminRemoveUserAllSessionsReq{}
+	if handler.BindAndCheck(ctx, req) {
+		return
+	}
+	err := uc.userSessionService.AdminRemoveUserAllSessions(ctx, req)
+	handler.HandleResponse(ctx, err, nil)
+}
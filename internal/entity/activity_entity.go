@@ -29,6 +29,7 @@ const (
 // Activity activity
 type Activity struct {
 	ID               string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID         int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt        time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt        time.Time `xorm:"updated TIMESTAMP updated_at"`
 	CancelledAt      time.Time `xorm:"TIMESTAMP cancelled_at"`
@@ -24,6 +24,7 @@ import "time"
 // AIConversation AI
 type AIConversation struct {
 	ID             int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID       int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt      time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
 	UpdatedAt      time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
 	ConversationID string    `xorm:"not null unique VARCHAR(255) conversation_id"`
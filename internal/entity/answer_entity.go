@@ -30,17 +30,20 @@ const (
 	AnswerStatusAvailable = 1
 	AnswerStatusDeleted   = 10
 	AnswerStatusPending   = 11
+	AnswerStatusScheduled = 12
 )
 
 var AdminAnswerSearchStatus = map[string]int{
 	"available": AnswerStatusAvailable,
 	"deleted":   AnswerStatusDeleted,
 	"pending":   AnswerStatusPending,
+	"scheduled": AnswerStatusScheduled,
 }
 
 // Answer answer
 type Answer struct {
 	ID             string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID       int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt      time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
 	UpdatedAt      time.Time `xorm:"updated_at TIMESTAMP"`
 	QuestionID     string    `xorm:"not null default 0 BIGINT(20) question_id"`
@@ -53,6 +56,8 @@ type Answer struct {
 	CommentCount   int       `xorm:"not null default 0 INT(11) comment_count"`
 	VoteCount      int       `xorm:"not null default 0 INT(11) vote_count"`
 	RevisionID     string    `xorm:"not null default 0 BIGINT(20) revision_id"`
+	PublishAt      time.Time `xorm:"INDEX publish_at TIMESTAMP"`
+	SpaceID        int       `xorm:"not null default 0 INT(11) INDEX space_id"`
 }
 
 type AnswerSearch struct {
@@ -62,6 +67,7 @@ type AnswerSearch struct {
 	Order          string `json:"order_by"`                   // default or updated
 	Page           int    `json:"page" form:"page"`           // Query number of pages
 	PageSize       int    `json:"page_size" form:"page_size"` // Search page size
+	SpaceIDs       []int  `json:"-"`                          // only the answers in these spaces if set
 }
 
 type PersonalAnswerPageQueryCond struct {
@@ -70,6 +76,7 @@ type PersonalAnswerPageQueryCond struct {
 	UserID      string
 	Order       string
 	ShowPending bool
+	SpaceIDs    []int
 }
 
 // TableName answer table name
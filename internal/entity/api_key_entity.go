@@ -26,6 +26,7 @@ import (
 // APIKey entity
 type APIKey struct {
 	ID          int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID    int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt   time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
 	UpdatedAt   time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
 	LastUsedAt  time.Time `xorm:"not null default CURRENT_TIMESTAMP TIMESTAMP last_used_at"`
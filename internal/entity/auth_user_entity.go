@@ -28,3 +28,11 @@ type UserCacheInfo struct {
 	ExternalID  string `json:"external_id"`
 	VisitToken  string `json:"visit_token"`
 }
+
+// UserSessionInfo the device and the activity of the login session of the access token
+type UserSessionInfo struct {
+	CreatedAt  int64  `json:"created_at"`
+	LastSeenAt int64  `json:"last_seen_at"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+}
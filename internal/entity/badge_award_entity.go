@@ -31,6 +31,7 @@ const (
 // BadgeAward badge_award
 type BadgeAward struct {
 	ID             string    `xorm:"not null pk BIGINT(20) id"`
+	TenantID       int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt      time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
 	UpdatedAt      time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
 	UserID         string    `xorm:"not null index BIGINT(20) user_id"`
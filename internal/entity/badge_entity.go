@@ -43,6 +43,7 @@ const (
 // Badge badge
 type Badge struct {
 	ID           string     `xorm:"not null pk BIGINT(20) id"`
+	TenantID     int        `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt    time.Time  `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
 	UpdatedAt    time.Time  `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
 	Name         string     `xorm:"not null default '' VARCHAR(256) name"`
@@ -24,6 +24,7 @@ import "time"
 // CollectionGroup collection group
 type CollectionGroup struct {
 	ID           string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID     int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt    time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
 	UpdatedAt    time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
 	UserID       string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
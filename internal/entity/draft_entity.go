@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	// DraftTypeNewQuestion the draft of a question being asked, the object id is always 0
+	DraftTypeNewQuestion = "new_question"
+	// DraftTypeNewAnswer the draft of an answer being written, the object id is the question id
+	DraftTypeNewAnswer = "new_answer"
+	// DraftTypeEditQuestion the draft of a question being edited, the object id is the question id
+	DraftTypeEditQuestion = "edit_question"
+	// DraftTypeEditAnswer the draft of an answer being edited, the object id is the answer id
+	DraftTypeEditAnswer = "edit_answer"
+)
+
+// Draft the unsubmitted content of a post being written or edited by user, one draft for each type and object
+type Draft struct {
+	ID        int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated not null default CURRENT_TIMESTAMP INDEX TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 UNIQUE(draft_key) BIGINT(20) user_id"`
+	DraftType string    `xorm:"not null default '' UNIQUE(draft_key) VARCHAR(20) draft_type"`
+	ObjectID  string    `xorm:"not null default 0 UNIQUE(draft_key) BIGINT(20) object_id"`
+	Title     string    `xorm:"not null default '' VARCHAR(150) title"`
+	Content   string    `xorm:"not null MEDIUMTEXT content"`
+	Tags      string    `xorm:"TEXT tags"`
+}
+
+// TableName draft table name
+func (d *Draft) TableName() string {
+	return "draft"
+}
@@ -29,6 +29,7 @@ const (
 // FileRecord file record
 type FileRecord struct {
 	ID        int       `xorm:"not null pk autoincr INT(10) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt time.Time `xorm:"not null default CURRENT_TIMESTAMP created TIMESTAMP created_at"`
 	UpdatedAt time.Time `xorm:"not null default CURRENT_TIMESTAMP updated TIMESTAMP updated_at"`
 	UserID    string    `xorm:"not null default 0 BIGINT(20) user_id"`
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	ImportObjectTypeUser     = "user"
+	ImportObjectTypeQuestion = "question"
+	ImportObjectTypeAnswer   = "answer"
+	ImportObjectTypeComment  = "comment"
+)
+
+// ImportMapping maps the id of the object in the source system to the id of the imported object,
+// so that an import can be run again without duplicating the content
+type ImportMapping struct {
+	ID         int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID   int       `xorm:"not null default 0 INT(11) UNIQUE(source_object) tenant_id"`
+	CreatedAt  time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	Source     string    `xorm:"not null default '' VARCHAR(100) UNIQUE(source_object) source"`
+	ObjectType string    `xorm:"not null default '' VARCHAR(20) UNIQUE(source_object) object_type"`
+	ExternalID string    `xorm:"not null default '' VARCHAR(191) UNIQUE(source_object) external_id"`
+	ObjectID   string    `xorm:"not null default 0 BIGINT(20) object_id"`
+}
+
+// TableName import mapping table name
+func (ImportMapping) TableName() string {
+	return "import_mapping"
+}
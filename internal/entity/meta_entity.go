@@ -32,6 +32,7 @@ const (
 // Meta meta
 type Meta struct {
 	ID        int       `xorm:"not null pk autoincr INT(10) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt time.Time `xorm:"not null default CURRENT_TIMESTAMP created TIMESTAMP created_at"`
 	UpdatedAt time.Time `xorm:"not null default CURRENT_TIMESTAMP updated TIMESTAMP updated_at"`
 	ObjectID  string    `xorm:"not null default 0 INDEX BIGINT(20) object_id"`
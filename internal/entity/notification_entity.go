@@ -24,6 +24,7 @@ import "time"
 // Notification notification
 type Notification struct {
 	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt time.Time `xorm:"TIMESTAMP updated_at"`
 	UserID    string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
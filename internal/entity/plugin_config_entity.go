@@ -22,7 +22,8 @@ package entity
 // PluginConfig plugin config
 type PluginConfig struct {
 	ID             int    `xorm:"not null pk autoincr INT(11) id"`
-	PluginSlugName string `xorm:"unique VARCHAR(128) plugin_slug_name"`
+	TenantID       int    `xorm:"not null default 0 INT(11) UNIQUE(tenant_slug) tenant_id"`
+	PluginSlugName string `xorm:"UNIQUE(tenant_slug) VARCHAR(128) plugin_slug_name"`
 	Value          string `xorm:"TEXT value"`
 }
 
@@ -21,6 +21,7 @@ package entity
 
 type PluginKVStorage struct {
 	ID             int    `xorm:"not null pk autoincr INT(11) id"`
+	TenantID       int    `xorm:"not null default 0 INT(11) UNIQUE(uk_psg) tenant_id"`
 	PluginSlugName string `xorm:"not null VARCHAR(128) UNIQUE(uk_psg) plugin_slug_name"`
 	Group          string `xorm:"not null VARCHAR(128) UNIQUE(uk_psg) 'group'"`
 	Key            string `xorm:"not null VARCHAR(128) UNIQUE(uk_psg) 'key'"`
@@ -22,6 +22,7 @@ package entity
 // PluginUserConfig plugin config
 type PluginUserConfig struct {
 	ID             int    `xorm:"not null pk autoincr INT(11) id"`
+	TenantID       int    `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	UserID         string `xorm:"not null default 0 BIGINT(20) UNIQUE(uk_up) user_id"`
 	PluginSlugName string `xorm:"VARCHAR(128) UNIQUE(uk_up) plugin_slug_name"`
 	Value          string `xorm:"TEXT value"`
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	// QuestionBountyStatusActive the bounty is open and the question is featured
+	QuestionBountyStatusActive = 1
+	// QuestionBountyStatusAwarded the bounty has been awarded to an answer
+	QuestionBountyStatusAwarded = 2
+	// QuestionBountyStatusExpired the bounty ended without an eligible answer
+	QuestionBountyStatusExpired = 3
+)
+
+// QuestionBounty the reputation offered by a user to attract answers to the question
+type QuestionBounty struct {
+	ID            string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID      int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt     time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt     time.Time `xorm:"updated TIMESTAMP updated_at"`
+	QuestionID    string    `xorm:"not null default 0 BIGINT(20) INDEX question_id"`
+	UserID        string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
+	Amount        int       `xorm:"not null default 0 INT(11) amount"`
+	Status        int       `xorm:"not null default 1 INT(11) INDEX status"`
+	ExpiredAt     time.Time `xorm:"TIMESTAMP expired_at"`
+	AnswerID      string    `xorm:"not null default 0 BIGINT(20) answer_id"`
+	AwardedUserID string    `xorm:"not null default 0 BIGINT(20) awarded_user_id"`
+	AwardedAmount int       `xorm:"not null default 0 INT(11) awarded_amount"`
+	AwardedAt     time.Time `xorm:"TIMESTAMP awarded_at"`
+}
+
+// TableName question bounty table name
+func (QuestionBounty) TableName() string {
+	return "question_bounty"
+}
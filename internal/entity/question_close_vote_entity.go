@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	// QuestionCloseVoteTypeClose the vote to close the open question
+	QuestionCloseVoteTypeClose = 1
+	// QuestionCloseVoteTypeReopen the vote to reopen the closed question
+	QuestionCloseVoteTypeReopen = 2
+)
+
+const (
+	// QuestionCloseVoteStatusActive the vote is counted towards closing or reopening the question
+	QuestionCloseVoteStatusActive = 1
+	// QuestionCloseVoteStatusCompleted the question has been closed or reopened
+	QuestionCloseVoteStatusCompleted = 2
+	// QuestionCloseVoteStatusExpired the vote aged away before the question was closed or reopened
+	QuestionCloseVoteStatusExpired = 3
+)
+
+// QuestionCloseVote the vote of a user to close or reopen the question
+type QuestionCloseVote struct {
+	ID                  int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID            int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt           time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt           time.Time `xorm:"updated TIMESTAMP updated_at"`
+	QuestionID          string    `xorm:"not null default 0 BIGINT(20) INDEX question_id"`
+	UserID              string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	VoteType            int       `xorm:"not null default 1 INT(11) vote_type"`
+	CloseType           int       `xorm:"not null default 0 INT(11) close_type"`
+	CloseMsg            string    `xorm:"not null default '' VARCHAR(500) close_msg"`
+	DuplicateQuestionID string    `xorm:"not null default 0 BIGINT(20) duplicate_question_id"`
+	Status              int       `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName question close vote table name
+func (QuestionCloseVote) TableName() string {
+	return "question_close_vote"
+}
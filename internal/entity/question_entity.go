@@ -28,6 +28,7 @@ const (
 	QuestionStatusClosed    = 2
 	QuestionStatusDeleted   = 10
 	QuestionStatusPending   = 11
+	QuestionStatusScheduled = 12
 	QuestionUnPin           = 1
 	QuestionPin             = 2
 	QuestionShow            = 1
@@ -39,6 +40,7 @@ var AdminQuestionSearchStatus = map[string]int{
 	"closed":    QuestionStatusClosed,
 	"deleted":   QuestionStatusDeleted,
 	"pending":   QuestionStatusPending,
+	"scheduled": QuestionStatusScheduled,
 }
 
 var AdminQuestionSearchStatusIntToString = map[int]string{
@@ -46,11 +48,13 @@ var AdminQuestionSearchStatusIntToString = map[int]string{
 	QuestionStatusClosed:    "closed",
 	QuestionStatusDeleted:   "deleted",
 	QuestionStatusPending:   "pending",
+	QuestionStatusScheduled: "scheduled",
 }
 
 // Question question
 type Question struct {
 	ID               string    `xorm:"not null pk BIGINT(20) id"`
+	TenantID         int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt        time.Time `xorm:"not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
 	UpdatedAt        time.Time `xorm:"updated_at TIMESTAMP"`
 	UserID           string    `xorm:"not null default 0 BIGINT(20) INDEX user_id"`
@@ -74,6 +78,11 @@ type Question struct {
 	PostUpdateTime   time.Time `xorm:"post_update_time TIMESTAMP"`
 	RevisionID       string    `xorm:"not null default 0 BIGINT(20) revision_id"`
 	LinkedCount      int       `xorm:"not null default 0 INT(11) linked_count"`
+	CloseVoteCount   int       `xorm:"not null default 0 INT(11) close_vote_count"`
+	ReopenVoteCount  int       `xorm:"not null default 0 INT(11) reopen_vote_count"`
+	BountyAmount     int       `xorm:"not null default 0 INT(11) bounty_amount"`
+	PublishAt        time.Time `xorm:"INDEX publish_at TIMESTAMP"`
+	SpaceID          int       `xorm:"not null default 0 INT(11) INDEX space_id"`
 }
 
 // TableName question table name
@@ -28,14 +28,23 @@ const (
 	QuestionLinkStatusDeleted   = 2
 )
 
+const (
+	// QuestionLinkTypeReference the question or answer content references another question
+	QuestionLinkTypeReference = 1
+	// QuestionLinkTypeDuplicate the question is closed as a duplicate of another question
+	QuestionLinkTypeDuplicate = 2
+)
+
 type QuestionLink struct {
 	ID             string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID       int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt      time.Time `xorm:"not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
 	UpdatedAt      time.Time `xorm:"updated_at TIMESTAMP"`
 	FromQuestionID string    `xorm:"not null default 0 BIGINT(20) index from_question_id"`
 	FromAnswerID   string    `xorm:"BIGINT(20) from_answer_id"`
 	ToQuestionID   string    `xorm:"not null default 0 BIGINT(20) index to_question_id"`
 	ToAnswerID     string    `xorm:"BIGINT(20) to_answer_id"`
+	LinkType       int       `xorm:"not null default 1 INT(11) link_type"`
 	Status         int       `xorm:"not null default 1 INT(11) status"`
 }
 
@@ -39,6 +39,7 @@ var (
 // Report report
 type Report struct {
 	ID             string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID       int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt      time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt      time.Time `xorm:"updated TIMESTAMP updated_at"`
 	UserID         string    `xorm:"not null BIGINT(20) user_id"`
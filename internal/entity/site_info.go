@@ -24,6 +24,7 @@ import "time"
 // SiteInfo site information setting
 type SiteInfo struct {
 	ID        string    `xorm:"not null pk autoincr INT(11) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
 	Type      string    `xorm:"not null VARCHAR(64) type"`
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	// PublicSpaceID the space id of the public content, which is visible to everyone
+	PublicSpaceID = 0
+
+	SpaceStatusAvailable = 1
+	SpaceStatusDeleted   = 10
+
+	// SpaceRoleOwner the owner manages the members and settings of the space
+	SpaceRoleOwner = 1
+	// SpaceRoleMember the member can read and post in the space
+	SpaceRoleMember = 2
+	// SpaceRoleViewer the viewer can only read the contents of the space
+	SpaceRoleViewer = 3
+)
+
+var SpaceRoleMapping = map[string]int{
+	"owner":  SpaceRoleOwner,
+	"member": SpaceRoleMember,
+	"viewer": SpaceRoleViewer,
+}
+
+var SpaceRoleIntToString = map[int]string{
+	SpaceRoleOwner:  "owner",
+	SpaceRoleMember: "member",
+	SpaceRoleViewer: "viewer",
+}
+
+// Space the private area of the site, its questions, answers and tags are only visible to its members
+type Space struct {
+	ID          int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID    int       `xorm:"not null default 0 INT(11) UNIQUE(tenant_slug) tenant_id"`
+	CreatedAt   time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt   time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	SlugName    string    `xorm:"not null default '' UNIQUE(tenant_slug) VARCHAR(35) slug_name"`
+	DisplayName string    `xorm:"not null default '' VARCHAR(35) display_name"`
+	Description string    `xorm:"not null default '' VARCHAR(500) description"`
+	UserID      string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	Status      int       `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName space table name
+func (Space) TableName() string {
+	return "space"
+}
+
+// SpaceMember the member of the space and its role
+type SpaceMember struct {
+	ID        int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	SpaceID   int       `xorm:"not null default 0 UNIQUE(space_user) INT(11) space_id"`
+	UserID    string    `xorm:"not null default 0 UNIQUE(space_user) INDEX BIGINT(20) user_id"`
+	Role      int       `xorm:"not null default 2 INT(11) role"`
+}
+
+// TableName space member table name
+func (SpaceMember) TableName() string {
+	return "space_member"
+}
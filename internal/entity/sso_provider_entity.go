@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	SSOProtocolOIDC = "oidc"
+	SSOProtocolSAML = "saml"
+
+	// SSOExternalLoginProviderPrefix the prefix of the provider in the user external login,
+	// so that the single sign-on never collides with the connector plugins
+	SSOExternalLoginProviderPrefix = "sso_"
+)
+
+// SSOProvider the identity provider of the single sign-on configured by the admin
+type SSOProvider struct {
+	ID        int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) UNIQUE(tenant_slug) tenant_id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	SlugName  string    `xorm:"not null default '' UNIQUE(tenant_slug) VARCHAR(100) slug_name"`
+	Name      string    `xorm:"not null default '' VARCHAR(100) name"`
+	Protocol  string    `xorm:"not null default '' VARCHAR(20) protocol"`
+	Enabled   bool      `xorm:"not null default false BOOL enabled"`
+	// Config the json of the protocol settings, OIDC or SAML
+	Config string `xorm:"not null TEXT config"`
+	// GroupRoleMapping the json of the roles granted to the groups of the identity provider
+	GroupRoleMapping string `xorm:"not null TEXT group_role_mapping"`
+}
+
+// TableName sso provider table name
+func (SSOProvider) TableName() string {
+	return "sso_provider"
+}
+
+// ExternalLoginProvider the provider of the user external login
+func (p *SSOProvider) ExternalLoginProvider() string {
+	return SSOExternalLoginProviderPrefix + p.SlugName
+}
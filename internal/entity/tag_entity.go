@@ -34,11 +34,13 @@ var TagStatusDisplayMapping = map[int]string{
 // Tag tag
 type Tag struct {
 	ID              string    `xorm:"not null pk comment('tag_id') BIGINT(20) id"`
+	TenantID        int       `xorm:"not null default 0 INT(11) UNIQUE(tenant_slug) tenant_id"`
 	CreatedAt       time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt       time.Time `xorm:"updated TIMESTAMP updated_at"`
 	MainTagID       int64     `xorm:"not null default 0 BIGINT(20) main_tag_id"`
 	MainTagSlugName string    `xorm:"not null default '' VARCHAR(35) main_tag_slug_name"`
-	SlugName        string    `xorm:"not null default '' unique VARCHAR(35) slug_name"`
+	ParentTagID     int64     `xorm:"not null default 0 BIGINT(20) INDEX parent_tag_id"`
+	SlugName        string    `xorm:"not null default '' UNIQUE(tenant_slug) VARCHAR(35) slug_name"`
 	DisplayName     string    `xorm:"not null default '' VARCHAR(35) display_name"`
 	OriginalText    string    `xorm:"not null MEDIUMTEXT original_text"`
 	ParsedText      string    `xorm:"not null MEDIUMTEXT parsed_text"`
@@ -49,6 +51,7 @@ type Tag struct {
 	Reserved        bool      `xorm:"not null default false BOOL reserved"`
 	RevisionID      string    `xorm:"not null default 0 BIGINT(20) revision_id"`
 	UserID          string    `xorm:"not null default 0 BIGINT(20) user_id"`
+	SpaceID         int       `xorm:"not null default 0 INT(11) INDEX space_id"`
 }
 
 // TableName tag table name
@@ -0,0 +1,36 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+// TagOwner the owner or expert of the tag, who is notified of the new questions and can edit the tag wiki
+type TagOwner struct {
+	ID        int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	TagID     string    `xorm:"not null default 0 BIGINT(20) UNIQUE(tag_user) tag_id"`
+	UserID    string    `xorm:"not null default 0 BIGINT(20) UNIQUE(tag_user) INDEX user_id"`
+}
+
+// TableName tag owner table name
+func (TagOwner) TableName() string {
+	return "tag_owner"
+}
@@ -30,6 +30,7 @@ const (
 // TagRel tag relation
 type TagRel struct {
 	ID        int64     `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
 	ObjectID  string    `xorm:"not null INDEX UNIQUE(s) BIGINT(20) object_id"`
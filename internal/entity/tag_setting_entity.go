@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+// TagSetting the settings of the tag for the questions using it
+type TagSetting struct {
+	ID               int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID         int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt        time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt        time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	TagID            string    `xorm:"not null default 0 BIGINT(20) UNIQUE tag_id"`
+	QuestionTemplate string    `xorm:"not null MEDIUMTEXT question_template"`
+	// CompanionTags the slug names in json array, the question must also have one of them
+	CompanionTags string `xorm:"not null TEXT companion_tags"`
+}
+
+// TableName tag setting table name
+func (TagSetting) TableName() string {
+	return "tag_setting"
+}
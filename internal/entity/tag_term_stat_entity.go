@@ -0,0 +1,36 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+// TagTermStat the count of the questions which contain the term and are tagged with the tag,
+// the row with tag id 0 is the count of the questions containing the term,
+// the row with empty term and tag id 0 is the count of all the trained questions.
+type TagTermStat struct {
+	ID       int64  `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID int    `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	Term     string `xorm:"not null default '' VARCHAR(64) UNIQUE(term_tag) term"`
+	TagID    string `xorm:"not null default 0 BIGINT(20) UNIQUE(term_tag) tag_id"`
+	Count    int    `xorm:"not null default 0 INT(11) count"`
+}
+
+// TableName tag term stat table name
+func (TagTermStat) TableName() string {
+	return "tag_term_stat"
+}
@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	// DefaultTenantID the tenant id of the primary site, the rows of the primary site are kept with it
+	DefaultTenantID = 0
+
+	TenantStatusAvailable = 1
+	TenantStatusDisabled  = 2
+)
+
+// Tenant the community hosted on the same deployment, it is resolved from the request host
+// and its data is kept in the shared database with its tenant id
+type Tenant struct {
+	ID        int       `xorm:"not null pk autoincr INT(11) id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	Host      string    `xorm:"not null default '' unique VARCHAR(255) host"`
+	Name      string    `xorm:"not null default '' VARCHAR(100) name"`
+	Status    int       `xorm:"not null default 1 INT(11) status"`
+}
+
+// TableName tenant table name
+func (Tenant) TableName() string {
+	return "tenant"
+}
+
+// TenantConfig the value of the config overridden by the tenant, the config of the primary site is used if it is absent
+type TenantConfig struct {
+	ID       int    `xorm:"not null pk autoincr INT(11) id"`
+	TenantID int    `xorm:"not null default 0 INT(11) UNIQUE(tenant_key) tenant_id"`
+	Key      string `xorm:"not null default '' UNIQUE(tenant_key) VARCHAR(128) key"`
+	Value    string `xorm:"TEXT value"`
+}
+
+// TableName tenant config table name
+func (TenantConfig) TableName() string {
+	return "tenant_config"
+}
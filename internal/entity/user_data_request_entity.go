@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+const (
+	// UserDataRequestTypeExport the user requests to download all personal data
+	UserDataRequestTypeExport = "export"
+	// UserDataRequestTypeErasure the user requests to erase the account after the cooling-off period
+	UserDataRequestTypeErasure = "erasure"
+)
+
+const (
+	UserDataRequestStatusPending    = 1
+	UserDataRequestStatusProcessing = 2
+	UserDataRequestStatusCompleted  = 3
+	UserDataRequestStatusFailed     = 4
+	UserDataRequestStatusCancelled  = 5
+	UserDataRequestStatusExpired    = 6
+)
+
+const (
+	// UserErasureModeAnonymize keep the content created by the user but remove the personal data
+	UserErasureModeAnonymize = "anonymize"
+	// UserErasureModeDelete delete the content created by the user as well
+	UserErasureModeDelete = "delete"
+)
+
+// UserDataRequest the data export or account erasure requested by the user, processed in background when scheduled
+type UserDataRequest struct {
+	ID           int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID     int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt    time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt    time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	UserID       string    `xorm:"not null default 0 INDEX BIGINT(20) user_id"`
+	RequestType  string    `xorm:"not null default '' VARCHAR(20) request_type"`
+	Status       int       `xorm:"not null default 1 INDEX TINYINT(4) status"`
+	ErasureMode  string    `xorm:"not null default '' VARCHAR(20) erasure_mode"`
+	ScheduledAt  time.Time `xorm:"not null default CURRENT_TIMESTAMP TIMESTAMP scheduled_at"`
+	CompletedAt  time.Time `xorm:"TIMESTAMP completed_at"`
+	FileName     string    `xorm:"not null default '' VARCHAR(255) file_name"`
+	DownloadCode string    `xorm:"not null default '' INDEX VARCHAR(64) download_code"`
+}
+
+// TableName user data request table name
+func (UserDataRequest) TableName() string {
+	return "user_data_request"
+}
@@ -42,13 +42,14 @@ var PermanentSuspensionTime = time.Date(2099, 12, 31, 23, 59, 59, 0, time.UTC)
 // User user
 type User struct {
 	ID             string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID       int       `xorm:"not null default 0 INT(11) UNIQUE(tenant_username) tenant_id"`
 	CreatedAt      time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt      time.Time `xorm:"updated TIMESTAMP updated_at"`
 	SuspendedAt    time.Time `xorm:"TIMESTAMP suspended_at"`
 	SuspendedUntil time.Time `xorm:"DATETIME suspended_until"`
 	DeletedAt      time.Time `xorm:"TIMESTAMP deleted_at"`
 	LastLoginDate  time.Time `xorm:"TIMESTAMP last_login_date"`
-	Username       string    `xorm:"not null default '' VARCHAR(50) UNIQUE username"`
+	Username       string    `xorm:"not null default '' VARCHAR(50) UNIQUE(tenant_username) username"`
 	Pass           string    `xorm:"not null default '' VARCHAR(255) pass"`
 	EMail          string    `xorm:"not null VARCHAR(100) e_mail"`
 	MailStatus     int       `xorm:"not null default 2 TINYINT(4) mail_status"`
@@ -24,6 +24,7 @@ import "time"
 // UserExternalLogin user external login
 type UserExternalLogin struct {
 	ID         int64     `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID   int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt  time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt  time.Time `xorm:"updated TIMESTAMP updated_at"`
 	UserID     string    `xorm:"not null default 0 BIGINT(20) user_id"`
@@ -24,6 +24,7 @@ import "time"
 // UserNotificationConfig user notification config
 type UserNotificationConfig struct {
 	ID        string    `xorm:"not null pk autoincr BIGINT(20) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
 	UserID    string    `xorm:"not null default 0 INDEX UNIQUE(uk_us) BIGINT(20) INDEX user_id"`
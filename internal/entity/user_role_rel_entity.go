@@ -24,6 +24,7 @@ import "time"
 // UserRoleRel role
 type UserRoleRel struct {
 	ID        int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
 	CreatedAt time.Time `xorm:"created TIMESTAMP created_at"`
 	UpdatedAt time.Time `xorm:"updated TIMESTAMP updated_at"`
 	UserID    string    `xorm:"not null default 0 BIGINT(20) user_id"`
@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package entity
+
+import "time"
+
+// UserTwoFactor the authenticator app and the recovery codes of the user
+type UserTwoFactor struct {
+	ID          int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID    int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt   time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt   time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	UserID      string    `xorm:"not null default 0 unique BIGINT(20) user_id"`
+	TOTPSecret  string    `xorm:"not null default '' VARCHAR(64) totp_secret"`
+	TOTPEnabled bool      `xorm:"not null default false BOOL totp_enabled"`
+	// TOTPLastStep the time step of the last accepted passcode, so that a passcode is used only once
+	TOTPLastStep int64 `xorm:"not null default 0 BIGINT(20) totp_last_step"`
+	// RecoveryCodes the json of the sha256 hashes of the unused recovery codes
+	RecoveryCodes string `xorm:"not null TEXT recovery_codes"`
+}
+
+// TableName user two factor table name
+func (UserTwoFactor) TableName() string {
+	return "user_two_factor"
+}
+
+// UserWebAuthnCredential the security key or passkey registered by the user
+type UserWebAuthnCredential struct {
+	ID        int       `xorm:"not null pk autoincr INT(11) id"`
+	TenantID  int       `xorm:"not null default 0 INT(11) INDEX tenant_id"`
+	CreatedAt time.Time `xorm:"created not null default CURRENT_TIMESTAMP TIMESTAMP created_at"`
+	UpdatedAt time.Time `xorm:"updated not null default CURRENT_TIMESTAMP TIMESTAMP updated_at"`
+	UserID    string    `xorm:"not null default 0 index BIGINT(20) user_id"`
+	Name      string    `xorm:"not null default '' VARCHAR(100) name"`
+	// CredentialID the base64url credential id
+	CredentialID string `xorm:"not null TEXT credential_id"`
+	// PublicKey the base64url COSE encoded public key
+	PublicKey  string    `xorm:"not null TEXT public_key"`
+	SignCount  int64     `xorm:"not null default 0 BIGINT(20) sign_count"`
+	LastUsedAt time.Time `xorm:"TIMESTAMP last_used_at"`
+}
+
+// TableName user webauthn credential table name
+func (UserWebAuthnCredential) TableName() string {
+	return "user_webauthn_credential"
+}
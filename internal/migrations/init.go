@@ -28,6 +28,7 @@ import (
 	"github.com/apache/answer/internal/base/constant"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/repo/revision"
 	"github.com/apache/answer/internal/repo/unique"
 	"github.com/apache/answer/internal/schema"
@@ -39,15 +40,17 @@ import (
 )
 
 type Mentor struct {
-	ctx      context.Context
-	engine   *xorm.Engine
-	userData *InitNeedUserInputData
-	err      error
-	Done     bool
+	ctx         context.Context
+	engine      *xorm.Engine
+	userData    *InitNeedUserInputData
+	tenantID    int
+	adminUserID string
+	err         error
+	Done        bool
 }
 
 func NewMentor(ctx context.Context, engine *xorm.Engine, data *InitNeedUserInputData) *Mentor {
-	return &Mentor{ctx: ctx, engine: engine, userData: data}
+	return &Mentor{ctx: ctx, engine: engine, userData: data, adminUserID: "1"}
 }
 
 type InitNeedUserInputData struct {
@@ -89,6 +92,34 @@ func (m *Mentor) InitDB() error {
 	m.do("init default badges", m.initDefaultBadges)
 	m.do("init default ai config", m.initSiteInfoAI)
 	m.do("init default MCP config", m.initSiteInfoMCP)
+	m.do("init search full text index", m.initSearchFullTextIndex)
+	return m.err
+}
+
+// InitTenant init the data of the tenant in the database shared with the primary site,
+// the tables, the config and the roles of the primary site are used by the tenant.
+func (m *Mentor) InitTenant(tenantID int) error {
+	m.tenantID = tenantID
+	m.ctx = handler.WithTenantID(m.ctx, tenantID)
+	m.do("init admin user", m.initAdminUser)
+	m.do("init default privileges config", m.initDefaultRankPrivileges)
+	m.do("init admin user role rel", m.initAdminUserRoleRel)
+	m.do("init site info interface", m.initSiteInfoInterface)
+	m.do("init site info users settings", m.initSiteInfoUsersSettings)
+	m.do("init site info general config", m.initSiteInfoGeneralData)
+	m.do("init site info login config", m.initSiteInfoLoginConfig)
+	m.do("init site info theme config", m.initSiteInfoThemeConfig)
+	m.do("init site info seo config", m.initSiteInfoSEOConfig)
+	m.do("init site info user config", m.initSiteInfoUsersConfig)
+	m.do("init site info privilege rank", m.initSiteInfoPrivilegeRank)
+	m.do("init site info write", m.initSiteInfoAdvanced)
+	m.do("init site info write", m.initSiteInfoQuestions)
+	m.do("init site info write", m.initSiteInfoTags)
+	m.do("init site info security", m.initSiteInfoSecurityConfig)
+	m.do("init default content", m.initDefaultContent)
+	m.do("init default badges", m.initDefaultBadges)
+	m.do("init default ai config", m.initSiteInfoAI)
+	m.do("init default MCP config", m.initSiteInfoMCP)
 	return m.err
 }
 
@@ -119,8 +150,8 @@ func (m *Mentor) initVersionTable() {
 
 func (m *Mentor) initAdminUser() {
 	generateFromPassword, _ := bcrypt.GenerateFromPassword([]byte(m.userData.AdminPassword), bcrypt.DefaultCost)
-	_, m.err = m.engine.Context(m.ctx).Insert(&entity.User{
-		ID:           "1",
+	user := &entity.User{
+		TenantID:     m.tenantID,
 		Username:     m.userData.AdminName,
 		Pass:         string(generateFromPassword),
 		EMail:        m.userData.AdminEmail,
@@ -129,7 +160,13 @@ func (m *Mentor) initAdminUser() {
 		Status:       1,
 		Rank:         1,
 		DisplayName:  m.userData.AdminName,
-	})
+	}
+	// the administrator of the tenant gets the id like the other users
+	if m.tenantID == entity.DefaultTenantID {
+		user.ID = m.adminUserID
+	}
+	_, m.err = m.engine.Context(m.ctx).Insert(user)
+	m.adminUserID = user.ID
 }
 
 func (m *Mentor) initConfig() {
@@ -139,6 +176,17 @@ func (m *Mentor) initConfig() {
 func (m *Mentor) initDefaultRankPrivileges() {
 	chooseOption := schema.DefaultPrivilegeOptions.Choose(schema.PrivilegeLevel2)
 	for _, privilege := range chooseOption.Privileges {
+		if m.tenantID != entity.DefaultTenantID {
+			_, m.err = m.engine.Context(m.ctx).Insert(&entity.TenantConfig{
+				TenantID: m.tenantID,
+				Key:      privilege.Key,
+				Value:    fmt.Sprintf("%d", privilege.Value),
+			})
+			if m.err != nil {
+				return
+			}
+			continue
+		}
 		_, err := m.engine.Context(m.ctx).Update(
 			&entity.Config{Value: fmt.Sprintf("%d", privilege.Value)},
 			&entity.Config{Key: privilege.Key},
@@ -162,7 +210,11 @@ func (m *Mentor) initRolePowerRel() {
 }
 
 func (m *Mentor) initAdminUserRoleRel() {
-	_, m.err = m.engine.Context(m.ctx).Insert(adminUserRoleRel)
+	_, m.err = m.engine.Context(m.ctx).Insert(&entity.UserRoleRel{
+		TenantID: m.tenantID,
+		UserID:   m.adminUserID,
+		RoleID:   adminUserRoleRel.RoleID,
+	})
 }
 
 func (m *Mentor) initSiteInfoInterface() {
@@ -191,9 +243,10 @@ func (m *Mentor) initSiteInfoInterface() {
 	}
 	interfaceDataBytes, _ := json.Marshal(interfaceData)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "interface_settings",
-		Content: string(interfaceDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "interface_settings",
+		Content:  string(interfaceDataBytes),
+		Status:   1,
 	})
 }
 
@@ -204,9 +257,10 @@ func (m *Mentor) initSiteInfoUsersSettings() {
 	}
 	usersSettingsDataBytes, _ := json.Marshal(usersSettings)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "users_settings",
-		Content: string(usersSettingsDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "users_settings",
+		Content:  string(usersSettingsDataBytes),
+		Status:   1,
 	})
 }
 
@@ -218,9 +272,10 @@ func (m *Mentor) initSiteInfoGeneralData() {
 	}
 	generalDataBytes, _ := json.Marshal(generalData)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "general",
-		Content: string(generalDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "general",
+		Content:  string(generalDataBytes),
+		Status:   1,
 	})
 }
 
@@ -233,9 +288,10 @@ func (m *Mentor) initSiteInfoLoginConfig() {
 	}
 	loginConfigDataBytes, _ := json.Marshal(loginConfig)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "login",
-		Content: string(loginConfigDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "login",
+		Content:  string(loginConfigDataBytes),
+		Status:   1,
 	})
 }
 
@@ -247,18 +303,20 @@ func (m *Mentor) initSiteInfoSecurityConfig() {
 	}
 	securityConfigDataBytes, _ := json.Marshal(securityConfig)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "security",
-		Content: string(securityConfigDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "security",
+		Content:  string(securityConfigDataBytes),
+		Status:   1,
 	})
 }
 
 func (m *Mentor) initSiteInfoThemeConfig() {
 	themeConfig := fmt.Sprintf(`{"theme":"default","theme_config":{"default":{"navbar_style":"#0033ff","primary_color":"#0033ff"}},"layout":"%s"}`, constant.ThemeLayoutFullWidth)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "theme",
-		Content: themeConfig,
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "theme",
+		Content:  themeConfig,
+		Status:   1,
 	})
 }
 
@@ -269,9 +327,10 @@ func (m *Mentor) initSiteInfoSEOConfig() {
 	}
 	seoDataBytes, _ := json.Marshal(seoData)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "seo",
-		Content: string(seoDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "seo",
+		Content:  string(seoDataBytes),
+		Status:   1,
 	})
 }
 
@@ -288,9 +347,10 @@ func (m *Mentor) initSiteInfoUsersConfig() {
 	}
 	usersDataBytes, _ := json.Marshal(usersData)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "users",
-		Content: string(usersDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "users",
+		Content:  string(usersDataBytes),
+		Status:   1,
 	})
 }
 
@@ -300,9 +360,10 @@ func (m *Mentor) initSiteInfoPrivilegeRank() {
 	}
 	privilegeRankDataBytes, _ := json.Marshal(privilegeRankData)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "privileges",
-		Content: string(privilegeRankDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "privileges",
+		Content:  string(privilegeRankDataBytes),
+		Status:   1,
 	})
 }
 
@@ -316,9 +377,10 @@ func (m *Mentor) initSiteInfoAdvanced() {
 	}
 	advancedDataBytes, _ := json.Marshal(advancedData)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "advanced",
-		Content: string(advancedDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "advanced",
+		Content:  string(advancedDataBytes),
+		Status:   1,
 	})
 }
 
@@ -330,9 +392,10 @@ func (m *Mentor) initSiteInfoQuestions() {
 	}
 	questionsDataBytes, _ := json.Marshal(questionsData)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "questions",
-		Content: string(questionsDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "questions",
+		Content:  string(questionsDataBytes),
+		Status:   1,
 	})
 }
 
@@ -344,15 +407,16 @@ func (m *Mentor) initSiteInfoTags() {
 	}
 	tagsDataBytes, _ := json.Marshal(tagsData)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    "tags",
-		Content: string(tagsDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     "tags",
+		Content:  string(tagsDataBytes),
+		Status:   1,
 	})
 }
 
 func (m *Mentor) initDefaultContent() {
-	uniqueIDRepo := unique.NewUniqueIDRepo(&data.Data{DB: m.engine})
-	revisionRepo := revision.NewRevisionRepo(&data.Data{DB: m.engine}, uniqueIDRepo)
+	uniqueIDRepo := unique.NewUniqueIDRepo(&data.Data{DB: data.WrapEngine(m.engine)})
+	revisionRepo := revision.NewRevisionRepo(&data.Data{DB: data.WrapEngine(m.engine)}, uniqueIDRepo)
 	now := time.Now()
 
 	tagId, err := uniqueIDRepo.GenUniqueIDStr(m.ctx, entity.Tag{}.TableName())
@@ -387,11 +451,12 @@ func (m *Mentor) initDefaultContent() {
 
 	tag := &entity.Tag{
 		ID:            tagId,
+		TenantID:      m.tenantID,
 		SlugName:      "support",
 		DisplayName:   "support",
 		OriginalText:  "For general support questions.",
 		ParsedText:    "<p>For general support questions.</p>",
-		UserID:        "1",
+		UserID:        m.adminUserID,
 		QuestionCount: 2,
 		Status:        entity.TagStatusAvailable,
 		RevisionID:    "0",
@@ -399,9 +464,10 @@ func (m *Mentor) initDefaultContent() {
 
 	q1 := &entity.Question{
 		ID:               q1Id,
+		TenantID:         m.tenantID,
 		CreatedAt:        now,
-		UserID:           "1",
-		LastEditUserID:   "1",
+		UserID:           m.adminUserID,
+		LastEditUserID:   m.adminUserID,
 		Title:            "What is a tag?",
 		OriginalText:     "When asking a question, we need to choose tags. What are tags and why should I use them?",
 		ParsedText:       "<p>When asking a question, we need to choose tags. What are tags and why should I use them?</p>",
@@ -417,9 +483,10 @@ func (m *Mentor) initDefaultContent() {
 
 	a1 := &entity.Answer{
 		ID:             a1Id,
+		TenantID:       m.tenantID,
 		CreatedAt:      now,
 		QuestionID:     q1Id,
-		UserID:         "1",
+		UserID:         m.adminUserID,
 		LastEditUserID: "0",
 		OriginalText:   "Tags help to organize content and make searching easier. It helps your question get more attention from people interested in that tag. Tags also send notifications. If you are interested in some topic, follow that tag to get updates.",
 		ParsedText:     "<p>Tags help to organize content and make searching easier. It helps your question get more attention from people interested in that tag. Tags also send notifications. If you are interested in some topic, follow that tag to get updates.</p>",
@@ -429,9 +496,10 @@ func (m *Mentor) initDefaultContent() {
 
 	q2 := &entity.Question{
 		ID:               q2Id,
+		TenantID:         m.tenantID,
 		CreatedAt:        now,
-		UserID:           "1",
-		LastEditUserID:   "1",
+		UserID:           m.adminUserID,
+		LastEditUserID:   m.adminUserID,
 		Title:            "What is reputation and how do I earn them?",
 		OriginalText:     "I see that each user has reputation points, What is it and how do I earn them?",
 		ParsedText:       "<p>I see that each user has reputation points, What is it and how do I earn them?</p>",
@@ -447,9 +515,10 @@ func (m *Mentor) initDefaultContent() {
 
 	a2 := &entity.Answer{
 		ID:             a2Id,
+		TenantID:       m.tenantID,
 		CreatedAt:      now,
 		QuestionID:     q2Id,
-		UserID:         "1",
+		UserID:         m.adminUserID,
 		LastEditUserID: "0",
 		OriginalText:   "Your reputation points show how much the community values your knowledge. You earn points when someone find your question or answer helpful. You also get points when the person who asked the question thinks you did a good job and accepts your answer.",
 		ParsedText:     "<p>Your reputation points show how much the community values your knowledge. You earn points when someone find your question or answer helpful. You also get points when the person who asked the question thinks you did a good job and accepts your answer.</p>",
@@ -531,6 +600,7 @@ func (m *Mentor) initDefaultContent() {
 	}
 
 	_, m.err = m.engine.Context(m.ctx).Insert(entity.TagRel{
+		TenantID: m.tenantID,
 		ObjectID: q1.ID,
 		TagID:    tag.ID,
 		Status:   entity.TagRelStatusAvailable,
@@ -583,6 +653,7 @@ func (m *Mentor) initDefaultContent() {
 	}
 
 	_, m.err = m.engine.Context(m.ctx).Insert(entity.TagRel{
+		TenantID: m.tenantID,
 		ObjectID: q2.ID,
 		TagID:    tag.ID,
 		Status:   entity.TagRelStatusAvailable,
@@ -593,18 +664,23 @@ func (m *Mentor) initDefaultContent() {
 }
 
 func (m *Mentor) initDefaultBadges() {
-	uniqueIDRepo := unique.NewUniqueIDRepo(&data.Data{DB: m.engine})
+	uniqueIDRepo := unique.NewUniqueIDRepo(&data.Data{DB: data.WrapEngine(m.engine)})
 
-	_, m.err = m.engine.Context(m.ctx).Insert(defaultBadgeGroupTable)
-	if m.err != nil {
-		return
+	// the badge groups are shared by the tenants
+	if m.tenantID == entity.DefaultTenantID {
+		_, m.err = m.engine.Context(m.ctx).Insert(defaultBadgeGroupTable)
+		if m.err != nil {
+			return
+		}
 	}
-	for _, badge := range defaultBadgeTable {
+	for _, defaultBadge := range defaultBadgeTable {
+		badge := *defaultBadge
+		badge.TenantID = m.tenantID
 		badge.ID, m.err = uniqueIDRepo.GenUniqueIDStr(m.ctx, new(entity.Badge).TableName())
 		if m.err != nil {
 			return
 		}
-		if _, m.err = m.engine.Context(m.ctx).Insert(badge); m.err != nil {
+		if _, m.err = m.engine.Context(m.ctx).Insert(&badge); m.err != nil {
 			return
 		}
 	}
@@ -619,9 +695,10 @@ func (m *Mentor) initSiteInfoAI() {
 	}
 	writeDataBytes, _ := json.Marshal(content)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    constant.SiteTypeAI,
-		Content: string(writeDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     constant.SiteTypeAI,
+		Content:  string(writeDataBytes),
+		Status:   1,
 	})
 }
 func (m *Mentor) initSiteInfoMCP() {
@@ -630,8 +707,13 @@ func (m *Mentor) initSiteInfoMCP() {
 	}
 	writeDataBytes, _ := json.Marshal(content)
 	_, m.err = m.engine.Context(m.ctx).Insert(&entity.SiteInfo{
-		Type:    constant.SiteTypeMCP,
-		Content: string(writeDataBytes),
-		Status:  1,
+		TenantID: m.tenantID,
+		Type:     constant.SiteTypeMCP,
+		Content:  string(writeDataBytes),
+		Status:   1,
 	})
 }
+
+func (m *Mentor) initSearchFullTextIndex() {
+	m.err = addSearchFullTextIndex(m.ctx, m.engine)
+}
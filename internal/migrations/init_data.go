@@ -79,6 +79,23 @@ var (
 		&entity.APIKey{},
 		&entity.AIConversation{},
 		&entity.AIConversationRecord{},
+		&entity.SavedSearch{},
+		&entity.TagOwner{},
+		&entity.TagSetting{},
+		&entity.TagTermStat{},
+		&entity.QuestionCloseVote{},
+		&entity.QuestionBounty{},
+		&entity.Draft{},
+		&entity.Space{},
+		&entity.SpaceMember{},
+		&entity.Tenant{},
+		&entity.TenantConfig{},
+		&entity.ImportMapping{},
+		&entity.UserDataRequest{},
+		&entity.SSOProvider{},
+		&entity.UserTwoFactor{},
+		&entity.UserWebAuthnCredential{},
+		&entity.RoleTagRel{},
 	}
 
 	roles = []*entity.Role{
@@ -129,6 +146,10 @@ var (
 		{ID: 39, Name: "recover answer", PowerType: permission.AnswerUnDelete, Description: "recover deleted answer"},
 		{ID: 40, Name: "recover question", PowerType: permission.QuestionUnDelete, Description: "recover deleted question"},
 		{ID: 41, Name: "recover tag", PowerType: permission.TagUnDelete, Description: "recover deleted tag"},
+		{ID: 42, Name: "tag use reserved tag", PowerType: permission.TagUseReservedTag, Description: "use the reserved tags"},
+		{ID: 43, Name: "tag merge", PowerType: permission.TagMerge, Description: "merge the tags"},
+		{ID: 44, Name: "question close vote", PowerType: permission.QuestionCloseVote, Description: "vote to close the question"},
+		{ID: 45, Name: "question bounty", PowerType: permission.QuestionBounty, Description: "offer a bounty on the question"},
 	}
 
 	rolePowerRels = []*entity.RolePowerRel{
@@ -354,6 +375,12 @@ var (
 		{ID: 129, Key: "rank.question.undeleted", Value: `-1`},
 		{ID: 130, Key: "rank.tag.undeleted", Value: `-1`},
 		{ID: 131, Key: "ai_config.provider", Value: `[{"default_api_host":"https://api.openai.com","display_name":"OpenAI","name":"openai"},{"default_api_host":"https://generativelanguage.googleapis.com","display_name":"Gemini","name":"gemini"},{"default_api_host":"https://api.anthropic.com","display_name":"Anthropic","name":"anthropic"}]`},
+		{ID: 132, Key: "rank.question.close_vote", Value: `3000`},
+		{ID: 133, Key: "question.close_voted", Value: `0`},
+		{ID: 134, Key: "question.reopen_voted", Value: `0`},
+		{ID: 135, Key: "question.bounty_offered", Value: `0`},
+		{ID: 136, Key: "answer.bounty_awarded", Value: `0`},
+		{ID: 137, Key: "rank.question.bounty", Value: `75`},
 	}
 
 	defaultBadgeGroupTable = []*entity.BadgeGroup{
@@ -110,6 +110,23 @@ var migrations = []Migration{
 	NewMigration("v2.0.1", "change avatar type to text", updateAvatarType, false),
 	NewMigration("v2.0.2", "add reasoning content to ai conversation record", addAIConversationReasoningContent, false),
 	NewMigration("v2.0.3", "add require email verification login setting", addRequireEmailVerification, true),
+	NewMigration("v2.0.4", "add full text index for search", addSearchFullTextIndex, false),
+	NewMigration("v2.0.5", "add saved search", addSavedSearch, false),
+	NewMigration("v2.0.6", "add tag hierarchy, owner and setting", addTagHierarchyAndOwner, false),
+	NewMigration("v2.0.7", "add tag term stat", addTagTermStat, false),
+	NewMigration("v2.0.8", "add question duplicate link and close vote", addQuestionCloseVote, true),
+	NewMigration("v2.0.9", "add question reopen vote and vote tally", addQuestionReopenVote, true),
+	NewMigration("v2.1.0", "add question bounty", addQuestionBounty, true),
+	NewMigration("v2.1.1", "add draft", addDraft, false),
+	NewMigration("v2.1.2", "add post publish time", addPostPublishTime, true),
+	NewMigration("v2.1.3", "add space", addSpace, true),
+	NewMigration("v2.1.4", "add tenant", addTenant, false),
+	NewMigration("v2.1.5", "add import mapping", addImportMapping, false),
+	NewMigration("v2.1.6", "add user data request", addUserDataRequest, false),
+	NewMigration("v2.1.7", "add sso provider", addSSOProvider, false),
+	NewMigration("v2.1.8", "add user two factor", addUserTwoFactor, false),
+	NewMigration("v2.1.9", "add custom role", addCustomRole, false),
+	NewMigration("v2.2.0", "add tenant id", addTenantID, true),
 }
 
 func GetMigrations() []Migration {
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/answer/internal/entity"
+	"xorm.io/xorm"
+)
+
+// Tables returns the beans of all the tables of Answer
+func Tables() []any {
+	return tables
+}
+
+// InitSchema creates all the tables of the latest version in an empty database and records the db version.
+// It is used to restore the exported data, the data exported from an older version is upgraded by Migrate later.
+func InitSchema(ctx context.Context, engine *xorm.Engine, dbVersion int64) error {
+	if dbVersion > ExpectedVersion() {
+		return fmt.Errorf("db version %d is newer than the latest version %d", dbVersion, ExpectedVersion())
+	}
+	if err := engine.Context(ctx).Sync(tables...); err != nil {
+		return fmt.Errorf("sync table failed: %w", err)
+	}
+	if err := addSearchFullTextIndex(ctx, engine); err != nil {
+		return fmt.Errorf("init search full text index failed: %w", err)
+	}
+	if _, err := engine.Context(ctx).Insert(&entity.Version{ID: 1, VersionNumber: dbVersion}); err != nil {
+		return fmt.Errorf("init version table failed: %w", err)
+	}
+	return nil
+}
@@ -30,7 +30,7 @@ import (
 )
 
 func addBadges(ctx context.Context, x *xorm.Engine) (err error) {
-	uniqueIDRepo := unique.NewUniqueIDRepo(&data.Data{DB: x})
+	uniqueIDRepo := unique.NewUniqueIDRepo(&data.Data{DB: data.WrapEngine(x)})
 
 	err = x.Context(ctx).Sync(new(entity.Badge), new(entity.BadgeGroup), new(entity.BadgeAward))
 	if err != nil {
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/segmentfault/pacman/log"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+// addSearchFullTextIndex create the native full text index of question and answer.
+// If the database does not support it, the search will fall back to LIKE, so the error is only logged.
+func addSearchFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	var err error
+	switch x.Dialect().URI().DBType {
+	case schemas.SQLITE:
+		err = createSQLiteFullTextIndex(ctx, x)
+	case schemas.POSTGRES:
+		err = createPostgresFullTextIndex(ctx, x)
+	case schemas.MYSQL:
+		err = createMySQLFullTextIndex(ctx, x)
+	}
+	if err != nil {
+		log.Warnf("create full text index failed, search will use LIKE instead: %v", err)
+	}
+	return nil
+}
+
+// createSQLiteFullTextIndex create the fts5 table and keep it in sync with question and answer by triggers
+func createSQLiteFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	table := entity.SearchFullTextTableName
+	sqls := []string{
+		fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS "%s" USING fts5(title, content, tokenize = 'porter unicode61 remove_diacritics 2')`, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS "%[1]s_question_insert" AFTER INSERT ON "question" BEGIN
+  INSERT INTO "%[1]s" (rowid, title, content) VALUES (new.id, new.title, new.original_text);
+END`, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS "%[1]s_question_update" AFTER UPDATE OF title, original_text ON "question" BEGIN
+  DELETE FROM "%[1]s" WHERE rowid = old.id;
+  INSERT INTO "%[1]s" (rowid, title, content) VALUES (new.id, new.title, new.original_text);
+END`, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS "%[1]s_question_delete" AFTER DELETE ON "question" BEGIN
+  DELETE FROM "%[1]s" WHERE rowid = old.id;
+END`, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS "%[1]s_answer_insert" AFTER INSERT ON "answer" BEGIN
+  INSERT INTO "%[1]s" (rowid, title, content) VALUES (new.id, '', new.original_text);
+END`, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS "%[1]s_answer_update" AFTER UPDATE OF original_text ON "answer" BEGIN
+  DELETE FROM "%[1]s" WHERE rowid = old.id;
+  INSERT INTO "%[1]s" (rowid, title, content) VALUES (new.id, '', new.original_text);
+END`, table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS "%[1]s_answer_delete" AFTER DELETE ON "answer" BEGIN
+  DELETE FROM "%[1]s" WHERE rowid = old.id;
+END`, table),
+		fmt.Sprintf(`DELETE FROM "%s"`, table),
+		fmt.Sprintf(`INSERT INTO "%s" (rowid, title, content) SELECT id, title, original_text FROM "question"`, table),
+		fmt.Sprintf(`INSERT INTO "%s" (rowid, title, content) SELECT id, '', original_text FROM "answer"`, table),
+	}
+	for _, sql := range sqls {
+		if _, err := x.Context(ctx).Exec(sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createPostgresFullTextIndex create the GIN expression index, it must be the same as the expression in search repo
+func createPostgresFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	_, err := x.Context(ctx).Exec(fmt.Sprintf(
+		`CREATE INDEX IF NOT EXISTS "%s" ON "question" USING GIN (to_tsvector('%s', "title" || ' ' || "original_text"))`,
+		entity.SearchFullTextQuestionIndex, entity.SearchTextSearchConfig))
+	if err != nil {
+		return err
+	}
+	_, err = x.Context(ctx).Exec(fmt.Sprintf(
+		`CREATE INDEX IF NOT EXISTS "%s" ON "answer" USING GIN (to_tsvector('%s', "original_text"))`,
+		entity.SearchFullTextAnswerIndex, entity.SearchTextSearchConfig))
+	return err
+}
+
+// createMySQLFullTextIndex create the FULLTEXT index, the columns must be the same as the MATCH columns in search repo
+func createMySQLFullTextIndex(ctx context.Context, x *xorm.Engine) error {
+	indexes := []struct {
+		table, name, columns string
+	}{
+		{table: "question", name: entity.SearchFullTextQuestionIndex, columns: "`title`, `original_text`"},
+		{table: "answer", name: entity.SearchFullTextAnswerIndex, columns: "`original_text`"},
+	}
+	for _, index := range indexes {
+		var count int64
+		_, err := x.Context(ctx).SQL("SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?",
+			index.table, index.name).Get(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		_, err = x.Context(ctx).Exec(fmt.Sprintf("ALTER TABLE `%s` ADD FULLTEXT INDEX `%s` (%s)", index.table, index.name, index.columns))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/permission"
+	"xorm.io/xorm"
+)
+
+func addCustomRole(ctx context.Context, x *xorm.Engine) error {
+	if err := x.Context(ctx).Sync(new(entity.RoleTagRel)); err != nil {
+		return fmt.Errorf("sync role tag rel table failed: %w", err)
+	}
+	// the powers checked by the code but never listed, so that they can be granted to the custom roles
+	powers := []*entity.Power{
+		{ID: 42, Name: "tag use reserved tag", PowerType: permission.TagUseReservedTag, Description: "use the reserved tags"},
+		{ID: 43, Name: "tag merge", PowerType: permission.TagMerge, Description: "merge the tags"},
+		{ID: 44, Name: "question close vote", PowerType: permission.QuestionCloseVote, Description: "vote to close the question"},
+		{ID: 45, Name: "question bounty", PowerType: permission.QuestionBounty, Description: "offer a bounty on the question"},
+	}
+	for _, power := range powers {
+		exist, err := x.Context(ctx).Get(&entity.Power{PowerType: power.PowerType})
+		if err != nil {
+			return fmt.Errorf("get power failed: %w", err)
+		}
+		if exist {
+			continue
+		}
+		if _, err = x.Context(ctx).Insert(power); err != nil {
+			return fmt.Errorf("insert power failed: %w", err)
+		}
+	}
+	return nil
+}
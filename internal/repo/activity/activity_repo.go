@@ -52,7 +52,7 @@ func NewActivityRepo(
 func (ar *activityRepo) GetObjectAllActivity(ctx context.Context, objectID string, showVote bool) (
 	activityList []*entity.Activity, err error) {
 	activityList = make([]*entity.Activity, 0)
-	session := ar.data.DB.Context(ctx).Desc("id")
+	session := ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Desc("id")
 
 	if !showVote {
 		activityTypeNotShown := ar.getAllActivityType(ctx)
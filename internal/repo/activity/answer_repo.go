@@ -29,6 +29,7 @@ import (
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
@@ -67,7 +68,7 @@ func NewAnswerActivityRepo(
 func (ar *AnswerActivityRepo) SaveAcceptAnswerActivity(ctx context.Context, op *schema.AcceptAnswerOperationInfo) (
 	err error) {
 	// save activity
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = ar.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 
 		userInfoMapping, err := ar.acquireUserInfo(session, op.GetUserIDs())
@@ -75,7 +76,7 @@ func (ar *AnswerActivityRepo) SaveAcceptAnswerActivity(ctx context.Context, op *
 			return nil, err
 		}
 
-		err = ar.saveActivitiesAvailable(session, op)
+		err = ar.saveActivitiesAvailable(ctx, session, op)
 		if err != nil {
 			return nil, err
 		}
@@ -114,7 +115,7 @@ func (ar *AnswerActivityRepo) SaveCancelAcceptAnswerActivity(ctx context.Context
 	}
 
 	// save activity
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = ar.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 
 		userInfoMapping, err := ar.acquireUserInfo(session, userIDs)
@@ -161,7 +162,7 @@ func (ar *AnswerActivityRepo) acquireUserInfo(session *xorm.Session, userIDs []s
 // If activity not exist it will be created or else will be updated
 // If this activity is already exist, set activity rank to 0
 // So after this function, the activity rank will be correct for update user rank
-func (ar *AnswerActivityRepo) saveActivitiesAvailable(session *xorm.Session, op *schema.AcceptAnswerOperationInfo) (
+func (ar *AnswerActivityRepo) saveActivitiesAvailable(ctx context.Context, session *xorm.Session, op *schema.AcceptAnswerOperationInfo) (
 	err error) {
 	for _, act := range op.Activities {
 		existsActivity := &entity.Activity{}
@@ -190,6 +191,7 @@ func (ar *AnswerActivityRepo) saveActivitiesAvailable(session *xorm.Session, op
 			}
 		} else {
 			insertActivity := entity.Activity{
+				TenantID:         handler.GetTenantID(ctx),
 				ObjectID:         op.AnswerObjectID,
 				OriginalObjectID: act.OriginalObjectID,
 				UserID:           act.ActivityUserID,
@@ -283,7 +285,7 @@ func (ar *AnswerActivityRepo) getExistActivity(ctx context.Context, op *schema.A
 	var activities []*entity.Activity
 	for _, action := range op.Activities {
 		var t []*entity.Activity
-		err := ar.data.DB.Context(ctx).
+		err := ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).
 			Where(builder.Eq{"user_id": action.ActivityUserID}).
 			And(builder.Eq{"activity_type": action.ActivityType}).
 			And(builder.Eq{"object_id": op.AnswerObjectID}).
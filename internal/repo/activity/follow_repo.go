@@ -30,6 +30,7 @@ import (
 	"xorm.io/builder"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/unique"
@@ -67,7 +68,7 @@ func (ar *FollowRepo) Follow(ctx context.Context, objectID, userID string) error
 		return err
 	}
 
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = ar.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 		var (
 			existsActivity entity.Activity
@@ -75,7 +76,8 @@ func (ar *FollowRepo) Follow(ctx context.Context, objectID, userID string) error
 		)
 		result = nil
 
-		has, err = session.Where(builder.Eq{"activity_type": activityType}).
+		has, err = session.Where(data.TenantCond(ctx)).
+			And(builder.Eq{"activity_type": activityType}).
 			And(builder.Eq{"user_id": userID}).
 			And(builder.Eq{"object_id": objectID}).
 			Get(&existsActivity)
@@ -97,6 +99,7 @@ func (ar *FollowRepo) Follow(ctx context.Context, objectID, userID string) error
 		} else {
 			// update existing activity with new user id and u object id
 			_, err = session.Insert(&entity.Activity{
+				TenantID:         handler.GetTenantID(ctx),
 				UserID:           userID,
 				ObjectID:         objectID,
 				OriginalObjectID: objectID,
@@ -134,7 +137,7 @@ func (ar *FollowRepo) FollowCancel(ctx context.Context, objectID, userID string)
 		return err
 	}
 
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = ar.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 		var (
 			existsActivity entity.Activity
@@ -142,7 +145,8 @@ func (ar *FollowRepo) FollowCancel(ctx context.Context, objectID, userID string)
 		)
 		result = nil
 
-		has, err = session.Where(builder.Eq{"activity_type": activityType}).
+		has, err = session.Where(data.TenantCond(ctx)).
+			And(builder.Eq{"activity_type": activityType}).
 			And(builder.Eq{"user_id": userID}).
 			And(builder.Eq{"object_id": objectID}).
 			Get(&existsActivity)
@@ -168,11 +172,12 @@ func (ar *FollowRepo) FollowCancel(ctx context.Context, objectID, userID string)
 	return err
 }
 
-func (ar *FollowRepo) updateFollows(_ context.Context, session *xorm.Session, objectID string, follows int) error {
+func (ar *FollowRepo) updateFollows(ctx context.Context, session *xorm.Session, objectID string, follows int) error {
 	objectType, err := obj.GetObjectTypeStrByObjectID(objectID)
 	if err != nil {
 		return err
 	}
+	session.Where(data.TenantCond(ctx))
 	switch objectType {
 	case "question":
 		_, err = session.Where("id = ?", objectID).Incr("follow_count", follows).Update(&entity.Question{})
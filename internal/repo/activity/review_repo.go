@@ -28,6 +28,7 @@ import (
 	"xorm.io/builder"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/activity"
@@ -72,6 +73,7 @@ func (ar *ReviewActivityRepo) Review(ctx context.Context, act *schema.PassReview
 		return err
 	}
 	addActivity := &entity.Activity{
+		TenantID:         handler.GetTenantID(ctx),
 		UserID:           act.UserID,
 		TriggerUserID:    converter.StringToInt64(act.TriggerUserID),
 		ObjectID:         act.ObjectID,
@@ -82,11 +84,11 @@ func (ar *ReviewActivityRepo) Review(ctx context.Context, act *schema.PassReview
 		RevisionID:       converter.StringToInt64(act.RevisionID),
 	}
 
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = ar.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 
 		user := &entity.User{}
-		exist, err := session.ID(addActivity.UserID).ForUpdate().Get(user)
+		exist, err := session.ID(addActivity.UserID).Where(data.TenantCond(ctx)).ForUpdate().Get(user)
 		if err != nil {
 			return nil, err
 		}
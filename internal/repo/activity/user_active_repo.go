@@ -26,6 +26,7 @@ import (
 	"xorm.io/builder"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/activity"
@@ -70,6 +71,7 @@ func (ar *UserActiveActivityRepo) UserActive(ctx context.Context, userID string)
 		return err
 	}
 	addActivity := &entity.Activity{
+		TenantID:         handler.GetTenantID(ctx),
 		UserID:           userID,
 		ObjectID:         "0",
 		OriginalObjectID: "0",
@@ -78,11 +80,11 @@ func (ar *UserActiveActivityRepo) UserActive(ctx context.Context, userID string)
 		HasRank:          1,
 	}
 
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = ar.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 
 		user := &entity.User{}
-		exist, err := session.ID(userID).ForUpdate().Get(user)
+		exist, err := session.ID(userID).Where(data.TenantCond(ctx)).ForUpdate().Get(user)
 		if err != nil {
 			return nil, err
 		}
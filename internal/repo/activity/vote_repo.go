@@ -38,6 +38,7 @@ import (
 	"xorm.io/builder"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
@@ -88,7 +89,7 @@ func (vr *VoteRepo) Vote(ctx context.Context, op *schema.VoteOperationInfo) (err
 		userIDs = append(userIDs, activity.ActivityUserID)
 	}
 
-	_, err = vr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = vr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 
 		userInfoMapping, err := vr.acquireUserInfo(session, userIDs)
@@ -101,7 +102,7 @@ func (vr *VoteRepo) Vote(ctx context.Context, op *schema.VoteOperationInfo) (err
 			return nil, err
 		}
 
-		sendInboxNotification, err = vr.saveActivitiesAvailable(session, op)
+		sendInboxNotification, err = vr.saveActivitiesAvailable(ctx, session, op)
 		if err != nil {
 			return nil, err
 		}
@@ -148,7 +149,7 @@ func (vr *VoteRepo) CancelVote(ctx context.Context, op *schema.VoteOperationInfo
 		return nil
 	}
 
-	_, err = vr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = vr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 
 		userInfoMapping, err := vr.acquireUserInfo(session, userIDs)
@@ -190,7 +191,7 @@ func (vr *VoteRepo) GetAndSaveVoteResult(ctx context.Context, objectID, objectTy
 
 func (vr *VoteRepo) ListUserVotes(ctx context.Context, userID string,
 	page int, pageSize int, activityTypes []int) (voteList []*entity.Activity, total int64, err error) {
-	session := vr.data.DB.Context(ctx)
+	session := vr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	cond := builder.
 		And(
 			builder.Eq{"user_id": userID},
@@ -309,7 +310,7 @@ func (vr *VoteRepo) rollbackUserRank(ctx context.Context, session *xorm.Session,
 // If activity not exist it will be created or else will be updated
 // If this activity is already exist, set activity rank to 0
 // So after this function, the activity rank will be correct for update user rank
-func (vr *VoteRepo) saveActivitiesAvailable(session *xorm.Session, op *schema.VoteOperationInfo) (newAct bool, err error) {
+func (vr *VoteRepo) saveActivitiesAvailable(ctx context.Context, session *xorm.Session, op *schema.VoteOperationInfo) (newAct bool, err error) {
 	for _, activity := range op.Activities {
 		existsActivity := &entity.Activity{}
 		exist, err := session.
@@ -338,6 +339,7 @@ func (vr *VoteRepo) saveActivitiesAvailable(session *xorm.Session, op *schema.Vo
 			}
 		} else {
 			insertActivity := entity.Activity{
+				TenantID:         handler.GetTenantID(ctx),
 				ObjectID:         op.ObjectID,
 				OriginalObjectID: op.ObjectID,
 				UserID:           activity.ActivityUserID,
@@ -392,7 +394,7 @@ func (vr *VoteRepo) getExistActivity(ctx context.Context, op *schema.VoteOperati
 	var activities []*entity.Activity
 	for _, action := range op.Activities {
 		t := &entity.Activity{}
-		exist, err := vr.data.DB.Context(ctx).
+		exist, err := vr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).
 			Where(builder.Eq{"user_id": action.ActivityUserID}).
 			And(builder.Eq{"trigger_user_id": action.TriggerUserID}).
 			And(builder.Eq{"activity_type": action.ActivityType}).
@@ -427,7 +429,7 @@ func (vr *VoteRepo) countVoteDown(ctx context.Context, objectID, objectType stri
 func (vr *VoteRepo) countVote(ctx context.Context, objectID, objectType, action string) (count int64, err error) {
 	activity := &entity.Activity{}
 	activityType, _ := vr.activityRepo.GetActivityTypeByObjectType(ctx, objectType, action)
-	count, err = vr.data.DB.Context(ctx).Where(builder.Eq{"object_id": objectID}).
+	count, err = vr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"object_id": objectID}).
 		And(builder.Eq{"activity_type": activityType}).
 		And(builder.Eq{"cancelled": 0}).
 		Count(activity)
@@ -438,7 +440,7 @@ func (vr *VoteRepo) countVote(ctx context.Context, objectID, objectType, action
 }
 
 func (vr *VoteRepo) updateVotes(ctx context.Context, objectID, objectType string, voteCount int) (err error) {
-	session := vr.data.DB.Context(ctx)
+	session := vr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	switch objectType {
 	case constant.QuestionObjectType:
 		_, err = session.ID(objectID).Cols("vote_count").Update(&entity.Question{VoteCount: voteCount})
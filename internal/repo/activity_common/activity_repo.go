@@ -32,6 +32,7 @@ import (
 	"xorm.io/xorm"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/service/config"
 	"github.com/apache/answer/internal/service/unique"
@@ -100,7 +101,8 @@ func (ar *ActivityRepo) GetActivity(ctx context.Context, session *xorm.Session,
 ) (existsActivity *entity.Activity, exist bool, err error) {
 	existsActivity = &entity.Activity{}
 	exist, err = session.
-		Where(builder.Eq{"object_id": objectID}).
+		Where(data.TenantCond(ctx)).
+		And(builder.Eq{"object_id": objectID}).
 		And(builder.Eq{"user_id": userID}).
 		And(builder.Eq{"activity_type": activityType}).
 		Get(existsActivity)
@@ -110,7 +112,7 @@ func (ar *ActivityRepo) GetActivity(ctx context.Context, session *xorm.Session,
 func (ar *ActivityRepo) GetUserActivitiesByActivityType(ctx context.Context, userID string, activityType int) (
 	activityList []*entity.Activity, err error) {
 	activityList = make([]*entity.Activity, 0)
-	err = ar.data.DB.Context(ctx).Where("user_id = ?", userID).
+	err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).
 		And("activity_type = ?", activityType).
 		And("cancelled = 0").
 		Find(&activityList)
@@ -122,7 +124,7 @@ func (ar *ActivityRepo) GetUserActivitiesByActivityType(ctx context.Context, use
 
 func (ar *ActivityRepo) GetUserIDObjectIDActivitySum(ctx context.Context, userID, objectID string) (int, error) {
 	sum := &entity.ActivityRankSum{}
-	_, err := ar.data.DB.Context(ctx).Table(entity.Activity{}.TableName()).
+	_, err := ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table(entity.Activity{}.TableName()).
 		Select("sum(`rank`) as `rank`").
 		Where("user_id =?", userID).
 		And("object_id = ?", objectID).
@@ -137,6 +139,7 @@ func (ar *ActivityRepo) GetUserIDObjectIDActivitySum(ctx context.Context, userID
 
 // AddActivity add activity
 func (ar *ActivityRepo) AddActivity(ctx context.Context, activity *entity.Activity) (err error) {
+	activity.TenantID = handler.GetTenantID(ctx)
 	_, err = ar.data.DB.Context(ctx).Insert(activity)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -148,7 +151,7 @@ func (ar *ActivityRepo) AddActivity(ctx context.Context, activity *entity.Activi
 func (ar *ActivityRepo) GetUsersWhoHasGainedTheMostReputation(
 	ctx context.Context, startTime, endTime time.Time, limit int) (rankStat []*entity.ActivityUserRankStat, err error) {
 	rankStat = make([]*entity.ActivityUserRankStat, 0)
-	session := ar.data.DB.Context(ctx).Select("user_id, SUM(`rank`) AS rank_amount").Table("activity")
+	session := ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Select("user_id, SUM(`rank`) AS rank_amount").Table("activity")
 	session.Where("has_rank = 1 AND cancelled = 0")
 	session.Where("created_at >= ?", startTime)
 	session.Where("created_at <= ?", endTime)
@@ -175,7 +178,7 @@ func (ar *ActivityRepo) GetUsersWhoHasVoteMost(
 		}
 	}
 
-	session := ar.data.DB.Context(ctx).Select("user_id, COUNT(*) AS vote_count").Table("activity")
+	session := ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Select("user_id, COUNT(*) AS vote_count").Table("activity")
 	session.Where("cancelled = 0")
 	session.In("activity_type", actIDs)
 	session.Where("created_at >= ?", startTime)
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/activity_common"
@@ -64,19 +65,19 @@ func (ar *FollowRepo) GetFollowAmount(ctx context.Context, objectID string) (fol
 	switch objectType {
 	case "question":
 		model := &entity.Question{}
-		_, err = ar.data.DB.Context(ctx).Where("id = ?", objectID).Cols("`follow_count`").Get(model)
+		_, err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", objectID).Cols("`follow_count`").Get(model)
 		if err == nil {
 			follows = model.FollowCount
 		}
 	case "user":
 		model := &entity.User{}
-		_, err = ar.data.DB.Context(ctx).Where("id = ?", objectID).Cols("`follow_count`").Get(model)
+		_, err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", objectID).Cols("`follow_count`").Get(model)
 		if err == nil {
 			follows = model.FollowCount
 		}
 	case "tag":
 		model := &entity.Tag{}
-		_, err = ar.data.DB.Context(ctx).Where("id = ?", objectID).Cols("`follow_count`").Get(model)
+		_, err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", objectID).Cols("`follow_count`").Get(model)
 		if err == nil {
 			follows = model.FollowCount
 		}
@@ -103,7 +104,7 @@ func (ar *FollowRepo) GetFollowUserIDs(ctx context.Context, objectID string) (us
 	}
 
 	userIDs = make([]string, 0)
-	session := ar.data.DB.Context(ctx).Select("user_id")
+	session := ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Select("user_id")
 	session.Table(entity.Activity{}.TableName())
 	session.Where("object_id = ?", objectID)
 	session.Where("activity_type = ?", activityType)
@@ -122,7 +123,7 @@ func (ar *FollowRepo) GetFollowIDs(ctx context.Context, userID, objectKey string
 	if err != nil {
 		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
-	session := ar.data.DB.Context(ctx).Select("object_id")
+	session := ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Select("object_id")
 	session.Table(entity.Activity{}.TableName())
 	session.Where("user_id = ? AND activity_type = ?", userID, activityType)
 	session.Where("cancelled = 0")
@@ -146,7 +147,7 @@ func (ar *FollowRepo) IsFollowed(ctx context.Context, userID, objectID string) (
 	}
 
 	at := &entity.Activity{}
-	has, err := ar.data.DB.Context(ctx).Where("user_id = ? AND object_id = ? AND activity_type = ?", userID, objectID, activityType).Get(at)
+	has, err := ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND object_id = ? AND activity_type = ?", userID, objectID, activityType).Get(at)
 	if err != nil {
 		return false, err
 	}
@@ -186,11 +187,12 @@ func (ar *FollowRepo) MigrateFollowers(ctx context.Context, sourceObjectID, targ
 		return err
 	}
 
-	_, err = ar.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = ar.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 		// 1. delete all follows of the source object
 		_, err = session.Table(entity.Activity{}.TableName()).
-			Where(builder.Eq{
+			Where(data.TenantCond(ctx)).
+			And(builder.Eq{
 				"object_id":     sourceObjectID,
 				"activity_type": activityType,
 			}).
@@ -201,7 +203,8 @@ func (ar *FollowRepo) MigrateFollowers(ctx context.Context, sourceObjectID, targ
 
 		// 2. update cancel status to active for target tag if source tag followers is active
 		_, err = session.Table(entity.Activity{}.TableName()).
-			Where(builder.Eq{
+			Where(data.TenantCond(ctx)).
+			And(builder.Eq{
 				"object_id":     targetObjectID,
 				"activity_type": activityType,
 			}).
@@ -217,7 +220,8 @@ func (ar *FollowRepo) MigrateFollowers(ctx context.Context, sourceObjectID, targ
 		// 3. get existing follows of the target object
 		targetFollowers := make([]string, 0)
 		err = session.Table(entity.Activity{}.TableName()).
-			Where(builder.Eq{
+			Where(data.TenantCond(ctx)).
+			And(builder.Eq{
 				"object_id":     targetObjectID,
 				"activity_type": activityType,
 				"cancelled":     entity.ActivityAvailable,
@@ -246,6 +250,7 @@ func (ar *FollowRepo) MigrateFollowers(ctx context.Context, sourceObjectID, targ
 		// Create new activities for the filtered users
 		for _, uid := range newFollowers {
 			activity := &entity.Activity{
+				TenantID:         handler.GetTenantID(ctx),
 				UserID:           uid,
 				ObjectID:         targetObjectID,
 				OriginalObjectID: targetObjectID,
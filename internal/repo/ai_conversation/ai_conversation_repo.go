@@ -23,6 +23,7 @@ import (
 	"context"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/pager"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
@@ -61,6 +62,7 @@ func NewAIConversationRepo(data *data.Data) AIConversationRepo {
 
 // CreateConversation creates a conversation
 func (r *aiConversationRepo) CreateConversation(ctx context.Context, conversation *entity.AIConversation) error {
+	conversation.TenantID = handler.GetTenantID(ctx)
 	_, err := r.data.DB.Context(ctx).Insert(conversation)
 	if err != nil {
 		log.Errorf("create ai conversation failed: %v", err)
@@ -72,7 +74,7 @@ func (r *aiConversationRepo) CreateConversation(ctx context.Context, conversatio
 // GetConversation gets a conversation
 func (r *aiConversationRepo) GetConversation(ctx context.Context, conversationID string) (*entity.AIConversation, bool, error) {
 	conversation := &entity.AIConversation{}
-	exist, err := r.data.DB.Context(ctx).Where(builder.Eq{"conversation_id": conversationID}).Get(conversation)
+	exist, err := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"conversation_id": conversationID}).Get(conversation)
 	if err != nil {
 		log.Errorf("get ai conversation failed: %v", err)
 		return nil, false, err
@@ -82,7 +84,7 @@ func (r *aiConversationRepo) GetConversation(ctx context.Context, conversationID
 
 // UpdateConversation updates a conversation
 func (r *aiConversationRepo) UpdateConversation(ctx context.Context, conversation *entity.AIConversation) error {
-	_, err := r.data.DB.Context(ctx).ID(conversation.ID).Update(conversation)
+	_, err := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(conversation.ID).Update(conversation)
 	if err != nil {
 		log.Errorf("update ai conversation failed: %v", err)
 		return err
@@ -93,7 +95,7 @@ func (r *aiConversationRepo) UpdateConversation(ctx context.Context, conversatio
 // GetConversationsPage get conversations by user ID
 func (r *aiConversationRepo) GetConversationsPage(ctx context.Context, page, pageSize int, cond *entity.AIConversation) (list []*entity.AIConversation, total int64, err error) {
 	list = make([]*entity.AIConversation, 0)
-	total, err = pager.Help(page, pageSize, &list, cond, r.data.DB.Context(ctx).Desc("id"))
+	total, err = pager.Help(page, pageSize, &list, cond, r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Desc("id"))
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -102,6 +104,7 @@ func (r *aiConversationRepo) GetConversationsPage(ctx context.Context, page, pag
 
 // CreateRecord creates a conversation record
 func (r *aiConversationRepo) CreateRecord(ctx context.Context, record *entity.AIConversationRecord) error {
+	record.TenantID = handler.GetTenantID(ctx)
 	_, err := r.data.DB.Context(ctx).Insert(record)
 	if err != nil {
 		log.Errorf("create ai conversation record failed: %v", err)
@@ -113,7 +116,7 @@ func (r *aiConversationRepo) CreateRecord(ctx context.Context, record *entity.AI
 // GetRecordsByConversationID get records by conversation ID
 func (r *aiConversationRepo) GetRecordsByConversationID(ctx context.Context, conversationID string) ([]*entity.AIConversationRecord, error) {
 	records := make([]*entity.AIConversationRecord, 0)
-	err := r.data.DB.Context(ctx).
+	err := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).
 		Where(builder.Eq{"conversation_id": conversationID}).
 		OrderBy("created_at ASC").
 		Find(&records)
@@ -126,7 +129,7 @@ func (r *aiConversationRepo) GetRecordsByConversationID(ctx context.Context, con
 
 // UpdateRecordVote update record vote
 func (r *aiConversationRepo) UpdateRecordVote(ctx context.Context, cond *entity.AIConversationRecord) (err error) {
-	_, err = r.data.DB.Context(ctx).ID(cond.ID).MustCols("helpful", "unhelpful").Update(cond)
+	_, err = r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(cond.ID).MustCols("helpful", "unhelpful").Update(cond)
 	if err != nil {
 		log.Errorf("update ai conversation record vote failed: %v", err)
 		return err
@@ -137,7 +140,7 @@ func (r *aiConversationRepo) UpdateRecordVote(ctx context.Context, cond *entity.
 // GetRecord get record
 func (r *aiConversationRepo) GetRecord(ctx context.Context, recordID int) (*entity.AIConversationRecord, bool, error) {
 	record := &entity.AIConversationRecord{}
-	exist, err := r.data.DB.Context(ctx).ID(recordID).Get(record)
+	exist, err := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(recordID).Get(record)
 	if err != nil {
 		log.Errorf("get ai conversation record failed: %v", err)
 		return nil, false, err
@@ -148,7 +151,7 @@ func (r *aiConversationRepo) GetRecord(ctx context.Context, recordID int) (*enti
 // GetRecordByChatCompletionID gets record by chat completion ID
 func (r *aiConversationRepo) GetRecordByChatCompletionID(ctx context.Context, role, chatCompletionID string) (*entity.AIConversationRecord, bool, error) {
 	record := &entity.AIConversationRecord{}
-	exist, err := r.data.DB.Context(ctx).Where(builder.Eq{"role": role}).
+	exist, err := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"role": role}).
 		Where(builder.Eq{"chat_completion_id": chatCompletionID}).Get(record)
 	if err != nil {
 		log.Errorf("get ai conversation record by chat completion id failed: %v", err)
@@ -160,7 +163,7 @@ func (r *aiConversationRepo) GetRecordByChatCompletionID(ctx context.Context, ro
 // GetConversationsForAdmin gets conversation list for admin
 func (r *aiConversationRepo) GetConversationsForAdmin(ctx context.Context, page, pageSize int, cond *entity.AIConversation) (list []*entity.AIConversation, total int64, err error) {
 	list = make([]*entity.AIConversation, 0)
-	total, err = pager.Help(page, pageSize, &list, cond, r.data.DB.Context(ctx).Desc("id"))
+	total, err = pager.Help(page, pageSize, &list, cond, r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Desc("id"))
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -169,7 +172,7 @@ func (r *aiConversationRepo) GetConversationsForAdmin(ctx context.Context, page,
 
 // GetConversationWithVoteStats gets conversation vote statistics
 func (r *aiConversationRepo) GetConversationWithVoteStats(ctx context.Context, conversationID string) (helpful, unhelpful int64, err error) {
-	res, err := r.data.DB.Context(ctx).SumsInt(&entity.AIConversationRecord{ConversationID: conversationID}, "helpful", "unhelpful")
+	res, err := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).SumsInt(&entity.AIConversationRecord{ConversationID: conversationID}, "helpful", "unhelpful")
 	if err != nil {
 		log.Errorf("get ai conversation vote stats failed: %v", err)
 		return 0, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -183,13 +186,13 @@ func (r *aiConversationRepo) GetConversationWithVoteStats(ctx context.Context, c
 
 // DeleteConversation deletes a conversation and its related records
 func (r *aiConversationRepo) DeleteConversation(ctx context.Context, conversationID string) error {
-	_, err := r.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
-		if _, err := session.Context(ctx).Where("conversation_id = ?", conversationID).Delete(&entity.AIConversationRecord{}); err != nil {
+	_, err := r.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		if _, err := session.Context(ctx).Where(data.TenantCond(ctx)).And("conversation_id = ?", conversationID).Delete(&entity.AIConversationRecord{}); err != nil {
 			log.Errorf("delete ai conversation records failed: %v", err)
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
 
-		if _, err := session.Context(ctx).Where("conversation_id = ?", conversationID).Delete(&entity.AIConversation{}); err != nil {
+		if _, err := session.Context(ctx).Where(data.TenantCond(ctx)).And("conversation_id = ?", conversationID).Delete(&entity.AIConversation{}); err != nil {
 			log.Errorf("delete ai conversation failed: %v", err)
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
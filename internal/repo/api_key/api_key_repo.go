@@ -23,6 +23,7 @@ import (
 	"context"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/apikey"
@@ -42,7 +43,7 @@ func NewAPIKeyRepo(data *data.Data) apikey.APIKeyRepo {
 
 func (ar *apiKeyRepo) GetAPIKeyList(ctx context.Context) (keys []*entity.APIKey, err error) {
 	keys = make([]*entity.APIKey, 0)
-	err = ar.data.DB.Context(ctx).Where("hidden = ?", 0).Find(&keys)
+	err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("hidden = ?", 0).Find(&keys)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -51,7 +52,7 @@ func (ar *apiKeyRepo) GetAPIKeyList(ctx context.Context) (keys []*entity.APIKey,
 
 func (ar *apiKeyRepo) GetAPIKey(ctx context.Context, apiKey string) (key *entity.APIKey, exist bool, err error) {
 	key = &entity.APIKey{}
-	exist, err = ar.data.DB.Context(ctx).Where("access_key = ?", apiKey).Get(key)
+	exist, err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("access_key = ?", apiKey).Get(key)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -59,7 +60,7 @@ func (ar *apiKeyRepo) GetAPIKey(ctx context.Context, apiKey string) (key *entity
 }
 
 func (ar *apiKeyRepo) UpdateAPIKey(ctx context.Context, apiKey entity.APIKey) (err error) {
-	_, err = ar.data.DB.Context(ctx).ID(apiKey.ID).Update(&apiKey)
+	_, err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(apiKey.ID).Update(&apiKey)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -67,6 +68,7 @@ func (ar *apiKeyRepo) UpdateAPIKey(ctx context.Context, apiKey entity.APIKey) (e
 }
 
 func (ar *apiKeyRepo) AddAPIKey(ctx context.Context, apiKey entity.APIKey) (err error) {
+	apiKey.TenantID = handler.GetTenantID(ctx)
 	_, err = ar.data.DB.Context(ctx).Insert(&apiKey)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -75,7 +77,7 @@ func (ar *apiKeyRepo) AddAPIKey(ctx context.Context, apiKey entity.APIKey) (err
 }
 
 func (ar *apiKeyRepo) DeleteAPIKey(ctx context.Context, id int) (err error) {
-	_, err = ar.data.DB.Context(ctx).ID(id).Delete(&entity.APIKey{})
+	_, err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Delete(&entity.APIKey{})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -83,7 +85,7 @@ func (ar *apiKeyRepo) DeleteAPIKey(ctx context.Context, id int) (err error) {
 }
 
 func (ar *apiKeyRepo) DeleteAPIKeysByUserID(ctx context.Context, userID string) (err error) {
-	_, err = ar.data.DB.Context(ctx).Where("user_id = ?", userID).Delete(&entity.APIKey{})
+	_, err = ar.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Delete(&entity.APIKey{})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -103,6 +103,11 @@ func (ar *authRepo) RemoveUserCacheInfo(ctx context.Context, accessToken string)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
+	for _, key := range []string{constant.UserSessionCacheKey, constant.UserRecentAuthCacheKey} {
+		if err := ar.data.Cache.Del(ctx, key+accessToken); err != nil {
+			log.Error(err)
+		}
+	}
 	return nil
 }
 
@@ -233,7 +238,97 @@ func (ar *authRepo) RemoveUserTokens(ctx context.Context, userID string, remainT
 	if err := ar.RemoveUserStatus(ctx, userID); err != nil {
 		log.Error(err)
 	}
+	// the remaining token is still signed in, so it is kept in the mapping
+	if len(remainToken) > 0 && mapping[remainToken] {
+		content, _ := json.Marshal(map[string]bool{remainToken: true})
+		if err := ar.data.Cache.SetString(ctx, key, string(content), constant.UserTokenCacheTime); err != nil {
+			log.Error(err)
+		}
+		return
+	}
 	if err := ar.data.Cache.Del(ctx, key); err != nil {
 		log.Error(err)
 	}
 }
+
+// SetUserRecentAuth set the mark of the recent authentication of the token
+func (ar *authRepo) SetUserRecentAuth(ctx context.Context, accessToken string) (err error) {
+	err = ar.data.Cache.SetString(ctx, constant.UserRecentAuthCacheKey+accessToken, "1", constant.UserRecentAuthCacheTime)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetUserRecentAuth get the mark of the recent authentication of the token
+func (ar *authRepo) GetUserRecentAuth(ctx context.Context, accessToken string) (exist bool, err error) {
+	_, exist, err = ar.data.Cache.GetString(ctx, constant.UserRecentAuthCacheKey+accessToken)
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return exist, nil
+}
+
+// GetUserSession get the session info of the token
+func (ar *authRepo) GetUserSession(ctx context.Context, accessToken string) (info *entity.UserSessionInfo, err error) {
+	res, exist, err := ar.data.Cache.GetString(ctx, constant.UserSessionCacheKey+accessToken)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		return nil, nil
+	}
+	info = &entity.UserSessionInfo{}
+	_ = json.Unmarshal([]byte(res), info)
+	return info, nil
+}
+
+// SetUserSession set the session info of the token, it lives as long as the token
+func (ar *authRepo) SetUserSession(ctx context.Context, accessToken string, info *entity.UserSessionInfo) (err error) {
+	content, _ := json.Marshal(info)
+	err = ar.data.Cache.SetString(ctx, constant.UserSessionCacheKey+accessToken, string(content),
+		constant.UserTokenCacheTime)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetUserTokens get the tokens of the user from the token mapping, some of them may have expired
+func (ar *authRepo) GetUserTokens(ctx context.Context, userID string) (tokens []string, err error) {
+	resp, _, err := ar.data.Cache.GetString(ctx, constant.UserTokenMappingCacheKey+userID)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	mapping := make(map[string]bool, 0)
+	if len(resp) > 0 {
+		_ = json.Unmarshal([]byte(resp), &mapping)
+	}
+	for token := range mapping {
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// RemoveUserTokenMapping remove the tokens from the token mapping of the user
+func (ar *authRepo) RemoveUserTokenMapping(ctx context.Context, userID string, accessTokens ...string) (err error) {
+	key := constant.UserTokenMappingCacheKey + userID
+	resp, exist, err := ar.data.Cache.GetString(ctx, key)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		return nil
+	}
+	mapping := make(map[string]bool, 0)
+	_ = json.Unmarshal([]byte(resp), &mapping)
+	for _, token := range accessTokens {
+		delete(mapping, token)
+	}
+	content, _ := json.Marshal(mapping)
+	err = ar.data.Cache.SetString(ctx, key, string(content), constant.UserTokenCacheTime)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
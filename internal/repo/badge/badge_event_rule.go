@@ -90,7 +90,7 @@ func (br *eventRuleRepo) FirstUpdateUserProfile(ctx context.Context,
 	badges := br.getBadgesByHandler(ctx, "FirstUpdateUserProfile")
 	for _, b := range badges {
 		bean := &entity.User{ID: event.UserID}
-		exist, err := br.data.DB.Context(ctx).Get(bean)
+		exist, err := br.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Get(bean)
 		if err != nil {
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
@@ -173,7 +173,7 @@ func (br *eventRuleRepo) ReachAnswerAcceptedAmount(ctx context.Context,
 	}
 
 	// count user's accepted answer amount
-	amount, err := br.data.DB.Context(ctx).Count(&entity.Answer{
+	amount, err := br.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Count(&entity.Answer{
 		UserID:   event.AnswerUserID,
 		Accepted: schema.AnswerAcceptedEnable,
 		Status:   entity.AnswerStatusAvailable,
@@ -237,7 +237,7 @@ func (br *eventRuleRepo) ReachQuestionVote(ctx context.Context,
 
 func (br *eventRuleRepo) getBadgesByHandler(ctx context.Context, handler string) (badges []*entity.Badge) {
 	badges = make([]*entity.Badge, 0)
-	err := br.data.DB.Context(ctx).Where("handler = ?", handler).Find(&badges)
+	err := br.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("handler = ?", handler).Find(&badges)
 	if err != nil {
 		log.Errorf("error getting badge by handler %s: %v", handler, err)
 		return nil
@@ -47,7 +47,7 @@ func NewBadgeRepo(data *data.Data, uniqueIDRepo unique.UniqueIDRepo) badge.Badge
 
 func (r *badgeRepo) GetByID(ctx context.Context, id string) (badge *entity.Badge, exists bool, err error) {
 	badge = &entity.Badge{}
-	exists, err = r.data.DB.Context(ctx).Where("id = ?", id).Get(badge)
+	exists, err = r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", id).Get(badge)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -56,7 +56,7 @@ func (r *badgeRepo) GetByID(ctx context.Context, id string) (badge *entity.Badge
 
 func (r *badgeRepo) GetByIDs(ctx context.Context, ids []string) (badges []*entity.Badge, err error) {
 	badges = make([]*entity.Badge, 0)
-	err = r.data.DB.Context(ctx).In("id", ids).Find(&badges)
+	err = r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("id", ids).Find(&badges)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -68,7 +68,7 @@ func (r *badgeRepo) ListPaged(ctx context.Context, page int, pageSize int) (badg
 	badges = make([]*entity.Badge, 0)
 	total = 0
 
-	session := r.data.DB.Context(ctx).Where("status <> ?", entity.BadgeStatusDeleted)
+	session := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status <> ?", entity.BadgeStatusDeleted)
 	if page == 0 || pageSize == 0 {
 		err = session.Find(&badges)
 	} else {
@@ -86,7 +86,7 @@ func (r *badgeRepo) ListActivated(ctx context.Context, page int, pageSize int) (
 	badges = make([]*entity.Badge, 0)
 	total = 0
 
-	session := r.data.DB.Context(ctx).Where("status = ?", entity.BadgeStatusActive)
+	session := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ?", entity.BadgeStatusActive)
 	if page == 0 || pageSize == 0 {
 		err = session.Find(&badges)
 	} else {
@@ -104,7 +104,7 @@ func (r *badgeRepo) ListInactivated(ctx context.Context, page int, pageSize int)
 	badges = make([]*entity.Badge, 0)
 	total = 0
 
-	session := r.data.DB.Context(ctx).Where("status = ?", entity.BadgeStatusInactive)
+	session := r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ?", entity.BadgeStatusInactive)
 	if page == 0 || pageSize == 0 {
 		err = session.Find(&badges)
 	} else {
@@ -119,8 +119,8 @@ func (r *badgeRepo) ListInactivated(ctx context.Context, page int, pageSize int)
 
 // UpdateStatus updates the award count of a badge
 func (r *badgeRepo) UpdateStatus(ctx context.Context, id string, status int8) (err error) {
-	_, err = r.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
-		_, err = session.ID(id).Update(&entity.Badge{
+	_, err = r.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		_, err = session.Where(data.TenantCond(ctx)).ID(id).Update(&entity.Badge{
 			Status: status,
 		})
 		if err != nil {
@@ -128,11 +128,11 @@ func (r *badgeRepo) UpdateStatus(ctx context.Context, id string, status int8) (e
 			return
 		}
 		if status >= entity.BadgeStatusDeleted {
-			_, err = session.Where("badge_id = ?", id).Cols("is_badge_deleted").Update(&entity.BadgeAward{
+			_, err = session.Where(data.TenantCond(ctx)).And("badge_id = ?", id).Cols("is_badge_deleted").Update(&entity.BadgeAward{
 				IsBadgeDeleted: entity.IsBadgeDeleted,
 			})
 		} else {
-			_, err = session.Where("badge_id = ?", id).Cols("is_badge_deleted").Update(&entity.BadgeAward{
+			_, err = session.Where(data.TenantCond(ctx)).And("badge_id = ?", id).Cols("is_badge_deleted").Update(&entity.BadgeAward{
 				IsBadgeDeleted: entity.IsBadgeNotDeleted,
 			})
 		}
@@ -144,7 +144,7 @@ func (r *badgeRepo) UpdateStatus(ctx context.Context, id string, status int8) (e
 
 // UpdateAwardCount updates the award count of a badge
 func (r *badgeRepo) UpdateAwardCount(ctx context.Context, badgeID string, awardCount int) (err error) {
-	_, err = r.data.DB.Context(ctx).ID(badgeID).Cols("award_count").Update(&entity.Badge{AwardCount: awardCount})
+	_, err = r.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(badgeID).Cols("award_count").Update(&entity.Badge{AwardCount: awardCount})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -0,0 +1,216 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package bounty
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/bounty"
+	"github.com/apache/answer/internal/service/rank"
+	"github.com/apache/answer/pkg/converter"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+type bountyRepo struct {
+	data         *data.Data
+	userRankRepo rank.UserRankRepo
+}
+
+// NewBountyRepo creates a new bounty repository
+func NewBountyRepo(data *data.Data, userRankRepo rank.UserRankRepo) bounty.BountyRepo {
+	return &bountyRepo{
+		data:         data,
+		userRankRepo: userRankRepo,
+	}
+}
+
+// AddBounty debit the reputation of the user and open the bounty on the question,
+// rankEnough is false if the user does not have enough reputation to offer the bounty.
+func (br *bountyRepo) AddBounty(ctx context.Context, bountyInfo *entity.QuestionBounty, activityType int) (
+	rankEnough bool, err error) {
+	res, err := br.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+
+		user := &entity.User{}
+		exist, err := session.Where(data.TenantCond(ctx)).ID(bountyInfo.UserID).ForUpdate().Get(user)
+		if err != nil {
+			return false, err
+		}
+		// the reputation of the user must stay positive after the bounty is offered
+		if !exist || user.Rank-bountyInfo.Amount < 1 {
+			return false, nil
+		}
+
+		bountyInfo.TenantID = handler.GetTenantID(ctx)
+		if _, err = session.Insert(bountyInfo); err != nil {
+			return false, err
+		}
+		_, err = session.Insert(&entity.Activity{
+			TenantID:         bountyInfo.TenantID,
+			UserID:           bountyInfo.UserID,
+			ObjectID:         bountyInfo.QuestionID,
+			OriginalObjectID: bountyInfo.QuestionID,
+			ActivityType:     activityType,
+			Rank:             -bountyInfo.Amount,
+			HasRank:          1,
+		})
+		if err != nil {
+			return false, err
+		}
+		if err = br.userRankRepo.ChangeUserRank(ctx, session, user.ID, user.Rank, -bountyInfo.Amount); err != nil {
+			return false, err
+		}
+		_, err = session.Where(data.TenantCond(ctx)).ID(bountyInfo.QuestionID).Cols("bounty_amount").
+			Update(&entity.Question{BountyAmount: bountyInfo.Amount})
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return res.(bool), nil
+}
+
+// AwardBounty close the open bounty and credit the reputation to the author of the answer,
+// awarded is false if the bounty has been closed already.
+func (br *bountyRepo) AwardBounty(ctx context.Context, award *schema.BountyAwardInfo, activityType int) (
+	awarded bool, err error) {
+	res, err := br.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+
+		affected, err := session.Where(data.TenantCond(ctx)).ID(award.BountyID).And(builder.Eq{"status": entity.QuestionBountyStatusActive}).
+			Cols("status", "answer_id", "awarded_user_id", "awarded_amount", "awarded_at").
+			Update(&entity.QuestionBounty{
+				Status:        entity.QuestionBountyStatusAwarded,
+				AnswerID:      award.AnswerID,
+				AwardedUserID: award.AwardedUserID,
+				AwardedAmount: award.AwardedAmount,
+				AwardedAt:     time.Now(),
+			})
+		if err != nil {
+			return false, err
+		}
+		if affected == 0 {
+			return false, nil
+		}
+
+		user := &entity.User{}
+		exist, err := session.Where(data.TenantCond(ctx)).ID(award.AwardedUserID).ForUpdate().Get(user)
+		if err != nil {
+			return false, err
+		}
+		if exist && award.AwardedAmount > 0 {
+			_, err = session.Insert(&entity.Activity{
+				TenantID:         handler.GetTenantID(ctx),
+				UserID:           award.AwardedUserID,
+				TriggerUserID:    converter.StringToInt64(award.OfferUserID),
+				ObjectID:         award.AnswerID,
+				OriginalObjectID: award.QuestionID,
+				ActivityType:     activityType,
+				Rank:             award.AwardedAmount,
+				HasRank:          1,
+			})
+			if err != nil {
+				return false, err
+			}
+			if err = br.userRankRepo.ChangeUserRank(ctx, session, user.ID, user.Rank, award.AwardedAmount); err != nil {
+				return false, err
+			}
+		}
+		if err = br.clearQuestionBounty(session, award.QuestionID); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return res.(bool), nil
+}
+
+// ExpireBounty close the open bounty without an award, the offered reputation is not refunded
+func (br *bountyRepo) ExpireBounty(ctx context.Context, bountyInfo *entity.QuestionBounty) (expired bool, err error) {
+	res, err := br.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+
+		affected, err := session.Where(data.TenantCond(ctx)).ID(bountyInfo.ID).And(builder.Eq{"status": entity.QuestionBountyStatusActive}).
+			Cols("status").Update(&entity.QuestionBounty{Status: entity.QuestionBountyStatusExpired})
+		if err != nil {
+			return false, err
+		}
+		if affected == 0 {
+			return false, nil
+		}
+		if err = br.clearQuestionBounty(session, bountyInfo.QuestionID); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return res.(bool), nil
+}
+
+func (br *bountyRepo) GetActiveBounty(ctx context.Context, questionID string) (
+	bountyInfo *entity.QuestionBounty, exist bool, err error) {
+	bountyInfo = &entity.QuestionBounty{}
+	exist, err = br.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("question_id = ? AND status = ?",
+		questionID, entity.QuestionBountyStatusActive).Get(bountyInfo)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (br *bountyRepo) GetBountyList(ctx context.Context, questionID string) (list []*entity.QuestionBounty, err error) {
+	list = make([]*entity.QuestionBounty, 0)
+	err = br.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("question_id = ?", questionID).Desc("id").Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (br *bountyRepo) GetExpiredActiveBounties(ctx context.Context, before time.Time, afterID int64, limit int) (
+	list []*entity.QuestionBounty, err error) {
+	list = make([]*entity.QuestionBounty, 0)
+	err = br.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ? AND expired_at <= ? AND id > ?",
+		entity.QuestionBountyStatusActive, before, afterID).Asc("id").Limit(limit).Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (br *bountyRepo) clearQuestionBounty(session *xorm.Session, questionID string) (err error) {
+	_, err = session.ID(questionID).Cols("bounty_amount").Update(&entity.Question{BountyAmount: 0})
+	return err
+}
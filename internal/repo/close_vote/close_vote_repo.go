@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package close_vote
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/close_vote"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+type closeVoteRepo struct {
+	data *data.Data
+}
+
+// NewCloseVoteRepo creates a new close vote repository
+func NewCloseVoteRepo(data *data.Data) close_vote.CloseVoteRepo {
+	return &closeVoteRepo{
+		data: data,
+	}
+}
+
+func (cr *closeVoteRepo) AddCloseVote(ctx context.Context, vote *entity.QuestionCloseVote) (err error) {
+	vote.TenantID = handler.GetTenantID(ctx)
+	_, err = cr.data.DB.Context(ctx).Insert(vote)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (cr *closeVoteRepo) RemoveCloseVote(ctx context.Context, questionID, userID string) (err error) {
+	_, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("question_id = ? AND user_id = ? AND status = ?",
+		questionID, userID, entity.QuestionCloseVoteStatusActive).Delete(&entity.QuestionCloseVote{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (cr *closeVoteRepo) GetActiveCloseVote(ctx context.Context, questionID, userID string) (
+	vote *entity.QuestionCloseVote, exist bool, err error) {
+	vote = &entity.QuestionCloseVote{}
+	exist, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("question_id = ? AND user_id = ? AND status = ?",
+		questionID, userID, entity.QuestionCloseVoteStatusActive).Get(vote)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (cr *closeVoteRepo) GetActiveCloseVoteList(ctx context.Context, questionID string, voteType int) (
+	list []*entity.QuestionCloseVote, err error) {
+	list = make([]*entity.QuestionCloseVote, 0)
+	err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("question_id = ? AND vote_type = ? AND status = ?",
+		questionID, voteType, entity.QuestionCloseVoteStatusActive).Asc("id").Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (cr *closeVoteRepo) CompleteCloseVotes(ctx context.Context, questionID string, voteType int) (err error) {
+	_, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("question_id = ? AND vote_type = ? AND status = ?",
+		questionID, voteType, entity.QuestionCloseVoteStatusActive).Cols("status").
+		Update(&entity.QuestionCloseVote{Status: entity.QuestionCloseVoteStatusCompleted})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ExpireCloseVotes expire the active votes created before the time, return the questions of the expired votes
+func (cr *closeVoteRepo) ExpireCloseVotes(ctx context.Context, before time.Time) (questionIDs []string, err error) {
+	questionIDs = make([]string, 0)
+	cond := builder.Eq{"status": entity.QuestionCloseVoteStatusActive}.And(builder.Lt{"created_at": before})
+	_, err = cr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		err = session.Table(new(entity.QuestionCloseVote).TableName()).Where(cond).
+			Distinct("question_id").Find(&questionIDs)
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.Where(cond).Cols("status").
+			Update(&entity.QuestionCloseVote{Status: entity.QuestionCloseVoteStatusExpired})
+		return nil, err
+	})
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return questionIDs, nil
+}
+
+// RecountCloseVotes update the tally of the active close and reopen votes of the question
+func (cr *closeVoteRepo) RecountCloseVotes(ctx context.Context, questionID string) (err error) {
+	_, err = cr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		counts := make(map[int]int64)
+		for _, voteType := range []int{entity.QuestionCloseVoteTypeClose, entity.QuestionCloseVoteTypeReopen} {
+			counts[voteType], err = session.Where("question_id = ? AND vote_type = ? AND status = ?",
+				questionID, voteType, entity.QuestionCloseVoteStatusActive).Count(&entity.QuestionCloseVote{})
+			if err != nil {
+				return nil, err
+			}
+		}
+		_, err = session.ID(questionID).Cols("close_vote_count", "reopen_vote_count").Update(&entity.Question{
+			CloseVoteCount:  int(counts[entity.QuestionCloseVoteTypeClose]),
+			ReopenVoteCount: int(counts[entity.QuestionCloseVoteTypeReopen]),
+		})
+		return nil, err
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetPendingCloseVoteQuestionPage get the questions with active votes, the latest voted first
+func (cr *closeVoteRepo) GetPendingCloseVoteQuestionPage(ctx context.Context, page, pageSize int) (
+	questionIDs []string, total int64, err error) {
+	questionIDs = make([]string, 0)
+	total, err = cr.CountPendingCloseVoteQuestions(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	session := cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table(new(entity.QuestionCloseVote).TableName()).
+		Select("question_id").Where("status = ?", entity.QuestionCloseVoteStatusActive).
+		GroupBy("question_id").OrderBy("MAX(id) DESC")
+	page, pageSize = pager.ValPageAndPageSize(page, pageSize)
+	session.Limit(pageSize, (page-1)*pageSize)
+	if err = session.Find(&questionIDs); err != nil {
+		return nil, 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return questionIDs, total, nil
+}
+
+// CountPendingCloseVoteQuestions count the questions with active votes
+func (cr *closeVoteRepo) CountPendingCloseVoteQuestions(ctx context.Context) (count int64, err error) {
+	count, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ?", entity.QuestionCloseVoteStatusActive).
+		Distinct("question_id").Count(&entity.QuestionCloseVote{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
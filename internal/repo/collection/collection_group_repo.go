@@ -26,6 +26,7 @@ import (
 	"xorm.io/xorm"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/pager"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
@@ -47,6 +48,7 @@ func NewCollectionGroupRepo(data *data.Data) collection.CollectionGroupRepo {
 
 // AddCollectionGroup add collection group
 func (cr *collectionGroupRepo) AddCollectionGroup(ctx context.Context, collectionGroup *entity.CollectionGroup) (err error) {
+	collectionGroup.TenantID = handler.GetTenantID(ctx)
 	_, err = cr.data.DB.Context(ctx).Insert(collectionGroup)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -57,6 +59,7 @@ func (cr *collectionGroupRepo) AddCollectionGroup(ctx context.Context, collectio
 // AddCollectionDefaultGroup add collection group
 func (cr *collectionGroupRepo) AddCollectionDefaultGroup(ctx context.Context, userID string) (collectionGroup *entity.CollectionGroup, err error) {
 	defaultGroup := &entity.CollectionGroup{
+		TenantID:     handler.GetTenantID(ctx),
 		Name:         "default",
 		DefaultGroup: schema.CGDefault,
 		UserID:       userID,
@@ -73,13 +76,13 @@ func (cr *collectionGroupRepo) AddCollectionDefaultGroup(ctx context.Context, us
 // CreateDefaultGroupIfNotExist create default group if not exist
 func (cr *collectionGroupRepo) CreateDefaultGroupIfNotExist(ctx context.Context, userID string) (
 	collectionGroup *entity.CollectionGroup, err error) {
-	_, err = cr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = cr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 		old := &entity.CollectionGroup{
 			UserID:       userID,
 			DefaultGroup: schema.CGDefault,
 		}
-		exist, err := session.ForUpdate().Get(old)
+		exist, err := session.Where(data.TenantCond(ctx)).ForUpdate().Get(old)
 		if err != nil {
 			return nil, err
 		}
@@ -89,6 +92,7 @@ func (cr *collectionGroupRepo) CreateDefaultGroupIfNotExist(ctx context.Context,
 		}
 
 		defaultGroup := &entity.CollectionGroup{
+			TenantID:     handler.GetTenantID(ctx),
 			Name:         "default",
 			DefaultGroup: schema.CGDefault,
 			UserID:       userID,
@@ -108,7 +112,7 @@ func (cr *collectionGroupRepo) CreateDefaultGroupIfNotExist(ctx context.Context,
 
 // UpdateCollectionGroup update collection group
 func (cr *collectionGroupRepo) UpdateCollectionGroup(ctx context.Context, collectionGroup *entity.CollectionGroup, cols []string) (err error) {
-	_, err = cr.data.DB.Context(ctx).ID(collectionGroup.ID).Cols(cols...).Update(collectionGroup)
+	_, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(collectionGroup.ID).Cols(cols...).Update(collectionGroup)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -120,7 +124,7 @@ func (cr *collectionGroupRepo) GetCollectionGroup(ctx context.Context, id string
 	collectionGroup *entity.CollectionGroup, exist bool, err error,
 ) {
 	collectionGroup = &entity.CollectionGroup{}
-	exist, err = cr.data.DB.Context(ctx).ID(id).Get(collectionGroup)
+	exist, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Get(collectionGroup)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -131,7 +135,7 @@ func (cr *collectionGroupRepo) GetCollectionGroup(ctx context.Context, id string
 func (cr *collectionGroupRepo) GetCollectionGroupPage(ctx context.Context, page, pageSize int, collectionGroup *entity.CollectionGroup) (collectionGroupList []*entity.CollectionGroup, total int64, err error) {
 	collectionGroupList = make([]*entity.CollectionGroup, 0)
 
-	session := cr.data.DB.Context(ctx)
+	session := cr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	if collectionGroup.UserID != "" && collectionGroup.UserID != "0" {
 		session = session.Where("user_id = ?", collectionGroup.UserID)
 	}
@@ -144,7 +148,7 @@ func (cr *collectionGroupRepo) GetCollectionGroupPage(ctx context.Context, page,
 
 func (cr *collectionGroupRepo) GetDefaultID(ctx context.Context, userID string) (collectionGroup *entity.CollectionGroup, has bool, err error) {
 	collectionGroup = &entity.CollectionGroup{}
-	has, err = cr.data.DB.Context(ctx).Where("user_id =? and  default_group = ?", userID, schema.CGDefault).Get(collectionGroup)
+	has, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id =? and  default_group = ?", userID, schema.CGDefault).Get(collectionGroup)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		return
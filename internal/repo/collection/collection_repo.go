@@ -56,19 +56,20 @@ func (cr *collectionRepo) AddCollection(ctx context.Context, collection *entity.
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 
-	_, err = cr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = cr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 		old := &entity.Collection{
 			UserID:   collection.UserID,
 			ObjectID: collection.ObjectID,
 		}
-		exist, err := session.ForUpdate().Get(old)
+		exist, err := session.Where(data.TenantCond(ctx)).ForUpdate().Get(old)
 		if err != nil {
 			return nil, err
 		}
 		if exist {
 			return nil, nil
 		}
+		collection.TenantID = handler.GetTenantID(ctx)
 		_, err = session.Insert(collection)
 		if err != nil {
 			return nil, err
@@ -83,7 +84,7 @@ func (cr *collectionRepo) AddCollection(ctx context.Context, collection *entity.
 
 // RemoveCollection delete collection
 func (cr *collectionRepo) RemoveCollection(ctx context.Context, id string) (err error) {
-	_, err = cr.data.DB.Context(ctx).Where("id = ?", id).Delete(&entity.Collection{})
+	_, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", id).Delete(&entity.Collection{})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -92,14 +93,14 @@ func (cr *collectionRepo) RemoveCollection(ctx context.Context, id string) (err
 
 // UpdateCollection update collection
 func (cr *collectionRepo) UpdateCollection(ctx context.Context, collection *entity.Collection, cols []string) (err error) {
-	_, err = cr.data.DB.Context(ctx).ID(collection.ID).Cols(cols...).Update(collection)
+	_, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(collection.ID).Cols(cols...).Update(collection)
 	return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 }
 
 // GetCollection get collection one
 func (cr *collectionRepo) GetCollection(ctx context.Context, id int) (collection *entity.Collection, exist bool, err error) {
 	collection = &entity.Collection{}
-	exist, err = cr.data.DB.Context(ctx).ID(id).Get(collection)
+	exist, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Get(collection)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -109,7 +110,7 @@ func (cr *collectionRepo) GetCollection(ctx context.Context, id int) (collection
 // GetCollectionList get collection list all
 func (cr *collectionRepo) GetCollectionList(ctx context.Context, collection *entity.Collection) (collectionList []*entity.Collection, err error) {
 	collectionList = make([]*entity.Collection, 0)
-	err = cr.data.DB.Context(ctx).Find(&collectionList, collection)
+	err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Find(&collectionList, collection)
 	err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	return
 }
@@ -117,7 +118,7 @@ func (cr *collectionRepo) GetCollectionList(ctx context.Context, collection *ent
 // GetOneByObjectIDAndUser get one by object TagID and user
 func (cr *collectionRepo) GetOneByObjectIDAndUser(ctx context.Context, userID string, objectID string) (collection *entity.Collection, exist bool, err error) {
 	collection = &entity.Collection{}
-	exist, err = cr.data.DB.Context(ctx).Where("user_id = ? and object_id = ?", userID, objectID).Get(collection)
+	exist, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? and object_id = ?", userID, objectID).Get(collection)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -127,7 +128,7 @@ func (cr *collectionRepo) GetOneByObjectIDAndUser(ctx context.Context, userID st
 // SearchByObjectIDsAndUser search by object IDs and user
 func (cr *collectionRepo) SearchByObjectIDsAndUser(ctx context.Context, userID string, objectIDs []string) ([]*entity.Collection, error) {
 	collectionList := make([]*entity.Collection, 0)
-	err := cr.data.DB.Context(ctx).Where("user_id = ?", userID).In("object_id", objectIDs).Find(&collectionList)
+	err := cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).In("object_id", objectIDs).Find(&collectionList)
 	if err != nil {
 		return collectionList, err
 	}
@@ -137,7 +138,7 @@ func (cr *collectionRepo) SearchByObjectIDsAndUser(ctx context.Context, userID s
 // CountByObjectID count by object TagID
 func (cr *collectionRepo) CountByObjectID(ctx context.Context, objectID string) (total int64, err error) {
 	collection := &entity.Collection{}
-	total, err = cr.data.DB.Context(ctx).Where("object_id = ?", objectID).Count(collection)
+	total, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID).Count(collection)
 	if err != nil {
 		return 0, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -148,7 +149,7 @@ func (cr *collectionRepo) CountByObjectID(ctx context.Context, objectID string)
 func (cr *collectionRepo) GetCollectionPage(ctx context.Context, page, pageSize int, collection *entity.Collection) (collectionList []*entity.Collection, total int64, err error) {
 	collectionList = make([]*entity.Collection, 0)
 
-	session := cr.data.DB.Context(ctx)
+	session := cr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	if collection.UserID != "" && collection.UserID != "0" {
 		session = session.Where("user_id = ?", collection.UserID)
 	}
@@ -201,7 +202,7 @@ func (cr *collectionRepo) SearchList(ctx context.Context, search *entity.Collect
 		search.PageSize = constant.DefaultPageSize
 	}
 	offset := search.Page * search.PageSize
-	session := cr.data.DB.Context(ctx).Where("")
+	session := cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("")
 	if len(search.UserID) > 0 {
 		session = session.And("user_id = ?", search.UserID)
 	} else {
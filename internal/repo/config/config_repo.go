@@ -25,11 +25,13 @@ import (
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/config"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
+	"xorm.io/builder"
 )
 
 // configRepo config repository
@@ -64,6 +66,9 @@ func (cr configRepo) GetConfigByID(ctx context.Context, id int) (c *entity.Confi
 	if !exist {
 		return nil, fmt.Errorf("config not found by id: %d", id)
 	}
+	if err = cr.overrideByTenant(ctx, c); err != nil {
+		return nil, err
+	}
 
 	// update cache
 	if err := cr.data.Cache.SetString(ctx, cacheKey, c.JsonString(), constant.ConfigCacheTime); err != nil {
@@ -83,13 +88,9 @@ func (cr configRepo) GetConfigByKey(ctx context.Context, key string) (c *entity.
 		}
 	}
 
-	c = &entity.Config{Key: key}
-	exist, err = cr.data.DB.Context(ctx).Get(c)
+	c, err = cr.GetConfigByKeyFromDB(ctx, key)
 	if err != nil {
-		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
-	}
-	if !exist {
-		return nil, fmt.Errorf("config not found by key: %s", key)
+		return nil, err
 	}
 
 	// update cache
@@ -108,9 +109,30 @@ func (cr configRepo) GetConfigByKeyFromDB(ctx context.Context, key string) (c *e
 	if !exist {
 		return nil, fmt.Errorf("config not found by key: %s", key)
 	}
+	if err = cr.overrideByTenant(ctx, c); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
+// overrideByTenant the value of the config is overridden by the tenant in the context.
+// The id is always of the config, so that the activity types are the same for all tenants.
+func (cr configRepo) overrideByTenant(ctx context.Context, c *entity.Config) (err error) {
+	tenantID := handler.GetTenantID(ctx)
+	if tenantID == entity.DefaultTenantID {
+		return nil
+	}
+	tenantConfig := &entity.TenantConfig{}
+	exist, err := cr.data.DB.Context(ctx).Where(builder.Eq{"tenant_id": tenantID, "`key`": c.Key}).Get(tenantConfig)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		c.Value = tenantConfig.Value
+	}
+	return nil
+}
+
 func (cr configRepo) UpdateConfig(ctx context.Context, key string, value string) (err error) {
 	// check if key exists
 	oldConfig := &entity.Config{Key: key}
@@ -122,8 +144,13 @@ func (cr configRepo) UpdateConfig(ctx context.Context, key string, value string)
 		return errors.BadRequest(reason.ObjectNotFound)
 	}
 
-	// update database
-	_, err = cr.data.DB.Context(ctx).ID(oldConfig.ID).Update(&entity.Config{Value: value})
+	// update database, the tenant keeps its value in the tenant config
+	tenantID := handler.GetTenantID(ctx)
+	if tenantID == entity.DefaultTenantID {
+		_, err = cr.data.DB.Context(ctx).ID(oldConfig.ID).Update(&entity.Config{Value: value})
+	} else {
+		err = cr.saveTenantConfig(ctx, tenantID, key, value)
+	}
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -141,3 +168,27 @@ func (cr configRepo) UpdateConfig(ctx context.Context, key string, value string)
 	}
 	return
 }
+
+func (cr configRepo) saveTenantConfig(ctx context.Context, tenantID int, key, value string) (err error) {
+	tenantConfig := &entity.TenantConfig{}
+	exist, err := cr.data.DB.Context(ctx).Where(builder.Eq{"tenant_id": tenantID, "`key`": key}).Get(tenantConfig)
+	if err != nil {
+		return err
+	}
+	if exist {
+		_, err = cr.data.DB.Context(ctx).ID(tenantConfig.ID).Cols("value").Update(&entity.TenantConfig{Value: value})
+		return err
+	}
+	_, err = cr.data.DB.Context(ctx).Insert(&entity.TenantConfig{TenantID: tenantID, Key: key, Value: value})
+	return err
+}
+
+// GetTenantConfigListByKey get the values of the config overridden by the tenants
+func (cr configRepo) GetTenantConfigListByKey(ctx context.Context, key string) (tenantConfigs []*entity.TenantConfig, err error) {
+	tenantConfigs = make([]*entity.TenantConfig, 0)
+	err = cr.data.DB.Context(ctx).Where(builder.Eq{"`key`": key}).Find(&tenantConfigs)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return tenantConfigs, nil
+}
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package draft
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/draft"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type draftRepo struct {
+	data *data.Data
+}
+
+// NewDraftRepo creates a new draft repository
+func NewDraftRepo(data *data.Data) draft.DraftRepo {
+	return &draftRepo{
+		data: data,
+	}
+}
+
+func (dr *draftRepo) AddDraft(ctx context.Context, draftInfo *entity.Draft) (err error) {
+	draftInfo.TenantID = handler.GetTenantID(ctx)
+	_, err = dr.data.DB.Context(ctx).Insert(draftInfo)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (dr *draftRepo) UpdateDraft(ctx context.Context, draftInfo *entity.Draft) (err error) {
+	_, err = dr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(draftInfo.ID).Where("user_id = ?", draftInfo.UserID).
+		Cols("title", "content", "tags").Update(draftInfo)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (dr *draftRepo) DeleteDraft(ctx context.Context, userID string, id int) (err error) {
+	_, err = dr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Where("user_id = ?", userID).Delete(&entity.Draft{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (dr *draftRepo) DeleteDraftByObject(ctx context.Context, userID, draftType, objectID string) (err error) {
+	_, err = dr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND draft_type = ? AND object_id = ?",
+		userID, draftType, objectID).Delete(&entity.Draft{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (dr *draftRepo) DeleteDraftsUpdatedBefore(ctx context.Context, before time.Time) (affected int64, err error) {
+	affected, err = dr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("updated_at < ?", before).Delete(&entity.Draft{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (dr *draftRepo) GetDraft(ctx context.Context, userID string, id int) (
+	draftInfo *entity.Draft, exist bool, err error) {
+	draftInfo = &entity.Draft{}
+	exist, err = dr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Where("user_id = ?", userID).Get(draftInfo)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (dr *draftRepo) GetDraftByObject(ctx context.Context, userID, draftType, objectID string) (
+	draftInfo *entity.Draft, exist bool, err error) {
+	draftInfo = &entity.Draft{}
+	exist, err = dr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND draft_type = ? AND object_id = ?",
+		userID, draftType, objectID).Get(draftInfo)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (dr *draftRepo) GetDraftPage(ctx context.Context, userID string, page, pageSize int) (
+	list []*entity.Draft, total int64, err error) {
+	list = make([]*entity.Draft, 0)
+	session := dr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Desc("updated_at", "id")
+	total, err = pager.Help(page, pageSize, &list, &entity.Draft{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (dr *draftRepo) CountDraft(ctx context.Context, userID string) (count int64, err error) {
+	count, err = dr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Count(&entity.Draft{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,198 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package feed
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/feed"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// feedRepo feed repository
+type feedRepo struct {
+	data *data.Data
+}
+
+// NewFeedRepo new repository
+func NewFeedRepo(data *data.Data) feed.FeedRepo {
+	return &feedRepo{
+		data: data,
+	}
+}
+
+// GetUserTagExpertise get the tags the user answered most, key is tag id and value is the answer amount
+func (fr *feedRepo) GetUserTagExpertise(ctx context.Context, userID string, limit int) (
+	expertise map[string]int64, err error) {
+	type tagAmount struct {
+		TagID  string `xorm:"tag_id"`
+		Amount int64  `xorm:"amount"`
+	}
+	rows := make([]*tagAmount, 0)
+	err = fr.data.DB.Context(ctx).Where(data.TenantCond(ctx, "answer")).Table(entity.Answer{}.TableName()).
+		Select("tag_rel.tag_id, COUNT(*) AS amount").
+		Join("INNER", entity.TagRel{}.TableName(), "answer.question_id = tag_rel.object_id").
+		Where("answer.user_id = ? AND answer.status = ?", userID, entity.AnswerStatusAvailable).
+		And("tag_rel.status = ?", entity.TagRelStatusAvailable).
+		GroupBy("tag_rel.tag_id").
+		OrderBy("amount DESC").
+		Limit(limit).
+		Find(&rows)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	expertise = make(map[string]int64, len(rows))
+	for _, row := range rows {
+		expertise[row.TagID] = row.Amount
+	}
+	return expertise, nil
+}
+
+// GetQuestionsByTagsOrAuthors get the questions with any of the tags or asked by any of the authors
+func (fr *feedRepo) GetQuestionsByTagsOrAuthors(ctx context.Context, userID string, tagIDs, authorIDs []string,
+	inDays, limit int) (questions []*entity.Question, err error) {
+	cond := builder.NewCond()
+	if len(tagIDs) > 0 {
+		cond = cond.Or(builder.In("question.id", builder.Select("object_id").From(entity.TagRel{}.TableName()).
+			Where(builder.Eq{"status": entity.TagRelStatusAvailable}.And(builder.In("tag_id", tagIDs)))))
+	}
+	if len(authorIDs) > 0 {
+		cond = cond.Or(builder.In("question.user_id", authorIDs))
+	}
+	questions = make([]*entity.Question, 0)
+	err = fr.candidateSession(ctx, userID, inDays).
+		And(cond).
+		OrderBy("question.created_at DESC").
+		Limit(limit).
+		Find(&questions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return questions, nil
+}
+
+// GetPopularQuestions get the hottest questions
+func (fr *feedRepo) GetPopularQuestions(ctx context.Context, userID string, inDays, limit int) (
+	questions []*entity.Question, err error) {
+	questions = make([]*entity.Question, 0)
+	err = fr.candidateSession(ctx, userID, inDays).
+		OrderBy("question.hot_score DESC, question.created_at DESC").
+		Limit(limit).
+		Find(&questions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return questions, nil
+}
+
+// GetQuestionsByIDs get the questions that can be recommended to the user by ids
+func (fr *feedRepo) GetQuestionsByIDs(ctx context.Context, userID string, questionIDs []string) (
+	questions []*entity.Question, err error) {
+	questions = make([]*entity.Question, 0)
+	if len(questionIDs) == 0 {
+		return questions, nil
+	}
+	err = fr.candidateSession(ctx, userID, 0).In("question.id", questionIDs).Find(&questions)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return questions, nil
+}
+
+// GetQuestionsTagIDs get the tag ids of each question
+func (fr *feedRepo) GetQuestionsTagIDs(ctx context.Context, questionIDs []string) (
+	tagIDs map[string][]string, err error) {
+	tagIDs = make(map[string][]string, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return tagIDs, nil
+	}
+	tagRelList := make([]*entity.TagRel, 0)
+	err = fr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("object_id", questionIDs).
+		Where("status = ?", entity.TagRelStatusAvailable).Find(&tagRelList)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	for _, rel := range tagRelList {
+		tagIDs[rel.ObjectID] = append(tagIDs[rel.ObjectID], rel.TagID)
+	}
+	return tagIDs, nil
+}
+
+// candidateSession the questions are visible, not asked or answered by the user
+func (fr *feedRepo) candidateSession(ctx context.Context, userID string, inDays int) *xorm.Session {
+	session := fr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table(entity.Question{}.TableName()).
+		Where("question.status = ? AND question.show = ?", entity.QuestionStatusAvailable, entity.QuestionShow).
+		And("question.space_id = ?", entity.PublicSpaceID).
+		And("question.user_id != ?", userID).
+		And("question.id NOT IN (SELECT question_id FROM answer WHERE user_id = ?)", userID)
+	if inDays > 0 {
+		session.And("question.created_at > ?", time.Now().AddDate(0, 0, -inDays))
+	}
+	return session
+}
+
+// GetFeedCache get the ranked feed of the user from cache
+func (fr *feedRepo) GetFeedCache(ctx context.Context, userID string) (content string, exist bool, err error) {
+	content, exist, err = fr.data.Cache.GetString(ctx, constant.QuestionFeedCacheKeyPrefix+userID)
+	if err != nil {
+		return "", false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return content, exist, nil
+}
+
+// SetFeedCache set the ranked feed of the user to cache
+func (fr *feedRepo) SetFeedCache(ctx context.Context, userID, content string) (err error) {
+	err = fr.data.Cache.SetString(ctx, constant.QuestionFeedCacheKeyPrefix+userID, content,
+		constant.QuestionFeedCacheTime)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+// GetRecentViewedQuestionIDs get the question ids recently viewed by the user, the latest first
+func (fr *feedRepo) GetRecentViewedQuestionIDs(ctx context.Context, userID string) (questionIDs []string, err error) {
+	content, exist, err := fr.data.Cache.GetString(ctx, constant.QuestionRecentViewedCacheKeyPrefix+userID)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist || len(content) == 0 {
+		return make([]string, 0), nil
+	}
+	return strings.Split(content, ","), nil
+}
+
+// SetRecentViewedQuestionIDs set the question ids recently viewed by the user
+func (fr *feedRepo) SetRecentViewedQuestionIDs(ctx context.Context, userID string, questionIDs []string) (err error) {
+	err = fr.data.Cache.SetString(ctx, constant.QuestionRecentViewedCacheKeyPrefix+userID,
+		strings.Join(questionIDs, ","), constant.QuestionRecentViewedCacheTime)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package importer
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/importer"
+	"github.com/apache/answer/pkg/obj"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+type importerRepo struct {
+	data *data.Data
+}
+
+// NewImporterRepo new repository
+func NewImporterRepo(data *data.Data) importer.ImporterRepo {
+	return &importerRepo{
+		data: data,
+	}
+}
+
+// GetMapping get the id of the object imported from the external id
+func (ir *importerRepo) GetMapping(ctx context.Context, source, objectType, externalID string) (
+	objectID string, exist bool, err error) {
+	mapping := &entity.ImportMapping{}
+	exist, err = ir.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{
+		"source":      source,
+		"object_type": objectType,
+		"external_id": externalID,
+	}).Get(mapping)
+	if err != nil {
+		return "", false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return mapping.ObjectID, exist, nil
+}
+
+func (ir *importerRepo) AddMapping(ctx context.Context, mapping *entity.ImportMapping) (err error) {
+	mapping.TenantID = handler.GetTenantID(ctx)
+	_, err = ir.data.DB.Context(ctx).Insert(mapping)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// importTime the original time of the imported object
+type importTime struct {
+	CreatedAt      time.Time `xorm:"TIMESTAMP created_at"`
+	UpdatedAt      time.Time `xorm:"TIMESTAMP updated_at"`
+	PostUpdateTime time.Time `xorm:"TIMESTAMP post_update_time"`
+}
+
+// UpdatePostTime set the original time of the imported question, answer or comment
+func (ir *importerRepo) UpdatePostTime(ctx context.Context, objectID string, createdAt, updatedAt time.Time) (err error) {
+	tableName, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return errors.BadRequest(reason.ObjectNotFound)
+	}
+	cols := []string{"created_at", "updated_at"}
+	if tableName == constant.QuestionObjectType {
+		cols = append(cols, "post_update_time")
+	}
+	_, err = ir.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table(tableName).Where("id = ?", objectID).Cols(cols...).NoAutoTime().
+		Update(&importTime{CreatedAt: createdAt, UpdatedAt: updatedAt, PostUpdateTime: updatedAt})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateRevisionTime set the original time of the imported revision
+func (ir *importerRepo) UpdateRevisionTime(ctx context.Context, revisionID string, createdAt time.Time) (err error) {
+	_, err = ir.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table(entity.Revision{}.TableName()).Where("id = ?", revisionID).
+		Cols("created_at", "updated_at").NoAutoTime().
+		Update(&importTime{CreatedAt: createdAt, UpdatedAt: createdAt})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddActivities add the activities with their original time
+func (ir *importerRepo) AddActivities(ctx context.Context, activities []*entity.Activity) (err error) {
+	_, err = ir.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		for _, activity := range activities {
+			activity.TenantID = handler.GetTenantID(ctx)
+			activity.UpdatedAt = activity.CreatedAt
+			if _, err = session.NoAutoTime().Insert(activity); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ExistActivity check whether the user has any of the activities on the object
+func (ir *importerRepo) ExistActivity(ctx context.Context, objectID, userID string, activityTypes ...int) (
+	exist bool, err error) {
+	exist, err = ir.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"object_id": objectID, "user_id": userID}).
+		And(builder.In("activity_type", activityTypes)).Exist(&entity.Activity{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateVoteCount recount the votes of the object from the vote activities
+func (ir *importerRepo) UpdateVoteCount(ctx context.Context, objectID string, voteUpType, voteDownType int) (err error) {
+	tableName, err := obj.GetObjectTypeStrByObjectID(objectID)
+	if err != nil {
+		return errors.BadRequest(reason.ObjectNotFound)
+	}
+	cond := builder.Eq{"object_id": objectID, "cancelled": entity.ActivityAvailable}
+	voteUp, err := ir.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(cond).And(builder.Eq{"activity_type": voteUpType}).Count(&entity.Activity{})
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	var voteDown int64
+	if voteDownType > 0 {
+		voteDown, err = ir.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(cond).And(builder.Eq{"activity_type": voteDownType}).Count(&entity.Activity{})
+		if err != nil {
+			return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+	}
+	_, err = ir.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table(tableName).Where("id = ?", objectID).NoAutoTime().Update(map[string]any{"vote_count": voteUp - voteDown})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
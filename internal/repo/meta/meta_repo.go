@@ -23,6 +23,7 @@ import (
 	"context"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	metacommon "github.com/apache/answer/internal/service/meta_common"
@@ -45,6 +46,7 @@ func NewMetaRepo(data *data.Data) metacommon.MetaRepo {
 
 // AddMeta add meta
 func (mr *metaRepo) AddMeta(ctx context.Context, meta *entity.Meta) (err error) {
+	meta.TenantID = handler.GetTenantID(ctx)
 	_, err = mr.data.DB.Context(ctx).Insert(meta)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -54,7 +56,7 @@ func (mr *metaRepo) AddMeta(ctx context.Context, meta *entity.Meta) (err error)
 
 // RemoveMeta delete meta
 func (mr *metaRepo) RemoveMeta(ctx context.Context, id int) (err error) {
-	_, err = mr.data.DB.Context(ctx).ID(id).Delete(&entity.Meta{})
+	_, err = mr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Delete(&entity.Meta{})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -63,7 +65,7 @@ func (mr *metaRepo) RemoveMeta(ctx context.Context, id int) (err error) {
 
 // UpdateMeta update meta
 func (mr *metaRepo) UpdateMeta(ctx context.Context, meta *entity.Meta) (err error) {
-	_, err = mr.data.DB.Context(ctx).ID(meta.ID).Update(meta)
+	_, err = mr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(meta.ID).Update(meta)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -72,12 +74,12 @@ func (mr *metaRepo) UpdateMeta(ctx context.Context, meta *entity.Meta) (err erro
 
 // AddOrUpdateMetaByObjectIdAndKey if exist record with same objectID and key, update it. Or create a new one
 func (mr *metaRepo) AddOrUpdateMetaByObjectIdAndKey(ctx context.Context, objectId, key string, f func(*entity.Meta, bool) (*entity.Meta, error)) error {
-	_, err := mr.data.DB.Transaction(func(session *xorm.Session) (any, error) {
+	_, err := mr.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
 		session = session.Context(ctx)
 
 		// 1. acquire meta entity with target object id and key
 		metaEntity := &entity.Meta{}
-		exist, err := session.Where(builder.Eq{"object_id": objectId}.And(builder.Eq{"`key`": key})).ForUpdate().Get(metaEntity)
+		exist, err := session.Where(data.TenantCond(ctx)).And(builder.Eq{"object_id": objectId}.And(builder.Eq{"`key`": key})).ForUpdate().Get(metaEntity)
 		if err != nil {
 			return nil, err
 		}
@@ -91,6 +93,7 @@ func (mr *metaRepo) AddOrUpdateMetaByObjectIdAndKey(ctx context.Context, objectI
 		if exist {
 			_, err = session.ID(metaEntity.ID).Update(meta)
 		} else {
+			meta.TenantID = handler.GetTenantID(ctx)
 			_, err = session.Insert(meta)
 		}
 
@@ -103,7 +106,7 @@ func (mr *metaRepo) AddOrUpdateMetaByObjectIdAndKey(ctx context.Context, objectI
 func (mr *metaRepo) GetMetaByObjectIdAndKey(ctx context.Context, objectID, key string) (
 	meta *entity.Meta, exist bool, err error) {
 	meta = &entity.Meta{}
-	exist, err = mr.data.DB.Context(ctx).Where(builder.Eq{"object_id": objectID}.And(builder.Eq{"`key`": key})).Desc("created_at").Get(meta)
+	exist, err = mr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"object_id": objectID}.And(builder.Eq{"`key`": key})).Desc("created_at").Get(meta)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -113,7 +116,7 @@ func (mr *metaRepo) GetMetaByObjectIdAndKey(ctx context.Context, objectID, key s
 // GetMetaList get meta list all
 func (mr *metaRepo) GetMetaList(ctx context.Context, meta *entity.Meta) (metaList []*entity.Meta, err error) {
 	metaList = make([]*entity.Meta, 0)
-	err = mr.data.DB.Context(ctx).Find(&metaList, meta)
+	err = mr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Find(&metaList, meta)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -23,10 +23,12 @@ import (
 	"context"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/plugin_common"
 	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
 )
 
 type pluginConfigRepo struct {
@@ -42,15 +44,19 @@ func NewPluginConfigRepo(data *data.Data) plugin_common.PluginConfigRepo {
 
 func (ur *pluginConfigRepo) SavePluginConfig(ctx context.Context, pluginSlugName, configValue string) (err error) {
 	old := &entity.PluginConfig{PluginSlugName: pluginSlugName}
-	exist, err := ur.data.DB.Context(ctx).Get(old)
+	exist, err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Get(old)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	if exist {
 		old.Value = configValue
-		_, err = ur.data.DB.Context(ctx).ID(old.ID).Update(old)
+		_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(old.ID).Update(old)
 	} else {
-		_, err = ur.data.DB.Context(ctx).Insert(&entity.PluginConfig{PluginSlugName: pluginSlugName, Value: configValue})
+		_, err = ur.data.DB.Context(ctx).Insert(&entity.PluginConfig{
+			TenantID:       handler.GetTenantID(ctx),
+			PluginSlugName: pluginSlugName,
+			Value:          configValue,
+		})
 	}
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -60,7 +66,17 @@ func (ur *pluginConfigRepo) SavePluginConfig(ctx context.Context, pluginSlugName
 
 func (ur *pluginConfigRepo) GetPluginConfigAll(ctx context.Context) (pluginConfigs []*entity.PluginConfig, err error) {
 	pluginConfigs = make([]*entity.PluginConfig, 0)
-	err = ur.data.DB.Context(ctx).Find(&pluginConfigs)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Find(&pluginConfigs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return pluginConfigs, err
+}
+
+// GetTenantPluginConfigAll get the plugin configs of all tenants except the primary site
+func (ur *pluginConfigRepo) GetTenantPluginConfigAll(ctx context.Context) (pluginConfigs []*entity.PluginConfig, err error) {
+	pluginConfigs = make([]*entity.PluginConfig, 0)
+	err = ur.data.DB.Context(ctx).Where(builder.Neq{"tenant_id": entity.DefaultTenantID}).Find(&pluginConfigs)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
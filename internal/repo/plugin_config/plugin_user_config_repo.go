@@ -26,6 +26,7 @@ import (
 	"xorm.io/xorm"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/plugin_common"
@@ -45,13 +46,13 @@ func NewPluginUserConfigRepo(data *data.Data) plugin_common.PluginUserConfigRepo
 
 func (ur *pluginUserConfigRepo) SaveUserPluginConfig(ctx context.Context, userID string,
 	pluginSlugName, configValue string) (err error) {
-	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (any, error) {
+	_, err = ur.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
 		session = session.Context(ctx)
 		old := &entity.PluginUserConfig{
 			UserID:         userID,
 			PluginSlugName: pluginSlugName,
 		}
-		exist, err := session.Get(old)
+		exist, err := session.Where(data.TenantCond(ctx)).Get(old)
 		if err != nil {
 			return nil, err
 		}
@@ -60,6 +61,7 @@ func (ur *pluginUserConfigRepo) SaveUserPluginConfig(ctx context.Context, userID
 			_, err = session.ID(old.ID).Update(old)
 		} else {
 			_, err = session.Insert(&entity.PluginUserConfig{
+				TenantID:       handler.GetTenantID(ctx),
 				UserID:         userID,
 				PluginSlugName: pluginSlugName,
 				Value:          configValue,
@@ -82,7 +84,7 @@ func (ur *pluginUserConfigRepo) GetPluginUserConfig(ctx context.Context, userID,
 		UserID:         userID,
 		PluginSlugName: pluginSlugName,
 	}
-	exist, err = ur.data.DB.Context(ctx).Get(pluginUserConfig)
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Get(pluginUserConfig)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -92,7 +94,7 @@ func (ur *pluginUserConfigRepo) GetPluginUserConfig(ctx context.Context, userID,
 func (ur *pluginUserConfigRepo) GetPluginUserConfigPage(ctx context.Context, page, pageSize int) (
 	pluginUserConfigs []*entity.PluginUserConfig, total int64, err error) {
 	pluginUserConfigs = make([]*entity.PluginUserConfig, 0)
-	total, err = pager.Help(page, pageSize, &pluginUserConfigs, &entity.PluginUserConfig{}, ur.data.DB.Context(ctx))
+	total, err = pager.Help(page, pageSize, &pluginUserConfigs, &entity.PluginUserConfig{}, ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)))
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -100,7 +102,7 @@ func (ur *pluginUserConfigRepo) GetPluginUserConfigPage(ctx context.Context, pag
 }
 
 func (ur *pluginUserConfigRepo) DeleteUserPluginConfig(ctx context.Context, userID string) (err error) {
-	_, err = ur.data.DB.Context(ctx).Where("user_id = ?", userID).Delete(&entity.PluginUserConfig{})
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Delete(&entity.PluginUserConfig{})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -30,12 +30,17 @@ import (
 	"github.com/apache/answer/internal/repo/badge"
 	"github.com/apache/answer/internal/repo/badge_award"
 	"github.com/apache/answer/internal/repo/badge_group"
+	"github.com/apache/answer/internal/repo/bounty"
 	"github.com/apache/answer/internal/repo/captcha"
+	"github.com/apache/answer/internal/repo/close_vote"
 	"github.com/apache/answer/internal/repo/collection"
 	"github.com/apache/answer/internal/repo/comment"
 	"github.com/apache/answer/internal/repo/config"
+	"github.com/apache/answer/internal/repo/draft"
 	"github.com/apache/answer/internal/repo/export"
+	"github.com/apache/answer/internal/repo/feed"
 	"github.com/apache/answer/internal/repo/file_record"
+	"github.com/apache/answer/internal/repo/importer"
 	"github.com/apache/answer/internal/repo/limit"
 	"github.com/apache/answer/internal/repo/meta"
 	"github.com/apache/answer/internal/repo/notification"
@@ -47,12 +52,21 @@ import (
 	"github.com/apache/answer/internal/repo/review"
 	"github.com/apache/answer/internal/repo/revision"
 	"github.com/apache/answer/internal/repo/role"
+	"github.com/apache/answer/internal/repo/saved_search"
+	"github.com/apache/answer/internal/repo/scheduled_post"
+	"github.com/apache/answer/internal/repo/scim"
 	"github.com/apache/answer/internal/repo/search_common"
 	"github.com/apache/answer/internal/repo/site_info"
+	"github.com/apache/answer/internal/repo/space"
+	"github.com/apache/answer/internal/repo/sso"
 	"github.com/apache/answer/internal/repo/tag"
 	"github.com/apache/answer/internal/repo/tag_common"
+	"github.com/apache/answer/internal/repo/tag_suggestion"
+	"github.com/apache/answer/internal/repo/tenant"
+	"github.com/apache/answer/internal/repo/two_factor"
 	"github.com/apache/answer/internal/repo/unique"
 	"github.com/apache/answer/internal/repo/user"
+	"github.com/apache/answer/internal/repo/user_data"
 	"github.com/apache/answer/internal/repo/user_external_login"
 	"github.com/apache/answer/internal/repo/user_notification_config"
 	"github.com/google/wire"
@@ -62,7 +76,8 @@ import (
 var ProviderSetRepo = wire.NewSet(
 	data.NewData,
 	data.NewDB,
-	data.NewCache,
+	data.NewInvalidationBus,
+	data.NewCacheWithBus,
 	comment.NewCommentRepo,
 	comment.NewCommentCommonRepo,
 	captcha.NewCaptchaRepo,
@@ -86,6 +101,8 @@ var ProviderSetRepo = wire.NewSet(
 	tag.NewTagRepo,
 	tag_common.NewTagCommonRepo,
 	tag.NewTagRelRepo,
+	tag.NewTagOwnerRepo,
+	tag.NewTagSettingRepo,
 	collection.NewCollectionRepo,
 	collection.NewCollectionGroupRepo,
 	auth.NewAuthRepo,
@@ -100,6 +117,7 @@ var ProviderSetRepo = wire.NewSet(
 	role.NewUserRoleRelRepo,
 	role.NewRolePowerRelRepo,
 	role.NewPowerRepo,
+	role.NewRoleTagRelRepo,
 	user_external_login.NewUserExternalLoginRepo,
 	plugin_config.NewPluginConfigRepo,
 	user_notification_config.NewUserNotificationConfigRepo,
@@ -113,4 +131,18 @@ var ProviderSetRepo = wire.NewSet(
 	file_record.NewFileRecordRepo,
 	api_key.NewAPIKeyRepo,
 	ai_conversation.NewAIConversationRepo,
+	feed.NewFeedRepo,
+	saved_search.NewSavedSearchRepo,
+	tag_suggestion.NewTagSuggestionRepo,
+	close_vote.NewCloseVoteRepo,
+	bounty.NewBountyRepo,
+	draft.NewDraftRepo,
+	scheduled_post.NewScheduledPostRepo,
+	space.NewSpaceRepo,
+	tenant.NewTenantRepo,
+	sso.NewSSOProviderRepo,
+	scim.NewSCIMRepo,
+	two_factor.NewTwoFactorRepo,
+	importer.NewImporterRepo,
+	user_data.NewUserDataRepo,
 )
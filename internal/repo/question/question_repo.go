@@ -68,6 +68,7 @@ func (qr *questionRepo) AddQuestion(ctx context.Context, question *entity.Questi
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
+	question.TenantID = handler.GetTenantID(ctx)
 	_, err = qr.data.DB.Context(ctx).Insert(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -81,7 +82,7 @@ func (qr *questionRepo) AddQuestion(ctx context.Context, question *entity.Questi
 // RemoveQuestion delete question
 func (qr *questionRepo) RemoveQuestion(ctx context.Context, id string) (err error) {
 	id = uid.DeShortID(id)
-	_, err = qr.data.DB.Context(ctx).Where("id =?", id).Delete(&entity.Question{})
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", id).Delete(&entity.Question{})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -91,7 +92,7 @@ func (qr *questionRepo) RemoveQuestion(ctx context.Context, id string) (err erro
 // UpdateQuestion update question
 func (qr *questionRepo) UpdateQuestion(ctx context.Context, question *entity.Question, cols []string) (err error) {
 	question.ID = uid.DeShortID(question.ID)
-	_, err = qr.data.DB.Context(ctx).Where("id =?", question.ID).Cols(cols...).Update(question)
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", question.ID).Cols(cols...).Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -105,7 +106,7 @@ func (qr *questionRepo) UpdateQuestion(ctx context.Context, question *entity.Que
 func (qr *questionRepo) UpdatePvCount(ctx context.Context, questionID string) (err error) {
 	questionID = uid.DeShortID(questionID)
 	question := &entity.Question{}
-	_, err = qr.data.DB.Context(ctx).Where("id =?", questionID).Incr("view_count", 1).Update(question)
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", questionID).Incr("view_count", 1).Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -117,7 +118,7 @@ func (qr *questionRepo) UpdateAnswerCount(ctx context.Context, questionID string
 	questionID = uid.DeShortID(questionID)
 	question := &entity.Question{}
 	question.AnswerCount = num
-	_, err = qr.data.DB.Context(ctx).Where("id =?", questionID).Cols("answer_count").Update(question)
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", questionID).Cols("answer_count").Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -127,7 +128,7 @@ func (qr *questionRepo) UpdateAnswerCount(ctx context.Context, questionID string
 
 func (qr *questionRepo) UpdateCollectionCount(ctx context.Context, questionID string) (count int64, err error) {
 	questionID = uid.DeShortID(questionID)
-	_, err = qr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err = qr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		session = session.Context(ctx)
 		count, err = session.Count(&entity.Collection{ObjectID: questionID})
 		if err != nil {
@@ -149,7 +150,7 @@ func (qr *questionRepo) UpdateCollectionCount(ctx context.Context, questionID st
 
 func (qr *questionRepo) UpdateQuestionStatus(ctx context.Context, questionID string, status int) (err error) {
 	questionID = uid.DeShortID(questionID)
-	_, err = qr.data.DB.Context(ctx).ID(questionID).Cols("status").Update(&entity.Question{Status: status})
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(questionID).Cols("status").Update(&entity.Question{Status: status})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -159,7 +160,7 @@ func (qr *questionRepo) UpdateQuestionStatus(ctx context.Context, questionID str
 
 func (qr *questionRepo) UpdateQuestionStatusWithOutUpdateTime(ctx context.Context, question *entity.Question) (err error) {
 	question.ID = uid.DeShortID(question.ID)
-	_, err = qr.data.DB.Context(ctx).Where("id =?", question.ID).Cols("status").Update(question)
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", question.ID).Cols("status").Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -170,7 +171,7 @@ func (qr *questionRepo) UpdateQuestionStatusWithOutUpdateTime(ctx context.Contex
 func (qr *questionRepo) DeletePermanentlyQuestions(ctx context.Context) (err error) {
 	// get all deleted question ids
 	ids := make([]string, 0)
-	err = qr.data.DB.Context(ctx).Select("id").Table(new(entity.Question).TableName()).
+	err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Select("id").Table(new(entity.Question).TableName()).
 		Where("status = ?", entity.QuestionStatusDeleted).Find(&ids)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -180,12 +181,12 @@ func (qr *questionRepo) DeletePermanentlyQuestions(ctx context.Context) (err err
 	}
 
 	// delete all revisions permanently
-	_, err = qr.data.DB.Context(ctx).In("object_id", ids).Delete(&entity.Revision{})
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("object_id", ids).Delete(&entity.Revision{})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 
-	_, err = qr.data.DB.Context(ctx).Where("status = ?", entity.QuestionStatusDeleted).Delete(&entity.Question{})
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ?", entity.QuestionStatusDeleted).Delete(&entity.Question{})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -194,7 +195,7 @@ func (qr *questionRepo) DeletePermanentlyQuestions(ctx context.Context) (err err
 
 func (qr *questionRepo) RecoverQuestion(ctx context.Context, questionID string) (err error) {
 	questionID = uid.DeShortID(questionID)
-	_, err = qr.data.DB.Context(ctx).ID(questionID).Cols("status").Update(&entity.Question{Status: entity.QuestionStatusAvailable})
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(questionID).Cols("status").Update(&entity.Question{Status: entity.QuestionStatusAvailable})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -204,7 +205,7 @@ func (qr *questionRepo) RecoverQuestion(ctx context.Context, questionID string)
 
 func (qr *questionRepo) UpdateQuestionOperation(ctx context.Context, question *entity.Question) (err error) {
 	question.ID = uid.DeShortID(question.ID)
-	_, err = qr.data.DB.Context(ctx).Where("id =?", question.ID).Cols("pin", "show").Update(question)
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", question.ID).Cols("pin", "show").Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -213,7 +214,7 @@ func (qr *questionRepo) UpdateQuestionOperation(ctx context.Context, question *e
 
 func (qr *questionRepo) UpdateAccepted(ctx context.Context, question *entity.Question) (err error) {
 	question.ID = uid.DeShortID(question.ID)
-	_, err = qr.data.DB.Context(ctx).Where("id =?", question.ID).Cols("accepted_answer_id").Update(question)
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", question.ID).Cols("accepted_answer_id").Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -223,7 +224,7 @@ func (qr *questionRepo) UpdateAccepted(ctx context.Context, question *entity.Que
 
 func (qr *questionRepo) UpdateLastAnswer(ctx context.Context, question *entity.Question) (err error) {
 	question.ID = uid.DeShortID(question.ID)
-	_, err = qr.data.DB.Context(ctx).Where("id =?", question.ID).Cols("last_answer_id").Update(question)
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", question.ID).Cols("last_answer_id").Update(question)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -238,7 +239,7 @@ func (qr *questionRepo) GetQuestion(ctx context.Context, id string) (
 	id = uid.DeShortID(id)
 	question = &entity.Question{}
 	question.ID = id
-	exist, err = qr.data.DB.Context(ctx).Where("id = ?", id).Get(question)
+	exist, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", id).Get(question)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -252,8 +253,8 @@ func (qr *questionRepo) GetQuestion(ctx context.Context, id string) (
 func (qr *questionRepo) GetQuestionsByTitle(ctx context.Context, title string, pageSize int) (
 	questionList []*entity.Question, err error) {
 	questionList = make([]*entity.Question, 0)
-	session := qr.data.DB.Context(ctx)
-	session.Where("status != ?", entity.QuestionStatusDeleted)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
+	session.Where(builder.Lt{"status": entity.QuestionStatusDeleted})
 	session.Where("title like ?", "%"+title+"%")
 	session.Limit(pageSize)
 	err = session.Find(&questionList)
@@ -273,7 +274,7 @@ func (qr *questionRepo) FindByID(ctx context.Context, id []string) (questionList
 		id[key] = uid.DeShortID(itemID)
 	}
 	questionList = make([]*entity.Question, 0)
-	err = qr.data.DB.Context(ctx).Table("question").In("id", id).Find(&questionList)
+	err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table("question").In("id", id).Find(&questionList)
 	if err != nil {
 		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -289,7 +290,7 @@ func (qr *questionRepo) FindByID(ctx context.Context, id []string) (questionList
 func (qr *questionRepo) GetQuestionList(ctx context.Context, question *entity.Question) (questionList []*entity.Question, err error) {
 	question.ID = uid.DeShortID(question.ID)
 	questionList = make([]*entity.Question, 0)
-	err = qr.data.DB.Context(ctx).Find(&questionList, question)
+	err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Find(&questionList, question)
 	if err != nil {
 		return questionList, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -300,7 +301,7 @@ func (qr *questionRepo) GetQuestionList(ctx context.Context, question *entity.Qu
 }
 
 func (qr *questionRepo) GetQuestionCount(ctx context.Context) (count int64, err error) {
-	session := qr.data.DB.Context(ctx)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	session.Where(builder.Lt{"status": entity.QuestionStatusDeleted})
 	count, err = session.Count(&entity.Question{Show: entity.QuestionShow})
 	if err != nil {
@@ -310,7 +311,7 @@ func (qr *questionRepo) GetQuestionCount(ctx context.Context) (count int64, err
 }
 
 func (qr *questionRepo) GetUnansweredQuestionCount(ctx context.Context) (count int64, err error) {
-	session := qr.data.DB.Context(ctx)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	session.Where(builder.Lt{"status": entity.QuestionStatusDeleted}).
 		And(builder.Eq{"answer_count": 0})
 	count, err = session.Count(&entity.Question{Show: entity.QuestionShow})
@@ -321,7 +322,7 @@ func (qr *questionRepo) GetUnansweredQuestionCount(ctx context.Context) (count i
 }
 
 func (qr *questionRepo) GetResolvedQuestionCount(ctx context.Context) (count int64, err error) {
-	session := qr.data.DB.Context(ctx)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	session.Where(builder.Lt{"status": entity.QuestionStatusDeleted}).
 		And(builder.Neq{"answer_count": 0}).
 		And(builder.Neq{"accepted_answer_id": 0})
@@ -333,7 +334,7 @@ func (qr *questionRepo) GetResolvedQuestionCount(ctx context.Context) (count int
 }
 
 func (qr *questionRepo) GetUserQuestionCount(ctx context.Context, userID string, show int) (count int64, err error) {
-	session := qr.data.DB.Context(ctx)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	session.Where(builder.Lt{"status": entity.QuestionStatusDeleted})
 	count, err = session.Count(&entity.Question{UserID: userID, Show: show})
 	if err != nil {
@@ -357,10 +358,11 @@ func (qr *questionRepo) SitemapQuestions(ctx context.Context, page, pageSize int
 
 	// get sitemap data from db
 	rows := make([]*entity.Question, 0)
-	session := qr.data.DB.Context(ctx)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	session.Select("id,title,created_at,post_update_time")
 	session.Where("`show` = ?", entity.QuestionShow)
 	session.Where("status = ? OR status = ?", entity.QuestionStatusAvailable, entity.QuestionStatusClosed)
+	session.Where("space_id = ?", entity.PublicSpaceID)
 	session.Limit(pageSize, page*pageSize)
 	session.Asc("created_at")
 	err = session.Find(&rows)
@@ -393,12 +395,12 @@ func (qr *questionRepo) SitemapQuestions(ctx context.Context, page, pageSize int
 
 // GetQuestionPage query question page
 func (qr *questionRepo) GetQuestionPage(ctx context.Context, page, pageSize int,
-	tagIDs []string, userID, orderCond string, inDays int, showHidden, showPending bool) (
+	tagIDs []string, userID, orderCond string, inDays int, showHidden, showPending bool, spaceIDs []int) (
 	questionList []*entity.Question, total int64, err error) {
 	questionList = make([]*entity.Question, 0)
-	session := qr.data.DB.Context(ctx)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx, "question"))
 	status := []int{entity.QuestionStatusAvailable}
-	if orderCond != "unanswered" {
+	if orderCond != "unanswered" && orderCond != "featured" {
 		status = append(status, entity.QuestionStatusClosed)
 	}
 	if showPending {
@@ -406,6 +408,9 @@ func (qr *questionRepo) GetQuestionPage(ctx context.Context, page, pageSize int,
 	}
 	session.Select("question.*")
 	session.In("question.status", status)
+	if len(spaceIDs) > 0 {
+		session.In("question.space_id", spaceIDs)
+	}
 	if len(tagIDs) > 0 {
 		session.Join("LEFT", "tag_rel", "question.id = tag_rel.object_id")
 		session.In("tag_rel.tag_id", tagIDs)
@@ -441,6 +446,9 @@ func (qr *questionRepo) GetQuestionPage(ctx context.Context, page, pageSize int,
 		session.OrderBy("question.pin desc,question.created_at DESC")
 	case "frequent":
 		session.OrderBy("question.pin DESC, question.linked_count DESC, question.updated_at DESC")
+	case "featured":
+		session.And("question.bounty_amount > 0")
+		session.OrderBy("question.bounty_amount DESC, question.created_at DESC")
 	}
 
 	session.GroupBy("question.id")
@@ -492,8 +500,11 @@ func (qr *questionRepo) GetRecommendQuestionPageByTags(ctx context.Context, user
 		}
 	}
 
+	// the tenant is checked after the followed questions are or-ed in
 	session.
+		And(data.TenantCond(ctx, "question")).
 		And("question.show = ? and question.status = ?", entity.QuestionShow, entity.QuestionStatusAvailable).
+		And("question.space_id = ?", entity.PublicSpaceID).
 		Distinct("question.id").
 		OrderBy(orderBySQL)
 
@@ -515,7 +526,7 @@ func (qr *questionRepo) AdminQuestionPage(ctx context.Context, search *schema.Ad
 	var (
 		count   int64
 		err     error
-		session = qr.data.DB.Context(ctx).Table("question")
+		session = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table("question")
 	)
 
 	session.Where(builder.Eq{
@@ -588,7 +599,7 @@ func (qr *questionRepo) UpdateSearch(ctx context.Context, questionID string) (er
 		s = search
 		return nil
 	})
-	if s == nil {
+	if s == nil || !plugin.StatusManager.IsEnabledFor(ctx, s.Info().SlugName) {
 		return
 	}
 	questionID = uid.DeShortID(questionID)
@@ -599,13 +610,21 @@ func (qr *questionRepo) UpdateSearch(ctx context.Context, questionID string) (er
 	if err != nil {
 		return err
 	}
+	// the scheduled question is not searchable until it is published
+	if question.Status == entity.QuestionStatusScheduled {
+		return
+	}
+	// the questions of the spaces are searched by the database only, they are never sent to the search plugin
+	if question.SpaceID != entity.PublicSpaceID {
+		return
+	}
 
 	// get tags
 	var (
 		tagListList = make([]*entity.TagRel, 0)
 		tags        = make([]string, 0)
 	)
-	session := qr.data.DB.Context(ctx).Where("object_id = ?", questionID)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", questionID)
 	session.Where("status = ?", entity.TagRelStatusAvailable)
 	err = session.Find(&tagListList)
 	if err != nil {
@@ -615,6 +634,7 @@ func (qr *questionRepo) UpdateSearch(ctx context.Context, questionID string) (er
 		tags = append(tags, tag.TagID)
 	}
 	content := &plugin.SearchContent{
+		TenantID:    question.TenantID,
 		ObjectID:    questionID,
 		Title:       question.Title,
 		Type:        constant.QuestionObjectType,
@@ -637,7 +657,7 @@ func (qr *questionRepo) UpdateSearch(ctx context.Context, questionID string) (er
 func (qr *questionRepo) RemoveAllUserQuestion(ctx context.Context, userID string) (err error) {
 	// get all question id that need to be deleted
 	questionIDs := make([]string, 0)
-	session := qr.data.DB.Context(ctx).Where("user_id = ?", userID)
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID)
 	session.Where("status != ?", entity.QuestionStatusDeleted)
 	err = session.Select("id").Table("question").Find(&questionIDs)
 	if err != nil {
@@ -650,7 +670,7 @@ func (qr *questionRepo) RemoveAllUserQuestion(ctx context.Context, userID string
 	log.Infof("find %d questions need to be deleted for user %s", len(questionIDs), userID)
 
 	// delete all question
-	session = qr.data.DB.Context(ctx).Where("user_id = ?", userID)
+	session = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID)
 	session.Where("status != ?", entity.QuestionStatusDeleted)
 	_, err = session.Cols("status", "updated_at").Update(&entity.Question{
 		UpdatedAt: time.Now(),
@@ -676,20 +696,24 @@ func (qr *questionRepo) LinkQuestion(ctx context.Context, link ...*entity.Questi
 		l.ToQuestionID = uid.DeShortID(l.ToQuestionID)
 		l.FromAnswerID = uid.DeShortID(l.FromAnswerID)
 		l.ToAnswerID = uid.DeShortID(l.ToAnswerID)
+		if l.LinkType == 0 {
+			l.LinkType = entity.QuestionLinkTypeReference
+		}
 		links = append(links, l)
 	}
 	// Retrieve existing records from the database
 	var existLinks []*entity.QuestionLink
-	session := qr.data.DB.Context(ctx)
+	linkCond := builder.NewCond()
 	for _, link := range links {
-		session = session.Or(builder.Eq{
+		linkCond = linkCond.Or(builder.Eq{
 			"from_question_id": link.FromQuestionID,
 			"to_question_id":   link.ToQuestionID,
 			"from_answer_id":   link.FromAnswerID,
 			"to_answer_id":     link.ToAnswerID,
+			"link_type":        link.LinkType,
 		})
 	}
-	err = session.Find(&existLinks)
+	err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).And(linkCond).Find(&existLinks)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -697,14 +721,14 @@ func (qr *questionRepo) LinkQuestion(ctx context.Context, link ...*entity.Questi
 	// Optimize separation of records that need to be updated or inserted using a map
 	existMap := make(map[string]*entity.QuestionLink)
 	for _, el := range existLinks {
-		key := fmt.Sprintf("%s:%s:%s:%s", el.FromQuestionID, el.ToQuestionID, el.FromAnswerID, el.ToAnswerID)
+		key := fmt.Sprintf("%s:%s:%s:%s:%d", el.FromQuestionID, el.ToQuestionID, el.FromAnswerID, el.ToAnswerID, el.LinkType)
 		existMap[key] = el
 	}
 
 	var updateLinks []*entity.QuestionLink
 	var insertLinks []*entity.QuestionLink
 	for _, link := range links {
-		key := fmt.Sprintf("%s:%s:%s:%s", link.FromQuestionID, link.ToQuestionID, link.FromAnswerID, link.ToAnswerID)
+		key := fmt.Sprintf("%s:%s:%s:%s:%d", link.FromQuestionID, link.ToQuestionID, link.FromAnswerID, link.ToAnswerID, link.LinkType)
 		if el, exist := existMap[key]; exist {
 			if el.Status == entity.QuestionLinkStatusDeleted {
 				el.Status = entity.QuestionLinkStatusAvailable
@@ -712,6 +736,7 @@ func (qr *questionRepo) LinkQuestion(ctx context.Context, link ...*entity.Questi
 				updateLinks = append(updateLinks, el)
 			}
 		} else {
+			link.TenantID = handler.GetTenantID(ctx)
 			link.Status = entity.QuestionLinkStatusAvailable
 			link.CreatedAt = time.Now()
 			link.UpdatedAt = time.Now()
@@ -722,7 +747,7 @@ func (qr *questionRepo) LinkQuestion(ctx context.Context, link ...*entity.Questi
 	// Batch update
 	if len(updateLinks) > 0 {
 		for _, link := range updateLinks {
-			_, err = qr.data.DB.Context(ctx).ID(link.ID).Cols("status").Update(&entity.QuestionLink{Status: entity.QuestionLinkStatusAvailable})
+			_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(link.ID).Cols("status").Update(&entity.QuestionLink{Status: entity.QuestionLinkStatusAvailable})
 			if err != nil {
 				return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 			}
@@ -743,7 +768,7 @@ func (qr *questionRepo) LinkQuestion(ctx context.Context, link ...*entity.Questi
 // UpdateQuestionLinkCount update question link count
 func (qr *questionRepo) UpdateQuestionLinkCount(ctx context.Context, questionID string) (err error) {
 	// count the number of links
-	count, err := qr.data.DB.Context(ctx).
+	count, err := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).
 		Where("to_question_id = ?", questionID).
 		Where("status = ?", entity.QuestionLinkStatusAvailable).
 		Count(&entity.QuestionLink{})
@@ -752,7 +777,7 @@ func (qr *questionRepo) UpdateQuestionLinkCount(ctx context.Context, questionID
 	}
 
 	// update the number of links
-	_, err = qr.data.DB.Context(ctx).ID(questionID).
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(questionID).
 		Cols("linked_count").Update(&entity.Question{LinkedCount: int(count)})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -764,7 +789,7 @@ func (qr *questionRepo) UpdateQuestionLinkCount(ctx context.Context, questionID
 func (qr *questionRepo) GetLinkedQuestionIDs(ctx context.Context, questionID string, status int) (
 	questionIDs []string, err error) {
 	questionIDs = make([]string, 0)
-	err = qr.data.DB.Context(ctx).
+	err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).
 		Select("to_question_id").
 		Table(new(entity.QuestionLink).TableName()).
 		Where("from_question_id = ?", questionID).
@@ -792,7 +817,7 @@ func (qr *questionRepo) UpdateQuestionLinkStatus(ctx context.Context, status int
 		return nil
 	}
 
-	session := qr.data.DB.Context(ctx).Cols("status")
+	linkCond := builder.NewCond()
 	for _, link := range links {
 		eq := builder.Eq{}
 		if link.FromQuestionID != "" {
@@ -807,9 +832,14 @@ func (qr *questionRepo) UpdateQuestionLinkStatus(ctx context.Context, status int
 		if link.ToAnswerID != "" {
 			eq["to_answer_id"] = uid.DeShortID(link.ToAnswerID)
 		}
-		session = session.Or(eq)
+		// the link type is optional, all types of links are updated if it is not specified
+		if link.LinkType != 0 {
+			eq["link_type"] = link.LinkType
+		}
+		linkCond = linkCond.Or(eq)
 	}
-	_, err = session.Update(&entity.QuestionLink{Status: status})
+	_, err = qr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).And(linkCond).Cols("status").
+		Update(&entity.QuestionLink{Status: status})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -827,12 +857,14 @@ func (qr *questionRepo) GetQuestionLink(ctx context.Context, page, pageSize int,
 		).WithStack()
 	}
 
-	session := qr.data.DB.Context(ctx).
+	session := qr.data.DB.Context(ctx).Where(data.TenantCond(ctx, "question_link")).
 		Table("question_link").
 		Join("INNER", "question", "question_link.from_question_id = question.id").
 		Where("question_link.to_question_id = ? AND question.show = ?", questionID, entity.QuestionShow).
 		Distinct("question.id").
 		Where("question_link.status = ?", entity.QuestionLinkStatusAvailable).
+		// only the questions in the same space as the linked question are listed
+		Where("question.space_id = (SELECT q.space_id FROM question q WHERE q.id = ?)", questionID).
 		Select("question.*").
 		In("question.status", questionStatus)
 
@@ -26,6 +26,7 @@ import (
 	"github.com/apache/answer/internal/base/pager"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/activity_type"
 	"github.com/apache/answer/internal/service/config"
 	"github.com/apache/answer/internal/service/rank"
 	"github.com/apache/answer/plugin"
@@ -203,7 +204,12 @@ func (ur *UserRankRepo) UserRankPage(ctx context.Context, userID string, page, p
 ) {
 	rankPage = make([]*entity.Activity, 0)
 
-	session := ur.data.DB.Context(ctx).Where(builder.Eq{"has_rank": 1}.And(builder.Eq{"cancelled": 0})).And(builder.Gt{"`rank`": 0})
+	// the reputation offered as a bounty is the only deduction shown to the user
+	rankCond := builder.Or(builder.Gt{"`rank`": 0})
+	if bountyOffered, err := ur.configService.GetIDByKey(ctx, activity_type.QuestionBountyOffered); err == nil {
+		rankCond = rankCond.Or(builder.Eq{"activity_type": bountyOffered})
+	}
+	session := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"has_rank": 1}.And(builder.Eq{"cancelled": 0})).And(rankCond)
 	session.Desc("created_at")
 
 	cond := &entity.Activity{UserID: userID}
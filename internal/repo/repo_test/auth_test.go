@@ -108,3 +108,60 @@ func Test_authRepo_RemoveAdminUserCacheInfo(t *testing.T) {
 	require.NoError(t, err)
 	assert.Nil(t, userInfo)
 }
+
+func Test_authRepo_UserSession(t *testing.T) {
+	authRepo := auth.NewAuthRepo(testDataSource)
+
+	info := &entity.UserSessionInfo{CreatedAt: 1, LastSeenAt: 2, IP: "127.0.0.1", UserAgent: "curl/8.5.0"}
+	err := authRepo.SetUserSession(context.TODO(), accessToken, info)
+	require.NoError(t, err)
+
+	got, err := authRepo.GetUserSession(context.TODO(), accessToken)
+	require.NoError(t, err)
+	assert.Equal(t, info, got)
+
+	err = authRepo.RemoveUserCacheInfo(context.TODO(), accessToken)
+	require.NoError(t, err)
+
+	got, err = authRepo.GetUserSession(context.TODO(), accessToken)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func Test_authRepo_RemoveUserTokenMapping(t *testing.T) {
+	authRepo := auth.NewAuthRepo(testDataSource)
+
+	err := authRepo.AddUserTokenMapping(context.TODO(), userID, accessToken)
+	require.NoError(t, err)
+	err = authRepo.AddUserTokenMapping(context.TODO(), userID, "session-token")
+	require.NoError(t, err)
+
+	err = authRepo.RemoveUserTokenMapping(context.TODO(), userID, accessToken)
+	require.NoError(t, err)
+
+	tokens, err := authRepo.GetUserTokens(context.TODO(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"session-token"}, tokens)
+
+	authRepo.RemoveUserTokens(context.TODO(), userID, "")
+}
+
+func Test_authRepo_RemoveUserTokensExceptRemain(t *testing.T) {
+	authRepo := auth.NewAuthRepo(testDataSource)
+
+	err := authRepo.AddUserTokenMapping(context.TODO(), userID, accessToken)
+	require.NoError(t, err)
+	err = authRepo.AddUserTokenMapping(context.TODO(), userID, "other-token")
+	require.NoError(t, err)
+
+	authRepo.RemoveUserTokens(context.TODO(), userID, accessToken)
+
+	tokens, err := authRepo.GetUserTokens(context.TODO(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{accessToken}, tokens)
+
+	authRepo.RemoveUserTokens(context.TODO(), userID, "")
+	tokens, err = authRepo.GetUserTokens(context.TODO(), userID)
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/bounty"
+	"github.com/apache/answer/internal/repo/config"
+	"github.com/apache/answer/internal/repo/rank"
+	"github.com/apache/answer/internal/schema"
+	config2 "github.com/apache/answer/internal/service/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_bountyRepo_AddAndAwardBounty(t *testing.T) {
+	ctx := context.TODO()
+	configService := config2.NewConfigService(config.NewConfigRepo(testDataSource))
+	bountyRepo := bounty.NewBountyRepo(testDataSource, rank.NewUserRankRepo(testDataSource, configService))
+
+	offerUser := &entity.User{Username: "bounty_offer", EMail: "bounty_offer@example.com", Rank: 100,
+		Status: entity.UserStatusAvailable, MailStatus: entity.EmailStatusAvailable}
+	answerUser := &entity.User{Username: "bounty_answer", EMail: "bounty_answer@example.com", Rank: 10,
+		Status: entity.UserStatusAvailable, MailStatus: entity.EmailStatusAvailable}
+	_, err := testDataSource.DB.Insert(offerUser, answerUser)
+	require.NoError(t, err)
+	question := &entity.Question{ID: "10010000000003601", UserID: offerUser.ID, Title: "bounty question",
+		Status: entity.QuestionStatusAvailable}
+	_, err = testDataSource.DB.Insert(question)
+	require.NoError(t, err)
+
+	bountyInfo := &entity.QuestionBounty{QuestionID: question.ID, UserID: offerUser.ID, Amount: 50,
+		Status: entity.QuestionBountyStatusActive, ExpiredAt: time.Now().AddDate(0, 0, 7)}
+	rankEnough, err := bountyRepo.AddBounty(ctx, bountyInfo, 135)
+	require.NoError(t, err)
+	assert.True(t, rankEnough)
+	assertUserRank(t, offerUser.ID, 50)
+	assertQuestionBountyAmount(t, question.ID, 50)
+
+	// the reputation must stay positive after the bounty is offered
+	rankEnough, err = bountyRepo.AddBounty(ctx, &entity.QuestionBounty{QuestionID: question.ID,
+		UserID: offerUser.ID, Amount: 50, Status: entity.QuestionBountyStatusActive}, 135)
+	require.NoError(t, err)
+	assert.False(t, rankEnough)
+	assertUserRank(t, offerUser.ID, 50)
+
+	award := &schema.BountyAwardInfo{
+		BountyID:      bountyInfo.ID,
+		QuestionID:    question.ID,
+		AnswerID:      "10020000000003601",
+		OfferUserID:   offerUser.ID,
+		AwardedUserID: answerUser.ID,
+		AwardedAmount: 50,
+	}
+	awarded, err := bountyRepo.AwardBounty(ctx, award, 136)
+	require.NoError(t, err)
+	assert.True(t, awarded)
+	assertUserRank(t, answerUser.ID, 60)
+	assertQuestionBountyAmount(t, question.ID, 0)
+
+	// the bounty can only be awarded once
+	awarded, err = bountyRepo.AwardBounty(ctx, award, 136)
+	require.NoError(t, err)
+	assert.False(t, awarded)
+	assertUserRank(t, answerUser.ID, 60)
+}
+
+func assertUserRank(t *testing.T, userID string, expected int) {
+	user := &entity.User{}
+	_, err := testDataSource.DB.ID(userID).Get(user)
+	require.NoError(t, err)
+	assert.Equal(t, expected, user.Rank)
+}
+
+func assertQuestionBountyAmount(t *testing.T, questionID string, expected int) {
+	question := &entity.Question{}
+	_, err := testDataSource.DB.ID(questionID).Get(question)
+	require.NoError(t, err)
+	assert.Equal(t, expected, question.BountyAmount)
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/draft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_draftRepo_SaveAndPurgeDraft(t *testing.T) {
+	ctx := context.TODO()
+	draftRepo := draft.NewDraftRepo(testDataSource)
+
+	draftInfo := &entity.Draft{UserID: "3701", DraftType: entity.DraftTypeNewAnswer, ObjectID: "10010000000003701",
+		Content: "draft content"}
+	require.NoError(t, draftRepo.AddDraft(ctx, draftInfo))
+
+	got, exist, err := draftRepo.GetDraftByObject(ctx, "3701", entity.DraftTypeNewAnswer, "10010000000003701")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, draftInfo.ID, got.ID)
+
+	// the draft of another user is invisible
+	_, exist, err = draftRepo.GetDraft(ctx, "3702", draftInfo.ID)
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	got.Content = "updated content"
+	require.NoError(t, draftRepo.UpdateDraft(ctx, got))
+	list, total, err := draftRepo.GetDraftPage(ctx, "3701", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "updated content", list[0].Content)
+
+	affected, err := draftRepo.DeleteDraftsUpdatedBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected)
+	affected, err = draftRepo.DeleteDraftsUpdatedBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	count, err := draftRepo.CountDraft(ctx, "3701")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_importerRepo_Mapping(t *testing.T) {
+	ctx := context.TODO()
+	importerRepo := importer.NewImporterRepo(testDataSource)
+
+	require.NoError(t, importerRepo.AddMapping(ctx, &entity.ImportMapping{Source: "discourse",
+		ObjectType: entity.ImportObjectTypeQuestion, ExternalID: "41", ObjectID: "10010000000004101"}))
+
+	objectID, exist, err := importerRepo.GetMapping(ctx, "discourse", entity.ImportObjectTypeQuestion, "41")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "10010000000004101", objectID)
+
+	_, exist, err = importerRepo.GetMapping(ctx, "stackoverflow", entity.ImportObjectTypeQuestion, "41")
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	// the same external id is mapped only once
+	assert.Error(t, importerRepo.AddMapping(ctx, &entity.ImportMapping{Source: "discourse",
+		ObjectType: entity.ImportObjectTypeQuestion, ExternalID: "41", ObjectID: "10010000000004102"}))
+}
+
+func Test_importerRepo_OriginalTimeAndVotes(t *testing.T) {
+	ctx := context.TODO()
+	importerRepo := importer.NewImporterRepo(testDataSource)
+	createdAt := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.AddDate(0, 1, 0)
+
+	question := &entity.Question{ID: "10010000000004103", UserID: "4101", Title: "imported question",
+		Status: entity.QuestionStatusAvailable}
+	_, err := testDataSource.DB.Insert(question)
+	require.NoError(t, err)
+	require.NoError(t, importerRepo.UpdatePostTime(ctx, question.ID, createdAt, updatedAt))
+
+	got := &entity.Question{}
+	_, err = testDataSource.DB.ID(question.ID).Get(got)
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(got.CreatedAt))
+	assert.True(t, updatedAt.Equal(got.PostUpdateTime))
+
+	require.NoError(t, importerRepo.AddActivities(ctx, []*entity.Activity{
+		{UserID: "4102", ObjectID: question.ID, ActivityType: 4101, CreatedAt: createdAt},
+		{UserID: "4103", ObjectID: question.ID, ActivityType: 4101, CreatedAt: createdAt},
+		{UserID: "4104", ObjectID: question.ID, ActivityType: 4102, CreatedAt: createdAt},
+	}))
+	exist, err := importerRepo.ExistActivity(ctx, question.ID, "4104", 4101, 4102)
+	require.NoError(t, err)
+	assert.True(t, exist)
+
+	require.NoError(t, importerRepo.UpdateVoteCount(ctx, question.ID, 4101, 4102))
+	got = &entity.Question{}
+	_, err = testDataSource.DB.ID(question.ID).Get(got)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.VoteCount)
+}
+
+func Test_importerRepo_MappingRolledBack(t *testing.T) {
+	ctx := context.TODO()
+	importerRepo := importer.NewImporterRepo(testDataSource)
+
+	// the activities and the mapping are added in the transaction, nothing is kept if the import fails
+	err := testDataSource.DB.TransactionContext(ctx, func(ctx context.Context) error {
+		require.NoError(t, importerRepo.AddActivities(ctx, []*entity.Activity{
+			{UserID: "4105", ObjectID: "10010000000004104", ActivityType: 4103},
+		}))
+		require.NoError(t, importerRepo.AddMapping(ctx, &entity.ImportMapping{Source: "discourse",
+			ObjectType: entity.ImportObjectTypeQuestion, ExternalID: "42", ObjectID: "10010000000004104"}))
+		return errors.New("import failed")
+	})
+	require.Error(t, err)
+
+	_, exist, err := importerRepo.GetMapping(ctx, "discourse", entity.ImportObjectTypeQuestion, "42")
+	require.NoError(t, err)
+	assert.False(t, exist)
+	exist, err = importerRepo.ExistActivity(ctx, "10010000000004104", "4105", 4103)
+	require.NoError(t, err)
+	assert.False(t, exist)
+}
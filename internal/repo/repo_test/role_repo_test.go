@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_roleRepo_CustomRole(t *testing.T) {
+	ctx := context.TODO()
+	roleRepo := role.NewRoleRepo(testDataSource)
+	rolePowerRelRepo := role.NewRolePowerRelRepo(testDataSource)
+	roleTagRelRepo := role.NewRoleTagRelRepo(testDataSource)
+
+	roleInfo := &entity.Role{Name: "Tag curator", Description: "curate the tags"}
+	require.NoError(t, roleRepo.AddRole(ctx, roleInfo))
+	assert.Greater(t, roleInfo.ID, 3)
+
+	require.NoError(t, rolePowerRelRepo.SaveRolePowers(ctx, roleInfo.ID, []string{"tag.edit", "tag.synonym"}))
+	require.NoError(t, rolePowerRelRepo.SaveRolePowers(ctx, roleInfo.ID, []string{"tag.edit"}))
+	powers, err := rolePowerRelRepo.GetRolePowerTypeList(ctx, roleInfo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tag.edit"}, powers)
+
+	require.NoError(t, roleTagRelRepo.SaveRoleTags(ctx, roleInfo.ID, []string{"10010000000000001"}))
+	tagIDs, err := roleTagRelRepo.GetRoleTagIDs(ctx, roleInfo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10010000000000001"}, tagIDs)
+
+	roleInfo.Name = "Tag gardener"
+	require.NoError(t, roleRepo.UpdateRole(ctx, roleInfo))
+	got, exist, err := roleRepo.GetRole(ctx, roleInfo.ID)
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, "Tag gardener", got.Name)
+
+	require.NoError(t, roleRepo.DeleteRole(ctx, roleInfo.ID))
+	_, exist, err = roleRepo.GetRole(ctx, roleInfo.ID)
+	require.NoError(t, err)
+	assert.False(t, exist)
+	powers, err = rolePowerRelRepo.GetRolePowerTypeList(ctx, roleInfo.ID)
+	require.NoError(t, err)
+	assert.Empty(t, powers)
+	tagIDs, err = roleTagRelRepo.GetRoleTagIDs(ctx, roleInfo.ID)
+	require.NoError(t, err)
+	assert.Empty(t, tagIDs)
+}
+
+func Test_roleRepo_CustomRoleRolledBack(t *testing.T) {
+	ctx := context.TODO()
+	roleRepo := role.NewRoleRepo(testDataSource)
+	roleTagRelRepo := role.NewRoleTagRelRepo(testDataSource)
+
+	// the role and its tags are saved in the transaction, nothing is kept if saving the tags fails
+	roleInfo := &entity.Role{Name: "Tag janitor", Description: "clean the tags"}
+	err := testDataSource.DB.TransactionContext(ctx, func(ctx context.Context) error {
+		require.NoError(t, roleRepo.AddRole(ctx, roleInfo))
+		require.NoError(t, roleTagRelRepo.SaveRoleTags(ctx, roleInfo.ID, []string{"10010000000000001"}))
+		return errors.New("save role failed")
+	})
+	require.Error(t, err)
+
+	_, exist, err := roleRepo.GetRole(ctx, roleInfo.ID)
+	require.NoError(t, err)
+	assert.False(t, exist)
+	tagIDs, err := roleTagRelRepo.GetRoleTagIDs(ctx, roleInfo.ID)
+	require.NoError(t, err)
+	assert.Empty(t, tagIDs)
+}
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/scheduled_post"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_scheduledPostRepo_PublishQuestion(t *testing.T) {
+	ctx := context.TODO()
+	scheduledPostRepo := scheduled_post.NewScheduledPostRepo(testDataSource)
+
+	now := time.Now()
+	question := &entity.Question{ID: "10010000000003801", UserID: "3801", Title: "scheduled question",
+		Status: entity.QuestionStatusScheduled, PublishAt: now.Add(time.Hour)}
+	_, err := testDataSource.DB.Insert(question)
+	require.NoError(t, err)
+
+	// the question is not due before the publish time
+	list, err := scheduledPostRepo.GetDueScheduledQuestions(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Len(t, list, 0)
+
+	updated, err := scheduledPostRepo.UpdateQuestionPublishAt(ctx, question.ID, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.True(t, updated)
+	list, err = scheduledPostRepo.GetDueScheduledQuestions(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, question.ID, list[0].ID)
+
+	published, err := scheduledPostRepo.PublishQuestion(ctx, question.ID, now)
+	require.NoError(t, err)
+	assert.True(t, published)
+	got := &entity.Question{}
+	_, err = testDataSource.DB.ID(question.ID).Get(got)
+	require.NoError(t, err)
+	assert.Equal(t, entity.QuestionStatusAvailable, got.Status)
+	assert.Equal(t, now.Unix(), got.CreatedAt.Unix())
+
+	// the published question can not be published or rescheduled again
+	published, err = scheduledPostRepo.PublishQuestion(ctx, question.ID, now)
+	require.NoError(t, err)
+	assert.False(t, published)
+	updated, err = scheduledPostRepo.UpdateQuestionPublishAt(ctx, question.ID, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, updated)
+}
+
+func Test_scheduledPostRepo_GetDueScheduledAnswers(t *testing.T) {
+	ctx := context.TODO()
+	scheduledPostRepo := scheduled_post.NewScheduledPostRepo(testDataSource)
+
+	now := time.Now()
+	waiting := &entity.Question{ID: "10010000000003811", UserID: "3801", Title: "waiting question",
+		Status: entity.QuestionStatusScheduled, PublishAt: now.Add(time.Hour)}
+	published := &entity.Question{ID: "10010000000003812", UserID: "3801", Title: "published question",
+		Status: entity.QuestionStatusAvailable}
+	_, err := testDataSource.DB.Insert(waiting, published)
+	require.NoError(t, err)
+	// the earlier answer of the scheduled question must not hold the later answer of the published question
+	_, err = testDataSource.DB.Insert(
+		&entity.Answer{ID: "10020000000003811", QuestionID: waiting.ID, UserID: "3801",
+			Status: entity.AnswerStatusScheduled, PublishAt: now.Add(-time.Hour)},
+		&entity.Answer{ID: "10020000000003812", QuestionID: published.ID, UserID: "3801",
+			Status: entity.AnswerStatusScheduled, PublishAt: now.Add(-time.Minute)},
+	)
+	require.NoError(t, err)
+
+	list, err := scheduledPostRepo.GetDueScheduledAnswers(ctx, now, 1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "10020000000003812", list[0].ID)
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/scim"
+	"github.com/apache/answer/internal/repo/user_external_login"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_scimRepo_GetUserPage(t *testing.T) {
+	ctx := context.TODO()
+	scimRepo := scim.NewSCIMRepo(testDataSource)
+
+	deleted := &entity.User{Username: "scim_deleted", DisplayName: "scim deleted", EMail: "scim_deleted@example.com",
+		Status: entity.UserStatusDeleted}
+	_, err := testDataSource.DB.Context(ctx).Insert(deleted)
+	require.NoError(t, err)
+
+	users, total, err := scimRepo.GetUserPage(ctx, 1, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(users)), total)
+	for _, user := range users {
+		assert.NotEqual(t, entity.UserStatusDeleted, user.Status)
+	}
+}
+
+func Test_scimRepo_GetUserExternalLogins(t *testing.T) {
+	ctx := context.TODO()
+	scimRepo := scim.NewSCIMRepo(testDataSource)
+	userExternalLoginRepo := user_external_login.NewUserExternalLoginRepo(testDataSource)
+
+	require.NoError(t, userExternalLoginRepo.AddUserExternalLogin(ctx, &entity.UserExternalLogin{
+		UserID: "5501", Provider: "scim", ExternalID: "scim_5501@example.com"}))
+	require.NoError(t, userExternalLoginRepo.AddUserExternalLogin(ctx, &entity.UserExternalLogin{
+		UserID: "5501", Provider: "github", ExternalID: "5501"}))
+
+	logins, err := scimRepo.GetUserExternalLogins(ctx, "scim", []string{"5501", "5502"})
+	require.NoError(t, err)
+	require.Len(t, logins, 1)
+	assert.Equal(t, "scim_5501@example.com", logins[0].ExternalID)
+
+	logins, err = scimRepo.GetUserExternalLogins(ctx, "scim", nil)
+	require.NoError(t, err)
+	assert.Empty(t, logins)
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/search_common"
+	"github.com/apache/answer/internal/repo/site_info"
+	"github.com/apache/answer/internal/repo/tag"
+	"github.com/apache/answer/internal/repo/tag_common"
+	"github.com/apache/answer/internal/repo/unique"
+	"github.com/apache/answer/internal/repo/user"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	tagcommon "github.com/apache/answer/internal/service/tag_common"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_searchRepo_SearchQuestions(t *testing.T) {
+	ctx := context.TODO()
+	uniqueIDRepo := unique.NewUniqueIDRepo(testDataSource)
+	tagCommonService := tagcommon.NewTagCommonService(tag_common.NewTagCommonRepo(testDataSource, uniqueIDRepo),
+		tag.NewTagRelRepo(testDataSource, uniqueIDRepo), tag.NewTagRepo(testDataSource, uniqueIDRepo),
+		tag.NewTagOwnerRepo(testDataSource), tag.NewTagSettingRepo(testDataSource), nil, nil, nil, nil)
+	userCommon := usercommon.NewUserCommon(user.NewUserRepo(testDataSource), nil, nil,
+		siteinfo_common.NewSiteInfoCommonService(site_info.NewSiteInfo(testDataSource)))
+	searchRepo := search_common.NewSearchRepo(testDataSource, uniqueIDRepo, userCommon, tagCommonService)
+
+	now := time.Now()
+	questions := []*entity.Question{
+		{ID: "10010000000000901", UserID: "10000000000000999", Title: "search parser", OriginalText: "```go\nfunc main() {}\n```",
+			ParsedText: "search parser", AcceptedAnswerID: "0", Status: entity.QuestionStatusAvailable, Show: entity.QuestionShow,
+			CreatedAt: now, PostUpdateTime: now},
+		{ID: "10010000000000902", UserID: "10000000000000999", Title: "search engine", OriginalText: "full text index",
+			ParsedText: "search engine", AcceptedAnswerID: "0", Status: entity.QuestionStatusClosed, Show: entity.QuestionShow,
+			CreatedAt: now.AddDate(-2, 0, 0), PostUpdateTime: now},
+	}
+	_, err := testDataSource.DB.Context(ctx).Insert(questions)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = testDataSource.DB.Context(ctx).In("id", questions[0].ID, questions[1].ID).Delete(&entity.Question{})
+	}()
+
+	term := func(word string) *plugin.SearchExpr {
+		return &plugin.SearchExpr{Type: plugin.SearchExprTerm, Value: word}
+	}
+	tests := []struct {
+		name string
+		cond *schema.SearchCondition
+		want []string
+	}{
+		{
+			name: "term",
+			cond: &schema.SearchCondition{Words: []string{"search"}, Expr: term("search")},
+			want: []string{questions[0].ID, questions[1].ID},
+		},
+		{
+			name: "not",
+			cond: &schema.SearchCondition{Words: []string{"search"}, Expr: &plugin.SearchExpr{
+				Type:     plugin.SearchExprAnd,
+				Children: []*plugin.SearchExpr{term("search"), {Type: plugin.SearchExprNot, Children: []*plugin.SearchExpr{term("engine")}}},
+			}},
+			want: []string{questions[0].ID},
+		},
+		{
+			name: "or",
+			cond: &schema.SearchCondition{Words: []string{"parser", "index"}, Expr: &plugin.SearchExpr{
+				Type: plugin.SearchExprOr, Children: []*plugin.SearchExpr{term("parser"), term("index")},
+			}},
+			want: []string{questions[0].ID, questions[1].ID},
+		},
+		{
+			name: "stemming",
+			cond: &schema.SearchCondition{Words: []string{"parsers"}, Expr: term("parsers")},
+			want: []string{questions[0].ID},
+		},
+		{
+			name: "phrase",
+			cond: &schema.SearchCondition{Words: []string{"text index"}, Expr: &plugin.SearchExpr{Type: plugin.SearchExprPhrase, Value: "text index"}},
+			want: []string{questions[1].ID},
+		},
+		{
+			name: "closed",
+			cond: &schema.SearchCondition{Words: []string{"search"}, Expr: term("search"), Closed: plugin.ClosedCondTrue},
+			want: []string{questions[1].ID},
+		},
+		{
+			name: "created",
+			cond: &schema.SearchCondition{Words: []string{"search"}, Expr: term("search"),
+				Created: plugin.SearchTimeRange{From: now.AddDate(0, 0, -7).Unix()}},
+			want: []string{questions[0].ID},
+		},
+		{
+			name: "lang",
+			cond: &schema.SearchCondition{Expr: term("search"), Lang: "go"},
+			want: []string{questions[0].ID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cond.VoteAmount, tt.cond.Views, tt.cond.AnswerAmount = -1, -1, -1
+			resp, total, err := searchRepo.SearchQuestions(ctx, tt.cond, 1, 10, "relevance")
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), total)
+			ids := make([]string, 0, len(resp))
+			for _, r := range resp {
+				ids = append(ids, r.Object.ID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+
+	t.Run("highlight", func(t *testing.T) {
+		cond := &schema.SearchCondition{Words: []string{"engine"}, Expr: term("engine"), VoteAmount: -1, Views: -1, AnswerAmount: -1}
+		resp, _, err := searchRepo.SearchQuestions(ctx, cond, 1, 10, "relevance")
+		require.NoError(t, err)
+		require.Len(t, resp, 1)
+		assert.Contains(t, resp[0].Object.Highlight, "<em>engine</em>")
+	})
+
+	t.Run("facets", func(t *testing.T) {
+		cond := &schema.SearchCondition{TargetType: "question", Words: []string{"search"}, Expr: term("search"),
+			VoteAmount: -1, Views: -1, AnswerAmount: -1}
+		facets, err := searchRepo.SearchFacets(ctx, cond)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), facets.Total)
+		assert.Equal(t, int64(0), facets.Accepted)
+		assert.Equal(t, []plugin.SearchFacetCount{{Value: "10000000000000999", Count: 2}}, facets.Users)
+		assert.Equal(t, []plugin.SearchFacetCount{
+			{Value: "1d", Count: 1}, {Value: "1w", Count: 1}, {Value: "1m", Count: 1}, {Value: "1y", Count: 1},
+		}, facets.Created)
+	})
+}
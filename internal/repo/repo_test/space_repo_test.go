@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/space"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_spaceRepo_AddSpaceAndMembers(t *testing.T) {
+	ctx := context.TODO()
+	spaceRepo := space.NewSpaceRepo(testDataSource)
+
+	spaceInfo := &entity.Space{SlugName: "team-3901", DisplayName: "Team", UserID: "3901",
+		Status: entity.SpaceStatusAvailable}
+	require.NoError(t, spaceRepo.AddSpace(ctx, spaceInfo, &entity.SpaceMember{UserID: "3901", Role: entity.SpaceRoleOwner}))
+
+	got, exist, err := spaceRepo.GetSpaceBySlugName(ctx, "team-3901")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, spaceInfo.ID, got.ID)
+
+	owner, exist, err := spaceRepo.GetSpaceMember(ctx, spaceInfo.ID, "3901")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, entity.SpaceRoleOwner, owner.Role)
+
+	require.NoError(t, spaceRepo.AddSpaceMember(ctx, &entity.SpaceMember{SpaceID: spaceInfo.ID, UserID: "3902",
+		Role: entity.SpaceRoleViewer}))
+	require.NoError(t, spaceRepo.UpdateSpaceMemberRole(ctx, spaceInfo.ID, "3902", entity.SpaceRoleMember))
+	count, err := spaceRepo.CountSpaceMember(ctx, spaceInfo.ID, entity.SpaceRoleMember)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	members, err := spaceRepo.GetUserSpaceMemberList(ctx, "3902")
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, spaceInfo.ID, members[0].SpaceID)
+
+	require.NoError(t, spaceRepo.RemoveSpaceMember(ctx, spaceInfo.ID, "3902"))
+	_, exist, err = spaceRepo.GetSpaceMember(ctx, spaceInfo.ID, "3902")
+	require.NoError(t, err)
+	assert.False(t, exist)
+	count, err = spaceRepo.CountSpaceMember(ctx, spaceInfo.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -198,3 +198,23 @@ func Test_tagRepo_UpdateTagSynonym(t *testing.T) {
 	assert.True(t, exist)
 	assert.Equal(t, testTagList[0].ID, fmt.Sprintf("%d", gotTag.MainTagID))
 }
+
+func Test_tagRepo_UpdateTagParent(t *testing.T) {
+	tagOnce.Do(addTagList)
+	tagRepo := tag.NewTagRepo(testDataSource, unique.NewUniqueIDRepo(testDataSource))
+
+	err := tagRepo.UpdateTagParent(context.TODO(), testTagList[2].ID, converter.StringToInt64(testTagList[0].ID))
+	require.NoError(t, err)
+
+	gotTags, err := tagRepo.GetTagListByParentIDs(context.TODO(), []string{testTagList[0].ID})
+	require.NoError(t, err)
+	require.Len(t, gotTags, 1)
+	assert.Equal(t, testTagList[2].ID, gotTags[0].ID)
+
+	err = tagRepo.UpdateTagParent(context.TODO(), testTagList[2].ID, 0)
+	require.NoError(t, err)
+
+	gotTags, err = tagRepo.GetTagListByParentIDs(context.TODO(), []string{testTagList[0].ID})
+	require.NoError(t, err)
+	assert.Empty(t, gotTags)
+}
@@ -0,0 +1,82 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/draft"
+	"github.com/apache/answer/internal/repo/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tenantRepo_AddAndGetTenant(t *testing.T) {
+	ctx := context.TODO()
+	tenantRepo := tenant.NewTenantRepo(testDataSource)
+
+	tenantInfo := &entity.Tenant{Host: "tenant-4001.example.com", Name: "Tenant", Status: entity.TenantStatusAvailable}
+	require.NoError(t, tenantRepo.AddTenant(ctx, tenantInfo))
+
+	// the tenants are read whichever tenant the context is of
+	got, exist, err := tenantRepo.GetTenantByHost(handler.WithTenantID(ctx, tenantInfo.ID), "tenant-4001.example.com")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, tenantInfo.ID, got.ID)
+
+	got.Status = entity.TenantStatusDisabled
+	require.NoError(t, tenantRepo.UpdateTenant(ctx, got, []string{"status"}))
+	tenants, err := tenantRepo.GetAvailableTenantList(ctx)
+	require.NoError(t, err)
+	for _, item := range tenants {
+		assert.NotEqual(t, tenantInfo.ID, item.ID)
+	}
+}
+
+func Test_tenantData_ScopedByTenantID(t *testing.T) {
+	ctx := context.TODO()
+	tenantInfo := &entity.Tenant{Host: "tenant-4002.example.com", Name: "Tenant", Status: entity.TenantStatusAvailable}
+	require.NoError(t, tenant.NewTenantRepo(testDataSource).AddTenant(ctx, tenantInfo))
+
+	tenantCtx := handler.WithTenantID(ctx, tenantInfo.ID)
+	draftRepo := draft.NewDraftRepo(testDataSource)
+	draftInfo := &entity.Draft{UserID: "4002", DraftType: entity.DraftTypeNewQuestion, Content: "tenant draft"}
+	require.NoError(t, draftRepo.AddDraft(tenantCtx, draftInfo))
+	assert.Equal(t, tenantInfo.ID, draftInfo.TenantID)
+
+	_, total, err := draftRepo.GetDraftPage(tenantCtx, "4002", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	_, total, err = draftRepo.GetDraftPage(ctx, "4002", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	_, exist, err := draftRepo.GetDraft(ctx, "4002", draftInfo.ID)
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	// the cache keys of the tenants never collide with the primary site
+	require.NoError(t, testDataSource.Cache.SetString(tenantCtx, "answer:test:tenant", "tenant", 0))
+	_, exist, err = testDataSource.Cache.GetString(ctx, "answer:test:tenant")
+	require.NoError(t, err)
+	assert.False(t, exist)
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/two_factor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_twoFactorRepo_SingleUse(t *testing.T) {
+	ctx := context.TODO()
+	twoFactorRepo := two_factor.NewTwoFactorRepo(testDataSource)
+
+	require.NoError(t, twoFactorRepo.SaveUserTwoFactor(ctx, &entity.UserTwoFactor{
+		UserID: "5601", TOTPSecret: "ABCDEF", TOTPEnabled: true, TOTPLastStep: 10, RecoveryCodes: `["a","b"]`}))
+
+	updated, err := twoFactorRepo.UpdateTOTPLastStep(ctx, "5601", 11)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = twoFactorRepo.UpdateTOTPLastStep(ctx, "5601", 11)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	updated, err = twoFactorRepo.UpdateRecoveryCodes(ctx, "5601", `["a","b"]`, `["b"]`)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = twoFactorRepo.UpdateRecoveryCodes(ctx, "5601", `["a","b"]`, `["a"]`)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	info, exist, err := twoFactorRepo.GetUserTwoFactor(ctx, "5601")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, int64(11), info.TOTPLastStep)
+	assert.Equal(t, `["b"]`, info.RecoveryCodes)
+}
+
+func Test_twoFactorRepo_WebAuthnCredential(t *testing.T) {
+	ctx := context.TODO()
+	twoFactorRepo := two_factor.NewTwoFactorRepo(testDataSource)
+
+	cred := &entity.UserWebAuthnCredential{UserID: "5602", Name: "key", CredentialID: "aWQ", PublicKey: "a2V5"}
+	require.NoError(t, twoFactorRepo.AddWebAuthnCredential(ctx, cred))
+
+	cred.SignCount = 3
+	updated, err := twoFactorRepo.UpdateWebAuthnSignCount(ctx, cred, 0)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = twoFactorRepo.UpdateWebAuthnSignCount(ctx, cred, 0)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	removed, err := twoFactorRepo.RemoveWebAuthnCredential(ctx, "5603", cred.ID)
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	require.NoError(t, twoFactorRepo.RemoveUserTwoFactor(ctx, "5602"))
+	creds, err := twoFactorRepo.GetWebAuthnCredentials(ctx, "5602")
+	require.NoError(t, err)
+	assert.Empty(t, creds)
+}
@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package repo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/repo/user_data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_userDataRepo_ProcessRequest(t *testing.T) {
+	ctx := context.TODO()
+	userDataRepo := user_data.NewUserDataRepo(testDataSource)
+
+	export := &entity.UserDataRequest{UserID: "4401", RequestType: entity.UserDataRequestTypeExport,
+		Status: entity.UserDataRequestStatusPending, ScheduledAt: time.Now().Add(-time.Minute)}
+	require.NoError(t, userDataRepo.AddUserDataRequest(ctx, export))
+	erasure := &entity.UserDataRequest{UserID: "4401", RequestType: entity.UserDataRequestTypeErasure,
+		Status: entity.UserDataRequestStatusPending, ErasureMode: entity.UserErasureModeAnonymize,
+		ScheduledAt: time.Now().Add(time.Hour)}
+	require.NoError(t, userDataRepo.AddUserDataRequest(ctx, erasure))
+
+	// the erasure is not due until the end of the cooling-off period
+	due, err := userDataRepo.GetDueUserDataRequests(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, export.ID, due[0].ID)
+
+	// the request can only be claimed once
+	updated, err := userDataRepo.UpdateUserDataRequestStatus(ctx, export.ID,
+		entity.UserDataRequestStatusPending, entity.UserDataRequestStatusProcessing)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = userDataRepo.UpdateUserDataRequestStatus(ctx, export.ID,
+		entity.UserDataRequestStatusPending, entity.UserDataRequestStatusProcessing)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	export.Status = entity.UserDataRequestStatusCompleted
+	export.CompletedAt = time.Now()
+	export.FileName = "answer_data_4401.zip"
+	export.DownloadCode = "download-code-4401"
+	require.NoError(t, userDataRepo.UpdateUserDataRequest(ctx, export))
+
+	got, exist, err := userDataRepo.GetUserDataRequestByCode(ctx, "download-code-4401")
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, entity.UserDataRequestStatusCompleted, got.Status)
+	assert.Equal(t, "answer_data_4401.zip", got.FileName)
+
+	expired, err := userDataRepo.GetExpiredUserDataExports(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, expired, 0)
+	expired, err = userDataRepo.GetExpiredUserDataExports(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, expired, 1)
+
+	latest, exist, err := userDataRepo.GetLatestUserDataRequest(ctx, "4401", entity.UserDataRequestTypeErasure)
+	require.NoError(t, err)
+	require.True(t, exist)
+	assert.Equal(t, erasure.ID, latest.ID)
+
+	list, err := userDataRepo.GetUserDataRequests(ctx, "4401")
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
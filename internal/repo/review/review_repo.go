@@ -23,6 +23,7 @@ import (
 	"context"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/pager"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
@@ -44,6 +45,7 @@ func NewReviewRepo(data *data.Data) review.ReviewRepo {
 
 // AddReview add review
 func (cr *reviewRepo) AddReview(ctx context.Context, review *entity.Review) (err error) {
+	review.TenantID = handler.GetTenantID(ctx)
 	_, err = cr.data.DB.Context(ctx).Insert(review)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -53,7 +55,7 @@ func (cr *reviewRepo) AddReview(ctx context.Context, review *entity.Review) (err
 
 // UpdateReviewStatus update review status
 func (cr *reviewRepo) UpdateReviewStatus(ctx context.Context, reviewID int, reviewerUserID string, status int) (err error) {
-	_, err = cr.data.DB.Context(ctx).ID(reviewID).Update(&entity.Review{
+	_, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(reviewID).Update(&entity.Review{
 		ReviewerUserID: reviewerUserID, Status: status})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -65,7 +67,7 @@ func (cr *reviewRepo) UpdateReviewStatus(ctx context.Context, reviewID int, revi
 func (cr *reviewRepo) GetReview(ctx context.Context, reviewID int) (
 	review *entity.Review, exist bool, err error) {
 	review = &entity.Review{}
-	exist, err = cr.data.DB.Context(ctx).ID(reviewID).Get(review)
+	exist, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(reviewID).Get(review)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -75,7 +77,7 @@ func (cr *reviewRepo) GetReview(ctx context.Context, reviewID int) (
 // GetReviewByObject get review by object
 func (cr *reviewRepo) GetReviewByObject(ctx context.Context, objectID string) (review *entity.Review, exist bool, err error) {
 	review = &entity.Review{}
-	exist, err = cr.data.DB.Context(ctx).Desc("id").Where("object_id = ?", objectID).Get(review)
+	exist, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Desc("id").Where("object_id = ?", objectID).Get(review)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -84,7 +86,7 @@ func (cr *reviewRepo) GetReviewByObject(ctx context.Context, objectID string) (r
 
 // GetReviewCount get review count
 func (cr *reviewRepo) GetReviewCount(ctx context.Context, status int) (count int64, err error) {
-	count, err = cr.data.DB.Context(ctx).Count(&entity.Review{Status: status})
+	count, err = cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Count(&entity.Review{Status: status})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -94,7 +96,7 @@ func (cr *reviewRepo) GetReviewCount(ctx context.Context, status int) (count int
 // GetReviewPage get review page
 func (cr *reviewRepo) GetReviewPage(ctx context.Context, page, pageSize int, cond *entity.Review) (
 	reviewList []*entity.Review, total int64, err error) {
-	session := cr.data.DB.Context(ctx).Asc("created_at")
+	session := cr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Asc("created_at")
 	reviewList = make([]*entity.Review, 0)
 	total, err = pager.Help(page, pageSize, &reviewList, cond, session)
 	if err != nil {
@@ -24,6 +24,7 @@ import (
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/pager"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
@@ -64,8 +65,9 @@ func (rr *revisionRepo) AddRevision(ctx context.Context, revision *entity.Revisi
 	if !rr.allowRecord(revision.ObjectType) {
 		return nil
 	}
-	_, err = rr.data.DB.Transaction(func(session *xorm.Session) (any, error) {
+	_, err = rr.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
 		session = session.Context(ctx)
+		revision.TenantID = handler.GetTenantID(ctx)
 		_, err = session.Insert(revision)
 		if err != nil {
 			_ = session.Rollback()
@@ -90,7 +92,7 @@ func (rr *revisionRepo) UpdateObjectRevisionId(ctx context.Context, revision *en
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
-	_, err = session.Table(tableName).Where("id = ?", revision.ObjectID).Cols("`revision_id`").Update(struct {
+	_, err = session.Table(tableName).Where(data.TenantCond(ctx)).And("id = ?", revision.ObjectID).Cols("`revision_id`").Update(struct {
 		RevisionID string `xorm:"revision_id"`
 	}{
 		RevisionID: revision.ID,
@@ -106,11 +108,11 @@ func (rr *revisionRepo) UpdateStatus(ctx context.Context, id string, status int,
 	if id == "" {
 		return nil
 	}
-	var data entity.Revision
-	data.ID = id
-	data.Status = status
-	data.ReviewUserID = converter.StringToInt64(reviewUserID)
-	_, err = rr.data.DB.Context(ctx).Where("id =?", id).Cols("status", "review_user_id").Update(&data)
+	var revision entity.Revision
+	revision.ID = id
+	revision.Status = status
+	revision.ReviewUserID = converter.StringToInt64(reviewUserID)
+	_, err = rr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id =?", id).Cols("status", "review_user_id").Update(&revision)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -122,7 +124,7 @@ func (rr *revisionRepo) GetRevision(ctx context.Context, id string) (
 	revision *entity.Revision, exist bool, err error,
 ) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.Context(ctx).ID(id).Get(revision)
+	exist, err = rr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -133,7 +135,7 @@ func (rr *revisionRepo) GetRevision(ctx context.Context, id string) (
 func (rr *revisionRepo) GetRevisionByID(ctx context.Context, revisionID string) (
 	revision *entity.Revision, exist bool, err error) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.Context(ctx).Where("id = ?", revisionID).Get(revision)
+	exist, err = rr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", revisionID).Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -143,7 +145,7 @@ func (rr *revisionRepo) GetRevisionByID(ctx context.Context, revisionID string)
 func (rr *revisionRepo) ExistUnreviewedByObjectID(ctx context.Context, objectID string) (
 	revision *entity.Revision, exist bool, err error) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.Context(ctx).Where("object_id = ?", objectID).And("status = ?", entity.RevisionUnreviewedStatus).Get(revision)
+	exist, err = rr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID).And("status = ?", entity.RevisionUnreviewedStatus).Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -155,7 +157,7 @@ func (rr *revisionRepo) GetLastRevisionByObjectID(ctx context.Context, objectID
 	revision *entity.Revision, exist bool, err error,
 ) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.Context(ctx).Where("object_id = ?", objectID).Desc("created_at").Get(revision)
+	exist, err = rr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID).Desc("created_at").Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -165,7 +167,7 @@ func (rr *revisionRepo) GetLastRevisionByObjectID(ctx context.Context, objectID
 // GetLastRevisionByFileURL get object's last revision by file url
 func (rr *revisionRepo) GetLastRevisionByFileURL(ctx context.Context, fileURL string) (revision *entity.Revision, exist bool, err error) {
 	revision = &entity.Revision{}
-	exist, err = rr.data.DB.Context(ctx).Where("content LIKE ?", "%"+fileURL+"%").Desc("created_at").Get(revision)
+	exist, err = rr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("content LIKE ?", "%"+fileURL+"%").Desc("created_at").Get(revision)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -175,7 +177,7 @@ func (rr *revisionRepo) GetLastRevisionByFileURL(ctx context.Context, fileURL st
 // GetRevisionList get revision list all
 func (rr *revisionRepo) GetRevisionList(ctx context.Context, revision *entity.Revision) (revisionList []entity.Revision, err error) {
 	revisionList = []entity.Revision{}
-	err = rr.data.DB.Context(ctx).Where(builder.Eq{
+	err = rr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{
 		"object_id": revision.ObjectID,
 	}).OrderBy("created_at DESC").Find(&revisionList)
 	if err != nil {
@@ -205,7 +207,7 @@ func (rr *revisionRepo) GetUnreviewedRevisionPage(ctx context.Context, page int,
 	if len(objectTypeList) == 0 {
 		return revisionList, 0, nil
 	}
-	session := rr.data.DB.Context(ctx)
+	session := rr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	session = session.And("status = ?", entity.RevisionUnreviewedStatus)
 	session = session.In("object_type", objectTypeList)
 	session = session.OrderBy("created_at asc")
@@ -222,7 +224,7 @@ func (rr *revisionRepo) CountUnreviewedRevision(ctx context.Context, objectTypeL
 	if len(objectTypeList) == 0 {
 		return 0, nil
 	}
-	session := rr.data.DB.Context(ctx)
+	session := rr.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	session = session.And("status = ?", entity.RevisionUnreviewedStatus)
 	session = session.In("object_type", objectTypeList)
 	count, err = session.Count(&entity.Revision{})
@@ -24,9 +24,11 @@ import (
 
 	"github.com/apache/answer/internal/base/data"
 	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/role"
 	"github.com/segmentfault/pacman/errors"
 	"xorm.io/builder"
+	"xorm.io/xorm"
 )
 
 // rolePowerRelRepo rolePowerRel repository
@@ -51,3 +53,26 @@ func (rr *rolePowerRelRepo) GetRolePowerTypeList(ctx context.Context, roleID int
 	}
 	return
 }
+
+// SaveRolePowers replace the powers of the role
+func (rr *rolePowerRelRepo) SaveRolePowers(ctx context.Context, roleID int, powerTypes []string) (err error) {
+	_, err = rr.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
+		session = session.Context(ctx)
+		if _, err := session.Where(builder.Eq{"role_id": roleID}).Delete(&entity.RolePowerRel{}); err != nil {
+			return nil, err
+		}
+		if len(powerTypes) == 0 {
+			return nil, nil
+		}
+		rels := make([]*entity.RolePowerRel, 0, len(powerTypes))
+		for _, powerType := range powerTypes {
+			rels = append(rels, &entity.RolePowerRel{RoleID: roleID, PowerType: powerType})
+		}
+		_, err := session.Insert(rels)
+		return nil, err
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
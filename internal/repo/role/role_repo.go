@@ -23,10 +23,13 @@ import (
 	"context"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	service "github.com/apache/answer/internal/service/role"
 	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
 )
 
 // roleRepo role repository
@@ -41,10 +44,18 @@ func NewRoleRepo(data *data.Data) service.RoleRepo {
 	}
 }
 
+// roleCond the built-in roles are shared by the tenants, the custom roles are of the tenant who adds them
+func roleCond(ctx context.Context) builder.Cond {
+	return builder.Or(
+		builder.In("id", service.RoleUserID, service.RoleAdminID, service.RoleModeratorID),
+		data.TenantCond(ctx),
+	)
+}
+
 // GetRoleAllList get role list all
 func (rr *roleRepo) GetRoleAllList(ctx context.Context) (roleList []*entity.Role, err error) {
 	roleList = make([]*entity.Role, 0)
-	err = rr.data.DB.Context(ctx).Find(&roleList)
+	err = rr.data.DB.Context(ctx).Where(roleCond(ctx)).Find(&roleList)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -63,3 +74,54 @@ func (rr *roleRepo) GetRoleAllMapping(ctx context.Context) (roleMapping map[int]
 	}
 	return roleMapping, nil
 }
+
+// GetRole get role by id
+func (rr *roleRepo) GetRole(ctx context.Context, roleID int) (role *entity.Role, exist bool, err error) {
+	role = &entity.Role{}
+	exist, err = rr.data.DB.Context(ctx).Where(roleCond(ctx)).ID(roleID).Get(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// AddRole add role
+func (rr *roleRepo) AddRole(ctx context.Context, role *entity.Role) (err error) {
+	role.TenantID = handler.GetTenantID(ctx)
+	_, err = rr.data.DB.Context(ctx).Insert(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateRole update the name and the description of the role
+func (rr *roleRepo) UpdateRole(ctx context.Context, role *entity.Role) (err error) {
+	_, err = rr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(role.ID).Cols("name", "description").Update(role)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// DeleteRole delete the role with its powers and tags
+func (rr *roleRepo) DeleteRole(ctx context.Context, roleID int) (err error) {
+	_, err = rr.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
+		session = session.Context(ctx)
+		affected, err := session.Where(data.TenantCond(ctx)).ID(roleID).Delete(&entity.Role{})
+		if err != nil || affected == 0 {
+			return nil, err
+		}
+		if _, err := session.Where(builder.Eq{"role_id": roleID}).Delete(&entity.RolePowerRel{}); err != nil {
+			return nil, err
+		}
+		if _, err := session.Where(builder.Eq{"role_id": roleID}).Delete(&entity.RoleTagRel{}); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package role
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/role"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// roleTagRelRepo roleTagRel repository
+type roleTagRelRepo struct {
+	data *data.Data
+}
+
+// NewRoleTagRelRepo new repository
+func NewRoleTagRelRepo(data *data.Data) role.RoleTagRelRepo {
+	return &roleTagRelRepo{
+		data: data,
+	}
+}
+
+// GetRoleTagIDs get the tags the role is scoped to
+func (rr *roleTagRelRepo) GetRoleTagIDs(ctx context.Context, roleID int) (tagIDs []string, err error) {
+	tagIDs = make([]string, 0)
+	err = rr.data.DB.Context(ctx).Table("role_tag_rel").
+		Cols("tag_id").Where(builder.Eq{"role_id": roleID}).Find(&tagIDs)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SaveRoleTags replace the tags the role is scoped to
+func (rr *roleTagRelRepo) SaveRoleTags(ctx context.Context, roleID int, tagIDs []string) (err error) {
+	_, err = rr.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
+		session = session.Context(ctx)
+		if _, err := session.Where(builder.Eq{"role_id": roleID}).Delete(&entity.RoleTagRel{}); err != nil {
+			return nil, err
+		}
+		if len(tagIDs) == 0 {
+			return nil, nil
+		}
+		rels := make([]*entity.RoleTagRel, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			rels = append(rels, &entity.RoleTagRel{RoleID: roleID, TagID: tagID})
+		}
+		_, err := session.Insert(rels)
+		return nil, err
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -23,6 +23,7 @@ import (
 	"context"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/role"
@@ -45,10 +46,10 @@ func NewUserRoleRelRepo(data *data.Data) role.UserRoleRelRepo {
 
 // SaveUserRoleRel save user role rel
 func (ur *userRoleRelRepo) SaveUserRoleRel(ctx context.Context, userID string, roleID int) (err error) {
-	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (any, error) {
+	_, err = ur.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
 		session = session.Context(ctx)
 		item := &entity.UserRoleRel{UserID: userID}
-		exist, err := session.Get(item)
+		exist, err := session.Where(data.TenantCond(ctx)).Get(item)
 		if err != nil {
 			return nil, err
 		}
@@ -56,7 +57,7 @@ func (ur *userRoleRelRepo) SaveUserRoleRel(ctx context.Context, userID string, r
 			item.RoleID = roleID
 			_, err = session.ID(item.ID).Update(item)
 		} else {
-			_, err = session.Insert(&entity.UserRoleRel{UserID: userID, RoleID: roleID})
+			_, err = session.Insert(&entity.UserRoleRel{TenantID: handler.GetTenantID(ctx), UserID: userID, RoleID: roleID})
 		}
 		if err != nil {
 			return nil, err
@@ -73,7 +74,7 @@ func (ur *userRoleRelRepo) SaveUserRoleRel(ctx context.Context, userID string, r
 func (ur *userRoleRelRepo) GetUserRoleRelList(ctx context.Context, userIDs []string) (
 	userRoleRelList []*entity.UserRoleRel, err error) {
 	userRoleRelList = make([]*entity.UserRoleRel, 0)
-	err = ur.data.DB.Context(ctx).In("user_id", userIDs).Find(&userRoleRelList)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("user_id", userIDs).Find(&userRoleRelList)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -84,7 +85,7 @@ func (ur *userRoleRelRepo) GetUserRoleRelList(ctx context.Context, userIDs []str
 func (ur *userRoleRelRepo) GetUserRoleRelListByRoleID(ctx context.Context, roleIDs []int) (
 	userRoleRelList []*entity.UserRoleRel, err error) {
 	userRoleRelList = make([]*entity.UserRoleRel, 0)
-	err = ur.data.DB.Context(ctx).In("role_id", roleIDs).Find(&userRoleRelList)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("role_id", roleIDs).Find(&userRoleRelList)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -95,7 +96,7 @@ func (ur *userRoleRelRepo) GetUserRoleRelListByRoleID(ctx context.Context, roleI
 func (ur *userRoleRelRepo) GetUserRoleRel(ctx context.Context, userID string) (
 	rolePowerRel *entity.UserRoleRel, exist bool, err error) {
 	rolePowerRel = &entity.UserRoleRel{}
-	exist, err = ur.data.DB.Context(ctx).Where(builder.Eq{"user_id": userID}).Get(rolePowerRel)
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"user_id": userID}).Get(rolePowerRel)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
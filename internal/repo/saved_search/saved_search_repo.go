@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package saved_search
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/saved_search"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type savedSearchRepo struct {
+	data *data.Data
+}
+
+// NewSavedSearchRepo creates a new saved search repository
+func NewSavedSearchRepo(data *data.Data) saved_search.SavedSearchRepo {
+	return &savedSearchRepo{
+		data: data,
+	}
+}
+
+func (sr *savedSearchRepo) AddSavedSearch(ctx context.Context, savedSearch *entity.SavedSearch) (err error) {
+	savedSearch.TenantID = handler.GetTenantID(ctx)
+	_, err = sr.data.DB.Context(ctx).Insert(savedSearch)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *savedSearchRepo) UpdateSavedSearch(ctx context.Context, savedSearch *entity.SavedSearch) (err error) {
+	_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(savedSearch.ID).Where("user_id = ?", savedSearch.UserID).
+		Cols("name", "query", "notify", "last_checked_at").Update(savedSearch)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *savedSearchRepo) UpdateLastCheckedAt(ctx context.Context, id int, lastCheckedAt time.Time) (err error) {
+	_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Cols("last_checked_at").
+		Update(&entity.SavedSearch{LastCheckedAt: lastCheckedAt})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *savedSearchRepo) DeleteSavedSearch(ctx context.Context, userID string, id int) (err error) {
+	_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Where("user_id = ?", userID).Delete(&entity.SavedSearch{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *savedSearchRepo) GetSavedSearch(ctx context.Context, userID string, id int) (
+	savedSearch *entity.SavedSearch, exist bool, err error) {
+	savedSearch = &entity.SavedSearch{}
+	exist, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Where("user_id = ?", userID).Get(savedSearch)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *savedSearchRepo) GetSavedSearchList(ctx context.Context, userID string) (list []*entity.SavedSearch, err error) {
+	list = make([]*entity.SavedSearch, 0)
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Desc("id").Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *savedSearchRepo) CountSavedSearch(ctx context.Context, userID string) (count int64, err error) {
+	count, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Count(&entity.SavedSearch{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *savedSearchRepo) GetNotifySavedSearchPage(ctx context.Context, page, pageSize int) (
+	list []*entity.SavedSearch, err error) {
+	list = make([]*entity.SavedSearch, 0)
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("notify = ?", true).Asc("id").
+		Limit(pageSize, (page-1)*pageSize).Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package scheduled_post
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/scheduled_post"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+type scheduledPostRepo struct {
+	data *data.Data
+}
+
+// NewScheduledPostRepo creates a new scheduled post repository
+func NewScheduledPostRepo(data *data.Data) scheduled_post.ScheduledPostRepo {
+	return &scheduledPostRepo{
+		data: data,
+	}
+}
+
+func (sr *scheduledPostRepo) GetUserScheduledQuestions(ctx context.Context, userID string) (
+	list []*entity.Question, err error) {
+	list = make([]*entity.Question, 0)
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND status = ?", userID, entity.QuestionStatusScheduled).
+		Asc("publish_at").Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *scheduledPostRepo) GetUserScheduledAnswers(ctx context.Context, userID string) (
+	list []*entity.Answer, err error) {
+	list = make([]*entity.Answer, 0)
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND status = ?", userID, entity.AnswerStatusScheduled).
+		Asc("publish_at").Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *scheduledPostRepo) GetDueScheduledQuestions(ctx context.Context, now time.Time, limit int) (
+	list []*entity.Question, err error) {
+	list = make([]*entity.Question, 0)
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ? AND publish_at <= ?", entity.QuestionStatusScheduled, now).
+		Asc("publish_at").Limit(limit).Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *scheduledPostRepo) GetDueScheduledAnswers(ctx context.Context, now time.Time, limit int) (
+	list []*entity.Answer, err error) {
+	list = make([]*entity.Answer, 0)
+	// the answers waiting for their question to be published are left out, so that they never hold the batch
+	waiting := builder.Select("id").From("question").
+		Where(builder.In("status", entity.QuestionStatusScheduled, entity.QuestionStatusPending))
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ? AND publish_at <= ?", entity.AnswerStatusScheduled, now).
+		And(builder.NotIn("question_id", waiting)).
+		Asc("publish_at").Limit(limit).Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *scheduledPostRepo) UpdateQuestionPublishAt(ctx context.Context, questionID string, publishAt time.Time) (
+	updated bool, err error) {
+	affected, err := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(uid.DeShortID(questionID)).
+		Where("status = ?", entity.QuestionStatusScheduled).
+		Cols("publish_at").Update(&entity.Question{PublishAt: publishAt})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+func (sr *scheduledPostRepo) UpdateAnswerPublishAt(ctx context.Context, answerID string, publishAt time.Time) (
+	updated bool, err error) {
+	affected, err := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(uid.DeShortID(answerID)).
+		Where("status = ?", entity.AnswerStatusScheduled).
+		Cols("publish_at").Update(&entity.Answer{PublishAt: publishAt})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// PublishQuestion make the scheduled question available, it is dated at the publish time
+func (sr *scheduledPostRepo) PublishQuestion(ctx context.Context, questionID string, now time.Time) (
+	published bool, err error) {
+	affected, err := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table(entity.Question{}.TableName()).
+		Where("id = ? AND status = ?", uid.DeShortID(questionID), entity.QuestionStatusScheduled).
+		Update(map[string]any{
+			"status":           entity.QuestionStatusAvailable,
+			"created_at":       now,
+			"post_update_time": now,
+		})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// PublishAnswer make the scheduled answer available, it is dated at the publish time
+func (sr *scheduledPostRepo) PublishAnswer(ctx context.Context, answerID string, now time.Time) (
+	published bool, err error) {
+	affected, err := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Table(entity.Answer{}.TableName()).
+		Where("id = ? AND status = ?", uid.DeShortID(answerID), entity.AnswerStatusScheduled).
+		Update(map[string]any{
+			"status":     entity.AnswerStatusAvailable,
+			"created_at": now,
+		})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
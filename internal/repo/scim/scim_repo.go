@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package scim
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/scim"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type scimRepo struct {
+	data *data.Data
+}
+
+// NewSCIMRepo new repository
+func NewSCIMRepo(data *data.Data) scim.SCIMRepo {
+	return &scimRepo{
+		data: data,
+	}
+}
+
+// GetUserPage get the users which are not deleted by page, in the order of creation
+func (sr *scimRepo) GetUserPage(ctx context.Context, page, pageSize int) (users []*entity.User, total int64, err error) {
+	users = make([]*entity.User, 0)
+	session := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status <> ?", entity.UserStatusDeleted).Asc("id")
+	total, err = pager.Help(page, pageSize, &users, &entity.User{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetUserExternalLogins get the external logins of the provider of the users
+func (sr *scimRepo) GetUserExternalLogins(ctx context.Context, provider string, userIDs []string) (
+	logins []*entity.UserExternalLogin, err error) {
+	logins = make([]*entity.UserExternalLogin, 0)
+	if len(userIDs) == 0 {
+		return logins, nil
+	}
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("provider = ?", provider).In("user_id", userIDs).Find(&logins)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
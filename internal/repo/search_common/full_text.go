@@ -0,0 +1,325 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_common
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/entity"
+	"github.com/segmentfault/pacman/log"
+	"xorm.io/builder"
+	"xorm.io/xorm/schemas"
+)
+
+const (
+	// snippetStartMark and snippetEndMark wrap the matched words in the snippet,
+	// they are replaced by html tags after the snippet is escaped.
+	snippetStartMark = "\x02"
+	snippetEndMark   = "\x03"
+)
+
+// fullTextSearch is the native full text search of the database, the objectType is question or answer.
+type fullTextSearch interface {
+	// matchCond the object matches all the terms, or the phrase if phrase is true
+	matchCond(objectType string, terms []string, phrase bool) builder.Cond
+	// relevanceField the relevance score of the object matches any of the words, the higher the better
+	relevanceField(objectType string, words []string) (field string, args []any)
+	// snippets get the snippets of the objects, the matched words are wrapped by snippet marks.
+	// If the database does not support it, return nil and the excerpt will be used.
+	snippets(ctx context.Context, objectIDs []string, words []string) (map[string]string, error)
+}
+
+// newFullTextSearch use the native full text search if the index is created, otherwise use LIKE
+func newFullTextSearch(ctx context.Context, db *data.DB) fullTextSearch {
+	var (
+		checkSQL string
+		ft       fullTextSearch
+	)
+	switch db.Dialect().URI().DBType {
+	case schemas.SQLITE:
+		checkSQL = fmt.Sprintf("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = '%s'", entity.SearchFullTextTableName)
+		ft = &sqliteFullText{db: db}
+	case schemas.POSTGRES:
+		checkSQL = fmt.Sprintf("SELECT COUNT(*) FROM pg_indexes WHERE indexname = '%s'", entity.SearchFullTextQuestionIndex)
+		ft = &postgresFullText{db: db}
+	case schemas.MYSQL:
+		checkSQL = fmt.Sprintf("SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND index_name = '%s'",
+			entity.SearchFullTextQuestionIndex)
+		ft = &mysqlFullText{}
+	default:
+		return &likeFullText{}
+	}
+	var count int64
+	if _, err := db.Context(ctx).SQL(checkSQL).Get(&count); err != nil || count == 0 {
+		log.Warnf("full text index is not found, search will use LIKE instead: %v", err)
+		return &likeFullText{}
+	}
+	return ft
+}
+
+func objectIDField(objectType string) string {
+	if objectType == constant.AnswerObjectType {
+		return "`answer`.`id`"
+	}
+	return "`question`.`id`"
+}
+
+func objectTextFields(objectType string) []string {
+	if objectType == constant.AnswerObjectType {
+		return answerTextFields
+	}
+	return questionTextFields
+}
+
+// likeFullText search by LIKE, it is used when the database does not have full text index
+type likeFullText struct{}
+
+func (lf *likeFullText) matchCond(objectType string, terms []string, _ bool) builder.Cond {
+	c := builder.NewCond()
+	for _, term := range terms {
+		termCond := builder.NewCond()
+		for _, field := range objectTextFields(objectType) {
+			termCond = termCond.Or(builder.Like{field, term})
+		}
+		c = c.And(termCond)
+	}
+	return c
+}
+
+// relevanceField the relevance is the total length of the matched words
+func (lf *likeFullText) relevanceField(objectType string, words []string) (field string, args []any) {
+	relevanceRes := make([]string, 0)
+	for _, searchField := range objectTextFields(objectType) {
+		replaced := searchField
+		for _, word := range words {
+			replaced = fmt.Sprintf("REPLACE(%s, ?, '')", replaced)
+			args = append(args, word)
+		}
+		relevanceRes = append(relevanceRes, fmt.Sprintf("(LENGTH(%s) - LENGTH(%s))", searchField, replaced))
+	}
+	return "(" + strings.Join(relevanceRes, " + ") + ")", args
+}
+
+func (lf *likeFullText) snippets(context.Context, []string, []string) (map[string]string, error) {
+	return nil, nil
+}
+
+// sqliteFullText search by the fts5 table, the rowid of the table is the object id
+type sqliteFullText struct {
+	db *data.DB
+}
+
+func (sf *sqliteFullText) matchCond(objectType string, terms []string, _ bool) builder.Cond {
+	return builder.Expr(fmt.Sprintf("%s IN (SELECT rowid FROM `%s` WHERE `%s` MATCH ?)",
+		objectIDField(objectType), entity.SearchFullTextTableName, entity.SearchFullTextTableName), ftsQuery(terms, " "))
+}
+
+// relevanceField the relevance is bm25 score, the title is more important than the content
+func (sf *sqliteFullText) relevanceField(objectType string, words []string) (field string, args []any) {
+	return fmt.Sprintf("COALESCE((SELECT -bm25(`%[1]s`, 10.0, 1.0) FROM `%[1]s` WHERE `%[1]s` MATCH ? AND rowid = %[2]s), 0)",
+		entity.SearchFullTextTableName, objectIDField(objectType)), []any{ftsQuery(words, " OR ")}
+}
+
+func (sf *sqliteFullText) snippets(ctx context.Context, objectIDs []string, words []string) (map[string]string, error) {
+	b := builder.Select("rowid AS id", "snippet(`"+entity.SearchFullTextTableName+"`, -1, char(2), char(3), '...', 32) AS snippet").
+		From("`" + entity.SearchFullTextTableName + "`").
+		Where(builder.Expr("`"+entity.SearchFullTextTableName+"` MATCH ?", ftsQuery(words, " OR "))).
+		And(builder.In("rowid", objectIDs))
+	return querySnippets(ctx, sf.db, b)
+}
+
+// ftsQuery quote each word as a fts5 string, so the special characters are not treated as operators
+func ftsQuery(words []string, sep string) string {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		quoted = append(quoted, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(quoted, sep)
+}
+
+// postgresFullText search by the tsvector GIN index, the tsvector expression must be the same as the index
+type postgresFullText struct {
+	db *data.DB
+}
+
+func (pf *postgresFullText) tsvector(objectType string) string {
+	if objectType == constant.AnswerObjectType {
+		return fmt.Sprintf("to_tsvector('%s', `answer`.`original_text`)", entity.SearchTextSearchConfig)
+	}
+	return fmt.Sprintf("to_tsvector('%s', `question`.`title` || ' ' || `question`.`original_text`)", entity.SearchTextSearchConfig)
+}
+
+// matchCond all the terms are in one tsquery, so the stop words in the terms are ignored
+func (pf *postgresFullText) matchCond(objectType string, terms []string, phrase bool) builder.Cond {
+	fn := "plainto_tsquery"
+	if phrase {
+		fn = "phraseto_tsquery"
+	}
+	return builder.Expr(fmt.Sprintf("%s @@ %s('%s', ?)", pf.tsvector(objectType), fn, entity.SearchTextSearchConfig),
+		strings.Join(terms, " "))
+}
+
+func (pf *postgresFullText) relevanceField(objectType string, words []string) (field string, args []any) {
+	return fmt.Sprintf("ts_rank_cd(%s, websearch_to_tsquery('%s', ?))", pf.tsvector(objectType), entity.SearchTextSearchConfig),
+		[]any{websearchQuery(words)}
+}
+
+func (pf *postgresFullText) snippets(ctx context.Context, objectIDs []string, words []string) (map[string]string, error) {
+	headline := fmt.Sprintf("ts_headline('%s', `original_text`, websearch_to_tsquery('%s', ?), ?) AS snippet",
+		entity.SearchTextSearchConfig, entity.SearchTextSearchConfig)
+	options := "StartSel=" + snippetStartMark + ", StopSel=" + snippetEndMark +
+		`, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" ... "`
+	query := websearchQuery(words)
+	b := builder.Select("`id`", headline).From("`question`").Where(builder.In("`id`", objectIDs)).
+		Union("all", builder.Select("`id`", headline).From("`answer`").Where(builder.In("`id`", objectIDs)))
+	sql, args, err := b.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	// the headline args are in front of the ids in each select
+	queryArgs := []any{sql, query, options}
+	queryArgs = append(queryArgs, args[:len(objectIDs)]...)
+	queryArgs = append(queryArgs, query, options)
+	queryArgs = append(queryArgs, args[len(objectIDs):]...)
+	res, err := pf.db.Context(ctx).Query(queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return parseSnippets(res), nil
+}
+
+// websearchQuery quote each word and combine them with or
+func websearchQuery(words []string) string {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		quoted = append(quoted, `"`+strings.ReplaceAll(word, `"`, " ")+`"`)
+	}
+	return strings.Join(quoted, " or ")
+}
+
+// mysqlFullText search by the FULLTEXT index, the MATCH columns must be the same as the index
+type mysqlFullText struct{}
+
+var (
+	// mysqlFullTextMinTokenSize is the default innodb_ft_min_token_size, the shorter words are not indexed
+	mysqlFullTextMinTokenSize = 3
+	// mysqlFullTextStopwords is the default stopwords of innodb, they are not indexed
+	mysqlFullTextStopwords = map[string]bool{
+		"a": true, "about": true, "an": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+		"com": true, "de": true, "en": true, "for": true, "from": true, "how": true, "i": true, "in": true,
+		"is": true, "it": true, "la": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+		"this": true, "to": true, "was": true, "what": true, "when": true, "where": true, "who": true,
+		"will": true, "with": true, "und": true, "www": true,
+	}
+	mysqlBooleanOperatorRegexp = regexp.MustCompile(`[+\-<>()~*"@]+`)
+)
+
+func (mf *mysqlFullText) matchFields(objectType string) string {
+	return "MATCH(" + strings.Join(objectTextFields(objectType), ", ") + ")"
+}
+
+// indexed check all the words in the text are indexed by the FULLTEXT index
+func (mf *mysqlFullText) indexed(text string) bool {
+	words := strings.Fields(mysqlBooleanOperatorRegexp.ReplaceAllString(text, " "))
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < mysqlFullTextMinTokenSize || mysqlFullTextStopwords[strings.ToLower(word)] {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// matchCond the words which are not indexed are matched by LIKE
+func (mf *mysqlFullText) matchCond(objectType string, terms []string, phrase bool) builder.Cond {
+	var (
+		c       = builder.NewCond()
+		indexed = make([]string, 0, len(terms))
+		like    = &likeFullText{}
+	)
+	for _, term := range terms {
+		if !mf.indexed(term) {
+			c = c.And(like.matchCond(objectType, []string{term}, phrase))
+			continue
+		}
+		indexed = append(indexed, `+"`+mysqlBooleanOperatorRegexp.ReplaceAllString(term, " ")+`"`)
+	}
+	if len(indexed) > 0 {
+		c = c.And(builder.Expr(mf.matchFields(objectType)+" AGAINST (? IN BOOLEAN MODE)", strings.Join(indexed, " ")))
+	}
+	return c
+}
+
+func (mf *mysqlFullText) relevanceField(objectType string, words []string) (field string, args []any) {
+	return mf.matchFields(objectType) + " AGAINST (? IN NATURAL LANGUAGE MODE)", []any{strings.Join(words, " ")}
+}
+
+func (mf *mysqlFullText) snippets(context.Context, []string, []string) (map[string]string, error) {
+	return nil, nil
+}
+
+func querySnippets(ctx context.Context, db *data.DB, b *builder.Builder) (map[string]string, error) {
+	res, err := db.Context(ctx).Query(b)
+	if err != nil {
+		return nil, err
+	}
+	return parseSnippets(res), nil
+}
+
+func parseSnippets(res []map[string][]byte) map[string]string {
+	snippets := make(map[string]string, len(res))
+	for _, r := range res {
+		snippets[string(r["id"])] = string(r["snippet"])
+	}
+	return snippets
+}
+
+// markWords wrap the words in the text by snippet marks, it is used when the database can not generate snippets
+func markWords(text string, words []string) string {
+	patterns := make([]string, 0, len(words))
+	for _, word := range words {
+		if len(strings.TrimSpace(word)) > 0 {
+			patterns = append(patterns, regexp.QuoteMeta(word))
+		}
+	}
+	if len(patterns) == 0 {
+		return text
+	}
+	// match the longer word first
+	sort.Slice(patterns, func(i, j int) bool { return len(patterns[i]) > len(patterns[j]) })
+	re, err := regexp.Compile("(?i)" + strings.Join(patterns, "|"))
+	if err != nil {
+		return text
+	}
+	return re.ReplaceAllString(text, snippetStartMark+"${0}"+snippetEndMark)
+}
+
+// renderSnippet escape the snippet and replace the snippet marks by <em> tags
+func renderSnippet(snippet string) string {
+	snippet = html.EscapeString(strings.Join(strings.Fields(snippet), " "))
+	return strings.NewReplacer(snippetStartMark, "<em>", snippetEndMark, "</em>").Replace(snippet)
+}
@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_common
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/plugin"
+	"xorm.io/builder"
+)
+
+var (
+	questionTextFields = []string{"`question`.`title`", "`question`.`original_text`"}
+	answerTextFields   = []string{"`answer`.`original_text`"}
+)
+
+// buildQuestionCond build the question search condition
+func buildQuestionCond(ctx context.Context, cond *schema.SearchCondition, ft fullTextSearch) builder.Cond {
+	c := builder.And(
+		data.TenantCond(ctx, "question"),
+		builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted},
+		builder.Eq{"`question`.`show`": entity.QuestionShow},
+		builder.Eq{"`question`.`space_id`": cond.SpaceID},
+		buildExprCond(cond.Expr, ft, constant.QuestionObjectType),
+	)
+	if len(cond.UserID) > 0 {
+		c = c.And(builder.Eq{"`question`.`user_id`": cond.UserID})
+	}
+	c = c.And(amountCond("`question`.`vote_count`", cond.VoteAmount))
+	if cond.Views > -1 {
+		c = c.And(builder.Gte{"`question`.`view_count`": cond.Views})
+	}
+	c = c.And(amountCond("`question`.`answer_count`", cond.AnswerAmount))
+	if cond.NotAccepted {
+		c = c.And(builder.Eq{"`question`.`accepted_answer_id`": 0})
+	} else if cond.HasAccepted {
+		c = c.And(builder.Gt{"`question`.`accepted_answer_id`": 0})
+	}
+	switch cond.Closed {
+	case plugin.ClosedCondTrue:
+		c = c.And(builder.Eq{"`question`.`status`": entity.QuestionStatusClosed})
+	case plugin.ClosedCondFalse:
+		c = c.And(builder.Neq{"`question`.`status`": entity.QuestionStatusClosed})
+	}
+	return c.And(
+		timeRangeCond("`question`.`created_at`", cond.Created),
+		timeRangeCond("`question`.`updated_at`", cond.Updated),
+		langCond("`question`.`original_text`", cond.Lang),
+	)
+}
+
+// buildAnswerCond build the answer search condition, the answer table must be joined with question table
+func buildAnswerCond(ctx context.Context, cond *schema.SearchCondition, ft fullTextSearch) builder.Cond {
+	c := builder.And(
+		data.TenantCond(ctx, "answer"),
+		builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted},
+		builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted},
+		builder.Eq{"`question`.`show`": entity.QuestionShow},
+		builder.Eq{"`question`.`space_id`": cond.SpaceID},
+		buildExprCond(cond.Expr, ft, constant.AnswerObjectType),
+	)
+	if len(cond.UserID) > 0 {
+		c = c.And(builder.Eq{"`answer`.`user_id`": cond.UserID})
+	}
+	c = c.And(amountCond("`answer`.`vote_count`", cond.VoteAmount))
+	if cond.Accepted {
+		c = c.And(builder.Eq{"`answer`.`adopted`": schema.AnswerAcceptedEnable})
+	}
+	if len(cond.QuestionID) > 0 {
+		c = c.And(builder.Eq{"`answer`.`question_id`": cond.QuestionID})
+	}
+	return c.And(
+		timeRangeCond("`answer`.`created_at`", cond.Created),
+		timeRangeCond("`answer`.`updated_at`", cond.Updated),
+		langCond("`answer`.`original_text`", cond.Lang),
+	)
+}
+
+// buildExprCond convert the search expression to condition.
+// The keywords and phrases are matched by the full text search, the tags match the question's tags.
+func buildExprCond(expr *plugin.SearchExpr, ft fullTextSearch, objectType string) builder.Cond {
+	if expr == nil {
+		return builder.NewCond()
+	}
+	switch expr.Type {
+	case plugin.SearchExprAnd, plugin.SearchExprOr:
+		var (
+			conds = make([]builder.Cond, 0, len(expr.Children))
+			terms = make([]string, 0)
+		)
+		for _, child := range expr.Children {
+			// the terms should all be matched are searched together
+			if expr.Type == plugin.SearchExprAnd && child.Type == plugin.SearchExprTerm {
+				terms = append(terms, child.Value)
+				continue
+			}
+			conds = append(conds, buildExprCond(child, ft, objectType))
+		}
+		if len(terms) > 0 {
+			conds = append(conds, ft.matchCond(objectType, terms, false))
+		}
+		if expr.Type == plugin.SearchExprAnd {
+			return builder.And(conds...)
+		}
+		return builder.Or(conds...)
+	case plugin.SearchExprNot:
+		if len(expr.Children) == 0 {
+			return builder.NewCond()
+		}
+		c := buildExprCond(expr.Children[0], ft, objectType)
+		if !c.IsValid() {
+			return c
+		}
+		return builder.Not{c}
+	case plugin.SearchExprTerm, plugin.SearchExprPhrase:
+		return ft.matchCond(objectType, []string{expr.Value}, expr.Type == plugin.SearchExprPhrase)
+	case plugin.SearchExprTag:
+		if len(expr.TagIDs) == 0 {
+			return builder.NewCond()
+		}
+		questionIDField := "`question`.`id`"
+		if objectType == constant.AnswerObjectType {
+			questionIDField = "`answer`.`question_id`"
+		}
+		return builder.In(questionIDField, builder.Select("object_id").From("tag_rel").
+			Where(builder.Eq{"status": entity.TagRelStatusAvailable}.And(builder.In("tag_id", expr.TagIDs))))
+	}
+	return builder.NewCond()
+}
+
+// amountCond the amount 0 means equal to 0, greater than 0 means at least, less than 0 means no limit
+func amountCond(field string, amount int) builder.Cond {
+	switch {
+	case amount == 0:
+		return builder.Eq{field: 0}
+	case amount > 0:
+		return builder.Gte{field: amount}
+	}
+	return builder.NewCond()
+}
+
+// timeRangeCond the time range is [from, to), zero means no limit
+func timeRangeCond(field string, timeRange plugin.SearchTimeRange) builder.Cond {
+	c := builder.NewCond()
+	if timeRange.From > 0 {
+		c = c.And(builder.Gte{field: time.Unix(timeRange.From, 0)})
+	}
+	if timeRange.To > 0 {
+		c = c.And(builder.Lt{field: time.Unix(timeRange.To, 0)})
+	}
+	return c
+}
+
+// langCond match the content which has the code block of the language, such as ```go
+func langCond(field, lang string) builder.Cond {
+	if len(lang) == 0 {
+		return builder.NewCond()
+	}
+	fence := "```" + lang
+	return builder.Or(
+		builder.Like{field, fence + "\n"},
+		builder.Like{field, fence + "\r"},
+		builder.Like{field, fence + " "},
+	)
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/pkg/converter"
+	"github.com/apache/answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+)
+
+// facetTopSize the number of the top tags and authors in the facets
+const facetTopSize = 10
+
+var (
+	qFacetFields = []string{
+		"`question`.`id` as `question_id`",
+		"`question`.`user_id` as `user_id`",
+		"`question`.`created_at` as `created_at`",
+		"CASE WHEN `question`.`accepted_answer_id` > 0 THEN 1 ELSE 0 END as `accepted`",
+		"CASE WHEN `question`.`answer_count` > 0 THEN 1 ELSE 0 END as `answered`",
+	}
+	aFacetFields = []string{
+		"`answer`.`question_id` as `question_id`",
+		"`answer`.`user_id` as `user_id`",
+		"`answer`.`created_at` as `created_at`",
+		"CASE WHEN `question`.`accepted_answer_id` > 0 THEN 1 ELSE 0 END as `accepted`",
+		"1 as `answered`",
+	}
+)
+
+// SearchFacets aggregate all the contents matched by the search condition
+func (sr *searchRepo) SearchFacets(ctx context.Context, cond *schema.SearchCondition) (facets *plugin.SearchFacets, err error) {
+	ft := sr.fullText(ctx)
+	var (
+		parts []string
+		args  []any
+	)
+	if !cond.SearchAnswer() {
+		bSQL, bArgs, err := builder.MySQL().Select(qFacetFields...).From("`question`").
+			Where(buildQuestionCond(ctx, cond, ft)).ToSQL()
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		parts = append(parts, bSQL)
+		args = append(args, bArgs...)
+	}
+	if !cond.SearchQuestion() {
+		ubSQL, ubArgs, err := builder.MySQL().Select(aFacetFields...).From("`answer`").
+			LeftJoin("`question`", "`question`.id = `answer`.question_id").
+			Where(buildAnswerCond(ctx, cond, ft)).ToSQL()
+		if err != nil {
+			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+		}
+		parts = append(parts, ubSQL)
+		args = append(args, ubArgs...)
+	}
+	subQuery := "(" + strings.Join(parts, " UNION ALL ") + ")"
+
+	facets = &plugin.SearchFacets{}
+	if err = sr.facetSummary(ctx, subQuery, args, facets); err != nil {
+		return nil, err
+	}
+	if facets.Total == 0 {
+		return facets, nil
+	}
+
+	facets.Users, err = sr.facetCounts(ctx, fmt.Sprintf("SELECT `user_id` as `value`, COUNT(*) as `cnt` FROM %s f "+
+		"GROUP BY `user_id` ORDER BY `cnt` DESC, `user_id` LIMIT %d", subQuery, facetTopSize), args)
+	if err != nil {
+		return nil, err
+	}
+	facets.Tags, err = sr.facetCounts(ctx, fmt.Sprintf("SELECT `tag_rel`.`tag_id` as `value`, COUNT(*) as `cnt` FROM %s f "+
+		"INNER JOIN `tag_rel` ON `tag_rel`.`object_id` = f.`question_id` WHERE `tag_rel`.`status` = %d "+
+		"GROUP BY `tag_rel`.`tag_id` ORDER BY `cnt` DESC, `tag_rel`.`tag_id` LIMIT %d",
+		subQuery, entity.TagRelStatusAvailable, facetTopSize), args)
+	if err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// facetSummary count the total, accepted, answered and the date buckets in one query
+func (sr *searchRepo) facetSummary(ctx context.Context, subQuery string, args []any, facets *plugin.SearchFacets) error {
+	fields := []string{
+		"COUNT(*) as `total`",
+		"COALESCE(SUM(`accepted`), 0) as `accepted`",
+		"COALESCE(SUM(`answered`), 0) as `answered`",
+	}
+	now := time.Now()
+	bucketArgs := make([]any, 0, len(plugin.SearchDateBuckets))
+	for i, bucket := range plugin.SearchDateBuckets {
+		fields = append(fields, fmt.Sprintf("COALESCE(SUM(CASE WHEN `created_at` >= ? THEN 1 ELSE 0 END), 0) as `created_%d`", i))
+		bucketArgs = append(bucketArgs, bucket.Since(now))
+	}
+	summarySQL := fmt.Sprintf("SELECT %s FROM %s f", strings.Join(fields, ", "), subQuery)
+
+	// the bucket args are in front of the sub query args
+	res, err := sr.data.DB.Context(ctx).Query(append([]any{summarySQL}, append(bucketArgs, args...)...)...)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	facets.Total = converter.StringToInt64(string(res[0]["total"]))
+	facets.Accepted = converter.StringToInt64(string(res[0]["accepted"]))
+	facets.Answered = converter.StringToInt64(string(res[0]["answered"]))
+	for i, bucket := range plugin.SearchDateBuckets {
+		facets.Created = append(facets.Created, plugin.SearchFacetCount{
+			Value: string(bucket),
+			Count: converter.StringToInt64(string(res[0][fmt.Sprintf("created_%d", i)])),
+		})
+	}
+	return nil
+}
+
+// facetCounts query the value and count pairs of a facet
+func (sr *searchRepo) facetCounts(ctx context.Context, querySQL string, args []any) (counts []plugin.SearchFacetCount, err error) {
+	res, err := sr.data.DB.Context(ctx).Query(append([]any{querySQL}, args...)...)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	counts = make([]plugin.SearchFacetCount, 0, len(res))
+	for _, row := range res {
+		counts = append(counts, plugin.SearchFacetCount{
+			Value: string(row["value"]),
+			Count: converter.StringToInt64(string(row["cnt"])),
+		})
+	}
+	return counts, nil
+}
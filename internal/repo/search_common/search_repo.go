@@ -22,8 +22,8 @@ package search_common
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	tagcommon "github.com/apache/answer/internal/service/tag_common"
@@ -31,6 +31,7 @@ import (
 
 	"github.com/apache/answer/pkg/htmltext"
 
+	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/data"
 	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
@@ -43,6 +44,7 @@ import (
 	"github.com/apache/answer/pkg/obj"
 	"github.com/apache/answer/pkg/uid"
 	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
 	"xorm.io/builder"
 )
 
@@ -81,6 +83,9 @@ type searchRepo struct {
 	userCommon   *usercommon.UserCommon
 	uniqueIDRepo unique.UniqueIDRepo
 	tagCommon    *tagcommon.TagCommonService
+
+	fullTextOnce   sync.Once
+	fullTextSearch fullTextSearch
 }
 
 // NewSearchRepo new repository
@@ -99,347 +104,129 @@ func NewSearchRepo(
 }
 
 // SearchContents search question and answer data
-func (sr *searchRepo) SearchContents(ctx context.Context, words []string, tagIDs [][]string, userID string, votes int, page, pageSize int, order string) (resp []*schema.SearchResult, total int64, err error) {
-	words = filterWords(words)
+func (sr *searchRepo) SearchContents(ctx context.Context, cond *schema.SearchCondition, page, pageSize int, order string) (resp []*schema.SearchResult, total int64, err error) {
+	words := filterWords(cond.Words)
 
 	var (
-		b     *builder.Builder
-		ub    *builder.Builder
 		qfs   = qFields
 		afs   = aFields
 		argsQ = []any{}
 		argsA = []any{}
 	)
 
+	ft := sr.fullText(ctx)
 	if order == "relevance" {
 		if len(words) > 0 {
-			qfs, argsQ = addRelevanceField([]string{"title", "original_text"}, words, qfs)
-			afs, argsA = addRelevanceField([]string{"`answer`.`original_text`"}, words, afs)
+			qfs, argsQ = addRelevanceField(ft, constant.QuestionObjectType, words, qfs)
+			afs, argsA = addRelevanceField(ft, constant.AnswerObjectType, words, afs)
 		} else {
 			order = "newest"
 		}
 	}
 
-	b = builder.MySQL().Select(qfs...).From("`question`")
-	ub = builder.MySQL().Select(afs...).From("`answer`").
-		LeftJoin("`question`", "`question`.id = `answer`.question_id")
-
-	b.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).
-		And(builder.Eq{"`question`.`show`": entity.QuestionShow})
-	ub.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).
-		And(builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted}).
-		And(builder.Eq{"`question`.`show`": entity.QuestionShow})
-
-	argsQ = append(argsQ, entity.QuestionStatusDeleted, entity.QuestionShow)
-	argsA = append(argsA, entity.QuestionStatusDeleted, entity.AnswerStatusDeleted, entity.QuestionShow)
-
-	likeConQ := builder.NewCond()
-	likeConA := builder.NewCond()
-	for _, word := range words {
-		likeConQ = likeConQ.Or(builder.Like{"title", word}).
-			Or(builder.Like{"original_text", word})
-		argsQ = append(argsQ, "%"+word+"%")
-		argsQ = append(argsQ, "%"+word+"%")
-
-		likeConA = likeConA.Or(builder.Like{"`answer`.original_text", word})
-		argsA = append(argsA, "%"+word+"%")
-	}
-
-	b.Where(likeConQ)
-	ub.Where(likeConA)
-
-	// check tag
-	for ti, tagID := range tagIDs {
-		ast := "tag_rel" + strconv.Itoa(ti)
-		b.Join("INNER", "tag_rel as "+ast, "question.id = "+ast+".object_id").
-			And(builder.Eq{
-				ast + ".status": entity.TagRelStatusAvailable,
-			}).
-			And(builder.In(ast+".tag_id", tagID))
-		ub.Join("INNER", "tag_rel as "+ast, "question_id = "+ast+".object_id").
-			And(builder.Eq{
-				ast + ".status": entity.TagRelStatusAvailable,
-			}).
-			And(builder.In(ast+".tag_id", tagID))
-		argsQ = append(argsQ, entity.TagRelStatusAvailable)
-		argsA = append(argsA, entity.TagRelStatusAvailable)
-		for _, t := range tagID {
-			argsQ = append(argsQ, t)
-			argsA = append(argsA, t)
-		}
-	}
-
-	// check user
-	if userID != "" {
-		b.Where(builder.Eq{"question.user_id": userID})
-		ub.Where(builder.Eq{"answer.user_id": userID})
-		argsQ = append(argsQ, userID)
-		argsA = append(argsA, userID)
-	}
-
-	// check vote
-	if votes == 0 {
-		b.Where(builder.Eq{"question.vote_count": votes})
-		ub.Where(builder.Eq{"answer.vote_count": votes})
-		argsQ = append(argsQ, votes)
-		argsA = append(argsA, votes)
-	} else if votes > 0 {
-		b.Where(builder.Gte{"question.vote_count": votes})
-		ub.Where(builder.Gte{"answer.vote_count": votes})
-		argsQ = append(argsQ, votes)
-		argsA = append(argsA, votes)
-	}
-
-	// b = b.Union("all", ub)
-	ubSQL, _, err := ub.ToSQL()
-	if err != nil {
-		return
-	}
-	bSQL, _, err := b.ToSQL()
-	if err != nil {
-		return
-	}
-	sql := fmt.Sprintf("(%s UNION ALL %s)", bSQL, ubSQL)
+	b := builder.MySQL().Select(qfs...).From("`question`").Where(buildQuestionCond(ctx, cond, ft))
+	ub := builder.MySQL().Select(afs...).From("`answer`").
+		LeftJoin("`question`", "`question`.id = `answer`.question_id").
+		Where(buildAnswerCond(ctx, cond, ft))
 
-	countSQL, _, err := builder.MySQL().Select("count(*) total").From(sql, "c").ToSQL()
+	bSQL, bArgs, err := b.ToSQL()
 	if err != nil {
 		return
 	}
-
-	startNum := (page - 1) * pageSize
-	querySQL, _, err := builder.MySQL().Select("*").From(sql, "t").OrderBy(sr.parseOrder(ctx, order)).Limit(pageSize, startNum).ToSQL()
+	ubSQL, ubArgs, err := ub.ToSQL()
 	if err != nil {
 		return
 	}
 
-	queryArgs := []any{}
-	countArgs := []any{}
-
-	queryArgs = append(queryArgs, querySQL)
-	queryArgs = append(queryArgs, argsQ...)
-	queryArgs = append(queryArgs, argsA...)
-
-	countArgs = append(countArgs, countSQL)
-	countArgs = append(countArgs, argsQ...)
-	countArgs = append(countArgs, argsA...)
-
-	res, err := sr.data.DB.Context(ctx).Query(queryArgs...)
-	if err != nil {
-		return
-	}
-
-	tr, err := sr.data.DB.Context(ctx).Query(countArgs...)
-	if len(tr) != 0 {
-		total = converter.StringToInt64(string(tr[0]["total"]))
-	}
-	if err != nil {
-		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
-		return
-	} else {
-		resp, err = sr.parseResult(ctx, res, words)
-		return
-	}
+	// the relevance field args are in front of the condition args
+	args := append(argsQ, bArgs...)
+	args = append(args, argsA...)
+	args = append(args, ubArgs...)
+	return sr.searchPage(ctx, fmt.Sprintf("(%s UNION ALL %s)", bSQL, ubSQL), args, words, page, pageSize, order)
 }
 
 // SearchQuestions search question data
-func (sr *searchRepo) SearchQuestions(ctx context.Context, words []string, tagIDs [][]string, notAccepted bool, views, answers int, page, pageSize int, order string) (resp []*schema.SearchResult, total int64, err error) {
-	words = filterWords(words)
+func (sr *searchRepo) SearchQuestions(ctx context.Context, cond *schema.SearchCondition, page, pageSize int, order string) (resp []*schema.SearchResult, total int64, err error) {
+	words := filterWords(cond.Words)
 	var (
 		qfs  = qFields
 		args = []any{}
 	)
+	ft := sr.fullText(ctx)
 	if order == "relevance" {
 		if len(words) > 0 {
-			qfs, args = addRelevanceField([]string{"title", "original_text"}, words, qfs)
+			qfs, args = addRelevanceField(ft, constant.QuestionObjectType, words, qfs)
 		} else {
 			order = "newest"
 		}
 	}
 
-	b := builder.MySQL().Select(qfs...).From("question")
-
-	b.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).And(builder.Eq{"`question`.`show`": entity.QuestionShow})
-	args = append(args, entity.QuestionStatusDeleted, entity.QuestionShow)
-
-	likeConQ := builder.NewCond()
-	for _, word := range words {
-		likeConQ = likeConQ.Or(builder.Like{"title", word}).
-			Or(builder.Like{"original_text", word})
-		args = append(args, "%"+word+"%")
-		args = append(args, "%"+word+"%")
-	}
-	b.Where(likeConQ)
-
-	// check tag
-	for ti, tagID := range tagIDs {
-		ast := "tag_rel" + strconv.Itoa(ti)
-		b.Join("INNER", "tag_rel as "+ast, "question.id = "+ast+".object_id").
-			And(builder.Eq{
-				ast + ".status": entity.TagRelStatusAvailable,
-			}).
-			And(builder.In(ast+".tag_id", tagID))
-		args = append(args, entity.TagRelStatusAvailable)
-		for _, t := range tagID {
-			args = append(args, t)
-		}
-	}
-
-	// check need filter has not accepted
-	if notAccepted {
-		b.And(builder.Eq{"accepted_answer_id": 0})
-		args = append(args, 0)
-	}
-
-	// check views
-	if views > -1 {
-		b.And(builder.Gte{"view_count": views})
-		args = append(args, views)
-	}
-
-	// check answers
-	if answers == 0 {
-		b.And(builder.Eq{"answer_count": answers})
-		args = append(args, answers)
-	} else if answers > 0 {
-		b.And(builder.Gte{"answer_count": answers})
-		args = append(args, answers)
-	}
-
-	if answers == 0 {
-		b.And(builder.Eq{"answer_count": 0})
-		args = append(args, 0)
-	} else if answers > 0 {
-		b.And(builder.Gte{"answer_count": answers})
-		args = append(args, answers)
-	}
-
-	queryArgs := []any{}
-	countArgs := []any{}
-
-	countSQL, _, err := builder.MySQL().Select("count(*) total").From(b, "c").ToSQL()
-	if err != nil {
-		return
-	}
-
-	startNum := (page - 1) * pageSize
-	querySQL, _, err := b.OrderBy(sr.parseOrder(ctx, order)).Limit(pageSize, startNum).ToSQL()
-	if err != nil {
-		return
-	}
-	queryArgs = append(queryArgs, querySQL)
-	queryArgs = append(queryArgs, args...)
-
-	countArgs = append(countArgs, countSQL)
-	countArgs = append(countArgs, args...)
-
-	res, err := sr.data.DB.Context(ctx).Query(queryArgs...)
+	b := builder.MySQL().Select(qfs...).From("`question`").Where(buildQuestionCond(ctx, cond, ft))
+	bSQL, bArgs, err := b.ToSQL()
 	if err != nil {
 		return
 	}
-
-	tr, err := sr.data.DB.Context(ctx).Query(countArgs...)
-	if err != nil {
-		return
-	}
-
-	if len(tr) != 0 {
-		total = converter.StringToInt64(string(tr[0]["total"]))
-	}
-	resp, err = sr.parseResult(ctx, res, words)
-	if err != nil {
-		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
-	}
-	return
+	args = append(args, bArgs...)
+	return sr.searchPage(ctx, "("+bSQL+")", args, words, page, pageSize, order)
 }
 
 // SearchAnswers search answer data
-func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs [][]string, accepted bool, questionID string, page, pageSize int, order string) (resp []*schema.SearchResult, total int64, err error) {
-	words = filterWords(words)
+func (sr *searchRepo) SearchAnswers(ctx context.Context, cond *schema.SearchCondition, page, pageSize int, order string) (resp []*schema.SearchResult, total int64, err error) {
+	words := filterWords(cond.Words)
 
 	var (
 		afs  = aFields
 		args = []any{}
 	)
+	ft := sr.fullText(ctx)
 	if order == "relevance" {
 		if len(words) > 0 {
-			afs, args = addRelevanceField([]string{"`answer`.`original_text`"}, words, afs)
+			afs, args = addRelevanceField(ft, constant.AnswerObjectType, words, afs)
 		} else {
 			order = "newest"
 		}
 	}
 
 	b := builder.MySQL().Select(afs...).From("`answer`").
-		LeftJoin("`question`", "`question`.id = `answer`.question_id")
-
-	b.Where(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).
-		And(builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted}).And(builder.Eq{"`question`.`show`": entity.QuestionShow})
-	args = append(args, entity.QuestionStatusDeleted, entity.AnswerStatusDeleted, entity.QuestionShow)
-
-	likeConA := builder.NewCond()
-	for _, word := range words {
-		likeConA = likeConA.Or(builder.Like{"`answer`.original_text", word})
-		args = append(args, "%"+word+"%")
-	}
-
-	b.Where(likeConA)
-
-	// check tag
-	for ti, tagID := range tagIDs {
-		ast := "tag_rel" + strconv.Itoa(ti)
-		b.Join("INNER", "tag_rel as "+ast, "question_id = "+ast+".object_id").
-			And(builder.Eq{
-				ast + ".status": entity.TagRelStatusAvailable,
-			}).
-			And(builder.In(ast+".tag_id", tagID))
-		args = append(args, entity.TagRelStatusAvailable)
-		for _, t := range tagID {
-			args = append(args, t)
-		}
-	}
-
-	// check limit accepted
-	if accepted {
-		b.Where(builder.Eq{"adopted": schema.AnswerAcceptedEnable})
-		args = append(args, schema.AnswerAcceptedEnable)
-	}
-
-	// check question id
-	if questionID != "" {
-		b.Where(builder.Eq{"question_id": questionID})
-		args = append(args, questionID)
+		LeftJoin("`question`", "`question`.id = `answer`.question_id").
+		Where(buildAnswerCond(ctx, cond, ft))
+	bSQL, bArgs, err := b.ToSQL()
+	if err != nil {
+		return
 	}
+	args = append(args, bArgs...)
+	return sr.searchPage(ctx, "("+bSQL+")", args, words, page, pageSize, order)
+}
 
-	queryArgs := []any{}
-	countArgs := []any{}
-
-	countSQL, _, err := builder.MySQL().Select("count(*) total").From(b, "c").ToSQL()
+// searchPage query the page of search result and the total count from the sub query
+func (sr *searchRepo) searchPage(ctx context.Context, subQuery string, args []any, words []string, page, pageSize int, order string) (
+	resp []*schema.SearchResult, total int64, err error) {
+	countSQL, _, err := builder.MySQL().Select("count(*) total").From(subQuery, "c").ToSQL()
 	if err != nil {
 		return
 	}
 
 	startNum := (page - 1) * pageSize
-	querySQL, _, err := b.OrderBy(sr.parseOrder(ctx, order)).Limit(pageSize, startNum).ToSQL()
+	querySQL, _, err := builder.MySQL().Select("*").From(subQuery, "t").OrderBy(sr.parseOrder(ctx, order)).Limit(pageSize, startNum).ToSQL()
 	if err != nil {
 		return
 	}
 
-	queryArgs = append(queryArgs, querySQL)
-	queryArgs = append(queryArgs, args...)
-
-	countArgs = append(countArgs, countSQL)
-	countArgs = append(countArgs, args...)
-
-	res, err := sr.data.DB.Context(ctx).Query(queryArgs...)
+	res, err := sr.data.DB.Context(ctx).Query(append([]any{querySQL}, args...)...)
 	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		return
 	}
 
-	tr, err := sr.data.DB.Context(ctx).Query(countArgs...)
+	tr, err := sr.data.DB.Context(ctx).Query(append([]any{countSQL}, args...)...)
 	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		return
 	}
+	if len(tr) != 0 {
+		total = converter.StringToInt64(string(tr[0]["total"]))
+	}
 
-	total = converter.StringToInt64(string(tr[0]["total"]))
 	resp, err = sr.parseResult(ctx, res, words)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -447,6 +234,14 @@ func (sr *searchRepo) SearchAnswers(ctx context.Context, words []string, tagIDs
 	return
 }
 
+// fullText get the full text search of the database, the index is checked only once
+func (sr *searchRepo) fullText(ctx context.Context) fullTextSearch {
+	sr.fullTextOnce.Do(func() {
+		sr.fullTextSearch = newFullTextSearch(ctx, sr.data.DB)
+	})
+	return sr.fullTextSearch
+}
+
 func (sr *searchRepo) parseOrder(_ context.Context, order string) (res string) {
 	switch order {
 	case "newest":
@@ -474,10 +269,12 @@ func (sr *searchRepo) ParseSearchPluginResult(ctx context.Context, sres []plugin
 		switch r.Type {
 		case "question":
 			b = builder.MySQL().Select(qFields...).From("question").Where(builder.Eq{"id": r.ID}).
+				And(data.TenantCond(ctx)).
 				And(builder.Lt{"`status`": entity.QuestionStatusDeleted})
 		case "answer":
 			b = builder.MySQL().Select(aFields...).From("answer").LeftJoin("`question`", "`question`.`id` = `answer`.`question_id`").
 				Where(builder.Eq{"`answer`.`id`": r.ID}).
+				And(data.TenantCond(ctx, "answer")).
 				And(builder.Lt{"`question`.`status`": entity.QuestionStatusDeleted}).
 				And(builder.Lt{"`answer`.`status`": entity.AnswerStatusDeleted}).And(builder.Eq{"`question`.`show`": entity.QuestionShow})
 		}
@@ -494,6 +291,7 @@ func (sr *searchRepo) ParseSearchPluginResult(ctx context.Context, sres []plugin
 func (sr *searchRepo) parseResult(ctx context.Context, res []map[string][]byte, words []string) (resp []*schema.SearchResult, err error) {
 	questionIDs := make([]string, 0)
 	userIDs := make([]string, 0)
+	objectIDs := make([]string, 0)
 	resultList := make([]*schema.SearchResult, 0)
 	for _, r := range res {
 		questionIDs = append(questionIDs, string(r["question_id"]))
@@ -545,6 +343,7 @@ func (sr *searchRepo) parseResult(ctx context.Context, res []map[string][]byte,
 			}
 		}
 
+		objectIDs = append(objectIDs, string(r["id"]))
 		resultList = append(resultList, &schema.SearchResult{
 			ObjectType: objectKey,
 			Object:     object,
@@ -560,7 +359,15 @@ func (sr *searchRepo) parseResult(ctx context.Context, res []map[string][]byte,
 		return nil, err
 	}
 
-	for _, item := range resultList {
+	snippets := sr.getSnippets(ctx, objectIDs, words)
+
+	for i, item := range resultList {
+		snippet, ok := snippets[objectIDs[i]]
+		if !ok {
+			snippet = markWords(item.Object.Excerpt, words)
+		}
+		item.Object.Highlight = renderSnippet(snippet)
+
 		tags, ok := tagsMap[item.Object.QuestionID]
 		if ok {
 			item.Object.Tags = tags
@@ -575,37 +382,24 @@ func (sr *searchRepo) parseResult(ctx context.Context, res []map[string][]byte,
 	return resultList, nil
 }
 
-func addRelevanceField(searchFields, words, fields []string) (res []string, args []any) {
-	relevanceRes := []string{}
-	args = []any{}
-
-	for _, searchField := range searchFields {
-		var (
-			relevance    = "(LENGTH(" + searchField + ") - LENGTH(%s))"
-			replacement  = "REPLACE(%s, ?, '')"
-			replaceField = searchField
-			replaced     string
-			argsField    = []any{}
-		)
-
-		res = fields
-		for i, word := range words {
-			if i == 0 {
-				argsField = append(argsField, word)
-				replaced = fmt.Sprintf(replacement, replaceField)
-			} else {
-				argsField = append(argsField, word)
-				replaced = fmt.Sprintf(replacement, replaced)
-			}
-		}
-		args = append(args, argsField...)
-
-		relevance = fmt.Sprintf(relevance, replaced)
-		relevanceRes = append(relevanceRes, relevance)
+// getSnippets get the highlighted snippets by the full text search, the excerpt will be used if failed
+func (sr *searchRepo) getSnippets(ctx context.Context, objectIDs, words []string) map[string]string {
+	if len(objectIDs) == 0 || len(words) == 0 {
+		return nil
+	}
+	snippets, err := sr.fullText(ctx).snippets(ctx, objectIDs, words)
+	if err != nil {
+		log.Warnf("get search snippets failed: %v", err)
+		return nil
 	}
+	return snippets
+}
 
-	res = append(res, "("+strings.Join(relevanceRes, " + ")+") as relevance")
-	return
+// addRelevanceField add the relevance field to the select fields, return the args of the relevance field
+func addRelevanceField(ft fullTextSearch, objectType string, words, fields []string) (res []string, args []any) {
+	field, args := ft.relevanceField(objectType, words)
+	res = append(append(make([]string, 0, len(fields)+1), fields...), field+" as relevance")
+	return res, args
 }
 
 func filterWords(words []string) (res []string) {
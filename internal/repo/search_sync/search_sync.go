@@ -24,6 +24,7 @@ import (
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/pkg/uid"
@@ -43,7 +44,7 @@ func (p *PluginSyncer) GetAnswersPage(ctx context.Context, page, pageSize int) (
 	answerList []*plugin.SearchContent, err error) {
 	answers := make([]*entity.Answer, 0)
 	startNum := (page - 1) * pageSize
-	err = p.data.DB.Context(ctx).Limit(pageSize, startNum).Find(&answers)
+	err = p.data.DB.Context(ctx).Where("space_id = ?", entity.PublicSpaceID).Limit(pageSize, startNum).Find(&answers)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +55,7 @@ func (p *PluginSyncer) GetQuestionsPage(ctx context.Context, page, pageSize int)
 	questionList []*plugin.SearchContent, err error) {
 	questions := make([]*entity.Question, 0)
 	startNum := (page - 1) * pageSize
-	err = p.data.DB.Context(ctx).Limit(pageSize, startNum).Find(&questions)
+	err = p.data.DB.Context(ctx).Where("space_id = ?", entity.PublicSpaceID).Limit(pageSize, startNum).Find(&questions)
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +65,9 @@ func (p *PluginSyncer) GetQuestionsPage(ctx context.Context, page, pageSize int)
 func (p *PluginSyncer) convertAnswers(ctx context.Context, answers []*entity.Answer) (
 	answerList []*plugin.SearchContent, err error) {
 	for _, answer := range answers {
+		ctx := handler.WithTenantID(ctx, answer.TenantID)
 		question := &entity.Question{}
-		exist, err := p.data.DB.Context(ctx).Where("id = ?", answer.QuestionID).Get(question)
+		exist, err := p.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", answer.QuestionID).Get(question)
 		if err != nil {
 			log.Errorf("get question failed %s", err)
 			continue
@@ -76,7 +78,7 @@ func (p *PluginSyncer) convertAnswers(ctx context.Context, answers []*entity.Ans
 
 		tagListList := make([]*entity.TagRel, 0)
 		tags := make([]string, 0)
-		err = p.data.DB.Context(ctx).Where("object_id = ?", uid.DeShortID(question.ID)).
+		err = p.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", uid.DeShortID(question.ID)).
 			Where("status = ?", entity.TagRelStatusAvailable).Find(&tagListList)
 		if err != nil {
 			log.Errorf("get tag list failed %s", err)
@@ -86,6 +88,7 @@ func (p *PluginSyncer) convertAnswers(ctx context.Context, answers []*entity.Ans
 		}
 
 		content := &plugin.SearchContent{
+			TenantID:    answer.TenantID,
 			ObjectID:    answer.ID,
 			Title:       question.Title,
 			Type:        constant.AnswerObjectType,
@@ -109,9 +112,10 @@ func (p *PluginSyncer) convertAnswers(ctx context.Context, answers []*entity.Ans
 func (p *PluginSyncer) convertQuestions(ctx context.Context, questions []*entity.Question) (
 	questionList []*plugin.SearchContent, err error) {
 	for _, question := range questions {
+		ctx := handler.WithTenantID(ctx, question.TenantID)
 		tagListList := make([]*entity.TagRel, 0)
 		tags := make([]string, 0)
-		err := p.data.DB.Context(ctx).Where("object_id = ?", question.ID).
+		err := p.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", question.ID).
 			Where("status = ?", entity.TagRelStatusAvailable).Find(&tagListList)
 		if err != nil {
 			log.Errorf("get tag list failed %s", err)
@@ -120,6 +124,7 @@ func (p *PluginSyncer) convertQuestions(ctx context.Context, questions []*entity
 			tags = append(tags, tag.TagID)
 		}
 		content := &plugin.SearchContent{
+			TenantID:    question.TenantID,
 			ObjectID:    question.ID,
 			Title:       question.Title,
 			Type:        constant.QuestionObjectType,
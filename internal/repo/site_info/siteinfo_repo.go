@@ -25,6 +25,7 @@ import (
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/siteinfo_common"
@@ -44,21 +45,22 @@ func NewSiteInfo(data *data.Data) siteinfo_common.SiteInfoRepo {
 }
 
 // SaveByType save site setting by type
-func (sr *siteInfoRepo) SaveByType(ctx context.Context, siteType string, data *entity.SiteInfo) (err error) {
+func (sr *siteInfoRepo) SaveByType(ctx context.Context, siteType string, siteInfo *entity.SiteInfo) (err error) {
 	old := &entity.SiteInfo{}
-	exist, err := sr.data.DB.Context(ctx).Where(builder.Eq{"type": siteType}).Get(old)
+	exist, err := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"type": siteType}).Get(old)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	if exist {
-		_, err = sr.data.DB.Context(ctx).ID(old.ID).Update(data)
+		_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(old.ID).Update(siteInfo)
 	} else {
-		_, err = sr.data.DB.Context(ctx).Insert(data)
+		siteInfo.TenantID = handler.GetTenantID(ctx)
+		_, err = sr.data.DB.Context(ctx).Insert(siteInfo)
 	}
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
-	sr.setCache(ctx, siteType, data)
+	sr.setCache(ctx, siteType, siteInfo)
 	return
 }
 
@@ -71,7 +73,7 @@ func (sr *siteInfoRepo) GetByType(ctx context.Context, siteType string, withoutC
 		}
 	}
 	siteInfo = &entity.SiteInfo{}
-	exist, err = sr.data.DB.Context(ctx).Where(builder.Eq{"type": siteType}).Get(siteInfo)
+	exist, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(builder.Eq{"type": siteType}).Get(siteInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		return nil, false, err
@@ -106,7 +108,7 @@ func (sr *siteInfoRepo) setCache(ctx context.Context, siteType string, siteInfo
 
 func (sr *siteInfoRepo) IsBrandingFileUsed(ctx context.Context, filePath string) (bool, error) {
 	siteInfo := &entity.SiteInfo{}
-	count, err := sr.data.DB.Context(ctx).
+	count, err := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).
 		Table("site_info").
 		Where(builder.Eq{"type": "branding"}).
 		And(builder.Like{"content", "%" + filePath + "%"}).
@@ -0,0 +1,190 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package space
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/space_common"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+type spaceRepo struct {
+	data *data.Data
+}
+
+// NewSpaceRepo creates a new space repository
+func NewSpaceRepo(data *data.Data) space_common.SpaceRepo {
+	return &spaceRepo{
+		data: data,
+	}
+}
+
+// AddSpace add the space with its first owner
+func (sr *spaceRepo) AddSpace(ctx context.Context, space *entity.Space, owner *entity.SpaceMember) (err error) {
+	_, err = sr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		space.TenantID = handler.GetTenantID(ctx)
+		if _, err = session.Insert(space); err != nil {
+			return nil, err
+		}
+		owner.TenantID = space.TenantID
+		owner.SpaceID = space.ID
+		_, err = session.Insert(owner)
+		return nil, err
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) UpdateSpace(ctx context.Context, space *entity.Space, cols []string) (err error) {
+	_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(space.ID).Cols(cols...).Update(space)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) GetSpace(ctx context.Context, id int) (space *entity.Space, exist bool, err error) {
+	space = &entity.Space{}
+	exist, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Get(space)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) GetSpaceBySlugName(ctx context.Context, slugName string) (
+	space *entity.Space, exist bool, err error) {
+	space = &entity.Space{}
+	exist, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("slug_name = ?", slugName).Get(space)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) GetSpaceListByIDs(ctx context.Context, ids []int) (spaces []*entity.Space, err error) {
+	spaces = make([]*entity.Space, 0)
+	if len(ids) == 0 {
+		return spaces, nil
+	}
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("id", ids).Asc("display_name").Find(&spaces)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetSpacePage get the available spaces, the query matches the slug name or display name
+func (sr *spaceRepo) GetSpacePage(ctx context.Context, page, pageSize int, query string) (
+	spaces []*entity.Space, total int64, err error) {
+	spaces = make([]*entity.Space, 0)
+	session := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ?", entity.SpaceStatusAvailable)
+	if len(query) > 0 {
+		session.And("slug_name LIKE ? OR display_name LIKE ?", query+"%", query+"%")
+	}
+	session.Desc("id")
+	total, err = pager.Help(page, pageSize, &spaces, &entity.Space{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) AddSpaceMember(ctx context.Context, member *entity.SpaceMember) (err error) {
+	member.TenantID = handler.GetTenantID(ctx)
+	_, err = sr.data.DB.Context(ctx).Insert(member)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) UpdateSpaceMemberRole(ctx context.Context, spaceID int, userID string, role int) (err error) {
+	_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("space_id = ? AND user_id = ?", spaceID, userID).
+		Cols("role").Update(&entity.SpaceMember{Role: role})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) RemoveSpaceMember(ctx context.Context, spaceID int, userID string) (err error) {
+	_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("space_id = ? AND user_id = ?", spaceID, userID).
+		Delete(&entity.SpaceMember{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) GetSpaceMember(ctx context.Context, spaceID int, userID string) (
+	member *entity.SpaceMember, exist bool, err error) {
+	member = &entity.SpaceMember{}
+	exist, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("space_id = ? AND user_id = ?", spaceID, userID).Get(member)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetSpaceMemberPage get the members of the space, the owners first
+func (sr *spaceRepo) GetSpaceMemberPage(ctx context.Context, spaceID, page, pageSize int) (
+	members []*entity.SpaceMember, total int64, err error) {
+	members = make([]*entity.SpaceMember, 0)
+	session := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("space_id = ?", spaceID).Asc("role", "id")
+	total, err = pager.Help(page, pageSize, &members, &entity.SpaceMember{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *spaceRepo) GetUserSpaceMemberList(ctx context.Context, userID string) (
+	members []*entity.SpaceMember, err error) {
+	members = make([]*entity.SpaceMember, 0)
+	err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Find(&members)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// CountSpaceMember count the members of the space, all roles are counted if the role is 0
+func (sr *spaceRepo) CountSpaceMember(ctx context.Context, spaceID, role int) (count int64, err error) {
+	session := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("space_id = ?", spaceID)
+	if role > 0 {
+		session.And("role = ?", role)
+	}
+	count, err = session.Count(&entity.SpaceMember{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
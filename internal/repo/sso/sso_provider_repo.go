@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package sso
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/sso"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type ssoProviderRepo struct {
+	data *data.Data
+}
+
+// NewSSOProviderRepo new repository
+func NewSSOProviderRepo(data *data.Data) sso.SSOProviderRepo {
+	return &ssoProviderRepo{
+		data: data,
+	}
+}
+
+func (sr *ssoProviderRepo) AddSSOProvider(ctx context.Context, provider *entity.SSOProvider) (err error) {
+	provider.TenantID = handler.GetTenantID(ctx)
+	_, err = sr.data.DB.Context(ctx).Insert(provider)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *ssoProviderRepo) UpdateSSOProvider(ctx context.Context, provider *entity.SSOProvider) (err error) {
+	_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(provider.ID).
+		Cols("slug_name", "name", "protocol", "enabled", "config", "group_role_mapping").Update(provider)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *ssoProviderRepo) DeleteSSOProvider(ctx context.Context, id int) (err error) {
+	_, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Delete(&entity.SSOProvider{})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *ssoProviderRepo) GetSSOProvider(ctx context.Context, id int) (
+	provider *entity.SSOProvider, exist bool, err error) {
+	provider = &entity.SSOProvider{}
+	exist, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(id).Get(provider)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *ssoProviderRepo) GetSSOProviderBySlugName(ctx context.Context, slugName string) (
+	provider *entity.SSOProvider, exist bool, err error) {
+	provider = &entity.SSOProvider{}
+	exist, err = sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("slug_name = ?", slugName).Get(provider)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (sr *ssoProviderRepo) GetSSOProviderList(ctx context.Context, onlyEnabled bool) (
+	providers []*entity.SSOProvider, err error) {
+	providers = make([]*entity.SSOProvider, 0)
+	session := sr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Asc("id")
+	if onlyEnabled {
+		session.Where("enabled = ?", true)
+	}
+	if err = session.Find(&providers); err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
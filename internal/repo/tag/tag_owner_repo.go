@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tag
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/tag_common"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/xorm"
+)
+
+// tagOwnerRepo tag owner repository
+type tagOwnerRepo struct {
+	data *data.Data
+}
+
+// NewTagOwnerRepo new repository
+func NewTagOwnerRepo(data *data.Data) tag_common.TagOwnerRepo {
+	return &tagOwnerRepo{
+		data: data,
+	}
+}
+
+// GetTagOwnerList get the owners of the tags
+func (tr *tagOwnerRepo) GetTagOwnerList(ctx context.Context, tagIDs []string) (owners []*entity.TagOwner, err error) {
+	owners = make([]*entity.TagOwner, 0)
+	err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("tag_id", tagIDs).Asc("id").Find(&owners)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// IsTagOwner check whether the user is the owner of the tag
+func (tr *tagOwnerRepo) IsTagOwner(ctx context.Context, tagID, userID string) (isOwner bool, err error) {
+	isOwner, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Exist(&entity.TagOwner{TenantID: handler.GetTenantID(ctx), TagID: tagID, UserID: userID})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ReplaceTagOwners replace all the owners of the tag
+func (tr *tagOwnerRepo) ReplaceTagOwners(ctx context.Context, tagID string, userIDs []string) (err error) {
+	_, err = tr.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
+		session = session.Context(ctx)
+		if _, err := session.Where(data.TenantCond(ctx)).And("tag_id = ?", tagID).Delete(&entity.TagOwner{}); err != nil {
+			return nil, err
+		}
+		owners := make([]*entity.TagOwner, 0, len(userIDs))
+		for _, userID := range userIDs {
+			owners = append(owners, &entity.TagOwner{TenantID: handler.GetTenantID(ctx), TagID: tagID, UserID: userID})
+		}
+		if len(owners) == 0 {
+			return nil, nil
+		}
+		_, err := session.Insert(owners)
+		return nil, err
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -52,6 +52,7 @@ func NewTagRelRepo(data *data.Data,
 func (tr *tagRelRepo) AddTagRelList(ctx context.Context, tagList []*entity.TagRel) (err error) {
 	for _, item := range tagList {
 		item.ObjectID = uid.DeShortID(item.ObjectID)
+		item.TenantID = handler.GetTenantID(ctx)
 	}
 	_, err = tr.data.DB.Context(ctx).Insert(tagList)
 	if err != nil {
@@ -68,7 +69,7 @@ func (tr *tagRelRepo) AddTagRelList(ctx context.Context, tagList []*entity.TagRe
 // RemoveTagRelListByObjectID delete tag list
 func (tr *tagRelRepo) RemoveTagRelListByObjectID(ctx context.Context, objectID string) (err error) {
 	objectID = uid.DeShortID(objectID)
-	_, err = tr.data.DB.Context(ctx).Where("object_id = ?", objectID).Update(&entity.TagRel{Status: entity.TagRelStatusDeleted})
+	_, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID).Update(&entity.TagRel{Status: entity.TagRelStatusDeleted})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -78,7 +79,7 @@ func (tr *tagRelRepo) RemoveTagRelListByObjectID(ctx context.Context, objectID s
 // RecoverTagRelListByObjectID recover tag list
 func (tr *tagRelRepo) RecoverTagRelListByObjectID(ctx context.Context, objectID string) (err error) {
 	objectID = uid.DeShortID(objectID)
-	_, err = tr.data.DB.Context(ctx).Where("object_id = ?", objectID).Update(&entity.TagRel{Status: entity.TagRelStatusAvailable})
+	_, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID).Update(&entity.TagRel{Status: entity.TagRelStatusAvailable})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -87,7 +88,7 @@ func (tr *tagRelRepo) RecoverTagRelListByObjectID(ctx context.Context, objectID
 
 func (tr *tagRelRepo) HideTagRelListByObjectID(ctx context.Context, objectID string) (err error) {
 	objectID = uid.DeShortID(objectID)
-	_, err = tr.data.DB.Context(ctx).Where("object_id = ?", objectID).And("status = ?", entity.TagRelStatusAvailable).Cols("status").Update(&entity.TagRel{Status: entity.TagRelStatusHide})
+	_, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID).And("status = ?", entity.TagRelStatusAvailable).Cols("status").Update(&entity.TagRel{Status: entity.TagRelStatusHide})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -96,7 +97,7 @@ func (tr *tagRelRepo) HideTagRelListByObjectID(ctx context.Context, objectID str
 
 func (tr *tagRelRepo) ShowTagRelListByObjectID(ctx context.Context, objectID string) (err error) {
 	objectID = uid.DeShortID(objectID)
-	_, err = tr.data.DB.Context(ctx).Where("object_id = ?", objectID).And("status = ?", entity.TagRelStatusHide).Cols("status").Update(&entity.TagRel{Status: entity.TagRelStatusAvailable})
+	_, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID).And("status = ?", entity.TagRelStatusHide).Cols("status").Update(&entity.TagRel{Status: entity.TagRelStatusAvailable})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -105,7 +106,7 @@ func (tr *tagRelRepo) ShowTagRelListByObjectID(ctx context.Context, objectID str
 
 // RemoveTagRelListByIDs delete tag list
 func (tr *tagRelRepo) RemoveTagRelListByIDs(ctx context.Context, ids []int64) (err error) {
-	_, err = tr.data.DB.Context(ctx).In("id", ids).Update(&entity.TagRel{Status: entity.TagRelStatusDeleted})
+	_, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("id", ids).Update(&entity.TagRel{Status: entity.TagRelStatusDeleted})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -118,7 +119,7 @@ func (tr *tagRelRepo) GetObjectTagRelWithoutStatus(ctx context.Context, objectID
 ) {
 	objectID = uid.DeShortID(objectID)
 	tagRel = &entity.TagRel{}
-	session := tr.data.DB.Context(ctx).Where("object_id = ?", objectID).And("tag_id = ?", tagID)
+	session := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID).And("tag_id = ?", tagID)
 	exist, err = session.Get(tagRel)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -136,7 +137,7 @@ func (tr *tagRelRepo) EnableTagRelByIDs(ctx context.Context, ids []int64, hide b
 	if hide {
 		status = entity.TagRelStatusHide
 	}
-	_, err = tr.data.DB.Context(ctx).In("id", ids).Update(&entity.TagRel{Status: status})
+	_, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("id", ids).Update(&entity.TagRel{Status: status})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -147,7 +148,7 @@ func (tr *tagRelRepo) EnableTagRelByIDs(ctx context.Context, ids []int64, hide b
 func (tr *tagRelRepo) GetObjectTagRelList(ctx context.Context, objectID string) (tagListList []*entity.TagRel, err error) {
 	objectID = uid.DeShortID(objectID)
 	tagListList = make([]*entity.TagRel, 0)
-	session := tr.data.DB.Context(ctx).Where("object_id = ?", objectID)
+	session := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", objectID)
 	session.In("status", []int{entity.TagRelStatusAvailable, entity.TagRelStatusHide})
 	err = session.Find(&tagListList)
 	if err != nil {
@@ -168,7 +169,7 @@ func (tr *tagRelRepo) BatchGetObjectTagRelList(ctx context.Context, objectIds []
 		objectIds[num] = uid.DeShortID(item)
 	}
 	tagListList = make([]*entity.TagRel, 0)
-	session := tr.data.DB.Context(ctx).In("object_id", objectIds)
+	session := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("object_id", objectIds)
 	session.Where("status = ?", entity.TagRelStatusAvailable)
 	err = session.Find(&tagListList)
 	if err != nil {
@@ -185,7 +186,7 @@ func (tr *tagRelRepo) BatchGetObjectTagRelList(ctx context.Context, objectIds []
 
 // CountTagRelByTagID count tag relation
 func (tr *tagRelRepo) CountTagRelByTagID(ctx context.Context, tagID string) (count int64, err error) {
-	count, err = tr.data.DB.Context(ctx).Count(&entity.TagRel{TagID: tagID, Status: entity.AnswerStatusAvailable})
+	count, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Count(&entity.TagRel{TagID: tagID, Status: entity.AnswerStatusAvailable})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -196,7 +197,7 @@ func (tr *tagRelRepo) CountTagRelByTagID(ctx context.Context, tagID string) (cou
 func (tr *tagRelRepo) GetTagRelDefaultStatusByObjectID(ctx context.Context, objectID string) (status int, err error) {
 	question := entity.Question{}
 	objectID = uid.DeShortID(objectID)
-	exist, err := tr.data.DB.Context(ctx).ID(objectID).Cols("show", "status").Get(&question)
+	exist, err := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(objectID).Cols("show", "status").Get(&question)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		return
@@ -209,17 +210,17 @@ func (tr *tagRelRepo) GetTagRelDefaultStatusByObjectID(ctx context.Context, obje
 
 // MigrateTagObjects migrate tag objects
 func (tr *tagRelRepo) MigrateTagObjects(ctx context.Context, sourceTagId, targetTagId string) error {
-	_, err := tr.data.DB.Transaction(func(session *xorm.Session) (result any, err error) {
+	_, err := tr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
 		// 1. Get all objects related to source tag
 		var sourceObjects []entity.TagRel
-		err = session.Where("tag_id = ?", sourceTagId).Find(&sourceObjects)
+		err = session.Where(data.TenantCond(ctx)).And("tag_id = ?", sourceTagId).Find(&sourceObjects)
 		if err != nil {
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
 
 		// 2. Get existing target tag relations
 		var existingTargets []entity.TagRel
-		err = session.Where("tag_id = ?", targetTagId).Find(&existingTargets)
+		err = session.Where(data.TenantCond(ctx)).And("tag_id = ?", targetTagId).Find(&existingTargets)
 		if err != nil {
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
@@ -235,6 +236,7 @@ func (tr *tagRelRepo) MigrateTagObjects(ctx context.Context, sourceTagId, target
 		for _, source := range sourceObjects {
 			if !existingMap[source.ObjectID] {
 				newRelations = append(newRelations, &entity.TagRel{
+					TenantID: handler.GetTenantID(ctx),
 					TagID:    targetTagId,
 					ObjectID: source.ObjectID,
 					Status:   source.Status,
@@ -250,7 +252,7 @@ func (tr *tagRelRepo) MigrateTagObjects(ctx context.Context, sourceTagId, target
 		}
 
 		// 4. Remove old relations
-		_, err = session.Where("tag_id = ?", sourceTagId).Delete(&entity.TagRel{})
+		_, err = session.Where(data.TenantCond(ctx)).And("tag_id = ?", sourceTagId).Delete(&entity.TagRel{})
 		if err != nil {
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tag
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/tag_common"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// tagSettingRepo tag setting repository
+type tagSettingRepo struct {
+	data *data.Data
+}
+
+// NewTagSettingRepo new repository
+func NewTagSettingRepo(data *data.Data) tag_common.TagSettingRepo {
+	return &tagSettingRepo{
+		data: data,
+	}
+}
+
+// GetTagSettingList get the settings of the tags
+func (tr *tagSettingRepo) GetTagSettingList(ctx context.Context, tagIDs []string) (settings []*entity.TagSetting, err error) {
+	settings = make([]*entity.TagSetting, 0)
+	err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("tag_id", tagIDs).Find(&settings)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// SaveTagSetting add or update the setting of the tag
+func (tr *tagSettingRepo) SaveTagSetting(ctx context.Context, setting *entity.TagSetting) (err error) {
+	old := &entity.TagSetting{}
+	exist, err := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("tag_id = ?", setting.TagID).Get(old)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		_, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(old.ID).Cols("question_template", "companion_tags").Update(setting)
+	} else {
+		setting.TenantID = handler.GetTenantID(ctx)
+		_, err = tr.data.DB.Context(ctx).Insert(setting)
+	}
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tag_suggestion
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/tag_suggestion"
+	"github.com/segmentfault/pacman/errors"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// tagTermStatBatchSize the count of the rows inserted at once
+const tagTermStatBatchSize = 500
+
+type tagSuggestionRepo struct {
+	data *data.Data
+}
+
+// NewTagSuggestionRepo creates a new tag suggestion repository
+func NewTagSuggestionRepo(data *data.Data) tag_suggestion.TagSuggestionRepo {
+	return &tagSuggestionRepo{
+		data: data,
+	}
+}
+
+// GetTrainingQuestions get the visible questions whose id is greater than the after id
+func (tr *tagSuggestionRepo) GetTrainingQuestions(ctx context.Context, afterID string, limit int) (
+	questions []*entity.Question, err error) {
+	questions = make([]*entity.Question, 0)
+	cond := builder.And(
+		builder.In("status", entity.QuestionStatusAvailable, entity.QuestionStatusClosed),
+		builder.Eq{"`show`": entity.QuestionShow},
+	)
+	if len(afterID) > 0 {
+		cond = cond.And(builder.Gt{"id": afterID})
+	}
+	err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Cols("id", "title", "parsed_text").Where(cond).
+		Asc("id").Limit(limit).Find(&questions)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// GetTagTermStatList get the stats of the terms, including the question count of each term
+func (tr *tagSuggestionRepo) GetTagTermStatList(ctx context.Context, terms []string) (
+	stats []*entity.TagTermStat, err error) {
+	stats = make([]*entity.TagTermStat, 0)
+	err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("term", terms).Find(&stats)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// ReplaceTagTermStats remove all the old stats and save the new stats
+func (tr *tagSuggestionRepo) ReplaceTagTermStats(ctx context.Context, stats []*entity.TagTermStat) (err error) {
+	_, err = tr.data.DB.Transaction(ctx, func(session *xorm.Session) (result any, err error) {
+		session = session.Context(ctx)
+		if _, err = session.Where(data.TenantCond(ctx)).Delete(&entity.TagTermStat{}); err != nil {
+			return nil, err
+		}
+		for _, stat := range stats {
+			stat.TenantID = handler.GetTenantID(ctx)
+		}
+		for start := 0; start < len(stats); start += tagTermStatBatchSize {
+			end := min(start+tagTermStatBatchSize, len(stats))
+			if _, err = session.Insert(stats[start:end]); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tenant
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/tenant"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type tenantRepo struct {
+	data *data.Data
+}
+
+// NewTenantRepo creates a new tenant repository
+func NewTenantRepo(data *data.Data) tenant.TenantRepo {
+	return &tenantRepo{
+		data: data,
+	}
+}
+
+func (tr *tenantRepo) AddTenant(ctx context.Context, tenant *entity.Tenant) (err error) {
+	_, err = tr.data.DB.Context(ctx).Insert(tenant)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *tenantRepo) UpdateTenant(ctx context.Context, tenant *entity.Tenant, cols []string) (err error) {
+	_, err = tr.data.DB.Context(ctx).ID(tenant.ID).Cols(cols...).Update(tenant)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *tenantRepo) GetTenant(ctx context.Context, id int) (tenant *entity.Tenant, exist bool, err error) {
+	tenant = &entity.Tenant{}
+	exist, err = tr.data.DB.Context(ctx).ID(id).Get(tenant)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *tenantRepo) GetTenantByHost(ctx context.Context, host string) (tenant *entity.Tenant, exist bool, err error) {
+	tenant = &entity.Tenant{}
+	exist, err = tr.data.DB.Context(ctx).Where("host = ?", host).Get(tenant)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *tenantRepo) GetTenantPage(ctx context.Context, page, pageSize int) (
+	tenants []*entity.Tenant, total int64, err error) {
+	tenants = make([]*entity.Tenant, 0)
+	session := tr.data.DB.Context(ctx).Desc("id")
+	total, err = pager.Help(page, pageSize, &tenants, &entity.Tenant{}, session)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *tenantRepo) GetAvailableTenantList(ctx context.Context) (tenants []*entity.Tenant, err error) {
+	tenants = make([]*entity.Tenant, 0)
+	err = tr.data.DB.Context(ctx).Where("status = ?", entity.TenantStatusAvailable).Asc("id").Find(&tenants)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
@@ -0,0 +1,204 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package two_factor
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/two_factor"
+	"github.com/segmentfault/pacman/errors"
+)
+
+type twoFactorRepo struct {
+	data *data.Data
+}
+
+// NewTwoFactorRepo new repository
+func NewTwoFactorRepo(data *data.Data) two_factor.TwoFactorRepo {
+	return &twoFactorRepo{
+		data: data,
+	}
+}
+
+func (tr *twoFactorRepo) GetUserTwoFactor(ctx context.Context, userID string) (
+	info *entity.UserTwoFactor, exist bool, err error) {
+	info = &entity.UserTwoFactor{}
+	exist, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Get(info)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *twoFactorRepo) SaveUserTwoFactor(ctx context.Context, info *entity.UserTwoFactor) (err error) {
+	old := &entity.UserTwoFactor{}
+	exist, err := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", info.UserID).Get(old)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if exist {
+		_, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(old.ID).
+			Cols("totp_secret", "totp_enabled", "totp_last_step", "recovery_codes").Update(info)
+	} else {
+		info.TenantID = handler.GetTenantID(ctx)
+		_, err = tr.data.DB.Context(ctx).Insert(info)
+	}
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateTOTPLastStep record the step of the accepted passcode,
+// it fails if the step or a later one has been used, which makes the passcode single use
+func (tr *twoFactorRepo) UpdateTOTPLastStep(ctx context.Context, userID string, step int64) (updated bool, err error) {
+	affected, err := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND totp_last_step < ?", userID, step).
+		Cols("totp_last_step").Update(&entity.UserTwoFactor{TOTPLastStep: step})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+// UpdateRecoveryCodes replace the recovery codes only if they are still the old ones,
+// so that a recovery code can not be used twice by concurrent requests
+func (tr *twoFactorRepo) UpdateRecoveryCodes(ctx context.Context, userID, oldCodes, newCodes string) (
+	updated bool, err error) {
+	affected, err := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND recovery_codes = ?", userID, oldCodes).
+		Cols("recovery_codes").Update(&entity.UserTwoFactor{RecoveryCodes: newCodes})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+func (tr *twoFactorRepo) RemoveUserTwoFactor(ctx context.Context, userID string) (err error) {
+	if _, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Delete(&entity.UserTwoFactor{}); err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if _, err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Delete(&entity.UserWebAuthnCredential{}); err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
+func (tr *twoFactorRepo) AddWebAuthnCredential(ctx context.Context, cred *entity.UserWebAuthnCredential) (err error) {
+	cred.TenantID = handler.GetTenantID(ctx)
+	_, err = tr.data.DB.Context(ctx).Insert(cred)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *twoFactorRepo) GetWebAuthnCredentials(ctx context.Context, userID string) (
+	creds []*entity.UserWebAuthnCredential, err error) {
+	creds = make([]*entity.UserWebAuthnCredential, 0)
+	err = tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Asc("id").Find(&creds)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateWebAuthnSignCount record the use of the credential, it fails if the counter has moved on meanwhile
+func (tr *twoFactorRepo) UpdateWebAuthnSignCount(ctx context.Context, cred *entity.UserWebAuthnCredential,
+	oldSignCount int64) (updated bool, err error) {
+	affected, err := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ? AND sign_count = ?", cred.ID, oldSignCount).
+		Cols("sign_count", "last_used_at").Update(cred)
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+func (tr *twoFactorRepo) RemoveWebAuthnCredential(ctx context.Context, userID string, id int) (
+	removed bool, err error) {
+	affected, err := tr.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ? AND user_id = ?", id, userID).
+		Delete(&entity.UserWebAuthnCredential{})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+func (tr *twoFactorRepo) SetLoginChallenge(ctx context.Context, token string,
+	challenge *schema.TwoFactorLoginChallenge) (err error) {
+	cacheData, _ := json.Marshal(challenge)
+	err = tr.data.Cache.SetString(ctx, constant.TwoFactorLoginChallengeCacheKey+token,
+		string(cacheData), constant.TwoFactorLoginChallengeCacheTime)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *twoFactorRepo) GetLoginChallenge(ctx context.Context, token string) (
+	challenge *schema.TwoFactorLoginChallenge, err error) {
+	res, exist, err := tr.data.Cache.GetString(ctx, constant.TwoFactorLoginChallengeCacheKey+token)
+	if err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	if !exist {
+		return nil, nil
+	}
+	challenge = &schema.TwoFactorLoginChallenge{}
+	_ = json.Unmarshal([]byte(res), challenge)
+	return challenge, nil
+}
+
+func (tr *twoFactorRepo) DeleteLoginChallenge(ctx context.Context, token string) (err error) {
+	err = tr.data.Cache.Del(ctx, constant.TwoFactorLoginChallengeCacheKey+token)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *twoFactorRepo) SetSetupState(ctx context.Context, key, value string) (err error) {
+	err = tr.data.Cache.SetString(ctx, constant.TwoFactorSetupCacheKey+key, value, constant.TwoFactorSetupCacheTime)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *twoFactorRepo) GetSetupState(ctx context.Context, key string) (value string, exist bool, err error) {
+	value, exist, err = tr.data.Cache.GetString(ctx, constant.TwoFactorSetupCacheKey+key)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (tr *twoFactorRepo) DeleteSetupState(ctx context.Context, key string) (err error) {
+	err = tr.data.Cache.Del(ctx, constant.TwoFactorSetupCacheKey+key)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
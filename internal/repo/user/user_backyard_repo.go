@@ -27,6 +27,7 @@ import (
 	"xorm.io/builder"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/pager"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
@@ -65,7 +66,7 @@ func (ur *userAdminRepo) UpdateUserStatus(ctx context.Context, userID string, us
 		// When restoring user status, clear suspended until time to zero
 		cond.SuspendedUntil = time.Time{}
 	}
-	_, err = ur.data.DB.Context(ctx).ID(userID).MustCols("status", "mail_status", "e_mail", "suspended_at", "suspended_until", "deleted_at").Update(cond)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(userID).MustCols("status", "mail_status", "e_mail", "suspended_at", "suspended_until", "deleted_at").Update(cond)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -86,6 +87,7 @@ func (ur *userAdminRepo) UpdateUserStatus(ctx context.Context, userID string, us
 
 // AddUser add user
 func (ur *userAdminRepo) AddUser(ctx context.Context, user *entity.User) (err error) {
+	user.TenantID = handler.GetTenantID(ctx)
 	_, err = ur.data.DB.Context(ctx).Insert(user)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -95,6 +97,9 @@ func (ur *userAdminRepo) AddUser(ctx context.Context, user *entity.User) (err er
 
 // AddUsers add users
 func (ur *userAdminRepo) AddUsers(ctx context.Context, users []*entity.User) (err error) {
+	for _, user := range users {
+		user.TenantID = handler.GetTenantID(ctx)
+	}
 	_, err = ur.data.DB.Context(ctx).Insert(users)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -104,7 +109,7 @@ func (ur *userAdminRepo) AddUsers(ctx context.Context, users []*entity.User) (er
 
 // UpdateUserPassword update user password
 func (ur *userAdminRepo) UpdateUserPassword(ctx context.Context, userID string, password string) (err error) {
-	_, err = ur.data.DB.Context(ctx).ID(userID).Update(&entity.User{Pass: password})
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(userID).Update(&entity.User{Pass: password})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -114,7 +119,7 @@ func (ur *userAdminRepo) UpdateUserPassword(ctx context.Context, userID string,
 // GetUserInfo get user info
 func (ur *userAdminRepo) GetUserInfo(ctx context.Context, userID string) (user *entity.User, exist bool, err error) {
 	user = &entity.User{}
-	exist, err = ur.data.DB.Context(ctx).ID(userID).Get(user)
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(userID).Get(user)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -131,7 +136,7 @@ func (ur *userAdminRepo) GetUserInfo(ctx context.Context, userID string) (user *
 // GetUserInfoByEmail get user info
 func (ur *userAdminRepo) GetUserInfoByEmail(ctx context.Context, email string) (user *entity.User, exist bool, err error) {
 	userInfo := &entity.User{}
-	exist, err = ur.data.DB.Context(ctx).Where("e_mail = ?", email).
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("e_mail = ?", email).
 		Where("status != ?", entity.UserStatusDeleted).Get(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -151,7 +156,7 @@ func (ur *userAdminRepo) GetUserInfoByEmail(ctx context.Context, email string) (
 func (ur *userAdminRepo) GetUserPage(ctx context.Context, page, pageSize int, user *entity.User,
 	usernameOrDisplayName string, isStaff bool) (users []*entity.User, total int64, err error) {
 	users = make([]*entity.User, 0)
-	session := ur.data.DB.Context(ctx)
+	session := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx, "user"))
 	switch user.Status {
 	case entity.UserStatusDeleted:
 		session.Desc("`user`.deleted_at")
@@ -182,19 +187,31 @@ func (ur *userAdminRepo) GetUserPage(ctx context.Context, page, pageSize int, us
 
 // DeletePermanentlyUsers delete permanently users
 func (ur *userAdminRepo) DeletePermanentlyUsers(ctx context.Context) (err error) {
-	_, err = ur.data.DB.Context(ctx).Where("status = ?", entity.UserStatusDeleted).Delete(&entity.User{})
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ?", entity.UserStatusDeleted).Delete(&entity.User{})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	return
 }
 
+// AnonymizeUser replace the names of the user and clear all the other personal data
+func (ur *userAdminRepo) AnonymizeUser(ctx context.Context, user *entity.User) (err error) {
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(user.ID).
+		MustCols("username", "display_name", "status", "deleted_at", "pass", "e_mail", "mobile", "avatar",
+			"bio", "bio_html", "website", "location", "ip_info", "language").
+		Update(user)
+	if err != nil {
+		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return nil
+}
+
 // GetExpiredSuspendedUsers gets all suspended users whose suspension has expired
 func (ur *userAdminRepo) GetExpiredSuspendedUsers(ctx context.Context) (users []*entity.User, err error) {
 	users = make([]*entity.User, 0)
 	now := time.Now()
 
-	err = ur.data.DB.Context(ctx).
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).
 		Where("status = ?", entity.UserStatusSuspended).
 		Where("suspended_until < ?", now).
 		Find(&users)
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
@@ -51,16 +52,17 @@ func NewUserRepo(data *data.Data) usercommon.UserRepo {
 
 // AddUser add user
 func (ur *userRepo) AddUser(ctx context.Context, user *entity.User) (err error) {
-	_, err = ur.data.DB.Transaction(func(session *xorm.Session) (any, error) {
+	_, err = ur.data.DB.Transaction(ctx, func(session *xorm.Session) (any, error) {
 		session = session.Context(ctx)
 		userInfo := &entity.User{}
-		exist, err := session.Where("username = ?", user.Username).Get(userInfo)
+		exist, err := session.Where(data.TenantCond(ctx)).And("username = ?", user.Username).Get(userInfo)
 		if err != nil {
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		}
 		if exist {
 			return nil, errors.InternalServer(reason.UsernameDuplicate)
 		}
+		user.TenantID = handler.GetTenantID(ctx)
 		_, err = session.Insert(user)
 		if err != nil {
 			return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -73,7 +75,7 @@ func (ur *userRepo) AddUser(ctx context.Context, user *entity.User) (err error)
 // IncreaseAnswerCount increase answer count
 func (ur *userRepo) IncreaseAnswerCount(ctx context.Context, userID string, amount int) (err error) {
 	user := &entity.User{}
-	_, err = ur.data.DB.Context(ctx).Where("id = ?", userID).Incr("answer_count", amount).Update(user)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Incr("answer_count", amount).Update(user)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -83,7 +85,7 @@ func (ur *userRepo) IncreaseAnswerCount(ctx context.Context, userID string, amou
 // IncreaseQuestionCount increase question count
 func (ur *userRepo) IncreaseQuestionCount(ctx context.Context, userID string, amount int) (err error) {
 	user := &entity.User{}
-	_, err = ur.data.DB.Context(ctx).Where("id = ?", userID).Incr("question_count", amount).Update(user)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Incr("question_count", amount).Update(user)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -93,7 +95,7 @@ func (ur *userRepo) IncreaseQuestionCount(ctx context.Context, userID string, am
 func (ur *userRepo) UpdateQuestionCount(ctx context.Context, userID string, count int64) (err error) {
 	user := &entity.User{}
 	user.QuestionCount = int(count)
-	_, err = ur.data.DB.Context(ctx).Where("id = ?", userID).Cols("question_count").Update(user)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Cols("question_count").Update(user)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -103,7 +105,7 @@ func (ur *userRepo) UpdateQuestionCount(ctx context.Context, userID string, coun
 func (ur *userRepo) UpdateAnswerCount(ctx context.Context, userID string, count int) (err error) {
 	user := &entity.User{}
 	user.AnswerCount = count
-	_, err = ur.data.DB.Context(ctx).Where("id = ?", userID).Cols("answer_count").Update(user)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Cols("answer_count").Update(user)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -113,7 +115,7 @@ func (ur *userRepo) UpdateAnswerCount(ctx context.Context, userID string, count
 // UpdateLastLoginDate update last login date
 func (ur *userRepo) UpdateLastLoginDate(ctx context.Context, userID string) (err error) {
 	user := &entity.User{LastLoginDate: time.Now()}
-	_, err = ur.data.DB.Context(ctx).Where("id = ?", userID).Cols("last_login_date").Update(user)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Cols("last_login_date").Update(user)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -123,7 +125,7 @@ func (ur *userRepo) UpdateLastLoginDate(ctx context.Context, userID string) (err
 // UpdateEmailStatus update email status
 func (ur *userRepo) UpdateEmailStatus(ctx context.Context, userID string, emailStatus int) error {
 	cond := &entity.User{MailStatus: emailStatus}
-	_, err := ur.data.DB.Context(ctx).Where("id = ?", userID).Cols("mail_status").Update(cond)
+	_, err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Cols("mail_status").Update(cond)
 	if err != nil {
 		return err
 	}
@@ -133,7 +135,7 @@ func (ur *userRepo) UpdateEmailStatus(ctx context.Context, userID string, emailS
 // UpdateNoticeStatus update notice status
 func (ur *userRepo) UpdateNoticeStatus(ctx context.Context, userID string, noticeStatus int) error {
 	cond := &entity.User{NoticeStatus: noticeStatus}
-	_, err := ur.data.DB.Context(ctx).Where("id = ?", userID).Cols("notice_status").Update(cond)
+	_, err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Cols("notice_status").Update(cond)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -141,7 +143,7 @@ func (ur *userRepo) UpdateNoticeStatus(ctx context.Context, userID string, notic
 }
 
 func (ur *userRepo) UpdatePass(ctx context.Context, userID, pass string) error {
-	_, err := ur.data.DB.Context(ctx).Where("id = ?", userID).Cols("pass").Update(&entity.User{Pass: pass})
+	_, err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Cols("pass").Update(&entity.User{Pass: pass})
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -149,7 +151,7 @@ func (ur *userRepo) UpdatePass(ctx context.Context, userID, pass string) error {
 }
 
 func (ur *userRepo) UpdateEmail(ctx context.Context, userID, email string) (err error) {
-	_, err = ur.data.DB.Context(ctx).Where("id = ?", userID).Update(&entity.User{EMail: email})
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Update(&entity.User{EMail: email})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -157,7 +159,7 @@ func (ur *userRepo) UpdateEmail(ctx context.Context, userID, email string) (err
 }
 
 func (ur *userRepo) UpdateUserInterface(ctx context.Context, userID, language, colorSchema string) (err error) {
-	session := ur.data.DB.Context(ctx).Where("id = ?", userID)
+	session := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID)
 	_, err = session.Cols("language", "color_scheme").Update(&entity.User{Language: language, ColorScheme: colorSchema})
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -167,7 +169,7 @@ func (ur *userRepo) UpdateUserInterface(ctx context.Context, userID, language, c
 
 // UpdateInfo update user info
 func (ur *userRepo) UpdateInfo(ctx context.Context, userInfo *entity.User) (err error) {
-	_, err = ur.data.DB.Context(ctx).Where("id = ?", userInfo.ID).
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userInfo.ID).
 		Cols("username", "display_name", "avatar", "bio", "bio_html", "website", "location").Update(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -177,7 +179,7 @@ func (ur *userRepo) UpdateInfo(ctx context.Context, userInfo *entity.User) (err
 
 // UpdateUserProfile update user profile
 func (ur *userRepo) UpdateUserProfile(ctx context.Context, userInfo *entity.User) (err error) {
-	_, err = ur.data.DB.Context(ctx).Where("id = ?", userInfo.ID).
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userInfo.ID).
 		Cols("username", "e_mail", "mail_status", "display_name").Update(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -188,7 +190,7 @@ func (ur *userRepo) UpdateUserProfile(ctx context.Context, userInfo *entity.User
 // GetByUserID get user info by user id
 func (ur *userRepo) GetByUserID(ctx context.Context, userID string) (userInfo *entity.User, exist bool, err error) {
 	userInfo = &entity.User{}
-	exist, err = ur.data.DB.Context(ctx).Where("id = ?", userID).Get(userInfo)
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", userID).Get(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		return
@@ -202,7 +204,7 @@ func (ur *userRepo) GetByUserID(ctx context.Context, userID string) (userInfo *e
 
 func (ur *userRepo) BatchGetByID(ctx context.Context, ids []string) ([]*entity.User, error) {
 	list := make([]*entity.User, 0)
-	err := ur.data.DB.Context(ctx).In("id", ids).Find(&list)
+	err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("id", ids).Find(&list)
 	if err != nil {
 		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -213,7 +215,7 @@ func (ur *userRepo) BatchGetByID(ctx context.Context, ids []string) ([]*entity.U
 // GetByUsername get user by username
 func (ur *userRepo) GetByUsername(ctx context.Context, username string) (userInfo *entity.User, exist bool, err error) {
 	userInfo = &entity.User{}
-	exist, err = ur.data.DB.Context(ctx).Where("username = ?", username).Get(userInfo)
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("username = ?", username).Get(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		return
@@ -227,7 +229,7 @@ func (ur *userRepo) GetByUsername(ctx context.Context, username string) (userInf
 
 func (ur *userRepo) GetByUsernames(ctx context.Context, usernames []string) ([]*entity.User, error) {
 	list := make([]*entity.User, 0)
-	err := ur.data.DB.Context(ctx).Where("status =?", entity.UserStatusAvailable).In("username", usernames).Find(&list)
+	err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status =?", entity.UserStatusAvailable).In("username", usernames).Find(&list)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 		return list, err
@@ -239,7 +241,7 @@ func (ur *userRepo) GetByUsernames(ctx context.Context, usernames []string) ([]*
 // GetByEmail get user by email
 func (ur *userRepo) GetByEmail(ctx context.Context, email string) (userInfo *entity.User, exist bool, err error) {
 	userInfo = &entity.User{}
-	exist, err = ur.data.DB.Context(ctx).Where("e_mail = ?", email).
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("e_mail = ?", email).
 		Where("status != ?", entity.UserStatusDeleted).Get(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -248,7 +250,7 @@ func (ur *userRepo) GetByEmail(ctx context.Context, email string) (userInfo *ent
 }
 
 func (ur *userRepo) GetUserCount(ctx context.Context) (count int64, err error) {
-	session := ur.data.DB.Context(ctx)
+	session := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx))
 	session.Where("status = ? OR status = ?", entity.UserStatusAvailable, entity.UserStatusSuspended)
 	count, err = session.Count(&entity.User{})
 	if err != nil {
@@ -260,7 +262,7 @@ func (ur *userRepo) GetUserCount(ctx context.Context) (count int64, err error) {
 func (ur *userRepo) SearchUserListByName(ctx context.Context, name string, limit int,
 	onlyStaff bool) (userList []*entity.User, err error) {
 	userList = make([]*entity.User, 0)
-	session := ur.data.DB.Context(ctx)
+	session := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx, "user"))
 	if onlyStaff {
 		session.Join("INNER", "user_role_rel", "`user`.id = `user_role_rel`.user_id AND `user_role_rel`.role_id > 1")
 	}
@@ -276,7 +278,7 @@ func (ur *userRepo) SearchUserListByName(ctx context.Context, name string, limit
 	return
 }
 
-func tryToDecorateUserInfoFromUserCenter(ctx context.Context, data *data.Data, original *entity.User) (err error) {
+func tryToDecorateUserInfoFromUserCenter(ctx context.Context, userData *data.Data, original *entity.User) (err error) {
 	if original == nil {
 		return nil
 	}
@@ -286,7 +288,7 @@ func tryToDecorateUserInfoFromUserCenter(ctx context.Context, data *data.Data, o
 	}
 
 	userInfo := &entity.UserExternalLogin{}
-	session := data.DB.Context(ctx).Where("user_id = ?", original.ID)
+	session := userData.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", original.ID)
 	session.Where("provider = ?", uc.Info().SlugName)
 	exist, err := session.Get(userInfo)
 	if err != nil {
@@ -306,7 +308,7 @@ func tryToDecorateUserInfoFromUserCenter(ctx context.Context, data *data.Data, o
 	return nil
 }
 
-func tryToDecorateUserListFromUserCenter(ctx context.Context, data *data.Data, original []*entity.User) {
+func tryToDecorateUserListFromUserCenter(ctx context.Context, userData *data.Data, original []*entity.User) {
 	uc, ok := plugin.GetUserCenter()
 	if !ok {
 		return
@@ -320,7 +322,7 @@ func tryToDecorateUserListFromUserCenter(ctx context.Context, data *data.Data, o
 	}
 
 	userExternalLoginList := make([]*entity.UserExternalLogin, 0)
-	session := data.DB.Context(ctx).Where("provider = ?", uc.Info().SlugName)
+	session := userData.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("provider = ?", uc.Info().SlugName)
 	session.In("user_id", ids)
 	err := session.Find(&userExternalLoginList)
 	if err != nil {
@@ -384,7 +386,7 @@ func decorateByUserCenterUser(original *entity.User, ucUser *plugin.UserCenterBa
 
 func (ur *userRepo) IsAvatarFileUsed(ctx context.Context, filePath string) (bool, error) {
 	user := &entity.User{}
-	count, err := ur.data.DB.Context(ctx).
+	count, err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).
 		Table("user").
 		Where(builder.Like{"avatar", "%" + filePath + "%"}).
 		Count(&user)
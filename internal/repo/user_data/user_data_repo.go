@@ -0,0 +1,194 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package user_data
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/user_data"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// userDataRequestLimit the max number of requests returned to the user
+const userDataRequestLimit = 20
+
+type userDataRepo struct {
+	data *data.Data
+}
+
+// NewUserDataRepo creates a new user data repository
+func NewUserDataRepo(data *data.Data) user_data.UserDataRepo {
+	return &userDataRepo{
+		data: data,
+	}
+}
+
+func (ur *userDataRepo) AddUserDataRequest(ctx context.Context, req *entity.UserDataRequest) (err error) {
+	req.TenantID = handler.GetTenantID(ctx)
+	_, err = ur.data.DB.Context(ctx).Insert(req)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+// UpdateUserDataRequestStatus change the status only if it is still the expected one,
+// so the request is never processed twice by the concurrent jobs
+func (ur *userDataRepo) UpdateUserDataRequestStatus(ctx context.Context, id, fromStatus, toStatus int) (
+	updated bool, err error) {
+	affected, err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ? AND status = ?", id, fromStatus).
+		Cols("status").Update(&entity.UserDataRequest{Status: toStatus})
+	if err != nil {
+		return false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return affected > 0, nil
+}
+
+func (ur *userDataRepo) UpdateUserDataRequest(ctx context.Context, req *entity.UserDataRequest) (err error) {
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(req.ID).
+		Cols("status", "completed_at", "file_name", "download_code").Update(req)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ur *userDataRepo) GetLatestUserDataRequest(ctx context.Context, userID, requestType string) (
+	req *entity.UserDataRequest, exist bool, err error) {
+	req = &entity.UserDataRequest{}
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND request_type = ?", userID, requestType).
+		Desc("id").Get(req)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ur *userDataRepo) GetUserDataRequestByCode(ctx context.Context, code string) (
+	req *entity.UserDataRequest, exist bool, err error) {
+	req = &entity.UserDataRequest{}
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("download_code = ?", code).Get(req)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ur *userDataRepo) GetUserDataRequests(ctx context.Context, userID string) (
+	list []*entity.UserDataRequest, err error) {
+	list = make([]*entity.UserDataRequest, 0)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Desc("id").Limit(userDataRequestLimit).Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ur *userDataRepo) GetDueUserDataRequests(ctx context.Context, now time.Time) (
+	list []*entity.UserDataRequest, err error) {
+	list = make([]*entity.UserDataRequest, 0)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("status = ? AND scheduled_at <= ?", entity.UserDataRequestStatusPending, now).
+		Asc("scheduled_at").Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ur *userDataRepo) GetExpiredUserDataExports(ctx context.Context, before time.Time) (
+	list []*entity.UserDataRequest, err error) {
+	list = make([]*entity.UserDataRequest, 0)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("request_type = ? AND status = ? AND completed_at < ?",
+		entity.UserDataRequestTypeExport, entity.UserDataRequestStatusCompleted, before).Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ur *userDataRepo) GetUserQuestions(ctx context.Context, userID string) (list []*entity.Question, err error) {
+	return findUserRows[entity.Question](ctx, ur.data, "user_id = ?", userID)
+}
+
+func (ur *userDataRepo) GetUserAnswers(ctx context.Context, userID string) (list []*entity.Answer, err error) {
+	return findUserRows[entity.Answer](ctx, ur.data, "user_id = ?", userID)
+}
+
+func (ur *userDataRepo) GetUserComments(ctx context.Context, userID string) (list []*entity.Comment, err error) {
+	return findUserRows[entity.Comment](ctx, ur.data, "user_id = ?", userID)
+}
+
+func (ur *userDataRepo) GetUserVotes(ctx context.Context, userID string, activityTypes []int) (
+	list []*entity.Activity, err error) {
+	list = make([]*entity.Activity, 0)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND cancelled = ?", userID, entity.ActivityAvailable).
+		In("activity_type", activityTypes).Asc("id").Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ur *userDataRepo) GetUserCollections(ctx context.Context, userID string) (list []*entity.Collection, err error) {
+	return findUserRows[entity.Collection](ctx, ur.data, "user_id = ?", userID)
+}
+
+func (ur *userDataRepo) GetUserNotifications(ctx context.Context, userID string) (
+	list []*entity.Notification, err error) {
+	return findUserRows[entity.Notification](ctx, ur.data, "user_id = ?", userID)
+}
+
+func (ur *userDataRepo) GetUserAIConversations(ctx context.Context, userID string) (
+	conversations []*entity.AIConversation, records []*entity.AIConversationRecord, err error) {
+	conversations, err = findUserRows[entity.AIConversation](ctx, ur.data, "user_id = ?", userID)
+	if err != nil || len(conversations) == 0 {
+		return
+	}
+	conversationIDs := make([]string, 0, len(conversations))
+	for _, conversation := range conversations {
+		conversationIDs = append(conversationIDs, conversation.ConversationID)
+	}
+	records = make([]*entity.AIConversationRecord, 0)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).In("conversation_id", conversationIDs).Asc("id").Find(&records)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
+
+func (ur *userDataRepo) GetUserFileRecords(ctx context.Context, userID string) (list []*entity.FileRecord, err error) {
+	return findUserRows[entity.FileRecord](ctx, ur.data, "user_id = ? AND status = ?",
+		userID, entity.FileRecordStatusAvailable)
+}
+
+// findUserRows find all rows of the user in the order of creation
+func findUserRows[T any](ctx context.Context, d *data.Data, query string, args ...any) (list []*T, err error) {
+	list = make([]*T, 0)
+	err = d.DB.Context(ctx).Where(data.TenantCond(ctx)).Where(query, args...).Asc("created_at").Find(&list)
+	if err != nil {
+		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+	return
+}
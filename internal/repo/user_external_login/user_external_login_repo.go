@@ -26,6 +26,7 @@ import (
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
@@ -46,6 +47,7 @@ func NewUserExternalLoginRepo(data *data.Data) user_external_login.UserExternalL
 
 // AddUserExternalLogin add external login information
 func (ur *userExternalLoginRepo) AddUserExternalLogin(ctx context.Context, user *entity.UserExternalLogin) (err error) {
+	user.TenantID = handler.GetTenantID(ctx)
 	_, err = ur.data.DB.Context(ctx).Insert(user)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -55,7 +57,7 @@ func (ur *userExternalLoginRepo) AddUserExternalLogin(ctx context.Context, user
 
 // UpdateInfo update user info
 func (ur *userExternalLoginRepo) UpdateInfo(ctx context.Context, userInfo *entity.UserExternalLogin) (err error) {
-	_, err = ur.data.DB.Context(ctx).ID(userInfo.ID).Update(userInfo)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(userInfo.ID).Update(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -66,7 +68,7 @@ func (ur *userExternalLoginRepo) UpdateInfo(ctx context.Context, userInfo *entit
 func (ur *userExternalLoginRepo) GetByExternalID(ctx context.Context, provider, externalID string) (
 	userInfo *entity.UserExternalLogin, exist bool, err error) {
 	userInfo = &entity.UserExternalLogin{}
-	exist, err = ur.data.DB.Context(ctx).Where("external_id = ?", externalID).Where("provider = ?", provider).Get(userInfo)
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("external_id = ?", externalID).Where("provider = ?", provider).Get(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -77,7 +79,7 @@ func (ur *userExternalLoginRepo) GetByExternalID(ctx context.Context, provider,
 func (ur *userExternalLoginRepo) GetByUserID(ctx context.Context, provider, userID string) (
 	userInfo *entity.UserExternalLogin, exist bool, err error) {
 	userInfo = &entity.UserExternalLogin{}
-	exist, err = ur.data.DB.Context(ctx).Where("user_id = ?", userID).Where("provider = ?", provider).Get(userInfo)
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Where("provider = ?", provider).Get(userInfo)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -88,7 +90,7 @@ func (ur *userExternalLoginRepo) GetByUserID(ctx context.Context, provider, user
 func (ur *userExternalLoginRepo) GetUserExternalLoginList(ctx context.Context, userID string) (
 	resp []*entity.UserExternalLogin, err error) {
 	resp = make([]*entity.UserExternalLogin, 0)
-	err = ur.data.DB.Context(ctx).Where("user_id = ?", userID).OrderBy("updated_at DESC").Find(&resp)
+	err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).OrderBy("updated_at DESC").Find(&resp)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -98,7 +100,7 @@ func (ur *userExternalLoginRepo) GetUserExternalLoginList(ctx context.Context, u
 // DeleteUserExternalLogin delete external user login info
 func (ur *userExternalLoginRepo) DeleteUserExternalLogin(ctx context.Context, userID, externalID string) (err error) {
 	cond := &entity.UserExternalLogin{}
-	_, err = ur.data.DB.Context(ctx).Where("user_id = ? AND external_id = ?", userID, externalID).Delete(cond)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ? AND external_id = ?", userID, externalID).Delete(cond)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -108,7 +110,7 @@ func (ur *userExternalLoginRepo) DeleteUserExternalLogin(ctx context.Context, us
 // DeleteUserExternalLoginByUserID delete external user login info by user ID
 func (ur *userExternalLoginRepo) DeleteUserExternalLoginByUserID(ctx context.Context, userID string) (err error) {
 	cond := &entity.UserExternalLogin{}
-	_, err = ur.data.DB.Context(ctx).Where("user_id = ?", userID).Delete(cond)
+	_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Delete(cond)
 	if err != nil {
 		err = errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/reason"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/user_notification_config"
@@ -47,6 +48,7 @@ func (ur *userNotificationConfigRepo) Add(ctx context.Context, userIDs []string,
 	var configs []*entity.UserNotificationConfig
 	for _, userID := range userIDs {
 		configs = append(configs, &entity.UserNotificationConfig{
+			TenantID: handler.GetTenantID(ctx),
 			UserID:   userID,
 			Source:   source,
 			Channels: channels,
@@ -63,15 +65,16 @@ func (ur *userNotificationConfigRepo) Add(ctx context.Context, userIDs []string,
 // Save save notification config, if existed, update, if not exist, insert
 func (ur *userNotificationConfigRepo) Save(ctx context.Context, uc *entity.UserNotificationConfig) (err error) {
 	old := &entity.UserNotificationConfig{UserID: uc.UserID, Source: uc.Source}
-	exist, err := ur.data.DB.Context(ctx).Get(old)
+	exist, err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Get(old)
 	if err != nil {
 		return errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
 	if exist {
 		old.Channels = uc.Channels
 		old.Enabled = uc.Enabled
-		_, err = ur.data.DB.Context(ctx).ID(old.ID).UseBool("enabled").Cols("channels", "enabled").Update(old)
+		_, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).ID(old.ID).UseBool("enabled").Cols("channels", "enabled").Update(old)
 	} else {
+		uc.TenantID = handler.GetTenantID(ctx)
 		_, err = ur.data.DB.Context(ctx).Insert(uc)
 	}
 	if err != nil {
@@ -84,7 +87,7 @@ func (ur *userNotificationConfigRepo) Save(ctx context.Context, uc *entity.UserN
 func (ur *userNotificationConfigRepo) GetByUserID(ctx context.Context, userID string) (
 	[]*entity.UserNotificationConfig, error) {
 	var configs []*entity.UserNotificationConfig
-	err := ur.data.DB.Context(ctx).Where("user_id = ?", userID).Find(&configs)
+	err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("user_id = ?", userID).Find(&configs)
 	if err != nil {
 		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -95,7 +98,7 @@ func (ur *userNotificationConfigRepo) GetByUserID(ctx context.Context, userID st
 func (ur *userNotificationConfigRepo) GetBySource(ctx context.Context, source constant.NotificationSource) (
 	[]*entity.UserNotificationConfig, error) {
 	var configs []*entity.UserNotificationConfig
-	err := ur.data.DB.Context(ctx).UseBool("enabled").
+	err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).UseBool("enabled").
 		Find(&configs, &entity.UserNotificationConfig{Source: string(source), Enabled: true})
 	if err != nil {
 		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
@@ -107,7 +110,7 @@ func (ur *userNotificationConfigRepo) GetBySource(ctx context.Context, source co
 func (ur *userNotificationConfigRepo) GetByUserIDAndSource(ctx context.Context, userID string, source constant.NotificationSource) (
 	conf *entity.UserNotificationConfig, exist bool, err error) {
 	config := &entity.UserNotificationConfig{UserID: userID, Source: string(source)}
-	exist, err = ur.data.DB.Context(ctx).Get(config)
+	exist, err = ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Get(config)
 	if err != nil {
 		return nil, false, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
 	}
@@ -119,7 +122,7 @@ func (ur *userNotificationConfigRepo) GetByUsersAndSource(
 	ctx context.Context, userIDs []string, source constant.NotificationSource) (
 	[]*entity.UserNotificationConfig, error) {
 	var configs []*entity.UserNotificationConfig
-	err := ur.data.DB.Context(ctx).UseBool("enabled").In("user_id", userIDs).
+	err := ur.data.DB.Context(ctx).Where(data.TenantCond(ctx)).UseBool("enabled").In("user_id", userIDs).
 		Find(&configs, &entity.UserNotificationConfig{Source: string(source), Enabled: true})
 	if err != nil {
 		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
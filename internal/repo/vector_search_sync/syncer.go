@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/pkg/uid"
 	"github.com/apache/answer/plugin"
@@ -43,26 +44,26 @@ type PluginSyncer struct {
 	data *data.Data
 }
 
-// GetQuestionsPage returns a page of questions with aggregated text
+// GetQuestionsPage returns a page of the questions of all tenants with aggregated text
 // (question title + body + all answers + all comments).
 func (p *PluginSyncer) GetQuestionsPage(ctx context.Context, page, pageSize int) (
 	[]*plugin.VectorSearchContent, error) {
 	questions := make([]*entity.Question, 0)
 	startNum := (page - 1) * pageSize
-	err := p.data.DB.Context(ctx).Limit(pageSize, startNum).Find(&questions)
+	err := p.data.DB.Context(ctx).Where("space_id = ?", entity.PublicSpaceID).Limit(pageSize, startNum).Find(&questions)
 	if err != nil {
 		return nil, err
 	}
 	return p.buildQuestionContents(ctx, questions)
 }
 
-// GetAnswersPage returns a page of answers with aggregated text
+// GetAnswersPage returns a page of the answers of all tenants with aggregated text
 // (parent question title + answer body + answer comments).
 func (p *PluginSyncer) GetAnswersPage(ctx context.Context, page, pageSize int) (
 	[]*plugin.VectorSearchContent, error) {
 	answers := make([]*entity.Answer, 0)
 	startNum := (page - 1) * pageSize
-	err := p.data.DB.Context(ctx).Limit(pageSize, startNum).Find(&answers)
+	err := p.data.DB.Context(ctx).Where("space_id = ?", entity.PublicSpaceID).Limit(pageSize, startNum).Find(&answers)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +75,10 @@ func (p *PluginSyncer) buildQuestionContents(ctx context.Context, questions []*e
 	[]*plugin.VectorSearchContent, error) {
 	result := make([]*plugin.VectorSearchContent, 0, len(questions))
 	for _, q := range questions {
+		// the answers and comments are of the tenant of the question
+		ctx := handler.WithTenantID(ctx, q.TenantID)
 		meta := plugin.VectorSearchMetadata{
+			TenantID:   q.TenantID,
 			QuestionID: uid.DeShortID(q.ID),
 		}
 
@@ -83,7 +87,7 @@ func (p *PluginSyncer) buildQuestionContents(ctx context.Context, questions []*e
 
 		// Get answers for this question
 		answers := make([]*entity.Answer, 0)
-		err := p.data.DB.Context(ctx).Where("question_id = ?", q.ID).Find(&answers)
+		err := p.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("question_id = ?", q.ID).Find(&answers)
 		if err != nil {
 			log.Warnf("get answers for question %s failed: %v", q.ID, err)
 		} else {
@@ -95,7 +99,7 @@ func (p *PluginSyncer) buildQuestionContents(ctx context.Context, questions []*e
 
 				// Get comments on this answer
 				answerComments := make([]*entity.Comment, 0)
-				err := p.data.DB.Context(ctx).Where("object_id = ?", a.ID).
+				err := p.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", a.ID).
 					OrderBy("created_at ASC").Limit(50).Find(&answerComments)
 				if err != nil {
 					log.Warnf("get comments for answer %s failed: %v", a.ID, err)
@@ -113,7 +117,7 @@ func (p *PluginSyncer) buildQuestionContents(ctx context.Context, questions []*e
 
 		// Get comments on the question
 		questionComments := make([]*entity.Comment, 0)
-		err = p.data.DB.Context(ctx).Where("object_id = ?", q.ID).
+		err = p.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", q.ID).
 			OrderBy("created_at ASC").Limit(50).Find(&questionComments)
 		if err != nil {
 			log.Warnf("get comments for question %s failed: %v", q.ID, err)
@@ -128,6 +132,7 @@ func (p *PluginSyncer) buildQuestionContents(ctx context.Context, questions []*e
 
 		metaJSON, _ := json.Marshal(meta)
 		result = append(result, &plugin.VectorSearchContent{
+			TenantID:   q.TenantID,
 			ObjectID:   uid.DeShortID(q.ID),
 			ObjectType: "question",
 			Title:      q.Title,
@@ -143,9 +148,10 @@ func (p *PluginSyncer) buildAnswerContents(ctx context.Context, answers []*entit
 	[]*plugin.VectorSearchContent, error) {
 	result := make([]*plugin.VectorSearchContent, 0, len(answers))
 	for _, a := range answers {
+		ctx := handler.WithTenantID(ctx, a.TenantID)
 		// Get parent question for title
 		question := &entity.Question{}
-		exist, err := p.data.DB.Context(ctx).Where("id = ?", a.QuestionID).Get(question)
+		exist, err := p.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", a.QuestionID).Get(question)
 		if err != nil {
 			log.Errorf("get question %s failed: %v", a.QuestionID, err)
 			continue
@@ -155,6 +161,7 @@ func (p *PluginSyncer) buildAnswerContents(ctx context.Context, answers []*entit
 		}
 
 		meta := plugin.VectorSearchMetadata{
+			TenantID:   a.TenantID,
 			QuestionID: uid.DeShortID(a.QuestionID),
 			AnswerID:   uid.DeShortID(a.ID),
 		}
@@ -169,7 +176,7 @@ func (p *PluginSyncer) buildAnswerContents(ctx context.Context, answers []*entit
 
 		// Get comments on this answer
 		answerComments := make([]*entity.Comment, 0)
-		err = p.data.DB.Context(ctx).Where("object_id = ?", a.ID).
+		err = p.data.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("object_id = ?", a.ID).
 			OrderBy("created_at ASC").Limit(50).Find(&answerComments)
 		if err != nil {
 			log.Warnf("get comments for answer %s failed: %v", a.ID, err)
@@ -185,6 +192,7 @@ func (p *PluginSyncer) buildAnswerContents(ctx context.Context, answers []*entit
 
 		metaJSON, _ := json.Marshal(meta)
 		result = append(result, &plugin.VectorSearchContent{
+			TenantID:   a.TenantID,
 			ObjectID:   uid.DeShortID(a.ID),
 			ObjectType: "answer",
 			Title:      question.Title,
@@ -197,16 +205,17 @@ func (p *PluginSyncer) buildAnswerContents(ctx context.Context, answers []*entit
 
 // BuildQuestionContentByID builds vector content for one question using the same
 // aggregation semantics as bulk vector sync.
-func BuildQuestionContentByID(ctx context.Context, data *data.Data, questionID string) (*plugin.VectorSearchContent, error) {
+func BuildQuestionContentByID(ctx context.Context, dataStore *data.Data, questionID string) (*plugin.VectorSearchContent, error) {
 	question := &entity.Question{}
-	exist, err := data.DB.Context(ctx).Where("id = ?", uid.DeShortID(questionID)).Get(question)
+	exist, err := dataStore.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", uid.DeShortID(questionID)).Get(question)
 	if err != nil {
 		return nil, err
 	}
-	if !exist {
+	// the contents of the spaces are not embedded
+	if !exist || question.SpaceID != entity.PublicSpaceID {
 		return nil, nil
 	}
-	syncer := &PluginSyncer{data: data}
+	syncer := &PluginSyncer{data: dataStore}
 	contents, err := syncer.buildQuestionContents(ctx, []*entity.Question{question})
 	if err != nil {
 		return nil, err
@@ -219,16 +228,17 @@ func BuildQuestionContentByID(ctx context.Context, data *data.Data, questionID s
 
 // BuildAnswerContentByID builds vector content for one answer using the same
 // aggregation semantics as bulk vector sync.
-func BuildAnswerContentByID(ctx context.Context, data *data.Data, answerID string) (*plugin.VectorSearchContent, error) {
+func BuildAnswerContentByID(ctx context.Context, dataStore *data.Data, answerID string) (*plugin.VectorSearchContent, error) {
 	answer := &entity.Answer{}
-	exist, err := data.DB.Context(ctx).Where("id = ?", uid.DeShortID(answerID)).Get(answer)
+	exist, err := dataStore.DB.Context(ctx).Where(data.TenantCond(ctx)).Where("id = ?", uid.DeShortID(answerID)).Get(answer)
 	if err != nil {
 		return nil, err
 	}
-	if !exist {
+	// the contents of the spaces are not embedded
+	if !exist || answer.SpaceID != entity.PublicSpaceID {
 		return nil, nil
 	}
-	syncer := &PluginSyncer{data: data}
+	syncer := &PluginSyncer{data: dataStore}
 	contents, err := syncer.buildAnswerContents(ctx, []*entity.Answer{answer})
 	if err != nil {
 		return nil, err
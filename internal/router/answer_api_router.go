@@ -23,6 +23,7 @@ import (
 	"github.com/apache/answer/internal/base/middleware"
 	"github.com/apache/answer/internal/controller"
 	"github.com/apache/answer/internal/controller_admin"
+	"github.com/apache/answer/internal/service/sso"
 	"github.com/gin-gonic/gin"
 )
 
@@ -62,6 +63,19 @@ type AnswerAPIRouter struct {
 	aiConversationController      *controller.AIConversationController
 	aiConversationAdminController *controller_admin.AIConversationAdminController
 	mcpController                 *controller.MCPController
+	closeVoteController           *controller.CloseVoteController
+	bountyController              *controller.BountyController
+	draftController               *controller.DraftController
+	scheduledPostController       *controller.ScheduledPostController
+	spaceController               *controller.SpaceController
+	tenantController              *controller_admin.TenantController
+	userDataController            *controller.UserDataController
+	ssoController                 *controller.SSOController
+	ssoProviderController         *controller_admin.SSOProviderController
+	scimController                *controller.SCIMController
+	twoFactorController           *controller.TwoFactorController
+	userSessionController         *controller.UserSessionController
+	authUserMiddleware            *middleware.AuthUserMiddleware
 }
 
 func NewAnswerAPIRouter(
@@ -100,6 +114,19 @@ func NewAnswerAPIRouter(
 	aiConversationController *controller.AIConversationController,
 	aiConversationAdminController *controller_admin.AIConversationAdminController,
 	mcpController *controller.MCPController,
+	closeVoteController *controller.CloseVoteController,
+	bountyController *controller.BountyController,
+	draftController *controller.DraftController,
+	scheduledPostController *controller.ScheduledPostController,
+	spaceController *controller.SpaceController,
+	tenantController *controller_admin.TenantController,
+	userDataController *controller.UserDataController,
+	ssoController *controller.SSOController,
+	ssoProviderController *controller_admin.SSOProviderController,
+	scimController *controller.SCIMController,
+	twoFactorController *controller.TwoFactorController,
+	userSessionController *controller.UserSessionController,
+	authUserMiddleware *middleware.AuthUserMiddleware,
 ) *AnswerAPIRouter {
 	return &AnswerAPIRouter{
 		langController:                langController,
@@ -137,6 +164,19 @@ func NewAnswerAPIRouter(
 		aiConversationController:      aiConversationController,
 		aiConversationAdminController: aiConversationAdminController,
 		mcpController:                 mcpController,
+		closeVoteController:           closeVoteController,
+		bountyController:              bountyController,
+		draftController:               draftController,
+		scheduledPostController:       scheduledPostController,
+		spaceController:               spaceController,
+		tenantController:              tenantController,
+		userDataController:            userDataController,
+		ssoController:                 ssoController,
+		ssoProviderController:         ssoProviderController,
+		scimController:                scimController,
+		twoFactorController:           twoFactorController,
+		userSessionController:         userSessionController,
+		authUserMiddleware:            authUserMiddleware,
 	}
 }
 
@@ -150,19 +190,30 @@ func (a *AnswerAPIRouter) RegisterMustUnAuthAnswerAPIRouter(authUserMiddleware *
 	r.GET("/siteinfo/legal", a.siteInfoController.GetSiteLegalInfo)
 
 	// user
-	r.GET("/user/info", a.userController.GetUserInfoByUserID)
+	r.GET("/user/info", authUserMiddleware.Auth(), a.userController.GetUserInfoByUserID)
 	r.GET("/user/action/record", authUserMiddleware.Auth(), a.userController.ActionRecord)
 	routerGroup := r.Group("", middleware.BanAPIForUserCenter)
 	routerGroup.POST("/user/login/email", a.userController.UserEmailLogin)
+	routerGroup.GET("/user/login/2fa", a.userController.GetUserTwoFactorChallenge)
+	routerGroup.POST("/user/login/2fa", a.userController.UserTwoFactorLogin)
+	routerGroup.POST("/user/login/2fa/totp/setup", a.userController.UserTwoFactorEnrollSetup)
+	routerGroup.POST("/user/login/2fa/totp", a.userController.UserTwoFactorEnrollLogin)
 	routerGroup.POST("/user/register/email", a.userController.UserRegisterByEmail)
 	routerGroup.POST("/user/email/verification", a.userController.UserVerifyEmail)
 	routerGroup.PUT("/user/email", a.userController.UserChangeEmailVerify)
 	routerGroup.POST("/user/password/reset", a.userController.RetrievePassWord)
 	routerGroup.POST("/user/password/replacement", a.userController.UseRePassWord)
 	routerGroup.PUT("/user/notification/unsubscribe", a.userController.UserUnsubscribeNotification)
+	r.GET("/user/data/export/download", a.userDataController.DownloadUserDataExport)
 
 	// plugins
 	r.GET("/plugin/status", a.pluginController.GetAllPluginStatus)
+
+	// sso
+	r.GET(sso.LoginRouterPrefix+":name", a.ssoController.SSOLogin)
+	r.GET(sso.OIDCCallbackRouterPrefix+":name", a.ssoController.SSOOIDCCallback)
+	r.POST(sso.SAMLRouterPrefix+":name/acs", a.ssoController.SSOSAMLACS)
+	r.GET(sso.SAMLRouterPrefix+":name/metadata", a.ssoController.SSOSAMLMetadata)
 }
 
 func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
@@ -185,6 +236,7 @@ func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 	r.GET("/personal/qa/top", a.questionController.UserTop)
 	r.GET("/personal/question/page", a.questionController.PersonalQuestionPage)
 	r.GET("/question/link", a.questionController.GetQuestionLink)
+	r.GET("/question/bounty", a.bountyController.GetBountyList)
 
 	// comment
 	r.GET("/comment/page", a.commentController.GetCommentWithPage)
@@ -197,6 +249,8 @@ func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 	r.GET("/tag", a.tagController.GetTagInfo)
 	r.GET("/tags", a.tagController.GetTagsBySlugName)
 	r.GET("/tag/synonyms", a.tagController.GetTagSynonyms)
+	r.GET("/tag/owners", a.tagController.GetTagOwners)
+	r.GET("/tag/setting", a.tagController.GetTagSetting)
 
 	// search
 	r.GET("/search", a.searchController.Search)
@@ -218,7 +272,10 @@ func (a *AnswerAPIRouter) RegisterUnAuthAnswerAPIRouter(r *gin.RouterGroup) {
 
 func (a *AnswerAPIRouter) RegisterAuthUserWithAnyStatusAnswerAPIRouter(r *gin.RouterGroup) {
 	r.GET("/user/logout", a.userController.UserLogout)
-	r.POST("/user/email/change/code", middleware.BanAPIForUserCenter, a.userController.UserChangeEmailSendCode)
+	r.POST("/user/email/change/code", middleware.BanAPIForUserCenter, a.authUserMiddleware.MustRecentAuth(),
+		a.userController.UserChangeEmailSendCode)
+	r.POST("/user/reauth", a.userController.UserReauth)
+	r.GET("/user/reauth/webauthn", a.twoFactorController.BeginWebAuthnReauth)
 	r.POST("/user/email/verification/send", middleware.BanAPIForUserCenter, a.userController.UserVerifyEmailSend)
 }
 
@@ -228,6 +285,8 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.GET("/revisions/unreviewed", a.revisionController.GetUnreviewedRevisionList)
 	r.PUT("/revisions/audit", a.revisionController.RevisionAudit)
 	r.GET("/revisions/edit/check", a.revisionController.CheckCanUpdateRevision)
+	r.GET("/revisions/diff", a.revisionController.GetRevisionDiff)
+	r.POST("/revisions/rollback", a.revisionController.RollbackRevision)
 	r.GET("/reviewing/type", a.revisionController.GetReviewingType)
 
 	// comment
@@ -254,12 +313,16 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 
 	// tag
 	r.GET("/question/tags", a.tagController.SearchTagLike)
+	r.POST("/question/tags/suggest", a.tagController.SuggestTags)
 	r.POST("/tag", a.tagController.AddTag)
 	r.PUT("/tag", a.tagController.UpdateTag)
 	r.POST("/tag/recover", a.tagController.RecoverTag)
 	r.DELETE("/tag", a.tagController.RemoveTag)
 	r.PUT("/tag/synonym", a.tagController.UpdateTagSynonym)
 	r.POST("/tag/merge", a.tagController.MergeTag)
+	r.PUT("/tag/parent", a.tagController.UpdateTagParent)
+	r.PUT("/tag/owners", a.tagController.UpdateTagOwners)
+	r.PUT("/tag/setting", a.tagController.UpdateTagSetting)
 
 	// collection
 	r.POST("/collection/switch", a.collectionController.CollectionSwitch)
@@ -275,6 +338,43 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.PUT("/question/operation", a.questionController.OperationQuestion)
 	r.PUT("/question/reopen", a.questionController.ReopenQuestion)
 	r.GET("/question/similar", a.questionController.GetSimilarQuestions)
+	r.POST("/question/duplicates", a.searchController.CheckDuplicateQuestions)
+	r.GET("/question/close/vote", a.closeVoteController.GetCloseVotes)
+	r.GET("/question/close/vote/page", a.closeVoteController.GetCloseVoteQuestionPage)
+	r.POST("/question/close/vote", a.closeVoteController.AddCloseVote)
+	r.POST("/question/close/vote/duplicate", a.closeVoteController.AddDuplicateVote)
+	r.POST("/question/reopen/vote", a.closeVoteController.AddReopenVote)
+	r.POST("/question/bounty", a.bountyController.AddBounty)
+	r.POST("/question/bounty/award", a.bountyController.AwardBounty)
+	r.DELETE("/question/close/vote", a.closeVoteController.RemoveCloseVote)
+	r.GET("/question/feed", a.questionController.QuestionFeed)
+
+	// saved search
+	r.GET("/search/saved", a.searchController.GetSavedSearchList)
+	r.POST("/search/saved", a.searchController.AddSavedSearch)
+	r.PUT("/search/saved", a.searchController.UpdateSavedSearch)
+	r.DELETE("/search/saved", a.searchController.DeleteSavedSearch)
+
+	// draft
+	r.GET("/drafts", a.draftController.GetDraftPage)
+	r.GET("/draft", a.draftController.GetDraft)
+	r.POST("/draft", a.draftController.SaveDraft)
+	r.PUT("/draft", a.draftController.UpdateDraft)
+	r.DELETE("/draft", a.draftController.DeleteDraft)
+
+	// scheduled post
+	r.GET("/post/scheduled", a.scheduledPostController.GetScheduledPostList)
+	r.PUT("/post/scheduled", a.scheduledPostController.ReschedulePost)
+	r.DELETE("/post/scheduled", a.scheduledPostController.CancelScheduledPost)
+
+	// space
+	r.GET("/spaces", a.spaceController.GetUserSpaces)
+	r.GET("/space", a.spaceController.GetSpace)
+	r.PUT("/space", a.spaceController.UpdateSpace)
+	r.GET("/space/members", a.spaceController.GetSpaceMemberPage)
+	r.POST("/space/member", a.spaceController.AddSpaceMember)
+	r.PUT("/space/member", a.spaceController.UpdateSpaceMember)
+	r.DELETE("/space/member", a.spaceController.RemoveSpaceMember)
 	r.POST("/question/recover", a.questionController.QuestionRecover)
 
 	// answer
@@ -285,13 +385,36 @@ func (a *AnswerAPIRouter) RegisterAnswerAPIRouter(r *gin.RouterGroup) {
 	r.POST("/answer/recover", a.answerController.RecoverAnswer)
 
 	// user
-	r.PUT("/user/password", middleware.BanAPIForUserCenter, a.userController.UserModifyPassWord)
+	r.PUT("/user/password", middleware.BanAPIForUserCenter, a.authUserMiddleware.MustRecentAuth(),
+		a.userController.UserModifyPassWord)
 	r.PUT("/user/info", a.userController.UserUpdateInfo)
 	r.PUT("/user/interface", a.userController.UserUpdateInterface)
 	r.GET("/user/notification/config", a.userController.GetUserNotificationConfig)
 	r.PUT("/user/notification/config", a.userController.UpdateUserNotificationConfig)
 	r.GET("/user/info/search", a.userController.SearchUserListByName)
 
+	// two factor
+	r.GET("/user/2fa", a.twoFactorController.GetTwoFactor)
+	twoFactorGroup := r.Group("", a.authUserMiddleware.MustRecentAuth())
+	twoFactorGroup.POST("/user/2fa/totp/setup", a.twoFactorController.SetupTOTP)
+	twoFactorGroup.POST("/user/2fa/totp", a.twoFactorController.EnableTOTP)
+	twoFactorGroup.DELETE("/user/2fa/totp", a.twoFactorController.DisableTOTP)
+	twoFactorGroup.POST("/user/2fa/recovery-codes", a.twoFactorController.RegenerateRecoveryCodes)
+	twoFactorGroup.GET("/user/2fa/webauthn/registration", a.twoFactorController.BeginWebAuthnRegistration)
+	twoFactorGroup.POST("/user/2fa/webauthn/registration", a.twoFactorController.FinishWebAuthnRegistration)
+	twoFactorGroup.DELETE("/user/2fa/webauthn", a.twoFactorController.RemoveWebAuthnCredential)
+
+	// sessions
+	r.GET("/user/sessions", a.userSessionController.GetUserSessions)
+	r.DELETE("/user/session", a.userSessionController.RemoveUserSession)
+	r.DELETE("/user/sessions", a.userSessionController.RemoveOtherUserSessions)
+
+	// user data
+	r.GET("/user/data/requests", a.userDataController.GetUserDataRequests)
+	r.POST("/user/data/export", a.userDataController.RequestUserDataExport)
+	r.POST("/user/data/erasure", a.userDataController.RequestUserErasure)
+	r.DELETE("/user/data/erasure", a.userDataController.CancelUserErasure)
+
 	// vote
 	r.GET("/personal/vote/page", a.voteController.UserVotes)
 
@@ -339,6 +462,26 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 	r.GET("/answer/page", a.questionController.AdminAnswerPage)
 	r.PUT("/answer/status", a.answerController.AdminUpdateAnswerStatus)
 
+	// space
+	r.GET("/spaces", a.spaceController.AdminGetSpacePage)
+	r.POST("/space", a.spaceController.AdminAddSpace)
+	r.DELETE("/space", a.spaceController.AdminDeleteSpace)
+
+	// tenant
+	r.GET("/tenants", a.tenantController.GetTenantPage)
+	r.POST("/tenant", a.tenantController.AddTenant)
+	r.PUT("/tenant", a.tenantController.UpdateTenant)
+
+	// sso
+	r.GET("/sso/providers", a.ssoProviderController.GetSSOProviders)
+	r.POST("/sso/provider", a.ssoProviderController.AddSSOProvider)
+	r.PUT("/sso/provider", a.ssoProviderController.UpdateSSOProvider)
+	r.DELETE("/sso/provider", a.ssoProviderController.DeleteSSOProvider)
+
+	// tag suggestion
+	r.GET("/tag/suggestion", a.tagController.AdminGetTagSuggestionStatus)
+	r.POST("/tag/suggestion/retrain", a.tagController.AdminRetrainTagSuggestion)
+
 	// user
 	r.GET("/users/page", a.adminUserController.GetUserPage)
 	r.PUT("/user/status", a.adminUserController.UpdateUserStatus)
@@ -347,7 +490,11 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 	r.POST("/user/activation", a.adminUserController.SendUserActivation)
 	r.POST("/user", a.adminUserController.AddUser)
 	r.POST("/users", a.adminUserController.AddUsers)
-	r.PUT("/user/password", a.adminUserController.UpdateUserPassword)
+	r.PUT("/user/password", a.authUserMiddleware.MustRecentAuth(), a.adminUserController.UpdateUserPassword)
+	r.DELETE("/user/2fa", a.authUserMiddleware.MustRecentAuth(), a.adminUserController.ResetUserTwoFactor)
+	r.GET("/user/sessions", a.adminUserController.GetUserSessions)
+	r.DELETE("/user/session", a.adminUserController.RemoveUserSession)
+	r.DELETE("/user/sessions", a.adminUserController.RemoveUserAllSessions)
 	r.PUT("/user/profile", a.adminUserController.EditUserProfile)
 
 	r.DELETE("/delete/permanently", a.adminUserController.DeletePermanently)
@@ -405,6 +552,12 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 
 	// roles
 	r.GET("/roles", a.roleController.GetRoleList)
+	r.GET("/role", a.roleController.GetRole)
+	r.POST("/role", a.roleController.AddRole)
+	r.PUT("/role", a.roleController.UpdateRole)
+	r.DELETE("/role", a.roleController.DeleteRole)
+	r.PUT("/role/powers", a.roleController.UpdateRolePowers)
+	r.GET("/powers", a.roleController.GetPowerList)
 
 	// plugin
 	r.GET("/plugins", a.pluginController.GetPluginList)
@@ -418,7 +571,7 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 
 	// api key
 	r.GET("/api-key/all", a.apiKeyController.GetAllAPIKeys)
-	r.POST("/api-key", a.apiKeyController.AddAPIKey)
+	r.POST("/api-key", a.authUserMiddleware.MustRecentAuth(), a.apiKeyController.AddAPIKey)
 	r.PUT("/api-key", a.apiKeyController.UpdateAPIKey)
 	r.DELETE("/api-key", a.apiKeyController.DeleteAPIKey)
 
@@ -432,6 +585,10 @@ func (a *AnswerAPIRouter) RegisterAnswerAdminAPIRouter(r *gin.RouterGroup) {
 	r.GET("/mcp-config", a.adminSiteInfoController.GetMCPConfig)
 	r.PUT("/mcp-config", a.adminSiteInfoController.UpdateMCPConfig)
 
+	// search config
+	r.GET("/search-config", a.adminSiteInfoController.GetSearchConfig)
+	r.PUT("/search-config", a.adminSiteInfoController.UpdateSearchConfig)
+
 	// AI conversation management
 	r.GET("/ai/conversation/page", a.aiConversationAdminController.GetConversationList)
 	r.GET("/ai/conversation", a.aiConversationAdminController.GetConversationDetail)
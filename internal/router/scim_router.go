@@ -0,0 +1,40 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package router
+
+import "github.com/gin-gonic/gin"
+
+// RegisterSCIMRouter register the SCIM 2.0 provisioning endpoints
+func (a *AnswerAPIRouter) RegisterSCIMRouter(r *gin.RouterGroup) {
+	r.GET("/ServiceProviderConfig", a.scimController.GetServiceProviderConfig)
+
+	r.GET("/Users", a.scimController.GetUsers)
+	r.POST("/Users", a.scimController.AddUser)
+	r.GET("/Users/:id", a.scimController.GetUser)
+	r.PUT("/Users/:id", a.scimController.UpdateUser)
+	r.PATCH("/Users/:id", a.scimController.PatchUser)
+	r.DELETE("/Users/:id", a.scimController.DeleteUser)
+
+	r.GET("/Groups", a.scimController.GetGroups)
+	r.POST("/Groups", a.scimController.AddGroup)
+	r.GET("/Groups/:id", a.scimController.GetGroup)
+	r.PUT("/Groups/:id", a.scimController.UpdateGroup)
+	r.PATCH("/Groups/:id", a.scimController.PatchGroup)
+	r.DELETE("/Groups/:id", a.scimController.DeleteGroup)
+}
@@ -44,10 +44,11 @@ func NewStaticRouter(serviceConfig *service_config.ServiceConfig) *StaticRouter
 
 // RegisterStaticRouter register static api router
 func (a *StaticRouter) RegisterStaticRouter(r *gin.RouterGroup) {
-	r.Static("/uploads/"+constant.AvatarSubPath, filepath.Join(a.serviceConfig.UploadPath, constant.AvatarSubPath))
-	r.Static("/uploads/"+constant.AvatarThumbSubPath, filepath.Join(a.serviceConfig.UploadPath, constant.AvatarThumbSubPath))
-	r.Static("/uploads/"+constant.PostSubPath, filepath.Join(a.serviceConfig.UploadPath, constant.PostSubPath))
-	r.Static("/uploads/"+constant.BrandingSubPath, filepath.Join(a.serviceConfig.UploadPath, constant.BrandingSubPath))
+	for _, subPath := range []string{constant.AvatarSubPath, constant.AvatarThumbSubPath,
+		constant.PostSubPath, constant.BrandingSubPath} {
+		r.GET("/uploads/"+subPath+"/*filepath", a.serveUploadFile(subPath))
+		r.HEAD("/uploads/"+subPath+"/*filepath", a.serveUploadFile(subPath))
+	}
 	r.GET("/uploads/"+constant.FilesPostSubPath+"/*filepath", func(c *gin.Context) {
 		// The filepath such as hash/123.pdf
 		filePath := c.Param("filepath")
@@ -56,7 +57,7 @@ func (a *StaticRouter) RegisterStaticRouter(r *gin.RouterGroup) {
 		// The real filename is hash.pdf
 		realFilename := strings.TrimSuffix(filePath, "/"+originalFilename) + filepath.Ext(originalFilename)
 		// The file local path is /uploads/files/post/hash.pdf
-		fileLocalPath := filepath.Join(a.serviceConfig.UploadPath, constant.FilesPostSubPath, realFilename)
+		fileLocalPath := filepath.Join(a.serviceConfig.GetUploadPath(c), constant.FilesPostSubPath, realFilename)
 		// If the file is not exist, return 404
 		if !dir.CheckFileExist(fileLocalPath) {
 			c.Redirect(http.StatusFound, "/404")
@@ -65,3 +66,15 @@ func (a *StaticRouter) RegisterStaticRouter(r *gin.RouterGroup) {
 		c.FileAttachment(fileLocalPath, originalFilename)
 	})
 }
+
+// serveUploadFile serve the uploaded files from the upload path of the tenant
+func (a *StaticRouter) serveUploadFile(subPath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fileLocalPath := filepath.Join(a.serviceConfig.GetUploadPath(c), subPath, filepath.Clean("/"+c.Param("filepath")))
+		if !dir.CheckFileExist(fileLocalPath) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.File(fileLocalPath)
+	}
+}
@@ -50,6 +50,7 @@ type AnswerAddReq struct {
 	QuestionID  string `json:"question_id"`
 	Content     string `validate:"required,notblank,gte=6,lte=65535" json:"content"`
 	HTML        string `json:"-"`
+	PublishAt   int64  `validate:"omitempty,gte=0" json:"publish_at"`
 	UserID      string `json:"-"`
 	CanEdit     bool   `json:"-"`
 	CanDelete   bool   `json:"-"`
@@ -135,6 +136,7 @@ type AnswerInfo struct {
 	VoteCount      int               `json:"vote_count"`
 	QuestionInfo   *QuestionInfoResp `json:"question_info,omitempty"`
 	Status         int               `json:"status"`
+	PublishAt      int64             `json:"publish_at,omitempty"`
 
 	// MemberActions
 	MemberActions []*PermissionMemberAction `json:"member_actions"`
@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+const (
+	BountyStatusActive  = "active"
+	BountyStatusAwarded = "awarded"
+	BountyStatusExpired = "expired"
+)
+
+// AddBountyReq offer reputation as a bounty on the open question
+type AddBountyReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	Amount     int    `validate:"required,gte=1" json:"amount"`
+	UserID     string `json:"-"`
+}
+
+// AwardBountyReq award the open bounty of the question to an answer
+type AwardBountyReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	AnswerID   string `validate:"required" json:"answer_id"`
+	UserID     string `json:"-"`
+}
+
+// GetBountyReq get the bounties of the question
+type GetBountyReq struct {
+	QuestionID string `validate:"required" form:"question_id"`
+	UserID     string `json:"-"`
+}
+
+// BountyResp the bounty of the question
+type BountyResp struct {
+	ID         string `json:"id"`
+	QuestionID string `json:"question_id"`
+	Amount     int    `json:"amount"`
+	// active, awarded or expired
+	Status    string         `json:"status"`
+	CreatedAt int64          `json:"created_at"`
+	ExpiredAt int64          `json:"expired_at"`
+	UserInfo  *UserBasicInfo `json:"user_info"`
+	// the answer the bounty is awarded to
+	AnswerID      string         `json:"answer_id,omitempty"`
+	AwardedAmount int            `json:"awarded_amount"`
+	AwardedAt     int64          `json:"awarded_at,omitempty"`
+	AwardedUser   *UserBasicInfo `json:"awarded_user,omitempty"`
+	// the login user offered the open bounty and can award it
+	CanAward bool `json:"can_award"`
+}
+
+// BountyAwardInfo the reputation transaction of awarding a bounty
+type BountyAwardInfo struct {
+	BountyID      string
+	QuestionID    string
+	AnswerID      string
+	OfferUserID   string
+	AwardedUserID string
+	AwardedAmount int
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+const (
+	// CloseVoteTypeClose vote to close the open question
+	CloseVoteTypeClose = "close"
+	// CloseVoteTypeReopen vote to reopen the closed question
+	CloseVoteTypeReopen = "reopen"
+)
+
+// AddCloseVoteReq vote to close the question with a close reason
+type AddCloseVoteReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	CloseType  int    `validate:"required" json:"close_type"`
+	CloseMsg   string `validate:"omitempty,lte=500" json:"close_msg"`
+	// the question that this question duplicates, required when closing as a duplicate
+	DuplicateQuestionID string `json:"duplicate_question_id"`
+	// the vote of the user who can close questions is binding, the question is closed at once
+	Binding bool   `json:"-"`
+	UserID  string `json:"-"`
+}
+
+// AddDuplicateVoteReq vote to close the question as a duplicate
+type AddDuplicateVoteReq struct {
+	QuestionID          string `validate:"required" json:"question_id"`
+	DuplicateQuestionID string `validate:"required" json:"duplicate_question_id"`
+	Binding             bool   `json:"-"`
+	UserID              string `json:"-"`
+}
+
+// AddReopenVoteReq vote to reopen the closed question
+type AddReopenVoteReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	// the vote of the user who can reopen questions is binding, the question is reopened at once
+	Binding bool   `json:"-"`
+	UserID  string `json:"-"`
+}
+
+// RemoveCloseVoteReq retract the close or reopen vote of the question
+type RemoveCloseVoteReq struct {
+	QuestionID string `validate:"required" json:"question_id"`
+	UserID     string `json:"-"`
+}
+
+// GetCloseVoteReq get the close or reopen votes of the question
+type GetCloseVoteReq struct {
+	QuestionID string `validate:"required" form:"question_id"`
+	UserID     string `json:"-"`
+}
+
+// GetCloseVoteResp the close votes of the open question or the reopen votes of the closed question
+type GetCloseVoteResp struct {
+	// close or reopen
+	VoteType  string `json:"vote_type"`
+	VoteCount int    `json:"vote_count"`
+	// the question is closed or reopened automatically when the vote count reaches the threshold
+	Threshold int  `json:"threshold"`
+	Voted     bool `json:"voted"`
+	Closed    bool `json:"closed"`
+	// the close reasons voted for, the most voted first
+	Reasons []*CloseVoteReason `json:"reasons"`
+	// the questions voted as the duplicate targets, the most voted first
+	Duplicates []*CloseVoteDuplicate `json:"duplicates"`
+}
+
+// CloseVoteReason the close reason voted for
+type CloseVoteReason struct {
+	CloseType int    `json:"close_type"`
+	Name      string `json:"name"`
+	VoteCount int    `json:"vote_count"`
+}
+
+// CloseVoteDuplicate the question voted as the duplicate target
+type CloseVoteDuplicate struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	UrlTitle  string `json:"url_title"`
+	VoteCount int    `json:"vote_count"`
+}
+
+// GetCloseVoteQuestionPageReq get the questions with pending close or reopen votes to review
+type GetCloseVoteQuestionPageReq struct {
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1,max=100" form:"page_size"`
+	UserID   string `json:"-"`
+}
+
+// CloseVoteQuestionResp the question with pending close or reopen votes
+type CloseVoteQuestionResp struct {
+	ID              string `json:"id"`
+	Title           string `json:"title"`
+	UrlTitle        string `json:"url_title"`
+	Status          int    `json:"status"`
+	CloseVoteCount  int    `json:"close_vote_count"`
+	ReopenVoteCount int    `json:"reopen_vote_count"`
+	// the votes of the question for the current state
+	Votes *GetCloseVoteResp `json:"votes"`
+}
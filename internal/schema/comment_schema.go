@@ -233,6 +233,8 @@ type GetCommentPersonalWithPageReq struct {
 	Username string `validate:"omitempty,gt=0,lte=100" form:"username"`
 	// user id
 	UserID string `json:"-"`
+	// login user id
+	LoginUserID string `json:"-"`
 }
 
 // GetCommentPersonalWithPageResp comment response
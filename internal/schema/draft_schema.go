@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// SaveDraftReq create or update the draft of the user for the draft type and object
+type SaveDraftReq struct {
+	// new_question, new_answer, edit_question or edit_answer
+	DraftType string   `validate:"required,oneof=new_question new_answer edit_question edit_answer" json:"draft_type"`
+	ObjectID  string   `validate:"omitempty" json:"object_id"`
+	Title     string   `validate:"omitempty,lte=150" json:"title"`
+	Content   string   `validate:"omitempty,lte=65535" json:"content"`
+	Tags      []string `validate:"omitempty,lte=10,dive,lte=35" json:"tags"`
+	UserID    string   `json:"-"`
+}
+
+// UpdateDraftReq update the draft by id
+type UpdateDraftReq struct {
+	ID      int      `validate:"required" json:"id"`
+	Title   string   `validate:"omitempty,lte=150" json:"title"`
+	Content string   `validate:"omitempty,lte=65535" json:"content"`
+	Tags    []string `validate:"omitempty,lte=10,dive,lte=35" json:"tags"`
+	UserID  string   `json:"-"`
+}
+
+// GetDraftReq get the draft of the user for the draft type and object to resume editing
+type GetDraftReq struct {
+	DraftType string `validate:"required,oneof=new_question new_answer edit_question edit_answer" form:"draft_type"`
+	ObjectID  string `validate:"omitempty" form:"object_id"`
+	UserID    string `json:"-"`
+}
+
+// GetDraftPageReq get the drafts of the user, the latest updated first
+type GetDraftPageReq struct {
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1,max=100" form:"page_size"`
+	UserID   string `json:"-"`
+}
+
+// DeleteDraftReq delete the draft by id
+type DeleteDraftReq struct {
+	ID     int    `validate:"required" json:"id"`
+	UserID string `json:"-"`
+}
+
+// DraftResp draft response
+type DraftResp struct {
+	ID        int      `json:"id"`
+	DraftType string   `json:"draft_type"`
+	ObjectID  string   `json:"object_id"`
+	Title     string   `json:"title"`
+	Content   string   `json:"content"`
+	Tags      []string `json:"tags"`
+	CreatedAt int64    `json:"created_at"`
+	UpdatedAt int64    `json:"updated_at"`
+	// the draft is deleted after this time if it is not updated
+	ExpiredAt int64 `json:"expired_at"`
+}
@@ -75,6 +75,18 @@ type TestTemplateData struct {
 	SiteName string
 }
 
+type UserDataExportTemplateData struct {
+	SiteName    string
+	DownloadUrl string
+	ExpireDays  int
+}
+
+type UserErasureTemplateData struct {
+	SiteName      string
+	ScheduledDate string
+	SettingsUrl   string
+}
+
 type NewAnswerTemplateRawData struct {
 	AnswerUserDisplayName string
 	QuestionTitle         string
@@ -134,6 +146,7 @@ type NewQuestionTemplateRawData struct {
 	UnsubscribeCode      string
 	Tags                 []string
 	TagIDs               []string
+	SpaceID              int
 }
 
 type NewQuestionTemplateData struct {
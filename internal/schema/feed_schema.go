@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+const (
+	// QuestionFeedDefaultPageSize default page size of personalized feed
+	QuestionFeedDefaultPageSize = 20
+)
+
+// QuestionFeedReq personalized question feed request
+type QuestionFeedReq struct {
+	Cursor   string `validate:"omitempty,lte=200" form:"cursor"`
+	PageSize int    `validate:"omitempty,min=1,max=50" form:"page_size"`
+
+	LoginUserID string `json:"-"`
+}
+
+// QuestionFeedResp personalized question feed response
+type QuestionFeedResp struct {
+	List []*QuestionPageResp `json:"list"`
+	// NextCursor is empty when there is no more data
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
@@ -23,6 +23,7 @@ const (
 	ForbiddenReasonTypeInactive      = "inactive"
 	ForbiddenReasonTypeURLExpired    = "url_expired"
 	ForbiddenReasonTypeUserSuspended = "suspended"
+	ForbiddenReasonTypeRecentAuth    = "recent_auth_required"
 )
 
 // ForbiddenResp forbidden response
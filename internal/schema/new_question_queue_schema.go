@@ -36,13 +36,14 @@ type ExternalNotificationMsg struct {
 }
 
 func CreateNewQuestionNotificationMsg(
-	questionID, questionTitle, questionAuthorUserID string, tags []*entity.Tag) *ExternalNotificationMsg {
+	questionID, questionTitle, questionAuthorUserID string, spaceID int, tags []*entity.Tag) *ExternalNotificationMsg {
 	questionID = uid.DeShortID(questionID)
 	msg := &ExternalNotificationMsg{
 		NewQuestionTemplateRawData: &NewQuestionTemplateRawData{
 			QuestionAuthorUserID: questionAuthorUserID,
 			QuestionID:           questionID,
 			QuestionTitle:        questionTitle,
+			SpaceID:              spaceID,
 		},
 	}
 	for _, tag := range tags {
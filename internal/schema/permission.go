@@ -44,6 +44,8 @@ type PermissionMemberAction struct {
 type GetPermissionReq struct {
 	Action  string   `form:"action"`
 	Actions []string `validate:"omitempty" form:"actions"`
+	// ObjectID the object which is operated, the powers of the role scoped to tags are checked on it
+	ObjectID string `validate:"omitempty" form:"object_id"`
 }
 
 func (r *GetPermissionReq) Check() (errField []*validator.FormErrorField, err error) {
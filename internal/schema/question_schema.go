@@ -54,6 +54,8 @@ type CloseQuestionReq struct {
 	CloseType int    `json:"close_type"` // close_type
 	CloseMsg  string `json:"close_msg"`  // close_type
 	UserID    string `json:"-"`          // user_id
+	// the question that this question duplicates, only used when closing as a duplicate
+	DuplicateQuestionID string `json:"duplicate_question_id"`
 }
 
 type OperationQuestionReq struct {
@@ -65,8 +67,9 @@ type OperationQuestionReq struct {
 }
 
 type CloseQuestionMeta struct {
-	CloseType int    `json:"close_type"`
-	CloseMsg  string `json:"close_msg"`
+	CloseType           int    `json:"close_type"`
+	CloseMsg            string `json:"close_msg"`
+	DuplicateQuestionID string `json:"duplicate_question_id,omitempty"`
 }
 
 // ReopenQuestionReq reopen question request
@@ -84,6 +87,10 @@ type QuestionAdd struct {
 	HTML string `json:"-"`
 	// tags
 	Tags []*TagItem `validate:"dive" json:"tags"`
+	// the unix time to publish the question, it is published immediately if not set
+	PublishAt int64 `validate:"omitempty,gte=0" json:"publish_at"`
+	// the space to ask in, the question is public if not set
+	SpaceID int `validate:"omitempty,min=0" json:"space_id"`
 	// user id
 	UserID string `json:"-"`
 	QuestionPermission
@@ -114,6 +121,10 @@ type QuestionAddByAnswer struct {
 	AnswerHTML    string `json:"-"`
 	// tags
 	Tags []*TagItem `validate:"dive" json:"tags"`
+	// the unix time to publish the question and answer, they are published immediately if not set
+	PublishAt int64 `validate:"omitempty,gte=0" json:"publish_at"`
+	// the space to ask in, the question is public if not set
+	SpaceID int `validate:"omitempty,min=0" json:"space_id"`
 	// user id
 	UserID              string   `json:"-"`
 	MentionUsernameList []string `validate:"omitempty" json:"mention_username_list"`
@@ -229,6 +240,24 @@ type QuestionBaseInfo struct {
 	AcceptedAnswer  bool   `json:"accepted_answer"`
 }
 
+// CheckDuplicateQuestionReq check whether the question draft duplicates the existing questions
+type CheckDuplicateQuestionReq struct {
+	Title   string `validate:"required,notblank,lte=150" json:"title"`
+	Content string `validate:"omitempty,lte=65535" json:"content"`
+}
+
+// DuplicateQuestionResp the existing question that the question draft may duplicate
+type DuplicateQuestionResp struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	UrlTitle    string `json:"url_title"`
+	AnswerCount int    `json:"answer_count"`
+	Accepted    bool   `json:"accepted"`
+	Status      string `json:"status"`
+	// the confidence of the duplication, from 0 to 1
+	Confidence float64 `json:"confidence"`
+}
+
 type QuestionInfoResp struct {
 	ID                   string         `json:"id" `
 	Title                string         `json:"title"`
@@ -243,16 +272,22 @@ type QuestionInfoResp struct {
 	AnswerCount          int            `json:"answer_count"`
 	CollectionCount      int            `json:"collection_count"`
 	FollowCount          int            `json:"follow_count"`
+	CloseVoteCount       int            `json:"close_vote_count"`
+	ReopenVoteCount      int            `json:"reopen_vote_count"`
+	BountyAmount         int            `json:"bounty_amount"`
 	AcceptedAnswerID     string         `json:"accepted_answer_id"`
 	LastAnswerID         string         `json:"last_answer_id"`
 	CreateTime           int64          `json:"create_time"`
 	UpdateTime           int64          `json:"-"`
 	PostUpdateTime       int64          `json:"update_time"`
 	QuestionUpdateTime   int64          `json:"edit_time"`
+	PublishAt            int64          `json:"publish_at,omitempty"`
 	Pin                  int            `json:"pin"`
 	Show                 int            `json:"show"`
 	Status               int            `json:"status"`
 	Operation            *Operation     `json:"operation,omitempty"`
+	DuplicateQuestionID  string         `json:"duplicate_question_id,omitempty"`
+	SpaceID              int            `json:"space_id"`
 	UserID               string         `json:"-"`
 	LastEditUserID       string         `json:"-"`
 	LastAnsweredUserID   string         `json:"-"`
@@ -359,6 +394,7 @@ const (
 	QuestionOrderCondUnanswered = "unanswered"
 	QuestionOrderCondRecommend  = "recommend"
 	QuestionOrderCondFrequent   = "frequent"
+	QuestionOrderCondFeatured   = "featured"
 
 	// HotInDays limit max days of the hottest question
 	HotInDays = 90
@@ -368,10 +404,11 @@ const (
 type QuestionPageReq struct {
 	Page      int    `validate:"omitempty,min=1" form:"page"`
 	PageSize  int    `validate:"omitempty,min=1" form:"page_size"`
-	OrderCond string `validate:"omitempty,oneof=newest active hot score unanswered recommend frequent" form:"order"`
+	OrderCond string `validate:"omitempty,oneof=newest active hot score unanswered recommend frequent featured" form:"order"`
 	Tag       string `validate:"omitempty,gt=0,lte=100" form:"tag"`
 	Username  string `validate:"omitempty,gt=0,lte=100" form:"username"`
 	InDays    int    `validate:"omitempty,min=1" form:"in_days"`
+	SpaceID   int    `validate:"omitempty,min=0" form:"space_id"`
 
 	LoginUserID      string `json:"-"`
 	UserIDBeSearched string `json:"-"`
@@ -403,6 +440,7 @@ type QuestionPageResp struct {
 	AnswerCount     int `json:"answer_count"`
 	CollectionCount int `json:"collection_count"`
 	FollowCount     int `json:"follow_count"`
+	BountyAmount    int `json:"bounty_amount"`
 
 	// answer information
 	AcceptedAnswerID   string    `json:"accepted_answer_id"`
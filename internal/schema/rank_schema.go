@@ -29,6 +29,8 @@ type GetRankPersonalWithPageReq struct {
 	Username string `validate:"omitempty,gt=0,lte=100" form:"username"`
 	// user id
 	UserID string `json:"-"`
+	// login user id
+	LoginUserID string `json:"-"`
 }
 
 // GetRankPersonalPageResp rank response
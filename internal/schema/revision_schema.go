@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/pkg/diff"
 )
 
 // AddRevisionDTO add revision request
@@ -49,6 +50,53 @@ type GetRevisionListReq struct {
 	UserID   string `json:"-"`
 }
 
+// GetRevisionDiffReq get the diff between two revisions of the same object
+type GetRevisionDiffReq struct {
+	// the old revision id
+	SourceID string `validate:"required" form:"source_id"`
+	// the new revision id
+	TargetID string `validate:"required" form:"target_id"`
+	IsAdmin  bool   `json:"-"`
+	UserID   string `json:"-"`
+}
+
+// GetRevisionDiffResp the word level diff of title, content and tags, for the tag object the tags is its slug name
+type GetRevisionDiffResp struct {
+	ObjectID   string          `json:"object_id"`
+	ObjectType string          `json:"object_type"`
+	SourceID   string          `json:"source_id"`
+	TargetID   string          `json:"target_id"`
+	Title      []*diff.Segment `json:"title"`
+	Content    []*diff.Segment `json:"content"`
+	Tags       []*diff.Segment `json:"tags"`
+}
+
+// RevisionRollbackReq restore the object to the revision by a new edit
+type RevisionRollbackReq struct {
+	// revision id
+	ID          string `validate:"required" json:"id"`
+	EditSummary string `validate:"omitempty,lte=255" json:"edit_summary"`
+	CaptchaID   string `json:"captcha_id"`
+	CaptchaCode string `json:"captcha_code"`
+	UserID      string `json:"-"`
+}
+
+// RevisionRollbackDTO the edit request to restore the object, only the one of the object type is set
+type RevisionRollbackDTO struct {
+	ObjectID   string
+	ObjectType string
+	Question   *QuestionUpdate
+	Answer     *AnswerUpdateReq
+	Tag        *UpdateTagReq
+}
+
+// RevisionRollbackResp revision rollback response
+type RevisionRollbackResp struct {
+	ObjectID      string `json:"object_id"`
+	ObjectType    string `json:"object_type"`
+	WaitForReview bool   `json:"wait_for_review"`
+}
+
 const RevisionAuditApprove = "approve"
 const RevisionAuditReject = "reject"
 
@@ -24,4 +24,61 @@ type GetRoleResp struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// BuiltIn the user, admin and moderator roles can not be edited or deleted
+	BuiltIn bool `json:"built_in"`
+}
+
+// GetRoleReq get role request
+type GetRoleReq struct {
+	ID int `validate:"required" form:"id"`
+}
+
+// GetRoleDetailResp the role with its powers and the tags they are scoped to
+type GetRoleDetailResp struct {
+	GetRoleResp
+	PowerTypes []string       `json:"power_types"`
+	Tags       []*RoleTagResp `json:"tags"`
+}
+
+// RoleTagResp the tag the role is scoped to
+type RoleTagResp struct {
+	ID          string `json:"id"`
+	SlugName    string `json:"slug_name"`
+	DisplayName string `json:"display_name"`
+}
+
+// AddRoleReq add the custom role, the powers apply only to the posts with the tags if any is set
+type AddRoleReq struct {
+	Name        string   `validate:"required,notblank,gt=0,lte=50" json:"name"`
+	Description string   `validate:"omitempty,lte=200" json:"description"`
+	TagIDs      []string `validate:"omitempty,max=100,dive,gt=0" json:"tag_ids"`
+}
+
+// AddRoleResp add role response
+type AddRoleResp struct {
+	ID int `json:"id"`
+}
+
+// UpdateRoleReq update the custom role
+type UpdateRoleReq struct {
+	ID int `validate:"required" json:"id"`
+	AddRoleReq
+}
+
+// DeleteRoleReq delete the custom role
+type DeleteRoleReq struct {
+	ID int `validate:"required" json:"id"`
+}
+
+// GetPowerResp the power that can be granted to the role
+type GetPowerResp struct {
+	PowerType   string `json:"power_type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// UpdateRolePowersReq replace the powers of the custom role
+type UpdateRolePowersReq struct {
+	RoleID     int      `validate:"required" json:"role_id"`
+	PowerTypes []string `validate:"omitempty,max=200,dive,gt=0,lte=100" json:"power_types"`
 }
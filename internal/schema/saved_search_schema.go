@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// GetSavedSearchListReq get saved search list request
+type GetSavedSearchListReq struct {
+	UserID string `json:"-"`
+}
+
+// SavedSearchResp saved search response
+type SavedSearchResp struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	Notify    bool   `json:"notify"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// AddSavedSearchReq add saved search request
+type AddSavedSearchReq struct {
+	Name   string `validate:"required,notblank,lte=100" json:"name"`
+	Query  string `validate:"required,notblank,lte=200" json:"query"`
+	Notify bool   `json:"notify"`
+	UserID string `json:"-"`
+}
+
+// UpdateSavedSearchReq update saved search request
+type UpdateSavedSearchReq struct {
+	ID     int    `validate:"required" json:"id"`
+	Name   string `validate:"required,notblank,lte=100" json:"name"`
+	Query  string `validate:"required,notblank,lte=200" json:"query"`
+	Notify bool   `json:"notify"`
+	UserID string `json:"-"`
+}
+
+// DeleteSavedSearchReq delete saved search request
+type DeleteSavedSearchReq struct {
+	ID     int    `validate:"required" json:"id"`
+	UserID string `json:"-"`
+}
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// GetScheduledPostListReq get the scheduled posts of the user
+type GetScheduledPostListReq struct {
+	UserID string `json:"-"`
+}
+
+// ReschedulePostReq change the publish time of the scheduled post
+type ReschedulePostReq struct {
+	// the id of the scheduled question or answer
+	ObjectID string `validate:"required" json:"object_id"`
+	// the unix time to publish the post
+	PublishAt        int64  `validate:"required,gt=0" json:"publish_at"`
+	UserID           string `json:"-"`
+	IsAdminModerator bool   `json:"-"`
+}
+
+// CancelScheduledPostReq cancel the scheduled post, the post is deleted
+type CancelScheduledPostReq struct {
+	// the id of the scheduled question or answer
+	ObjectID         string `validate:"required" json:"object_id"`
+	UserID           string `json:"-"`
+	IsAdminModerator bool   `json:"-"`
+}
+
+// ScheduledPostResp the scheduled post
+type ScheduledPostResp struct {
+	ObjectID string `json:"object_id"`
+	// question or answer
+	ObjectType string `json:"object_type"`
+	QuestionID string `json:"question_id"`
+	// the title of the question or the question of the answer
+	Title     string `json:"title"`
+	Excerpt   string `json:"excerpt"`
+	PublishAt int64  `json:"publish_at"`
+	CreatedAt int64  `json:"created_at"`
+}
@@ -20,30 +20,43 @@
 package schema
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
 	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/validator"
+	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/plugin"
 )
 
+const (
+	// SearchModeKeyword search by keywords only
+	SearchModeKeyword = "keyword"
+	// SearchModeHybrid fuse the keyword and semantic search results
+	SearchModeHybrid = "hybrid"
+)
+
 type SearchDTO struct {
-	Query       string `validate:"required,gte=1,lte=60" form:"q"`
-	Page        int    `validate:"omitempty,min=1" form:"page,default=1"`
-	Size        int    `validate:"omitempty,min=1,max=50" form:"size,default=30"`
-	Order       string `validate:"required,oneof=newest active score relevance" form:"order,default=relevance" enums:"newest,active,score,relevance"`
+	Query string `validate:"required,gte=1,lte=200" form:"q"`
+	Page  int    `validate:"omitempty,min=1" form:"page,default=1"`
+	Size  int    `validate:"omitempty,min=1,max=50" form:"size,default=30"`
+	Order string `validate:"required,oneof=newest active score relevance" form:"order,default=relevance" enums:"newest,active,score,relevance"`
+	Mode  string `validate:"omitempty,oneof=keyword hybrid" form:"mode" enums:"keyword,hybrid"`
+	// search in the space, the public contents are searched by default
+	SpaceID int `validate:"omitempty,min=0" form:"space_id"`
+	// return the facets of all matched contents
+	Facets      bool   `form:"facets"`
 	CaptchaID   string `form:"captcha_id"`
 	CaptchaCode string `form:"captcha_code"`
 	UserID      string `json:"-"`
 }
 
 func (s *SearchDTO) Check() (errField []*validator.FormErrorField, err error) {
-	// Replace special characters.
-	// Special characters will cause the search abnormal, such as search for "#" will get nearly all the content that Markdown format.
-	replacedContent, patterns := ReplaceSearchContent(s.Query)
-	s.Query = strings.Join(append(patterns, replacedContent), " ")
-
+	// The special characters are handled by the search parser,
+	// because the operators like - ( ) " are part of the query syntax.
+	s.Query = strings.TrimSpace(s.Query)
 	return nil, nil
 }
 
@@ -78,6 +91,8 @@ type SearchCondition struct {
 	VoteAmount int
 	// only show not accepted answer's question
 	NotAccepted bool
+	// only show the question that has accepted answer
+	HasAccepted bool
 	// view amount
 	Views int
 	// answer count
@@ -88,8 +103,22 @@ type SearchCondition struct {
 	QuestionID string
 	// search query tags
 	Tags [][]string
+	// search query tags that should be excluded
+	NotTags [][]string
 	// search query keywords
 	Words []string
+	// search query expression, include keywords, phrases and tags with boolean operators
+	Expr *plugin.SearchExpr
+	// question closed condition
+	Closed plugin.SearchClosedCond
+	// created time range
+	Created plugin.SearchTimeRange
+	// updated time range
+	Updated plugin.SearchTimeRange
+	// code block language
+	Lang string
+	// the space of the contents, 0 for the public contents
+	SpaceID int
 }
 
 // SearchAll check if search all
@@ -107,9 +136,40 @@ func (s *SearchCondition) SearchAnswer() bool {
 	return s.TargetType == constant.AnswerObjectType
 }
 
+// IsPlainText check if the search only has keywords and phrases without any filters,
+// the semantic search can not apply the filters, so only the plain text search can be hybrid.
+func (s *SearchCondition) IsPlainText() bool {
+	if len(s.UserID) > 0 || s.VoteAmount >= 0 || s.Views >= 0 || s.AnswerAmount >= 0 ||
+		s.NotAccepted || s.HasAccepted || s.Accepted || len(s.QuestionID) > 0 ||
+		s.Closed != plugin.ClosedCondAll || !s.Created.IsZero() || !s.Updated.IsZero() || len(s.Lang) > 0 ||
+		s.SpaceID != entity.PublicSpaceID {
+		return false
+	}
+	return isPlainTextExpr(s.Expr)
+}
+
+func isPlainTextExpr(expr *plugin.SearchExpr) bool {
+	if expr == nil {
+		return true
+	}
+	switch expr.Type {
+	case plugin.SearchExprTerm, plugin.SearchExprPhrase:
+		return true
+	case plugin.SearchExprAnd, plugin.SearchExprOr:
+		for _, child := range expr.Children {
+			if !isPlainTextExpr(child) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 // Convert2PluginSearchCond convert to plugin search condition
-func (s *SearchCondition) Convert2PluginSearchCond(page, pageSize int, order string) *plugin.SearchBasicCond {
+func (s *SearchCondition) Convert2PluginSearchCond(ctx context.Context, page, pageSize int, order string) *plugin.SearchBasicCond {
 	basic := &plugin.SearchBasicCond{
+		TenantID:     handler.GetTenantID(ctx),
 		Page:         page,
 		PageSize:     pageSize,
 		Words:        s.Words,
@@ -120,26 +180,37 @@ func (s *SearchCondition) Convert2PluginSearchCond(page, pageSize int, order str
 		VoteAmount:   s.VoteAmount,
 		ViewAmount:   s.Views,
 		AnswerAmount: s.AnswerAmount,
+		Expr:         s.Expr,
+		NotTagIDs:    s.NotTags,
+		Created:      s.Created,
+		Updated:      s.Updated,
+		Lang:         s.Lang,
 	}
 	if s.Accepted {
 		basic.AnswerAccepted = plugin.AcceptedCondTrue
 	} else {
 		basic.AnswerAccepted = plugin.AcceptedCondAll
 	}
-	if s.NotAccepted {
+	switch {
+	case s.NotAccepted:
 		basic.QuestionAccepted = plugin.AcceptedCondFalse
-	} else {
+	case s.HasAccepted:
+		basic.QuestionAccepted = plugin.AcceptedCondTrue
+	default:
 		basic.QuestionAccepted = plugin.AcceptedCondAll
 	}
+	basic.QuestionClosed = s.Closed
 	return basic
 }
 
 type SearchObject struct {
-	ID              string `json:"id"`
-	QuestionID      string `json:"question_id"`
-	Title           string `json:"title"`
-	UrlTitle        string `json:"url_title"`
-	Excerpt         string `json:"excerpt"`
+	ID         string `json:"id"`
+	QuestionID string `json:"question_id"`
+	Title      string `json:"title"`
+	UrlTitle   string `json:"url_title"`
+	Excerpt    string `json:"excerpt"`
+	// the snippet of the matched content, the matched words are wrapped by <em> and the others are escaped
+	Highlight       string `json:"highlight"`
 	CreatedAtParsed int64  `json:"created_at"`
 	VoteCount       int    `json:"vote_count"`
 	Accepted        bool   `json:"accepted"`
@@ -179,8 +250,30 @@ type SearchResult struct {
 
 type SearchResp struct {
 	Total int64 `json:"count"`
+	// search mode: keyword/hybrid
+	Mode string `json:"mode"`
 	// search response
 	SearchResults []*SearchResult `json:"list"`
+	// the facets of all matched contents, only returned when requested
+	Facets *SearchFacetsResp `json:"facets,omitempty"`
+}
+
+// SearchFacetItem the count of matched contents of a facet value
+type SearchFacetItem struct {
+	// tag slug name, username, accepted/unaccepted, answered/unanswered or the date bucket like 1w
+	Key string `json:"key"`
+	// the display name of the tag or user
+	Name  string `json:"name,omitempty"`
+	Count int64  `json:"count"`
+}
+
+type SearchFacetsResp struct {
+	Tags     []*SearchFacetItem `json:"tags"`
+	Authors  []*SearchFacetItem `json:"authors"`
+	Accepted []*SearchFacetItem `json:"accepted"`
+	Answered []*SearchFacetItem `json:"answered"`
+	// the date buckets are cumulative, 1w contains 1d
+	Created []*SearchFacetItem `json:"created"`
 }
 
 type SearchDescResp struct {
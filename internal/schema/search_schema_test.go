@@ -23,6 +23,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/apache/answer/plugin"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,3 +40,24 @@ func TestReplaceSearchContent(t *testing.T) {
 
 	assert.Equal(t, "user:aaa-sss score:3 [tag1] [tag2] ssssfdfdf as fsadf", ret)
 }
+
+func TestSearchCondition_IsPlainText(t *testing.T) {
+	newCond := func(expr *plugin.SearchExpr) *SearchCondition {
+		return &SearchCondition{VoteAmount: -1, Views: -1, AnswerAmount: -1, Expr: expr}
+	}
+	term := &plugin.SearchExpr{Type: plugin.SearchExprTerm, Value: "go"}
+	phrase := &plugin.SearchExpr{Type: plugin.SearchExprPhrase, Value: "hello world"}
+	tag := &plugin.SearchExpr{Type: plugin.SearchExprTag, Value: "go"}
+
+	assert.True(t, newCond(term).IsPlainText())
+	assert.True(t, newCond(&plugin.SearchExpr{Type: plugin.SearchExprOr, Children: []*plugin.SearchExpr{term, phrase}}).IsPlainText())
+	assert.False(t, newCond(&plugin.SearchExpr{Type: plugin.SearchExprAnd, Children: []*plugin.SearchExpr{term, tag}}).IsPlainText())
+	assert.False(t, newCond(&plugin.SearchExpr{Type: plugin.SearchExprNot, Children: []*plugin.SearchExpr{term}}).IsPlainText())
+
+	cond := newCond(term)
+	cond.UserID = "1"
+	assert.False(t, cond.IsPlainText())
+	cond = newCond(term)
+	cond.Created = plugin.SearchTimeRange{From: 1}
+	assert.False(t, cond.IsPlainText())
+}
@@ -43,6 +43,7 @@ type SimpleObjectInfo struct {
 	ObjectType            string `json:"object_type"`
 	Title                 string `json:"title"`
 	Content               string `json:"content"`
+	SpaceID               int    `json:"space_id"`
 }
 
 // IsDeleted is deleted
@@ -109,9 +110,11 @@ func (s *SimpleObjectInfo) isObjectRestricted() bool {
 	case constant.QuestionObjectType:
 		return s.QuestionStatus == entity.QuestionStatusDeleted ||
 			s.QuestionStatus == entity.QuestionStatusPending ||
+			s.QuestionStatus == entity.QuestionStatusScheduled ||
 			s.QuestionShow == entity.QuestionHide
 	case constant.AnswerObjectType:
-		return s.AnswerStatus == entity.AnswerStatusDeleted || s.AnswerStatus == entity.AnswerStatusPending
+		return s.AnswerStatus == entity.AnswerStatusDeleted || s.AnswerStatus == entity.AnswerStatusPending ||
+			s.AnswerStatus == entity.AnswerStatusScheduled
 	case constant.CommentObjectType:
 		return s.CommentStatus == entity.CommentStatusDeleted || s.CommentStatus == entity.CommentStatusPending
 	case constant.TagObjectType:
@@ -124,6 +127,7 @@ func (s *SimpleObjectInfo) isObjectRestricted() bool {
 func (s *SimpleObjectInfo) isParentQuestionRestricted() bool {
 	return s.QuestionStatus == entity.QuestionStatusDeleted ||
 		s.QuestionStatus == entity.QuestionStatusPending ||
+		s.QuestionStatus == entity.QuestionStatusScheduled ||
 		s.QuestionShow == entity.QuestionHide
 }
 
@@ -107,11 +107,37 @@ type SiteWriteReq struct {
 
 type SiteWriteResp SiteWriteReq
 
+const (
+	// DefaultCloseVoteThreshold is the default number of votes to close or reopen a question
+	DefaultCloseVoteThreshold = 5
+	// DefaultCloseVoteExpireDays is the default days after which the open votes age away
+	DefaultCloseVoteExpireDays = 4
+	// DefaultBountyMinAmount is the default minimum reputation of a bounty
+	DefaultBountyMinAmount = 50
+	// DefaultBountyMaxAmount is the default maximum reputation of a bounty
+	DefaultBountyMaxAmount = 500
+	// DefaultBountyDays is the default days a bounty stays open
+	DefaultBountyDays = 7
+	// DefaultDraftRetentionDays is the default days a draft is kept since its last update
+	DefaultDraftRetentionDays = 30
+)
+
 // SiteQuestionsReq site questions settings request
 type SiteQuestionsReq struct {
 	MinimumTags    int  `validate:"omitempty,gte=0,lte=5" json:"min_tags"`
 	MinimumContent int  `validate:"omitempty,gte=0,lte=65535" json:"min_content"`
 	RestrictAnswer bool `validate:"omitempty" json:"restrict_answer"`
+	// the number of votes to close or reopen a question automatically
+	CloseVoteThreshold int `validate:"omitempty,gte=1,lte=100" json:"close_vote_threshold"`
+	// the days after which the close and reopen votes age away if the threshold is not reached
+	CloseVoteExpireDays int `validate:"omitempty,gte=1,lte=365" json:"close_vote_expire_days"`
+	// the range of the reputation a user can offer as a bounty
+	BountyMinAmount int `validate:"omitempty,gte=1" json:"bounty_min_amount"`
+	BountyMaxAmount int `validate:"omitempty,gte=1" json:"bounty_max_amount"`
+	// the days a bounty stays open before it is awarded automatically
+	BountyDays int `validate:"omitempty,gte=1,lte=30" json:"bounty_days"`
+	// the days a draft is kept since its last update
+	DraftRetentionDays int `validate:"omitempty,gte=1,lte=365" json:"draft_retention_days"`
 }
 
 // SiteAdvancedReq site advanced settings request
@@ -178,6 +204,11 @@ type SiteSecurityReq struct {
 	LoginRequired          bool   `json:"login_required"`
 	ExternalContentDisplay string `validate:"required,oneof=always_display ask_before_display" json:"external_content_display"`
 	CheckUpdate            bool   `validate:"omitempty,sanitizer" form:"check_update" json:"check_update"`
+	RequireStaffTwoFactor  bool   `json:"require_staff_two_factor"`
+	// SessionIdleTimeout the minutes a session is kept without activity, 0 means no limit
+	SessionIdleTimeout int `validate:"omitempty,min=0,max=10080" json:"session_idle_timeout"`
+	// SessionAbsoluteTimeout the minutes a session is kept since the login, 0 means no limit
+	SessionAbsoluteTimeout int `validate:"omitempty,min=0,max=10080" json:"session_absolute_timeout"`
 }
 
 type SitePoliciesResp SitePoliciesReq
@@ -311,6 +342,26 @@ type SiteMCPResp struct {
 	HTTPHeader string `json:"http_header"`
 }
 
+const (
+	// DefaultSearchRRFK is the default constant k of reciprocal rank fusion
+	DefaultSearchRRFK = 60
+)
+
+// SiteSearchReq search configuration request
+type SiteSearchReq struct {
+	// fuse the keyword and semantic search results when a vector search plugin is enabled
+	HybridEnabled bool `validate:"omitempty" form:"hybrid_enabled" json:"hybrid_enabled"`
+	// the weight of keyword search results in reciprocal rank fusion
+	KeywordWeight float64 `validate:"gte=0,lte=10" form:"keyword_weight" json:"keyword_weight"`
+	// the weight of semantic search results in reciprocal rank fusion
+	SemanticWeight float64 `validate:"gte=0,lte=10" form:"semantic_weight" json:"semantic_weight"`
+	// the constant k of reciprocal rank fusion, the larger k the less difference between the top ranks
+	RRFK int `validate:"gte=1,lte=1000" form:"rrf_k" json:"rrf_k"`
+}
+
+// SiteSearchResp search configuration response
+type SiteSearchResp SiteSearchReq
+
 // SiteGeneralResp site general response
 type SiteGeneralResp SiteGeneralReq
 
@@ -559,6 +610,8 @@ var (
 		constant.RankTagAuditKey:                  {1, 2500, 5000},
 		constant.RankTagEditWithoutReviewKey:      {1, 10000, 20000},
 		constant.RankTagSynonymKey:                {1, 10000, 20000},
+		constant.RankQuestionCloseVoteKey:         {1, 1500, 3000},
+		constant.RankQuestionBountyKey:            {1, 75, 75},
 	}
 )
 
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// AddSpaceReq create a space, the owner is the creator if the owner username is empty
+type AddSpaceReq struct {
+	SlugName      string `validate:"required,gt=0,lte=35" json:"slug_name"`
+	DisplayName   string `validate:"required,gt=0,lte=35" json:"display_name"`
+	Description   string `validate:"omitempty,lte=500" json:"description"`
+	OwnerUsername string `validate:"omitempty,lte=100" json:"owner_username"`
+	UserID        string `json:"-"`
+}
+
+// AddSpaceResp create space response
+type AddSpaceResp struct {
+	ID int `json:"id"`
+}
+
+// UpdateSpaceReq update the name and description of the space
+type UpdateSpaceReq struct {
+	ID          int    `validate:"required" json:"id"`
+	DisplayName string `validate:"required,gt=0,lte=35" json:"display_name"`
+	Description string `validate:"omitempty,lte=500" json:"description"`
+	UserID      string `json:"-"`
+}
+
+// DeleteSpaceReq delete the space, its contents are only visible to the administrators after deleted
+type DeleteSpaceReq struct {
+	ID int `validate:"required" json:"id"`
+}
+
+// GetSpaceReq get the space by id
+type GetSpaceReq struct {
+	ID     int    `validate:"required" form:"id"`
+	UserID string `json:"-"`
+}
+
+// GetSpacePageReq get all spaces by page for the administrator
+type GetSpacePageReq struct {
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1,max=100" form:"page_size"`
+	Query    string `validate:"omitempty,lte=35" form:"query"`
+}
+
+// SpaceResp space response
+type SpaceResp struct {
+	ID          int    `json:"id"`
+	SlugName    string `json:"slug_name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	MemberCount int64  `json:"member_count"`
+	CreatedAt   int64  `json:"created_at"`
+	// the role of the login user in the space, empty if the user is not a member
+	Role string `json:"role"`
+}
+
+// GetSpaceMemberPageReq get the members of the space by page
+type GetSpaceMemberPageReq struct {
+	SpaceID  int    `validate:"required" form:"space_id"`
+	Page     int    `validate:"omitempty,min=1" form:"page"`
+	PageSize int    `validate:"omitempty,min=1,max=100" form:"page_size"`
+	UserID   string `json:"-"`
+}
+
+// SpaceMemberResp space member response
+type SpaceMemberResp struct {
+	User     *UserBasicInfo `json:"user"`
+	Role     string         `json:"role"`
+	JoinedAt int64          `json:"joined_at"`
+}
+
+// AddSpaceMemberReq add the user to the space with the role
+type AddSpaceMemberReq struct {
+	SpaceID  int    `validate:"required" json:"space_id"`
+	Username string `validate:"required,gt=0,lte=100" json:"username"`
+	Role     string `validate:"required,oneof=owner member viewer" json:"role"`
+	UserID   string `json:"-"`
+}
+
+// UpdateSpaceMemberReq change the role of the member
+type UpdateSpaceMemberReq struct {
+	SpaceID      int    `validate:"required" json:"space_id"`
+	MemberUserID string `validate:"required" json:"user_id"`
+	Role         string `validate:"required,oneof=owner member viewer" json:"role"`
+	UserID       string `json:"-"`
+}
+
+// RemoveSpaceMemberReq remove the member from the space, the member can leave the space by itself
+type RemoveSpaceMemberReq struct {
+	SpaceID      int    `validate:"required" json:"space_id"`
+	MemberUserID string `validate:"required" json:"user_id"`
+	UserID       string `json:"-"`
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// SSOOIDCConfig the settings of the OpenID Connect provider, the endpoints are found by the discovery of the issuer
+type SSOOIDCConfig struct {
+	Issuer       string   `validate:"required,url,lte=500" json:"issuer"`
+	ClientID     string   `validate:"required,gt=0,lte=500" json:"client_id"`
+	ClientSecret string   `validate:"omitempty,lte=500" json:"client_secret"`
+	Scopes       []string `validate:"omitempty,dive,gt=0,lte=100" json:"scopes"`
+	// GroupsClaim the claim of the groups of the user, groups by default
+	GroupsClaim string `validate:"omitempty,lte=100" json:"groups_claim"`
+	// ActiveClaim the boolean claim whether the user is active, the user is deactivated when it is false
+	ActiveClaim string `validate:"omitempty,lte=100" json:"active_claim"`
+}
+
+// SSOSAMLConfig the settings of the SAML 2.0 identity provider
+type SSOSAMLConfig struct {
+	IdPEntityID string `validate:"required,gt=0,lte=500" json:"idp_entity_id"`
+	IdPSSOURL   string `validate:"required,url,lte=500" json:"idp_sso_url"`
+	// IdPCertificate the signing certificate in PEM or base64
+	IdPCertificate    string `validate:"required,gt=0,lte=20000" json:"idp_certificate"`
+	EmailAttribute    string `validate:"omitempty,lte=255" json:"email_attribute"`
+	NameAttribute     string `validate:"omitempty,lte=255" json:"name_attribute"`
+	UsernameAttribute string `validate:"omitempty,lte=255" json:"username_attribute"`
+	GroupsAttribute   string `validate:"omitempty,lte=255" json:"groups_attribute"`
+	ActiveAttribute   string `validate:"omitempty,lte=255" json:"active_attribute"`
+}
+
+// SSOGroupRole the role granted to the members of the group
+type SSOGroupRole struct {
+	Group  string `validate:"required,gt=0,lte=255" json:"group"`
+	RoleID int    `validate:"required,gt=0" json:"role_id"`
+}
+
+// AddSSOProviderReq add the single sign-on provider, the settings of its protocol are required
+type AddSSOProviderReq struct {
+	SlugName         string          `validate:"required,gt=0,lte=100" json:"slug_name"`
+	Name             string          `validate:"required,gt=0,lte=100" json:"name"`
+	Protocol         string          `validate:"required,oneof=oidc saml" json:"protocol"`
+	Enabled          bool            `json:"enabled"`
+	OIDC             *SSOOIDCConfig  `json:"oidc"`
+	SAML             *SSOSAMLConfig  `json:"saml"`
+	GroupRoleMapping []*SSOGroupRole `validate:"omitempty,dive" json:"group_role_mapping"`
+}
+
+// AddSSOProviderResp add sso provider response
+type AddSSOProviderResp struct {
+	ID int `json:"id"`
+}
+
+// UpdateSSOProviderReq update the single sign-on provider, the client secret is kept when it is empty
+type UpdateSSOProviderReq struct {
+	ID int `validate:"required" json:"id"`
+	AddSSOProviderReq
+}
+
+// DeleteSSOProviderReq delete the single sign-on provider
+type DeleteSSOProviderReq struct {
+	ID int `validate:"required" json:"id"`
+}
+
+// SSOProviderResp the single sign-on provider, the client secret is never returned.
+// The urls are filled in the settings of the identity provider.
+type SSOProviderResp struct {
+	ID               int             `json:"id"`
+	SlugName         string          `json:"slug_name"`
+	Name             string          `json:"name"`
+	Protocol         string          `json:"protocol"`
+	Enabled          bool            `json:"enabled"`
+	OIDC             *SSOOIDCConfig  `json:"oidc,omitempty"`
+	SAML             *SSOSAMLConfig  `json:"saml,omitempty"`
+	GroupRoleMapping []*SSOGroupRole `json:"group_role_mapping"`
+	LoginURL         string          `json:"login_url"`
+	RedirectURL      string          `json:"redirect_url,omitempty"`
+	MetadataURL      string          `json:"metadata_url,omitempty"`
+	ACSURL           string          `json:"acs_url,omitempty"`
+}
+
+// SSOSAMLResponseReq the response posted by the identity provider
+type SSOSAMLResponseReq struct {
+	SAMLResponse string `validate:"required" form:"SAMLResponse"`
+	RelayState   string `form:"RelayState"`
+}
@@ -29,8 +29,11 @@ import (
 // SearchTagLikeReq get tag list all request
 type SearchTagLikeReq struct {
 	// tag
-	Tag     string `validate:"omitempty" form:"tag"`
+	Tag string `validate:"omitempty" form:"tag"`
+	// space id, the tags of the space are returned together with the public tags
+	SpaceID int    `validate:"omitempty,min=0" form:"space_id"`
 	IsAdmin bool   `json:"-"`
+	UserID  string `json:"-"`
 }
 
 // SearchTagsBySlugName search tags by slug name
@@ -88,6 +91,14 @@ type GetTagResp struct {
 	MainTagSlugName string `json:"main_tag_slug_name"`
 	Recommend       bool   `json:"recommend"`
 	Reserved        bool   `json:"reserved"`
+	// parent tag, nil if the tag has no parent
+	ParentTag *GetTagBasicResp `json:"parent_tag"`
+	// child tags
+	ChildTags []*GetTagBasicResp `json:"child_tags"`
+	// the owners of the tag
+	Owners []*UserBasicInfo `json:"owners"`
+	// whether the login user is the owner of the tag
+	IsOwner bool `json:"is_owner"`
 }
 
 func (tr *GetTagResp) GetExcerpt() {
@@ -141,6 +152,8 @@ func (tr *GetTagPageResp) GetExcerpt() {
 type TagChange struct {
 	ObjectID string     `json:"object_id"` // object_id
 	Tags     []*TagItem `json:"tags"`      // tags name
+	// the space of the object, the new tags are created in this space
+	SpaceID int `json:"-"`
 	// user id
 	UserID string `json:"-"`
 }
@@ -236,6 +249,8 @@ type GetTagWithPageReq struct {
 	DisplayName string `validate:"omitempty,gt=0,lte=35" form:"display_name"`
 	// query condition
 	QueryCond string `validate:"omitempty,oneof=popular name newest" form:"query_cond"`
+	// space id, the public tags are returned by default
+	SpaceID int `validate:"omitempty,min=0" form:"space_id"`
 	// user id
 	UserID string `json:"-"`
 }
@@ -321,3 +336,96 @@ type MergeTagReq struct {
 // MergeTagResp merge tag response
 type MergeTagResp struct {
 }
+
+// UpdateTagParentReq update tag parent request
+type UpdateTagParentReq struct {
+	// tag_id
+	TagID string `validate:"required" json:"tag_id"`
+	// parent tag slug name, empty means the tag has no parent
+	ParentSlugName string `validate:"omitempty,lte=35" json:"parent_slug_name"`
+	// user id
+	UserID string `json:"-"`
+}
+
+func (r *UpdateTagParentReq) Check() (errFields []*validator.FormErrorField, err error) {
+	r.ParentSlugName = strings.ToLower(strings.TrimSpace(r.ParentSlugName))
+	return nil, nil
+}
+
+// GetTagOwnersReq get tag owners request
+type GetTagOwnersReq struct {
+	// tag_id
+	TagID string `validate:"required" form:"tag_id"`
+}
+
+// UpdateTagOwnersReq update tag owners request
+type UpdateTagOwnersReq struct {
+	// tag_id
+	TagID string `validate:"required" json:"tag_id"`
+	// the usernames of the owners, empty means the tag has no owner
+	Usernames []string `validate:"omitempty,dive,required,lte=30" json:"usernames"`
+	// user id
+	UserID string `json:"-"`
+}
+
+// GetTagSettingReq get tag setting request
+type GetTagSettingReq struct {
+	// tag_id
+	TagID string `validate:"required" form:"tag_id"`
+}
+
+// GetTagSettingResp get tag setting response
+type GetTagSettingResp struct {
+	TagID string `json:"tag_id"`
+	// the template of the question content when asking a question with this tag
+	QuestionTemplate string `json:"question_template"`
+	// the question with this tag must also have one of the companion tags
+	CompanionTags []string `json:"companion_tags"`
+}
+
+// UpdateTagSettingReq update tag setting request
+type UpdateTagSettingReq struct {
+	// tag_id
+	TagID            string   `validate:"required" json:"tag_id"`
+	QuestionTemplate string   `validate:"omitempty,lte=65535" json:"question_template"`
+	CompanionTags    []string `validate:"omitempty,max=10,dive,required,lte=35" json:"companion_tags"`
+	// user id
+	UserID string `json:"-"`
+}
+
+func (r *UpdateTagSettingReq) Check() (errFields []*validator.FormErrorField, err error) {
+	for i := range r.CompanionTags {
+		r.CompanionTags[i] = strings.ToLower(strings.TrimSpace(r.CompanionTags[i]))
+	}
+	return nil, nil
+}
+
+// SuggestTagsReq suggest tags request
+type SuggestTagsReq struct {
+	// the draft title of the question
+	Title string `validate:"omitempty,lte=150" json:"title"`
+	// the draft content of the question
+	Content string `validate:"omitempty,lte=65535" json:"content"`
+	// the slug names of the tags already selected, they are not suggested again
+	Tags []string `validate:"omitempty,dive,lte=35" json:"tags"`
+	// the max count of the suggested tags
+	Size int `validate:"omitempty,min=1,max=10" json:"size"`
+}
+
+func (r *SuggestTagsReq) Check() (errFields []*validator.FormErrorField, err error) {
+	if r.Size == 0 {
+		r.Size = 5
+	}
+	for i := range r.Tags {
+		r.Tags[i] = strings.ToLower(r.Tags[i])
+	}
+	return nil, nil
+}
+
+// GetTagSuggestionStatusResp get tag suggestion model status response
+type GetTagSuggestionStatusResp struct {
+	// whether the model is training
+	Training bool `json:"training"`
+	// the count of the questions used to train the model
+	QuestionCount int `json:"question_count"`
+}
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// AddTenantReq add the community hosted on the host, it is initialized with the site info and the administrator
+type AddTenantReq struct {
+	Host          string `validate:"required,gt=0,lte=255" json:"host"`
+	Name          string `validate:"required,gt=0,lte=100" json:"name"`
+	Language      string `validate:"required,gt=0,lte=30" json:"lang"`
+	SiteURL       string `validate:"required,gt=0,lte=512,url" json:"site_url"`
+	ContactEmail  string `validate:"required,email,gt=0,lte=500" json:"contact_email"`
+	AdminName     string `validate:"required,gte=2,lte=30" json:"admin_name"`
+	AdminPassword string `validate:"required,gte=8,lte=32" json:"admin_password"`
+	AdminEmail    string `validate:"required,email,gt=0,lte=500" json:"admin_email"`
+	LoginRequired bool   `json:"login_required"`
+}
+
+// AddTenantResp add tenant response
+type AddTenantResp struct {
+	ID int `json:"id"`
+}
+
+// UpdateTenantReq update the host, name or status of the tenant
+type UpdateTenantReq struct {
+	ID     int    `validate:"required" json:"id"`
+	Host   string `validate:"required,gt=0,lte=255" json:"host"`
+	Name   string `validate:"required,gt=0,lte=100" json:"name"`
+	Status string `validate:"required,oneof=available disabled" json:"status"`
+}
+
+// GetTenantPageReq get the tenants by page
+type GetTenantPageReq struct {
+	Page     int `validate:"omitempty,min=1" form:"page"`
+	PageSize int `validate:"omitempty,min=1,max=100" form:"page_size"`
+}
+
+// TenantResp tenant response
+type TenantResp struct {
+	ID        int    `json:"id"`
+	Host      string `json:"host"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"created_at"`
+}
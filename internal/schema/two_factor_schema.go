@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+import "github.com/apache/answer/pkg/webauthn"
+
+const (
+	TwoFactorMethodPassword     = "password"
+	TwoFactorMethodTOTP         = "totp"
+	TwoFactorMethodRecoveryCode = "recovery_code"
+	TwoFactorMethodWebAuthn     = "webauthn"
+)
+
+// TwoFactorLoginChallenge the sign-in waiting for the second factor, kept in the cache
+type TwoFactorLoginChallenge struct {
+	UserID     string `json:"user_id"`
+	ExternalID string `json:"external_id"`
+	// EnrollRequired the role of the user requires a second factor, which the user has not set up yet
+	EnrollRequired    bool   `json:"enroll_required"`
+	WebAuthnChallenge string `json:"webauthn_challenge"`
+}
+
+// TwoFactorChallengeResp the second factor is required to finish signing in
+type TwoFactorChallengeResp struct {
+	ChallengeToken string `json:"challenge_token"`
+	// Methods the second factors the user can sign in with
+	Methods []string `json:"methods"`
+	// EnrollRequired the user must set up the authenticator app to finish signing in
+	EnrollRequired bool                     `json:"enroll_required"`
+	WebAuthn       *webauthn.RequestOptions `json:"webauthn,omitempty"`
+}
+
+// TwoFactorVerifyReq the second factor: the passcode of the authenticator app,
+// a recovery code or the assertion of the security key
+type TwoFactorVerifyReq struct {
+	Method   string                      `validate:"required,oneof=totp recovery_code webauthn" json:"method"`
+	Code     string                      `validate:"omitempty,lte=32" json:"code"`
+	WebAuthn *webauthn.AssertionResponse `json:"webauthn"`
+}
+
+// TwoFactorLoginReq finish signing in with the second factor
+type TwoFactorLoginReq struct {
+	ChallengeToken string `validate:"required,gt=0,lte=100" json:"challenge_token"`
+	TwoFactorVerifyReq
+}
+
+// GetTwoFactorChallengeReq get the sign-in waiting for the second factor
+type GetTwoFactorChallengeReq struct {
+	ChallengeToken string `validate:"required,gt=0,lte=100" form:"challenge_token"`
+}
+
+// TwoFactorEnrollSetupReq set up the authenticator app while signing in
+type TwoFactorEnrollSetupReq struct {
+	ChallengeToken string `validate:"required,gt=0,lte=100" json:"challenge_token"`
+}
+
+// TwoFactorEnrollLoginReq enable the authenticator app and finish signing in
+type TwoFactorEnrollLoginReq struct {
+	ChallengeToken string `validate:"required,gt=0,lte=100" json:"challenge_token"`
+	Code           string `validate:"required,gt=0,lte=32" json:"code"`
+}
+
+// TwoFactorEnrollLoginResp the user signed in and the recovery codes to be saved by the user
+type TwoFactorEnrollLoginResp struct {
+	*UserLoginResp
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// UserReauthReq confirm the identity before a sensitive action,
+// with the second factor if the user has one and with the password otherwise
+type UserReauthReq struct {
+	Method      string                      `validate:"required,oneof=password totp recovery_code webauthn" json:"method"`
+	Pass        string                      `validate:"omitempty,lte=32" json:"pass"`
+	Code        string                      `validate:"omitempty,lte=32" json:"code"`
+	WebAuthn    *webauthn.AssertionResponse `json:"webauthn"`
+	UserID      string                      `json:"-"`
+	AccessToken string                      `json:"-"`
+}
+
+// VerifyReq the second factor of the request
+func (r *UserReauthReq) VerifyReq() *TwoFactorVerifyReq {
+	return &TwoFactorVerifyReq{Method: r.Method, Code: r.Code, WebAuthn: r.WebAuthn}
+}
+
+// GetTwoFactorResp the second factors of the user
+type GetTwoFactorResp struct {
+	TOTPEnabled            bool                      `json:"totp_enabled"`
+	RecoveryCodesRemaining int                       `json:"recovery_codes_remaining"`
+	WebAuthnCredentials    []*WebAuthnCredentialResp `json:"webauthn_credentials"`
+	// Required the role of the user requires a second factor, so the last one can not be removed
+	Required bool `json:"required"`
+}
+
+// WebAuthnCredentialResp the registered security key
+type WebAuthnCredentialResp struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	CreatedAt  int64  `json:"created_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+}
+
+// TOTPSetupResp the secret to be added to the authenticator app
+type TOTPSetupResp struct {
+	Secret string `json:"secret"`
+	// URL the otpauth url to be shown as a QR code
+	URL string `json:"otpauth_url"`
+}
+
+// EnableTOTPReq enable the authenticator app with its first passcode
+type EnableTOTPReq struct {
+	Code   string `validate:"required,gt=0,lte=32" json:"code"`
+	UserID string `json:"-"`
+}
+
+// RecoveryCodesResp the recovery codes, they are shown only once
+type RecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// FinishWebAuthnRegistrationReq register the security key created by the browser
+type FinishWebAuthnRegistrationReq struct {
+	Name       string                        `validate:"required,gt=0,lte=100" json:"name"`
+	Credential *webauthn.AttestationResponse `validate:"required" json:"credential"`
+	UserID     string                        `json:"-"`
+}
+
+// RemoveWebAuthnCredentialReq remove the security key
+type RemoveWebAuthnCredentialReq struct {
+	ID     int    `validate:"required" json:"id"`
+	UserID string `json:"-"`
+}
+
+// ResetUserTwoFactorReq remove all the second factors of the user who lost them
+type ResetUserTwoFactorReq struct {
+	UserID      string `validate:"required" json:"user_id"`
+	LoginUserID string `json:"-"`
+}
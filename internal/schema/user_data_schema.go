@@ -0,0 +1,167 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+import "encoding/json"
+
+// RequestUserDataExportReq request to export all the personal data of the user
+type RequestUserDataExportReq struct {
+	UserID string `json:"-"`
+}
+
+// RequestUserErasureReq request to delete the account of the user after the cooling-off period
+type RequestUserErasureReq struct {
+	// anonymize or delete
+	Mode string `validate:"required,oneof=anonymize delete" json:"mode"`
+	// the current password, required if the user has set a password
+	Pass   string `validate:"omitempty,lte=32" json:"pass"`
+	UserID string `json:"-"`
+}
+
+// CancelUserErasureReq cancel the pending account deletion of the user
+type CancelUserErasureReq struct {
+	UserID string `json:"-"`
+}
+
+// GetUserDataRequestsReq get the data export and erasure requests of the user
+type GetUserDataRequestsReq struct {
+	UserID string `json:"-"`
+}
+
+// DownloadUserDataExportReq download the data export by the code sent by email
+type DownloadUserDataExportReq struct {
+	Code string `validate:"required,lte=64" form:"code"`
+}
+
+// UserDataRequestResp user data request response
+type UserDataRequestResp struct {
+	ID int `json:"id"`
+	// export or erasure
+	RequestType string `json:"request_type"`
+	// pending, processing, completed, failed, cancelled or expired
+	Status string `json:"status"`
+	// anonymize or delete, only for erasure
+	ErasureMode string `json:"erasure_mode,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+	// the time the request is processed, for erasure it is the end of the cooling-off period
+	ScheduledAt int64 `json:"scheduled_at"`
+	CompletedAt int64 `json:"completed_at"`
+	// the download url of the completed export, it expires at ExpiredAt
+	DownloadURL string `json:"download_url,omitempty"`
+	ExpiredAt   int64  `json:"expired_at,omitempty"`
+}
+
+// UserDataProfile the profile in the data export
+type UserDataProfile struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	DisplayName   string `json:"display_name"`
+	Email         string `json:"email"`
+	Bio           string `json:"bio"`
+	Website       string `json:"website"`
+	Location      string `json:"location"`
+	Avatar        string `json:"avatar"`
+	Language      string `json:"language"`
+	Rank          int    `json:"rank"`
+	IPInfo        string `json:"ip_info"`
+	CreatedAt     int64  `json:"created_at"`
+	LastLoginDate int64  `json:"last_login_date"`
+}
+
+// UserDataQuestion the question in the data export
+type UserDataQuestion struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Content     string `json:"content"`
+	Status      int    `json:"status"`
+	ViewCount   int    `json:"view_count"`
+	VoteCount   int    `json:"vote_count"`
+	AnswerCount int    `json:"answer_count"`
+	CreatedAt   int64  `json:"created_at"`
+	UpdatedAt   int64  `json:"updated_at"`
+}
+
+// UserDataAnswer the answer in the data export
+type UserDataAnswer struct {
+	ID         string `json:"id"`
+	QuestionID string `json:"question_id"`
+	Content    string `json:"content"`
+	Status     int    `json:"status"`
+	Accepted   bool   `json:"accepted"`
+	VoteCount  int    `json:"vote_count"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+// UserDataComment the comment in the data export
+type UserDataComment struct {
+	ID        string `json:"id"`
+	ObjectID  string `json:"object_id"`
+	Content   string `json:"content"`
+	Status    int    `json:"status"`
+	VoteCount int    `json:"vote_count"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// UserDataVote the vote in the data export
+type UserDataVote struct {
+	ObjectID string `json:"object_id"`
+	// question.vote_up, answer.vote_down, comment.vote_up etc.
+	VoteType  string `json:"vote_type"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// UserDataCollection the collected question in the data export
+type UserDataCollection struct {
+	ObjectID  string `json:"object_id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// UserDataNotification the notification in the data export
+type UserDataNotification struct {
+	ObjectID  string          `json:"object_id"`
+	Type      int             `json:"type"`
+	Content   json.RawMessage `json:"content"`
+	IsRead    bool            `json:"is_read"`
+	CreatedAt int64           `json:"created_at"`
+}
+
+// UserDataAIConversation the AI conversation in the data export
+type UserDataAIConversation struct {
+	ConversationID string                          `json:"conversation_id"`
+	Topic          string                          `json:"topic"`
+	CreatedAt      int64                           `json:"created_at"`
+	Records        []*UserDataAIConversationRecord `json:"records"`
+}
+
+// UserDataAIConversationRecord the message of the AI conversation in the data export
+type UserDataAIConversationRecord struct {
+	Role      string `json:"role"`
+	Content   string `json:"content"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// UserDataFile the uploaded file in the data export, the file is in the files directory if it is stored locally
+type UserDataFile struct {
+	FileURL   string `json:"file_url"`
+	Path      string `json:"path,omitempty"`
+	Source    string `json:"source"`
+	CreatedAt int64  `json:"created_at"`
+}
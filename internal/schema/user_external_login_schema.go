@@ -31,6 +31,8 @@ type UserExternalLoginResp struct {
 	// ErrMsg error message, if not empty, means login failed and this message should be displayed.
 	ErrMsg   string `json:"-"`
 	ErrTitle string `json:"-"`
+	// TwoFactor if not nil, the user must pass the second factor to finish signing in
+	TwoFactor *TwoFactorChallengeResp `json:"two_factor,omitempty"`
 }
 
 // ExternalLoginBindingUserSendEmailReq external login binding user request
@@ -44,8 +46,9 @@ type ExternalLoginBindingUserSendEmailReq struct {
 
 // ExternalLoginBindingUserSendEmailResp external login binding user response
 type ExternalLoginBindingUserSendEmailResp struct {
-	EmailExistAndMustBeConfirmed bool   `json:"email_exist_and_must_be_confirmed"`
-	AccessToken                  string `json:"access_token"`
+	EmailExistAndMustBeConfirmed bool                    `json:"email_exist_and_must_be_confirmed"`
+	AccessToken                  string                  `json:"access_token"`
+	TwoFactor                    *TwoFactorChallengeResp `json:"two_factor,omitempty"`
 }
 
 // ExternalLoginBindingUserReq external login binding user request
@@ -78,6 +81,8 @@ type ExternalLoginUserInfoCache struct {
 	MetaInfo string
 	// optional. The bio provided by the third-party login platform
 	Bio string
+	// optional. The role granted by the groups of the identity provider, 0 keeps the role of the user
+	RoleID int
 }
 
 // ExternalLoginOAuthState stores the local OAuth request state.
@@ -85,6 +90,10 @@ type ExternalLoginOAuthState struct {
 	Provider string `json:"provider"`
 	Intent   string `json:"intent"`
 	UserID   string `json:"user_id,omitempty"`
+	// the secrets of the single sign-on request, which are checked by the callback
+	CodeVerifier string `json:"code_verifier,omitempty"`
+	Nonce        string `json:"nonce,omitempty"`
+	RequestID    string `json:"request_id,omitempty"`
 }
 
 // ExternalLoginUnbindingReq external login unbinding user
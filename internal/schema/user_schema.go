@@ -101,6 +101,8 @@ type UserLoginResp struct {
 	VisitToken string `json:"visit_token"`
 	// suspended until timestamp
 	SuspendedUntil int64 `json:"suspended_until"`
+	// second factor required, the user is not signed in until it is verified
+	TwoFactor *TwoFactorChallengeResp `json:"two_factor,omitempty"`
 }
 
 func (r *UserLoginResp) ConvertFromUserEntity(userInfo *entity.User) {
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package schema
+
+// UserSessionResp the signed-in session of the user
+type UserSessionResp struct {
+	// ID identifies the session without revealing its access token
+	ID         string `json:"id"`
+	CreatedAt  int64  `json:"created_at"`
+	LastSeenAt int64  `json:"last_seen_at"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	Browser    string `json:"browser"`
+	OS         string `json:"os"`
+	DeviceType string `json:"device_type"`
+	// Current the session is the one making the request
+	Current bool `json:"current"`
+}
+
+// GetUserSessionsReq get the sessions of the user
+type GetUserSessionsReq struct {
+	UserID      string `json:"-"`
+	AccessToken string `json:"-"`
+}
+
+// RemoveUserSessionReq sign out the session
+type RemoveUserSessionReq struct {
+	ID          string `validate:"required,gt=0,lte=64" json:"id"`
+	UserID      string `json:"-"`
+	AccessToken string `json:"-"`
+}
+
+// RemoveOtherUserSessionsReq sign out all the sessions but the current one
+type RemoveOtherUserSessionsReq struct {
+	UserID      string `json:"-"`
+	AccessToken string `json:"-"`
+}
+
+// AdminGetUserSessionsReq get the sessions of any user
+type AdminGetUserSessionsReq struct {
+	UserID string `validate:"required" form:"user_id"`
+}
+
+// AdminRemoveUserSessionReq sign out the session of any user
+type AdminRemoveUserSessionReq struct {
+	UserID string `validate:"required" json:"user_id"`
+	ID     string `validate:"required,gt=0,lte=64" json:"id"`
+}
+
+// AdminRemoveUserAllSessionsReq sign out all the sessions of any user
+type AdminRemoveUserAllSessionsReq struct {
+	UserID string `validate:"required" json:"user_id"`
+}
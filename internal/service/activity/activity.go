@@ -300,7 +300,10 @@ func (as *ActivityService) ensureTimelineObjectVisible(ctx context.Context, obje
 		}
 	}
 
-	return validateTimelineObjectVisibility(objInfo, parentQuestionInfo, userID, isAdminModerator)
+	if err = validateTimelineObjectVisibility(objInfo, parentQuestionInfo, userID, isAdminModerator); err != nil {
+		return err
+	}
+	return as.objectInfoService.CheckSpaceVisibility(ctx, objInfo, userID)
 }
 
 func validateTimelineObjectVisibility(objInfo, parentQuestionInfo *schema.SimpleObjectInfo,
@@ -337,7 +340,8 @@ func isTimelineObjectRestricted(objInfo *schema.SimpleObjectInfo) bool {
 	case constant.QuestionObjectType:
 		return isTimelineQuestionRestricted(objInfo)
 	case constant.AnswerObjectType:
-		return objInfo.AnswerStatus == entity.AnswerStatusDeleted || objInfo.AnswerStatus == entity.AnswerStatusPending
+		return objInfo.AnswerStatus == entity.AnswerStatusDeleted || objInfo.AnswerStatus == entity.AnswerStatusPending ||
+			objInfo.AnswerStatus == entity.AnswerStatusScheduled
 	case constant.CommentObjectType:
 		return objInfo.CommentStatus == entity.CommentStatusDeleted || objInfo.CommentStatus == entity.CommentStatusPending
 	case constant.TagObjectType:
@@ -350,6 +354,7 @@ func isTimelineObjectRestricted(objInfo *schema.SimpleObjectInfo) bool {
 func isTimelineQuestionRestricted(questionInfo *schema.SimpleObjectInfo) bool {
 	return questionInfo.QuestionStatus == entity.QuestionStatusDeleted ||
 		questionInfo.QuestionStatus == entity.QuestionStatusPending ||
+		questionInfo.QuestionStatus == entity.QuestionStatusScheduled ||
 		questionInfo.QuestionShow == entity.QuestionHide
 }
 
@@ -32,6 +32,9 @@ const (
 	AnswerAccept      = "answer.accept"
 	CommentVoteUp     = "comment.vote_up"
 	EditAccepted      = "edit.accepted"
+
+	QuestionBountyOffered = "question.bounty_offered"
+	AnswerBountyAwarded   = "answer.bounty_awarded"
 )
 
 var (
@@ -72,5 +75,8 @@ var (
 		AnswerAccept:      "action_activity_type.accept",
 		CommentVoteUp:     "action_activity_type.upvote",
 		EditAccepted:      "action_activity_type.edit",
+
+		QuestionBountyOffered: "action_activity_type.bounty_offered",
+		AnswerBountyAwarded:   "action_activity_type.bounty_awarded",
 	}
 )
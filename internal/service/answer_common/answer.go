@@ -114,6 +114,9 @@ func (as *AnswerCommon) ShowFormat(ctx context.Context, data *entity.Answer) *sc
 	info.UserID = data.UserID
 	info.UpdateUserID = data.LastEditUserID
 	info.Status = data.Status
+	if data.Status == entity.AnswerStatusScheduled {
+		info.PublishAt = data.PublishAt.Unix()
+	}
 	info.MemberActions = make([]*schema.PermissionMemberAction, 0)
 	return &info
 }
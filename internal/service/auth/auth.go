@@ -21,6 +21,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/service/apikey"
@@ -29,6 +30,9 @@ import (
 	"github.com/segmentfault/pacman/log"
 )
 
+// sessionActivityInterval the activity of a session is recorded at most once in the interval
+const sessionActivityInterval = time.Minute
+
 // AuthRepo auth repository
 type AuthRepo interface {
 	GetUserCacheInfo(ctx context.Context, accessToken string) (userInfo *entity.UserCacheInfo, err error)
@@ -44,6 +48,12 @@ type AuthRepo interface {
 	RemoveAdminUserCacheInfo(ctx context.Context, accessToken string) (err error)
 	AddUserTokenMapping(ctx context.Context, userID, accessToken string) (err error)
 	RemoveUserTokens(ctx context.Context, userID string, remainToken string)
+	SetUserRecentAuth(ctx context.Context, accessToken string) (err error)
+	GetUserRecentAuth(ctx context.Context, accessToken string) (exist bool, err error)
+	GetUserSession(ctx context.Context, accessToken string) (info *entity.UserSessionInfo, err error)
+	SetUserSession(ctx context.Context, accessToken string, info *entity.UserSessionInfo) (err error)
+	GetUserTokens(ctx context.Context, userID string) (tokens []string, err error)
+	RemoveUserTokenMapping(ctx context.Context, userID string, accessTokens ...string) (err error)
 }
 
 // AuthService kit service
@@ -98,6 +108,15 @@ func (as *AuthService) SetUserCacheInfo(ctx context.Context, userInfo *entity.Us
 	if err != nil {
 		return "", "", err
 	}
+	now := time.Now().Unix()
+	if err := as.authRepo.SetUserSession(ctx, accessToken,
+		&entity.UserSessionInfo{CreatedAt: now, LastSeenAt: now}); err != nil {
+		log.Error(err)
+	}
+	// The user has just signed in, which is a fresh proof of identity.
+	if err := as.authRepo.SetUserRecentAuth(ctx, accessToken); err != nil {
+		log.Error(err)
+	}
 	return accessToken, visitToken, err
 }
 
@@ -142,6 +161,125 @@ func (as *AuthService) RemoveTokensExceptCurrentUser(ctx context.Context, userID
 	as.authRepo.RemoveUserTokens(ctx, userID, accessToken)
 }
 
+// SetUserRecentAuth record that the user of the token has just proved their identity
+func (as *AuthService) SetUserRecentAuth(ctx context.Context, accessToken string) (err error) {
+	return as.authRepo.SetUserRecentAuth(ctx, accessToken)
+}
+
+// CheckUserRecentAuth check whether the user of the token has proved their identity recently,
+// which sensitive actions require besides the session.
+func (as *AuthService) CheckUserRecentAuth(ctx context.Context, accessToken string) (recent bool) {
+	recent, err := as.authRepo.GetUserRecentAuth(ctx, accessToken)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	return recent
+}
+
+// CheckUserSession check the timeouts of the session and record its activity,
+// the session is removed if it has been idle or alive for too long. A zero timeout means no limit.
+// The session is not valid if it can not be checked.
+func (as *AuthService) CheckUserSession(ctx context.Context, accessToken, ip, userAgent string,
+	idleTimeout, absoluteTimeout time.Duration) (valid bool) {
+	info, err := as.authRepo.GetUserSession(ctx, accessToken)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	now := time.Now()
+	if info == nil {
+		// the sessions created before the session info was recorded start from now
+		info = &entity.UserSessionInfo{CreatedAt: now.Unix()}
+	}
+	if sessionExpired(info, now, idleTimeout, absoluteTimeout) {
+		as.RemoveUserSession(ctx, accessToken)
+		return false
+	}
+	// record the activity often enough that an active session never looks idle
+	interval := sessionActivityInterval
+	if idleTimeout > 0 && idleTimeout/2 < interval {
+		interval = idleTimeout / 2
+	}
+	if now.Sub(time.Unix(info.LastSeenAt, 0)) < interval && info.IP == ip && info.UserAgent == userAgent {
+		return true
+	}
+	info.LastSeenAt, info.IP, info.UserAgent = now.Unix(), ip, userAgent
+	if err = as.authRepo.SetUserSession(ctx, accessToken, info); err != nil {
+		log.Error(err)
+	}
+	return true
+}
+
+// GetUserSessions get the live sessions of the user by their tokens, the expired tokens are cleaned up
+func (as *AuthService) GetUserSessions(ctx context.Context, userID string, idleTimeout, absoluteTimeout time.Duration) (
+	sessions map[string]*entity.UserSessionInfo, err error) {
+	now := time.Now()
+	tokens, err := as.authRepo.GetUserTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions = make(map[string]*entity.UserSessionInfo, len(tokens))
+	expired := make([]string, 0)
+	for _, token := range tokens {
+		userInfo, err := as.authRepo.GetUserCacheInfo(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if userInfo == nil {
+			expired = append(expired, token)
+			continue
+		}
+		info, err := as.authRepo.GetUserSession(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			info = &entity.UserSessionInfo{}
+		}
+		if sessionExpired(info, now, idleTimeout, absoluteTimeout) {
+			as.RemoveUserSession(ctx, token)
+			continue
+		}
+		sessions[token] = info
+	}
+	if len(expired) > 0 {
+		if err = as.authRepo.RemoveUserTokenMapping(ctx, userID, expired...); err != nil {
+			log.Error(err)
+		}
+	}
+	return sessions, nil
+}
+
+func sessionExpired(info *entity.UserSessionInfo, now time.Time, idleTimeout, absoluteTimeout time.Duration) bool {
+	if absoluteTimeout > 0 && info.CreatedAt > 0 && now.Sub(time.Unix(info.CreatedAt, 0)) > absoluteTimeout {
+		return true
+	}
+	return idleTimeout > 0 && info.LastSeenAt > 0 && now.Sub(time.Unix(info.LastSeenAt, 0)) > idleTimeout
+}
+
+// RemoveUserSession log out the session of the token
+func (as *AuthService) RemoveUserSession(ctx context.Context, accessToken string) {
+	userInfo, err := as.authRepo.GetUserCacheInfo(ctx, accessToken)
+	if err != nil {
+		log.Error(err)
+	}
+	if userInfo != nil {
+		if err = as.RemoveUserVisitCacheInfo(ctx, userInfo.VisitToken); err != nil {
+			log.Error(err)
+		}
+		if err = as.authRepo.RemoveUserTokenMapping(ctx, userInfo.UserID, accessToken); err != nil {
+			log.Error(err)
+		}
+	}
+	if err = as.authRepo.RemoveUserCacheInfo(ctx, accessToken); err != nil {
+		log.Error(err)
+	}
+	if err = as.authRepo.RemoveAdminUserCacheInfo(ctx, accessToken); err != nil {
+		log.Error(err)
+	}
+}
+
 // Admin
 
 func (as *AuthService) GetAdminUserCacheInfo(ctx context.Context, accessToken string) (userInfo *entity.UserCacheInfo, err error) {
@@ -189,18 +327,25 @@ func (as *AuthService) RemoveAdminUserCacheInfo(ctx context.Context, accessToken
 	return as.authRepo.RemoveAdminUserCacheInfo(ctx, accessToken)
 }
 func (as *AuthService) AuthAPIKey(ctx context.Context, read bool, apiKey string) (pass bool, err error) {
+	_, pass, err = as.AuthAPIKeyOwner(ctx, read, apiKey)
+	return pass, err
+}
+
+// AuthAPIKeyOwner authenticate the API key, and return the id of the administrator who created it
+func (as *AuthService) AuthAPIKeyOwner(ctx context.Context, read bool, apiKey string) (
+	ownerID string, pass bool, err error) {
 	apiKeyInfo, exist, err := as.apiKeyRepo.GetAPIKey(ctx, apiKey)
 	if err != nil {
-		return false, err
+		return "", false, err
 	}
 	if !exist {
-		return false, nil
+		return "", false, nil
 	}
 	// If the request is not read-only, check if the API key has write permissions
 	if !read && apiKeyInfo.Scope == "read-only" {
 		log.Warnf("API key %s does not have write permissions", apiKeyInfo.AccessKey)
-		return false, nil
+		return "", false, nil
 	}
 	log.Infof("API key %s is valid, scope: %s", apiKeyInfo.AccessKey, apiKeyInfo.Scope)
-	return true, nil
+	return apiKeyInfo.UserID, true, nil
 }
@@ -0,0 +1,368 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package bounty
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/activity_type"
+	answercommon "github.com/apache/answer/internal/service/answer_common"
+	"github.com/apache/answer/internal/service/config"
+	"github.com/apache/answer/internal/service/noticequeue"
+	questioncommon "github.com/apache/answer/internal/service/question_common"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/space_common"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/pkg/converter"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/apache/answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// expireBatchSize the number of the expired bounties handled in a batch
+	expireBatchSize = 100
+	// autoAwardMinVotes the minimum votes of an answer to be awarded automatically
+	autoAwardMinVotes = 2
+)
+
+type BountyRepo interface {
+	AddBounty(ctx context.Context, bountyInfo *entity.QuestionBounty, activityType int) (rankEnough bool, err error)
+	AwardBounty(ctx context.Context, award *schema.BountyAwardInfo, activityType int) (awarded bool, err error)
+	ExpireBounty(ctx context.Context, bountyInfo *entity.QuestionBounty) (expired bool, err error)
+	GetActiveBounty(ctx context.Context, questionID string) (bountyInfo *entity.QuestionBounty, exist bool, err error)
+	GetBountyList(ctx context.Context, questionID string) (list []*entity.QuestionBounty, err error)
+	GetExpiredActiveBounties(ctx context.Context, before time.Time, afterID int64, limit int) (list []*entity.QuestionBounty, err error)
+}
+
+// BountyService the bounties offered on questions
+type BountyService struct {
+	bountyRepo               BountyRepo
+	questionRepo             questioncommon.QuestionRepo
+	answerRepo               answercommon.AnswerRepo
+	userCommon               *usercommon.UserCommon
+	configService            *config.ConfigService
+	siteInfoCommonService    siteinfo_common.SiteInfoCommonService
+	notificationQueueService noticequeue.Service
+	spaceCommon              *space_common.SpaceCommon
+}
+
+// NewBountyService new bounty service
+func NewBountyService(
+	bountyRepo BountyRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	userCommon *usercommon.UserCommon,
+	configService *config.ConfigService,
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+	notificationQueueService noticequeue.Service,
+	spaceCommon *space_common.SpaceCommon,
+) *BountyService {
+	return &BountyService{
+		bountyRepo:               bountyRepo,
+		questionRepo:             questionRepo,
+		answerRepo:               answerRepo,
+		userCommon:               userCommon,
+		configService:            configService,
+		siteInfoCommonService:    siteInfoCommonService,
+		notificationQueueService: notificationQueueService,
+		spaceCommon:              spaceCommon,
+	}
+}
+
+// AddBounty offer the reputation of the user as a bounty on the open question
+func (bs *BountyService) AddBounty(ctx context.Context, req *schema.AddBountyReq) (resp *schema.BountyResp, err error) {
+	// the reputation is managed by the rank agent plugin, it cannot be offered here
+	if plugin.RankAgentEnabled() {
+		return nil, errors.BadRequest(reason.ErrFeatureDisabled)
+	}
+	questionInfo, exist, err := bs.questionRepo.GetQuestion(ctx, req.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	// the question the user cannot read is not found, so that its existence is not revealed
+	if !exist || questionInfo.Status == entity.QuestionStatusDeleted ||
+		questionInfo.Status == entity.QuestionStatusPending ||
+		questionInfo.Status == entity.QuestionStatusScheduled ||
+		questionInfo.Show == entity.QuestionHide {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
+	canView, err := bs.spaceCommon.CanViewSpace(ctx, questionInfo.SpaceID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
+	if questionInfo.Status != entity.QuestionStatusAvailable {
+		return nil, errors.BadRequest(reason.QuestionAlreadyClosed)
+	}
+	_, exist, err = bs.bountyRepo.GetActiveBounty(ctx, questionInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, errors.BadRequest(reason.BountyAlreadyOpen)
+	}
+
+	siteQuestion, err := bs.siteInfoCommonService.GetSiteQuestion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount < siteQuestion.BountyMinAmount || req.Amount > siteQuestion.BountyMaxAmount {
+		return nil, errors.BadRequest(reason.BountyAmountInvalid)
+	}
+	activityType, err := bs.configService.GetIDByKey(ctx, activity_type.QuestionBountyOffered)
+	if err != nil {
+		return nil, err
+	}
+
+	bountyInfo := &entity.QuestionBounty{
+		QuestionID: questionInfo.ID,
+		UserID:     req.UserID,
+		Amount:     req.Amount,
+		Status:     entity.QuestionBountyStatusActive,
+		ExpiredAt:  time.Now().AddDate(0, 0, siteQuestion.BountyDays),
+	}
+	rankEnough, err := bs.bountyRepo.AddBounty(ctx, bountyInfo, activityType)
+	if err != nil {
+		return nil, err
+	}
+	if !rankEnough {
+		return nil, errors.BadRequest(reason.BountyRankNotEnough)
+	}
+	return bs.formatBounty(ctx, bountyInfo, nil, req.UserID), nil
+}
+
+// AwardBounty award the open bounty to an answer of the question, only the user who offered it can award it
+func (bs *BountyService) AwardBounty(ctx context.Context, req *schema.AwardBountyReq) (err error) {
+	bountyInfo, exist, err := bs.bountyRepo.GetActiveBounty(ctx, req.QuestionID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.BountyNotFound)
+	}
+	if bountyInfo.UserID != req.UserID {
+		return errors.Forbidden(reason.ForbiddenError)
+	}
+	answerInfo, exist, err := bs.answerRepo.GetByID(ctx, req.AnswerID)
+	if err != nil {
+		return err
+	}
+	if !exist || answerInfo.QuestionID != bountyInfo.QuestionID ||
+		answerInfo.Status != entity.AnswerStatusAvailable || answerInfo.UserID == bountyInfo.UserID {
+		return errors.BadRequest(reason.BountyAnswerNotEligible)
+	}
+	return bs.awardBounty(ctx, bountyInfo, answerInfo, bountyInfo.Amount)
+}
+
+// GetBountyList get the bounties of the question, the latest first
+func (bs *BountyService) GetBountyList(ctx context.Context, req *schema.GetBountyReq) (
+	resp []*schema.BountyResp, err error) {
+	list, err := bs.bountyRepo.GetBountyList(ctx, req.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(list)*2)
+	for _, item := range list {
+		userIDs = append(userIDs, item.UserID, item.AwardedUserID)
+	}
+	userInfoMapping, err := bs.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.BountyResp, 0, len(list))
+	for _, item := range list {
+		resp = append(resp, bs.formatBounty(ctx, item, userInfoMapping, req.UserID))
+	}
+	return resp, nil
+}
+
+// ExpireBountyCron close the bounties that are out of time, the bounty is awarded automatically
+// if there is an eligible answer.
+func (bs *BountyService) ExpireBountyCron(ctx context.Context) {
+	// the bounties are walked by id, so the one failed to close does not block the others
+	var lastID int64
+	now := time.Now()
+	for {
+		list, err := bs.bountyRepo.GetExpiredActiveBounties(ctx, now, lastID, expireBatchSize)
+		if err != nil {
+			log.Errorf("get expired bounties failed: %v", err)
+			return
+		}
+		for _, bountyInfo := range list {
+			lastID = converter.StringToInt64(bountyInfo.ID)
+			if err = bs.closeExpiredBounty(ctx, bountyInfo); err != nil {
+				log.Errorf("close expired bounty %s failed: %v", bountyInfo.ID, err)
+			}
+		}
+		if len(list) < expireBatchSize {
+			return
+		}
+	}
+}
+
+func (bs *BountyService) closeExpiredBounty(ctx context.Context, bountyInfo *entity.QuestionBounty) (err error) {
+	questionInfo, exist, err := bs.questionRepo.GetQuestion(ctx, bountyInfo.QuestionID)
+	if err != nil {
+		return err
+	}
+	if exist && questionInfo.Status != entity.QuestionStatusDeleted {
+		answers, err := bs.answerRepo.GetAnswerList(ctx, &entity.Answer{
+			QuestionID: bountyInfo.QuestionID,
+			Status:     entity.AnswerStatusAvailable,
+		})
+		if err != nil {
+			return err
+		}
+		answerInfo, amount := chooseBountyAnswer(bountyInfo, questionInfo.UserID, answers)
+		if answerInfo != nil {
+			answerInfo.ID = uid.DeShortID(answerInfo.ID)
+			return bs.awardBounty(ctx, bountyInfo, answerInfo, amount)
+		}
+	}
+
+	expired, err := bs.bountyRepo.ExpireBounty(ctx, bountyInfo)
+	if err != nil || !expired {
+		return err
+	}
+	bs.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+		TriggerUserID:       bountyInfo.UserID,
+		ReceiverUserID:      bountyInfo.UserID,
+		Type:                schema.NotificationTypeInbox,
+		ObjectID:            bountyInfo.QuestionID,
+		ObjectType:          constant.QuestionObjectType,
+		NotificationAction:  constant.NotificationYourBountyExpired,
+		NoNeedPushAllFollow: true,
+	})
+	return nil
+}
+
+func (bs *BountyService) awardBounty(ctx context.Context, bountyInfo *entity.QuestionBounty,
+	answerInfo *entity.Answer, amount int) (err error) {
+	activityType, err := bs.configService.GetIDByKey(ctx, activity_type.AnswerBountyAwarded)
+	if err != nil {
+		return err
+	}
+	awarded, err := bs.bountyRepo.AwardBounty(ctx, &schema.BountyAwardInfo{
+		BountyID:      bountyInfo.ID,
+		QuestionID:    bountyInfo.QuestionID,
+		AnswerID:      answerInfo.ID,
+		OfferUserID:   bountyInfo.UserID,
+		AwardedUserID: answerInfo.UserID,
+		AwardedAmount: amount,
+	}, activityType)
+	if err != nil {
+		return err
+	}
+	if !awarded {
+		return errors.BadRequest(reason.BountyNotFound)
+	}
+
+	bs.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+		TriggerUserID:       bountyInfo.UserID,
+		ReceiverUserID:      answerInfo.UserID,
+		Type:                schema.NotificationTypeInbox,
+		ObjectID:            answerInfo.ID,
+		ObjectType:          constant.AnswerObjectType,
+		NotificationAction:  constant.NotificationBountyAwarded,
+		NoNeedPushAllFollow: true,
+	})
+	bs.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+		TriggerUserID:  bountyInfo.UserID,
+		ReceiverUserID: answerInfo.UserID,
+		Type:           schema.NotificationTypeAchievement,
+		ObjectID:       answerInfo.ID,
+		ObjectType:     constant.AnswerObjectType,
+	})
+	return nil
+}
+
+func (bs *BountyService) formatBounty(ctx context.Context, bountyInfo *entity.QuestionBounty,
+	userInfoMapping map[string]*schema.UserBasicInfo, loginUserID string) (resp *schema.BountyResp) {
+	resp = &schema.BountyResp{
+		ID:            bountyInfo.ID,
+		QuestionID:    bountyInfo.QuestionID,
+		Amount:        bountyInfo.Amount,
+		CreatedAt:     bountyInfo.CreatedAt.Unix(),
+		ExpiredAt:     bountyInfo.ExpiredAt.Unix(),
+		UserInfo:      userInfoMapping[bountyInfo.UserID],
+		AwardedAmount: bountyInfo.AwardedAmount,
+	}
+	switch bountyInfo.Status {
+	case entity.QuestionBountyStatusActive:
+		resp.Status = schema.BountyStatusActive
+		resp.CanAward = bountyInfo.UserID == loginUserID
+	case entity.QuestionBountyStatusAwarded:
+		resp.Status = schema.BountyStatusAwarded
+		resp.AnswerID = bountyInfo.AnswerID
+		resp.AwardedAt = bountyInfo.AwardedAt.Unix()
+		resp.AwardedUser = userInfoMapping[bountyInfo.AwardedUserID]
+	default:
+		resp.Status = schema.BountyStatusExpired
+	}
+	if handler.GetEnableShortID(ctx) {
+		resp.QuestionID = uid.EnShortID(resp.QuestionID)
+		if len(resp.AnswerID) > 0 {
+			resp.AnswerID = uid.EnShortID(resp.AnswerID)
+		}
+	}
+	return resp
+}
+
+// chooseBountyAnswer choose the answer to award the expired bounty to, only the answers posted
+// during the bounty by others are eligible. The accepted answer gets the whole bounty if the author
+// of the question offered it, otherwise the most voted answer gets half of it.
+func chooseBountyAnswer(bountyInfo *entity.QuestionBounty, questionUserID string, answers []*entity.Answer) (
+	answerInfo *entity.Answer, amount int) {
+	for _, answer := range answers {
+		if !isBountyEligible(bountyInfo, answer) {
+			continue
+		}
+		if answer.Accepted == schema.AnswerAcceptedEnable && bountyInfo.UserID == questionUserID {
+			return answer, bountyInfo.Amount
+		}
+		if answer.VoteCount < autoAwardMinVotes {
+			continue
+		}
+		if answerInfo == nil || answer.VoteCount > answerInfo.VoteCount ||
+			(answer.VoteCount == answerInfo.VoteCount && answer.CreatedAt.Before(answerInfo.CreatedAt)) {
+			answerInfo = answer
+		}
+	}
+	if answerInfo == nil {
+		return nil, 0
+	}
+	return answerInfo, bountyInfo.Amount / 2
+}
+
+func isBountyEligible(bountyInfo *entity.QuestionBounty, answer *entity.Answer) bool {
+	return answer.Status == entity.AnswerStatusAvailable &&
+		answer.UserID != bountyInfo.UserID &&
+		!answer.CreatedAt.Before(bountyInfo.CreatedAt)
+}
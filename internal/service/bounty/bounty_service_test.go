@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package bounty
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChooseBountyAnswer(t *testing.T) {
+	start := time.Now()
+	bountyInfo := &entity.QuestionBounty{UserID: "1", Amount: 100, CreatedAt: start}
+	answers := []*entity.Answer{
+		// posted before the bounty
+		{ID: "10", UserID: "2", VoteCount: 9, Status: entity.AnswerStatusAvailable, CreatedAt: start.Add(-time.Hour)},
+		// posted by the user who offered the bounty
+		{ID: "11", UserID: "1", VoteCount: 8, Status: entity.AnswerStatusAvailable, CreatedAt: start.Add(time.Hour)},
+		{ID: "12", UserID: "3", VoteCount: 3, Status: entity.AnswerStatusAvailable, CreatedAt: start.Add(2 * time.Hour)},
+		{ID: "13", UserID: "4", VoteCount: 3, Status: entity.AnswerStatusAvailable, CreatedAt: start.Add(time.Hour)},
+		{ID: "14", UserID: "5", VoteCount: 1, Status: entity.AnswerStatusAvailable, CreatedAt: start.Add(time.Hour),
+			Accepted: schema.AnswerAcceptedEnable},
+	}
+
+	// the accepted answer gets the whole bounty offered by the author of the question
+	answerInfo, amount := chooseBountyAnswer(bountyInfo, "1", answers)
+	assert.Equal(t, "14", answerInfo.ID)
+	assert.Equal(t, 100, amount)
+
+	// otherwise the most voted answer gets half, the earlier one wins a tie
+	answerInfo, amount = chooseBountyAnswer(bountyInfo, "9", answers)
+	assert.Equal(t, "13", answerInfo.ID)
+	assert.Equal(t, 50, amount)
+
+	answerInfo, amount = chooseBountyAnswer(bountyInfo, "9", answers[:2])
+	assert.Nil(t, answerInfo)
+	assert.Equal(t, 0, amount)
+}
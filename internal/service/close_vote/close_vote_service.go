@@ -0,0 +1,552 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package close_vote
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/activityqueue"
+	"github.com/apache/answer/internal/service/config"
+	"github.com/apache/answer/internal/service/content"
+	questioncommon "github.com/apache/answer/internal/service/question_common"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/pkg/htmltext"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// questionCloseReasonsKey the config key of the reasons to close a question
+const questionCloseReasonsKey = "question.close.reasons"
+
+type CloseVoteRepo interface {
+	AddCloseVote(ctx context.Context, vote *entity.QuestionCloseVote) (err error)
+	RemoveCloseVote(ctx context.Context, questionID, userID string) (err error)
+	GetActiveCloseVote(ctx context.Context, questionID, userID string) (vote *entity.QuestionCloseVote, exist bool, err error)
+	GetActiveCloseVoteList(ctx context.Context, questionID string, voteType int) (list []*entity.QuestionCloseVote, err error)
+	CompleteCloseVotes(ctx context.Context, questionID string, voteType int) (err error)
+	ExpireCloseVotes(ctx context.Context, before time.Time) (questionIDs []string, err error)
+	RecountCloseVotes(ctx context.Context, questionID string) (err error)
+	GetPendingCloseVoteQuestionPage(ctx context.Context, page, pageSize int) (questionIDs []string, total int64, err error)
+	CountPendingCloseVoteQuestions(ctx context.Context) (count int64, err error)
+}
+
+// CloseVoteService the community votes to close and reopen questions
+type CloseVoteService struct {
+	closeVoteRepo         CloseVoteRepo
+	questionRepo          questioncommon.QuestionRepo
+	questionCommon        *questioncommon.QuestionCommon
+	questionService       *content.QuestionService
+	configService         *config.ConfigService
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService
+	activityQueueService  activityqueue.Service
+}
+
+// NewCloseVoteService new close vote service
+func NewCloseVoteService(
+	closeVoteRepo CloseVoteRepo,
+	questionRepo questioncommon.QuestionRepo,
+	questionCommon *questioncommon.QuestionCommon,
+	questionService *content.QuestionService,
+	configService *config.ConfigService,
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+	activityQueueService activityqueue.Service,
+) *CloseVoteService {
+	return &CloseVoteService{
+		closeVoteRepo:         closeVoteRepo,
+		questionRepo:          questionRepo,
+		questionCommon:        questionCommon,
+		questionService:       questionService,
+		configService:         configService,
+		siteInfoCommonService: siteInfoCommonService,
+		activityQueueService:  activityQueueService,
+	}
+}
+
+// AddDuplicateVote vote to close the question as a duplicate
+func (cs *CloseVoteService) AddDuplicateVote(ctx context.Context, req *schema.AddDuplicateVoteReq) (
+	resp *schema.GetCloseVoteResp, err error) {
+	closeType, err := cs.configService.GetIDByKey(ctx, constant.ReasonADuplicate)
+	if err != nil {
+		return nil, err
+	}
+	return cs.AddCloseVote(ctx, &schema.AddCloseVoteReq{
+		QuestionID:          req.QuestionID,
+		CloseType:           closeType,
+		DuplicateQuestionID: req.DuplicateQuestionID,
+		Binding:             req.Binding,
+		UserID:              req.UserID,
+	})
+}
+
+// AddCloseVote vote to close the question, the question is closed with the most voted reason
+// when the votes reach the threshold or the vote is binding.
+func (cs *CloseVoteService) AddCloseVote(ctx context.Context, req *schema.AddCloseVoteReq) (
+	resp *schema.GetCloseVoteResp, err error) {
+	questionInfo, err := cs.getVotableQuestion(ctx, req.QuestionID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if questionInfo.Status == entity.QuestionStatusClosed {
+		return nil, errors.BadRequest(reason.QuestionAlreadyClosed)
+	}
+	closeReason, err := cs.getCloseReason(ctx, req.CloseType)
+	if err != nil {
+		return nil, err
+	}
+	vote := &entity.QuestionCloseVote{
+		QuestionID: req.QuestionID,
+		UserID:     req.UserID,
+		VoteType:   entity.QuestionCloseVoteTypeClose,
+		CloseType:  req.CloseType,
+		CloseMsg:   req.CloseMsg,
+		Status:     entity.QuestionCloseVoteStatusActive,
+	}
+	if closeReason.Key == constant.ReasonADuplicate {
+		vote.DuplicateQuestionID, err = cs.questionCommon.GetCanonicalQuestionID(ctx, req.QuestionID, req.DuplicateQuestionID)
+		if err != nil {
+			return nil, err
+		}
+		if len(vote.DuplicateQuestionID) == 0 {
+			return nil, errors.BadRequest(reason.QuestionDuplicateInvalid)
+		}
+	}
+	// the reopen votes are left from the last time the question was closed
+	if err = cs.closeVoteRepo.CompleteCloseVotes(ctx, req.QuestionID, entity.QuestionCloseVoteTypeReopen); err != nil {
+		return nil, err
+	}
+	if err = cs.addVote(ctx, vote, constant.ActQuestionCloseVoted); err != nil {
+		return nil, err
+	}
+
+	votes, err := cs.closeVoteRepo.GetActiveCloseVoteList(ctx, req.QuestionID, entity.QuestionCloseVoteTypeClose)
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := cs.getThreshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Binding || len(votes) >= threshold {
+		// the binding vote closes the question with its own reason
+		closeReq := &schema.CloseQuestionReq{ID: req.QuestionID, UserID: req.UserID}
+		if req.Binding {
+			closeReq.CloseType, closeReq.CloseMsg, closeReq.DuplicateQuestionID = vote.CloseType, vote.CloseMsg, vote.DuplicateQuestionID
+		} else {
+			closeReq.CloseType, closeReq.CloseMsg, closeReq.DuplicateQuestionID = decideCloseReason(votes)
+		}
+		if err = cs.questionService.CloseQuestion(ctx, closeReq); err != nil {
+			return nil, err
+		}
+		if err = cs.completeVotes(ctx, req.QuestionID, entity.QuestionCloseVoteTypeClose); err != nil {
+			return nil, err
+		}
+	}
+	return cs.GetCloseVotes(ctx, &schema.GetCloseVoteReq{QuestionID: req.QuestionID, UserID: req.UserID})
+}
+
+// AddReopenVote vote to reopen the closed question, the question is reopened
+// when the votes reach the threshold or the vote is binding.
+func (cs *CloseVoteService) AddReopenVote(ctx context.Context, req *schema.AddReopenVoteReq) (
+	resp *schema.GetCloseVoteResp, err error) {
+	questionInfo, err := cs.getVotableQuestion(ctx, req.QuestionID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if questionInfo.Status != entity.QuestionStatusClosed {
+		return nil, errors.BadRequest(reason.QuestionNotClosed)
+	}
+	// the close votes are left from the last time the question was open
+	if err = cs.closeVoteRepo.CompleteCloseVotes(ctx, req.QuestionID, entity.QuestionCloseVoteTypeClose); err != nil {
+		return nil, err
+	}
+	err = cs.addVote(ctx, &entity.QuestionCloseVote{
+		QuestionID: req.QuestionID,
+		UserID:     req.UserID,
+		VoteType:   entity.QuestionCloseVoteTypeReopen,
+		Status:     entity.QuestionCloseVoteStatusActive,
+	}, constant.ActQuestionReopenVoted)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := cs.closeVoteRepo.GetActiveCloseVoteList(ctx, req.QuestionID, entity.QuestionCloseVoteTypeReopen)
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := cs.getThreshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Binding || len(votes) >= threshold {
+		err = cs.questionService.ReopenQuestion(ctx, &schema.ReopenQuestionReq{QuestionID: req.QuestionID, UserID: req.UserID})
+		if err != nil {
+			return nil, err
+		}
+		if err = cs.completeVotes(ctx, req.QuestionID, entity.QuestionCloseVoteTypeReopen); err != nil {
+			return nil, err
+		}
+	}
+	return cs.GetCloseVotes(ctx, &schema.GetCloseVoteReq{QuestionID: req.QuestionID, UserID: req.UserID})
+}
+
+// RemoveCloseVote retract the active close or reopen vote of the user
+func (cs *CloseVoteService) RemoveCloseVote(ctx context.Context, req *schema.RemoveCloseVoteReq) (err error) {
+	if err = cs.closeVoteRepo.RemoveCloseVote(ctx, req.QuestionID, req.UserID); err != nil {
+		return err
+	}
+	return cs.closeVoteRepo.RecountCloseVotes(ctx, req.QuestionID)
+}
+
+// GetCloseVotes get the close votes of the open question or the reopen votes of the closed question
+func (cs *CloseVoteService) GetCloseVotes(ctx context.Context, req *schema.GetCloseVoteReq) (
+	resp *schema.GetCloseVoteResp, err error) {
+	questionInfo, exist, err := cs.questionRepo.GetQuestion(ctx, req.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
+	threshold, err := cs.getThreshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cs.formatCloseVotes(ctx, questionInfo, threshold, req.UserID)
+}
+
+// GetCloseVoteQuestionPage get the questions with pending votes for the review queue
+func (cs *CloseVoteService) GetCloseVoteQuestionPage(ctx context.Context, req *schema.GetCloseVoteQuestionPageReq) (
+	pageModel *pager.PageModel, err error) {
+	questionIDs, total, err := cs.closeVoteRepo.GetPendingCloseVoteQuestionPage(ctx, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := cs.questionRepo.FindByID(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+	questionMapping := make(map[string]*entity.Question, len(questions))
+	for _, question := range questions {
+		questionMapping[question.ID] = question
+	}
+	threshold, err := cs.getThreshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*schema.CloseVoteQuestionResp, 0, len(questionIDs))
+	for _, questionID := range questionIDs {
+		question, ok := questionMapping[questionID]
+		if !ok || question.Status == entity.QuestionStatusDeleted {
+			continue
+		}
+		votes, err := cs.formatCloseVotes(ctx, question, threshold, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		item := &schema.CloseVoteQuestionResp{
+			ID:              question.ID,
+			Title:           question.Title,
+			UrlTitle:        htmltext.UrlTitle(question.Title),
+			Status:          question.Status,
+			CloseVoteCount:  question.CloseVoteCount,
+			ReopenVoteCount: question.ReopenVoteCount,
+			Votes:           votes,
+		}
+		if handler.GetEnableShortID(ctx) {
+			item.ID = uid.EnShortID(item.ID)
+		}
+		list = append(list, item)
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// GetReviewingType get the amount of the questions with pending votes in the review queue
+func (cs *CloseVoteService) GetReviewingType(ctx context.Context) (resp *schema.GetReviewingTypeResp, err error) {
+	count, err := cs.closeVoteRepo.CountPendingCloseVoteQuestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.GetReviewingTypeResp{
+		Name:       string(constant.CloseVote),
+		Label:      translator.Tr(handler.GetLangByCtx(ctx), constant.ReviewCloseVoteLabel),
+		TodoAmount: count,
+	}, nil
+}
+
+// ExpireCloseVotesCron age away the close and reopen votes that have not reached the threshold in time
+func (cs *CloseVoteService) ExpireCloseVotesCron(ctx context.Context) {
+	siteQuestion, err := cs.siteInfoCommonService.GetSiteQuestion(ctx)
+	if err != nil {
+		log.Errorf("get site question config failed: %v", err)
+		return
+	}
+	before := time.Now().AddDate(0, 0, -siteQuestion.CloseVoteExpireDays)
+	questionIDs, err := cs.closeVoteRepo.ExpireCloseVotes(ctx, before)
+	if err != nil {
+		log.Errorf("expire close votes failed: %v", err)
+		return
+	}
+	for _, questionID := range questionIDs {
+		if err = cs.closeVoteRepo.RecountCloseVotes(ctx, questionID); err != nil {
+			log.Errorf("recount close votes of question %s failed: %v", questionID, err)
+		}
+	}
+	if len(questionIDs) > 0 {
+		log.Infof("expired the close votes of %d questions", len(questionIDs))
+	}
+}
+
+func (cs *CloseVoteService) getVotableQuestion(ctx context.Context, questionID, userID string) (
+	questionInfo *entity.Question, err error) {
+	questionInfo, exist, err := cs.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || questionInfo.Status == entity.QuestionStatusDeleted {
+		return nil, errors.BadRequest(reason.QuestionNotFound)
+	}
+	_, voted, err := cs.closeVoteRepo.GetActiveCloseVote(ctx, questionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if voted {
+		return nil, errors.BadRequest(reason.QuestionAlreadyVotedToClose)
+	}
+	return questionInfo, nil
+}
+
+// getCloseReason check the close type is one of the reasons to close a question
+func (cs *CloseVoteService) getCloseReason(ctx context.Context, closeType int) (cfg *entity.Config, err error) {
+	cfg, err = cs.configService.GetConfigByID(ctx, closeType)
+	if err != nil || cfg == nil {
+		return nil, errors.BadRequest(reason.ReportNotFound)
+	}
+	reasonKeys, err := cs.configService.GetArrayStringValue(ctx, questionCloseReasonsKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range reasonKeys {
+		if key == cfg.Key {
+			return cfg, nil
+		}
+	}
+	return nil, errors.BadRequest(reason.ReportNotFound)
+}
+
+func (cs *CloseVoteService) addVote(ctx context.Context, vote *entity.QuestionCloseVote,
+	activityTypeKey constant.ActivityTypeKey) (err error) {
+	if err = cs.closeVoteRepo.AddCloseVote(ctx, vote); err != nil {
+		return err
+	}
+	if err = cs.closeVoteRepo.RecountCloseVotes(ctx, vote.QuestionID); err != nil {
+		return err
+	}
+	cs.activityQueueService.Send(ctx, &schema.ActivityMsg{
+		UserID:           vote.UserID,
+		ObjectID:         vote.QuestionID,
+		OriginalObjectID: vote.QuestionID,
+		ActivityTypeKey:  activityTypeKey,
+	})
+	return nil
+}
+
+func (cs *CloseVoteService) completeVotes(ctx context.Context, questionID string, voteType int) (err error) {
+	if err = cs.closeVoteRepo.CompleteCloseVotes(ctx, questionID, voteType); err != nil {
+		return err
+	}
+	return cs.closeVoteRepo.RecountCloseVotes(ctx, questionID)
+}
+
+func (cs *CloseVoteService) getThreshold(ctx context.Context) (threshold int, err error) {
+	siteQuestion, err := cs.siteInfoCommonService.GetSiteQuestion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return siteQuestion.CloseVoteThreshold, nil
+}
+
+// formatCloseVotes format the close votes of the open question or the reopen votes of the closed question
+func (cs *CloseVoteService) formatCloseVotes(ctx context.Context, questionInfo *entity.Question,
+	threshold int, userID string) (resp *schema.GetCloseVoteResp, err error) {
+	resp = &schema.GetCloseVoteResp{
+		VoteType:   schema.CloseVoteTypeClose,
+		Threshold:  threshold,
+		Closed:     questionInfo.Status == entity.QuestionStatusClosed,
+		Reasons:    make([]*schema.CloseVoteReason, 0),
+		Duplicates: make([]*schema.CloseVoteDuplicate, 0),
+	}
+	voteType := entity.QuestionCloseVoteTypeClose
+	if resp.Closed {
+		resp.VoteType = schema.CloseVoteTypeReopen
+		voteType = entity.QuestionCloseVoteTypeReopen
+	}
+	votes, err := cs.closeVoteRepo.GetActiveCloseVoteList(ctx, questionInfo.ID, voteType)
+	if err != nil {
+		return nil, err
+	}
+	resp.VoteCount = len(votes)
+	for _, vote := range votes {
+		if vote.UserID == userID {
+			resp.Voted = true
+		}
+	}
+	if resp.Closed {
+		return resp, nil
+	}
+
+	lang := handler.GetLangByCtx(ctx)
+	for _, tally := range tallyCloseReasons(votes) {
+		cfg, err := cs.configService.GetConfigByID(ctx, tally.closeType)
+		if err != nil {
+			log.Errorf("get close reason %d failed: %v", tally.closeType, err)
+			continue
+		}
+		reasonItem := &schema.ReasonItem{}
+		_ = json.Unmarshal(cfg.GetByteValue(), reasonItem)
+		reasonItem.Translate(cfg.Key, lang)
+		resp.Reasons = append(resp.Reasons, &schema.CloseVoteReason{
+			CloseType: tally.closeType,
+			Name:      reasonItem.Name,
+			VoteCount: tally.count,
+		})
+	}
+
+	tallies := tallyDuplicateVotes(votes)
+	questionIDs := make([]string, 0, len(tallies))
+	for _, tally := range tallies {
+		questionIDs = append(questionIDs, tally.questionID)
+	}
+	questions, err := cs.questionRepo.FindByID(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+	questionMapping := make(map[string]*entity.Question, len(questions))
+	for _, question := range questions {
+		questionMapping[question.ID] = question
+	}
+	for _, tally := range tallies {
+		question, ok := questionMapping[tally.questionID]
+		if !ok {
+			continue
+		}
+		item := &schema.CloseVoteDuplicate{
+			ID:        question.ID,
+			Title:     question.Title,
+			UrlTitle:  htmltext.UrlTitle(question.Title),
+			VoteCount: tally.count,
+		}
+		if handler.GetEnableShortID(ctx) {
+			item.ID = uid.EnShortID(item.ID)
+		}
+		resp.Duplicates = append(resp.Duplicates, item)
+	}
+	return resp, nil
+}
+
+// decideCloseReason choose the most voted close reason, the duplicate target is the most voted one
+// and the close message is the first one given for the reason.
+func decideCloseReason(votes []*entity.QuestionCloseVote) (closeType int, closeMsg, duplicateQuestionID string) {
+	reasons := tallyCloseReasons(votes)
+	if len(reasons) == 0 {
+		return 0, "", ""
+	}
+	closeType = reasons[0].closeType
+	reasonVotes := make([]*entity.QuestionCloseVote, 0)
+	for _, vote := range votes {
+		if vote.CloseType != closeType {
+			continue
+		}
+		reasonVotes = append(reasonVotes, vote)
+		if len(closeMsg) == 0 {
+			closeMsg = vote.CloseMsg
+		}
+	}
+	if duplicates := tallyDuplicateVotes(reasonVotes); len(duplicates) > 0 {
+		duplicateQuestionID = duplicates[0].questionID
+	}
+	return closeType, closeMsg, duplicateQuestionID
+}
+
+// reasonTally the number of votes for a close reason
+type reasonTally struct {
+	closeType int
+	count     int
+}
+
+// tallyCloseReasons count the votes of each close reason, the most voted first,
+// the reasons with the same votes are ordered by their first vote.
+func tallyCloseReasons(votes []*entity.QuestionCloseVote) (tallies []*reasonTally) {
+	tallies = make([]*reasonTally, 0)
+	mapping := make(map[int]*reasonTally)
+	for _, vote := range votes {
+		tally, ok := mapping[vote.CloseType]
+		if !ok {
+			tally = &reasonTally{closeType: vote.CloseType}
+			mapping[vote.CloseType] = tally
+			tallies = append(tallies, tally)
+		}
+		tally.count++
+	}
+	sort.SliceStable(tallies, func(i, j int) bool {
+		return tallies[i].count > tallies[j].count
+	})
+	return tallies
+}
+
+// duplicateTally the number of votes for a duplicate target
+type duplicateTally struct {
+	questionID string
+	count      int
+}
+
+// tallyDuplicateVotes count the votes of each duplicate target, the most voted first,
+// the targets with the same votes are ordered by their first vote.
+func tallyDuplicateVotes(votes []*entity.QuestionCloseVote) (tallies []*duplicateTally) {
+	tallies = make([]*duplicateTally, 0)
+	mapping := make(map[string]*duplicateTally)
+	for _, vote := range votes {
+		if len(vote.DuplicateQuestionID) == 0 || vote.DuplicateQuestionID == "0" {
+			continue
+		}
+		tally, ok := mapping[vote.DuplicateQuestionID]
+		if !ok {
+			tally = &duplicateTally{questionID: vote.DuplicateQuestionID}
+			mapping[vote.DuplicateQuestionID] = tally
+			tallies = append(tallies, tally)
+		}
+		tally.count++
+	}
+	sort.SliceStable(tallies, func(i, j int) bool {
+		return tallies[i].count > tallies[j].count
+	})
+	return tallies
+}
@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package close_vote
+
+import (
+	"testing"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTallyDuplicateVotes(t *testing.T) {
+	votes := []*entity.QuestionCloseVote{
+		{UserID: "1", DuplicateQuestionID: "10"},
+		{UserID: "2", DuplicateQuestionID: "20"},
+		{UserID: "3", DuplicateQuestionID: "20"},
+		{UserID: "4", DuplicateQuestionID: "30"},
+		{UserID: "5", DuplicateQuestionID: "0"},
+	}
+	tallies := tallyDuplicateVotes(votes)
+	assert.Len(t, tallies, 3)
+	assert.Equal(t, "20", tallies[0].questionID)
+	assert.Equal(t, 2, tallies[0].count)
+	// the targets with the same votes are ordered by their first vote
+	assert.Equal(t, "10", tallies[1].questionID)
+	assert.Equal(t, "30", tallies[2].questionID)
+
+	assert.Empty(t, tallyDuplicateVotes(nil))
+}
+
+func TestDecideCloseReason(t *testing.T) {
+	votes := []*entity.QuestionCloseVote{
+		{UserID: "1", CloseType: 1, CloseMsg: "off topic"},
+		{UserID: "2", CloseType: 2, DuplicateQuestionID: "10"},
+		{UserID: "3", CloseType: 2, DuplicateQuestionID: "20", CloseMsg: "same question"},
+		{UserID: "4", CloseType: 2, DuplicateQuestionID: "20"},
+		{UserID: "5", CloseType: 1},
+	}
+	closeType, closeMsg, duplicateQuestionID := decideCloseReason(votes)
+	assert.Equal(t, 2, closeType)
+	assert.Equal(t, "same question", closeMsg)
+	assert.Equal(t, "20", duplicateQuestionID)
+
+	// the reasons with the same votes are ordered by their first vote
+	closeType, closeMsg, duplicateQuestionID = decideCloseReason(votes[:2])
+	assert.Equal(t, 1, closeType)
+	assert.Equal(t, "off topic", closeMsg)
+	assert.Empty(t, duplicateQuestionID)
+
+	closeType, _, _ = decideCloseReason(nil)
+	assert.Equal(t, 0, closeType)
+}
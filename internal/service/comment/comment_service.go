@@ -144,6 +144,9 @@ func (cs *CommentService) AddComment(ctx context.Context, req *schema.AddComment
 	if objInfo.IsDeleted() {
 		return nil, errors.BadRequest(reason.NewObjectAlreadyDeleted)
 	}
+	if err := cs.objectInfoService.CheckSpacePost(ctx, objInfo, req.UserID); err != nil {
+		return nil, err
+	}
 	objInfo.ObjectID = uid.DeShortID(objInfo.ObjectID)
 	objInfo.QuestionID = uid.DeShortID(objInfo.QuestionID)
 	objInfo.AnswerID = uid.DeShortID(objInfo.AnswerID)
@@ -355,6 +358,9 @@ func (cs *CommentService) GetComment(ctx context.Context, req *schema.GetComment
 	if err := objInfo.CheckVisibility(req.UserID, req.IsAdminModerator); err != nil {
 		return nil, err
 	}
+	if err := cs.objectInfoService.CheckSpaceVisibility(ctx, objInfo, req.UserID); err != nil {
+		return nil, err
+	}
 
 	resp = &schema.GetCommentResp{
 		CommentID:      comment.ID,
@@ -413,6 +419,9 @@ func (cs *CommentService) GetCommentWithPage(ctx context.Context, req *schema.Ge
 	if err := objInfo.CheckVisibility(req.UserID, req.IsAdminModerator); err != nil {
 		return nil, err
 	}
+	if err := cs.objectInfoService.CheckSpaceVisibility(ctx, objInfo, req.UserID); err != nil {
+		return nil, err
+	}
 	dto := &CommentQuery{
 		PageCond:  pager.PageCond{Page: req.Page, PageSize: req.PageSize},
 		ObjectID:  req.ObjectID,
@@ -550,6 +559,10 @@ func (cs *CommentService) GetCommentPersonalWithPage(ctx context.Context, req *s
 			if err != nil {
 				log.Error(err)
 			} else {
+				// the comments in the spaces that the viewer cannot read are not shown
+				if cs.objectInfoService.CheckSpaceVisibility(ctx, objInfo, req.LoginUserID) != nil {
+					continue
+				}
 				commentResp.ObjectType = objInfo.ObjectType
 				commentResp.Title = objInfo.Title
 				commentResp.UrlTitle = htmltext.UrlTitle(objInfo.Title)
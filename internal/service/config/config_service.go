@@ -33,6 +33,7 @@ type ConfigRepo interface {
 	GetConfigByKey(ctx context.Context, key string) (c *entity.Config, err error)
 	GetConfigByKeyFromDB(ctx context.Context, key string) (c *entity.Config, err error)
 	UpdateConfig(ctx context.Context, key, value string) (err error)
+	GetTenantConfigListByKey(ctx context.Context, key string) (tenantConfigs []*entity.TenantConfig, err error)
 }
 
 // ConfigService user service
@@ -116,3 +117,16 @@ func (cs *ConfigService) GetIDByKey(ctx context.Context, key string) (id int, er
 func (cs *ConfigService) UpdateConfig(ctx context.Context, key, value string) (err error) {
 	return cs.configRepo.UpdateConfig(ctx, key, value)
 }
+
+// GetTenantValuesByKey get the values of the config overridden by the tenants, the key of the map is the tenant id
+func (cs *ConfigService) GetTenantValuesByKey(ctx context.Context, key string) (values map[int]string, err error) {
+	tenantConfigs, err := cs.configRepo.GetTenantConfigListByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	values = make(map[int]string, len(tenantConfigs))
+	for _, tenantConfig := range tenantConfigs {
+		values[tenantConfig.TenantID] = tenantConfig.Value
+	}
+	return values, nil
+}
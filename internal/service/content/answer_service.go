@@ -42,6 +42,7 @@ import (
 	"github.com/apache/answer/internal/service/review"
 	"github.com/apache/answer/internal/service/revision_common"
 	"github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/space_common"
 	usercommon "github.com/apache/answer/internal/service/user_common"
 	"github.com/apache/answer/internal/service/vector_sync"
 	"github.com/apache/answer/pkg/converter"
@@ -72,6 +73,7 @@ type AnswerService struct {
 	reviewService                    *review.ReviewService
 	eventQueueService                eventqueue.Service
 	vectorSyncService                vector_sync.Service
+	spaceCommon                      *space_common.SpaceCommon
 }
 
 func NewAnswerService(
@@ -93,6 +95,7 @@ func NewAnswerService(
 	reviewService *review.ReviewService,
 	eventQueueService eventqueue.Service,
 	vectorSyncService vector_sync.Service,
+	spaceCommon *space_common.SpaceCommon,
 ) *AnswerService {
 	return &AnswerService{
 		answerRepo:                       answerRepo,
@@ -113,6 +116,7 @@ func NewAnswerService(
 		reviewService:                    reviewService,
 		eventQueueService:                eventQueueService,
 		vectorSyncService:                vectorSyncService,
+		spaceCommon:                      spaceCommon,
 	}
 }
 
@@ -262,6 +266,13 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 		err = errors.BadRequest(reason.AnswerCannotAddByClosedQuestion)
 		return "", err
 	}
+	canPost, err := as.spaceCommon.CanPostInSpace(ctx, questionInfo.SpaceID, req.UserID)
+	if err != nil {
+		return "", err
+	}
+	if !canPost {
+		return "", errors.Forbidden(reason.SpaceNoPermission)
+	}
 	insertData := &entity.Answer{}
 	insertData.UserID = req.UserID
 	insertData.OriginalText = req.Content
@@ -271,11 +282,23 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 	insertData.RevisionID = "0"
 	insertData.LastEditUserID = "0"
 	insertData.Status = entity.AnswerStatusPending
+	insertData.SpaceID = questionInfo.SpaceID
+	if req.PublishAt > 0 {
+		insertData.PublishAt = time.Unix(req.PublishAt, 0)
+	}
+	// the answer of the scheduled question can not be published before the question
+	if questionInfo.Status == entity.QuestionStatusScheduled && questionInfo.PublishAt.After(insertData.PublishAt) {
+		insertData.PublishAt = questionInfo.PublishAt
+	}
 	// insertData.UpdatedAt = now
 	if err = as.answerRepo.AddAnswer(ctx, insertData); err != nil {
 		return "", err
 	}
 	insertData.Status = as.reviewService.AddAnswerReview(ctx, insertData, req.IP, req.UserAgent)
+	// the approved answer is hidden until the publish time
+	if insertData.Status == entity.AnswerStatusAvailable && insertData.PublishAt.After(time.Now()) {
+		insertData.Status = entity.AnswerStatusScheduled
+	}
 	if err := as.answerRepo.UpdateAnswerStatus(ctx, insertData.ID, insertData.Status); err != nil {
 		return "", err
 	}
@@ -292,13 +315,16 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 	if err != nil {
 		log.Error("IncreaseAnswerCount error", err.Error())
 	}
-	err = as.questionCommon.UpdateLastAnswer(ctx, req.QuestionID, uid.DeShortID(insertData.ID))
-	if err != nil {
-		log.Error("UpdateLastAnswer error", err.Error())
-	}
-	err = as.questionCommon.UpdatePostTime(ctx, req.QuestionID)
-	if err != nil {
-		return insertData.ID, err
+	// the question is not bumped by the scheduled answer until it is published
+	if insertData.Status != entity.AnswerStatusScheduled {
+		err = as.questionCommon.UpdateLastAnswer(ctx, req.QuestionID, uid.DeShortID(insertData.ID))
+		if err != nil {
+			log.Error("UpdateLastAnswer error", err.Error())
+		}
+		err = as.questionCommon.UpdatePostTime(ctx, req.QuestionID)
+		if err != nil {
+			return insertData.ID, err
+		}
 	}
 	userAnswerCount, err := as.answerRepo.GetCountByUserID(ctx, req.UserID)
 	if err != nil {
@@ -338,8 +364,11 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 		OriginalObjectID: questionInfo.ID,
 		ActivityTypeKey:  constant.ActQuestionAnswered,
 	})
-	as.eventQueueService.Send(ctx, schema.NewEvent(constant.EventAnswerCreate, req.UserID).TID(insertData.ID).
-		AID(insertData.ID, insertData.UserID))
+	// the events of the scheduled answer are sent when it is published
+	if insertData.Status != entity.AnswerStatusScheduled {
+		as.eventQueueService.Send(ctx, schema.NewEvent(constant.EventAnswerCreate, req.UserID).TID(insertData.ID).
+			AID(insertData.ID, insertData.UserID))
+	}
 	if insertData.Status == entity.AnswerStatusAvailable {
 		as.vectorSyncService.Send(ctx, &vector_sync.Task{Action: vector_sync.ActionUpsert, ObjectType: vector_sync.ObjectTypeAnswer, ObjectID: insertData.ID})
 		as.vectorSyncService.Send(ctx, &vector_sync.Task{Action: vector_sync.ActionUpsert, ObjectType: vector_sync.ObjectTypeQuestion, ObjectID: insertData.QuestionID})
@@ -347,6 +376,48 @@ func (as *AnswerService) Insert(ctx context.Context, req *schema.AnswerAddReq) (
 	return insertData.ID, nil
 }
 
+// AfterAnswerPublished send the events, notifications and search sync of the scheduled answer
+// which has just been published
+func (as *AnswerService) AfterAnswerPublished(ctx context.Context, answer *entity.Answer) {
+	questionInfo, exist, err := as.questionRepo.GetQuestion(ctx, answer.QuestionID)
+	if err != nil || !exist {
+		log.Errorf("get question of the published answer %s failed: %v", answer.ID, err)
+		return
+	}
+	parsedText, err := as.questionCommon.UpdateQuestionLink(ctx, answer.QuestionID, answer.ID, answer.ParsedText, answer.OriginalText)
+	if err != nil {
+		log.Errorf("update answer link failed: %v", err)
+	} else {
+		answer.ParsedText = parsedText
+	}
+	// the search content is synced with the update
+	if err = as.answerRepo.UpdateAnswer(ctx, answer, []string{"parsed_text"}); err != nil {
+		log.Errorf("update published answer failed: %v", err)
+	}
+	if err = as.questionCommon.UpdateAnswerCount(ctx, answer.QuestionID); err != nil {
+		log.Errorf("update question answer count failed: %v", err)
+	}
+	if err = as.questionCommon.UpdateLastAnswer(ctx, answer.QuestionID, uid.DeShortID(answer.ID)); err != nil {
+		log.Errorf("update question last answer failed: %v", err)
+	}
+	if err = as.questionCommon.UpdatePostTime(ctx, answer.QuestionID); err != nil {
+		log.Errorf("update question post time failed: %v", err)
+	}
+	userAnswerCount, err := as.answerRepo.GetCountByUserID(ctx, answer.UserID)
+	if err != nil {
+		log.Errorf("get user answer count failed: %v", err)
+	} else if err = as.userCommon.UpdateAnswerCount(ctx, answer.UserID, int(userAnswerCount)); err != nil {
+		log.Errorf("update user answer count failed: %v", err)
+	}
+
+	as.notificationAnswerTheQuestion(ctx, questionInfo.UserID, questionInfo.ID, answer.ID, answer.UserID, questionInfo.Title,
+		htmltext.FetchExcerpt(answer.ParsedText, "...", 240))
+	as.eventQueueService.Send(ctx, schema.NewEvent(constant.EventAnswerCreate, answer.UserID).TID(answer.ID).
+		AID(answer.ID, answer.UserID))
+	as.vectorSyncService.Send(ctx, &vector_sync.Task{Action: vector_sync.ActionUpsert, ObjectType: vector_sync.ObjectTypeAnswer, ObjectID: answer.ID})
+	as.vectorSyncService.Send(ctx, &vector_sync.Task{Action: vector_sync.ActionUpsert, ObjectType: vector_sync.ObjectTypeQuestion, ObjectID: answer.QuestionID})
+}
+
 func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq) (string, error) {
 	var canUpdate bool
 	_, existUnreviewed, err := as.revisionService.ExistUnreviewedByObjectID(ctx, req.ID)
@@ -375,6 +446,13 @@ func (as *AnswerService) Update(ctx context.Context, req *schema.AnswerUpdateReq
 	if !exist {
 		return "", errors.BadRequest(reason.QuestionNotFound)
 	}
+	canPost, err := as.spaceCommon.CanPostInSpace(ctx, questionInfo.SpaceID, req.UserID)
+	if err != nil {
+		return "", err
+	}
+	if !canPost {
+		return "", errors.Forbidden(reason.SpaceNoPermission)
+	}
 
 	// If the content is the same, ignore it
 	if answerInfo.OriginalText == req.Content {
@@ -557,15 +635,24 @@ func (as *AnswerService) Get(ctx context.Context, answerID, loginUserID string,
 
 	if (question.Status == entity.QuestionStatusDeleted ||
 		question.Status == entity.QuestionStatusPending ||
+		question.Status == entity.QuestionStatusScheduled ||
 		question.Show == entity.QuestionHide) &&
 		!isAdminModerator && question.UserID != loginUserID {
 		return nil, nil, false, errors.NotFound(reason.AnswerNotFound)
 	}
 	if (answerInfo.Status == entity.AnswerStatusDeleted ||
-		answerInfo.Status == entity.AnswerStatusPending) &&
+		answerInfo.Status == entity.AnswerStatusPending ||
+		answerInfo.Status == entity.AnswerStatusScheduled) &&
 		!isAdminModerator && answerInfo.UserID != loginUserID {
 		return nil, nil, false, errors.NotFound(reason.AnswerNotFound)
 	}
+	canView, err := as.spaceCommon.CanViewSpace(ctx, question.SpaceID, loginUserID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if !canView {
+		return nil, nil, false, errors.NotFound(reason.AnswerNotFound)
+	}
 	info := as.ShowFormat(ctx, answerInfo)
 	// todo questionFunc
 	questionInfo, err := as.questionCommon.Info(ctx, answerInfo.QuestionID, loginUserID)
@@ -687,10 +774,18 @@ func (as *AnswerService) SearchList(ctx context.Context, req *schema.AnswerListR
 	}
 	if (questionInfo.Status == entity.QuestionStatusDeleted ||
 		questionInfo.Status == entity.QuestionStatusPending ||
+		questionInfo.Status == entity.QuestionStatusScheduled ||
 		questionInfo.Show == entity.QuestionHide) &&
 		!req.IsAdminModerator && questionInfo.UserID != req.UserID {
 		return list, 0, errors.NotFound(reason.QuestionNotFound)
 	}
+	canView, err := as.spaceCommon.CanViewSpace(ctx, questionInfo.SpaceID, req.UserID)
+	if err != nil {
+		return list, 0, err
+	}
+	if !canView {
+		return list, 0, errors.NotFound(reason.QuestionNotFound)
+	}
 	dbSearch := entity.AnswerSearch{}
 	dbSearch.QuestionID = req.QuestionID
 	dbSearch.Page = req.Page
@@ -23,6 +23,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -53,6 +54,7 @@ import (
 	"github.com/apache/answer/internal/service/revision_common"
 	"github.com/apache/answer/internal/service/role"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/space_common"
 	"github.com/apache/answer/internal/service/tag"
 	tagcommon "github.com/apache/answer/internal/service/tag_common"
 	usercommon "github.com/apache/answer/internal/service/user_common"
@@ -95,6 +97,7 @@ type QuestionService struct {
 	eventQueueService                eventqueue.Service
 	reviewRepo                       review.ReviewRepo
 	vectorSyncService                vector_sync.Service
+	spaceCommon                      *space_common.SpaceCommon
 }
 
 func NewQuestionService(
@@ -122,6 +125,7 @@ func NewQuestionService(
 	eventQueueService eventqueue.Service,
 	reviewRepo review.ReviewRepo,
 	vectorSyncService vector_sync.Service,
+	spaceCommon *space_common.SpaceCommon,
 ) *QuestionService {
 	return &QuestionService{
 		activityRepo:                     activityRepo,
@@ -148,6 +152,7 @@ func NewQuestionService(
 		eventQueueService:                eventQueueService,
 		reviewRepo:                       reviewRepo,
 		vectorSyncService:                vectorSyncService,
+		spaceCommon:                      spaceCommon,
 	}
 }
 
@@ -164,8 +169,12 @@ func (qs *QuestionService) CloseQuestion(ctx context.Context, req *schema.CloseQ
 	if err != nil || cf == nil {
 		return errors.BadRequest(reason.ReportNotFound)
 	}
-	if cf.Key == constant.ReasonADuplicate && !checker.IsURL(req.CloseMsg) {
-		return errors.BadRequest(reason.InvalidURLError)
+	var duplicateQuestionID string
+	if cf.Key == constant.ReasonADuplicate {
+		duplicateQuestionID, err = qs.getDuplicateQuestionIDForClose(ctx, questionInfo.ID, req)
+		if err != nil {
+			return err
+		}
 	}
 
 	questionInfo.Status = entity.QuestionStatusClosed
@@ -175,15 +184,16 @@ func (qs *QuestionService) CloseQuestion(ctx context.Context, req *schema.CloseQ
 	}
 
 	closeMeta, _ := json.Marshal(schema.CloseQuestionMeta{
-		CloseType: req.CloseType,
-		CloseMsg:  req.CloseMsg,
+		CloseType:           req.CloseType,
+		CloseMsg:            req.CloseMsg,
+		DuplicateQuestionID: duplicateQuestionID,
 	})
 	err = qs.metaService.AddMeta(ctx, req.ID, entity.QuestionCloseReasonKey, string(closeMeta))
 	if err != nil {
 		return err
 	}
-	if cf.Key == constant.ReasonADuplicate {
-		qs.questioncommon.AddQuestionLinkForCloseReason(ctx, questionInfo, req.CloseMsg)
+	if len(duplicateQuestionID) > 0 {
+		qs.questioncommon.AddDuplicateQuestionLink(ctx, questionInfo.ID, duplicateQuestionID)
 	}
 
 	qs.activityQueueService.Send(ctx, &schema.ActivityMsg{
@@ -195,6 +205,36 @@ func (qs *QuestionService) CloseQuestion(ctx context.Context, req *schema.CloseQ
 	return nil
 }
 
+// getDuplicateQuestionIDForClose get the canonical question that the closing question duplicates,
+// the duplicate question is given by id, or by the question url in the close message as before.
+func (qs *QuestionService) getDuplicateQuestionIDForClose(ctx context.Context, questionID string,
+	req *schema.CloseQuestionReq) (duplicateQuestionID string, err error) {
+	duplicateQuestionID = req.DuplicateQuestionID
+	byURL := len(duplicateQuestionID) == 0
+	if byURL {
+		if !checker.IsURL(req.CloseMsg) {
+			return "", errors.BadRequest(reason.InvalidURLError)
+		}
+		duplicateQuestionID = qs.questioncommon.GetDuplicateQuestionID(ctx, &schema.CloseQuestionMeta{CloseMsg: req.CloseMsg})
+		// the url is not a question of this site, keep it as the close message only
+		if len(duplicateQuestionID) == 0 {
+			return "", nil
+		}
+	}
+	canonicalID, err := qs.questioncommon.GetCanonicalQuestionID(ctx, questionID, duplicateQuestionID)
+	if err != nil {
+		if byURL {
+			log.Debugf("the question in the close message is not available: %v", err)
+			return "", nil
+		}
+		return "", err
+	}
+	if len(canonicalID) == 0 {
+		return "", errors.BadRequest(reason.QuestionDuplicateInvalid)
+	}
+	return canonicalID, nil
+}
+
 // ReopenQuestion reopen question
 func (qs *QuestionService) ReopenQuestion(ctx context.Context, req *schema.ReopenQuestionReq) error {
 	questionInfo, has, err := qs.questionRepo.GetQuestion(ctx, req.QuestionID)
@@ -295,7 +335,23 @@ func (qs *QuestionService) CheckAddQuestion(ctx context.Context, req *schema.Que
 			return errorlist, err
 		}
 	}
-	return nil, nil
+	if errorlist, err := qs.tagCommon.CheckCompanionTags(ctx, tagNameList); err != nil {
+		return errorlist, err
+	}
+	return qs.checkQuestionSpace(ctx, req.SpaceID, req.UserID, tagNameList)
+}
+
+// checkQuestionSpace check that the user can ask in the space and the tags are usable in it
+func (qs *QuestionService) checkQuestionSpace(ctx context.Context, spaceID int, userID string, tagNameList []string) (
+	errorlist []*validator.FormErrorField, err error) {
+	canPost, err := qs.spaceCommon.CanPostInSpace(ctx, spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canPost {
+		return nil, errors.Forbidden(reason.SpaceNoPermission)
+	}
+	return qs.tagCommon.CheckSpaceTags(ctx, spaceID, tagNameList)
 }
 
 // HasNewTag
@@ -368,6 +424,12 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 			return errorlist, err
 		}
 	}
+	if errorlist, err := qs.tagCommon.CheckCompanionTags(ctx, tagNameList); err != nil {
+		return errorlist, err
+	}
+	if errorlist, err := qs.checkQuestionSpace(ctx, req.SpaceID, req.UserID, tagNameList); err != nil {
+		return errorlist, err
+	}
 
 	question := &entity.Question{}
 	now := time.Now()
@@ -385,12 +447,20 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 	question.PostUpdateTime = now
 	question.Pin = entity.QuestionUnPin
 	question.Show = entity.QuestionShow
+	question.SpaceID = req.SpaceID
+	if req.PublishAt > 0 {
+		question.PublishAt = time.Unix(req.PublishAt, 0)
+	}
 	// question.UpdatedAt = nil
 	err = qs.questionRepo.AddQuestion(ctx, question)
 	if err != nil {
 		return
 	}
 	question.Status = qs.reviewService.AddQuestionReview(ctx, question, req.Tags, req.IP, req.UserAgent)
+	// the approved question is hidden until the publish time
+	if question.Status == entity.QuestionStatusAvailable && question.PublishAt.After(now) {
+		question.Status = entity.QuestionStatusScheduled
+	}
 	if err := qs.questionRepo.UpdateQuestionStatus(ctx, question.ID, question.Status); err != nil {
 		return nil, err
 	}
@@ -407,6 +477,7 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 	objectTagData := schema.TagChange{}
 	objectTagData.ObjectID = question.ID
 	objectTagData.Tags = req.Tags
+	objectTagData.SpaceID = question.SpaceID
 	objectTagData.UserID = req.UserID
 	errorlist, err := qs.ChangeTag(ctx, &objectTagData)
 	if err != nil {
@@ -452,14 +523,20 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 		if newTagsErr != nil {
 			log.Error("get question newTags error %v", newTagsErr)
 			qs.externalNotificationQueueService.Send(ctx,
-				schema.CreateNewQuestionNotificationMsg(question.ID, question.Title, question.UserID, tags))
+				schema.CreateNewQuestionNotificationMsg(question.ID, question.Title, question.UserID, question.SpaceID, tags))
 		} else {
 			qs.externalNotificationQueueService.Send(ctx,
-				schema.CreateNewQuestionNotificationMsg(question.ID, question.Title, question.UserID, newTags))
+				schema.CreateNewQuestionNotificationMsg(question.ID, question.Title, question.UserID, question.SpaceID, newTags))
 		}
 	}
-	qs.eventQueueService.Send(ctx, schema.NewEvent(constant.EventQuestionCreate, req.UserID).TID(question.ID).
-		QID(question.ID, question.UserID))
+	if question.Status == entity.QuestionStatusAvailable {
+		qs.notifyTagOwners(ctx, question, tags)
+	}
+	// the events of the scheduled question are sent when it is published
+	if question.Status != entity.QuestionStatusScheduled {
+		qs.eventQueueService.Send(ctx, schema.NewEvent(constant.EventQuestionCreate, req.UserID).TID(question.ID).
+			QID(question.ID, question.UserID))
+	}
 	if question.Status == entity.QuestionStatusAvailable {
 		qs.vectorSyncService.Send(ctx, &vector_sync.Task{Action: vector_sync.ActionUpsert, ObjectType: vector_sync.ObjectTypeQuestion, ObjectID: question.ID})
 	}
@@ -468,6 +545,65 @@ func (qs *QuestionService) AddQuestion(ctx context.Context, req *schema.Question
 	return
 }
 
+// AfterQuestionPublished send the events, notifications and search sync of the scheduled question
+// which has just been published
+func (qs *QuestionService) AfterQuestionPublished(ctx context.Context, question *entity.Question) {
+	parsedText, err := qs.questioncommon.UpdateQuestionLink(ctx, question.ID, "", question.ParsedText, question.OriginalText)
+	if err != nil {
+		log.Errorf("update question link failed: %v", err)
+	} else {
+		question.ParsedText = parsedText
+	}
+	// the search content is synced with the update
+	if err = qs.questionRepo.UpdateQuestion(ctx, question, []string{"parsed_text"}); err != nil {
+		log.Errorf("update published question failed: %v", err)
+	}
+	userQuestionCount, err := qs.questioncommon.GetUserQuestionCount(ctx, question.UserID)
+	if err != nil {
+		log.Errorf("get user question count error %v", err)
+	} else if err = qs.userCommon.UpdateQuestionCount(ctx, question.UserID, userQuestionCount); err != nil {
+		log.Errorf("update user question count error %v", err)
+	}
+
+	tags, err := qs.tagCommon.GetObjectEntityTag(ctx, question.ID)
+	if err != nil {
+		log.Errorf("get question tags failed: %v", err)
+	}
+	qs.externalNotificationQueueService.Send(ctx,
+		schema.CreateNewQuestionNotificationMsg(question.ID, question.Title, question.UserID, question.SpaceID, tags))
+	qs.notifyTagOwners(ctx, question, tags)
+	qs.eventQueueService.Send(ctx, schema.NewEvent(constant.EventQuestionCreate, question.UserID).TID(question.ID).
+		QID(question.ID, question.UserID))
+	qs.vectorSyncService.Send(ctx, &vector_sync.Task{Action: vector_sync.ActionUpsert, ObjectType: vector_sync.ObjectTypeQuestion, ObjectID: question.ID})
+}
+
+// notifyTagOwners notify the owners of the question tags that a new question is asked
+func (qs *QuestionService) notifyTagOwners(ctx context.Context, question *entity.Question, tags []*entity.Tag) {
+	tagIDs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	ownerIDs, err := qs.tagCommon.GetTagOwnerIDsByTagIDs(ctx, tagIDs)
+	if err != nil {
+		log.Errorf("get tag owners failed: %v", err)
+		return
+	}
+	for _, ownerID := range ownerIDs {
+		if ownerID == question.UserID {
+			continue
+		}
+		qs.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+			TriggerUserID:       question.UserID,
+			ReceiverUserID:      ownerID,
+			Type:                schema.NotificationTypeInbox,
+			ObjectID:            question.ID,
+			ObjectType:          constant.QuestionObjectType,
+			NotificationAction:  constant.NotificationNewQuestionInOwnedTag,
+			NoNeedPushAllFollow: true,
+		})
+	}
+}
+
 // OperationQuestion
 func (qs *QuestionService) OperationQuestion(ctx context.Context, req *schema.OperationQuestionReq) (err error) {
 	questionInfo, has, err := qs.questionRepo.GetQuestion(ctx, req.ID)
@@ -726,7 +862,10 @@ func (qs *QuestionService) UpdateQuestionCheckTags(ctx context.Context, req *sch
 			return errorlist, err
 		}
 	}
-	return nil, nil
+	if errorlist, err := qs.tagCommon.CheckCompanionTags(ctx, tagNameList); err != nil {
+		return errorlist, err
+	}
+	return qs.tagCommon.CheckSpaceTags(ctx, dbinfo.SpaceID, tagNameList)
 }
 
 func (qs *QuestionService) RecoverQuestion(ctx context.Context, req *schema.QuestionRecoverReq) (err error) {
@@ -927,6 +1066,13 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 		err = errors.BadRequest(reason.QuestionCannotUpdate)
 		return nil, err
 	}
+	canPost, err := qs.spaceCommon.CanPostInSpace(ctx, dbinfo.SpaceID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canPost {
+		return nil, errors.Forbidden(reason.SpaceNoPermission)
+	}
 
 	now := time.Now()
 	question := &entity.Question{}
@@ -1020,6 +1166,12 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 		err = errors.BadRequest(reason.RecommendTagEnter)
 		return errorlist, err
 	}
+	if errorlist, err := qs.tagCommon.CheckCompanionTags(ctx, tagNameList); err != nil {
+		return errorlist, err
+	}
+	if errorlist, err := qs.tagCommon.CheckSpaceTags(ctx, dbinfo.SpaceID, tagNameList); err != nil {
+		return errorlist, err
+	}
 
 	// Administrators and themselves do not need to be audited
 
@@ -1053,6 +1205,7 @@ func (qs *QuestionService) UpdateQuestion(ctx context.Context, req *schema.Quest
 		objectTagData := schema.TagChange{}
 		objectTagData.ObjectID = question.ID
 		objectTagData.Tags = req.Tags
+		objectTagData.SpaceID = dbinfo.SpaceID
 		objectTagData.UserID = req.UserID
 		errorlist, tagerr := qs.ChangeTag(ctx, &objectTagData)
 		if tagerr != nil {
@@ -1091,14 +1244,22 @@ func (qs *QuestionService) GetQuestion(ctx context.Context, questionID, userID s
 	if err != nil {
 		return
 	}
-	// If the question is deleted or pending, only the administrator and the author can view it
+	// If the question is deleted, pending or scheduled, only the administrator and the author can view it
 	if (question.Status == entity.QuestionStatusDeleted ||
-		question.Status == entity.QuestionStatusPending) && !per.CanReopen && question.UserID != userID {
+		question.Status == entity.QuestionStatusPending ||
+		question.Status == entity.QuestionStatusScheduled) && !per.CanReopen && question.UserID != userID {
 		return nil, errors.NotFound(reason.QuestionNotFound)
 	}
 	if question.Show == entity.QuestionHide && !per.IsAdminModerator && question.UserID != userID {
 		return nil, errors.NotFound(reason.QuestionNotFound)
 	}
+	canView, err := qs.spaceCommon.CanViewSpace(ctx, question.SpaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.NotFound(reason.QuestionNotFound)
+	}
 	if question.Status != entity.QuestionStatusClosed {
 		per.CanReopen = false
 	}
@@ -1132,6 +1293,12 @@ func (qs *QuestionService) GetQuestion(ctx context.Context, questionID, userID s
 		operation.Level = schema.OperationLevelSecondary
 		question.Operation = operation
 	}
+	if question.Status == entity.QuestionStatusScheduled {
+		operation := &schema.Operation{}
+		operation.Msg = translator.Tr(handler.GetLangByCtx(ctx), reason.QuestionScheduled)
+		operation.Level = schema.OperationLevelSecondary
+		question.Operation = operation
+	}
 
 	question.Description = htmltext.FetchExcerpt(question.HTML, "...", 240)
 	question.MemberActions = permission.GetQuestionPermission(ctx, userID, question.UserID, question.Status,
@@ -1220,6 +1387,10 @@ func (qs *QuestionService) PersonalAnswerPage(ctx context.Context, req *schema.P
 	cond.Page = req.Page
 	cond.PageSize = req.PageSize
 	cond.ShowPending = req.IsAdmin || req.LoginUserID == cond.UserID
+	cond.SpaceIDs, err = qs.getProfileSpaceIDs(ctx, req.LoginUserID, cond.UserID)
+	if err != nil {
+		return nil, err
+	}
 	if req.OrderCond == "newest" {
 		cond.Order = entity.AnswerSearchOrderByTime
 	} else {
@@ -1284,12 +1455,17 @@ func (qs *QuestionService) PersonalCollectionPage(ctx context.Context, req *sche
 	if err != nil {
 		return nil, err
 	}
+	// the collected questions of the spaces that the user has left are not shown
+	spaceIDs, err := qs.getProfileSpaceIDs(ctx, req.UserID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
 	for _, id := range questionIDs {
 		if handler.GetEnableShortID(ctx) {
 			id = uid.EnShortID(id)
 		}
 		_, ok := questionMaps[id]
-		if ok {
+		if ok && slices.Contains(spaceIDs, questionMaps[id].SpaceID) {
 			questionMaps[id].LastAnsweredUserInfo = nil
 			questionMaps[id].UpdateUserInfo = nil
 			questionMaps[id].Content = ""
@@ -1331,6 +1507,10 @@ func (qs *QuestionService) SearchUserTopList(ctx context.Context, userName strin
 	answersearch.UserID = userinfo.ID
 	answersearch.PageSize = 5
 	answersearch.Order = entity.AnswerSearchOrderByVote
+	answersearch.SpaceIDs, err = qs.getProfileSpaceIDs(ctx, loginUserID, userinfo.ID)
+	if err != nil {
+		return userQuestionlist, userAnswerlist, err
+	}
 	questionIDs := make([]string, 0)
 	answerList, _, err := qs.questioncommon.AnswerCommon.Search(ctx, answersearch)
 	if err != nil {
@@ -1386,10 +1566,11 @@ func (qs *QuestionService) GetQuestionsByTitle(ctx context.Context, title string
 	})
 
 	var questions []*entity.Question
-	if finder != nil {
+	if finder != nil && plugin.StatusManager.IsEnabledFor(ctx, finder.Info().SlugName) {
 		// call search plugin if available
 		words := []string{title}
 		res, _, err := finder.SearchQuestions(ctx, &plugin.SearchBasicCond{
+			TenantID: handler.GetTenantID(ctx),
 			Words:    words,
 			Page:     1,
 			PageSize: 10,
@@ -1415,6 +1596,10 @@ func (qs *QuestionService) GetQuestionsByTitle(ctx context.Context, title string
 		}
 	}
 	for _, question := range questions {
+		// the questions of the spaces are not suggested by title
+		if question.SpaceID != entity.PublicSpaceID {
+			continue
+		}
 		item := &schema.QuestionBaseInfo{}
 		item.ID = question.ID
 		item.Title = question.Title
@@ -1452,6 +1637,7 @@ func (qs *QuestionService) SimilarQuestion(ctx context.Context, questionID strin
 	if len(tagNames) > 0 {
 		search.Tag = tagNames[0]
 	}
+	search.SpaceID = question.SpaceID
 	search.LoginUserID = loginUserID
 	similarQuestions, _, err := qs.GetQuestionPage(ctx, search)
 	if err != nil {
@@ -1490,12 +1676,10 @@ func (qs *QuestionService) GetQuestionPage(ctx context.Context, req *schema.Ques
 			return nil, 0, err
 		}
 		if exist {
-			synTagIds, err := qs.tagCommon.GetTagIDsByMainTagID(ctx, tagInfo.ID)
+			tagIDs, err = qs.tagCommon.GetTagFamilyIDs(ctx, tagInfo)
 			if err != nil {
 				return nil, 0, err
 			}
-			tagIDs = append(tagIDs, synTagIds...)
-			tagIDs = append(tagIDs, tagInfo.ID)
 		} else {
 			return questions, 0, nil
 		}
@@ -1517,8 +1701,13 @@ func (qs *QuestionService) GetQuestionPage(ctx context.Context, req *schema.Ques
 		req.InDays = schema.HotInDays
 	}
 
+	spaceIDs, err := qs.getQuestionPageSpaceIDs(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	questionList, total, err := qs.questionRepo.GetQuestionPage(ctx, req.Page, req.PageSize,
-		tagIDs, req.UserIDBeSearched, req.OrderCond, req.InDays, showHidden, req.ShowPending)
+		tagIDs, req.UserIDBeSearched, req.OrderCond, req.InDays, showHidden, req.ShowPending, spaceIDs)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1529,6 +1718,37 @@ func (qs *QuestionService) GetQuestionPage(ctx context.Context, req *schema.Ques
 	return questions, total, nil
 }
 
+// getQuestionPageSpaceIDs the list shows the public questions by default, the questions of the space when it is requested,
+// and the users can see the questions they asked in all their spaces on their own profile
+func (qs *QuestionService) getQuestionPageSpaceIDs(ctx context.Context, req *schema.QuestionPageReq) (
+	spaceIDs []int, err error) {
+	if req.SpaceID != entity.PublicSpaceID {
+		canView, err := qs.spaceCommon.CanViewSpace(ctx, req.SpaceID, req.LoginUserID)
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			return nil, errors.NotFound(reason.SpaceNotFound)
+		}
+		return []int{req.SpaceID}, nil
+	}
+	return qs.getProfileSpaceIDs(ctx, req.LoginUserID, req.UserIDBeSearched)
+}
+
+// getProfileSpaceIDs the profile of the user shows the public posts, and the posts in the spaces only to the user self
+func (qs *QuestionService) getProfileSpaceIDs(ctx context.Context, loginUserID, profileUserID string) (
+	spaceIDs []int, err error) {
+	spaceIDs = []int{entity.PublicSpaceID}
+	if len(loginUserID) == 0 || loginUserID != profileUserID {
+		return spaceIDs, nil
+	}
+	userSpaceIDs, err := qs.spaceCommon.GetUserSpaceIDs(ctx, loginUserID)
+	if err != nil {
+		return nil, err
+	}
+	return append(spaceIDs, userSpaceIDs...), nil
+}
+
 // GetRecommendQuestionPage retrieves recommended question page based on following tags and questions.
 func (qs *QuestionService) GetRecommendQuestionPage(ctx context.Context, req *schema.QuestionPageReq) (
 	questions []*schema.QuestionPageResp, total int64, err error) {
@@ -43,6 +43,7 @@ import (
 	"github.com/apache/answer/internal/service/tag_common"
 	usercommon "github.com/apache/answer/internal/service/user_common"
 	"github.com/apache/answer/pkg/converter"
+	"github.com/apache/answer/pkg/diff"
 	"github.com/apache/answer/pkg/htmltext"
 	"github.com/apache/answer/pkg/obj"
 	"github.com/apache/answer/pkg/uid"
@@ -225,6 +226,7 @@ func (rs *RevisionService) revisionAuditQuestion(ctx context.Context, revisionit
 		objectTagData := schema.TagChange{}
 		objectTagData.ObjectID = question.ID
 		objectTagData.Tags = objectTagTags
+		objectTagData.SpaceID = dbquestion.SpaceID
 		minimumTags, err := rs.tagCommon.GetMinimumTags(ctx)
 		if err != nil {
 			return err
@@ -407,6 +409,9 @@ func (rs *RevisionService) GetRevisionList(ctx context.Context, req *schema.GetR
 	if err := objInfo.CheckVisibility(req.UserID, req.IsAdmin); err != nil {
 		return nil, err
 	}
+	if err := rs.objectInfoService.CheckSpaceVisibility(ctx, objInfo, req.UserID); err != nil {
+		return nil, err
+	}
 
 	_ = copier.Copy(&rev, req)
 
@@ -561,3 +566,158 @@ func (rs *RevisionService) GetReviewingType(ctx context.Context, req *schema.Get
 	}
 	return resp, nil
 }
+
+// GetRevisionDiff compare two revisions of the same object word by word
+func (rs *RevisionService) GetRevisionDiff(ctx context.Context, req *schema.GetRevisionDiffReq) (
+	resp *schema.GetRevisionDiffResp, err error) {
+	source, err := rs.getAvailableRevision(ctx, req.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := rs.getAvailableRevision(ctx, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if source.ObjectID != target.ObjectID {
+		return nil, errors.BadRequest(reason.RequestFormatError)
+	}
+	objInfo, err := rs.objectInfoService.GetInfo(ctx, source.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := objInfo.CheckVisibility(req.UserID, req.IsAdmin); err != nil {
+		return nil, err
+	}
+	if err := rs.objectInfoService.CheckSpaceVisibility(ctx, objInfo, req.UserID); err != nil {
+		return nil, err
+	}
+
+	sourceTitle, sourceContent, sourceTags, err := parseRevisionSnapshot(source)
+	if err != nil {
+		return nil, err
+	}
+	targetTitle, targetContent, targetTags, err := parseRevisionSnapshot(target)
+	if err != nil {
+		return nil, err
+	}
+	resp = &schema.GetRevisionDiffResp{
+		ObjectID:   source.ObjectID,
+		ObjectType: constant.ObjectTypeNumberMapping[source.ObjectType],
+		SourceID:   source.ID,
+		TargetID:   target.ID,
+		Title:      diff.Words(sourceTitle, targetTitle),
+		Content:    diff.Words(sourceContent, targetContent),
+		Tags:       diff.Strings(sourceTags, targetTags),
+	}
+	if handler.GetEnableShortID(ctx) {
+		resp.ObjectID = uid.EnShortID(resp.ObjectID)
+	}
+	return resp, nil
+}
+
+// GetRevisionRollback build the edit request which restores the object to the revision,
+// the edit goes through the same permission check, review and activity as the normal edit.
+func (rs *RevisionService) GetRevisionRollback(ctx context.Context, req *schema.RevisionRollbackReq) (
+	dto *schema.RevisionRollbackDTO, err error) {
+	rev, err := rs.getAvailableRevision(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	dto = &schema.RevisionRollbackDTO{
+		ObjectID:   rev.ObjectID,
+		ObjectType: constant.ObjectTypeNumberMapping[rev.ObjectType],
+	}
+	switch dto.ObjectType {
+	case constant.QuestionObjectType:
+		question := &entity.QuestionWithTagsRevision{}
+		if err = json.Unmarshal([]byte(rev.Content), question); err != nil {
+			return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		}
+		tags := make([]*schema.TagItem, 0, len(question.Tags))
+		for _, tag := range question.Tags {
+			tags = append(tags, &schema.TagItem{SlugName: tag.SlugName, DisplayName: tag.DisplayName})
+		}
+		dto.Question = &schema.QuestionUpdate{
+			ID:          rev.ObjectID,
+			Title:       question.Title,
+			Content:     question.OriginalText,
+			HTML:        converter.Markdown2HTML(question.OriginalText),
+			Tags:        tags,
+			EditSummary: req.EditSummary,
+			UserID:      req.UserID,
+		}
+	case constant.AnswerObjectType:
+		answer := &entity.Answer{}
+		if err = json.Unmarshal([]byte(rev.Content), answer); err != nil {
+			return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		}
+		dto.Answer = &schema.AnswerUpdateReq{
+			ID:          rev.ObjectID,
+			Content:     answer.OriginalText,
+			HTML:        converter.Markdown2HTML(answer.OriginalText),
+			EditSummary: req.EditSummary,
+			UserID:      req.UserID,
+		}
+	case constant.TagObjectType:
+		tag := &entity.Tag{}
+		if err = json.Unmarshal([]byte(rev.Content), tag); err != nil {
+			return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		}
+		dto.Tag = &schema.UpdateTagReq{
+			TagID:        rev.ObjectID,
+			SlugName:     tag.SlugName,
+			DisplayName:  tag.DisplayName,
+			OriginalText: tag.OriginalText,
+			ParsedText:   converter.Markdown2HTML(tag.OriginalText),
+			EditSummary:  req.EditSummary,
+			UserID:       req.UserID,
+		}
+	default:
+		return nil, errors.BadRequest(reason.ObjectNotFound)
+	}
+	return dto, nil
+}
+
+// getAvailableRevision get the revision which is in effect, the unreviewed and rejected revisions are not available
+func (rs *RevisionService) getAvailableRevision(ctx context.Context, revisionID string) (rev *entity.Revision, err error) {
+	rev, exist, err := rs.revisionRepo.GetRevisionByID(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || (rev.Status != entity.RevisionNormalStatus && rev.Status != entity.RevisionReviewPassStatus) {
+		return nil, errors.BadRequest(reason.ObjectNotFound)
+	}
+	return rev, nil
+}
+
+// parseRevisionSnapshot get the title, content and tags from the revision content.
+// The answer has no title, and the tag's slug name is regarded as its tags.
+func parseRevisionSnapshot(rev *entity.Revision) (title, content string, tags []string, err error) {
+	switch constant.ObjectTypeNumberMapping[rev.ObjectType] {
+	case constant.QuestionObjectType:
+		question := &entity.QuestionWithTagsRevision{}
+		if err = json.Unmarshal([]byte(rev.Content), question); err != nil {
+			break
+		}
+		for _, tag := range question.Tags {
+			tags = append(tags, tag.SlugName)
+		}
+		return question.Title, question.OriginalText, tags, nil
+	case constant.AnswerObjectType:
+		answer := &entity.Answer{}
+		if err = json.Unmarshal([]byte(rev.Content), answer); err != nil {
+			break
+		}
+		return "", answer.OriginalText, nil, nil
+	case constant.TagObjectType:
+		tag := &entity.Tag{}
+		if err = json.Unmarshal([]byte(rev.Content), tag); err != nil {
+			break
+		}
+		return tag.DisplayName, tag.OriginalText, []string{tag.SlugName}, nil
+	}
+	if err != nil {
+		return "", "", nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	return "", "", nil, errors.BadRequest(reason.ObjectNotFound)
+}
@@ -21,25 +21,77 @@ package content
 
 import (
 	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/embedding"
 	"github.com/apache/answer/internal/service/search_common"
 	"github.com/apache/answer/internal/service/search_parser"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/space_common"
+	tagcommon "github.com/apache/answer/internal/service/tag_common"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/pkg/uid"
 	"github.com/apache/answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
 )
 
+// hybridCandidateSize the number of candidates from keyword and semantic search to be fused
+const hybridCandidateSize = 100
+
+const (
+	// duplicateCandidateSize the number of candidates from keyword and semantic search to check the duplication
+	duplicateCandidateSize = 20
+	// duplicateResultSize the max number of the possible duplicate questions returned
+	duplicateResultSize = 5
+	// duplicateMinConfidence the questions with lower confidence are not considered as duplicates
+	duplicateMinConfidence = 0.3
+)
+
+// titleStopwords the common words of the question titles that are not counted in the duplicate check
+var titleStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"can": true, "do": true, "does": true, "for": true, "from": true, "how": true, "i": true, "in": true,
+	"is": true, "it": true, "my": true, "of": true, "on": true, "or": true, "the": true, "this": true,
+	"to": true, "what": true, "when": true, "why": true, "with": true,
+}
+
 type SearchService struct {
-	searchParser *search_parser.SearchParser
-	searchRepo   search_common.SearchRepo
+	searchParser          *search_parser.SearchParser
+	searchRepo            search_common.SearchRepo
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService
+	embeddingService      *embedding.EmbeddingService
+	tagCommonService      *tagcommon.TagCommonService
+	userCommon            *usercommon.UserCommon
+	spaceCommon           *space_common.SpaceCommon
 }
 
 func NewSearchService(
 	searchParser *search_parser.SearchParser,
 	searchRepo search_common.SearchRepo,
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+	embeddingService *embedding.EmbeddingService,
+	tagCommonService *tagcommon.TagCommonService,
+	userCommon *usercommon.UserCommon,
+	spaceCommon *space_common.SpaceCommon,
 ) *SearchService {
 	return &SearchService{
-		searchParser: searchParser,
-		searchRepo:   searchRepo,
+		searchParser:          searchParser,
+		searchRepo:            searchRepo,
+		siteInfoCommonService: siteInfoCommonService,
+		embeddingService:      embeddingService,
+		tagCommonService:      tagCommonService,
+		userCommon:            userCommon,
+		spaceCommon:           spaceCommon,
 	}
 }
 
@@ -51,13 +103,162 @@ func (ss *SearchService) Search(ctx context.Context, dto *schema.SearchDTO) (res
 	if len(dto.Query) == 0 {
 		return &schema.SearchResp{
 			Total:         0,
+			Mode:          schema.SearchModeKeyword,
 			SearchResults: make([]*schema.SearchResult, 0),
 		}, nil
 	}
 
+	if dto.SpaceID != entity.PublicSpaceID {
+		canView, err := ss.spaceCommon.CanViewSpace(ctx, dto.SpaceID, dto.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			return nil, errors.NotFound(reason.SpaceNotFound)
+		}
+	}
+
 	// search type
 	cond := ss.searchParser.ParseStructure(ctx, dto)
+	cond.SpaceID = dto.SpaceID
+	resp, err = ss.search(ctx, dto, cond)
+	if err != nil {
+		return nil, err
+	}
+	if dto.Facets {
+		resp.Facets, err = ss.searchFacets(ctx, cond)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (ss *SearchService) search(ctx context.Context, dto *schema.SearchDTO, cond *schema.SearchCondition) (
+	resp *schema.SearchResp, err error) {
+	if ss.canHybridSearch(ctx, dto, cond) {
+		resp, ok, err := ss.hybridSearch(ctx, cond, dto)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return resp, nil
+		}
+	}
+	resp, err = ss.keywordSearch(ctx, cond, dto.Page, dto.Size, dto.Order)
+	if resp != nil {
+		resp.Mode = schema.SearchModeKeyword
+	}
+	return resp, err
+}
+
+// searchFacets aggregate the contents matched by keywords, by the search plugin if it supports facets,
+// or the system search if the plugin is not found. The facets are not available for other search plugins.
+func (ss *SearchService) searchFacets(ctx context.Context, cond *schema.SearchCondition) (
+	resp *schema.SearchFacetsResp, err error) {
+	var finder plugin.Search
+	_ = plugin.CallSearch(func(search plugin.Search) error {
+		finder = search
+		return nil
+	})
+
+	var facets *plugin.SearchFacets
+	if finder == nil || cond.SpaceID != entity.PublicSpaceID || !plugin.StatusManager.IsEnabledFor(ctx, finder.Info().SlugName) {
+		facets, err = ss.searchRepo.SearchFacets(ctx, cond)
+	} else if facetFinder, ok := finder.(plugin.SearchFacetFinder); ok {
+		facets, err = facetFinder.SearchFacets(ctx, cond.TargetType, cond.Convert2PluginSearchCond(ctx, 1, 0, ""))
+	} else {
+		return nil, nil
+	}
+	if err != nil || facets == nil {
+		return nil, err
+	}
+	return ss.formatFacets(ctx, facets)
+}
+
+// formatFacets convert the tag and user ids of the facets to their names
+func (ss *SearchService) formatFacets(ctx context.Context, facets *plugin.SearchFacets) (
+	resp *schema.SearchFacetsResp, err error) {
+	resp = &schema.SearchFacetsResp{
+		Tags:    make([]*schema.SearchFacetItem, 0, len(facets.Tags)),
+		Authors: make([]*schema.SearchFacetItem, 0, len(facets.Users)),
+		Accepted: []*schema.SearchFacetItem{
+			{Key: "accepted", Count: facets.Accepted},
+			{Key: "unaccepted", Count: facets.Total - facets.Accepted},
+		},
+		Answered: []*schema.SearchFacetItem{
+			{Key: "answered", Count: facets.Answered},
+			{Key: "unanswered", Count: facets.Total - facets.Answered},
+		},
+		Created: make([]*schema.SearchFacetItem, 0, len(facets.Created)),
+	}
+	for _, item := range facets.Created {
+		resp.Created = append(resp.Created, &schema.SearchFacetItem{Key: item.Value, Count: item.Count})
+	}
 
+	if len(facets.Tags) > 0 {
+		tagIDs := make([]string, 0, len(facets.Tags))
+		for _, item := range facets.Tags {
+			tagIDs = append(tagIDs, item.Value)
+		}
+		tagList, err := ss.tagCommonService.GetTagListByIDs(ctx, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+		tagMapping := make(map[string]*entity.Tag, len(tagList))
+		for _, tag := range tagList {
+			tagMapping[tag.ID] = tag
+		}
+		for _, item := range facets.Tags {
+			if tag, ok := tagMapping[item.Value]; ok {
+				resp.Tags = append(resp.Tags, &schema.SearchFacetItem{
+					Key: tag.SlugName, Name: tag.DisplayName, Count: item.Count})
+			}
+		}
+	}
+
+	if len(facets.Users) > 0 {
+		userIDs := make([]string, 0, len(facets.Users))
+		for _, item := range facets.Users {
+			userIDs = append(userIDs, item.Value)
+		}
+		userMapping, err := ss.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range facets.Users {
+			if user, ok := userMapping[item.Value]; ok {
+				resp.Authors = append(resp.Authors, &schema.SearchFacetItem{
+					Key: user.Username, Name: user.DisplayName, Count: item.Count})
+			}
+		}
+	}
+	return resp, nil
+}
+
+// SearchNewContents search the contents matched by the query and created in the time range, the newest first.
+// It is used to check the new contents of the saved searches, so only the keyword search is used.
+func (ss *SearchService) SearchNewContents(ctx context.Context, query, userID string, created plugin.SearchTimeRange,
+	page, size int) (results []*schema.SearchResult, err error) {
+	cond := ss.searchParser.ParseStructure(ctx, &schema.SearchDTO{Query: query, UserID: userID})
+	// the created range of the query is narrowed down to the time range
+	cond.Created.From = max(cond.Created.From, created.From)
+	if cond.Created.To == 0 || cond.Created.To > created.To {
+		cond.Created.To = created.To
+	}
+	if cond.Created.From >= cond.Created.To {
+		return nil, nil
+	}
+	resp, err := ss.keywordSearch(ctx, cond, page, size, string(plugin.SearchNewestOrder))
+	if err != nil {
+		return nil, err
+	}
+	return resp.SearchResults, nil
+}
+
+// keywordSearch search by the search plugin, or the system search if the plugin is not found
+func (ss *SearchService) keywordSearch(ctx context.Context, cond *schema.SearchCondition, page, size int, order string) (
+	resp *schema.SearchResp, err error) {
 	// check search plugin
 	var finder plugin.Search
 	_ = plugin.CallSearch(func(search plugin.Search) error {
@@ -66,34 +267,35 @@ func (ss *SearchService) Search(ctx context.Context, dto *schema.SearchDTO) (res
 	})
 
 	resp = &schema.SearchResp{}
-	// search plugin is not found, call system search
-	if finder == nil {
+	// search plugin is not found, call system search. The contents of the spaces are not in the search plugin.
+	if finder == nil || cond.SpaceID != entity.PublicSpaceID || !plugin.StatusManager.IsEnabledFor(ctx, finder.Info().SlugName) {
 		switch {
 		case cond.SearchAll():
 			resp.SearchResults, resp.Total, err =
-				ss.searchRepo.SearchContents(ctx, cond.Words, cond.Tags, cond.UserID, cond.VoteAmount, dto.Page, dto.Size, dto.Order)
+				ss.searchRepo.SearchContents(ctx, cond, page, size, order)
 		case cond.SearchQuestion():
 			resp.SearchResults, resp.Total, err =
-				ss.searchRepo.SearchQuestions(ctx, cond.Words, cond.Tags, cond.NotAccepted, cond.Views, cond.AnswerAmount, dto.Page, dto.Size, dto.Order)
+				ss.searchRepo.SearchQuestions(ctx, cond, page, size, order)
 		case cond.SearchAnswer():
 			resp.SearchResults, resp.Total, err =
-				ss.searchRepo.SearchAnswers(ctx, cond.Words, cond.Tags, cond.Accepted, cond.QuestionID, dto.Page, dto.Size, dto.Order)
+				ss.searchRepo.SearchAnswers(ctx, cond, page, size, order)
 		}
 		return
 	}
-	return ss.searchByPlugin(ctx, finder, cond, dto)
+	return ss.searchByPlugin(ctx, finder, cond, page, size, order)
 }
 
-func (ss *SearchService) searchByPlugin(ctx context.Context, finder plugin.Search, cond *schema.SearchCondition, dto *schema.SearchDTO) (resp *schema.SearchResp, err error) {
+func (ss *SearchService) searchByPlugin(ctx context.Context, finder plugin.Search, cond *schema.SearchCondition,
+	page, size int, order string) (resp *schema.SearchResp, err error) {
 	var res []plugin.SearchResult
 	resp = &schema.SearchResp{}
 	switch {
 	case cond.SearchAll():
-		res, resp.Total, err = finder.SearchContents(ctx, cond.Convert2PluginSearchCond(dto.Page, dto.Size, dto.Order))
+		res, resp.Total, err = finder.SearchContents(ctx, cond.Convert2PluginSearchCond(ctx, page, size, order))
 	case cond.SearchQuestion():
-		res, resp.Total, err = finder.SearchQuestions(ctx, cond.Convert2PluginSearchCond(dto.Page, dto.Size, dto.Order))
+		res, resp.Total, err = finder.SearchQuestions(ctx, cond.Convert2PluginSearchCond(ctx, page, size, order))
 	case cond.SearchAnswer():
-		res, resp.Total, err = finder.SearchAnswers(ctx, cond.Convert2PluginSearchCond(dto.Page, dto.Size, dto.Order))
+		res, resp.Total, err = finder.SearchAnswers(ctx, cond.Convert2PluginSearchCond(ctx, page, size, order))
 	}
 	if err != nil {
 		return resp, err
@@ -102,3 +304,280 @@ func (ss *SearchService) searchByPlugin(ctx context.Context, finder plugin.Searc
 	resp.SearchResults, err = ss.searchRepo.ParseSearchPluginResult(ctx, res, cond.Words)
 	return resp, err
 }
+
+// canHybridSearch the hybrid search is only used for sorting by relevance without any filters
+func (ss *SearchService) canHybridSearch(ctx context.Context, dto *schema.SearchDTO, cond *schema.SearchCondition) bool {
+	if dto.Mode == schema.SearchModeKeyword || dto.Order != "relevance" || len(cond.Words) == 0 || !cond.IsPlainText() {
+		return false
+	}
+	searchConfig, err := ss.siteInfoCommonService.GetSiteSearch(ctx)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	return searchConfig.HybridEnabled
+}
+
+// hybridSearch fuse the keyword and semantic search results by reciprocal rank fusion.
+// If the semantic search is not available, return false to fall back to keyword search.
+func (ss *SearchService) hybridSearch(ctx context.Context, cond *schema.SearchCondition, dto *schema.SearchDTO) (
+	resp *schema.SearchResp, ok bool, err error) {
+	vectorResults, err := ss.embeddingService.SearchSimilar(ctx, dto.Query, hybridCandidateSize)
+	if err != nil {
+		log.Debugf("semantic search is not available, use keyword search: %v", err)
+		return nil, false, nil
+	}
+	searchConfig, err := ss.siteInfoCommonService.GetSiteSearch(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	keywordResp, err := ss.keywordSearch(ctx, cond, 1, hybridCandidateSize, dto.Order)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the keyword results are loaded, the semantic results only have the id and type
+	keywordIDs := make([]string, 0, len(keywordResp.SearchResults))
+	loaded := make(map[string]*schema.SearchResult, len(keywordResp.SearchResults))
+	for _, item := range keywordResp.SearchResults {
+		id := uid.DeShortID(item.Object.ID)
+		keywordIDs = append(keywordIDs, id)
+		loaded[id] = item
+	}
+	semanticIDs := make([]string, 0, len(vectorResults))
+	objectTypes := make(map[string]string, len(vectorResults))
+	for _, item := range vectorResults {
+		if (cond.SearchQuestion() && item.ObjectType != constant.QuestionObjectType) ||
+			(cond.SearchAnswer() && item.ObjectType != constant.AnswerObjectType) {
+			continue
+		}
+		semanticIDs = append(semanticIDs, item.ObjectID)
+		objectTypes[item.ObjectID] = item.ObjectType
+	}
+
+	fused := fuseByRRF(searchConfig.RRFK,
+		&rankedList{ids: keywordIDs, weight: searchConfig.KeywordWeight},
+		&rankedList{ids: semanticIDs, weight: searchConfig.SemanticWeight},
+	)
+
+	resp = &schema.SearchResp{
+		Total:         int64(len(fused)),
+		Mode:          schema.SearchModeHybrid,
+		SearchResults: make([]*schema.SearchResult, 0),
+	}
+	start := (dto.Page - 1) * dto.Size
+	if start >= len(fused) {
+		return resp, true, nil
+	}
+	pageIDs := fused[start:min(start+dto.Size, len(fused))]
+
+	unloaded := make([]plugin.SearchResult, 0)
+	for _, id := range pageIDs {
+		if _, ok := loaded[id]; !ok {
+			unloaded = append(unloaded, plugin.SearchResult{ID: id, Type: objectTypes[id]})
+		}
+	}
+	if len(unloaded) > 0 {
+		results, err := ss.searchRepo.ParseSearchPluginResult(ctx, unloaded, cond.Words)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, item := range results {
+			loaded[uid.DeShortID(item.Object.ID)] = item
+		}
+	}
+	// the deleted contents are not loaded, skip them
+	for _, id := range pageIDs {
+		if item, ok := loaded[id]; ok {
+			resp.SearchResults = append(resp.SearchResults, item)
+		}
+	}
+	return resp, true, nil
+}
+
+// rankedList is a list of ids ordered by rank and its weight in fusion
+type rankedList struct {
+	ids    []string
+	weight float64
+}
+
+// fuseByRRF merge the ranked lists by reciprocal rank fusion, the score of an id is sum(weight / (k + rank)).
+// The ids with the same score are ordered by their first appearance in the lists.
+func fuseByRRF(k int, lists ...*rankedList) (ids []string) {
+	scores := make(map[string]float64)
+	order := make(map[string]int)
+	for _, list := range lists {
+		for rank, id := range list.ids {
+			if _, ok := order[id]; !ok {
+				order[id] = len(order)
+				ids = append(ids, id)
+			}
+			scores[id] += list.weight / float64(k+rank+1)
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return order[ids[i]] < order[ids[j]]
+	})
+	return ids
+}
+
+// FindDuplicateQuestions find the existing questions that the question draft may duplicate. The confidence is
+// the weighted average of the title terms overlap and the semantic similarity if a vector search plugin is enabled.
+func (ss *SearchService) FindDuplicateQuestions(ctx context.Context, req *schema.CheckDuplicateQuestionReq) (
+	resp []*schema.DuplicateQuestionResp, err error) {
+	resp = make([]*schema.DuplicateQuestionResp, 0)
+	reqTerms := titleTerms(req.Title)
+	if len(reqTerms) == 0 {
+		return resp, nil
+	}
+	searchConfig, err := ss.siteInfoCommonService.GetSiteSearch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the questions that match any term of the title
+	terms := make([]*plugin.SearchExpr, 0, len(reqTerms))
+	for _, term := range reqTerms {
+		terms = append(terms, &plugin.SearchExpr{Type: plugin.SearchExprTerm, Value: term})
+	}
+	cond := &schema.SearchCondition{
+		TargetType:   constant.QuestionObjectType,
+		VoteAmount:   -1,
+		Views:        -1,
+		AnswerAmount: -1,
+		Words:        reqTerms,
+		Expr:         &plugin.SearchExpr{Type: plugin.SearchExprOr, Children: terms},
+	}
+	keywordResp, err := ss.keywordSearch(ctx, cond, 1, duplicateCandidateSize, "relevance")
+	if err != nil {
+		return nil, err
+	}
+	candidates := make(map[string]*schema.SearchObject, len(keywordResp.SearchResults))
+	order := make([]string, 0, len(keywordResp.SearchResults))
+	for _, item := range keywordResp.SearchResults {
+		id := uid.DeShortID(item.Object.ID)
+		candidates[id] = item.Object
+		order = append(order, id)
+	}
+
+	semanticScores, semanticEnabled := ss.getSemanticScores(ctx, req.Title+"\n"+req.Content)
+	unloaded := make([]plugin.SearchResult, 0)
+	for id := range semanticScores {
+		if _, ok := candidates[id]; !ok {
+			unloaded = append(unloaded, plugin.SearchResult{ID: id, Type: constant.QuestionObjectType})
+		}
+	}
+	if len(unloaded) > 0 {
+		results, err := ss.searchRepo.ParseSearchPluginResult(ctx, unloaded, reqTerms)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range results {
+			id := uid.DeShortID(item.Object.ID)
+			candidates[id] = item.Object
+			order = append(order, id)
+		}
+	}
+
+	confidences := make(map[string]float64, len(candidates))
+	for _, id := range order {
+		keywordScore := termsOverlap(reqTerms, titleTerms(candidates[id].Title))
+		confidence := keywordScore
+		if semanticEnabled {
+			confidence = blendConfidence(keywordScore, semanticScores[id], searchConfig.KeywordWeight, searchConfig.SemanticWeight)
+		}
+		if confidence >= duplicateMinConfidence {
+			confidences[id] = confidence
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return confidences[order[i]] > confidences[order[j]]
+	})
+	for _, id := range order {
+		if _, ok := confidences[id]; !ok || len(resp) >= duplicateResultSize {
+			continue
+		}
+		object := candidates[id]
+		resp = append(resp, &schema.DuplicateQuestionResp{
+			ID:          object.ID,
+			Title:       object.Title,
+			UrlTitle:    object.UrlTitle,
+			AnswerCount: object.AnswerCount,
+			Accepted:    object.Accepted,
+			Status:      object.StatusStr,
+			Confidence:  math.Round(confidences[id]*100) / 100,
+		})
+	}
+	return resp, nil
+}
+
+// getSemanticScores get the max similarity of the questions and their answers to the text,
+// return false if the vector search plugin is not enabled.
+func (ss *SearchService) getSemanticScores(ctx context.Context, text string) (scores map[string]float64, ok bool) {
+	scores = make(map[string]float64)
+	results, err := ss.embeddingService.SearchSimilar(ctx, text, duplicateCandidateSize)
+	if err != nil {
+		log.Debugf("semantic search is not available, check duplicates by keywords: %v", err)
+		return scores, false
+	}
+	for _, result := range results {
+		questionID := result.ObjectID
+		if result.ObjectType != constant.QuestionObjectType {
+			meta := &plugin.VectorSearchMetadata{}
+			if err := json.Unmarshal([]byte(result.Metadata), meta); err != nil || len(meta.QuestionID) == 0 {
+				continue
+			}
+			questionID = meta.QuestionID
+		}
+		questionID = uid.DeShortID(questionID)
+		scores[questionID] = math.Max(scores[questionID], result.Score)
+	}
+	return scores, true
+}
+
+// titleTerms split the title into distinct lowercase words, the stopwords and the single letters are dropped
+func titleTerms(title string) (terms []string) {
+	terms = make([]string, 0)
+	seen := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+#.-_", r)
+	})
+	for _, word := range words {
+		word = strings.Trim(word, ".-_")
+		if utf8.RuneCountInString(word) < 2 || titleStopwords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// termsOverlap the dice coefficient of the two term sets, from 0 to 1
+func termsOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, term := range a {
+		set[term] = true
+	}
+	common := 0
+	for _, term := range b {
+		if set[term] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// blendConfidence the weighted average of the keyword and semantic scores, equally weighted if both weights are zero
+func blendConfidence(keywordScore, semanticScore, keywordWeight, semanticWeight float64) float64 {
+	if keywordWeight+semanticWeight <= 0 {
+		keywordWeight, semanticWeight = 1, 1
+	}
+	return (keywordScore*keywordWeight + semanticScore*semanticWeight) / (keywordWeight + semanticWeight)
+}
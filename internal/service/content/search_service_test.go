@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuseByRRF(t *testing.T) {
+	keyword := &rankedList{ids: []string{"a", "b", "c"}, weight: 1}
+	semantic := &rankedList{ids: []string{"c", "d", "a"}, weight: 1}
+
+	// a: 1/61 + 1/63, c: 1/63 + 1/61, the tie is broken by the first appearance
+	assert.Equal(t, []string{"a", "c", "b", "d"}, fuseByRRF(60, keyword, semantic))
+
+	// the semantic results are more important
+	semantic.weight = 3
+	assert.Equal(t, []string{"c", "a", "d", "b"}, fuseByRRF(60, keyword, semantic))
+
+	// the semantic results are ignored
+	semantic.weight = 0
+	assert.Equal(t, []string{"a", "b", "c", "d"}, fuseByRRF(60, keyword, semantic))
+
+	assert.Empty(t, fuseByRRF(60, &rankedList{weight: 1}, &rankedList{weight: 1}))
+}
+
+func TestTitleTerms(t *testing.T) {
+	assert.Equal(t, []string{"use", "c++", "node.js", "release"}, titleTerms("How to use C++ with Node.js? The release."))
+}
+
+func TestTermsOverlap(t *testing.T) {
+	assert.Equal(t, 1.0, termsOverlap([]string{"go", "channel"}, []string{"channel", "go"}))
+	assert.Equal(t, 0.5, termsOverlap([]string{"go", "channel"}, []string{"go", "map"}))
+	assert.Equal(t, 0.0, termsOverlap([]string{"go"}, nil))
+}
+
+func TestBlendConfidence(t *testing.T) {
+	assert.InDelta(t, 0.6, blendConfidence(0.4, 0.8, 1, 1), 1e-9)
+	assert.InDelta(t, 0.7, blendConfidence(0.4, 0.8, 1, 3), 1e-9)
+	// both weights are zero, the scores are equally weighted
+	assert.InDelta(t, 0.6, blendConfidence(0.4, 0.8, 0, 0), 1e-9)
+}
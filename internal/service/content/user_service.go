@@ -45,6 +45,7 @@ import (
 	"github.com/apache/answer/internal/service/file_record"
 	"github.com/apache/answer/internal/service/role"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/two_factor"
 	usercommon "github.com/apache/answer/internal/service/user_common"
 	"github.com/apache/answer/internal/service/user_external_login"
 	"github.com/apache/answer/pkg/checker"
@@ -70,6 +71,7 @@ type UserService struct {
 	questionService               *questioncommon.QuestionCommon
 	eventQueueService             eventqueue.Service
 	fileRecordService             *file_record.FileRecordService
+	twoFactorService              *two_factor.TwoFactorService
 }
 
 func NewUserService(userRepo usercommon.UserRepo,
@@ -86,6 +88,7 @@ func NewUserService(userRepo usercommon.UserRepo,
 	questionService *questioncommon.QuestionCommon,
 	eventQueueService eventqueue.Service,
 	fileRecordService *file_record.FileRecordService,
+	twoFactorService *two_factor.TwoFactorService,
 ) *UserService {
 	return &UserService{
 		userCommonService:             userCommonService,
@@ -102,6 +105,7 @@ func NewUserService(userRepo usercommon.UserRepo,
 		questionService:               questionService,
 		eventQueueService:             eventQueueService,
 		fileRecordService:             fileRecordService,
+		twoFactorService:              twoFactorService,
 	}
 }
 
@@ -180,6 +184,89 @@ func (us *UserService) EmailLogin(ctx context.Context, req *schema.UserEmailLogi
 		return nil, errors.BadRequest(reason.EmailOrPasswordWrong)
 	}
 
+	// The user is not signed in until the second factor is verified.
+	challenge, err := us.twoFactorService.NewLoginChallenge(ctx, userInfo.ID, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &schema.UserLoginResp{TwoFactor: challenge}, nil
+	}
+	return us.login(ctx, userInfo, externalID)
+}
+
+// TwoFactorLogin finish signing in with the second factor
+func (us *UserService) TwoFactorLogin(ctx context.Context, req *schema.TwoFactorLoginReq) (
+	resp *schema.UserLoginResp, err error) {
+	challenge, err := us.twoFactorService.VerifyLoginChallenge(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	userInfo, err := us.getLoginChallengeUser(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	return us.login(ctx, userInfo, challenge.ExternalID)
+}
+
+// TwoFactorEnrollLogin enable the authenticator app required by the role of the user, and finish signing in
+func (us *UserService) TwoFactorEnrollLogin(ctx context.Context, req *schema.TwoFactorEnrollLoginReq) (
+	resp *schema.TwoFactorEnrollLoginResp, err error) {
+	challenge, codes, err := us.twoFactorService.EnableTOTPForLogin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	userInfo, err := us.getLoginChallengeUser(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	resp = &schema.TwoFactorEnrollLoginResp{RecoveryCodes: codes.RecoveryCodes}
+	resp.UserLoginResp, err = us.login(ctx, userInfo, challenge.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (us *UserService) getLoginChallengeUser(ctx context.Context, challenge *schema.TwoFactorLoginChallenge) (
+	userInfo *entity.User, err error) {
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || userInfo.Status == entity.UserStatusDeleted {
+		return nil, errors.BadRequest(reason.TwoFactorChallengeInvalid)
+	}
+	return userInfo, nil
+}
+
+// Reauth confirm the identity of the signed in user before a sensitive action.
+// The users with a second factor must use it, the password alone is not enough for them.
+func (us *UserService) Reauth(ctx context.Context, req *schema.UserReauthReq) (err error) {
+	hasTwoFactor, err := us.twoFactorService.HasTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if req.Method == schema.TwoFactorMethodPassword {
+		if hasTwoFactor {
+			return errors.BadRequest(reason.RecentAuthSecondFactorRequired)
+		}
+		userInfo, exist, err := us.userRepo.GetByUserID(ctx, req.UserID)
+		if err != nil {
+			return err
+		}
+		if !exist || len(userInfo.Pass) == 0 || len(req.Pass) == 0 || !us.verifyPassword(ctx, req.Pass, userInfo.Pass) {
+			return errors.BadRequest(reason.RecentAuthPasswordWrong)
+		}
+	} else if err = us.twoFactorService.Reauth(ctx, req.UserID, req.VerifyReq()); err != nil {
+		return err
+	}
+	return us.authService.SetUserRecentAuth(ctx, req.AccessToken)
+}
+
+// login sign in the user, whose identity is verified
+func (us *UserService) login(ctx context.Context, userInfo *entity.User, externalID string) (
+	resp *schema.UserLoginResp, err error) {
 	err = us.userRepo.UpdateLastLoginDate(ctx, userInfo.ID)
 	if err != nil {
 		log.Errorf("update last login data failed, err: %v", err)
@@ -668,7 +755,30 @@ func (us *UserService) UserVerifyEmail(ctx context.Context, req *schema.UserVeri
 		}
 	}
 
-	accessToken, userCacheInfo, err := us.userCommonService.CacheLoginUserInfo(
+	roleID, err := us.userRoleService.GetUserRole(ctx, userInfo.ID)
+	if err != nil {
+		log.Error(err)
+	}
+	// User verified email will update user email status. So user status cache should be updated.
+	err = us.authService.SetUserStatus(ctx, &entity.UserCacheInfo{
+		UserID:      userInfo.ID,
+		EmailStatus: userInfo.MailStatus,
+		UserStatus:  userInfo.Status,
+		RoleID:      roleID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The user is not signed in until the second factor is verified.
+	challenge, err := us.twoFactorService.NewLoginChallenge(ctx, userInfo.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &schema.UserLoginResp{TwoFactor: challenge}, nil
+	}
+	accessToken, _, err := us.userCommonService.CacheLoginUserInfo(
 		ctx, userInfo.ID, userInfo.MailStatus, userInfo.Status, "")
 	if err != nil {
 		return nil, err
@@ -678,10 +788,6 @@ func (us *UserService) UserVerifyEmail(ctx context.Context, req *schema.UserVeri
 	resp.ConvertFromUserEntity(userInfo)
 	resp.Avatar = us.siteInfoService.FormatAvatar(ctx, userInfo.Avatar, userInfo.EMail, userInfo.Status).GetURL()
 	resp.AccessToken = accessToken
-	// User verified email will update user email status. So user status cache should be updated.
-	if err = us.authService.SetUserStatus(ctx, userCacheInfo); err != nil {
-		return nil, err
-	}
 	return resp, nil
 }
 
@@ -809,12 +915,21 @@ func (us *UserService) UserChangeEmailVerify(ctx context.Context, content string
 		UserStatus:  userInfo.Status,
 		RoleID:      roleID,
 	}
-	resp.AccessToken, resp.VisitToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
+	// User verified email will update user email status. So user status cache should be updated.
+	if err = us.authService.SetUserStatus(ctx, userCacheInfo); err != nil {
+		return nil, err
+	}
+
+	// The user is not signed in until the second factor is verified.
+	challenge, err := us.twoFactorService.NewLoginChallenge(ctx, userInfo.ID, "")
 	if err != nil {
 		return nil, err
 	}
-	// User verified email will update user email status. So user status cache should be updated.
-	if err = us.authService.SetUserStatus(ctx, userCacheInfo); err != nil {
+	if challenge != nil {
+		return &schema.UserLoginResp{TwoFactor: challenge}, nil
+	}
+	resp.AccessToken, resp.VisitToken, err = us.authService.SetUserCacheInfo(ctx, userCacheInfo)
+	if err != nil {
 		return nil, err
 	}
 	resp.RoleID = userCacheInfo.RoleID
@@ -113,7 +113,7 @@ func (ds *dashboardService) Statistical(ctx context.Context) (*schema.DashboardI
 		dashboardInfo.CommentCount = ds.commentCount(ctx)
 		dashboardInfo.UserCount = ds.userCount(ctx)
 		dashboardInfo.VoteCount = ds.voteCount(ctx)
-		dashboardInfo.OccupyingStorageSpace = ds.calculateStorage()
+		dashboardInfo.OccupyingStorageSpace = ds.calculateStorage(ctx)
 		if security.CheckUpdate {
 			dashboardInfo.VersionInfo.RemoteVersion = ds.remoteVersion(ctx)
 		}
@@ -331,8 +331,8 @@ func (ds *dashboardService) getTimezone(ctx context.Context) string {
 	return siteInfoInterface.TimeZone
 }
 
-func (ds *dashboardService) calculateStorage() string {
-	dirSize, err := dir.DirSize(ds.serviceConfig.UploadPath)
+func (ds *dashboardService) calculateStorage(ctx context.Context) string {
+	dirSize, err := dir.DirSize(ds.serviceConfig.GetUploadPath(ctx))
 	if err != nil {
 		log.Errorf("get upload dir size failed: %s", err)
 		return ""
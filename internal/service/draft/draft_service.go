@@ -0,0 +1,248 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// draftLimit the max number of drafts of a user
+	draftLimit = 100
+	// newQuestionObjectID the object id of the new question draft
+	newQuestionObjectID = "0"
+)
+
+type DraftRepo interface {
+	AddDraft(ctx context.Context, draftInfo *entity.Draft) (err error)
+	UpdateDraft(ctx context.Context, draftInfo *entity.Draft) (err error)
+	DeleteDraft(ctx context.Context, userID string, id int) (err error)
+	DeleteDraftByObject(ctx context.Context, userID, draftType, objectID string) (err error)
+	DeleteDraftsUpdatedBefore(ctx context.Context, before time.Time) (affected int64, err error)
+	GetDraft(ctx context.Context, userID string, id int) (draftInfo *entity.Draft, exist bool, err error)
+	GetDraftByObject(ctx context.Context, userID, draftType, objectID string) (
+		draftInfo *entity.Draft, exist bool, err error)
+	GetDraftPage(ctx context.Context, userID string, page, pageSize int) (list []*entity.Draft, total int64, err error)
+	CountDraft(ctx context.Context, userID string) (count int64, err error)
+}
+
+// DraftService the unsubmitted posts of users saved on the server
+type DraftService struct {
+	draftRepo             DraftRepo
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService
+}
+
+// NewDraftService new draft service
+func NewDraftService(
+	draftRepo DraftRepo,
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+) *DraftService {
+	return &DraftService{
+		draftRepo:             draftRepo,
+		siteInfoCommonService: siteInfoCommonService,
+	}
+}
+
+// SaveDraft create the draft of the user for the draft type and object, or update it if it already exists
+func (ds *DraftService) SaveDraft(ctx context.Context, req *schema.SaveDraftReq) (resp *schema.DraftResp, err error) {
+	objectID, err := draftObjectID(req.DraftType, req.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	draftInfo, exist, err := ds.draftRepo.GetDraftByObject(ctx, req.UserID, req.DraftType, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		draftInfo.Title = req.Title
+		draftInfo.Content = req.Content
+		draftInfo.Tags = encodeDraftTags(req.Tags)
+		if err = ds.draftRepo.UpdateDraft(ctx, draftInfo); err != nil {
+			return nil, err
+		}
+		return ds.convertDraftResp(ctx, draftInfo), nil
+	}
+
+	count, err := ds.draftRepo.CountDraft(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= draftLimit {
+		return nil, errors.BadRequest(reason.DraftLimitExceeded)
+	}
+	draftInfo = &entity.Draft{
+		UserID:    req.UserID,
+		DraftType: req.DraftType,
+		ObjectID:  objectID,
+		Title:     req.Title,
+		Content:   req.Content,
+		Tags:      encodeDraftTags(req.Tags),
+	}
+	if err = ds.draftRepo.AddDraft(ctx, draftInfo); err != nil {
+		return nil, err
+	}
+	return ds.convertDraftResp(ctx, draftInfo), nil
+}
+
+// UpdateDraft update the draft of the user by id
+func (ds *DraftService) UpdateDraft(ctx context.Context, req *schema.UpdateDraftReq) (resp *schema.DraftResp, err error) {
+	draftInfo, exist, err := ds.draftRepo.GetDraft(ctx, req.UserID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.DraftNotFound)
+	}
+	draftInfo.Title = req.Title
+	draftInfo.Content = req.Content
+	draftInfo.Tags = encodeDraftTags(req.Tags)
+	if err = ds.draftRepo.UpdateDraft(ctx, draftInfo); err != nil {
+		return nil, err
+	}
+	return ds.convertDraftResp(ctx, draftInfo), nil
+}
+
+// GetDraft get the draft of the user for the draft type and object
+func (ds *DraftService) GetDraft(ctx context.Context, req *schema.GetDraftReq) (resp *schema.DraftResp, err error) {
+	objectID, err := draftObjectID(req.DraftType, req.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	draftInfo, exist, err := ds.draftRepo.GetDraftByObject(ctx, req.UserID, req.DraftType, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.DraftNotFound)
+	}
+	return ds.convertDraftResp(ctx, draftInfo), nil
+}
+
+// GetDraftPage get the drafts of the user, the latest updated first
+func (ds *DraftService) GetDraftPage(ctx context.Context, req *schema.GetDraftPageReq) (
+	pageModel *pager.PageModel, err error) {
+	list, total, err := ds.draftRepo.GetDraftPage(ctx, req.UserID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]*schema.DraftResp, 0, len(list))
+	for _, draftInfo := range list {
+		resp = append(resp, ds.convertDraftResp(ctx, draftInfo))
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
+// DeleteDraft delete the draft of the user by id
+func (ds *DraftService) DeleteDraft(ctx context.Context, req *schema.DeleteDraftReq) (err error) {
+	return ds.draftRepo.DeleteDraft(ctx, req.UserID, req.ID)
+}
+
+// DiscardDraft delete the draft of the user for the draft type and object after the post is submitted
+func (ds *DraftService) DiscardDraft(ctx context.Context, userID, draftType, objectID string) {
+	objectID, err := draftObjectID(draftType, objectID)
+	if err != nil {
+		return
+	}
+	if err = ds.draftRepo.DeleteDraftByObject(ctx, userID, draftType, objectID); err != nil {
+		log.Errorf("discard %s draft of user %s failed: %v", draftType, userID, err)
+	}
+}
+
+// PurgeExpiredDraftsCron delete the drafts which are not updated within the retention days
+func (ds *DraftService) PurgeExpiredDraftsCron(ctx context.Context) {
+	questionSetting, err := ds.siteInfoCommonService.GetSiteQuestion(ctx)
+	if err != nil {
+		log.Errorf("get site question setting failed: %v", err)
+		return
+	}
+	before := time.Now().AddDate(0, 0, -questionSetting.DraftRetentionDays)
+	affected, err := ds.draftRepo.DeleteDraftsUpdatedBefore(ctx, before)
+	if err != nil {
+		log.Errorf("purge expired drafts failed: %v", err)
+		return
+	}
+	log.Debugf("purged %d expired drafts", affected)
+}
+
+func (ds *DraftService) convertDraftResp(ctx context.Context, draftInfo *entity.Draft) *schema.DraftResp {
+	resp := &schema.DraftResp{
+		ID:        draftInfo.ID,
+		DraftType: draftInfo.DraftType,
+		ObjectID:  draftInfo.ObjectID,
+		Title:     draftInfo.Title,
+		Content:   draftInfo.Content,
+		Tags:      decodeDraftTags(draftInfo.Tags),
+		CreatedAt: draftInfo.CreatedAt.Unix(),
+		UpdatedAt: draftInfo.UpdatedAt.Unix(),
+	}
+	questionSetting, err := ds.siteInfoCommonService.GetSiteQuestion(ctx)
+	if err != nil {
+		log.Error(err)
+	} else {
+		resp.ExpiredAt = draftInfo.UpdatedAt.AddDate(0, 0, questionSetting.DraftRetentionDays).Unix()
+	}
+	if resp.ObjectID != newQuestionObjectID && handler.GetEnableShortID(ctx) {
+		resp.ObjectID = uid.EnShortID(resp.ObjectID)
+	}
+	return resp
+}
+
+// draftObjectID the object id of the new question draft is always 0, and the others must be set
+func draftObjectID(draftType, objectID string) (string, error) {
+	if draftType == entity.DraftTypeNewQuestion {
+		return newQuestionObjectID, nil
+	}
+	objectID = uid.DeShortID(objectID)
+	if len(objectID) == 0 || objectID == newQuestionObjectID {
+		return "", errors.BadRequest(reason.ObjectNotFound)
+	}
+	return objectID, nil
+}
+
+func encodeDraftTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(tags)
+	return string(data)
+}
+
+func decodeDraftTags(tags string) []string {
+	list := make([]string, 0)
+	if len(tags) == 0 {
+		return list
+	}
+	if err := json.Unmarshal([]byte(tags), &list); err != nil {
+		log.Warnf("decode draft tags failed: %v", err)
+	}
+	return list
+}
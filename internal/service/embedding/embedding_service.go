@@ -21,8 +21,10 @@ package embedding
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/plugin"
 )
 
@@ -43,6 +45,9 @@ func (s *EmbeddingService) SearchSimilar(ctx context.Context, query string, topK
 	found := false
 
 	err := plugin.CallVectorSearch(func(vs plugin.VectorSearch) error {
+		if !plugin.StatusManager.IsEnabledFor(ctx, vs.Info().SlugName) {
+			return nil
+		}
 		found = true
 		results, searchErr = vs.SearchSimilar(ctx, query, topK)
 		return nil
@@ -56,5 +61,23 @@ func (s *EmbeddingService) SearchSimilar(ctx context.Context, query string, topK
 	if searchErr != nil {
 		return nil, searchErr
 	}
-	return results, nil
+	return filterByTenant(ctx, results), nil
+}
+
+// filterByTenant drop the results of the other tenants in case the plugin does not filter them
+func filterByTenant(ctx context.Context, results []plugin.VectorSearchResult) []plugin.VectorSearchResult {
+	tenantID := handler.GetTenantID(ctx)
+	filtered := make([]plugin.VectorSearchResult, 0, len(results))
+	for _, result := range results {
+		meta := &plugin.VectorSearchMetadata{}
+		if len(result.Metadata) > 0 {
+			if err := json.Unmarshal([]byte(result.Metadata), meta); err != nil {
+				continue
+			}
+		}
+		if meta.TenantID == tenantID {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
 }
@@ -225,6 +225,45 @@ func (es *EmailService) TestTemplate(ctx context.Context) (title, body string, e
 	return title, body, nil
 }
 
+// UserDataExportTemplate the email sent to the user when the data export is ready to download
+func (es *EmailService) UserDataExportTemplate(ctx context.Context, downloadUrl string, expireDays int) (
+	title, body string, err error) {
+	siteInfo, err := es.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return
+	}
+	templateData := &schema.UserDataExportTemplateData{
+		SiteName:    siteInfo.Name,
+		DownloadUrl: downloadUrl,
+		ExpireDays:  expireDays,
+	}
+
+	lang := handler.GetLangByCtx(ctx)
+	title = translator.TrWithData(lang, constant.EmailTplKeyUserDataExportTitle, templateData)
+	templateData.SiteName = escapeEmailHTMLText(templateData.SiteName)
+	body = translator.TrWithData(lang, constant.EmailTplKeyUserDataExportBody, templateData)
+	return title, body, nil
+}
+
+// UserErasureTemplate the email sent to the user when the account is scheduled for deletion
+func (es *EmailService) UserErasureTemplate(ctx context.Context, scheduledAt time.Time) (title, body string, err error) {
+	siteInfo, err := es.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return
+	}
+	templateData := &schema.UserErasureTemplateData{
+		SiteName:      siteInfo.Name,
+		ScheduledDate: scheduledAt.UTC().Format("2006-01-02 15:04 MST"),
+		SettingsUrl:   fmt.Sprintf("%s/users/settings/account", siteInfo.SiteUrl),
+	}
+
+	lang := handler.GetLangByCtx(ctx)
+	title = translator.TrWithData(lang, constant.EmailTplKeyUserErasureTitle, templateData)
+	templateData.SiteName = escapeEmailHTMLText(templateData.SiteName)
+	body = translator.TrWithData(lang, constant.EmailTplKeyUserErasureBody, templateData)
+	return title, body, nil
+}
+
 func escapeEmailHTMLText(text string) string {
 	return html.EscapeString(text)
 }
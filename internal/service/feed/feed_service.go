@@ -0,0 +1,454 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package feed
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/activity_common"
+	"github.com/apache/answer/internal/service/embedding"
+	questioncommon "github.com/apache/answer/internal/service/question_common"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/apache/answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// The weights of each signal used to rank the personalized feed.
+const (
+	weightFollowedTag  = 3.0
+	weightFollowedUser = 2.5
+	weightExpertise    = 2.0
+	weightUnanswered   = 1.5
+	weightSimilarity   = 3.0
+	weightPopularity   = 0.5
+
+	// decayHalfLifeHours the score of a question halves every half-life
+	decayHalfLifeHours = 72.0
+
+	candidateLimit    = 300
+	popularLimit      = 100
+	expertiseTagLimit = 20
+	feedMaxSize       = 500
+	recentViewedMax   = 10
+	similarQueryViews = 3
+	similarSearchTopK = 20
+	candidateInDays   = schema.HotInDays
+)
+
+// FeedRepo feed repository
+type FeedRepo interface {
+	GetUserTagExpertise(ctx context.Context, userID string, limit int) (expertise map[string]int64, err error)
+	GetQuestionsByTagsOrAuthors(ctx context.Context, userID string, tagIDs, authorIDs []string, inDays, limit int) (
+		questions []*entity.Question, err error)
+	GetPopularQuestions(ctx context.Context, userID string, inDays, limit int) (questions []*entity.Question, err error)
+	GetQuestionsByIDs(ctx context.Context, userID string, questionIDs []string) (questions []*entity.Question, err error)
+	GetQuestionsTagIDs(ctx context.Context, questionIDs []string) (tagIDs map[string][]string, err error)
+	GetFeedCache(ctx context.Context, userID string) (content string, exist bool, err error)
+	SetFeedCache(ctx context.Context, userID, content string) (err error)
+	GetRecentViewedQuestionIDs(ctx context.Context, userID string) (questionIDs []string, err error)
+	SetRecentViewedQuestionIDs(ctx context.Context, userID string, questionIDs []string) (err error)
+}
+
+// FeedService personalized question feed service
+type FeedService struct {
+	feedRepo         FeedRepo
+	followRepo       activity_common.FollowRepo
+	questionRepo     questioncommon.QuestionRepo
+	questionCommon   *questioncommon.QuestionCommon
+	embeddingService *embedding.EmbeddingService
+}
+
+// NewFeedService new feed service
+func NewFeedService(
+	feedRepo FeedRepo,
+	followRepo activity_common.FollowRepo,
+	questionRepo questioncommon.QuestionRepo,
+	questionCommon *questioncommon.QuestionCommon,
+	embeddingService *embedding.EmbeddingService,
+) *FeedService {
+	return &FeedService{
+		feedRepo:         feedRepo,
+		followRepo:       followRepo,
+		questionRepo:     questionRepo,
+		questionCommon:   questionCommon,
+		embeddingService: embeddingService,
+	}
+}
+
+// feedItem is a ranked question in the cached feed
+type feedItem struct {
+	QuestionID string  `json:"id"`
+	Score      float64 `json:"score"`
+}
+
+// userSignals are the user preferences used to rank the candidate questions
+type userSignals struct {
+	followedTags  map[string]bool
+	followedUsers map[string]bool
+	expertise     map[string]int64
+	maxExpertise  int64
+	similarity    map[string]float64
+	questionTags  map[string][]string
+}
+
+// GetQuestionFeed get the personalized question feed of the login user
+func (fs *FeedService) GetQuestionFeed(ctx context.Context, req *schema.QuestionFeedReq) (
+	resp *schema.QuestionFeedResp, err error) {
+	if req.PageSize <= 0 {
+		req.PageSize = schema.QuestionFeedDefaultPageSize
+	}
+	var cursor *feedItem
+	if len(req.Cursor) > 0 {
+		cursor, err = decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, errors.BadRequest(reason.RequestFormatError)
+		}
+	}
+
+	items, err := fs.getRankedFeed(ctx, req.LoginUserID)
+	if err != nil {
+		return nil, err
+	}
+	pageItems, hasMore := pageFeedItems(items, cursor, req.PageSize)
+
+	resp = &schema.QuestionFeedResp{List: make([]*schema.QuestionPageResp, 0), HasMore: hasMore}
+	if hasMore {
+		resp.NextCursor = encodeCursor(pageItems[len(pageItems)-1])
+	}
+	if len(pageItems) == 0 {
+		return resp, nil
+	}
+
+	questionIDs := make([]string, 0, len(pageItems))
+	for _, item := range pageItems {
+		questionIDs = append(questionIDs, item.QuestionID)
+	}
+	questionList, err := fs.questionRepo.FindByID(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+	questionMapping := make(map[string]*entity.Question, len(questionList))
+	for _, question := range questionList {
+		questionMapping[uid.DeShortID(question.ID)] = question
+	}
+	// The cached feed may be a little stale, keep the ranked order and skip the questions no longer visible.
+	orderedQuestions := make([]*entity.Question, 0, len(pageItems))
+	for _, item := range pageItems {
+		question, ok := questionMapping[item.QuestionID]
+		if !ok || !visibleInFeed(question) {
+			continue
+		}
+		orderedQuestions = append(orderedQuestions, question)
+	}
+	resp.List, err = fs.questionCommon.FormatQuestionsPage(ctx, orderedQuestions, req.LoginUserID,
+		schema.QuestionOrderCondRecommend)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// visibleInFeed check the question can still be served from the cached feed, the feed only ranks
+// the public questions, so a question moved into a space since is skipped as well as a hidden one
+func visibleInFeed(question *entity.Question) bool {
+	return question.Status == entity.QuestionStatusAvailable &&
+		question.Show == entity.QuestionShow &&
+		question.SpaceID == entity.PublicSpaceID
+}
+
+// RecordQuestionView remember the questions recently viewed by the user, used for similarity ranking
+func (fs *FeedService) RecordQuestionView(ctx context.Context, userID, questionID string) {
+	if len(userID) == 0 || len(questionID) == 0 {
+		return
+	}
+	questionID = uid.DeShortID(questionID)
+	viewed, err := fs.feedRepo.GetRecentViewedQuestionIDs(ctx, userID)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	questionIDs := []string{questionID}
+	for _, id := range viewed {
+		if id != questionID && len(questionIDs) < recentViewedMax {
+			questionIDs = append(questionIDs, id)
+		}
+	}
+	if err = fs.feedRepo.SetRecentViewedQuestionIDs(ctx, userID, questionIDs); err != nil {
+		log.Error(err)
+	}
+}
+
+// getRankedFeed get the ranked feed from cache, rebuild it if the cache is expired
+func (fs *FeedService) getRankedFeed(ctx context.Context, userID string) (items []*feedItem, err error) {
+	content, exist, err := fs.feedRepo.GetFeedCache(ctx, userID)
+	if err != nil {
+		log.Error(err)
+	}
+	if exist {
+		items = make([]*feedItem, 0)
+		if err = json.Unmarshal([]byte(content), &items); err == nil {
+			return items, nil
+		}
+		log.Errorf("unmarshal question feed cache failed: %v", err)
+	}
+
+	items, err = fs.buildRankedFeed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := json.Marshal(items)
+	if err = fs.feedRepo.SetFeedCache(ctx, userID, string(data)); err != nil {
+		log.Error(err)
+	}
+	return items, nil
+}
+
+// buildRankedFeed collect the candidate questions and rank them by the user signals
+func (fs *FeedService) buildRankedFeed(ctx context.Context, userID string) (items []*feedItem, err error) {
+	signals := &userSignals{
+		followedTags:  make(map[string]bool),
+		followedUsers: make(map[string]bool),
+	}
+	followedTagIDs, err := fs.followRepo.GetFollowIDs(ctx, userID, entity.Tag{}.TableName())
+	if err != nil {
+		return nil, err
+	}
+	for _, tagID := range followedTagIDs {
+		signals.followedTags[tagID] = true
+	}
+	followedUserIDs, err := fs.followRepo.GetFollowIDs(ctx, userID, constant.UserObjectType)
+	if err != nil {
+		return nil, err
+	}
+	for _, followedUserID := range followedUserIDs {
+		signals.followedUsers[followedUserID] = true
+	}
+	signals.expertise, err = fs.feedRepo.GetUserTagExpertise(ctx, userID, expertiseTagLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, amount := range signals.expertise {
+		signals.maxExpertise = max(signals.maxExpertise, amount)
+	}
+
+	viewedIDs, err := fs.feedRepo.GetRecentViewedQuestionIDs(ctx, userID)
+	if err != nil {
+		log.Error(err)
+	}
+	viewed := make(map[string]bool, len(viewedIDs))
+	for _, id := range viewedIDs {
+		viewed[id] = true
+	}
+	signals.similarity = fs.getSimilarityScores(ctx, viewedIDs)
+
+	// Questions with the followed tags or the tags the user is good at, and questions of the followed users.
+	tagIDs := append([]string{}, followedTagIDs...)
+	for tagID := range signals.expertise {
+		if !signals.followedTags[tagID] {
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+	candidates := make(map[string]*entity.Question)
+	addCandidates := func(questions []*entity.Question) {
+		for _, question := range questions {
+			if !viewed[question.ID] {
+				candidates[question.ID] = question
+			}
+		}
+	}
+	if len(tagIDs) > 0 || len(followedUserIDs) > 0 {
+		questions, err := fs.feedRepo.GetQuestionsByTagsOrAuthors(ctx, userID, tagIDs, followedUserIDs,
+			candidateInDays, candidateLimit)
+		if err != nil {
+			return nil, err
+		}
+		addCandidates(questions)
+	}
+	// Popular questions make sure the new user without any preference still has a feed.
+	questions, err := fs.feedRepo.GetPopularQuestions(ctx, userID, candidateInDays, popularLimit)
+	if err != nil {
+		return nil, err
+	}
+	addCandidates(questions)
+
+	similarIDs := make([]string, 0)
+	for questionID := range signals.similarity {
+		if _, ok := candidates[questionID]; !ok && !viewed[questionID] {
+			similarIDs = append(similarIDs, questionID)
+		}
+	}
+	if len(similarIDs) > 0 {
+		questions, err := fs.feedRepo.GetQuestionsByIDs(ctx, userID, similarIDs)
+		if err != nil {
+			return nil, err
+		}
+		addCandidates(questions)
+	}
+
+	candidateList := make([]*entity.Question, 0, len(candidates))
+	candidateIDs := make([]string, 0, len(candidates))
+	for _, question := range candidates {
+		candidateList = append(candidateList, question)
+		candidateIDs = append(candidateIDs, question.ID)
+	}
+	signals.questionTags, err = fs.feedRepo.GetQuestionsTagIDs(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	items = rankQuestions(time.Now(), candidateList, signals)
+	if len(items) > feedMaxSize {
+		items = items[:feedMaxSize]
+	}
+	return items, nil
+}
+
+// getSimilarityScores search the questions similar to the recently viewed ones by vector search plugin
+func (fs *FeedService) getSimilarityScores(ctx context.Context, viewedIDs []string) (similarity map[string]float64) {
+	similarity = make(map[string]float64)
+	if len(viewedIDs) == 0 {
+		return similarity
+	}
+	if len(viewedIDs) > similarQueryViews {
+		viewedIDs = viewedIDs[:similarQueryViews]
+	}
+	viewedQuestions, err := fs.questionRepo.FindByID(ctx, append([]string{}, viewedIDs...))
+	if err != nil {
+		log.Error(err)
+		return similarity
+	}
+	for _, question := range viewedQuestions {
+		results, err := fs.embeddingService.SearchSimilar(ctx, question.Title, similarSearchTopK)
+		if err != nil {
+			// vector search plugin is optional, skip the similarity signal if it is not available
+			log.Debugf("search similar questions for feed failed: %v", err)
+			return similarity
+		}
+		for _, result := range results {
+			questionID := result.ObjectID
+			if result.ObjectType != constant.QuestionObjectType {
+				meta := &plugin.VectorSearchMetadata{}
+				if err := json.Unmarshal([]byte(result.Metadata), meta); err != nil || len(meta.QuestionID) == 0 {
+					continue
+				}
+				questionID = meta.QuestionID
+			}
+			questionID = uid.DeShortID(questionID)
+			similarity[questionID] = math.Max(similarity[questionID], result.Score)
+		}
+	}
+	return similarity
+}
+
+// rankQuestions score each question and sort them by score desc
+func rankQuestions(now time.Time, questions []*entity.Question, signals *userSignals) (items []*feedItem) {
+	items = make([]*feedItem, 0, len(questions))
+	for _, question := range questions {
+		var relevance float64
+		tagIDs := signals.questionTags[question.ID]
+		var expertise int64
+		for _, tagID := range tagIDs {
+			expertise += signals.expertise[tagID]
+		}
+		for _, tagID := range tagIDs {
+			if signals.followedTags[tagID] {
+				relevance += weightFollowedTag
+				break
+			}
+		}
+		if signals.followedUsers[question.UserID] {
+			relevance += weightFollowedUser
+		}
+		if expertise > 0 && signals.maxExpertise > 0 {
+			relevance += weightExpertise * math.Min(1, math.Log1p(float64(expertise))/math.Log1p(float64(signals.maxExpertise)))
+			// the user may be the one who can answer it
+			if question.AnswerCount == 0 {
+				relevance += weightUnanswered
+			}
+		}
+		relevance += weightSimilarity * signals.similarity[question.ID]
+
+		popularity := weightPopularity * math.Log1p(float64(max(question.VoteCount, 0)+question.AnswerCount))
+		ageInHours := math.Max(0, now.Sub(question.CreatedAt).Hours())
+		decay := math.Pow(0.5, ageInHours/decayHalfLifeHours)
+		items = append(items, &feedItem{
+			QuestionID: question.ID,
+			Score:      (relevance + popularity) * decay,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return feedItemBefore(items[i], items[j])
+	})
+	return items
+}
+
+// feedItemBefore order by score desc, question id asc
+func feedItemBefore(a, b *feedItem) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.QuestionID < b.QuestionID
+}
+
+// pageFeedItems get the items after the cursor
+func pageFeedItems(items []*feedItem, cursor *feedItem, pageSize int) (page []*feedItem, hasMore bool) {
+	start := 0
+	if cursor != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return feedItemBefore(cursor, items[i])
+		})
+	}
+	end := min(start+pageSize, len(items))
+	return items[start:end], end < len(items)
+}
+
+func encodeCursor(item *feedItem) string {
+	raw := strconv.FormatFloat(item.Score, 'g', -1, 64) + ":" + item.QuestionID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (item *feedItem, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	score, questionID, ok := strings.Cut(string(raw), ":")
+	if !ok || len(questionID) == 0 {
+		return nil, fmt.Errorf("invalid cursor %s", cursor)
+	}
+	item = &feedItem{QuestionID: questionID}
+	item.Score, err = strconv.ParseFloat(score, 64)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
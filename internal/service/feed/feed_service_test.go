@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankQuestions(t *testing.T) {
+	now := time.Now()
+	questions := []*entity.Question{
+		{ID: "1", UserID: "10", CreatedAt: now.Add(-time.Hour)},
+		{ID: "2", UserID: "11", CreatedAt: now.Add(-time.Hour)},
+		{ID: "3", UserID: "12", CreatedAt: now.Add(-time.Hour)},
+		{ID: "4", UserID: "13", CreatedAt: now.Add(-time.Hour), AnswerCount: 1},
+		{ID: "5", UserID: "14", CreatedAt: now.Add(-30 * 24 * time.Hour)},
+	}
+	signals := &userSignals{
+		followedTags:  map[string]bool{"100": true},
+		followedUsers: map[string]bool{"11": true},
+		expertise:     map[string]int64{"200": 5},
+		maxExpertise:  5,
+		similarity:    map[string]float64{},
+		questionTags: map[string][]string{
+			"1": {"100"},
+			"3": {"200"},
+			"4": {"200"},
+			"5": {"100"},
+		},
+	}
+
+	items := rankQuestions(now, questions, signals)
+	require.Len(t, items, 5)
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.QuestionID)
+	}
+	// unanswered question of expertise > followed tag > followed user > answered question of expertise > old question
+	assert.Equal(t, []string{"3", "1", "2", "4", "5"}, ids)
+}
+
+func TestPageFeedItems(t *testing.T) {
+	items := []*feedItem{
+		{QuestionID: "1", Score: 3},
+		{QuestionID: "2", Score: 2},
+		{QuestionID: "3", Score: 2},
+		{QuestionID: "4", Score: 1},
+	}
+
+	page, hasMore := pageFeedItems(items, nil, 2)
+	assert.Equal(t, items[:2], page)
+	assert.True(t, hasMore)
+
+	cursor, err := decodeCursor(encodeCursor(page[len(page)-1]))
+	require.NoError(t, err)
+	page, hasMore = pageFeedItems(items, cursor, 2)
+	assert.Equal(t, items[2:], page)
+	assert.False(t, hasMore)
+
+	page, hasMore = pageFeedItems(items, &feedItem{QuestionID: "4", Score: 1}, 2)
+	assert.Empty(t, page)
+	assert.False(t, hasMore)
+
+	_, err = decodeCursor("invalid")
+	assert.Error(t, err)
+}
+
+func TestVisibleInFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		question *entity.Question
+		want     bool
+	}{
+		{"public", &entity.Question{Status: entity.QuestionStatusAvailable, Show: entity.QuestionShow}, true},
+		{"hidden", &entity.Question{Status: entity.QuestionStatusAvailable, Show: entity.QuestionHide}, false},
+		{"closed", &entity.Question{Status: entity.QuestionStatusClosed, Show: entity.QuestionShow}, false},
+		{"moved into a space", &entity.Question{Status: entity.QuestionStatusAvailable, Show: entity.QuestionShow,
+			SpaceID: 3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, visibleInFeed(tt.question))
+		})
+	}
+}
@@ -163,7 +163,7 @@ func isBrandingOrAvatarFile(filePath string) bool {
 }
 
 func (fs *FileRecordService) PurgeDeletedFiles(ctx context.Context) {
-	deletedPath := filepath.Join(fs.serviceConfig.UploadPath, constant.DeletedSubPath)
+	deletedPath := filepath.Join(fs.serviceConfig.GetUploadPath(ctx), constant.DeletedSubPath)
 	log.Infof("purge deleted files: %s", deletedPath)
 	err := os.RemoveAll(deletedPath)
 	if err != nil {
@@ -184,8 +184,8 @@ func (fs *FileRecordService) DeleteAndMoveFileRecord(ctx context.Context, fileRe
 
 	// Move the file to the deleted directory
 	oldFilename := filepath.Base(fileRecord.FilePath)
-	oldFilePath := filepath.Join(fs.serviceConfig.UploadPath, fileRecord.FilePath)
-	deletedPath := filepath.Join(fs.serviceConfig.UploadPath, constant.DeletedSubPath, oldFilename)
+	oldFilePath := filepath.Join(fs.serviceConfig.GetUploadPath(ctx), fileRecord.FilePath)
+	deletedPath := filepath.Join(fs.serviceConfig.GetUploadPath(ctx), constant.DeletedSubPath, oldFilename)
 
 	if err := writer.MoveFile(oldFilePath, deletedPath); err != nil {
 		return fmt.Errorf("move file error: %v", err)
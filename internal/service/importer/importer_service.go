@@ -21,151 +21,788 @@ package importer
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
 
-	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/data"
 	"github.com/apache/answer/internal/base/reason"
-	"github.com/apache/answer/internal/base/translator"
-	"github.com/apache/answer/internal/base/validator"
+	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
-	"github.com/apache/answer/internal/service/content"
-	"github.com/apache/answer/internal/service/permission"
-	"github.com/apache/answer/internal/service/rank"
+	"github.com/apache/answer/internal/service/activity_common"
+	"github.com/apache/answer/internal/service/activity_type"
+	answercommon "github.com/apache/answer/internal/service/answer_common"
+	"github.com/apache/answer/internal/service/comment"
+	questioncommon "github.com/apache/answer/internal/service/question_common"
+	"github.com/apache/answer/internal/service/revision_common"
+	tagcommon "github.com/apache/answer/internal/service/tag_common"
+	"github.com/apache/answer/internal/service/uploader"
 	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/pkg/converter"
+	"github.com/apache/answer/pkg/random"
+	"github.com/apache/answer/pkg/uid"
 	"github.com/apache/answer/plugin"
-	"github.com/gin-gonic/gin"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
 )
 
+// ImporterRepo importer repository
+type ImporterRepo interface {
+	GetMapping(ctx context.Context, source, objectType, externalID string) (objectID string, exist bool, err error)
+	AddMapping(ctx context.Context, mapping *entity.ImportMapping) (err error)
+	UpdatePostTime(ctx context.Context, objectID string, createdAt, updatedAt time.Time) (err error)
+	UpdateRevisionTime(ctx context.Context, revisionID string, createdAt time.Time) (err error)
+	AddActivities(ctx context.Context, activities []*entity.Activity) (err error)
+	ExistActivity(ctx context.Context, objectID, userID string, activityTypes ...int) (exist bool, err error)
+	UpdateVoteCount(ctx context.Context, objectID string, voteUpType, voteDownType int) (err error)
+}
+
 // ImporterService importer service
+// The content is imported quietly: there are no reviews, notifications, events or reputation changes,
+// and the original authors and times are kept.
 type ImporterService struct {
-	questionService *content.QuestionService
-	rankService     *rank.RankService
+	importerRepo    ImporterRepo
+	questionRepo    questioncommon.QuestionRepo
+	questionCommon  *questioncommon.QuestionCommon
+	answerRepo      answercommon.AnswerRepo
+	commentRepo     comment.CommentRepo
+	revisionService *revision_common.RevisionService
+	tagCommon       *tagcommon.TagCommonService
+	userRepo        usercommon.UserRepo
 	userCommon      *usercommon.UserCommon
+	activityRepo    activity_common.ActivityRepo
+	uploaderService uploader.UploaderService
+	data            *data.Data
 }
 
-// NewRankService new rank service
+// NewImporterService new importer service
 func NewImporterService(
-	questionService *content.QuestionService,
-	rankService *rank.RankService,
-	userCommon *usercommon.UserCommon) *ImporterService {
+	importerRepo ImporterRepo,
+	questionRepo questioncommon.QuestionRepo,
+	questionCommon *questioncommon.QuestionCommon,
+	answerRepo answercommon.AnswerRepo,
+	commentRepo comment.CommentRepo,
+	revisionService *revision_common.RevisionService,
+	tagCommon *tagcommon.TagCommonService,
+	userRepo usercommon.UserRepo,
+	userCommon *usercommon.UserCommon,
+	activityRepo activity_common.ActivityRepo,
+	uploaderService uploader.UploaderService,
+	data *data.Data,
+) *ImporterService {
 	return &ImporterService{
-		questionService: questionService,
-		rankService:     rankService,
+		importerRepo:    importerRepo,
+		questionRepo:    questionRepo,
+		questionCommon:  questionCommon,
+		answerRepo:      answerRepo,
+		commentRepo:     commentRepo,
+		revisionService: revisionService,
+		tagCommon:       tagCommon,
+		userRepo:        userRepo,
 		userCommon:      userCommon,
+		activityRepo:    activityRepo,
+		uploaderService: uploaderService,
+		data:            data,
 	}
 }
 
+// ImporterFunc is registered to the importer plugin, the external ids are mapped per plugin
 type ImporterFunc struct {
 	importerService *ImporterService
+	source          string
+}
+
+func (ipfunc *ImporterFunc) AddUser(ctx context.Context, userInfo plugin.ImportUser) (err error) {
+	_, err = ipfunc.importerService.getOrCreateUser(ctx, ipfunc.source, &userInfo)
+	return err
+}
+
+func (ipfunc *ImporterFunc) AddTag(ctx context.Context, tagInfo plugin.TagImporterInfo) (err error) {
+	return ipfunc.importerService.ImportTag(ctx, tagInfo)
 }
 
 func (ipfunc *ImporterFunc) AddQuestion(ctx context.Context, questionInfo plugin.QuestionImporterInfo) (err error) {
-	return ipfunc.importerService.ImportQuestion(ctx, questionInfo)
+	return ipfunc.importerService.ImportQuestion(ctx, ipfunc.source, questionInfo)
 }
 
-func (ip *ImporterService) NewImporterFunc() plugin.ImporterFunc {
-	return &ImporterFunc{importerService: ip}
+func (ipfunc *ImporterFunc) AddAnswer(ctx context.Context, answerInfo plugin.AnswerImporterInfo) (err error) {
+	return ipfunc.importerService.ImportAnswer(ctx, ipfunc.source, answerInfo)
 }
 
-func (ip *ImporterService) ImportQuestion(ctx context.Context, questionInfo plugin.QuestionImporterInfo) (err error) {
-	req := &schema.QuestionAdd{}
-	errFields := make([]*validator.FormErrorField, 0)
-	// To limit rate, remove the following code from comment: Part 1/2
-	// reject, rejectKey := ipc.rateLimitMiddleware.DuplicateRequestRejection(ctx, req)
-	// if reject {
-	// 	return
-	// }
-	userInfo, exist, err := ip.userCommon.GetByEmail(ctx, questionInfo.UserEmail)
+func (ipfunc *ImporterFunc) AddComment(ctx context.Context, commentInfo plugin.CommentImporterInfo) (err error) {
+	return ipfunc.importerService.ImportComment(ctx, ipfunc.source, commentInfo)
+}
+
+func (ipfunc *ImporterFunc) AddVote(ctx context.Context, voteInfo plugin.VoteImporterInfo) (err error) {
+	return ipfunc.importerService.ImportVote(ctx, ipfunc.source, voteInfo)
+}
+
+func (ip *ImporterService) NewImporterFunc(source string) plugin.ImporterFunc {
+	return &ImporterFunc{importerService: ip, source: source}
+}
+
+// ImportTag import the tag with its description, the tag which already exists is skipped
+func (ip *ImporterService) ImportTag(ctx context.Context, tagInfo plugin.TagImporterInfo) (err error) {
+	slugName := strings.ToLower(strings.ReplaceAll(tagInfo.SlugName, " ", "-"))
+	_, exist, err := ip.tagCommon.GetTagBySlugName(ctx, slugName)
 	if err != nil {
-		log.Errorf("error: %v", err)
 		return err
 	}
-	if !exist {
-		return fmt.Errorf("user not found")
-	}
-
-	// To limit rate, remove the following code from comment: Part 2/2
-	// defer func() {
-	// 	// If status is not 200 means that the bad request has been returned, so the record should be cleared
-	// 	if ctx.Writer.Status() != http.StatusOK {
-	// 		ipc.rateLimitMiddleware.DuplicateRequestClear(ctx, rejectKey)
-	// 	}
-	// }()
-	req.UserID = userInfo.ID
-	req.Title = questionInfo.Title
-	req.Content = questionInfo.Content
-	req.HTML = "<p>" + questionInfo.Content + "</p>"
-	req.Tags = make([]*schema.TagItem, len(questionInfo.Tags))
-	for i, tag := range questionInfo.Tags {
-		req.Tags[i] = &schema.TagItem{
-			SlugName:    tag,
-			DisplayName: tag,
-		}
-	}
-	canList, requireRanks, err := ip.rankService.CheckOperationPermissionsForRanks(ctx, req.UserID, []string{
-		permission.QuestionAdd,
-		permission.QuestionEdit,
-		permission.QuestionDelete,
-		permission.QuestionClose,
-		permission.QuestionReopen,
-		permission.TagUseReservedTag,
-		permission.TagAdd,
-		permission.LinkUrlLimit,
+	if exist {
+		return nil
+	}
+	displayName := tagInfo.DisplayName
+	if len(displayName) == 0 {
+		displayName = tagInfo.SlugName
+	}
+	_, err = ip.tagCommon.AddTag(ctx, &schema.AddTagReq{
+		SlugName:     slugName,
+		DisplayName:  displayName,
+		OriginalText: tagInfo.Description,
+		ParsedText:   converter.Markdown2HTML(tagInfo.Description),
+		UserID:       "0",
+	})
+	return err
+}
+
+// ImportQuestion import the question with its tags and revisions.
+// The question which has already been imported from the source is skipped.
+func (ip *ImporterService) ImportQuestion(ctx context.Context, source string, questionInfo plugin.QuestionImporterInfo) (err error) {
+	if len(questionInfo.ExternalID) > 0 {
+		_, exist, err := ip.importerRepo.GetMapping(ctx, source, entity.ImportObjectTypeQuestion, questionInfo.ExternalID)
+		if err != nil {
+			return err
+		}
+		if exist {
+			return nil
+		}
+	}
+	var question *entity.Question
+	err = ip.data.DB.TransactionContext(ctx, func(ctx context.Context) (err error) {
+		question, err = ip.addQuestion(ctx, source, questionInfo)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_ = ip.questionRepo.UpdateSearch(ctx, question.ID)
+
+	userQuestionCount, err := ip.questionCommon.GetUserQuestionCount(ctx, question.UserID)
+	if err != nil {
+		log.Errorf("get user question count error %v", err)
+	} else if err = ip.userCommon.UpdateQuestionCount(ctx, question.UserID, userQuestionCount); err != nil {
+		log.Errorf("update user question count error %v", err)
+	}
+	return nil
+}
+
+// addQuestion add the question with its tags, revisions and activities, the mapping is added at last
+// so that the question is never skipped as imported if any of them fails.
+func (ip *ImporterService) addQuestion(ctx context.Context, source string, questionInfo plugin.QuestionImporterInfo) (
+	question *entity.Question, err error) {
+	if questionInfo.User == nil && len(questionInfo.UserEmail) > 0 {
+		questionInfo.User = &plugin.ImportUser{Email: questionInfo.UserEmail}
+	}
+	userID, err := ip.getOrCreateUser(ctx, source, questionInfo.User)
+	if err != nil {
+		return nil, err
+	}
+	content, html, err := ip.saveAttachments(ctx, userID,
+		questionInfo.Content, questionInfo.HTML, questionInfo.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, updatedAt := importTime(questionInfo.CreatedAt, questionInfo.UpdatedAt)
+
+	question = &entity.Question{
+		UserID:           userID,
+		LastEditUserID:   "0",
+		Title:            questionInfo.Title,
+		OriginalText:     content,
+		ParsedText:       html,
+		Pin:              entity.QuestionUnPin,
+		Show:             entity.QuestionShow,
+		Status:           entity.QuestionStatusAvailable,
+		ViewCount:        questionInfo.ViewCount,
+		UniqueViewCount:  questionInfo.ViewCount,
+		VoteCount:        questionInfo.VoteCount,
+		AcceptedAnswerID: "0",
+		LastAnswerID:     "0",
+		RevisionID:       "0",
+		CreatedAt:        createdAt,
+		PostUpdateTime:   updatedAt,
+	}
+	if err = ip.questionRepo.AddQuestion(ctx, question); err != nil {
+		return nil, err
+	}
+	question.ID = uid.DeShortID(question.ID)
+
+	tagItems := make([]*schema.TagItem, 0, len(questionInfo.Tags))
+	for _, tag := range questionInfo.Tags {
+		tagItems = append(tagItems, &schema.TagItem{SlugName: tag, DisplayName: tag})
+	}
+	if _, err = ip.tagCommon.ObjectChangeTag(ctx, &schema.TagChange{
+		ObjectID: question.ID,
+		Tags:     tagItems,
+		UserID:   userID,
+	}, 0); err != nil {
+		return nil, err
+	}
+
+	// the previous versions are followed by the current one, the first version is the asked one
+	versions := append(append([]plugin.ImportRevision{}, questionInfo.Revisions...), plugin.ImportRevision{
+		Title:     questionInfo.Title,
+		Content:   content,
+		Tags:      questionInfo.Tags,
+		User:      questionInfo.LastEditUser,
+		CreatedAt: updatedAt,
+	})
+	activities := make([]*entity.Activity, 0, len(versions))
+	for i, version := range versions {
+		editorID := userID
+		if version.User != nil {
+			editorID, err = ip.getOrCreateUser(ctx, source, version.User)
+			if err != nil {
+				return nil, err
+			}
+		}
+		revisionQuestion := *question
+		revisionQuestion.Title = version.Title
+		revisionQuestion.OriginalText = version.Content
+		revisionQuestion.ParsedText = html
+		if i < len(versions)-1 {
+			revisionQuestion.ParsedText = converter.Markdown2HTML(version.Content)
+		}
+		tags, err := ip.tagCommon.GetTagListByNames(ctx, version.Tags)
+		if err != nil {
+			return nil, err
+		}
+		revisionContent := &entity.QuestionWithTagsRevision{Question: revisionQuestion}
+		for _, tag := range tags {
+			revisionContent.Tags = append(revisionContent.Tags, &entity.TagSimpleInfoForRevision{
+				ID:          tag.ID,
+				SlugName:    tag.SlugName,
+				DisplayName: tag.DisplayName,
+				Recommend:   tag.Recommend,
+				Reserved:    tag.Reserved,
+			})
+		}
+		revisionCreatedAt := version.CreatedAt
+		if i == 0 {
+			revisionCreatedAt = createdAt
+		}
+		revisionID, err := ip.addRevision(ctx, editorID, question.ID, version.Title, version.Log,
+			revisionContent, revisionCreatedAt, i == len(versions)-1)
+		if err != nil {
+			return nil, err
+		}
+		activityKey := constant.ActQuestionEdited
+		if i == 0 {
+			activityKey = constant.ActQuestionAsked
+		} else {
+			question.LastEditUserID = editorID
+		}
+		activity, err := ip.newActivity(ctx, activityKey, editorID, question.ID, question.ID, revisionCreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		activity.RevisionID = converter.StringToInt64(revisionID)
+		activities = append(activities, activity)
+	}
+	if err = ip.importerRepo.AddActivities(ctx, activities); err != nil {
+		return nil, err
+	}
+
+	if question.LastEditUserID != "0" {
+		if err = ip.questionRepo.UpdateQuestion(ctx, question, []string{"last_edit_user_id"}); err != nil {
+			return nil, err
+		}
+	}
+	if err = ip.importerRepo.UpdatePostTime(ctx, question.ID, createdAt, updatedAt); err != nil {
+		return nil, err
+	}
+	if err = ip.addMapping(ctx, source, entity.ImportObjectTypeQuestion, questionInfo.ExternalID, question.ID); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// ImportAnswer import the answer of the imported question
+func (ip *ImporterService) ImportAnswer(ctx context.Context, source string, answerInfo plugin.AnswerImporterInfo) (err error) {
+	if len(answerInfo.ExternalID) > 0 {
+		_, exist, err := ip.importerRepo.GetMapping(ctx, source, entity.ImportObjectTypeAnswer, answerInfo.ExternalID)
+		if err != nil {
+			return err
+		}
+		if exist {
+			return nil
+		}
+	}
+	var answer *entity.Answer
+	err = ip.data.DB.TransactionContext(ctx, func(ctx context.Context) (err error) {
+		answer, err = ip.addAnswer(ctx, source, answerInfo)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = ip.questionCommon.UpdateAnswerCount(ctx, answer.QuestionID); err != nil {
+		log.Errorf("update answer count error %v", err)
+	}
+	if err = ip.questionCommon.UpdateLastAnswer(ctx, answer.QuestionID, answer.ID); err != nil {
+		log.Errorf("update last answer error %v", err)
+	}
+	_ = ip.questionRepo.UpdateSearch(ctx, answer.QuestionID)
+
+	userAnswerCount, err := ip.answerRepo.GetCountByUserID(ctx, answer.UserID)
+	if err != nil {
+		log.Errorf("get user answer count error %v", err)
+	} else if err = ip.userCommon.UpdateAnswerCount(ctx, answer.UserID, int(userAnswerCount)); err != nil {
+		log.Errorf("update user answer count error %v", err)
+	}
+	return nil
+}
+
+// addAnswer add the answer with its revisions and activities, the mapping is added at last
+func (ip *ImporterService) addAnswer(ctx context.Context, source string, answerInfo plugin.AnswerImporterInfo) (
+	answer *entity.Answer, err error) {
+	questionID, err := ip.getObjectID(ctx, source, plugin.ImportObjectTypeQuestion, answerInfo.QuestionExternalID)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := ip.getOrCreateUser(ctx, source, answerInfo.User)
+	if err != nil {
+		return nil, err
+	}
+	content, html, err := ip.saveAttachments(ctx, userID,
+		answerInfo.Content, answerInfo.HTML, answerInfo.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, updatedAt := importTime(answerInfo.CreatedAt, answerInfo.UpdatedAt)
+
+	answer = &entity.Answer{
+		QuestionID:     questionID,
+		UserID:         userID,
+		LastEditUserID: "0",
+		OriginalText:   content,
+		ParsedText:     html,
+		Status:         entity.AnswerStatusAvailable,
+		Accepted:       schema.AnswerAcceptedFailed,
+		VoteCount:      answerInfo.VoteCount,
+		RevisionID:     "0",
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+	if err = ip.answerRepo.AddAnswer(ctx, answer); err != nil {
+		return nil, err
+	}
+	answer.ID = uid.DeShortID(answer.ID)
+	answer.QuestionID = questionID
+	answer.CreatedAt = createdAt
+
+	versions := append(append([]plugin.ImportRevision{}, answerInfo.Revisions...), plugin.ImportRevision{
+		Content:   content,
+		User:      answerInfo.LastEditUser,
+		CreatedAt: updatedAt,
+	})
+	activities := make([]*entity.Activity, 0, len(versions)+1)
+	for i, version := range versions {
+		editorID := userID
+		if version.User != nil {
+			editorID, err = ip.getOrCreateUser(ctx, source, version.User)
+			if err != nil {
+				return nil, err
+			}
+		}
+		revisionAnswer := *answer
+		revisionAnswer.OriginalText = version.Content
+		revisionAnswer.ParsedText = html
+		if i < len(versions)-1 {
+			revisionAnswer.ParsedText = converter.Markdown2HTML(version.Content)
+		}
+		revisionCreatedAt := version.CreatedAt
+		if i == 0 {
+			revisionCreatedAt = createdAt
+		}
+		revisionID, err := ip.addRevision(ctx, editorID, answer.ID, "", version.Log,
+			revisionAnswer, revisionCreatedAt, i == len(versions)-1)
+		if err != nil {
+			return nil, err
+		}
+		activityKey := constant.ActAnswerEdited
+		if i == 0 {
+			activityKey = constant.ActAnswerAnswered
+		} else {
+			answer.LastEditUserID = editorID
+		}
+		activity, err := ip.newActivity(ctx, activityKey, editorID, answer.ID, answer.ID, revisionCreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		activity.RevisionID = converter.StringToInt64(revisionID)
+		activities = append(activities, activity)
+	}
+	activity, err := ip.newActivity(ctx, constant.ActQuestionAnswered, userID, answer.ID, questionID, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	activities = append(activities, activity)
+	if err = ip.importerRepo.AddActivities(ctx, activities); err != nil {
+		return nil, err
+	}
+
+	if answer.LastEditUserID != "0" {
+		if err = ip.answerRepo.UpdateAnswer(ctx, answer, []string{"last_edit_user_id"}); err != nil {
+			return nil, err
+		}
+	}
+	if err = ip.importerRepo.UpdatePostTime(ctx, answer.ID, createdAt, updatedAt); err != nil {
+		return nil, err
+	}
+
+	if answerInfo.Accepted {
+		if err = ip.answerRepo.UpdateAcceptedStatus(ctx, answer.ID, questionID); err != nil {
+			return nil, err
+		}
+		if err = ip.questionCommon.UpdateAccepted(ctx, questionID, answer.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err = ip.addMapping(ctx, source, entity.ImportObjectTypeAnswer, answerInfo.ExternalID, answer.ID); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// ImportComment import the comment of the imported question or answer
+func (ip *ImporterService) ImportComment(ctx context.Context, source string, commentInfo plugin.CommentImporterInfo) (err error) {
+	if len(commentInfo.ExternalID) > 0 {
+		_, exist, err := ip.importerRepo.GetMapping(ctx, source, entity.ImportObjectTypeComment, commentInfo.ExternalID)
+		if err != nil {
+			return err
+		}
+		if exist {
+			return nil
+		}
+	}
+	return ip.data.DB.TransactionContext(ctx, func(ctx context.Context) error {
+		return ip.addComment(ctx, source, commentInfo)
 	})
+}
+
+// addComment add the comment and its activity, the mapping is added at last
+func (ip *ImporterService) addComment(ctx context.Context, source string, commentInfo plugin.CommentImporterInfo) (err error) {
+	objectID, err := ip.getObjectID(ctx, source, commentInfo.ObjectType, commentInfo.ObjectExternalID)
 	if err != nil {
-		log.Errorf("error: %v", err)
 		return err
 	}
-	req.CanAdd = canList[0]
-	req.CanEdit = canList[1]
-	req.CanDelete = canList[2]
-	req.CanClose = canList[3]
-	req.CanReopen = canList[4]
-	req.CanUseReservedTag = canList[5]
-	req.CanAddTag = canList[6]
-	if !req.CanAdd {
-		log.Errorf("error: %v", err)
+	questionID := objectID
+	activityKey := constant.ActQuestionCommented
+	if commentInfo.ObjectType == plugin.ImportObjectTypeAnswer {
+		answer, exist, err := ip.answerRepo.GetByID(ctx, objectID)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return errors.BadRequest(reason.AnswerNotFound)
+		}
+		questionID = answer.QuestionID
+		activityKey = constant.ActAnswerCommented
+	}
+	userID, err := ip.getOrCreateUser(ctx, source, commentInfo.User)
+	if err != nil {
 		return err
 	}
-	hasNewTag, err := ip.questionService.HasNewTag(ctx.(*gin.Context), req.Tags)
+	createdAt, _ := importTime(commentInfo.CreatedAt, time.Time{})
+
+	newComment := &entity.Comment{
+		UserID:       userID,
+		ObjectID:     objectID,
+		QuestionID:   questionID,
+		Status:       entity.CommentStatusAvailable,
+		OriginalText: commentInfo.Content,
+		ParsedText:   converter.Markdown2HTML(commentInfo.Content),
+	}
+	if len(commentInfo.ReplyExternalID) > 0 {
+		replyCommentID, exist, err := ip.importerRepo.GetMapping(ctx, source,
+			entity.ImportObjectTypeComment, commentInfo.ReplyExternalID)
+		if err != nil {
+			return err
+		}
+		if exist {
+			replyComment, exist, err := ip.commentRepo.GetComment(ctx, replyCommentID)
+			if err != nil {
+				return err
+			}
+			if exist {
+				newComment.ReplyCommentID = sql.NullInt64{Int64: converter.StringToInt64(replyComment.ID), Valid: true}
+				newComment.ReplyUserID = sql.NullInt64{Int64: converter.StringToInt64(replyComment.UserID), Valid: true}
+			}
+		}
+	}
+	if err = ip.commentRepo.AddComment(ctx, newComment); err != nil {
+		return err
+	}
+	if err = ip.importerRepo.UpdatePostTime(ctx, newComment.ID, createdAt, createdAt); err != nil {
+		return err
+	}
+	activity, err := ip.newActivity(ctx, activityKey, userID, newComment.ID, objectID, createdAt)
+	if err != nil {
+		return err
+	}
+	if err = ip.importerRepo.AddActivities(ctx, []*entity.Activity{activity}); err != nil {
+		return err
+	}
+	return ip.addMapping(ctx, source, entity.ImportObjectTypeComment, commentInfo.ExternalID, newComment.ID)
+}
+
+// ImportVote import the vote of the imported question or answer, the vote of the author is ignored
+func (ip *ImporterService) ImportVote(ctx context.Context, source string, voteInfo plugin.VoteImporterInfo) (err error) {
+	objectID, err := ip.getObjectID(ctx, source, voteInfo.ObjectType, voteInfo.ObjectExternalID)
+	if err != nil {
+		return err
+	}
+	var objectUserID string
+	var actions []string
+	switch voteInfo.ObjectType {
+	case plugin.ImportObjectTypeQuestion:
+		question, exist, err := ip.questionRepo.GetQuestion(ctx, objectID)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return errors.BadRequest(reason.QuestionNotFound)
+		}
+		objectUserID = question.UserID
+		actions = []string{activity_type.QuestionVoteUp, activity_type.QuestionVoteDown,
+			activity_type.QuestionVotedUp, activity_type.QuestionVotedDown}
+	case plugin.ImportObjectTypeAnswer:
+		answer, exist, err := ip.answerRepo.GetByID(ctx, objectID)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return errors.BadRequest(reason.AnswerNotFound)
+		}
+		objectUserID = answer.UserID
+		actions = []string{activity_type.AnswerVoteUp, activity_type.AnswerVoteDown,
+			activity_type.AnswerVotedUp, activity_type.AnswerVotedDown}
+	}
+	userID, err := ip.getOrCreateUser(ctx, source, voteInfo.User)
 	if err != nil {
-		log.Errorf("error: %v", err)
 		return err
 	}
-	if !req.CanAddTag && hasNewTag {
-		lang := handler.GetLangByCtx(ctx.(*gin.Context))
-		msg := translator.TrWithData(lang, reason.NoEnoughRankToOperate, &schema.PermissionTrTplData{Rank: requireRanks[6]})
-		log.Errorf("error: %v", msg)
-		return errors.BadRequest(msg)
+	if userID == objectUserID {
+		return nil
 	}
 
-	errList, err := ip.questionService.CheckAddQuestion(ctx, req)
+	activityTypes := make([]int, len(actions))
+	for i, action := range actions {
+		activityTypes[i], err = ip.activityRepo.GetActivityTypeByConfigKey(ctx, action)
+		if err != nil {
+			return err
+		}
+	}
+	// a user votes the same post only once
+	exist, err := ip.importerRepo.ExistActivity(ctx, objectID, userID, activityTypes[0], activityTypes[1])
 	if err != nil {
-		errlist, ok := errList.([]*validator.FormErrorField)
-		if ok {
-			errFields = append(errFields, errlist...)
+		return err
+	}
+	if exist {
+		return nil
+	}
+	voteType, votedType := activityTypes[0], activityTypes[2]
+	if !voteInfo.VoteUp {
+		voteType, votedType = activityTypes[1], activityTypes[3]
+	}
+	createdAt, _ := importTime(voteInfo.CreatedAt, time.Time{})
+	activities := []*entity.Activity{
+		{
+			UserID:           userID,
+			ObjectID:         objectID,
+			OriginalObjectID: objectID,
+			ActivityType:     voteType,
+			Cancelled:        entity.ActivityAvailable,
+			CreatedAt:        createdAt,
+		},
+		{
+			UserID:           objectUserID,
+			TriggerUserID:    converter.StringToInt64(userID),
+			ObjectID:         objectID,
+			OriginalObjectID: objectID,
+			ActivityType:     votedType,
+			Cancelled:        entity.ActivityAvailable,
+			CreatedAt:        createdAt,
+		},
+	}
+	if err = ip.importerRepo.AddActivities(ctx, activities); err != nil {
+		return err
+	}
+	return ip.importerRepo.UpdateVoteCount(ctx, objectID, activityTypes[0], activityTypes[1])
+}
+
+// getOrCreateUser get the user of the imported content by the external id or the email,
+// the user is created if not exists
+func (ip *ImporterService) getOrCreateUser(ctx context.Context, source string, importUser *plugin.ImportUser) (
+	userID string, err error) {
+	if importUser == nil || (len(importUser.ExternalID) == 0 && len(importUser.Email) == 0) {
+		return "", errors.BadRequest(reason.UserNotFound)
+	}
+	if len(importUser.ExternalID) > 0 {
+		userID, exist, err := ip.importerRepo.GetMapping(ctx, source, entity.ImportObjectTypeUser, importUser.ExternalID)
+		if err != nil {
+			return "", err
+		}
+		if exist {
+			return userID, nil
 		}
 	}
-	if len(errFields) > 0 {
-		return errors.BadRequest(reason.RequestFormatError)
+	if len(importUser.Email) > 0 {
+		userInfo, exist, err := ip.userRepo.GetByEmail(ctx, importUser.Email)
+		if err != nil {
+			return "", err
+		}
+		if exist {
+			return userInfo.ID, ip.addMapping(ctx, source, entity.ImportObjectTypeUser, importUser.ExternalID, userInfo.ID)
+		}
 	}
-	ginCtx := ctx.(*gin.Context)
-	req.UserAgent = ginCtx.GetHeader("User-Agent")
-	req.IP = ginCtx.ClientIP()
-	resp, err := ip.questionService.AddQuestion(ctx, req)
+
+	userInfo := &entity.User{
+		EMail:       importUser.Email,
+		DisplayName: truncate(importUser.DisplayName, 30),
+		MailStatus:  entity.EmailStatusToBeVerified,
+		Status:      entity.UserStatusAvailable,
+		Rank:        max(importUser.Reputation, 1),
+		Bio:         importUser.Bio,
+		BioHTML:     converter.Markdown2HTML(importUser.Bio),
+		Website:     truncate(importUser.Website, 255),
+		Location:    truncate(importUser.Location, 100),
+	}
+	if len(userInfo.EMail) > 0 {
+		userInfo.MailStatus = entity.EmailStatusAvailable
+	}
+	username := importUser.Username
+	if len(username) == 0 {
+		username = importUser.DisplayName
+	}
+	userInfo.Username, err = ip.userCommon.MakeUsername(ctx, username)
+	if err != nil {
+		log.Warnf("make username for imported user %s failed: %v", username, err)
+		userInfo.Username = random.Username()
+	}
+	if len(userInfo.DisplayName) == 0 {
+		userInfo.DisplayName = userInfo.Username
+	}
+	if err = ip.userRepo.AddUser(ctx, userInfo); err != nil {
+		return "", err
+	}
+	return userInfo.ID, ip.addMapping(ctx, source, entity.ImportObjectTypeUser, importUser.ExternalID, userInfo.ID)
+}
+
+// getObjectID get the id of the imported question or answer
+func (ip *ImporterService) getObjectID(ctx context.Context, source string, objectType plugin.ImportObjectType,
+	externalID string) (objectID string, err error) {
+	if objectType != plugin.ImportObjectTypeQuestion && objectType != plugin.ImportObjectTypeAnswer {
+		return "", errors.BadRequest(reason.ObjectNotFound)
+	}
+	objectID, exist, err := ip.importerRepo.GetMapping(ctx, source, string(objectType), externalID)
 	if err != nil {
-		errlist, ok := resp.([]*validator.FormErrorField)
-		if ok {
-			errFields = append(errFields, errlist...)
+		return "", err
+	}
+	if !exist {
+		return "", errors.BadRequest(reason.ObjectNotFound)
+	}
+	return objectID, nil
+}
+
+func (ip *ImporterService) addMapping(ctx context.Context, source, objectType, externalID, objectID string) error {
+	if len(externalID) == 0 {
+		return nil
+	}
+	return ip.importerRepo.AddMapping(ctx, &entity.ImportMapping{
+		Source:     source,
+		ObjectType: objectType,
+		ExternalID: externalID,
+		ObjectID:   objectID,
+	})
+}
+
+// saveAttachments save the attachments and replace their URLs in the content,
+// the HTML is rendered from the content if it is not provided
+func (ip *ImporterService) saveAttachments(ctx context.Context, userID, content, html string,
+	attachments []plugin.ImportAttachment) (newContent, newHTML string, err error) {
+	for _, attachment := range attachments {
+		fileURL, err := ip.uploaderService.SaveImportedFile(ctx, userID, attachment.FileName, attachment.Data)
+		if err != nil {
+			return "", "", err
+		}
+		if len(attachment.URL) == 0 {
+			continue
 		}
+		content = strings.ReplaceAll(content, attachment.URL, fileURL)
+		html = strings.ReplaceAll(html, attachment.URL, fileURL)
+	}
+	if len(html) == 0 {
+		return content, converter.Markdown2HTML(content), nil
 	}
+	return content, converter.SanitizeHTML(html), nil
+}
 
-	if len(errFields) > 0 {
-		log.Errorf("error: RequestFormatError")
-		return errors.BadRequest(reason.RequestFormatError)
+func (ip *ImporterService) addRevision(ctx context.Context, userID, objectID, title, revisionLog string,
+	content any, createdAt time.Time, current bool) (revisionID string, err error) {
+	contentJSON, _ := json.Marshal(content)
+	revisionID, err = ip.revisionService.AddRevision(ctx, &schema.AddRevisionDTO{
+		UserID:   userID,
+		ObjectID: objectID,
+		Title:    title,
+		Content:  string(contentJSON),
+		Log:      revisionLog,
+	}, current)
+	if err != nil {
+		return "", err
 	}
-	log.Info("Add Question Successfully")
-	return nil
+	if err = ip.importerRepo.UpdateRevisionTime(ctx, revisionID, createdAt); err != nil {
+		return "", err
+	}
+	return revisionID, nil
+}
+
+func (ip *ImporterService) newActivity(ctx context.Context, activityKey constant.ActivityTypeKey,
+	userID, objectID, originalObjectID string, createdAt time.Time) (*entity.Activity, error) {
+	activityType, err := ip.activityRepo.GetActivityTypeByConfigKey(ctx, string(activityKey))
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Activity{
+		UserID:           userID,
+		ObjectID:         objectID,
+		OriginalObjectID: originalObjectID,
+		ActivityType:     activityType,
+		Cancelled:        entity.ActivityAvailable,
+		CreatedAt:        createdAt,
+	}, nil
+}
+
+// importTime the creation time defaults to now and the update time defaults to the creation time
+func importTime(createdAt, updatedAt time.Time) (time.Time, time.Time) {
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	if updatedAt.IsZero() || updatedAt.Before(createdAt) {
+		updatedAt = createdAt
+	}
+	return createdAt, updatedAt
+}
+
+// truncate cut the string to fit the column of the user
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length])
 }
@@ -297,6 +297,21 @@ func (mr *MockSiteInfoCommonServiceMockRecorder) GetSiteMCP(ctx any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSiteMCP", reflect.TypeOf((*MockSiteInfoCommonService)(nil).GetSiteMCP), ctx)
 }
 
+// GetSiteSearch mocks base method.
+func (m *MockSiteInfoCommonService) GetSiteSearch(ctx context.Context) (*schema.SiteSearchResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSiteSearch", ctx)
+	ret0, _ := ret[0].(*schema.SiteSearchResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSiteSearch indicates an expected call of GetSiteSearch.
+func (mr *MockSiteInfoCommonServiceMockRecorder) GetSiteSearch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSiteSearch", reflect.TypeOf((*MockSiteInfoCommonService)(nil).GetSiteSearch), ctx)
+}
+
 // GetSitePolicies mocks base method.
 func (m *MockSiteInfoCommonService) GetSitePolicies(ctx context.Context) (*schema.SitePoliciesResp, error) {
 	m.ctrl.T.Helper()
@@ -29,6 +29,7 @@ import (
 	"github.com/apache/answer/internal/service/activity_common"
 	"github.com/apache/answer/internal/service/export"
 	"github.com/apache/answer/internal/service/noticequeue"
+	"github.com/apache/answer/internal/service/object_info"
 	"github.com/apache/answer/internal/service/siteinfo_common"
 	usercommon "github.com/apache/answer/internal/service/user_common"
 	"github.com/apache/answer/internal/service/user_external_login"
@@ -46,6 +47,7 @@ type ExternalNotificationService struct {
 	userExternalLoginRepo      user_external_login.UserExternalLoginRepo
 	siteInfoService            siteinfo_common.SiteInfoCommonService
 	newQuestionEmailWorker     *newQuestionEmailWorker
+	objectInfoService          *object_info.ObjService
 }
 
 func NewExternalNotificationService(
@@ -57,6 +59,7 @@ func NewExternalNotificationService(
 	notificationQueueService noticequeue.ExternalService,
 	userExternalLoginRepo user_external_login.UserExternalLoginRepo,
 	siteInfoService siteinfo_common.SiteInfoCommonService,
+	objectInfoService *object_info.ObjService,
 ) *ExternalNotificationService {
 	n := &ExternalNotificationService{
 		data:                       data,
@@ -67,6 +70,7 @@ func NewExternalNotificationService(
 		notificationQueueService:   notificationQueueService,
 		userExternalLoginRepo:      userExternalLoginRepo,
 		siteInfoService:            siteInfoService,
+		objectInfoService:          objectInfoService,
 	}
 	n.newQuestionEmailWorker = newQuestionEmailWorkerWithDefaults(
 		newQuestionNotificationEmailSendInterval,
@@ -89,12 +93,21 @@ func (ns *ExternalNotificationService) Handler(ctx context.Context, msg *schema.
 		return ns.handleNewQuestionNotification(ctx, msg)
 	}
 	if msg.NewCommentTemplateRawData != nil {
+		if !ns.canReceiverViewQuestion(ctx, msg.NewCommentTemplateRawData.QuestionID, msg.ReceiverUserID) {
+			return nil
+		}
 		return ns.handleNewCommentNotification(ctx, msg)
 	}
 	if msg.NewAnswerTemplateRawData != nil {
+		if !ns.canReceiverViewQuestion(ctx, msg.NewAnswerTemplateRawData.QuestionID, msg.ReceiverUserID) {
+			return nil
+		}
 		return ns.handleNewAnswerNotification(ctx, msg)
 	}
 	if msg.NewInviteAnswerTemplateRawData != nil {
+		if !ns.canReceiverViewQuestion(ctx, msg.NewInviteAnswerTemplateRawData.QuestionID, msg.ReceiverUserID) {
+			return nil
+		}
 		return ns.handleInviteAnswerNotification(ctx, msg)
 	}
 	log.Errorf("unknown notification message: %+v", msg)
@@ -113,3 +126,22 @@ func (ns *ExternalNotificationService) checkUserStatusBeforeNotification(ctx con
 	}
 	return false
 }
+
+// canReceiverViewQuestion whether the receiver can read the space of the question
+func (ns *ExternalNotificationService) canReceiverViewQuestion(ctx context.Context, questionID, userID string) bool {
+	objInfo, err := ns.objectInfoService.GetInfo(ctx, questionID)
+	if err != nil {
+		log.Errorf("get question %s info error: %v", questionID, err)
+		return false
+	}
+	return ns.canReceiverViewSpace(ctx, objInfo.SpaceID, userID)
+}
+
+// canReceiverViewSpace whether the receiver can read the contents of the space
+func (ns *ExternalNotificationService) canReceiverViewSpace(ctx context.Context, spaceID int, userID string) bool {
+	if spaceID == entity.PublicSpaceID {
+		return true
+	}
+	err := ns.objectInfoService.CheckSpaceVisibility(ctx, &schema.SimpleObjectInfo{SpaceID: spaceID}, userID)
+	return err == nil
+}
@@ -27,6 +27,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/pkg/token"
 	"github.com/segmentfault/pacman/log"
@@ -44,6 +45,7 @@ type newQuestionEmailTask struct {
 	QuestionID    string
 	Tags          []string
 	TagIDs        []string
+	TenantID      int
 }
 
 type newQuestionEmailIntervalProvider func() time.Duration
@@ -230,7 +232,7 @@ func (w *newQuestionEmailWorker) processTask(task newQuestionEmailTask, emailAtt
 			*emailAttemptSent = true
 			continue
 		}
-		w.send(w.ctx, userID, task.newRawData())
+		w.send(handler.WithTenantID(w.ctx, task.TenantID), userID, task.newRawData())
 		*emailAttemptSent = true
 	}
 	return true
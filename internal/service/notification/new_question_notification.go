@@ -25,7 +25,9 @@ import (
 	"time"
 
 	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/entity"
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/pkg/display"
 	"github.com/apache/answer/plugin"
@@ -49,16 +51,30 @@ func (ns *ExternalNotificationService) handleNewQuestionNotification(ctx context
 	}
 	log.Debugf("get subscribers %d for question %s", len(subscribers), msg.NewQuestionTemplateRawData.QuestionID)
 
+	// the questions of the spaces are only sent to the members, never to the plugins
+	if msg.NewQuestionTemplateRawData.SpaceID != entity.PublicSpaceID {
+		members := make([]*NewQuestionSubscriber, 0, len(subscribers))
+		for _, subscriber := range subscribers {
+			if ns.canReceiverViewSpace(ctx, msg.NewQuestionTemplateRawData.SpaceID, subscriber.UserID) {
+				members = append(members, subscriber)
+			}
+		}
+		ns.enqueueNewQuestionNotificationEmails(ctx, members, msg.NewQuestionTemplateRawData)
+		return nil
+	}
+
 	ns.syncNewQuestionNotificationToPlugin(ctx, msg)
-	ns.enqueueNewQuestionNotificationEmails(subscribers, msg.NewQuestionTemplateRawData)
+	ns.enqueueNewQuestionNotificationEmails(ctx, subscribers, msg.NewQuestionTemplateRawData)
 	return nil
 }
 
 func (ns *ExternalNotificationService) enqueueNewQuestionNotificationEmails(
+	ctx context.Context,
 	subscribers []*NewQuestionSubscriber,
 	rawData *schema.NewQuestionTemplateRawData,
 ) {
 	task := newQuestionEmailTaskFromRawData(collectNewQuestionNotificationEmailUserIDs(subscribers), rawData)
+	task.TenantID = handler.GetTenantID(ctx)
 	if len(task.UserIDs) == 0 {
 		return
 	}
@@ -674,6 +674,11 @@ func (newQuestionNotificationTestConfigRepo) UpdateConfig(context.Context, strin
 	return nil
 }
 
+func (newQuestionNotificationTestConfigRepo) GetTenantConfigListByKey(
+	context.Context, string) ([]*entity.TenantConfig, error) {
+	return nil, nil
+}
+
 type newQuestionNotificationTestEmailRepo struct {
 	codesByUserID map[string][]string
 }
@@ -142,6 +142,12 @@ func (ns *NotificationCommon) AddNotification(ctx context.Context, msg *schema.N
 			objectMap["comment"] = objInfo.CommentID
 			req.ObjectInfo.ObjectMap = objectMap
 		}
+		// the receiver who can not read the space is not notified, but the followers still are
+		if err := ns.objectInfoService.CheckSpaceVisibility(ctx, objInfo, req.ReceiverUserID); err != nil {
+			log.Debugf("skip the notification of %s to %s: %v", req.ObjectInfo.ObjectID, req.ReceiverUserID, err)
+			go ns.SendNotificationToAllFollower(ctx, msg, questionID)
+			return nil
+		}
 	}
 
 	if msg.Type == schema.NotificationTypeAchievement {
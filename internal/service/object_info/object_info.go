@@ -28,6 +28,7 @@ import (
 	answercommon "github.com/apache/answer/internal/service/answer_common"
 	"github.com/apache/answer/internal/service/comment_common"
 	questioncommon "github.com/apache/answer/internal/service/question_common"
+	"github.com/apache/answer/internal/service/space_common"
 	tagcommon "github.com/apache/answer/internal/service/tag_common"
 	"github.com/apache/answer/pkg/checker"
 	"github.com/apache/answer/pkg/obj"
@@ -41,6 +42,7 @@ type ObjService struct {
 	commentRepo  comment_common.CommentCommonRepo
 	tagRepo      tagcommon.TagCommonRepo
 	tagCommon    *tagcommon.TagCommonService
+	spaceCommon  *space_common.SpaceCommon
 }
 
 // NewObjService new object service
@@ -50,6 +52,7 @@ func NewObjService(
 	commentRepo comment_common.CommentCommonRepo,
 	tagRepo tagcommon.TagCommonRepo,
 	tagCommon *tagcommon.TagCommonService,
+	spaceCommon *space_common.SpaceCommon,
 ) *ObjService {
 	return &ObjService{
 		answerRepo:   answerRepo,
@@ -57,6 +60,7 @@ func NewObjService(
 		commentRepo:  commentRepo,
 		tagRepo:      tagRepo,
 		tagCommon:    tagCommon,
+		spaceCommon:  spaceCommon,
 	}
 }
 func (os *ObjService) GetUnreviewedRevisionInfo(ctx context.Context, objectID string) (objInfo *schema.UnreviewedRevisionInfoInfo, err error) {
@@ -209,6 +213,7 @@ func (os *ObjService) GetInfo(ctx context.Context, objectID string) (objInfo *sc
 			ObjectType:            objectType,
 			Title:                 questionInfo.Title,
 			Content:               questionInfo.ParsedText, // todo trim
+			SpaceID:               questionInfo.SpaceID,
 		}
 	case constant.AnswerObjectType:
 		answerInfo, exist, err := os.answerRepo.GetAnswer(ctx, objectID)
@@ -237,6 +242,7 @@ func (os *ObjService) GetInfo(ctx context.Context, objectID string) (objInfo *sc
 			ObjectType:            objectType,
 			Title:                 questionInfo.Title,    // this should be question title
 			Content:               answerInfo.ParsedText, // todo trim
+			SpaceID:               questionInfo.SpaceID,
 		}
 	case constant.CommentObjectType:
 		commentInfo, exist, err := os.commentRepo.GetComment(ctx, objectID)
@@ -265,6 +271,7 @@ func (os *ObjService) GetInfo(ctx context.Context, objectID string) (objInfo *sc
 				objInfo.QuestionStatus = questionInfo.Status
 				objInfo.QuestionShow = questionInfo.Show
 				objInfo.Title = questionInfo.Title
+				objInfo.SpaceID = questionInfo.SpaceID
 			}
 			answerInfo, exist, err := os.answerRepo.GetAnswer(ctx, commentInfo.ObjectID)
 			if err != nil {
@@ -290,6 +297,7 @@ func (os *ObjService) GetInfo(ctx context.Context, objectID string) (objInfo *sc
 			ObjectType:          objectType,
 			Title:               tagInfo.SlugName,
 			Content:             tagInfo.ParsedText, // todo trim
+			SpaceID:             tagInfo.SpaceID,
 		}
 	}
 	if objInfo == nil {
@@ -297,3 +305,49 @@ func (os *ObjService) GetInfo(ctx context.Context, objectID string) (objInfo *sc
 	}
 	return objInfo, err
 }
+
+// GetObjectTagIDs get the tags of the object, which are the tags of the question for the answer and the comment
+func (os *ObjService) GetObjectTagIDs(ctx context.Context, objectID string) (tagIDs []string, err error) {
+	objInfo, err := os.GetInfo(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(objInfo.TagID) > 0 {
+		return []string{objInfo.TagID}, nil
+	}
+	if len(objInfo.QuestionID) == 0 {
+		return nil, nil
+	}
+	tags, err := os.tagCommon.GetObjectEntityTag(ctx, objInfo.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	return tagIDs, nil
+}
+
+// CheckSpaceVisibility check that the user can read the space which the object belongs to
+func (os *ObjService) CheckSpaceVisibility(ctx context.Context, objInfo *schema.SimpleObjectInfo, userID string) error {
+	canView, err := os.spaceCommon.CanViewSpace(ctx, objInfo.SpaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !canView {
+		return errors.NotFound(reason.ObjectNotFound)
+	}
+	return nil
+}
+
+// CheckSpacePost check that the user can post in the space which the object belongs to
+func (os *ObjService) CheckSpacePost(ctx context.Context, objInfo *schema.SimpleObjectInfo, userID string) error {
+	canPost, err := os.spaceCommon.CanPostInSpace(ctx, objInfo.SpaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !canPost {
+		return errors.Forbidden(reason.SpaceNoPermission)
+	}
+	return nil
+}
@@ -27,6 +27,8 @@ const (
 	QuestionDelete              = "question.delete"
 	QuestionClose               = "question.close"
 	QuestionReopen              = "question.reopen"
+	QuestionCloseVote           = "question.close_vote"
+	QuestionBounty              = "question.bounty"
 	QuestionVoteUp              = "question.vote_up"
 	QuestionVoteDown            = "question.vote_down"
 	QuestionPin                 = "question.pin"
@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 
 	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/repo/search_sync"
 	"github.com/apache/answer/internal/repo/vector_search_sync"
 
@@ -42,6 +43,7 @@ import (
 type PluginConfigRepo interface {
 	SavePluginConfig(ctx context.Context, pluginSlugName, configValue string) (err error)
 	GetPluginConfigAll(ctx context.Context) (pluginConfigs []*entity.PluginConfig, err error)
+	GetTenantPluginConfigAll(ctx context.Context) (pluginConfigs []*entity.PluginConfig, err error)
 }
 
 type PluginUserConfigRepo interface {
@@ -81,40 +83,139 @@ func NewPluginCommonService(
 	return p
 }
 
-// UpdatePluginStatus update plugin status
-func (ps *PluginCommonService) UpdatePluginStatus(ctx context.Context) (err error) {
-	content, err := plugin.StatusManager.MarshalJSON()
+// UpdatePluginStatus update plugin status, the tenant can only enable the plugins enabled by the primary site
+func (ps *PluginCommonService) UpdatePluginStatus(ctx context.Context, req *schema.UpdatePluginStatusReq) (err error) {
+	tenantID := handler.GetTenantID(ctx)
+	if req.Enabled && tenantID != entity.DefaultTenantID && !plugin.StatusManager.IsEnabled(req.PluginSlugName) {
+		return errors.BadRequest(reason.TenantPluginDisabled)
+	}
+	plugin.StatusManager.EnableForTenant(tenantID, req.PluginSlugName, req.Enabled)
+
+	content, err := plugin.StatusManager.MarshalTenantJSON(tenantID)
 	if err != nil {
 		return errors.InternalServer(reason.UnknownError).WithError(err)
 	}
-	return ps.configService.UpdateConfig(ctx, constant.PluginStatus, string(content))
+	if err = ps.configService.UpdateConfig(ctx, constant.PluginStatus, string(content)); err != nil {
+		return err
+	}
+	ps.data.Bus.Publish(ctx, data.TopicPluginStatus, "")
+	return nil
+}
+
+// GetPluginConfigFields get the config fields of the plugin for the tenant
+func (ps *PluginCommonService) GetPluginConfigFields(ctx context.Context, fn plugin.Config) (
+	fields []plugin.ConfigField, err error) {
+	fields, err = plugin.GetConfigFields(handler.GetTenantID(ctx), fn)
+	if err != nil {
+		return nil, errors.BadRequest(reason.TenantPluginConfigUnsupported).WithError(err)
+	}
+	return fields, nil
 }
 
 // UpdatePluginConfig update plugin config
 func (ps *PluginCommonService) UpdatePluginConfig(ctx context.Context, req *schema.UpdatePluginConfigReq) (err error) {
 	configValue, _ := json.Marshal(req.ConfigFields)
+	err = plugin.CallConfig(func(fn plugin.Config) error {
+		if fn.Info().SlugName != req.PluginSlugName {
+			return nil
+		}
+		err := plugin.ReceiveConfig(handler.GetTenantID(ctx), fn, configValue)
+		if err == plugin.ErrTenantConfigUnsupported {
+			return errors.BadRequest(reason.TenantPluginConfigUnsupported)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
 	err = ps.pluginConfigRepo.SavePluginConfig(ctx, req.PluginSlugName, string(configValue))
 	if err != nil {
 		return err
 	}
+	ps.registerPluginFunc(ctx, req.PluginSlugName)
+	ps.data.Bus.Publish(ctx, data.TopicPluginConfig, req.PluginSlugName)
+	return nil
+}
 
+// registerPluginFunc register the functions of the core to the plugin after its config is changed
+func (ps *PluginCommonService) registerPluginFunc(ctx context.Context, pluginSlugName string) {
 	_ = plugin.CallSearch(func(search plugin.Search) error {
-		if search.Info().SlugName == req.PluginSlugName {
+		if search.Info().SlugName == pluginSlugName {
 			search.RegisterSyncer(ctx, search_sync.NewPluginSyncer(ps.data))
 		}
 		return nil
 	})
 	_ = plugin.CallVectorSearch(func(vs plugin.VectorSearch) error {
-		if vs.Info().SlugName == req.PluginSlugName {
+		if vs.Info().SlugName == pluginSlugName {
 			vs.RegisterSyncer(ctx, vector_search_sync.NewPluginSyncer(ps.data))
 		}
 		return nil
 	})
 	_ = plugin.CallImporter(func(importer plugin.Importer) error {
-		importer.RegisterImporterFunc(ctx, ps.importerService.NewImporterFunc())
+		importer.RegisterImporterFunc(ctx, ps.importerService.NewImporterFunc(importer.Info().SlugName))
 		return nil
 	})
-	return nil
+}
+
+// loadPluginStatus load the status of the plugins of the tenant in the context from the database
+func (ps *PluginCommonService) loadPluginStatus(ctx context.Context) {
+	pluginStatus, err := ps.configService.GetStringValueFromDB(ctx, constant.PluginStatus)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if err := plugin.StatusManager.UnmarshalTenantJSON(handler.GetTenantID(ctx), []byte(pluginStatus)); err != nil {
+		log.Error(err)
+	}
+}
+
+// loadAllPluginStatus load the status of the plugins of the primary site and all tenants from the database
+func (ps *PluginCommonService) loadAllPluginStatus(ctx context.Context) {
+	ps.loadPluginStatus(ctx)
+	tenantStatus, err := ps.configService.GetTenantValuesByKey(ctx, constant.PluginStatus)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for tenantID, pluginStatus := range tenantStatus {
+		if err := plugin.StatusManager.UnmarshalTenantJSON(tenantID, []byte(pluginStatus)); err != nil {
+			log.Errorf("parse plugin status of tenant %d failed: %v", tenantID, err)
+		}
+	}
+}
+
+// reloadPluginConfig reload the config of the plugin changed by the other instance, all plugins of all tenants for InvalidateAll
+func (ps *PluginCommonService) reloadPluginConfig(ctx context.Context, pluginSlugName string) {
+	pluginConfigs, err := ps.pluginConfigRepo.GetPluginConfigAll(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if pluginSlugName == data.InvalidateAll {
+		tenantPluginConfigs, err := ps.pluginConfigRepo.GetTenantPluginConfigAll(ctx)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		pluginConfigs = append(pluginConfigs, tenantPluginConfigs...)
+	}
+	for _, pluginConfig := range pluginConfigs {
+		if pluginSlugName != data.InvalidateAll && pluginConfig.PluginSlugName != pluginSlugName {
+			continue
+		}
+		err := plugin.CallConfig(func(fn plugin.Config) error {
+			if fn.Info().SlugName == pluginConfig.PluginSlugName {
+				return plugin.ReceiveConfig(pluginConfig.TenantID, fn, []byte(pluginConfig.Value))
+			}
+			return nil
+		})
+		if err != nil {
+			log.Errorf("parse plugin config failed: %s %d %v", pluginConfig.PluginSlugName, pluginConfig.TenantID, err)
+			continue
+		}
+		ps.registerPluginFunc(handler.WithTenantID(ctx, pluginConfig.TenantID), pluginConfig.PluginSlugName)
+	}
 }
 
 // UpdatePluginUserConfig update plugin config
@@ -142,8 +243,9 @@ func (ps *PluginCommonService) GetUserPluginConfig(ctx context.Context, req *sch
 
 func (ps *PluginCommonService) initPluginData() {
 	_ = plugin.CallKVStorage(func(k plugin.KVStorage) error {
+		// the data of the plugins is kept for the tenant of the context
 		k.SetOperator(plugin.NewKVOperator(
-			ps.data.DB,
+			ps.data.DB.Engine,
 			ps.data.Cache,
 			k.Info().SlugName,
 		))
@@ -151,14 +253,17 @@ func (ps *PluginCommonService) initPluginData() {
 	})
 
 	// init plugin status
-	pluginStatus, err := ps.configService.GetStringValueFromDB(context.TODO(), constant.PluginStatus)
-	if err != nil {
-		log.Error(err)
-	} else {
-		if err := plugin.StatusManager.UnmarshalJSON([]byte(pluginStatus)); err != nil {
-			log.Error(err)
+	ps.loadAllPluginStatus(context.TODO())
+
+	// the plugins changed on the other instances of the site
+	ps.data.Bus.Subscribe(data.TopicPluginStatus, func(ctx context.Context, payload string) {
+		if payload == data.InvalidateAll {
+			ps.loadAllPluginStatus(ctx)
+			return
 		}
-	}
+		ps.loadPluginStatus(ctx)
+	})
+	ps.data.Bus.Subscribe(data.TopicPluginConfig, ps.reloadPluginConfig)
 
 	// init plugin config
 	pluginConfigs, err := ps.pluginConfigRepo.GetPluginConfigAll(context.Background())
@@ -177,8 +282,25 @@ func (ps *PluginCommonService) initPluginData() {
 			}
 		}
 
+		// the tenant aware plugins receive the configs of the tenants
+		tenantPluginConfigs, err := ps.pluginConfigRepo.GetTenantPluginConfigAll(context.Background())
+		if err != nil {
+			log.Error(err)
+		}
+		for _, pluginConfig := range tenantPluginConfigs {
+			err := plugin.CallConfig(func(fn plugin.Config) error {
+				if fn.Info().SlugName == pluginConfig.PluginSlugName {
+					return plugin.ReceiveConfig(pluginConfig.TenantID, fn, []byte(pluginConfig.Value))
+				}
+				return nil
+			})
+			if err != nil {
+				log.Errorf("parse plugin config failed: %s %d %v", pluginConfig.PluginSlugName, pluginConfig.TenantID, err)
+			}
+		}
+
 		_ = plugin.CallCache(func(cache plugin.Cache) error {
-			ps.data.Cache = cache
+			ps.data.Cache = data.NewTenantCache(cache)
 			return nil
 		})
 	}
@@ -29,6 +29,8 @@ import (
 	"github.com/apache/answer/internal/service/apikey"
 	"github.com/apache/answer/internal/service/auth"
 	"github.com/apache/answer/internal/service/badge"
+	"github.com/apache/answer/internal/service/bounty"
+	"github.com/apache/answer/internal/service/close_vote"
 	"github.com/apache/answer/internal/service/collection"
 	collectioncommon "github.com/apache/answer/internal/service/collection_common"
 	"github.com/apache/answer/internal/service/comment"
@@ -36,10 +38,12 @@ import (
 	"github.com/apache/answer/internal/service/config"
 	"github.com/apache/answer/internal/service/content"
 	"github.com/apache/answer/internal/service/dashboard"
+	"github.com/apache/answer/internal/service/draft"
 	"github.com/apache/answer/internal/service/embedding"
 	"github.com/apache/answer/internal/service/eventqueue"
 	"github.com/apache/answer/internal/service/export"
 	"github.com/apache/answer/internal/service/feature_toggle"
+	"github.com/apache/answer/internal/service/feed"
 	"github.com/apache/answer/internal/service/file_record"
 	"github.com/apache/answer/internal/service/follow"
 	"github.com/apache/answer/internal/service/importer"
@@ -58,16 +62,28 @@ import (
 	"github.com/apache/answer/internal/service/review"
 	"github.com/apache/answer/internal/service/revision_common"
 	"github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/role_admin"
+	"github.com/apache/answer/internal/service/saved_search"
+	"github.com/apache/answer/internal/service/scheduled_post"
+	"github.com/apache/answer/internal/service/scim"
 	"github.com/apache/answer/internal/service/search_parser"
 	"github.com/apache/answer/internal/service/siteinfo"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/space"
+	"github.com/apache/answer/internal/service/space_common"
+	"github.com/apache/answer/internal/service/sso"
 	"github.com/apache/answer/internal/service/tag"
 	tagcommon "github.com/apache/answer/internal/service/tag_common"
+	"github.com/apache/answer/internal/service/tag_suggestion"
+	"github.com/apache/answer/internal/service/tenant"
+	"github.com/apache/answer/internal/service/two_factor"
 	"github.com/apache/answer/internal/service/uploader"
 	"github.com/apache/answer/internal/service/user_admin"
 	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/internal/service/user_data"
 	"github.com/apache/answer/internal/service/user_external_login"
 	"github.com/apache/answer/internal/service/user_notification_config"
+	"github.com/apache/answer/internal/service/user_session"
 	"github.com/apache/answer/internal/service/vector_sync"
 	"github.com/google/wire"
 )
@@ -138,4 +154,20 @@ var ProviderSetService = wire.NewSet(
 	feature_toggle.NewFeatureToggleService,
 	embedding.NewEmbeddingService,
 	vector_sync.NewService,
+	feed.NewFeedService,
+	saved_search.NewSavedSearchService,
+	tag_suggestion.NewTagSuggestionService,
+	close_vote.NewCloseVoteService,
+	bounty.NewBountyService,
+	draft.NewDraftService,
+	scheduled_post.NewScheduledPostService,
+	space_common.NewSpaceCommon,
+	space.NewSpaceService,
+	tenant.NewTenantService,
+	sso.NewSSOService,
+	scim.NewSCIMService,
+	two_factor.NewTwoFactorService,
+	user_session.NewUserSessionService,
+	role_admin.NewRoleAdminService,
+	user_data.NewUserDataService,
 )
@@ -52,6 +52,9 @@ import (
 	"github.com/segmentfault/pacman/log"
 )
 
+// maxDuplicateChainDepth the max number of duplicate links followed to find the canonical question
+const maxDuplicateChainDepth = 10
+
 // QuestionRepo question repository
 type QuestionRepo interface {
 	AddQuestion(ctx context.Context, question *entity.Question) (err error)
@@ -59,7 +62,7 @@ type QuestionRepo interface {
 	UpdateQuestion(ctx context.Context, question *entity.Question, Cols []string) (err error)
 	GetQuestion(ctx context.Context, id string) (question *entity.Question, exist bool, err error)
 	GetQuestionList(ctx context.Context, question *entity.Question) (questions []*entity.Question, err error)
-	GetQuestionPage(ctx context.Context, page, pageSize int, tagIDs []string, userID, orderCond string, inDays int, showHidden, showPending bool) (
+	GetQuestionPage(ctx context.Context, page, pageSize int, tagIDs []string, userID, orderCond string, inDays int, showHidden, showPending bool, spaceIDs []int) (
 		questionList []*entity.Question, total int64, err error)
 	GetRecommendQuestionPageByTags(ctx context.Context, userID string, tagIDs, followedQuestionIDs []string, page, pageSize int) (questionList []*entity.Question, total int64, err error)
 	UpdateQuestionStatus(ctx context.Context, questionID string, status int) (err error)
@@ -285,6 +288,9 @@ func (qs *QuestionCommon) Info(ctx context.Context, questionID string, loginUser
 					operation.Time = metaInfo.CreatedAt.Unix()
 					operation.Level = schema.OperationLevelInfo
 					resp.Operation = operation
+					if cfg.Key == constant.ReasonADuplicate {
+						resp.DuplicateQuestionID = qs.getCanonicalQuestionIDForShow(ctx, questionInfo.ID, closeMsg)
+					}
 				}
 			}
 		}
@@ -381,6 +387,7 @@ func (qs *QuestionCommon) FormatQuestionsPage(
 			AnswerCount:      questionInfo.AnswerCount,
 			CollectionCount:  questionInfo.CollectionCount,
 			FollowCount:      questionInfo.FollowCount,
+			BountyAmount:     questionInfo.BountyAmount,
 			AcceptedAnswerID: questionInfo.AcceptedAnswerID,
 			LastAnswerID:     questionInfo.LastAnswerID,
 			Pin:              questionInfo.Pin,
@@ -655,6 +662,10 @@ func (qs *QuestionCommon) ShowFormat(ctx context.Context, data *entity.Question)
 	info.AnswerCount = data.AnswerCount
 	info.CollectionCount = data.CollectionCount
 	info.FollowCount = data.FollowCount
+	info.CloseVoteCount = data.CloseVoteCount
+	info.ReopenVoteCount = data.ReopenVoteCount
+	info.BountyAmount = data.BountyAmount
+	info.SpaceID = data.SpaceID
 	info.AcceptedAnswerID = data.AcceptedAnswerID
 	info.LastAnswerID = data.LastAnswerID
 	info.CreateTime = data.CreatedAt.Unix()
@@ -663,6 +674,9 @@ func (qs *QuestionCommon) ShowFormat(ctx context.Context, data *entity.Question)
 	if data.PostUpdateTime.Unix() < 1 {
 		info.PostUpdateTime = 0
 	}
+	if data.Status == entity.QuestionStatusScheduled {
+		info.PublishAt = data.PublishAt.Unix()
+	}
 	info.QuestionUpdateTime = data.UpdatedAt.Unix()
 	if data.UpdatedAt.Unix() < 1 {
 		info.QuestionUpdateTime = 0
@@ -701,9 +715,11 @@ func (qs *QuestionCommon) ShowFormatWithTag(ctx context.Context, data *entity.Qu
 }
 
 func (qs *QuestionCommon) UpdateQuestionLink(ctx context.Context, questionID, answerID, parsedText, originalText string) (string, error) {
+	// only the links referenced by the content are rebuilt, the duplicate link is kept
 	err := qs.questionRepo.RemoveQuestionLink(ctx, &entity.QuestionLink{
 		FromQuestionID: uid.DeShortID(questionID),
 		FromAnswerID:   uid.DeShortID(answerID),
+		LinkType:       entity.QuestionLinkTypeReference,
 	})
 	if err != nil {
 		return parsedText, err
@@ -827,29 +843,94 @@ func (qs *QuestionCommon) UpdateQuestionLink(ctx context.Context, questionID, an
 	return parsedText, nil
 }
 
-// AddQuestionLinkForCloseReason When the reason about close question is a question link, add the link to the question
-func (qs *QuestionCommon) AddQuestionLinkForCloseReason(ctx context.Context,
-	questionInfo *entity.Question, closeMsg string) {
-	questionID := qs.tryToGetQuestionIDFromMsg(ctx, closeMsg)
-	if len(questionID) == 0 {
-		return
+// GetDuplicateQuestionID get the question that the closed question duplicates, the questions closed
+// before the structured duplicate link only have the question url in the close message.
+func (qs *QuestionCommon) GetDuplicateQuestionID(ctx context.Context, closeMeta *schema.CloseQuestionMeta) string {
+	if len(closeMeta.DuplicateQuestionID) > 0 {
+		return uid.DeShortID(closeMeta.DuplicateQuestionID)
+	}
+	return qs.tryToGetQuestionIDFromMsg(ctx, closeMeta.CloseMsg)
+}
+
+// GetCanonicalQuestionID follow the duplicate links from the question to the question that is not a duplicate,
+// return empty if the links lead back to the question that is going to be closed.
+func (qs *QuestionCommon) GetCanonicalQuestionID(ctx context.Context, closingQuestionID, questionID string) (
+	canonicalID string, err error) {
+	closingQuestionID = uid.DeShortID(closingQuestionID)
+	canonicalID = uid.DeShortID(questionID)
+	for range maxDuplicateChainDepth {
+		if canonicalID == closingQuestionID {
+			return "", nil
+		}
+		questionInfo, exist, err := qs.questionRepo.GetQuestion(ctx, canonicalID)
+		if err != nil {
+			return "", err
+		}
+		if !exist || questionInfo.Status == entity.QuestionStatusDeleted {
+			return "", errors.BadRequest(reason.QuestionNotFound)
+		}
+		if questionInfo.Status != entity.QuestionStatusClosed {
+			return canonicalID, nil
+		}
+		duplicateID := qs.getDuplicateQuestionIDOfClosed(ctx, canonicalID)
+		if len(duplicateID) == 0 {
+			return canonicalID, nil
+		}
+		canonicalID = duplicateID
 	}
+	return canonicalID, nil
+}
 
-	linkedQuestion, exist, err := qs.questionRepo.GetQuestion(ctx, questionID)
+// getDuplicateQuestionIDOfClosed get the question that the closed question duplicates,
+// return empty if the question is closed for other reasons.
+func (qs *QuestionCommon) getDuplicateQuestionIDOfClosed(ctx context.Context, questionID string) string {
+	metaInfo, err := qs.metaCommonService.GetMetaByObjectIdAndKey(ctx, questionID, entity.QuestionCloseReasonKey)
 	if err != nil {
-		log.Errorf("get question error %s", err)
-		return
+		return ""
 	}
-	if !exist {
-		return
+	closeMeta := &schema.CloseQuestionMeta{}
+	if err = json.Unmarshal([]byte(metaInfo.Value), closeMeta); err != nil {
+		return ""
 	}
-	err = qs.questionRepo.LinkQuestion(ctx, &entity.QuestionLink{
-		FromQuestionID: questionInfo.ID,
-		ToQuestionID:   linkedQuestion.ID,
+	cfg, err := qs.configService.GetConfigByID(ctx, closeMeta.CloseType)
+	if err != nil || cfg.Key != constant.ReasonADuplicate {
+		return ""
+	}
+	return qs.GetDuplicateQuestionID(ctx, closeMeta)
+}
+
+// getCanonicalQuestionIDForShow get the canonical question of the duplicate question to show and redirect to,
+// the canonical question is not shown if the duplicate links are broken or form a cycle.
+func (qs *QuestionCommon) getCanonicalQuestionIDForShow(ctx context.Context, questionID string,
+	closeMeta *schema.CloseQuestionMeta) string {
+	duplicateQuestionID := qs.GetDuplicateQuestionID(ctx, closeMeta)
+	if len(duplicateQuestionID) == 0 {
+		return ""
+	}
+	canonicalID, err := qs.GetCanonicalQuestionID(ctx, questionID, duplicateQuestionID)
+	if err != nil || len(canonicalID) == 0 {
+		return ""
+	}
+	if handler.GetEnableShortID(ctx) {
+		canonicalID = uid.EnShortID(canonicalID)
+	}
+	return canonicalID
+}
+
+// AddDuplicateQuestionLink link the question to the question it duplicates
+func (qs *QuestionCommon) AddDuplicateQuestionLink(ctx context.Context, questionID, duplicateQuestionID string) {
+	err := qs.questionRepo.LinkQuestion(ctx, &entity.QuestionLink{
+		FromQuestionID: questionID,
+		ToQuestionID:   duplicateQuestionID,
+		LinkType:       entity.QuestionLinkTypeDuplicate,
 		Status:         entity.QuestionLinkStatusAvailable,
 	})
 	if err != nil {
 		log.Errorf("link question error %s", err)
+		return
+	}
+	if err = qs.questionRepo.UpdateQuestionLinkCount(ctx, uid.DeShortID(duplicateQuestionID)); err != nil {
+		log.Errorf("update question link count error %v", err)
 	}
 }
 
@@ -863,16 +944,21 @@ func (qs *QuestionCommon) RemoveQuestionLinkForReopen(ctx context.Context, quest
 	closeMsgMeta := &schema.CloseQuestionMeta{}
 	_ = json.Unmarshal([]byte(metaInfo.Value), closeMsgMeta)
 
-	linkedQuestionID := qs.tryToGetQuestionIDFromMsg(ctx, closeMsgMeta.CloseMsg)
+	linkedQuestionID := qs.GetDuplicateQuestionID(ctx, closeMsgMeta)
 	if len(linkedQuestionID) == 0 {
 		return
 	}
 	err = qs.questionRepo.RemoveQuestionLink(ctx, &entity.QuestionLink{
 		FromQuestionID: questionInfo.ID,
 		ToQuestionID:   linkedQuestionID,
+		LinkType:       entity.QuestionLinkTypeDuplicate,
 	})
 	if err != nil {
 		log.Errorf("remove question link error %s", err)
+		return
+	}
+	if err = qs.questionRepo.UpdateQuestionLinkCount(ctx, linkedQuestionID); err != nil {
+		log.Errorf("update question link count error %v", err)
 	}
 }
 
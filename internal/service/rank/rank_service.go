@@ -21,6 +21,7 @@ package rank
 
 import (
 	"context"
+	"slices"
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/handler"
@@ -99,7 +100,7 @@ func (rs *RankService) CheckOperationPermission(ctx context.Context, userID stri
 	if !exist {
 		return false, nil
 	}
-	powerMapping := rs.getUserPowerMapping(ctx, userID)
+	powerMapping := rs.getUserPowerMapping(ctx, userID, objectID)
 	if powerMapping[action] {
 		return true, nil
 	}
@@ -120,8 +121,9 @@ func (rs *RankService) CheckOperationPermission(ctx context.Context, userID stri
 	return can, nil
 }
 
-// CheckOperationPermissionsForRanks verify that the user has permission
-func (rs *RankService) CheckOperationPermissionsForRanks(ctx context.Context, userID string, actions []string) (
+// CheckOperationPermissionsForRanks verify that the user has permission, the powers of the role scoped to tags
+// are only granted on the object with one of the tags, so the object id is empty when no object is operated
+func (rs *RankService) CheckOperationPermissionsForRanks(ctx context.Context, userID, objectID string, actions []string) (
 	can []bool, requireRanks []int, err error) {
 	can = make([]bool, len(actions))
 	requireRanks = make([]int, len(actions))
@@ -138,7 +140,7 @@ func (rs *RankService) CheckOperationPermissionsForRanks(ctx context.Context, us
 		return can, requireRanks, nil
 	}
 
-	powerMapping := rs.getUserPowerMapping(ctx, userID)
+	powerMapping := rs.getUserPowerMapping(ctx, userID, objectID)
 	for idx, action := range actions {
 		if powerMapping[action] {
 			can[idx] = true
@@ -152,9 +154,9 @@ func (rs *RankService) CheckOperationPermissionsForRanks(ctx context.Context, us
 }
 
 // CheckOperationPermissions verify that the user has permission
-func (rs *RankService) CheckOperationPermissions(ctx context.Context, userID string, actions []string) (
+func (rs *RankService) CheckOperationPermissions(ctx context.Context, userID, objectID string, actions []string) (
 	can []bool, err error) {
-	can, _, err = rs.CheckOperationPermissionsForRanks(ctx, userID, actions)
+	can, _, err = rs.CheckOperationPermissionsForRanks(ctx, userID, objectID, actions)
 	return can, err
 }
 
@@ -214,7 +216,7 @@ func (rs *RankService) CheckVotePermission(ctx context.Context, userID, objectID
 			action = permission.CommentVoteDown
 		}
 	}
-	powerMapping := rs.getUserPowerMapping(ctx, userID)
+	powerMapping := rs.getUserPowerMapping(ctx, userID, objectID)
 	if powerMapping[action] {
 		return true, 0, nil
 	}
@@ -222,8 +224,9 @@ func (rs *RankService) CheckVotePermission(ctx context.Context, userID, objectID
 	return can, needRank, nil
 }
 
-// getUserPowerMapping get user power mapping
-func (rs *RankService) getUserPowerMapping(ctx context.Context, userID string) (powerMapping map[string]bool) {
+// getUserPowerMapping get user power mapping. The powers of the role scoped to some tags
+// apply only to the object with one of the tags, and never to the action without an object.
+func (rs *RankService) getUserPowerMapping(ctx context.Context, userID, objectID string) (powerMapping map[string]bool) {
 	powerMapping = make(map[string]bool, 0)
 	userRole, err := rs.roleService.GetUserRole(ctx, userID)
 	if err != nil {
@@ -235,6 +238,9 @@ func (rs *RankService) getUserPowerMapping(ctx context.Context, userID string) (
 		log.Error(err)
 		return powerMapping
 	}
+	if len(powers) > 0 && !rs.inRoleTagScope(ctx, userRole, objectID) {
+		return powerMapping
+	}
 
 	for _, power := range powers {
 		powerMapping[power] = true
@@ -242,6 +248,32 @@ func (rs *RankService) getUserPowerMapping(ctx context.Context, userID string) (
 	return powerMapping
 }
 
+// inRoleTagScope check the object has one of the tags the role is scoped to, it is always true for the role without tags
+func (rs *RankService) inRoleTagScope(ctx context.Context, roleID int, objectID string) bool {
+	roleTagIDs, err := rs.rolePowerService.GetRoleTagIDs(ctx, roleID)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if len(roleTagIDs) == 0 {
+		return true
+	}
+	if len(objectID) == 0 {
+		return false
+	}
+	objectTagIDs, err := rs.objectInfoService.GetObjectTagIDs(ctx, uid.DeShortID(objectID))
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	for _, tagID := range objectTagIDs {
+		if slices.Contains(roleTagIDs, tagID) {
+			return true
+		}
+	}
+	return false
+}
+
 // checkUserRank verify that the user meets the prestige criteria
 func (rs *RankService) checkUserRank(ctx context.Context, userID string, userRank int, action string) (
 	can bool, rank int) {
@@ -284,12 +316,12 @@ func (rs *RankService) GetRankPersonalPage(ctx context.Context, req *schema.GetR
 		return nil, err
 	}
 
-	resp := rs.decorateRankPersonalPageResp(ctx, userRankPage)
+	resp := rs.decorateRankPersonalPageResp(ctx, userRankPage, req.LoginUserID)
 	return pager.NewPageModel(total, resp), nil
 }
 
 func (rs *RankService) decorateRankPersonalPageResp(
-	ctx context.Context, userRankPage []*entity.Activity) []*schema.GetRankPersonalPageResp {
+	ctx context.Context, userRankPage []*entity.Activity, loginUserID string) []*schema.GetRankPersonalPageResp {
 	resp := make([]*schema.GetRankPersonalPageResp, 0)
 	lang := handler.GetLangByCtx(ctx)
 
@@ -302,6 +334,10 @@ func (rs *RankService) decorateRankPersonalPageResp(
 			log.Error(err)
 			continue
 		}
+		// the objects in the spaces that the viewer cannot read are not shown
+		if rs.objectInfoService.CheckSpaceVisibility(ctx, objInfo, loginUserID) != nil {
+			continue
+		}
 
 		commentResp := &schema.GetRankPersonalPageResp{
 			CreatedAt:  userRankInfo.CreatedAt.Unix(),
@@ -21,6 +21,7 @@ package review
 
 import (
 	"context"
+	"time"
 
 	"github.com/apache/answer/internal/base/constant"
 	"github.com/apache/answer/internal/base/pager"
@@ -281,19 +282,26 @@ func (cs *ReviewService) updateObjectStatus(ctx context.Context, review *entity.
 		}
 		if isApprove {
 			questionInfo.Status = entity.QuestionStatusAvailable
+			// the approved question waits for the publish time, it is notified when published
+			if questionInfo.PublishAt.After(time.Now()) {
+				questionInfo.Status = entity.QuestionStatusScheduled
+			}
 		} else {
 			questionInfo.Status = entity.QuestionStatusDeleted
 		}
 		if err := cs.questionRepo.UpdateQuestionStatus(ctx, questionInfo.ID, questionInfo.Status); err != nil {
 			return err
 		}
+		if questionInfo.Status == entity.QuestionStatusScheduled {
+			return nil
+		}
 		if isApprove {
 			tags, err := cs.tagCommon.GetObjectEntityTag(ctx, questionInfo.ID)
 			if err != nil {
 				log.Errorf("get question tags failed, err: %v", err)
 			}
 			cs.externalNotificationQueueService.Send(ctx,
-				schema.CreateNewQuestionNotificationMsg(questionInfo.ID, questionInfo.Title, questionInfo.UserID, tags))
+				schema.CreateNewQuestionNotificationMsg(questionInfo.ID, questionInfo.Title, questionInfo.UserID, questionInfo.SpaceID, tags))
 			cs.vectorSyncService.Send(ctx, &vector_sync.Task{Action: vector_sync.ActionUpsert, ObjectType: vector_sync.ObjectTypeQuestion, ObjectID: questionInfo.ID})
 		} else {
 			cs.vectorSyncService.Send(ctx, &vector_sync.Task{Action: vector_sync.ActionDelete, ObjectType: vector_sync.ObjectTypeQuestion, ObjectID: questionInfo.ID})
@@ -317,12 +325,19 @@ func (cs *ReviewService) updateObjectStatus(ctx context.Context, review *entity.
 		}
 		if isApprove {
 			answerInfo.Status = entity.AnswerStatusAvailable
+			// the approved answer waits for the publish time, it is notified when published
+			if answerInfo.PublishAt.After(time.Now()) {
+				answerInfo.Status = entity.AnswerStatusScheduled
+			}
 		} else {
 			answerInfo.Status = entity.AnswerStatusDeleted
 		}
 		if err := cs.answerRepo.UpdateAnswerStatus(ctx, answerInfo.ID, answerInfo.Status); err != nil {
 			return err
 		}
+		if answerInfo.Status == entity.AnswerStatusScheduled {
+			return nil
+		}
 		questionInfo, exist, err := cs.questionRepo.GetQuestion(ctx, answerInfo.QuestionID)
 		if err != nil {
 			return err
@@ -26,19 +26,23 @@ import (
 // RolePowerRelRepo rolePowerRel repository
 type RolePowerRelRepo interface {
 	GetRolePowerTypeList(ctx context.Context, roleID int) (powers []string, err error)
+	SaveRolePowers(ctx context.Context, roleID int, powerTypes []string) (err error)
 }
 
 // RolePowerRelService user service
 type RolePowerRelService struct {
 	rolePowerRelRepo   RolePowerRelRepo
+	roleTagRelRepo     RoleTagRelRepo
 	userRoleRelService *UserRoleRelService
 }
 
 // NewRolePowerRelService new role power rel service
 func NewRolePowerRelService(rolePowerRelRepo RolePowerRelRepo,
+	roleTagRelRepo RoleTagRelRepo,
 	userRoleRelService *UserRoleRelService) *RolePowerRelService {
 	return &RolePowerRelService{
 		rolePowerRelRepo:   rolePowerRelRepo,
+		roleTagRelRepo:     roleTagRelRepo,
 		userRoleRelService: userRoleRelService,
 	}
 }
@@ -56,3 +60,11 @@ func (rs *RolePowerRelService) GetUserPowerList(ctx context.Context, userID stri
 	}
 	return rs.rolePowerRelRepo.GetRolePowerTypeList(ctx, roleID)
 }
+
+// GetRoleTagIDs get the tags the powers of the role are scoped to, empty if the powers apply everywhere
+func (rs *RolePowerRelService) GetRoleTagIDs(ctx context.Context, roleID int) (tagIDs []string, err error) {
+	if IsBuiltInRole(roleID) {
+		return nil, nil
+	}
+	return rs.roleTagRelRepo.GetRoleTagIDs(ctx, roleID)
+}
@@ -30,9 +30,8 @@ import (
 )
 
 const (
-	// Since there is currently no need to edit roles to add roles and other operations,
-	// the current role information is translated directly.
-	// Later on, when the relevant ability is available, it can be adjusted by the user himself.
+	// The built-in roles can not be edited, their information is translated directly.
+	// The custom roles added by the administrators are shown as they are named.
 
 	RoleUserID      = 1
 	RoleAdminID     = 2
@@ -55,6 +54,30 @@ const (
 type RoleRepo interface {
 	GetRoleAllList(ctx context.Context) (roles []*entity.Role, err error)
 	GetRoleAllMapping(ctx context.Context) (roleMapping map[int]*entity.Role, err error)
+	GetRole(ctx context.Context, roleID int) (role *entity.Role, exist bool, err error)
+	AddRole(ctx context.Context, role *entity.Role) (err error)
+	UpdateRole(ctx context.Context, role *entity.Role) (err error)
+	DeleteRole(ctx context.Context, roleID int) (err error)
+}
+
+// IsBuiltInRole the user, admin and moderator roles are built in, the others are added by the administrators
+func IsBuiltInRole(roleID int) bool {
+	return roleID == RoleUserID || roleID == RoleAdminID || roleID == RoleModeratorID
+}
+
+// Priority the user having several roles by the groups gets the most privileged one,
+// the custom roles are above the user role and below the moderator role
+func Priority(roleID int) int {
+	switch roleID {
+	case RoleUserID:
+		return 1
+	case RoleModeratorID:
+		return 3
+	case RoleAdminID:
+		return 4
+	default:
+		return 2
+	}
 }
 
 // RoleService user service
@@ -81,14 +104,30 @@ func (rs *RoleService) GetRoleList(ctx context.Context) (resp []*schema.GetRoleR
 
 	resp = []*schema.GetRoleResp{}
 	_ = copier.Copy(&resp, roles)
+	for _, r := range resp {
+		r.BuiltIn = IsBuiltInRole(r.ID)
+	}
 	return
 }
 
+// GetRole get role by id, the built-in role is translated
+func (rs *RoleService) GetRole(ctx context.Context, roleID int) (role *entity.Role, exist bool, err error) {
+	role, exist, err = rs.roleRepo.GetRole(ctx, roleID)
+	if err != nil || !exist {
+		return nil, exist, err
+	}
+	rs.translateRole(ctx, role)
+	return role, true, nil
+}
+
 func (rs *RoleService) GetRoleMapping(ctx context.Context) (roleMapping map[int]*entity.Role, err error) {
 	return rs.roleRepo.GetRoleAllMapping(ctx)
 }
 
 func (rs *RoleService) translateRole(ctx context.Context, role *entity.Role) {
+	if !IsBuiltInRole(role.ID) {
+		return
+	}
 	switch role.Name {
 	case roleUserName:
 		role.Name = translator.Tr(handler.GetLangByCtx(ctx), trRoleNameUser)
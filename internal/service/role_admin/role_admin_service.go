@@ -0,0 +1,274 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package role_admin
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/permission"
+	"github.com/apache/answer/internal/service/role"
+	tagcommon "github.com/apache/answer/internal/service/tag_common"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// RoleAdminService the custom roles managed by the administrators.
+// A custom role grants its powers on top of the reputation of the user, it never gives the access to the admin pages.
+type RoleAdminService struct {
+	data               *data.Data
+	roleRepo           role.RoleRepo
+	rolePowerRelRepo   role.RolePowerRelRepo
+	roleTagRelRepo     role.RoleTagRelRepo
+	powerRepo          role.PowerRepo
+	roleService        *role.RoleService
+	userRoleRelService *role.UserRoleRelService
+	tagCommonService   *tagcommon.TagCommonService
+}
+
+// NewRoleAdminService new role admin service
+func NewRoleAdminService(
+	data *data.Data,
+	roleRepo role.RoleRepo,
+	rolePowerRelRepo role.RolePowerRelRepo,
+	roleTagRelRepo role.RoleTagRelRepo,
+	powerRepo role.PowerRepo,
+	roleService *role.RoleService,
+	userRoleRelService *role.UserRoleRelService,
+	tagCommonService *tagcommon.TagCommonService,
+) *RoleAdminService {
+	return &RoleAdminService{
+		data:               data,
+		roleRepo:           roleRepo,
+		rolePowerRelRepo:   rolePowerRelRepo,
+		roleTagRelRepo:     roleTagRelRepo,
+		powerRepo:          powerRepo,
+		roleService:        roleService,
+		userRoleRelService: userRoleRelService,
+		tagCommonService:   tagCommonService,
+	}
+}
+
+// GetRole get the role with its powers and tags
+func (rs *RoleAdminService) GetRole(ctx context.Context, req *schema.GetRoleReq) (resp *schema.GetRoleDetailResp, err error) {
+	roleInfo, exist, err := rs.roleService.GetRole(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.NotFound(reason.RoleNotFound)
+	}
+	powerTypes, err := rs.rolePowerRelRepo.GetRolePowerTypeList(ctx, roleInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp = &schema.GetRoleDetailResp{
+		GetRoleResp: schema.GetRoleResp{
+			ID:          roleInfo.ID,
+			Name:        roleInfo.Name,
+			Description: roleInfo.Description,
+			BuiltIn:     role.IsBuiltInRole(roleInfo.ID),
+		},
+		PowerTypes: powerTypes,
+		Tags:       make([]*schema.RoleTagResp, 0),
+	}
+	if resp.BuiltIn {
+		return resp, nil
+	}
+	tagIDs, err := rs.roleTagRelRepo.GetRoleTagIDs(ctx, roleInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tagIDs) == 0 {
+		return resp, nil
+	}
+	tags, err := rs.tagCommonService.GetTagListByIDs(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		resp.Tags = append(resp.Tags, &schema.RoleTagResp{
+			ID:          tag.ID,
+			SlugName:    tag.SlugName,
+			DisplayName: tag.DisplayName,
+		})
+	}
+	return resp, nil
+}
+
+// AddRole add the custom role without any power
+func (rs *RoleAdminService) AddRole(ctx context.Context, req *schema.AddRoleReq) (resp *schema.AddRoleResp, err error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if err = rs.checkRoleName(ctx, req.Name, 0); err != nil {
+		return nil, err
+	}
+	tagIDs, err := rs.checkTags(ctx, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+	roleInfo := &entity.Role{Name: req.Name, Description: req.Description}
+	// the role is never left without the tags it is scoped to
+	err = rs.data.DB.TransactionContext(ctx, func(ctx context.Context) error {
+		if err := rs.roleRepo.AddRole(ctx, roleInfo); err != nil {
+			return err
+		}
+		return rs.roleTagRelRepo.SaveRoleTags(ctx, roleInfo.ID, tagIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schema.AddRoleResp{ID: roleInfo.ID}, nil
+}
+
+// UpdateRole update the name, the description and the tags of the custom role
+func (rs *RoleAdminService) UpdateRole(ctx context.Context, req *schema.UpdateRoleReq) (err error) {
+	if _, err = rs.getCustomRole(ctx, req.ID); err != nil {
+		return err
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err = rs.checkRoleName(ctx, req.Name, req.ID); err != nil {
+		return err
+	}
+	tagIDs, err := rs.checkTags(ctx, req.TagIDs)
+	if err != nil {
+		return err
+	}
+	return rs.data.DB.TransactionContext(ctx, func(ctx context.Context) error {
+		err := rs.roleRepo.UpdateRole(ctx, &entity.Role{ID: req.ID, Name: req.Name, Description: req.Description})
+		if err != nil {
+			return err
+		}
+		return rs.roleTagRelRepo.SaveRoleTags(ctx, req.ID, tagIDs)
+	})
+}
+
+// DeleteRole delete the custom role that is not assigned to any user
+func (rs *RoleAdminService) DeleteRole(ctx context.Context, req *schema.DeleteRoleReq) (err error) {
+	if _, err = rs.getCustomRole(ctx, req.ID); err != nil {
+		return err
+	}
+	users, err := rs.userRoleRelService.GetUserByRoleID(ctx, []int{req.ID})
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return errors.BadRequest(reason.RoleInUse)
+	}
+	return rs.roleRepo.DeleteRole(ctx, req.ID)
+}
+
+// GetPowerList get the powers that can be granted to the custom roles
+func (rs *RoleAdminService) GetPowerList(ctx context.Context) (resp []*schema.GetPowerResp, err error) {
+	powers, err := rs.getGrantablePowers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.GetPowerResp, 0, len(powers))
+	for _, power := range powers {
+		resp = append(resp, &schema.GetPowerResp{
+			PowerType:   power.PowerType,
+			Name:        power.Name,
+			Description: power.Description,
+		})
+	}
+	return resp, nil
+}
+
+// UpdateRolePowers replace the powers of the custom role
+func (rs *RoleAdminService) UpdateRolePowers(ctx context.Context, req *schema.UpdateRolePowersReq) (err error) {
+	if _, err = rs.getCustomRole(ctx, req.RoleID); err != nil {
+		return err
+	}
+	powers, err := rs.getGrantablePowers(ctx)
+	if err != nil {
+		return err
+	}
+	powerTypes := make([]string, 0, len(req.PowerTypes))
+	for _, powerType := range req.PowerTypes {
+		if !slices.ContainsFunc(powers, func(p *entity.Power) bool { return p.PowerType == powerType }) {
+			return errors.BadRequest(reason.RolePowerInvalid)
+		}
+		if !slices.Contains(powerTypes, powerType) {
+			powerTypes = append(powerTypes, powerType)
+		}
+	}
+	return rs.rolePowerRelRepo.SaveRolePowers(ctx, req.RoleID, powerTypes)
+}
+
+func (rs *RoleAdminService) getCustomRole(ctx context.Context, roleID int) (roleInfo *entity.Role, err error) {
+	if role.IsBuiltInRole(roleID) {
+		return nil, errors.BadRequest(reason.RoleBuiltInCannotModify)
+	}
+	roleInfo, exist, err := rs.roleRepo.GetRole(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.NotFound(reason.RoleNotFound)
+	}
+	return roleInfo, nil
+}
+
+func (rs *RoleAdminService) checkRoleName(ctx context.Context, name string, roleID int) error {
+	roles, err := rs.roleRepo.GetRoleAllList(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range roles {
+		if r.ID != roleID && strings.EqualFold(r.Name, name) {
+			return errors.BadRequest(reason.RoleNameDuplicate)
+		}
+	}
+	return nil
+}
+
+// checkTags check the tags exist, the duplicated ones are removed
+func (rs *RoleAdminService) checkTags(ctx context.Context, tagIDs []string) (ids []string, err error) {
+	ids = make([]string, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	tags, err := rs.tagCommonService.GetTagListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(ids) {
+		return nil, errors.BadRequest(reason.TagNotFound)
+	}
+	return ids, nil
+}
+
+// getGrantablePowers the access to the admin pages follows the admin role, so it is not a power of the custom roles
+func (rs *RoleAdminService) getGrantablePowers(ctx context.Context) (powers []*entity.Power, err error) {
+	powers, err = rs.powerRepo.GetPowerList(ctx, &entity.Power{})
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(powers, func(p *entity.Power) bool { return p.PowerType == permission.AdminAccess }), nil
+}
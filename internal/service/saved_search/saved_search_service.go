@@ -0,0 +1,195 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package saved_search
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/content"
+	"github.com/apache/answer/internal/service/noticequeue"
+	"github.com/apache/answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// savedSearchLimit the max number of saved searches of a user
+	savedSearchLimit    = 20
+	savedSearchPageSize = 100
+)
+
+type SavedSearchRepo interface {
+	AddSavedSearch(ctx context.Context, savedSearch *entity.SavedSearch) (err error)
+	UpdateSavedSearch(ctx context.Context, savedSearch *entity.SavedSearch) (err error)
+	UpdateLastCheckedAt(ctx context.Context, id int, lastCheckedAt time.Time) (err error)
+	DeleteSavedSearch(ctx context.Context, userID string, id int) (err error)
+	GetSavedSearch(ctx context.Context, userID string, id int) (savedSearch *entity.SavedSearch, exist bool, err error)
+	GetSavedSearchList(ctx context.Context, userID string) (list []*entity.SavedSearch, err error)
+	CountSavedSearch(ctx context.Context, userID string) (count int64, err error)
+	GetNotifySavedSearchPage(ctx context.Context, page, pageSize int) (list []*entity.SavedSearch, err error)
+}
+
+// SavedSearchService saved search service
+type SavedSearchService struct {
+	savedSearchRepo          SavedSearchRepo
+	searchService            *content.SearchService
+	notificationQueueService noticequeue.Service
+}
+
+// NewSavedSearchService new saved search service
+func NewSavedSearchService(
+	savedSearchRepo SavedSearchRepo,
+	searchService *content.SearchService,
+	notificationQueueService noticequeue.Service,
+) *SavedSearchService {
+	return &SavedSearchService{
+		savedSearchRepo:          savedSearchRepo,
+		searchService:            searchService,
+		notificationQueueService: notificationQueueService,
+	}
+}
+
+// GetSavedSearchList get the saved searches of the user
+func (s *SavedSearchService) GetSavedSearchList(ctx context.Context, req *schema.GetSavedSearchListReq) (
+	resp []*schema.SavedSearchResp, err error) {
+	list, err := s.savedSearchRepo.GetSavedSearchList(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.SavedSearchResp, 0, len(list))
+	for _, item := range list {
+		resp = append(resp, convertSavedSearchResp(item))
+	}
+	return resp, nil
+}
+
+// AddSavedSearch save a search for the user
+func (s *SavedSearchService) AddSavedSearch(ctx context.Context, req *schema.AddSavedSearchReq) (
+	resp *schema.SavedSearchResp, err error) {
+	count, err := s.savedSearchRepo.CountSavedSearch(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= savedSearchLimit {
+		return nil, errors.BadRequest(reason.SavedSearchLimitExceeded)
+	}
+	savedSearch := &entity.SavedSearch{
+		UserID: req.UserID,
+		Name:   req.Name,
+		Query:  req.Query,
+		Notify: req.Notify,
+		// only the contents created after saving are notified
+		LastCheckedAt: time.Now(),
+	}
+	if err = s.savedSearchRepo.AddSavedSearch(ctx, savedSearch); err != nil {
+		return nil, err
+	}
+	return convertSavedSearchResp(savedSearch), nil
+}
+
+// UpdateSavedSearch update the saved search of the user
+func (s *SavedSearchService) UpdateSavedSearch(ctx context.Context, req *schema.UpdateSavedSearchReq) (err error) {
+	savedSearch, exist, err := s.savedSearchRepo.GetSavedSearch(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.SavedSearchNotFound)
+	}
+	// the contents matched by the old query or before the notification is enabled are not notified
+	if savedSearch.Query != req.Query || (!savedSearch.Notify && req.Notify) {
+		savedSearch.LastCheckedAt = time.Now()
+	}
+	savedSearch.Name = req.Name
+	savedSearch.Query = req.Query
+	savedSearch.Notify = req.Notify
+	return s.savedSearchRepo.UpdateSavedSearch(ctx, savedSearch)
+}
+
+// DeleteSavedSearch delete the saved search of the user
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, req *schema.DeleteSavedSearchReq) (err error) {
+	return s.savedSearchRepo.DeleteSavedSearch(ctx, req.UserID, req.ID)
+}
+
+// NotifyNewMatchesCron notify the owners of the saved searches about the contents created since the last check
+func (s *SavedSearchService) NotifyNewMatchesCron(ctx context.Context) {
+	now := time.Now()
+	for page := 1; ; page++ {
+		list, err := s.savedSearchRepo.GetNotifySavedSearchPage(ctx, page, savedSearchPageSize)
+		if err != nil {
+			log.Errorf("get saved searches failed: %v", err)
+			return
+		}
+		for _, savedSearch := range list {
+			if err := s.notifyNewMatches(ctx, savedSearch, now); err != nil {
+				log.Errorf("notify saved search %d failed: %v", savedSearch.ID, err)
+			}
+		}
+		if len(list) < savedSearchPageSize {
+			return
+		}
+	}
+}
+
+func (s *SavedSearchService) notifyNewMatches(ctx context.Context, savedSearch *entity.SavedSearch, now time.Time) error {
+	// the time range is fixed before paging, so all the matches in it are notified before the last checked time moves on
+	created := plugin.SearchTimeRange{From: savedSearch.LastCheckedAt.Unix(), To: now.Unix()}
+	for page := 1; ; page++ {
+		results, err := s.searchService.SearchNewContents(ctx, savedSearch.Query, savedSearch.UserID,
+			created, page, savedSearchPageSize)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			// the user's own contents are not notified
+			if result.Object.UserInfo == nil || result.Object.UserInfo.ID == savedSearch.UserID {
+				continue
+			}
+			s.notificationQueueService.Send(ctx, &schema.NotificationMsg{
+				TriggerUserID:       result.Object.UserInfo.ID,
+				ReceiverUserID:      savedSearch.UserID,
+				Type:                schema.NotificationTypeInbox,
+				ObjectID:            result.Object.ID,
+				ObjectType:          result.ObjectType,
+				NotificationAction:  constant.NotificationSavedSearchMatched,
+				NoNeedPushAllFollow: true,
+			})
+		}
+		if len(results) < savedSearchPageSize {
+			break
+		}
+	}
+	return s.savedSearchRepo.UpdateLastCheckedAt(ctx, savedSearch.ID, now)
+}
+
+func convertSavedSearchResp(savedSearch *entity.SavedSearch) *schema.SavedSearchResp {
+	return &schema.SavedSearchResp{
+		ID:        savedSearch.ID,
+		Name:      savedSearch.Name,
+		Query:     savedSearch.Query,
+		Notify:    savedSearch.Notify,
+		CreatedAt: savedSearch.CreatedAt.Unix(),
+	}
+}
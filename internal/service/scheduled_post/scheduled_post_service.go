@@ -0,0 +1,283 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package scheduled_post
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	answercommon "github.com/apache/answer/internal/service/answer_common"
+	"github.com/apache/answer/internal/service/content"
+	questioncommon "github.com/apache/answer/internal/service/question_common"
+	"github.com/apache/answer/pkg/htmltext"
+	"github.com/apache/answer/pkg/obj"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// publishBatchSize the number of the due posts published in each run
+const publishBatchSize = 100
+
+type ScheduledPostRepo interface {
+	GetUserScheduledQuestions(ctx context.Context, userID string) (list []*entity.Question, err error)
+	GetUserScheduledAnswers(ctx context.Context, userID string) (list []*entity.Answer, err error)
+	GetDueScheduledQuestions(ctx context.Context, now time.Time, limit int) (list []*entity.Question, err error)
+	GetDueScheduledAnswers(ctx context.Context, now time.Time, limit int) (list []*entity.Answer, err error)
+	UpdateQuestionPublishAt(ctx context.Context, questionID string, publishAt time.Time) (updated bool, err error)
+	UpdateAnswerPublishAt(ctx context.Context, answerID string, publishAt time.Time) (updated bool, err error)
+	PublishQuestion(ctx context.Context, questionID string, now time.Time) (published bool, err error)
+	PublishAnswer(ctx context.Context, answerID string, now time.Time) (published bool, err error)
+}
+
+// ScheduledPostService the questions and answers which are published at a chosen time
+type ScheduledPostService struct {
+	scheduledPostRepo ScheduledPostRepo
+	questionRepo      questioncommon.QuestionRepo
+	answerRepo        answercommon.AnswerRepo
+	questionService   *content.QuestionService
+	answerService     *content.AnswerService
+}
+
+// NewScheduledPostService new scheduled post service
+func NewScheduledPostService(
+	scheduledPostRepo ScheduledPostRepo,
+	questionRepo questioncommon.QuestionRepo,
+	answerRepo answercommon.AnswerRepo,
+	questionService *content.QuestionService,
+	answerService *content.AnswerService,
+) *ScheduledPostService {
+	return &ScheduledPostService{
+		scheduledPostRepo: scheduledPostRepo,
+		questionRepo:      questionRepo,
+		answerRepo:        answerRepo,
+		questionService:   questionService,
+		answerService:     answerService,
+	}
+}
+
+// GetScheduledPostList get the scheduled questions and answers of the user, the earliest published first
+func (ss *ScheduledPostService) GetScheduledPostList(ctx context.Context, req *schema.GetScheduledPostListReq) (
+	resp []*schema.ScheduledPostResp, err error) {
+	questions, err := ss.scheduledPostRepo.GetUserScheduledQuestions(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := ss.scheduledPostRepo.GetUserScheduledAnswers(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp = make([]*schema.ScheduledPostResp, 0, len(questions)+len(answers))
+	for _, question := range questions {
+		resp = append(resp, &schema.ScheduledPostResp{
+			ObjectID:   question.ID,
+			ObjectType: constant.QuestionObjectType,
+			QuestionID: question.ID,
+			Title:      question.Title,
+			Excerpt:    htmltext.FetchExcerpt(question.ParsedText, "...", 240),
+			PublishAt:  question.PublishAt.Unix(),
+			CreatedAt:  question.CreatedAt.Unix(),
+		})
+	}
+	questionIDs := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		questionIDs = append(questionIDs, answer.QuestionID)
+	}
+	questionTitles := make(map[string]string, len(questionIDs))
+	if len(questionIDs) > 0 {
+		answerQuestions, err := ss.questionRepo.FindByID(ctx, questionIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, question := range answerQuestions {
+			questionTitles[uid.DeShortID(question.ID)] = question.Title
+		}
+	}
+	for _, answer := range answers {
+		resp = append(resp, &schema.ScheduledPostResp{
+			ObjectID:   answer.ID,
+			ObjectType: constant.AnswerObjectType,
+			QuestionID: answer.QuestionID,
+			Title:      questionTitles[answer.QuestionID],
+			Excerpt:    htmltext.FetchExcerpt(answer.ParsedText, "...", 240),
+			PublishAt:  answer.PublishAt.Unix(),
+			CreatedAt:  answer.CreatedAt.Unix(),
+		})
+	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].PublishAt < resp[j].PublishAt
+	})
+	if handler.GetEnableShortID(ctx) {
+		for _, item := range resp {
+			item.ObjectID = uid.EnShortID(item.ObjectID)
+			item.QuestionID = uid.EnShortID(item.QuestionID)
+		}
+	}
+	return resp, nil
+}
+
+// ReschedulePost change the publish time of the scheduled post, only the author and the moderators can do it
+func (ss *ScheduledPostService) ReschedulePost(ctx context.Context, req *schema.ReschedulePostReq) (err error) {
+	publishAt := time.Unix(req.PublishAt, 0)
+	if !publishAt.After(time.Now()) {
+		return errors.BadRequest(reason.ScheduledPostTimeInvalid)
+	}
+	objectType, authorID, err := ss.getScheduledPost(ctx, req.ObjectID)
+	if err != nil {
+		return err
+	}
+	if authorID != req.UserID && !req.IsAdminModerator {
+		return errors.Forbidden(reason.ForbiddenError)
+	}
+
+	var updated bool
+	if objectType == constant.QuestionObjectType {
+		updated, err = ss.scheduledPostRepo.UpdateQuestionPublishAt(ctx, req.ObjectID, publishAt)
+	} else {
+		updated, err = ss.scheduledPostRepo.UpdateAnswerPublishAt(ctx, req.ObjectID, publishAt)
+	}
+	if err != nil {
+		return err
+	}
+	// the post has been published in the meantime
+	if !updated {
+		return errors.BadRequest(reason.ScheduledPostNotFound)
+	}
+	return nil
+}
+
+// CancelScheduledPost delete the scheduled post before it is published, only the author and the moderators can do it
+func (ss *ScheduledPostService) CancelScheduledPost(ctx context.Context, req *schema.CancelScheduledPostReq) (err error) {
+	objectType, authorID, err := ss.getScheduledPost(ctx, req.ObjectID)
+	if err != nil {
+		return err
+	}
+	if authorID != req.UserID && !req.IsAdminModerator {
+		return errors.Forbidden(reason.ForbiddenError)
+	}
+	if objectType == constant.QuestionObjectType {
+		// the scheduled answers of the question are cancelled with it when they are due
+		return ss.questionService.RemoveQuestion(ctx, &schema.RemoveQuestionReq{
+			ID:      req.ObjectID,
+			UserID:  req.UserID,
+			IsAdmin: true,
+		})
+	}
+	return ss.answerService.RemoveAnswer(ctx, &schema.RemoveAnswerReq{
+		ID:     req.ObjectID,
+		UserID: authorID,
+	})
+}
+
+// PublishScheduledPostsCron publish the scheduled questions and answers whose publish time has arrived
+func (ss *ScheduledPostService) PublishScheduledPostsCron(ctx context.Context) {
+	now := time.Now()
+	questions, err := ss.scheduledPostRepo.GetDueScheduledQuestions(ctx, now, publishBatchSize)
+	if err != nil {
+		log.Errorf("get due scheduled questions failed: %v", err)
+		return
+	}
+	for _, question := range questions {
+		published, err := ss.scheduledPostRepo.PublishQuestion(ctx, question.ID, now)
+		if err != nil {
+			log.Errorf("publish scheduled question %s failed: %v", question.ID, err)
+			continue
+		}
+		if !published {
+			continue
+		}
+		question.Status = entity.QuestionStatusAvailable
+		question.CreatedAt = now
+		question.PostUpdateTime = now
+		ss.questionService.AfterQuestionPublished(ctx, question)
+	}
+
+	answers, err := ss.scheduledPostRepo.GetDueScheduledAnswers(ctx, now, publishBatchSize)
+	if err != nil {
+		log.Errorf("get due scheduled answers failed: %v", err)
+		return
+	}
+	for _, answer := range answers {
+		if err := ss.publishAnswer(ctx, answer, now); err != nil {
+			log.Errorf("publish scheduled answer %s failed: %v", answer.ID, err)
+		}
+	}
+}
+
+func (ss *ScheduledPostService) publishAnswer(ctx context.Context, answer *entity.Answer, now time.Time) error {
+	question, exist, err := ss.questionRepo.GetQuestion(ctx, answer.QuestionID)
+	if err != nil {
+		return err
+	}
+	// the answer of a removed question is cancelled
+	if !exist || question.Status == entity.QuestionStatusDeleted {
+		return ss.answerService.RemoveAnswer(ctx, &schema.RemoveAnswerReq{ID: answer.ID, UserID: answer.UserID})
+	}
+	// the answer waits until its question is published
+	if question.Status == entity.QuestionStatusScheduled || question.Status == entity.QuestionStatusPending {
+		return nil
+	}
+	published, err := ss.scheduledPostRepo.PublishAnswer(ctx, answer.ID, now)
+	if err != nil || !published {
+		return err
+	}
+	answer.Status = entity.AnswerStatusAvailable
+	answer.CreatedAt = now
+	ss.answerService.AfterAnswerPublished(ctx, answer)
+	return nil
+}
+
+// getScheduledPost get the type and the author of the scheduled question or answer
+func (ss *ScheduledPostService) getScheduledPost(ctx context.Context, objectID string) (
+	objectType, authorID string, err error) {
+	objectType, err = obj.GetObjectTypeStrByObjectID(uid.DeShortID(objectID))
+	if err != nil {
+		return "", "", errors.BadRequest(reason.ScheduledPostNotFound)
+	}
+	switch objectType {
+	case constant.QuestionObjectType:
+		question, exist, err := ss.questionRepo.GetQuestion(ctx, objectID)
+		if err != nil {
+			return "", "", err
+		}
+		if !exist || question.Status != entity.QuestionStatusScheduled {
+			return "", "", errors.BadRequest(reason.ScheduledPostNotFound)
+		}
+		return objectType, question.UserID, nil
+	case constant.AnswerObjectType:
+		answer, exist, err := ss.answerRepo.GetAnswer(ctx, objectID)
+		if err != nil {
+			return "", "", err
+		}
+		if !exist || answer.Status != entity.AnswerStatusScheduled {
+			return "", "", errors.BadRequest(reason.ScheduledPostNotFound)
+		}
+		return objectType, answer.UserID, nil
+	default:
+		return "", "", errors.BadRequest(reason.ScheduledPostNotFound)
+	}
+}
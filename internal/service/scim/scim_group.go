@@ -0,0 +1,381 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/role"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// GetGroups get the groups
+func (ss *SCIMService) GetGroups(ctx context.Context, req *schema.SCIMListReq) (resp *schema.SCIMListResp, err error) {
+	startIndex, count := listRange(req)
+	siteURL, roles, err := ss.formatContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*entity.Role, 0, len(roles))
+	for _, r := range roles {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	if len(req.Filter) > 0 {
+		attribute, value, err := parseFilter(req.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if attribute != "displayname" {
+			return nil, errors.BadRequest(reason.SCIMFilterInvalid)
+		}
+		list = slices.DeleteFunc(list, func(r *entity.Role) bool { return !strings.EqualFold(r.Name, value) })
+	}
+
+	withMembers := !strings.Contains(strings.ToLower(req.ExcludedAttributes), "members")
+	resp = newListResp(int64(len(list)), startIndex)
+	for _, r := range pageOf(list, startIndex, count) {
+		group, err := ss.formatGroup(ctx, siteURL, r, withMembers)
+		if err != nil {
+			return nil, err
+		}
+		resp.Resources = append(resp.Resources, group)
+	}
+	resp.ItemsPerPage = len(resp.Resources)
+	return resp, nil
+}
+
+// GetGroup get the group
+func (ss *SCIMService) GetGroup(ctx context.Context, groupID string) (resp *schema.SCIMGroup, err error) {
+	siteURL, roles, err := ss.formatContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := getRole(roles, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return ss.formatGroup(ctx, siteURL, r, true)
+}
+
+// UpdateGroup replace the members of the group, the group itself can not be renamed
+func (ss *SCIMService) UpdateGroup(ctx context.Context, operatorID, groupID string, req *schema.SCIMGroup) (
+	resp *schema.SCIMGroup, err error) {
+	roles, err := ss.roleService.GetRoleMapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := getRole(roles, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.DisplayName) > 0 && !strings.EqualFold(req.DisplayName, r.Name) {
+		return nil, errors.BadRequest(reason.SCIMGroupReadOnly)
+	}
+	if err = ss.setMembers(ctx, operatorID, r.ID, memberIDs(req.Members)); err != nil {
+		return nil, err
+	}
+	return ss.GetGroup(ctx, groupID)
+}
+
+// PatchGroup add or remove the members of the group
+func (ss *SCIMService) PatchGroup(ctx context.Context, operatorID, groupID string, req *schema.SCIMPatchReq) (
+	resp *schema.SCIMGroup, err error) {
+	roles, err := ss.roleService.GetRoleMapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err := getRole(roles, groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range req.Operations {
+		values := map[string]json.RawMessage{strings.ToLower(op.Path): op.Value}
+		if len(op.Path) == 0 {
+			if err = json.Unmarshal(op.Value, &values); err != nil {
+				return nil, patchInvalid(ctx, "the value of the operation without path must be an object")
+			}
+		}
+		for path, value := range values {
+			if err = ss.patchGroupAttribute(ctx, operatorID, r, strings.ToLower(op.Op), strings.ToLower(path), value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ss.GetGroup(ctx, groupID)
+}
+
+func (ss *SCIMService) patchGroupAttribute(ctx context.Context, operatorID string, r *entity.Role,
+	op, path string, value json.RawMessage) error {
+	switch {
+	case path == "displayname":
+		var name string
+		if err := parseString(ctx, value, &name); err != nil {
+			return err
+		}
+		if op == "remove" || !strings.EqualFold(name, r.Name) {
+			return errors.BadRequest(reason.SCIMGroupReadOnly)
+		}
+		return nil
+	case path == "members":
+		var members []*schema.SCIMMultiValue
+		if len(value) > 0 && string(value) != "null" {
+			if err := json.Unmarshal(value, &members); err != nil {
+				return patchInvalid(ctx, "the members must be an array")
+			}
+		}
+		switch op {
+		case "add":
+			for _, userID := range memberIDs(members) {
+				if err := ss.addMember(ctx, operatorID, r.ID, userID); err != nil {
+					return err
+				}
+			}
+			return nil
+		case "replace":
+			return ss.setMembers(ctx, operatorID, r.ID, memberIDs(members))
+		case "remove":
+			userIDs := memberIDs(members)
+			// the remove without value removes all the members
+			if len(members) == 0 {
+				current, err := ss.currentMembers(ctx, r.ID)
+				if err != nil {
+					return err
+				}
+				userIDs = current
+			}
+			for _, userID := range userIDs {
+				if err := ss.removeMember(ctx, operatorID, r.ID, userID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]") && op == "remove":
+		// such as members[value eq "123"]
+		attribute, userID, err := parseFilter(path[len("members[") : len(path)-1])
+		if err != nil || attribute != "value" {
+			return patchInvalid(ctx, "the member must be selected by the value")
+		}
+		return ss.removeMember(ctx, operatorID, r.ID, userID)
+	case path == "id", path == "externalid":
+		return nil
+	}
+	return patchInvalid(ctx, "only the members of the group can be changed")
+}
+
+// setMembers make the users the exact members of the group. The members of the user role can not be listed
+// as every user has it, so they are only added.
+func (ss *SCIMService) setMembers(ctx context.Context, operatorID string, roleID int, userIDs []string) error {
+	current, err := ss.currentMembers(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	for _, userID := range current {
+		if !slices.Contains(userIDs, userID) {
+			if err = ss.removeMember(ctx, operatorID, roleID, userID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, userID := range userIDs {
+		if err = ss.addMember(ctx, operatorID, roleID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *SCIMService) addMember(ctx context.Context, operatorID string, roleID int, userID string) error {
+	pu, err := ss.getMember(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(pu.binding.Groups, roleID) {
+		pu.binding.Groups = append(pu.binding.Groups, roleID)
+	}
+	return ss.syncRole(ctx, operatorID, pu)
+}
+
+func (ss *SCIMService) removeMember(ctx context.Context, operatorID string, roleID int, userID string) error {
+	pu, err := ss.getMember(ctx, userID)
+	if err != nil {
+		return err
+	}
+	pu.binding.Groups = slices.DeleteFunc(pu.binding.Groups, func(id int) bool { return id == roleID })
+	return ss.syncRole(ctx, operatorID, pu)
+}
+
+// syncRole change the role of the user to the most privileged one of the groups the user is in,
+// or the user role if none. The last administrator is never demoted.
+func (ss *SCIMService) syncRole(ctx context.Context, operatorID string, pu *provisionedUser) error {
+	roles, err := ss.roleService.GetRoleMapping(ctx)
+	if err != nil {
+		return err
+	}
+	roleID := role.RoleUserID
+	for _, id := range pu.binding.Groups {
+		// the custom role may have been deleted since the user joined the group
+		if roles[id] != nil && role.Priority(id) > role.Priority(roleID) {
+			roleID = id
+		}
+	}
+	if roleID != pu.roleID {
+		if err := ss.checkLastAdmin(ctx, pu); err != nil {
+			return err
+		}
+	}
+	if err := ss.saveBinding(ctx, pu, pu.userName()); err != nil {
+		return err
+	}
+	if roleID == pu.roleID {
+		return nil
+	}
+	log.Infof("the role of user %s is changed from %d to %d by SCIM", pu.user.ID, pu.roleID, roleID)
+	err = ss.userAdminService.UpdateUserRole(ctx, &schema.UpdateUserRoleReq{
+		UserID:      pu.user.ID,
+		RoleID:      roleID,
+		LoginUserID: operatorID,
+	})
+	if err != nil {
+		return err
+	}
+	pu.roleID = roleID
+	return nil
+}
+
+// checkLastAdmin refuse to demote, suspend or delete the user if it is the last available administrator,
+// so that the site is never left without anyone to manage it
+func (ss *SCIMService) checkLastAdmin(ctx context.Context, pu *provisionedUser) error {
+	if pu.roleID != role.RoleAdminID || pu.user.Status != entity.UserStatusAvailable {
+		return nil
+	}
+	rels, err := ss.userRoleService.GetUserByRoleID(ctx, []int{role.RoleAdminID})
+	if err != nil {
+		return err
+	}
+	otherIDs := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		if rel.UserID != pu.user.ID {
+			otherIDs = append(otherIDs, rel.UserID)
+		}
+	}
+	if len(otherIDs) > 0 {
+		admins, err := ss.userRepo.BatchGetByID(ctx, otherIDs)
+		if err != nil {
+			return err
+		}
+		for _, admin := range admins {
+			if admin.Status == entity.UserStatusAvailable {
+				return nil
+			}
+		}
+	}
+	return errors.BadRequest(reason.SCIMLastAdmin)
+}
+
+func (ss *SCIMService) getMember(ctx context.Context, userID string) (*provisionedUser, error) {
+	pu, err := ss.getProvisionedUser(ctx, userID)
+	if err != nil {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	return pu, nil
+}
+
+// currentMembers the users having the role, it is empty for the user role
+func (ss *SCIMService) currentMembers(ctx context.Context, roleID int) (userIDs []string, err error) {
+	if roleID == role.RoleUserID {
+		return nil, nil
+	}
+	rels, err := ss.userRoleService.GetUserByRoleID(ctx, []int{roleID})
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		userIDs = append(userIDs, rel.UserID)
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	users, err := ss.userRepo.BatchGetByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	userIDs = userIDs[:0]
+	for _, user := range users {
+		if user.Status != entity.UserStatusDeleted {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	return userIDs, nil
+}
+
+func (ss *SCIMService) formatGroup(ctx context.Context, baseURL string, r *entity.Role, withMembers bool) (
+	*schema.SCIMGroup, error) {
+	groupID := strconv.Itoa(r.ID)
+	group := &schema.SCIMGroup{
+		Schemas:     []string{schema.SCIMSchemaGroup},
+		ID:          groupID,
+		DisplayName: r.Name,
+		Meta: &schema.SCIMMeta{
+			ResourceType: "Group",
+			Location:     baseURL + "/Groups/" + groupID,
+		},
+	}
+	if !withMembers {
+		return group, nil
+	}
+	userIDs, err := ss.currentMembers(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		group.Members = append(group.Members, &schema.SCIMMultiValue{
+			Value: userID,
+			Ref:   baseURL + "/Users/" + userID,
+		})
+	}
+	return group, nil
+}
+
+func getRole(roles map[int]*entity.Role, groupID string) (*entity.Role, error) {
+	id, err := strconv.Atoi(groupID)
+	if err != nil || roles[id] == nil {
+		return nil, errors.NotFound(reason.SCIMGroupNotFound)
+	}
+	return roles[id], nil
+}
+
+func memberIDs(members []*schema.SCIMMultiValue) (userIDs []string) {
+	for _, m := range members {
+		if len(m.Value) > 0 {
+			userIDs = append(userIDs, m.Value)
+		}
+	}
+	return userIDs
+}
@@ -0,0 +1,217 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/schema"
+	"github.com/segmentfault/pacman/errors"
+)
+
+const (
+	minDisplayNameLength = 2
+	maxDisplayNameLength = 30
+)
+
+// parseFilter parse the equality filter such as `userName eq "alice"`, which is all the identity providers use
+// to find the resource before they create it. The attribute is returned in lower case.
+func parseFilter(filter string) (attribute, value string, err error) {
+	i := strings.LastIndex(strings.ToLower(filter), ` eq "`)
+	if i <= 0 {
+		return "", "", errors.BadRequest(reason.SCIMFilterInvalid)
+	}
+	attribute = strings.ToLower(strings.TrimSpace(filter[:i]))
+	if err = json.Unmarshal([]byte(strings.TrimSpace(filter[i+4:])), &value); err != nil {
+		return "", "", errors.BadRequest(reason.SCIMFilterInvalid)
+	}
+	return attribute, value, nil
+}
+
+// isEmailsAttribute whether the attribute is the value of the emails, such as `emails[type eq "work"].value`
+func isEmailsAttribute(attribute string) bool {
+	return attribute == "emails" || (strings.HasPrefix(attribute, "emails") && strings.HasSuffix(attribute, "value"))
+}
+
+// userIdentity the email and the userName of the user, the userName is the email if it is absent
+func userIdentity(user *schema.SCIMUser) (email, userName string, err error) {
+	userName = strings.TrimSpace(user.UserName)
+	for _, e := range user.Emails {
+		if len(email) == 0 || e.Primary {
+			email = strings.TrimSpace(e.Value)
+		}
+	}
+	if len(email) == 0 {
+		email = userName
+	}
+	if addr, e := mail.ParseAddress(email); e != nil || addr.Address != email {
+		return "", "", errors.BadRequest(reason.SCIMEmailRequired)
+	}
+	if len(userName) == 0 {
+		userName = email
+	}
+	return email, userName, nil
+}
+
+// displayNameOf the display name fitting the limit of the site
+func displayNameOf(user *schema.SCIMUser, email string) string {
+	name := strings.TrimSpace(user.DisplayName)
+	if len(name) == 0 && user.Name != nil {
+		name = strings.TrimSpace(user.Name.Formatted)
+		if len(name) == 0 {
+			name = strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName)
+		}
+	}
+	if len(name) == 0 {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	if utf8.RuneCountInString(name) > maxDisplayNameLength {
+		name = string([]rune(name)[:maxDisplayNameLength])
+	}
+	for utf8.RuneCountInString(name) < minDisplayNameLength {
+		name += "_"
+	}
+	return name
+}
+
+// patchUser apply the operations to the user. The attributes not kept by the site, such as the phone numbers
+// and the enterprise extension, are ignored, so that the identity provider is not blocked by them.
+func patchUser(ctx context.Context, user *schema.SCIMUser, req *schema.SCIMPatchReq) error {
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if len(op.Path) > 0 {
+				if err := setUserAttribute(ctx, user, op.Path, op.Value); err != nil {
+					return err
+				}
+				continue
+			}
+			values := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return patchInvalid(ctx, "the value of the operation without path must be an object")
+			}
+			for path, value := range values {
+				if err := setUserAttribute(ctx, user, path, value); err != nil {
+					return err
+				}
+			}
+		case "remove":
+			removeUserAttribute(user, op.Path)
+		default:
+			return patchInvalid(ctx, fmt.Sprintf("the op %s is not supported", op.Op))
+		}
+	}
+	return nil
+}
+
+func setUserAttribute(ctx context.Context, user *schema.SCIMUser, path string, value json.RawMessage) (err error) {
+	attribute := strings.ToLower(strings.TrimPrefix(strings.ToLower(path), strings.ToLower(schema.SCIMSchemaUser)+":"))
+	if user.Name == nil {
+		user.Name = &schema.SCIMName{}
+	}
+	switch {
+	case attribute == "active":
+		var active bool
+		if active, err = parseBool(ctx, value); err != nil {
+			return err
+		}
+		user.Active = &active
+	case attribute == "username":
+		err = parseString(ctx, value, &user.UserName)
+	case attribute == "displayname":
+		err = parseString(ctx, value, &user.DisplayName)
+	case attribute == "externalid":
+		err = parseString(ctx, value, &user.ExternalID)
+	case attribute == "name":
+		if err = json.Unmarshal(value, user.Name); err != nil {
+			return patchInvalid(ctx, "the name must be an object")
+		}
+		// the display name follows the name if it is not set together
+		user.DisplayName = ""
+	case attribute == "name.formatted":
+		err = parseString(ctx, value, &user.Name.Formatted)
+		user.DisplayName = ""
+	case attribute == "name.givenname":
+		err = parseString(ctx, value, &user.Name.GivenName)
+		user.Name.Formatted, user.DisplayName = "", ""
+	case attribute == "name.familyname":
+		err = parseString(ctx, value, &user.Name.FamilyName)
+		user.Name.Formatted, user.DisplayName = "", ""
+	case attribute == "emails":
+		var emails []*schema.SCIMMultiValue
+		if err = json.Unmarshal(value, &emails); err != nil {
+			return patchInvalid(ctx, "the emails must be an array")
+		}
+		user.Emails = emails
+	case isEmailsAttribute(attribute):
+		var email string
+		if err = parseString(ctx, value, &email); err != nil {
+			return err
+		}
+		user.Emails = []*schema.SCIMMultiValue{{Value: email, Type: "work", Primary: true}}
+	}
+	return err
+}
+
+func removeUserAttribute(user *schema.SCIMUser, path string) {
+	switch strings.ToLower(path) {
+	case "externalid":
+		user.ExternalID = ""
+	case "displayname":
+		user.DisplayName = ""
+	}
+}
+
+func parseString(ctx context.Context, value json.RawMessage, s *string) error {
+	if err := json.Unmarshal(value, s); err != nil {
+		return patchInvalid(ctx, "the value must be a string")
+	}
+	return nil
+}
+
+// parseBool parse the boolean which may be sent as the string such as "False"
+func parseBool(ctx context.Context, value json.RawMessage) (bool, error) {
+	var b bool
+	if json.Unmarshal(value, &b) == nil {
+		return b, nil
+	}
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		switch strings.ToLower(s) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, patchInvalid(ctx, "the value must be a boolean")
+}
+
+func patchInvalid(ctx context.Context, cause string) error {
+	msg := translator.TrWithData(handler.GetLangByCtx(ctx), reason.SCIMPatchInvalid, map[string]any{"Reason": cause})
+	return errors.BadRequest(reason.SCIMPatchInvalid).WithMsg(msg)
+}
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/answer/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	attribute, value, err := parseFilter(`userName eq "alice@example.com"`)
+	require.NoError(t, err)
+	assert.Equal(t, "username", attribute)
+	assert.Equal(t, "alice@example.com", value)
+
+	attribute, value, err = parseFilter(`emails[type eq "work"].value EQ "a\"b@example.com"`)
+	require.NoError(t, err)
+	assert.True(t, isEmailsAttribute(attribute))
+	assert.Equal(t, `a"b@example.com`, value)
+
+	_, _, err = parseFilter(`userName sw "a"`)
+	assert.Error(t, err)
+	_, _, err = parseFilter(`eq "a"`)
+	assert.Error(t, err)
+}
+
+func TestUserIdentity(t *testing.T) {
+	email, userName, err := userIdentity(&schema.SCIMUser{UserName: "alice@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", email)
+	assert.Equal(t, "alice@example.com", userName)
+
+	email, userName, err = userIdentity(&schema.SCIMUser{UserName: "alice", Emails: []*schema.SCIMMultiValue{
+		{Value: "home@example.com", Type: "home"},
+		{Value: "work@example.com", Type: "work", Primary: true},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "work@example.com", email)
+	assert.Equal(t, "alice", userName)
+
+	_, _, err = userIdentity(&schema.SCIMUser{UserName: "alice"})
+	assert.Error(t, err)
+}
+
+func TestDisplayNameOf(t *testing.T) {
+	assert.Equal(t, "Alice Smith", displayNameOf(&schema.SCIMUser{
+		Name: &schema.SCIMName{GivenName: "Alice", FamilyName: "Smith"}}, "a@example.com"))
+	assert.Equal(t, "alice", displayNameOf(&schema.SCIMUser{}, "alice@example.com"))
+	assert.Equal(t, "a_", displayNameOf(&schema.SCIMUser{}, "a@example.com"))
+	assert.Len(t, []rune(displayNameOf(&schema.SCIMUser{DisplayName: "一二三四五六七八九十一二三四五六七八九十一二三四五六七八九十一二"}, "")), 30)
+}
+
+func TestPatchUser(t *testing.T) {
+	active := true
+	user := &schema.SCIMUser{
+		UserName:    "alice@example.com",
+		DisplayName: "Alice",
+		Name:        &schema.SCIMName{Formatted: "Alice"},
+		Active:      &active,
+	}
+	req := &schema.SCIMPatchReq{}
+	require.NoError(t, json.Unmarshal([]byte(`{"Operations":[
+		{"op":"Replace","path":"active","value":"False"},
+		{"op":"replace","path":"emails[type eq \"work\"].value","value":"alice.smith@example.com"},
+		{"op":"add","value":{"name.givenName":"Alice","name.familyName":"Smith","title":"Engineer"}},
+		{"op":"add","path":"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department","value":"R&D"}
+	]}`), req))
+	require.NoError(t, patchUser(context.Background(), user, req))
+	assert.False(t, *user.Active)
+	assert.Equal(t, "alice.smith@example.com", user.Emails[0].Value)
+	assert.Equal(t, "Alice Smith", displayNameOf(user, user.Emails[0].Value))
+
+	req = &schema.SCIMPatchReq{Operations: []*schema.SCIMPatchOperation{{Op: "move", Path: "active"}}}
+	assert.Error(t, patchUser(context.Background(), user, req))
+	req = &schema.SCIMPatchReq{Operations: []*schema.SCIMPatchOperation{
+		{Op: "replace", Path: "active", Value: json.RawMessage(`"maybe"`)}}}
+	assert.Error(t, patchUser(context.Background(), user, req))
+}
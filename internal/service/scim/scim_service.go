@@ -0,0 +1,559 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package scim
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/user_admin"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/internal/service/user_external_login"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// RouterPrefix the prefix of the SCIM endpoints
+	RouterPrefix = "/scim/v2"
+	// ExternalLoginProvider the provider of the user external login which keeps the SCIM identity of the user
+	ExternalLoginProvider = "scim"
+)
+
+// SCIMRepo scim repository
+type SCIMRepo interface {
+	GetUserPage(ctx context.Context, page, pageSize int) (users []*entity.User, total int64, err error)
+	GetUserExternalLogins(ctx context.Context, provider string, userIDs []string) (
+		logins []*entity.UserExternalLogin, err error)
+}
+
+// SCIMService provision the users and their roles pushed by the identity provider through SCIM 2.0.
+// The users are created, updated and suspended by the user admin service as if an administrator did,
+// and the groups are the roles of the site.
+type SCIMService struct {
+	scimRepo              SCIMRepo
+	userRepo              usercommon.UserRepo
+	userExternalLoginRepo user_external_login.UserExternalLoginRepo
+	userAdminService      *user_admin.UserAdminService
+	userRoleService       *role.UserRoleRelService
+	roleService           *role.RoleService
+	siteInfoService       siteinfo_common.SiteInfoCommonService
+}
+
+// NewSCIMService new scim service
+func NewSCIMService(
+	scimRepo SCIMRepo,
+	userRepo usercommon.UserRepo,
+	userExternalLoginRepo user_external_login.UserExternalLoginRepo,
+	userAdminService *user_admin.UserAdminService,
+	userRoleService *role.UserRoleRelService,
+	roleService *role.RoleService,
+	siteInfoService siteinfo_common.SiteInfoCommonService,
+) *SCIMService {
+	return &SCIMService{
+		scimRepo:              scimRepo,
+		userRepo:              userRepo,
+		userExternalLoginRepo: userExternalLoginRepo,
+		userAdminService:      userAdminService,
+		userRoleService:       userRoleService,
+		roleService:           roleService,
+		siteInfoService:       siteInfoService,
+	}
+}
+
+// binding the SCIM identity of the user kept in the meta info of the user external login,
+// the external id of the login is the userName
+type binding struct {
+	ExternalID string `json:"external_id,omitempty"`
+	// Groups the ids of the groups the user is added to, the role of the user is the highest of them
+	Groups []int `json:"groups,omitempty"`
+}
+
+// provisionedUser the user with its SCIM identity, the login is nil if the user is not provisioned by SCIM yet
+type provisionedUser struct {
+	user    *entity.User
+	login   *entity.UserExternalLogin
+	binding *binding
+	roleID  int
+}
+
+func (pu *provisionedUser) userName() string {
+	if pu.login != nil {
+		return pu.login.ExternalID
+	}
+	return pu.user.EMail
+}
+
+// GetServiceProviderConfig the features of the service, the filter supports the equality only
+func (ss *SCIMService) GetServiceProviderConfig(ctx context.Context) (resp map[string]any, err error) {
+	general, err := ss.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return nil, err
+	}
+	supported := func(b bool) map[string]any { return map[string]any{"supported": b} }
+	return map[string]any{
+		"schemas":        []string{schema.SCIMSchemaServiceProviderConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": schema.SCIMMaxResults},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "API key",
+			"description": "The API key created by the administrator, sent as the bearer token",
+		}},
+		"meta": map[string]any{
+			"resourceType": "ServiceProviderConfig",
+			"location":     general.SiteUrl + RouterPrefix + "/ServiceProviderConfig",
+		},
+	}, nil
+}
+
+// GetUser get the user
+func (ss *SCIMService) GetUser(ctx context.Context, userID string) (resp *schema.SCIMUser, err error) {
+	pu, err := ss.getProvisionedUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	siteURL, roles, err := ss.formatContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ss.formatUser(siteURL, roles, pu), nil
+}
+
+// GetUsers get the users by page, or the user matched by the filter
+func (ss *SCIMService) GetUsers(ctx context.Context, req *schema.SCIMListReq) (resp *schema.SCIMListResp, err error) {
+	startIndex, count := listRange(req)
+	var users []*provisionedUser
+	var total int64
+	if len(req.Filter) > 0 {
+		attribute, value, err := parseFilter(req.Filter)
+		if err != nil {
+			return nil, err
+		}
+		pu, err := ss.findUser(ctx, attribute, value)
+		if err != nil {
+			return nil, err
+		}
+		if pu != nil {
+			users, total = []*provisionedUser{pu}, 1
+		}
+		if startIndex > 1 || count == 0 {
+			users = nil
+		}
+	} else {
+		// the page size can not be 0, so one user is fetched only for the total
+		page, pageSize := (startIndex-1)/max(count, 1)+1, max(count, 1)
+		list, n, err := ss.scimRepo.GetUserPage(ctx, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		total = n
+		if count > 0 {
+			users, err = ss.provisionedUsers(ctx, list)
+			if err != nil {
+				return nil, err
+			}
+			startIndex = (page-1)*pageSize + 1
+		}
+	}
+
+	siteURL, roles, err := ss.formatContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp = newListResp(total, startIndex)
+	for _, pu := range users {
+		resp.Resources = append(resp.Resources, ss.formatUser(siteURL, roles, pu))
+	}
+	resp.ItemsPerPage = len(resp.Resources)
+	return resp, nil
+}
+
+// AddUser create the user, the user is suspended if it is created inactive
+func (ss *SCIMService) AddUser(ctx context.Context, operatorID string, req *schema.SCIMUser) (
+	resp *schema.SCIMUser, err error) {
+	email, userName, err := userIdentity(req)
+	if err != nil {
+		return nil, err
+	}
+	_, exist, err := ss.userExternalLoginRepo.GetByExternalID(ctx, ExternalLoginProvider, userName)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, errors.Conflict(reason.SCIMUserNameExists)
+	}
+	_, exist, err = ss.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, errors.Conflict(reason.EmailDuplicate)
+	}
+
+	// the user logs in with the single sign-on, or resets the password by the email
+	err = ss.userAdminService.AddUser(ctx, &schema.AddUserReq{
+		DisplayName: displayNameOf(req, email),
+		Email:       email,
+		Password:    randomPassword(),
+		LoginUserID: operatorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	user, exist, err := ss.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.InternalServer(reason.UnknownError)
+	}
+	pu := &provisionedUser{user: user, binding: &binding{ExternalID: req.ExternalID}, roleID: role.RoleUserID}
+	if err = ss.saveBinding(ctx, pu, userName); err != nil {
+		return nil, err
+	}
+	if req.Active != nil && !*req.Active {
+		if err = ss.setActive(ctx, operatorID, pu, false); err != nil {
+			return nil, err
+		}
+	}
+	log.Infof("user %s is provisioned by SCIM as %s", user.ID, userName)
+	return ss.GetUser(ctx, user.ID)
+}
+
+// UpdateUser replace the attributes of the user, the groups of the user are changed by the groups only
+func (ss *SCIMService) UpdateUser(ctx context.Context, operatorID, userID string, req *schema.SCIMUser) (
+	resp *schema.SCIMUser, err error) {
+	pu, err := ss.getProvisionedUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err = ss.applyUser(ctx, operatorID, pu, req); err != nil {
+		return nil, err
+	}
+	return ss.GetUser(ctx, userID)
+}
+
+// PatchUser patch the attributes of the user
+func (ss *SCIMService) PatchUser(ctx context.Context, operatorID, userID string, req *schema.SCIMPatchReq) (
+	resp *schema.SCIMUser, err error) {
+	pu, err := ss.getProvisionedUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	siteURL, roles, err := ss.formatContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	patched := ss.formatUser(siteURL, roles, pu)
+	if err = patchUser(ctx, patched, req); err != nil {
+		return nil, err
+	}
+	if err = ss.applyUser(ctx, operatorID, pu, patched); err != nil {
+		return nil, err
+	}
+	return ss.GetUser(ctx, userID)
+}
+
+// DeleteUser delete the user, the content of the user is kept
+func (ss *SCIMService) DeleteUser(ctx context.Context, operatorID, userID string) (err error) {
+	pu, err := ss.getProvisionedUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err = ss.checkLastAdmin(ctx, pu); err != nil {
+		return err
+	}
+	log.Infof("user %s is deleted by SCIM", userID)
+	return ss.userAdminService.UpdateUserStatus(ctx, &schema.UpdateUserStatusReq{
+		UserID:      userID,
+		Status:      constant.UserDeleted,
+		LoginUserID: operatorID,
+	})
+}
+
+// applyUser change the user to the attributes of the request
+func (ss *SCIMService) applyUser(ctx context.Context, operatorID string, pu *provisionedUser, req *schema.SCIMUser) error {
+	email, userName, err := userIdentity(req)
+	if err != nil {
+		return err
+	}
+	if userName != pu.userName() {
+		login, exist, err := ss.userExternalLoginRepo.GetByExternalID(ctx, ExternalLoginProvider, userName)
+		if err != nil {
+			return err
+		}
+		if exist && login.UserID != pu.user.ID {
+			return errors.Conflict(reason.SCIMUserNameExists)
+		}
+	}
+
+	if email != pu.user.EMail {
+		user, exist, err := ss.userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+		if exist && user.ID != pu.user.ID {
+			return errors.Conflict(reason.EmailDuplicate)
+		}
+	}
+	displayName := displayNameOf(req, email)
+	if displayName != pu.user.DisplayName || email != pu.user.EMail {
+		_, err = ss.userAdminService.EditUserProfile(ctx, &schema.EditUserProfileReq{
+			UserID:      pu.user.ID,
+			DisplayName: displayName,
+			Username:    pu.user.Username,
+			Email:       email,
+			LoginUserID: operatorID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	pu.binding.ExternalID = req.ExternalID
+	if err = ss.saveBinding(ctx, pu, userName); err != nil {
+		return err
+	}
+	if req.Active != nil {
+		return ss.setActive(ctx, operatorID, pu, *req.Active)
+	}
+	return nil
+}
+
+// setActive suspend the inactive user, so that the sessions and the API keys of the user are revoked
+func (ss *SCIMService) setActive(ctx context.Context, operatorID string, pu *provisionedUser, active bool) error {
+	status := ""
+	switch {
+	case active && pu.user.Status == entity.UserStatusSuspended:
+		status = constant.UserNormal
+	case !active && pu.user.Status == entity.UserStatusAvailable:
+		if err := ss.checkLastAdmin(ctx, pu); err != nil {
+			return err
+		}
+		status = constant.UserSuspended
+	default:
+		return nil
+	}
+	log.Infof("the status of user %s is changed to %s by SCIM", pu.user.ID, status)
+	return ss.userAdminService.UpdateUserStatus(ctx, &schema.UpdateUserStatusReq{
+		UserID:          pu.user.ID,
+		Status:          status,
+		SuspendDuration: "forever",
+		LoginUserID:     operatorID,
+	})
+}
+
+func (ss *SCIMService) getProvisionedUser(ctx context.Context, userID string) (*provisionedUser, error) {
+	user, exist, err := ss.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || user.Status == entity.UserStatusDeleted {
+		return nil, errors.NotFound(reason.UserNotFound)
+	}
+	users, err := ss.provisionedUsers(ctx, []*entity.User{user})
+	if err != nil {
+		return nil, err
+	}
+	return users[0], nil
+}
+
+func (ss *SCIMService) provisionedUsers(ctx context.Context, users []*entity.User) ([]*provisionedUser, error) {
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	logins, err := ss.scimRepo.GetUserExternalLogins(ctx, ExternalLoginProvider, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	loginMapping := make(map[string]*entity.UserExternalLogin, len(logins))
+	for _, login := range logins {
+		loginMapping[login.UserID] = login
+	}
+	roleMapping, err := ss.userRoleService.GetUserRoleMapping(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*provisionedUser, 0, len(users))
+	for _, user := range users {
+		pu := &provisionedUser{user: user, login: loginMapping[user.ID], binding: &binding{}, roleID: role.RoleUserID}
+		if pu.login != nil {
+			_ = json.Unmarshal([]byte(pu.login.MetaInfo), pu.binding)
+		}
+		if r, ok := roleMapping[user.ID]; ok && r != nil {
+			pu.roleID = r.ID
+		}
+		result = append(result, pu)
+	}
+	return result, nil
+}
+
+// findUser find the user by the userName or the email. The user not provisioned by SCIM yet is matched by the email,
+// so that the identity provider can take over the existing users.
+func (ss *SCIMService) findUser(ctx context.Context, attribute, value string) (*provisionedUser, error) {
+	var user *entity.User
+	switch {
+	case attribute == "username":
+		login, exist, err := ss.userExternalLoginRepo.GetByExternalID(ctx, ExternalLoginProvider, value)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			u, exist, err := ss.userRepo.GetByUserID(ctx, login.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if exist {
+				user = u
+			}
+			break
+		}
+		fallthrough
+	case isEmailsAttribute(attribute):
+		u, exist, err := ss.userRepo.GetByEmail(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			user = u
+		}
+	default:
+		return nil, errors.BadRequest(reason.SCIMFilterInvalid)
+	}
+	if user == nil || user.Status == entity.UserStatusDeleted {
+		return nil, nil
+	}
+	users, err := ss.provisionedUsers(ctx, []*entity.User{user})
+	if err != nil {
+		return nil, err
+	}
+	pu := users[0]
+	// the user provisioned with another userName is not the one of the userName
+	if attribute == "username" && pu.userName() != value {
+		return nil, nil
+	}
+	return pu, nil
+}
+
+func (ss *SCIMService) saveBinding(ctx context.Context, pu *provisionedUser, userName string) error {
+	metaInfo, _ := json.Marshal(pu.binding)
+	if pu.login == nil {
+		pu.login = &entity.UserExternalLogin{
+			UserID:     pu.user.ID,
+			Provider:   ExternalLoginProvider,
+			ExternalID: userName,
+			MetaInfo:   string(metaInfo),
+		}
+		return ss.userExternalLoginRepo.AddUserExternalLogin(ctx, pu.login)
+	}
+	if pu.login.ExternalID == userName && pu.login.MetaInfo == string(metaInfo) {
+		return nil
+	}
+	pu.login.ExternalID = userName
+	pu.login.MetaInfo = string(metaInfo)
+	return ss.userExternalLoginRepo.UpdateInfo(ctx, pu.login)
+}
+
+func (ss *SCIMService) formatContext(ctx context.Context) (siteURL string, roles map[int]*entity.Role, err error) {
+	general, err := ss.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	roles, err = ss.roleService.GetRoleMapping(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return general.SiteUrl + RouterPrefix, roles, nil
+}
+
+func (ss *SCIMService) formatUser(baseURL string, roles map[int]*entity.Role, pu *provisionedUser) *schema.SCIMUser {
+	active := pu.user.Status == entity.UserStatusAvailable
+	resp := &schema.SCIMUser{
+		Schemas:     []string{schema.SCIMSchemaUser},
+		ID:          pu.user.ID,
+		ExternalID:  pu.binding.ExternalID,
+		UserName:    pu.userName(),
+		Name:        &schema.SCIMName{Formatted: pu.user.DisplayName},
+		DisplayName: pu.user.DisplayName,
+		Emails:      []*schema.SCIMMultiValue{{Value: pu.user.EMail, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &schema.SCIMMeta{
+			ResourceType: "User",
+			Created:      pu.user.CreatedAt.UTC().Format(time.RFC3339),
+			LastModified: pu.user.UpdatedAt.UTC().Format(time.RFC3339),
+			Location:     baseURL + "/Users/" + pu.user.ID,
+		},
+	}
+	if r, ok := roles[pu.roleID]; ok {
+		resp.Groups = []*schema.SCIMMultiValue{{
+			Value:   strconv.Itoa(r.ID),
+			Display: r.Name,
+			Ref:     baseURL + "/Groups/" + strconv.Itoa(r.ID),
+		}}
+	}
+	return resp
+}
+
+// listRange the 1-based start index and the count of the query, the count is 0 when only the total is queried
+func listRange(req *schema.SCIMListReq) (startIndex, count int) {
+	startIndex, count = max(req.StartIndex, 1), schema.SCIMMaxResults
+	if req.Count != nil {
+		count = min(max(*req.Count, 0), schema.SCIMMaxResults)
+	}
+	return startIndex, count
+}
+
+func newListResp(total int64, startIndex int) *schema.SCIMListResp {
+	return &schema.SCIMListResp{
+		Schemas:      []string{schema.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		Resources:    make([]any, 0),
+	}
+}
+
+func randomPassword() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// pageOf the page of the list
+func pageOf[T any](list []T, startIndex, count int) []T {
+	from := min(startIndex-1, len(list))
+	to := min(from+count, len(list))
+	return list[from:to]
+}
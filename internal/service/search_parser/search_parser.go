@@ -21,16 +21,24 @@ package search_parser
 
 import (
 	"context"
-	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apache/answer/internal/base/constant"
-
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/tag_common"
 	usercommon "github.com/apache/answer/internal/service/user_common"
 	"github.com/apache/answer/pkg/converter"
+	"github.com/apache/answer/pkg/uid"
+	"github.com/apache/answer/plugin"
+)
+
+var (
+	numberRegexp       = regexp.MustCompile(`^\d+$`)
+	yearRegexp         = regexp.MustCompile(`^\d{4}$`)
+	relativeTimeRegexp = regexp.MustCompile(`^(\d+)([dwmy])$`)
 )
 
 type SearchParser struct {
@@ -45,291 +53,245 @@ func NewSearchParser(tagCommonService *tag_common.TagCommonService, userCommon *
 	}
 }
 
-// ParseStructure parse search structure, maybe match one of type all/questions/answers,
-// but if match two type, it will return false
+// ParseStructure parse search structure. The keywords, "phrases" and [tags] can be combined with AND/OR/NOT
+// and grouped by parentheses, the qualifiers like user:me always apply to the whole query.
+// The qualifiers may limit the search type to questions or answers, and is:question/is:answer has the highest priority.
 func (sp *SearchParser) ParseStructure(ctx context.Context, dto *schema.SearchDTO) (cond *schema.SearchCondition) {
-	cond = &schema.SearchCondition{}
+	cond = &schema.SearchCondition{
+		VoteAmount:   -1,
+		Views:        -1,
+		AnswerAmount: -1,
+	}
+	expr, qualifiers := parseQuery(dto.Query)
+
 	var (
-		query      = dto.Query
-		limitWords = 5
+		targetType string
+		invalid    = make([]*plugin.SearchExpr, 0)
 	)
+	for _, q := range qualifiers {
+		if q.key == "is" && !q.negated && (q.value == constant.QuestionObjectType || q.value == constant.AnswerObjectType) {
+			targetType = q.value
+			continue
+		}
+		if !sp.applyQualifier(ctx, cond, q, dto.UserID) {
+			// the qualifier can not be recognized, search it as a keyword
+			node := &plugin.SearchExpr{Type: plugin.SearchExprTerm, Value: q.key + ":" + q.value}
+			if q.negated {
+				node = &plugin.SearchExpr{Type: plugin.SearchExprNot, Children: []*plugin.SearchExpr{node}}
+			}
+			invalid = append(invalid, node)
+		}
+	}
+	if len(targetType) > 0 {
+		cond.TargetType = targetType
+	}
 
-	// match tags
-	cond.Tags = sp.parseTags(ctx, &query)
-
-	// match all
-	cond.UserID = sp.parseUserID(ctx, &query, dto.UserID)
-	cond.VoteAmount = sp.parseVotes(&query)
-	cond.Words = sp.parseWithin(&query)
+	cond.Expr = sp.resolveTags(ctx, combine(plugin.SearchExprAnd, append([]*plugin.SearchExpr{expr}, invalid...)))
+	cond.Words = positiveWords(cond.Expr)
+	cond.Tags, cond.NotTags = requiredTags(cond.Expr)
+	return cond
+}
 
-	// match questions
-	cond.NotAccepted = sp.parseNotAccepted(&query)
-	if cond.NotAccepted {
-		cond.TargetType = constant.QuestionObjectType
+// applyQualifier set the qualifier to the search condition, return false if the qualifier is invalid
+func (sp *SearchParser) applyQualifier(ctx context.Context, cond *schema.SearchCondition, q *qualifier,
+	currentUserID string) (ok bool) {
+	value := strings.ToLower(q.value)
+	// only these qualifiers can be negated
+	if q.negated && q.key != "has" && q.key != "closed" {
+		return false
 	}
-	cond.Views = sp.parseViews(&query)
-	if cond.Views != -1 {
+	switch q.key {
+	case "user":
+		if value == "me" {
+			cond.UserID = currentUserID
+			return true
+		}
+		user, has, err := sp.userCommon.GetUserBasicInfoByUserName(ctx, q.value)
+		if err != nil || !has {
+			return false
+		}
+		cond.UserID = user.ID
+	case "score":
+		if !numberRegexp.MatchString(value) {
+			return false
+		}
+		cond.VoteAmount = converter.StringToInt(value)
+	case "views":
+		if !numberRegexp.MatchString(value) {
+			return false
+		}
+		cond.Views = converter.StringToInt(value)
 		cond.TargetType = constant.QuestionObjectType
-	}
-	cond.AnswerAmount = sp.parseAnswers(&query)
-	if cond.AnswerAmount != -1 {
+	case "answers":
+		if !numberRegexp.MatchString(value) {
+			return false
+		}
+		cond.AnswerAmount = converter.StringToInt(value)
 		cond.TargetType = constant.QuestionObjectType
-	}
-
-	// match answers
-	cond.Accepted = sp.parseAccepted(&query)
-	if cond.Accepted {
+	case "hasaccepted":
+		if value != "no" {
+			return false
+		}
+		cond.NotAccepted = true
+		cond.TargetType = constant.QuestionObjectType
+	case "has":
+		if value != "accepted" {
+			return false
+		}
+		cond.HasAccepted, cond.NotAccepted = !q.negated, q.negated
+		cond.TargetType = constant.QuestionObjectType
+	case "isaccepted":
+		if value != "yes" {
+			return false
+		}
+		cond.Accepted = true
 		cond.TargetType = constant.AnswerObjectType
-	}
-	cond.QuestionID = sp.parseQuestionID(&query)
-	if cond.QuestionID != "" {
+	case "inquestion":
+		questionID := uid.DeShortID(q.value)
+		if !numberRegexp.MatchString(questionID) {
+			return false
+		}
+		cond.QuestionID = questionID
 		cond.TargetType = constant.AnswerObjectType
-	}
-
-	if sp.parseIsQuestion(&query) {
+	case "closed":
+		var closed bool
+		switch value {
+		case "yes", "true":
+			closed = true
+		case "no", "false":
+			closed = false
+		default:
+			return false
+		}
+		if closed != q.negated {
+			cond.Closed = plugin.ClosedCondTrue
+		} else {
+			cond.Closed = plugin.ClosedCondFalse
+		}
 		cond.TargetType = constant.QuestionObjectType
+	case "created", "updated":
+		timeRange, ok := parseTimeRange(value, time.Now())
+		if !ok {
+			return false
+		}
+		if q.key == "created" {
+			cond.Created = timeRange
+		} else {
+			cond.Updated = timeRange
+		}
+	case "lang":
+		if strings.IndexFunc(value, func(r rune) bool { return !isWordRune(r) }) >= 0 {
+			return false
+		}
+		cond.Lang = value
+	default:
+		return false
 	}
-	if sp.parseIsAnswer(&query) {
-		cond.TargetType = constant.AnswerObjectType
-	}
-
-	if len(strings.TrimSpace(query)) > 0 {
-		words := strings.Split(strings.TrimSpace(query), " ")
-		cond.Words = append(cond.Words, words...)
-	}
-
-	// check limit words
-	if len(cond.Words) > limitWords {
-		cond.Words = cond.Words[:limitWords]
-	}
-	return
+	return true
 }
 
-// parseTags parse search tags, return tag ids array
-func (sp *SearchParser) parseTags(ctx context.Context, query *string) (tags [][]string) {
-	var (
-		// expire tag pattern
-		exprTag = `\[(.*?)\]`
-		q       = *query
-		limit   = 5
-	)
-
-	re := regexp.MustCompile(exprTag)
-	res := re.FindAllStringSubmatch(q, -1)
-	if len(res) == 0 {
-		return
-	}
-
-	tags = make([][]string, 0)
-	for _, item := range res {
-		tagGroup := make([]string, 0)
-		tag, exists, err := sp.tagCommonService.GetTagBySlugName(ctx, item[1])
+// resolveTags get the tag ids of each tag node by slug name, the tag node will be removed if the tag is not found
+func (sp *SearchParser) resolveTags(ctx context.Context, expr *plugin.SearchExpr) *plugin.SearchExpr {
+	return walkExpr(expr, func(node *plugin.SearchExpr) *plugin.SearchExpr {
+		if node.Type != plugin.SearchExprTag {
+			return node
+		}
+		tag, exists, err := sp.tagCommonService.GetTagBySlugName(ctx, node.Value)
 		if err != nil || !exists {
-			continue
+			return nil
 		}
-		tagGroup = append(tagGroup, tag.ID)
-		if tag.MainTagID > 0 {
-			tagGroup = append(tagGroup, fmt.Sprintf("%d", tag.MainTagID))
+		// the tag also matches its synonyms and child tags
+		tagGroup, err := sp.tagCommonService.GetTagFamilyIDs(ctx, tag)
+		if err != nil {
+			return nil
 		}
-		synIDs, err := sp.tagCommonService.GetTagIDsByMainTagID(ctx, tag.ID)
-		if err != nil || !exists {
-			continue
+		return &plugin.SearchExpr{
+			Type:   plugin.SearchExprTag,
+			Value:  node.Value,
+			TagIDs: tagGroup,
 		}
-		tagGroup = append(tagGroup, tag.ID)
-		tagGroup = append(tagGroup, synIDs...)
-		tagGroup = converter.UniqueArray(tagGroup)
-		tags = append(tags, tagGroup)
-	}
-
-	// limit maximum 5 tags
-	if len(tags) > limit {
-		tags = tags[:limit]
-	}
-
-	q = strings.TrimSpace(re.ReplaceAllString(q, ""))
-	*query = q
-	return
+	})
 }
 
-// parseUserID return user id or current login user id
-func (sp *SearchParser) parseUserID(ctx context.Context, query *string, currentUserID string) (userID string) {
-	var (
-		exprUsername = `user:(\S+)`
-		exprMe       = "user:me"
-		q            = *query
-	)
-
-	re := regexp.MustCompile(exprUsername)
-	res := re.FindStringSubmatch(q)
-	if strings.Contains(q, exprMe) {
-		userID = currentUserID
-		q = strings.ReplaceAll(q, exprMe, "")
-	} else if len(res) > 1 {
-		name := res[1]
-		user, has, err := sp.userCommon.GetUserBasicInfoByUserName(ctx, name)
-		if err == nil && has {
-			userID = user.ID
-			q = re.ReplaceAllString(q, "")
+// parseTimeRange parse the time range, support the formats below:
+//
+//	2024-01-02, 2024-01, 2024     the day, month or year
+//	7d, 2w, 3m, 1y                the last 7 days, 2 weeks, 3 months or 1 year
+//	>2024-01, >=2024-01           after or since the month, also support < and <=
+//	2024-01-01..2024-03, 2024..*  between the dates, * means no limit
+func parseTimeRange(value string, now time.Time) (timeRange plugin.SearchTimeRange, ok bool) {
+	switch {
+	case strings.Contains(value, ".."):
+		fromStr, toStr, _ := strings.Cut(value, "..")
+		if len(fromStr) > 0 && fromStr != "*" {
+			start, _, ok := parseDate(fromStr, now)
+			if !ok {
+				return timeRange, false
+			}
+			timeRange.From = start.Unix()
 		}
-	}
-	*query = strings.TrimSpace(q)
-	return
-}
-
-// parseVotes return the votes of search query
-func (sp *SearchParser) parseVotes(query *string) (votes int) {
-	var (
-		expr = `score:(\d+)`
-		q    = *query
-	)
-	votes = -1
-
-	re := regexp.MustCompile(expr)
-	res := re.FindStringSubmatch(q)
-	if len(res) > 1 {
-		votes = converter.StringToInt(res[1])
-		q = re.ReplaceAllString(q, "")
-	}
-
-	*query = strings.TrimSpace(q)
-	return
-}
-
-// parseWithin parse quotes within words like: "hello world"
-func (sp *SearchParser) parseWithin(query *string) (words []string) {
-	var (
-		q    = *query
-		expr = `(?U)(".+")`
-	)
-	re := regexp.MustCompile(expr)
-	matches := re.FindAllStringSubmatch(q, -1)
-	words = []string{}
-	for _, match := range matches {
-		if len(match[1]) == 0 {
-			continue
+		if len(toStr) > 0 && toStr != "*" {
+			_, end, ok := parseDate(toStr, now)
+			if !ok {
+				return timeRange, false
+			}
+			timeRange.To = end.Unix()
 		}
-		words = append(words, match[1])
-	}
-	q = re.ReplaceAllString(q, "")
-	*query = strings.TrimSpace(q)
-	return
-}
-
-// parseNotAccepted return the question has not accepted the answer
-func (sp *SearchParser) parseNotAccepted(query *string) (notAccepted bool) {
-	var (
-		q    = *query
-		expr = `hasaccepted:no`
-	)
-
-	if strings.Contains(q, expr) {
-		q = strings.ReplaceAll(q, expr, "")
-		notAccepted = true
-	}
-
-	*query = strings.TrimSpace(q)
-	return
-}
-
-// parseIsQuestion check the result if only limit question or not
-func (sp *SearchParser) parseIsQuestion(query *string) (isQuestion bool) {
-	var (
-		q    = *query
-		expr = `is:question`
-	)
-
-	if strings.Contains(q, expr) {
-		q = strings.ReplaceAll(q, expr, "")
-		isQuestion = true
-	}
-
-	*query = strings.TrimSpace(q)
-	return
-}
-
-// parseViews check search has views or not
-func (sp *SearchParser) parseViews(query *string) (views int) {
-	var (
-		q    = *query
-		expr = `views:(\d+)`
-	)
-	views = -1
-
-	re := regexp.MustCompile(expr)
-	res := re.FindStringSubmatch(q)
-	if len(res) > 1 {
-		views = converter.StringToInt(res[1])
-		q = re.ReplaceAllString(q, "")
+	case strings.HasPrefix(value, ">="), strings.HasPrefix(value, "<="):
+		start, end, ok := parseDate(value[2:], now)
+		if !ok {
+			return timeRange, false
+		}
+		if value[0] == '>' {
+			timeRange.From = start.Unix()
+		} else {
+			timeRange.To = end.Unix()
+		}
+	case strings.HasPrefix(value, ">"), strings.HasPrefix(value, "<"):
+		start, end, ok := parseDate(value[1:], now)
+		if !ok {
+			return timeRange, false
+		}
+		if value[0] == '>' {
+			timeRange.From = end.Unix()
+		} else {
+			timeRange.To = start.Unix()
+		}
+	default:
+		start, end, ok := parseDate(value, now)
+		if !ok {
+			return timeRange, false
+		}
+		timeRange.From, timeRange.To = start.Unix(), end.Unix()
 	}
-	*query = strings.TrimSpace(q)
-	return
+	return timeRange, !timeRange.IsZero()
 }
 
-// parseAnswers check whether specified answer count for question
-func (sp *SearchParser) parseAnswers(query *string) (answers int) {
-	var (
-		q    = *query
-		expr = `answers:(\d+)`
-	)
-	answers = -1
-
-	re := regexp.MustCompile(expr)
-	res := re.FindStringSubmatch(q)
-	if len(res) > 1 {
-		answers = converter.StringToInt(res[1])
-		q = re.ReplaceAllString(q, "")
+// parseDate parse the date to a time range [start, end)
+func parseDate(value string, now time.Time) (start, end time.Time, ok bool) {
+	if res := relativeTimeRegexp.FindStringSubmatch(value); len(res) == 3 {
+		amount, _ := strconv.Atoi(res[1])
+		switch res[2] {
+		case "d":
+			start = now.AddDate(0, 0, -amount)
+		case "w":
+			start = now.AddDate(0, 0, -7*amount)
+		case "m":
+			start = now.AddDate(0, -amount, 0)
+		case "y":
+			start = now.AddDate(-amount, 0, 0)
+		}
+		return start, now, true
 	}
-
-	*query = strings.TrimSpace(q)
-	return
-}
-
-// parseAccepted check the search is limit accepted answer or not
-func (sp *SearchParser) parseAccepted(query *string) (accepted bool) {
-	var (
-		q    = *query
-		expr = `isaccepted:yes`
-	)
-
-	if strings.Contains(q, expr) {
-		accepted = true
-		q = strings.ReplaceAll(q, expr, "")
+	if yearRegexp.MatchString(value) {
+		start, err := time.ParseInLocation("2006", value, time.Local)
+		return start, start.AddDate(1, 0, 0), err == nil
 	}
-
-	*query = strings.TrimSpace(q)
-	return
-}
-
-// parseQuestionID check whether specified question's id
-func (sp *SearchParser) parseQuestionID(query *string) (questionID string) {
-	var (
-		q    = *query
-		expr = `inquestion:(\d+)`
-	)
-
-	re := regexp.MustCompile(expr)
-	res := re.FindStringSubmatch(q)
-	if len(res) == 2 {
-		questionID = res[1]
-		q = re.ReplaceAllString(q, "")
+	if start, err := time.ParseInLocation("2006-01", value, time.Local); err == nil {
+		return start, start.AddDate(0, 1, 0), true
 	}
-
-	*query = strings.TrimSpace(q)
-	return
-}
-
-// parseIsAnswer check the result if only limit answer or not
-func (sp *SearchParser) parseIsAnswer(query *string) (isAnswer bool) {
-	var (
-		q    = *query
-		expr = `is:answer`
-	)
-
-	if strings.Contains(q, expr) {
-		isAnswer = true
-		q = strings.ReplaceAll(q, expr, "")
+	if start, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return start, start.AddDate(0, 0, 1), true
 	}
-
-	*query = strings.TrimSpace(q)
-	return
+	return start, end, false
 }
@@ -0,0 +1,401 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/apache/answer/plugin"
+)
+
+const (
+	// maxQueryLeaves limit the maximum number of terms, phrases and tags in a query
+	maxQueryLeaves = 20
+	// maxQueryTags limit the maximum number of tags in a query
+	maxQueryTags = 5
+)
+
+type tokenType int
+
+const (
+	tokenTerm tokenType = iota
+	tokenPhrase
+	tokenTag
+	tokenQualifier
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	typ   tokenType
+	value string
+	// key of the qualifier token, like "user" of "user:me"
+	key string
+	// negated is true if the qualifier has a "-" or "NOT" prefix
+	negated bool
+}
+
+// qualifier is a filter like `user:me`, it always applies to the whole query wherever it is
+type qualifier struct {
+	key     string
+	value   string
+	negated bool
+}
+
+var (
+	qualifierKeys = map[string]bool{
+		"user": true, "score": true, "views": true, "answers": true, "is": true,
+		"has": true, "closed": true, "created": true, "updated": true, "lang": true,
+		"hasaccepted": true, "isaccepted": true, "inquestion": true,
+	}
+	qualifierRegexp = regexp.MustCompile(`^(\w+):(\S+)$`)
+	// Special characters will cause the search abnormal, such as search for "#" will get nearly all the content that Markdown format.
+	termReplaceRegexp = regexp.MustCompile(`[+#.<>_*]`)
+)
+
+// tokenize split the query into tokens
+func tokenize(query string) (tokens []*token) {
+	tokens = make([]*token, 0)
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, &token{typ: tokenLParen})
+			i++
+		case r == ')':
+			tokens = append(tokens, &token{typ: tokenRParen})
+			i++
+		case r == '"':
+			end := indexRune(runes, i+1, '"')
+			phrase := strings.TrimSpace(string(runes[i+1 : end]))
+			if len(phrase) > 0 {
+				tokens = append(tokens, &token{typ: tokenPhrase, value: phrase})
+			}
+			i = end + 1
+		case r == '-':
+			// "-" is a negation only at the beginning of a word
+			if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+				tokens = append(tokens, &token{typ: tokenNot})
+			}
+			i++
+		case r == '[':
+			end := indexRune(runes, i+1, ']')
+			if end == len(runes) {
+				// without the closing bracket, treat it as a normal word
+				word, next := readWord(runes, i+1)
+				tokens = appendWordToken(tokens, word)
+				i = next
+				continue
+			}
+			tag := strings.ToLower(strings.TrimSpace(string(runes[i+1 : end])))
+			if len(tag) > 0 {
+				tokens = append(tokens, &token{typ: tokenTag, value: tag})
+			}
+			i = end + 1
+		default:
+			word, next := readWord(runes, i)
+			tokens = appendWordToken(tokens, word)
+			i = next
+		}
+	}
+	return mergeNegatedQualifiers(tokens)
+}
+
+// indexRune find the rune from start, return the length of runes if not found
+func indexRune(runes []rune, start int, target rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == target {
+			return i
+		}
+	}
+	return len(runes)
+}
+
+// readWord read a word until space, parentheses or quote
+func readWord(runes []rune, start int) (word string, next int) {
+	next = start
+	for next < len(runes) {
+		r := runes[next]
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+			break
+		}
+		next++
+	}
+	return string(runes[start:next]), next
+}
+
+func appendWordToken(tokens []*token, word string) []*token {
+	switch word {
+	case "":
+		return tokens
+	case "AND", "&&":
+		return append(tokens, &token{typ: tokenAnd})
+	case "OR", "||":
+		return append(tokens, &token{typ: tokenOr})
+	case "NOT":
+		return append(tokens, &token{typ: tokenNot})
+	}
+	if res := qualifierRegexp.FindStringSubmatch(word); len(res) == 3 && qualifierKeys[strings.ToLower(res[1])] {
+		return append(tokens, &token{typ: tokenQualifier, key: strings.ToLower(res[1]), value: res[2]})
+	}
+	// the word may be split into several terms after the special characters are replaced
+	for _, term := range strings.Fields(termReplaceRegexp.ReplaceAllString(word, " ")) {
+		tokens = append(tokens, &token{typ: tokenTerm, value: term})
+	}
+	return tokens
+}
+
+// mergeNegatedQualifiers merge the negation into the following qualifier, like `-has:accepted`
+func mergeNegatedQualifiers(tokens []*token) []*token {
+	merged := make([]*token, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].typ == tokenNot && i+1 < len(tokens) && tokens[i+1].typ == tokenQualifier {
+			tokens[i+1].negated = !tokens[i+1].negated
+			continue
+		}
+		merged = append(merged, tokens[i])
+	}
+	return merged
+}
+
+// queryParser is a recursive descent parser of the search query. The grammar is:
+//
+//	query   = orExpr { orExpr }
+//	orExpr  = andExpr { "OR" andExpr }
+//	andExpr = unary { ["AND"] unary }
+//	unary   = ( "NOT" | "-" ) unary | primary
+//	primary = "(" orExpr ")" | term | "phrase" | [tag]
+//
+// Qualifiers are not part of the expression, they are collected separately and always apply to the whole query.
+type queryParser struct {
+	tokens     []*token
+	pos        int
+	leaves     int
+	tags       int
+	qualifiers []*qualifier
+}
+
+// parseQuery parse the query into a boolean expression and the qualifiers,
+// the tag node value is the slug name and the tag ids are not resolved.
+func parseQuery(query string) (expr *plugin.SearchExpr, qualifiers []*qualifier) {
+	p := &queryParser{qualifiers: make([]*qualifier, 0)}
+	for _, t := range tokenize(query) {
+		if t.typ == tokenQualifier {
+			p.qualifiers = append(p.qualifiers, &qualifier{key: t.key, value: t.value, negated: t.negated})
+			continue
+		}
+		p.tokens = append(p.tokens, t)
+	}
+
+	nodes := make([]*plugin.SearchExpr, 0)
+	for p.pos < len(p.tokens) {
+		if node := p.parseOr(); node != nil {
+			nodes = append(nodes, node)
+		}
+		// skip the unmatched right parenthesis
+		if p.peek(tokenRParen) {
+			p.pos++
+		}
+	}
+	return combine(plugin.SearchExprAnd, nodes), p.qualifiers
+}
+
+func (p *queryParser) peek(typ tokenType) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].typ == typ
+}
+
+func (p *queryParser) parseOr() *plugin.SearchExpr {
+	nodes := make([]*plugin.SearchExpr, 0)
+	if node := p.parseAnd(); node != nil {
+		nodes = append(nodes, node)
+	}
+	for p.peek(tokenOr) {
+		p.pos++
+		if node := p.parseAnd(); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return combine(plugin.SearchExprOr, nodes)
+}
+
+func (p *queryParser) parseAnd() *plugin.SearchExpr {
+	nodes := make([]*plugin.SearchExpr, 0)
+	for p.pos < len(p.tokens) && !p.peek(tokenOr) && !p.peek(tokenRParen) {
+		if p.peek(tokenAnd) {
+			p.pos++
+			continue
+		}
+		if node := p.parseUnary(); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return combine(plugin.SearchExprAnd, nodes)
+}
+
+func (p *queryParser) parseUnary() *plugin.SearchExpr {
+	if !p.peek(tokenNot) {
+		return p.parsePrimary()
+	}
+	p.pos++
+	if p.pos >= len(p.tokens) || p.peek(tokenOr) || p.peek(tokenRParen) {
+		return nil
+	}
+	node := p.parseUnary()
+	if node == nil {
+		return nil
+	}
+	// double negation
+	if node.Type == plugin.SearchExprNot {
+		return node.Children[0]
+	}
+	return &plugin.SearchExpr{Type: plugin.SearchExprNot, Children: []*plugin.SearchExpr{node}}
+}
+
+func (p *queryParser) parsePrimary() *plugin.SearchExpr {
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.typ {
+	case tokenLParen:
+		node := p.parseOr()
+		if p.peek(tokenRParen) {
+			p.pos++
+		}
+		return node
+	case tokenTerm, tokenPhrase, tokenTag:
+		if p.leaves >= maxQueryLeaves {
+			return nil
+		}
+		if t.typ == tokenTag {
+			if p.tags >= maxQueryTags {
+				return nil
+			}
+			p.tags++
+		}
+		p.leaves++
+		node := &plugin.SearchExpr{Value: t.value}
+		switch t.typ {
+		case tokenTerm:
+			node.Type = plugin.SearchExprTerm
+		case tokenPhrase:
+			node.Type = plugin.SearchExprPhrase
+		default:
+			node.Type = plugin.SearchExprTag
+		}
+		return node
+	}
+	// the operator is in the wrong place, ignore it
+	return nil
+}
+
+// combine the nodes with and/or, the nested node with the same type is flattened
+func combine(typ plugin.SearchExprType, nodes []*plugin.SearchExpr) *plugin.SearchExpr {
+	children := make([]*plugin.SearchExpr, 0, len(nodes))
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		if node.Type == typ {
+			children = append(children, node.Children...)
+		} else {
+			children = append(children, node)
+		}
+	}
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return children[0]
+	}
+	return &plugin.SearchExpr{Type: typ, Children: children}
+}
+
+// walkExpr rebuild the expression by the leaf function, the leaf will be removed if the function returns nil
+func walkExpr(expr *plugin.SearchExpr, leaf func(node *plugin.SearchExpr) *plugin.SearchExpr) *plugin.SearchExpr {
+	if expr == nil {
+		return nil
+	}
+	switch expr.Type {
+	case plugin.SearchExprAnd, plugin.SearchExprOr:
+		nodes := make([]*plugin.SearchExpr, 0, len(expr.Children))
+		for _, child := range expr.Children {
+			nodes = append(nodes, walkExpr(child, leaf))
+		}
+		return combine(expr.Type, nodes)
+	case plugin.SearchExprNot:
+		child := walkExpr(expr.Children[0], leaf)
+		if child == nil {
+			return nil
+		}
+		return &plugin.SearchExpr{Type: plugin.SearchExprNot, Children: []*plugin.SearchExpr{child}}
+	}
+	return leaf(expr)
+}
+
+// positiveWords get all terms and phrases which are not negated
+func positiveWords(expr *plugin.SearchExpr) (words []string) {
+	words = make([]string, 0)
+	if expr == nil {
+		return words
+	}
+	switch expr.Type {
+	case plugin.SearchExprAnd, plugin.SearchExprOr:
+		for _, child := range expr.Children {
+			words = append(words, positiveWords(child)...)
+		}
+	case plugin.SearchExprTerm, plugin.SearchExprPhrase:
+		words = append(words, expr.Value)
+	}
+	return words
+}
+
+// requiredTags get the tags that must or must not be matched, only the top level tags are required
+func requiredTags(expr *plugin.SearchExpr) (tags, notTags [][]string) {
+	tags, notTags = make([][]string, 0), make([][]string, 0)
+	if expr == nil {
+		return
+	}
+	nodes := []*plugin.SearchExpr{expr}
+	if expr.Type == plugin.SearchExprAnd {
+		nodes = expr.Children
+	}
+	for _, node := range nodes {
+		switch {
+		case node.Type == plugin.SearchExprTag:
+			tags = append(tags, node.TagIDs)
+		case node.Type == plugin.SearchExprNot && node.Children[0].Type == plugin.SearchExprTag:
+			notTags = append(notTags, node.Children[0].TagIDs)
+		}
+	}
+	return
+}
+
+// isWordRune check the rune is a valid rune of the language name like "c++" "c#" "objective-c"
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("+#-._", r))
+}
@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package search_parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/answer/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+// exprString format the expression like `and(a, or("b c", [go]), not(d))`
+func exprString(expr *plugin.SearchExpr) string {
+	if expr == nil {
+		return ""
+	}
+	switch expr.Type {
+	case plugin.SearchExprTerm:
+		return expr.Value
+	case plugin.SearchExprPhrase:
+		return `"` + expr.Value + `"`
+	case plugin.SearchExprTag:
+		return "[" + expr.Value + "]"
+	}
+	children := make([]string, 0, len(expr.Children))
+	for _, child := range expr.Children {
+		children = append(children, exprString(child))
+	}
+	return string(expr.Type) + "(" + strings.Join(children, ", ") + ")"
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query      string
+		expr       string
+		qualifiers []*qualifier
+	}{
+		{query: "", expr: ""},
+		{query: "golang web", expr: "and(golang, web)"},
+		{query: "golang AND web", expr: "and(golang, web)"},
+		{query: "golang OR rust web", expr: "or(golang, and(rust, web))"},
+		{query: "(golang || rust) && web", expr: "and(or(golang, rust), web)"},
+		{query: `"hello world" -java`, expr: `and("hello world", not(java))`},
+		{query: "NOT NOT java", expr: "java"},
+		{query: "[Go] -[java] c++", expr: "and([go], not([java]), c)"},
+		{query: "((golang", expr: "golang"},
+		{query: "golang) OR", expr: "golang"},
+		{query: "test-driven", expr: "test-driven"},
+		{
+			query:      "user:me golang -has:accepted is:question",
+			expr:       "golang",
+			qualifiers: []*qualifier{{key: "user", value: "me"}, {key: "has", value: "accepted", negated: true}, {key: "is", value: "question"}},
+		},
+		{
+			query:      "NOT closed:yes foo:bar",
+			expr:       "foo:bar",
+			qualifiers: []*qualifier{{key: "closed", value: "yes", negated: true}},
+		},
+		{query: "[a] [b] [c] [d] [e] [f]", expr: "and([a], [b], [c], [d], [e])"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, qualifiers := parseQuery(tt.query)
+			assert.Equal(t, tt.expr, exprString(expr))
+			if tt.qualifiers == nil {
+				tt.qualifiers = []*qualifier{}
+			}
+			assert.Equal(t, tt.qualifiers, qualifiers)
+		})
+	}
+}
+
+func TestParseQuery_MaxLeaves(t *testing.T) {
+	expr, _ := parseQuery(strings.Repeat("a ", maxQueryLeaves+5))
+	assert.Len(t, expr.Children, maxQueryLeaves)
+}
+
+func TestPositiveWordsAndRequiredTags(t *testing.T) {
+	expr, _ := parseQuery(`golang -java "hello world" (web OR [api]) [go] -[c]`)
+	expr = walkExpr(expr, func(node *plugin.SearchExpr) *plugin.SearchExpr {
+		if node.Type == plugin.SearchExprTag {
+			node.TagIDs = []string{node.Value}
+		}
+		return node
+	})
+	assert.Equal(t, []string{"golang", "hello world", "web"}, positiveWords(expr))
+	tags, notTags := requiredTags(expr)
+	assert.Equal(t, [][]string{{"go"}}, tags)
+	assert.Equal(t, [][]string{{"c"}}, notTags)
+}
+
+func TestParseTimeRange(t *testing.T) {
+	now := time.Date(2024, 5, 20, 10, 0, 0, 0, time.Local)
+	day := func(y int, m time.Month, d int) int64 {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.Local).Unix()
+	}
+	tests := []struct {
+		value string
+		want  plugin.SearchTimeRange
+		ok    bool
+	}{
+		{value: "2024-01-02", want: plugin.SearchTimeRange{From: day(2024, 1, 2), To: day(2024, 1, 3)}, ok: true},
+		{value: "2024-01", want: plugin.SearchTimeRange{From: day(2024, 1, 1), To: day(2024, 2, 1)}, ok: true},
+		{value: "2023", want: plugin.SearchTimeRange{From: day(2023, 1, 1), To: day(2024, 1, 1)}, ok: true},
+		{value: ">2024-01", want: plugin.SearchTimeRange{From: day(2024, 2, 1)}, ok: true},
+		{value: ">=2024-01", want: plugin.SearchTimeRange{From: day(2024, 1, 1)}, ok: true},
+		{value: "<2024-01", want: plugin.SearchTimeRange{To: day(2024, 1, 1)}, ok: true},
+		{value: "<=2024-01", want: plugin.SearchTimeRange{To: day(2024, 2, 1)}, ok: true},
+		{value: "2023..2024-03", want: plugin.SearchTimeRange{From: day(2023, 1, 1), To: day(2024, 4, 1)}, ok: true},
+		{value: "2023..*", want: plugin.SearchTimeRange{From: day(2023, 1, 1)}, ok: true},
+		{value: "7d", want: plugin.SearchTimeRange{From: now.AddDate(0, 0, -7).Unix(), To: now.Unix()}, ok: true},
+		{value: "1m", want: plugin.SearchTimeRange{From: now.AddDate(0, -1, 0).Unix(), To: now.Unix()}, ok: true},
+		{value: "yesterday", ok: false},
+		{value: "*..*", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseTimeRange(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
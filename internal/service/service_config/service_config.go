@@ -19,9 +19,27 @@
 
 package service_config
 
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/entity"
+)
+
 type ServiceConfig struct {
 	UploadPath                    string `json:"upload_path" mapstructure:"upload_path" yaml:"upload_path"`
 	CleanUpUploads                bool   `json:"clean_up_uploads" mapstructure:"clean_up_uploads" yaml:"clean_up_uploads"`
 	CleanOrphanUploadsPeriodHours int    `json:"clean_orphan_uploads_period_hours" mapstructure:"clean_orphan_uploads_period_hours" yaml:"clean_orphan_uploads_period_hours"`
 	PurgeDeletedFilesPeriodDays   int    `json:"purge_deleted_files_period_days" mapstructure:"purge_deleted_files_period_days" yaml:"purge_deleted_files_period_days"`
 }
+
+// GetUploadPath get the upload path of the tenant in the context, the tenants keep their files in their own directories
+func (s *ServiceConfig) GetUploadPath(ctx context.Context) string {
+	tenantID := handler.GetTenantID(ctx)
+	if tenantID == entity.DefaultTenantID {
+		return s.UploadPath
+	}
+	return filepath.Join(s.UploadPath, "tenants", strconv.Itoa(tenantID))
+}
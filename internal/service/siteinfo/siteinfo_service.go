@@ -495,6 +495,22 @@ func (s *SiteInfoService) SaveSiteMCP(ctx context.Context, req *schema.SiteMCPRe
 	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeMCP, siteInfo)
 }
 
+// GetSiteSearch get site search configuration
+func (s *SiteInfoService) GetSiteSearch(ctx context.Context) (resp *schema.SiteSearchResp, err error) {
+	return s.siteInfoCommonService.GetSiteSearch(ctx)
+}
+
+// SaveSiteSearch save site search configuration
+func (s *SiteInfoService) SaveSiteSearch(ctx context.Context, req *schema.SiteSearchReq) (err error) {
+	content, _ := json.Marshal(req)
+	siteInfo := &entity.SiteInfo{
+		Type:    constant.SiteTypeSearch,
+		Content: string(content),
+		Status:  1,
+	}
+	return s.siteInfoRepo.SaveByType(ctx, constant.SiteTypeSearch, siteInfo)
+}
+
 // GetSMTPConfig get smtp config
 func (s *SiteInfoService) GetSMTPConfig(ctx context.Context) (resp *schema.GetSMTPConfigResp, err error) {
 	emailConfig, err := s.emailService.GetEmailConfig(ctx)
@@ -711,7 +727,7 @@ func (s *SiteInfoService) CleanUpRemovedBrandingFiles(
 
 func (s *SiteInfoService) GetAIProvider(ctx context.Context) (resp []*schema.GetAIProviderResp, err error) {
 	resp = make([]*schema.GetAIProviderResp, 0)
-	aiProviderConfig, err := s.configService.GetStringValue(context.TODO(), constant.AIConfigProvider)
+	aiProviderConfig, err := s.configService.GetStringValue(ctx, constant.AIConfigProvider)
 	if err != nil {
 		log.Error(err)
 		return resp, nil
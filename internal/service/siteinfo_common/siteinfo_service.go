@@ -66,6 +66,7 @@ type SiteInfoCommonService interface {
 	IsBrandingFileUsed(ctx context.Context, filePath string) bool
 	GetSiteAI(ctx context.Context) (resp *schema.SiteAIResp, err error)
 	GetSiteMCP(ctx context.Context) (resp *schema.SiteMCPResp, err error)
+	GetSiteSearch(ctx context.Context) (resp *schema.SiteSearchResp, err error)
 }
 
 // NewSiteInfoCommonService new site info common service
@@ -203,6 +204,24 @@ func (s *siteInfoCommonService) GetSiteQuestion(ctx context.Context) (resp *sche
 	if err = s.GetSiteInfoByType(ctx, constant.SiteTypeQuestions, resp); err != nil {
 		return nil, err
 	}
+	if resp.CloseVoteThreshold <= 0 {
+		resp.CloseVoteThreshold = schema.DefaultCloseVoteThreshold
+	}
+	if resp.CloseVoteExpireDays <= 0 {
+		resp.CloseVoteExpireDays = schema.DefaultCloseVoteExpireDays
+	}
+	if resp.BountyMinAmount <= 0 {
+		resp.BountyMinAmount = schema.DefaultBountyMinAmount
+	}
+	if resp.BountyMaxAmount < resp.BountyMinAmount {
+		resp.BountyMaxAmount = max(schema.DefaultBountyMaxAmount, resp.BountyMinAmount)
+	}
+	if resp.BountyDays <= 0 {
+		resp.BountyDays = schema.DefaultBountyDays
+	}
+	if resp.DraftRetentionDays <= 0 {
+		resp.DraftRetentionDays = schema.DefaultDraftRetentionDays
+	}
 	return resp, nil
 }
 
@@ -324,3 +343,17 @@ func (s *siteInfoCommonService) GetSiteMCP(ctx context.Context) (resp *schema.Si
 	}
 	return resp, nil
 }
+
+// GetSiteSearch get site search configuration, the hybrid search is enabled by default
+func (s *siteInfoCommonService) GetSiteSearch(ctx context.Context) (resp *schema.SiteSearchResp, err error) {
+	resp = &schema.SiteSearchResp{
+		HybridEnabled:  true,
+		KeywordWeight:  1,
+		SemanticWeight: 1,
+		RRFK:           schema.DefaultSearchRRFK,
+	}
+	if err = s.GetSiteInfoByType(ctx, constant.SiteTypeSearch, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,344 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package space
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/space_common"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// SpaceService the private spaces and their members
+type SpaceService struct {
+	spaceRepo   space_common.SpaceRepo
+	spaceCommon *space_common.SpaceCommon
+	userCommon  *usercommon.UserCommon
+}
+
+// NewSpaceService new space service
+func NewSpaceService(
+	spaceRepo space_common.SpaceRepo,
+	spaceCommon *space_common.SpaceCommon,
+	userCommon *usercommon.UserCommon,
+) *SpaceService {
+	return &SpaceService{
+		spaceRepo:   spaceRepo,
+		spaceCommon: spaceCommon,
+		userCommon:  userCommon,
+	}
+}
+
+// AddSpace create the space by the administrator
+func (ss *SpaceService) AddSpace(ctx context.Context, req *schema.AddSpaceReq) (resp *schema.AddSpaceResp, err error) {
+	slugName := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(req.SlugName), " ", "-"))
+	_, exist, err := ss.spaceRepo.GetSpaceBySlugName(ctx, slugName)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, errors.BadRequest(reason.SpaceSlugNameExists)
+	}
+
+	ownerUserID := req.UserID
+	if len(req.OwnerUsername) > 0 {
+		owner, exist, err := ss.userCommon.GetUserBasicInfoByUserName(ctx, req.OwnerUsername)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			return nil, errors.BadRequest(reason.UserNotFound)
+		}
+		ownerUserID = owner.ID
+	}
+
+	space := &entity.Space{
+		SlugName:    slugName,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		UserID:      req.UserID,
+		Status:      entity.SpaceStatusAvailable,
+	}
+	owner := &entity.SpaceMember{UserID: ownerUserID, Role: entity.SpaceRoleOwner}
+	if err = ss.spaceRepo.AddSpace(ctx, space, owner); err != nil {
+		return nil, err
+	}
+	return &schema.AddSpaceResp{ID: space.ID}, nil
+}
+
+// UpdateSpace update the space by its owner or the administrator
+func (ss *SpaceService) UpdateSpace(ctx context.Context, req *schema.UpdateSpaceReq) (err error) {
+	space, err := ss.getManagedSpace(ctx, req.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+	space.DisplayName = req.DisplayName
+	space.Description = req.Description
+	return ss.spaceRepo.UpdateSpace(ctx, space, []string{"display_name", "description"})
+}
+
+// DeleteSpace delete the space by the administrator
+func (ss *SpaceService) DeleteSpace(ctx context.Context, req *schema.DeleteSpaceReq) (err error) {
+	space, exist, err := ss.spaceCommon.GetSpace(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.SpaceNotFound)
+	}
+	space.Status = entity.SpaceStatusDeleted
+	return ss.spaceRepo.UpdateSpace(ctx, space, []string{"status"})
+}
+
+// GetSpace get the space that the user can read
+func (ss *SpaceService) GetSpace(ctx context.Context, req *schema.GetSpaceReq) (resp *schema.SpaceResp, err error) {
+	space, exist, err := ss.spaceCommon.GetSpace(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.NotFound(reason.SpaceNotFound)
+	}
+	canView, err := ss.spaceCommon.CanViewSpace(ctx, space.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.NotFound(reason.SpaceNotFound)
+	}
+	memberRole, err := ss.spaceCommon.GetMemberRole(ctx, space.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return ss.formatSpace(ctx, space, memberRole)
+}
+
+// GetSpacePage get all available spaces for the administrator
+func (ss *SpaceService) GetSpacePage(ctx context.Context, req *schema.GetSpacePageReq) (
+	pageModel *pager.PageModel, err error) {
+	spaces, total, err := ss.spaceRepo.GetSpacePage(ctx, req.Page, req.PageSize, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*schema.SpaceResp, 0, len(spaces))
+	for _, space := range spaces {
+		item, err := ss.formatSpace(ctx, space, 0)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// GetUserSpaces get the available spaces that the user is a member of
+func (ss *SpaceService) GetUserSpaces(ctx context.Context, userID string) (resp []*schema.SpaceResp, err error) {
+	resp = make([]*schema.SpaceResp, 0)
+	members, err := ss.spaceRepo.GetUserSpaceMemberList(ctx, userID)
+	if err != nil || len(members) == 0 {
+		return resp, err
+	}
+	roleMapping := make(map[int]int, len(members))
+	spaceIDs := make([]int, 0, len(members))
+	for _, member := range members {
+		roleMapping[member.SpaceID] = member.Role
+		spaceIDs = append(spaceIDs, member.SpaceID)
+	}
+	spaces, err := ss.spaceRepo.GetSpaceListByIDs(ctx, spaceIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, space := range spaces {
+		if space.Status != entity.SpaceStatusAvailable {
+			continue
+		}
+		item, err := ss.formatSpace(ctx, space, roleMapping[space.ID])
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, item)
+	}
+	return resp, nil
+}
+
+// GetSpaceMemberPage get the members of the space that the user can read
+func (ss *SpaceService) GetSpaceMemberPage(ctx context.Context, req *schema.GetSpaceMemberPageReq) (
+	pageModel *pager.PageModel, err error) {
+	canView, err := ss.spaceCommon.CanViewSpace(ctx, req.SpaceID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.NotFound(reason.SpaceNotFound)
+	}
+	members, total, err := ss.spaceRepo.GetSpaceMemberPage(ctx, req.SpaceID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	userMapping, err := ss.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*schema.SpaceMemberResp, 0, len(members))
+	for _, member := range members {
+		user, ok := userMapping[member.UserID]
+		if !ok {
+			continue
+		}
+		list = append(list, &schema.SpaceMemberResp{
+			User:     user,
+			Role:     entity.SpaceRoleIntToString[member.Role],
+			JoinedAt: member.CreatedAt.Unix(),
+		})
+	}
+	return pager.NewPageModel(total, list), nil
+}
+
+// AddSpaceMember add the user to the space, or change its role if it is already a member
+func (ss *SpaceService) AddSpaceMember(ctx context.Context, req *schema.AddSpaceMemberReq) (err error) {
+	if _, err = ss.getManagedSpace(ctx, req.SpaceID, req.UserID); err != nil {
+		return err
+	}
+	user, exist, err := ss.userCommon.GetUserBasicInfoByUserName(ctx, req.Username)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.UserNotFound)
+	}
+	member, exist, err := ss.spaceRepo.GetSpaceMember(ctx, req.SpaceID, user.ID)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return ss.changeMemberRole(ctx, member, entity.SpaceRoleMapping[req.Role])
+	}
+	return ss.spaceRepo.AddSpaceMember(ctx, &entity.SpaceMember{
+		SpaceID: req.SpaceID,
+		UserID:  user.ID,
+		Role:    entity.SpaceRoleMapping[req.Role],
+	})
+}
+
+// UpdateSpaceMember change the role of the member
+func (ss *SpaceService) UpdateSpaceMember(ctx context.Context, req *schema.UpdateSpaceMemberReq) (err error) {
+	if _, err = ss.getManagedSpace(ctx, req.SpaceID, req.UserID); err != nil {
+		return err
+	}
+	member, exist, err := ss.spaceRepo.GetSpaceMember(ctx, req.SpaceID, req.MemberUserID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.SpaceMemberNotFound)
+	}
+	return ss.changeMemberRole(ctx, member, entity.SpaceRoleMapping[req.Role])
+}
+
+// RemoveSpaceMember remove the member from the space by the owner, or the member leaves the space
+func (ss *SpaceService) RemoveSpaceMember(ctx context.Context, req *schema.RemoveSpaceMemberReq) (err error) {
+	if req.MemberUserID != req.UserID {
+		if _, err = ss.getManagedSpace(ctx, req.SpaceID, req.UserID); err != nil {
+			return err
+		}
+	}
+	member, exist, err := ss.spaceRepo.GetSpaceMember(ctx, req.SpaceID, req.MemberUserID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.SpaceMemberNotFound)
+	}
+	if err = ss.checkLastOwner(ctx, member); err != nil {
+		return err
+	}
+	return ss.spaceRepo.RemoveSpaceMember(ctx, req.SpaceID, req.MemberUserID)
+}
+
+func (ss *SpaceService) changeMemberRole(ctx context.Context, member *entity.SpaceMember, memberRole int) (err error) {
+	if member.Role == memberRole {
+		return nil
+	}
+	if err = ss.checkLastOwner(ctx, member); err != nil {
+		return err
+	}
+	return ss.spaceRepo.UpdateSpaceMemberRole(ctx, member.SpaceID, member.UserID, memberRole)
+}
+
+// checkLastOwner the space must have at least one owner, so the last owner can not leave or be demoted
+func (ss *SpaceService) checkLastOwner(ctx context.Context, member *entity.SpaceMember) (err error) {
+	if member.Role != entity.SpaceRoleOwner {
+		return nil
+	}
+	ownerCount, err := ss.spaceRepo.CountSpaceMember(ctx, member.SpaceID, entity.SpaceRoleOwner)
+	if err != nil {
+		return err
+	}
+	if ownerCount <= 1 {
+		return errors.BadRequest(reason.SpaceOwnerRequired)
+	}
+	return nil
+}
+
+func (ss *SpaceService) getManagedSpace(ctx context.Context, spaceID int, userID string) (
+	space *entity.Space, err error) {
+	space, exist, err := ss.spaceCommon.GetSpace(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.NotFound(reason.SpaceNotFound)
+	}
+	canManage, err := ss.spaceCommon.CanManageSpace(ctx, spaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.Forbidden(reason.SpaceNoPermission)
+	}
+	return space, nil
+}
+
+func (ss *SpaceService) formatSpace(ctx context.Context, space *entity.Space, memberRole int) (
+	resp *schema.SpaceResp, err error) {
+	memberCount, err := ss.spaceRepo.CountSpaceMember(ctx, space.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.SpaceResp{
+		ID:          space.ID,
+		SlugName:    space.SlugName,
+		DisplayName: space.DisplayName,
+		Description: space.Description,
+		MemberCount: memberCount,
+		CreatedAt:   space.CreatedAt.Unix(),
+		Role:        entity.SpaceRoleIntToString[memberRole],
+	}, nil
+}
@@ -0,0 +1,177 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package space_common
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/service/role"
+)
+
+// SpaceRepo space repository
+type SpaceRepo interface {
+	AddSpace(ctx context.Context, space *entity.Space, owner *entity.SpaceMember) (err error)
+	UpdateSpace(ctx context.Context, space *entity.Space, cols []string) (err error)
+	GetSpace(ctx context.Context, id int) (space *entity.Space, exist bool, err error)
+	GetSpaceBySlugName(ctx context.Context, slugName string) (space *entity.Space, exist bool, err error)
+	GetSpaceListByIDs(ctx context.Context, ids []int) (spaces []*entity.Space, err error)
+	GetSpacePage(ctx context.Context, page, pageSize int, query string) (spaces []*entity.Space, total int64, err error)
+	AddSpaceMember(ctx context.Context, member *entity.SpaceMember) (err error)
+	UpdateSpaceMemberRole(ctx context.Context, spaceID int, userID string, role int) (err error)
+	RemoveSpaceMember(ctx context.Context, spaceID int, userID string) (err error)
+	GetSpaceMember(ctx context.Context, spaceID int, userID string) (member *entity.SpaceMember, exist bool, err error)
+	GetSpaceMemberPage(ctx context.Context, spaceID, page, pageSize int) (members []*entity.SpaceMember, total int64, err error)
+	GetUserSpaceMemberList(ctx context.Context, userID string) (members []*entity.SpaceMember, err error)
+	CountSpaceMember(ctx context.Context, spaceID, role int) (count int64, err error)
+}
+
+// SpaceCommon checks whether the user can read or post the contents of the space
+type SpaceCommon struct {
+	spaceRepo          SpaceRepo
+	userRoleRelService *role.UserRoleRelService
+}
+
+// NewSpaceCommon new space common service
+func NewSpaceCommon(
+	spaceRepo SpaceRepo,
+	userRoleRelService *role.UserRoleRelService,
+) *SpaceCommon {
+	return &SpaceCommon{
+		spaceRepo:          spaceRepo,
+		userRoleRelService: userRoleRelService,
+	}
+}
+
+// GetSpace get the available space
+func (sc *SpaceCommon) GetSpace(ctx context.Context, spaceID int) (space *entity.Space, exist bool, err error) {
+	space, exist, err = sc.spaceRepo.GetSpace(ctx, spaceID)
+	if err != nil || !exist {
+		return nil, false, err
+	}
+	if space.Status != entity.SpaceStatusAvailable {
+		return nil, false, nil
+	}
+	return space, true, nil
+}
+
+// GetMemberRole get the role of the user in the available space, 0 if the user is not a member
+func (sc *SpaceCommon) GetMemberRole(ctx context.Context, spaceID int, userID string) (memberRole int, err error) {
+	if len(userID) == 0 {
+		return 0, nil
+	}
+	_, exist, err := sc.GetSpace(ctx, spaceID)
+	if err != nil || !exist {
+		return 0, err
+	}
+	member, exist, err := sc.spaceRepo.GetSpaceMember(ctx, spaceID, userID)
+	if err != nil || !exist {
+		return 0, err
+	}
+	return member.Role, nil
+}
+
+// CanViewSpace whether the user can read the contents of the space.
+// The public contents are visible to everyone, the administrators and moderators can read every space.
+func (sc *SpaceCommon) CanViewSpace(ctx context.Context, spaceID int, userID string) (bool, error) {
+	if spaceID == entity.PublicSpaceID {
+		return true, nil
+	}
+	if len(userID) == 0 {
+		return false, nil
+	}
+	memberRole, err := sc.GetMemberRole(ctx, spaceID, userID)
+	if err != nil {
+		return false, err
+	}
+	if memberRole > 0 {
+		return true, nil
+	}
+	return sc.isAdminModerator(ctx, userID)
+}
+
+// CanPostInSpace whether the user can ask, answer and comment in the space, the viewer can only read.
+func (sc *SpaceCommon) CanPostInSpace(ctx context.Context, spaceID int, userID string) (bool, error) {
+	if spaceID == entity.PublicSpaceID {
+		return true, nil
+	}
+	memberRole, err := sc.GetMemberRole(ctx, spaceID, userID)
+	if err != nil {
+		return false, err
+	}
+	if memberRole == entity.SpaceRoleOwner || memberRole == entity.SpaceRoleMember {
+		return true, nil
+	}
+	return sc.isAdminModerator(ctx, userID)
+}
+
+// CanManageSpace whether the user can manage the members and settings of the space
+func (sc *SpaceCommon) CanManageSpace(ctx context.Context, spaceID int, userID string) (bool, error) {
+	memberRole, err := sc.GetMemberRole(ctx, spaceID, userID)
+	if err != nil {
+		return false, err
+	}
+	if memberRole == entity.SpaceRoleOwner {
+		return true, nil
+	}
+	return sc.isAdmin(ctx, userID)
+}
+
+// GetUserSpaceIDs get the ids of the available spaces that the user is a member of
+func (sc *SpaceCommon) GetUserSpaceIDs(ctx context.Context, userID string) (spaceIDs []int, err error) {
+	spaceIDs = make([]int, 0)
+	if len(userID) == 0 {
+		return spaceIDs, nil
+	}
+	members, err := sc.spaceRepo.GetUserSpaceMemberList(ctx, userID)
+	if err != nil || len(members) == 0 {
+		return spaceIDs, err
+	}
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.SpaceID)
+	}
+	spaces, err := sc.spaceRepo.GetSpaceListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, space := range spaces {
+		if space.Status == entity.SpaceStatusAvailable {
+			spaceIDs = append(spaceIDs, space.ID)
+		}
+	}
+	return spaceIDs, nil
+}
+
+func (sc *SpaceCommon) isAdminModerator(ctx context.Context, userID string) (bool, error) {
+	roleID, err := sc.userRoleRelService.GetUserRole(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return roleID == role.RoleAdminID || roleID == role.RoleModeratorID, nil
+}
+
+func (sc *SpaceCommon) isAdmin(ctx context.Context, userID string) (bool, error) {
+	roleID, err := sc.userRoleRelService.GetUserRole(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return roleID == role.RoleAdminID, nil
+}
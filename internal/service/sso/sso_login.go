@@ -0,0 +1,380 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package sso
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/pkg/oidc"
+	"github.com/apache/answer/pkg/saml"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// identity the user authenticated by the identity provider
+type identity struct {
+	ExternalID  string
+	Email       string
+	DisplayName string
+	Username    string
+	Avatar      string
+	MetaInfo    string
+	// Groups is nil when the provider does not send the groups, then the role of the user is kept
+	Groups []string
+	Active bool
+}
+
+// AuthRedirectURL start the single sign-on, returns the url of the identity provider.
+// The state of the binding intent, generated for the connectors of the user, is carried over.
+func (ss *SSOService) AuthRedirectURL(ctx context.Context, slugName, state string) (redirectURL string, err error) {
+	provider, err := ss.getEnabledProvider(ctx, slugName, "")
+	if err != nil {
+		return "", err
+	}
+	stateInfo := &schema.ExternalLoginOAuthState{
+		Provider: provider.ExternalLoginProvider(),
+		Intent:   schema.ExternalLoginOAuthStateLoginIntent,
+	}
+	if len(state) > 0 {
+		oldStateInfo, err := ss.userExternalLoginService.ConsumeOAuthState(ctx, state)
+		if err != nil {
+			return "", err
+		}
+		if oldStateInfo == nil || oldStateInfo.Provider != stateInfo.Provider {
+			return "", errors.BadRequest(reason.UserAccessDenied)
+		}
+		stateInfo.Intent = oldStateInfo.Intent
+		stateInfo.UserID = oldStateInfo.UserID
+	}
+	siteURL, err := ss.siteURL(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	switch provider.Protocol {
+	case entity.SSOProtocolOIDC:
+		config := &schema.SSOOIDCConfig{}
+		_ = json.Unmarshal([]byte(provider.Config), config)
+		p, err := ss.oidcProvider(ctx, config.Issuer)
+		if err != nil {
+			return "", wrapLoginError(provider, err)
+		}
+		verifier, challenge := oidc.NewPKCE()
+		stateInfo.CodeVerifier = verifier
+		stateInfo.Nonce = oidc.RandomString(16)
+		state, err = ss.userExternalLoginService.SaveOAuthState(ctx, stateInfo)
+		if err != nil {
+			return "", err
+		}
+		return p.AuthCodeURL(config.ClientID, oidcRedirectURL(siteURL, provider), state,
+			stateInfo.Nonce, challenge, config.Scopes), nil
+	case entity.SSOProtocolSAML:
+		sp, err := ss.serviceProvider(siteURL, provider)
+		if err != nil {
+			return "", wrapLoginError(provider, err)
+		}
+		stateInfo.RequestID = saml.NewRequestID()
+		state, err = ss.userExternalLoginService.SaveOAuthState(ctx, stateInfo)
+		if err != nil {
+			return "", err
+		}
+		return sp.AuthnRequestURL(stateInfo.RequestID, state)
+	default:
+		return "", errors.NotFound(reason.SSOProviderNotFound)
+	}
+}
+
+// OIDCCallback receive the authorization code of the OpenID Connect provider
+func (ss *SSOService) OIDCCallback(ctx context.Context, slugName, state, code string) (
+	stateInfo *schema.ExternalLoginOAuthState, userInfo *schema.ExternalLoginUserInfoCache, err error) {
+	provider, err := ss.getEnabledProvider(ctx, slugName, entity.SSOProtocolOIDC)
+	if err != nil {
+		return nil, nil, err
+	}
+	stateInfo, err = ss.consumeState(ctx, provider, state)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(code) == 0 || len(stateInfo.CodeVerifier) == 0 {
+		return nil, nil, wrapLoginError(provider, fmt.Errorf("the authorization code is missing"))
+	}
+	siteURL, err := ss.siteURL(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	config := &schema.SSOOIDCConfig{}
+	_ = json.Unmarshal([]byte(provider.Config), config)
+	p, err := ss.oidcProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, nil, wrapLoginError(provider, err)
+	}
+	token, err := p.Exchange(ctx, config.ClientID, config.ClientSecret, oidcRedirectURL(siteURL, provider),
+		code, stateInfo.CodeVerifier)
+	if err != nil {
+		return nil, nil, wrapLoginError(provider, err)
+	}
+	claims, err := p.VerifyIDToken(ctx, token.IDToken, config.ClientID, stateInfo.Nonce)
+	if err != nil {
+		return nil, nil, wrapLoginError(provider, err)
+	}
+	// the userinfo may carry more claims than the id token, e.g. the groups
+	if len(token.AccessToken) > 0 {
+		userInfoClaims, err := p.UserInfo(ctx, token.AccessToken)
+		if err != nil {
+			log.Warnf("single sign-on with %s get userinfo failed: %v", provider.SlugName, err)
+		} else if userInfoClaims.String("sub") == claims.String("sub") {
+			for k, v := range userInfoClaims {
+				if _, ok := claims[k]; !ok {
+					claims[k] = v
+				}
+			}
+		}
+	}
+
+	id := &identity{
+		ExternalID:  claims.String("sub"),
+		DisplayName: claims.String("name"),
+		Username:    claims.String("preferred_username"),
+		Avatar:      claims.String("picture"),
+		Active:      true,
+	}
+	// only the verified email is trusted, otherwise the user is asked to confirm the email
+	if verified, _ := claims.Bool("email_verified"); verified {
+		id.Email = claims.String("email")
+	}
+	groupsClaim := config.GroupsClaim
+	if len(groupsClaim) == 0 {
+		groupsClaim = "groups"
+	}
+	if groups, exist := claims.Strings(groupsClaim); exist {
+		id.Groups = append(make([]string, 0, len(groups)), groups...)
+	}
+	if len(config.ActiveClaim) > 0 {
+		if active, exist := claims.Bool(config.ActiveClaim); exist {
+			id.Active = active
+		}
+	}
+	metaInfo, _ := json.Marshal(claims)
+	id.MetaInfo = string(metaInfo)
+
+	userInfo, err = ss.provision(ctx, provider, id)
+	return stateInfo, userInfo, err
+}
+
+// SAMLCallback receive the response of the SAML provider posted to the assertion consumer service
+func (ss *SSOService) SAMLCallback(ctx context.Context, slugName string, req *schema.SSOSAMLResponseReq) (
+	stateInfo *schema.ExternalLoginOAuthState, userInfo *schema.ExternalLoginUserInfoCache, err error) {
+	provider, err := ss.getEnabledProvider(ctx, slugName, entity.SSOProtocolSAML)
+	if err != nil {
+		return nil, nil, err
+	}
+	stateInfo, err = ss.consumeState(ctx, provider, req.RelayState)
+	if err != nil {
+		return nil, nil, err
+	}
+	siteURL, err := ss.siteURL(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sp, err := ss.serviceProvider(siteURL, provider)
+	if err != nil {
+		return nil, nil, wrapLoginError(provider, err)
+	}
+	assertion, err := sp.ParseResponse(req.SAMLResponse, stateInfo.RequestID)
+	if err != nil {
+		return nil, nil, wrapLoginError(provider, err)
+	}
+
+	config := &schema.SSOSAMLConfig{}
+	_ = json.Unmarshal([]byte(provider.Config), config)
+	attribute := func(name, defaultName string) []string {
+		if len(name) == 0 {
+			name = defaultName
+		}
+		return assertion.Attributes[name]
+	}
+	first := func(values []string) string {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	id := &identity{
+		ExternalID:  assertion.NameID,
+		Email:       first(attribute(config.EmailAttribute, "email")),
+		DisplayName: first(attribute(config.NameAttribute, "displayName")),
+		Username:    first(attribute(config.UsernameAttribute, "username")),
+		Active:      true,
+	}
+	groupsAttribute := config.GroupsAttribute
+	if len(groupsAttribute) == 0 {
+		groupsAttribute = "groups"
+	}
+	if groups, exist := assertion.Attributes[groupsAttribute]; exist {
+		id.Groups = append(make([]string, 0, len(groups)), groups...)
+	}
+	if len(config.ActiveAttribute) > 0 {
+		if values, exist := assertion.Attributes[config.ActiveAttribute]; exist {
+			id.Active = isActiveValue(first(values))
+		}
+	}
+	metaInfo, _ := json.Marshal(assertion.Attributes)
+	id.MetaInfo = string(metaInfo)
+
+	userInfo, err = ss.provision(ctx, provider, id)
+	return stateInfo, userInfo, err
+}
+
+func (ss *SSOService) consumeState(ctx context.Context, provider *entity.SSOProvider, state string) (
+	*schema.ExternalLoginOAuthState, error) {
+	stateInfo, err := ss.userExternalLoginService.ConsumeOAuthState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if stateInfo == nil || stateInfo.Provider != provider.ExternalLoginProvider() {
+		return nil, wrapLoginError(provider, fmt.Errorf("the state is invalid or expired"))
+	}
+	return stateInfo, nil
+}
+
+// provision sync the existing user with the identity provider before the user logs in.
+// The user deactivated by the provider is suspended, and the role follows the groups of the user.
+// The new user is registered by the user external login with the role.
+func (ss *SSOService) provision(ctx context.Context, provider *entity.SSOProvider, id *identity) (
+	userInfo *schema.ExternalLoginUserInfoCache, err error) {
+	if len(id.ExternalID) == 0 {
+		return nil, wrapLoginError(provider, fmt.Errorf("the subject is missing"))
+	}
+	userInfo = &schema.ExternalLoginUserInfoCache{
+		Provider:    provider.ExternalLoginProvider(),
+		ExternalID:  id.ExternalID,
+		DisplayName: id.DisplayName,
+		Username:    id.Username,
+		Email:       id.Email,
+		Avatar:      id.Avatar,
+		MetaInfo:    id.MetaInfo,
+		RoleID:      mappedRole(parseGroupRoleMapping(provider), id.Groups),
+	}
+
+	externalLogin, exist, err := ss.userExternalLoginRepo.GetByExternalID(ctx, userInfo.Provider, userInfo.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		if !id.Active {
+			return nil, errors.Forbidden(reason.SSOUserDeactivated)
+		}
+		return userInfo, nil
+	}
+	user, exist, err := ss.userRepo.GetByUserID(ctx, externalLogin.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || user.Status == entity.UserStatusDeleted {
+		return userInfo, nil
+	}
+
+	if !id.Active {
+		if user.Status == entity.UserStatusAvailable {
+			log.Infof("user %s is deactivated by the identity provider %s", user.ID, provider.SlugName)
+			err = ss.userAdminService.UpdateUserStatus(ctx, &schema.UpdateUserStatusReq{
+				UserID: user.ID,
+				Status: constant.UserSuspended,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, errors.Forbidden(reason.SSOUserDeactivated)
+	}
+
+	if userInfo.RoleID > 0 {
+		if err = ss.syncUserRole(ctx, provider, user.ID, userInfo.RoleID); err != nil {
+			return nil, err
+		}
+	}
+	return userInfo, nil
+}
+
+// syncUserRole change the role of the user to the one granted by the groups,
+// the sessions of the user are revoked by the change. The last administrator is never demoted.
+func (ss *SSOService) syncUserRole(ctx context.Context, provider *entity.SSOProvider, userID string, roleID int) error {
+	currentRoleID, err := ss.userRoleService.GetUserRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if currentRoleID == roleID {
+		return nil
+	}
+	if _, exist, err := ss.roleService.GetRole(ctx, roleID); err != nil {
+		return err
+	} else if !exist {
+		log.Warnf("the groups of %s map to the role %d which does not exist, the role is kept", provider.SlugName, roleID)
+		return nil
+	}
+	if currentRoleID == role.RoleAdminID {
+		admins, err := ss.userRoleService.GetUserByRoleID(ctx, []int{role.RoleAdminID})
+		if err != nil {
+			return err
+		}
+		if len(admins) <= 1 {
+			log.Warnf("the groups of %s demote the last administrator %s, the role is kept", provider.SlugName, userID)
+			return nil
+		}
+	}
+	log.Infof("the role of user %s is changed from %d to %d by the groups of %s",
+		userID, currentRoleID, roleID, provider.SlugName)
+	return ss.userAdminService.UpdateUserRole(ctx, &schema.UpdateUserRoleReq{
+		UserID: userID,
+		RoleID: roleID,
+	})
+}
+
+// mappedRole the most privileged role of the groups, the user role when none is matched.
+// It is 0 when there is no mapping or the provider does not send the groups, then the role is left unchanged.
+func mappedRole(mapping []*schema.SSOGroupRole, groups []string) int {
+	if len(mapping) == 0 || groups == nil {
+		return 0
+	}
+	roleID := role.RoleUserID
+	for _, m := range mapping {
+		if hasString(groups, m.Group) && role.Priority(m.RoleID) > role.Priority(roleID) {
+			roleID = m.RoleID
+		}
+	}
+	return roleID
+}
+
+func isActiveValue(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "false", "0", "no", "inactive", "disabled", "suspended", "deprovisioned":
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,441 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package sso
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/user_admin"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/internal/service/user_external_login"
+	"github.com/apache/answer/pkg/oidc"
+	"github.com/apache/answer/pkg/saml"
+	"github.com/segmentfault/pacman/errors"
+)
+
+const (
+	// LoginRouterPrefix the start of the single sign-on, the slug name of the provider follows
+	LoginRouterPrefix = "/sso/login/"
+	// OIDCCallbackRouterPrefix the redirect uri of the OpenID Connect provider
+	OIDCCallbackRouterPrefix = "/sso/callback/"
+	// SAMLRouterPrefix the metadata and the assertion consumer service of the SAML service provider
+	SAMLRouterPrefix = "/sso/saml/"
+
+	apiRouterPrefix = "/answer/api/v1"
+
+	// oidcProviderRefreshInterval the discovery document is fetched again after the interval
+	oidcProviderRefreshInterval = time.Hour
+)
+
+var slugNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// SSOProviderRepo sso provider repository
+type SSOProviderRepo interface {
+	AddSSOProvider(ctx context.Context, provider *entity.SSOProvider) (err error)
+	UpdateSSOProvider(ctx context.Context, provider *entity.SSOProvider) (err error)
+	DeleteSSOProvider(ctx context.Context, id int) (err error)
+	GetSSOProvider(ctx context.Context, id int) (provider *entity.SSOProvider, exist bool, err error)
+	GetSSOProviderBySlugName(ctx context.Context, slugName string) (provider *entity.SSOProvider, exist bool, err error)
+	GetSSOProviderList(ctx context.Context, onlyEnabled bool) (providers []*entity.SSOProvider, err error)
+}
+
+// SSOService the single sign-on with OpenID Connect and SAML 2.0 identity providers.
+// The users are provisioned just in time by the user external login, their roles follow the groups of the provider.
+type SSOService struct {
+	ssoProviderRepo          SSOProviderRepo
+	userExternalLoginRepo    user_external_login.UserExternalLoginRepo
+	userRepo                 usercommon.UserRepo
+	userRoleService          *role.UserRoleRelService
+	roleService              *role.RoleService
+	userAdminService         *user_admin.UserAdminService
+	userExternalLoginService *user_external_login.UserExternalLoginService
+	siteInfoService          siteinfo_common.SiteInfoCommonService
+	httpClient               *http.Client
+	oidcProviders            sync.Map
+}
+
+type cachedOIDCProvider struct {
+	provider  *oidc.Provider
+	fetchedAt time.Time
+}
+
+// NewSSOService new sso service
+func NewSSOService(
+	ssoProviderRepo SSOProviderRepo,
+	userExternalLoginRepo user_external_login.UserExternalLoginRepo,
+	userRepo usercommon.UserRepo,
+	userRoleService *role.UserRoleRelService,
+	roleService *role.RoleService,
+	userAdminService *user_admin.UserAdminService,
+	userExternalLoginService *user_external_login.UserExternalLoginService,
+	siteInfoService siteinfo_common.SiteInfoCommonService,
+) *SSOService {
+	return &SSOService{
+		ssoProviderRepo:          ssoProviderRepo,
+		userExternalLoginRepo:    userExternalLoginRepo,
+		userRepo:                 userRepo,
+		userRoleService:          userRoleService,
+		roleService:              roleService,
+		userAdminService:         userAdminService,
+		userExternalLoginService: userExternalLoginService,
+		siteInfoService:          siteInfoService,
+		httpClient:               &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetSSOProviders get all sso providers
+func (ss *SSOService) GetSSOProviders(ctx context.Context) (resp []*schema.SSOProviderResp, err error) {
+	providers, err := ss.ssoProviderRepo.GetSSOProviderList(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	siteURL, err := ss.siteURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.SSOProviderResp, 0, len(providers))
+	for _, provider := range providers {
+		resp = append(resp, ss.formatProvider(provider, siteURL))
+	}
+	return resp, nil
+}
+
+// AddSSOProvider add sso provider
+func (ss *SSOService) AddSSOProvider(ctx context.Context, req *schema.AddSSOProviderReq) (
+	resp *schema.AddSSOProviderResp, err error) {
+	if err = ss.checkSlugName(ctx, req.SlugName, 0); err != nil {
+		return nil, err
+	}
+	provider := &entity.SSOProvider{}
+	if err = ss.fillProvider(ctx, provider, req); err != nil {
+		return nil, err
+	}
+	if err = ss.ssoProviderRepo.AddSSOProvider(ctx, provider); err != nil {
+		return nil, err
+	}
+	return &schema.AddSSOProviderResp{ID: provider.ID}, nil
+}
+
+// UpdateSSOProvider update sso provider
+func (ss *SSOService) UpdateSSOProvider(ctx context.Context, req *schema.UpdateSSOProviderReq) (err error) {
+	provider, exist, err := ss.ssoProviderRepo.GetSSOProvider(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.SSOProviderNotFound)
+	}
+	if err = ss.checkSlugName(ctx, req.SlugName, provider.ID); err != nil {
+		return err
+	}
+	// the client secret is never returned, so the empty one means it is unchanged
+	if req.OIDC != nil && len(req.OIDC.ClientSecret) == 0 && provider.Protocol == entity.SSOProtocolOIDC {
+		oldConfig := &schema.SSOOIDCConfig{}
+		_ = json.Unmarshal([]byte(provider.Config), oldConfig)
+		req.OIDC.ClientSecret = oldConfig.ClientSecret
+	}
+	if err = ss.fillProvider(ctx, provider, &req.AddSSOProviderReq); err != nil {
+		return err
+	}
+	return ss.ssoProviderRepo.UpdateSSOProvider(ctx, provider)
+}
+
+// DeleteSSOProvider delete sso provider, the users keep their accounts but can not log in with it anymore
+func (ss *SSOService) DeleteSSOProvider(ctx context.Context, req *schema.DeleteSSOProviderReq) (err error) {
+	_, exist, err := ss.ssoProviderRepo.GetSSOProvider(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.SSOProviderNotFound)
+	}
+	return ss.ssoProviderRepo.DeleteSSOProvider(ctx, req.ID)
+}
+
+// GetEnabledSSOProviders get the providers shown on the login page
+func (ss *SSOService) GetEnabledSSOProviders(ctx context.Context) (providers []*entity.SSOProvider, err error) {
+	return ss.ssoProviderRepo.GetSSOProviderList(ctx, true)
+}
+
+// LoginURL the url of the single sign-on of the provider
+func (ss *SSOService) LoginURL(siteURL string, provider *entity.SSOProvider) string {
+	return siteURL + apiRouterPrefix + LoginRouterPrefix + provider.SlugName
+}
+
+func (ss *SSOService) checkSlugName(ctx context.Context, slugName string, id int) error {
+	if !slugNameRegexp.MatchString(slugName) {
+		return errors.BadRequest(reason.SSOProviderSlugInvalid)
+	}
+	provider, exist, err := ss.ssoProviderRepo.GetSSOProviderBySlugName(ctx, slugName)
+	if err != nil {
+		return err
+	}
+	if exist && provider.ID != id {
+		return errors.BadRequest(reason.SSOProviderSlugExists)
+	}
+	return nil
+}
+
+// fillProvider check the settings of the protocol and fill them into the provider
+func (ss *SSOService) fillProvider(ctx context.Context, provider *entity.SSOProvider, req *schema.AddSSOProviderReq) error {
+	var config any
+	switch req.Protocol {
+	case entity.SSOProtocolOIDC:
+		if req.OIDC == nil {
+			return configInvalid(ctx, "the OpenID Connect settings are required")
+		}
+		if len(req.OIDC.Scopes) == 0 {
+			req.OIDC.Scopes = []string{"openid", "profile", "email"}
+		}
+		if !hasString(req.OIDC.Scopes, "openid") {
+			req.OIDC.Scopes = append([]string{"openid"}, req.OIDC.Scopes...)
+		}
+		req.OIDC.Issuer = strings.TrimSuffix(req.OIDC.Issuer, "/")
+		config = req.OIDC
+	case entity.SSOProtocolSAML:
+		if req.SAML == nil {
+			return configInvalid(ctx, "the SAML settings are required")
+		}
+		if _, err := saml.ParseCertificates(req.SAML.IdPCertificate); err != nil {
+			return configInvalid(ctx, err.Error())
+		}
+		config = req.SAML
+	}
+	roles, err := ss.roleService.GetRoleMapping(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range req.GroupRoleMapping {
+		if roles[m.RoleID] == nil {
+			return errors.BadRequest(reason.RoleNotFound)
+		}
+	}
+	content, _ := json.Marshal(config)
+	mapping, _ := json.Marshal(req.GroupRoleMapping)
+	if req.GroupRoleMapping == nil {
+		mapping = []byte("[]")
+	}
+
+	provider.SlugName = req.SlugName
+	provider.Name = req.Name
+	provider.Protocol = req.Protocol
+	provider.Enabled = req.Enabled
+	provider.Config = string(content)
+	provider.GroupRoleMapping = string(mapping)
+	return nil
+}
+
+func (ss *SSOService) formatProvider(provider *entity.SSOProvider, siteURL string) *schema.SSOProviderResp {
+	resp := &schema.SSOProviderResp{
+		ID:               provider.ID,
+		SlugName:         provider.SlugName,
+		Name:             provider.Name,
+		Protocol:         provider.Protocol,
+		Enabled:          provider.Enabled,
+		GroupRoleMapping: parseGroupRoleMapping(provider),
+		LoginURL:         ss.LoginURL(siteURL, provider),
+	}
+	switch provider.Protocol {
+	case entity.SSOProtocolOIDC:
+		resp.OIDC = &schema.SSOOIDCConfig{}
+		_ = json.Unmarshal([]byte(provider.Config), resp.OIDC)
+		resp.OIDC.ClientSecret = ""
+		resp.RedirectURL = oidcRedirectURL(siteURL, provider)
+	case entity.SSOProtocolSAML:
+		resp.SAML = &schema.SSOSAMLConfig{}
+		_ = json.Unmarshal([]byte(provider.Config), resp.SAML)
+		resp.MetadataURL = samlMetadataURL(siteURL, provider)
+		resp.ACSURL = samlACSURL(siteURL, provider)
+	}
+	return resp
+}
+
+func (ss *SSOService) siteURL(ctx context.Context) (string, error) {
+	general, err := ss.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return "", err
+	}
+	return general.SiteUrl, nil
+}
+
+func parseGroupRoleMapping(provider *entity.SSOProvider) []*schema.SSOGroupRole {
+	mapping := make([]*schema.SSOGroupRole, 0)
+	_ = json.Unmarshal([]byte(provider.GroupRoleMapping), &mapping)
+	return mapping
+}
+
+func configInvalid(ctx context.Context, cause string) error {
+	msg := translator.TrWithData(handler.GetLangByCtx(ctx), reason.SSOProviderConfigInvalid,
+		map[string]any{"Reason": cause})
+	return errors.BadRequest(reason.SSOProviderConfigInvalid).WithMsg(msg)
+}
+
+func oidcRedirectURL(siteURL string, provider *entity.SSOProvider) string {
+	return siteURL + apiRouterPrefix + OIDCCallbackRouterPrefix + provider.SlugName
+}
+
+func samlMetadataURL(siteURL string, provider *entity.SSOProvider) string {
+	return siteURL + apiRouterPrefix + SAMLRouterPrefix + provider.SlugName + "/metadata"
+}
+
+func samlACSURL(siteURL string, provider *entity.SSOProvider) string {
+	return siteURL + apiRouterPrefix + SAMLRouterPrefix + provider.SlugName + "/acs"
+}
+
+func hasString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// oidcProvider the discovered provider of the issuer, it is cached to keep the signing keys
+func (ss *SSOService) oidcProvider(ctx context.Context, issuer string) (*oidc.Provider, error) {
+	if v, ok := ss.oidcProviders.Load(issuer); ok {
+		cached := v.(*cachedOIDCProvider)
+		if time.Since(cached.fetchedAt) < oidcProviderRefreshInterval {
+			return cached.provider, nil
+		}
+	}
+	provider, err := oidc.Discover(ctx, ss.httpClient, issuer)
+	if err != nil {
+		return nil, err
+	}
+	ss.oidcProviders.Store(issuer, &cachedOIDCProvider{provider: provider, fetchedAt: time.Now()})
+	return provider, nil
+}
+
+func (ss *SSOService) serviceProvider(siteURL string, provider *entity.SSOProvider) (*saml.ServiceProvider, error) {
+	config := &schema.SSOSAMLConfig{}
+	if err := json.Unmarshal([]byte(provider.Config), config); err != nil {
+		return nil, err
+	}
+	certs, err := saml.ParseCertificates(config.IdPCertificate)
+	if err != nil {
+		return nil, err
+	}
+	return &saml.ServiceProvider{
+		EntityID:        samlMetadataURL(siteURL, provider),
+		ACSURL:          samlACSURL(siteURL, provider),
+		IdPEntityID:     config.IdPEntityID,
+		IdPSSOURL:       config.IdPSSOURL,
+		IdPCertificates: certs,
+	}, nil
+}
+
+// getEnabledProvider get the enabled provider of the slug name, the disabled one is treated as not found
+func (ss *SSOService) getEnabledProvider(ctx context.Context, slugName, protocol string) (*entity.SSOProvider, error) {
+	provider, exist, err := ss.ssoProviderRepo.GetSSOProviderBySlugName(ctx, slugName)
+	if err != nil {
+		return nil, err
+	}
+	if !exist || !provider.Enabled || (len(protocol) > 0 && provider.Protocol != protocol) {
+		return nil, errors.NotFound(reason.SSOProviderNotFound)
+	}
+	return provider, nil
+}
+
+// SAMLMetadata the metadata of the site as the service provider of the SAML provider
+func (ss *SSOService) SAMLMetadata(ctx context.Context, slugName string) ([]byte, error) {
+	provider, err := ss.getEnabledProvider(ctx, slugName, entity.SSOProtocolSAML)
+	if err != nil {
+		return nil, err
+	}
+	siteURL, err := ss.siteURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ss.serviceProvider(siteURL, provider)
+	if err != nil {
+		return nil, err
+	}
+	return sp.Metadata(), nil
+}
+
+func wrapLoginError(provider *entity.SSOProvider, err error) error {
+	return fmt.Errorf("single sign-on with %s failed: %w", provider.SlugName, err)
+}
+
+// ssoLogoSVG the key icon of the single sign-on shown with the connectors
+const ssoLogoSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" fill="currentColor" viewBox="0 0 16 16">` +
+	`<path d="M3.5 11.5a3.5 3.5 0 1 1 3.163-5H14L15.5 8 14 9.5l-1-1-1 1-1-1-1 1-1-1-1 1H6.663a3.5 3.5 0 0 1-3.163 2M2.5 9a1 1 0 1 0 0-2 1 1 0 0 0 0 2"/></svg>`
+
+// ConnectorsInfo the enabled providers shown with the connectors on the login page
+func (ss *SSOService) ConnectorsInfo(ctx context.Context, siteURL string) (resp []*schema.ConnectorInfoResp, err error) {
+	providers, err := ss.ssoProviderRepo.GetSSOProviderList(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, provider := range providers {
+		resp = append(resp, &schema.ConnectorInfoResp{
+			Name: provider.Name,
+			Icon: ssoLogoSVG,
+			Link: ss.LoginURL(siteURL, provider),
+		})
+	}
+	return resp, nil
+}
+
+// ConnectorsUserInfo the enabled providers shown with the connectors in the account settings,
+// the user binds the provider by the link with the state of the binding intent
+func (ss *SSOService) ConnectorsUserInfo(ctx context.Context, siteURL, userID string,
+	userExternalLoginMapping map[string]string) (resp []*schema.ConnectorUserInfoResp, err error) {
+	providers, err := ss.ssoProviderRepo.GetSSOProviderList(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, provider := range providers {
+		externalID := userExternalLoginMapping[provider.ExternalLoginProvider()]
+		link := ss.LoginURL(siteURL, provider)
+		if len(externalID) == 0 {
+			state, err := ss.userExternalLoginService.GenerateOAuthState(ctx, provider.ExternalLoginProvider(),
+				schema.ExternalLoginOAuthStateBindIntent, userID)
+			if err != nil {
+				return nil, err
+			}
+			link = fmt.Sprintf("%s?state=%s", link, url.QueryEscape(state))
+		}
+		resp = append(resp, &schema.ConnectorUserInfoResp{
+			Name:       provider.Name,
+			Icon:       ssoLogoSVG,
+			Link:       link,
+			Binding:    len(externalID) > 0,
+			ExternalID: externalID,
+		})
+	}
+	return resp, nil
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/apache/answer/internal/service/revision_common"
 	"github.com/apache/answer/internal/service/siteinfo_common"
 	tagcommonser "github.com/apache/answer/internal/service/tag_common"
+	usercommon "github.com/apache/answer/internal/service/user_common"
 	"github.com/apache/answer/pkg/htmltext"
 	"github.com/jinzhu/copier"
 
@@ -51,6 +52,7 @@ type TagService struct {
 	followCommon         activity_common.FollowRepo
 	siteInfoService      siteinfo_common.SiteInfoCommonService
 	activityQueueService activityqueue.Service
+	userCommon           *usercommon.UserCommon
 }
 
 // NewTagService new tag service
@@ -61,6 +63,7 @@ func NewTagService(
 	followCommon activity_common.FollowRepo,
 	siteInfoService siteinfo_common.SiteInfoCommonService,
 	activityQueueService activityqueue.Service,
+	userCommon *usercommon.UserCommon,
 ) *TagService {
 	return &TagService{
 		tagRepo:              tagRepo,
@@ -69,6 +72,7 @@ func NewTagService(
 		followCommon:         followCommon,
 		siteInfoService:      siteInfoService,
 		activityQueueService: activityQueueService,
+		userCommon:           userCommon,
 	}
 }
 
@@ -159,6 +163,13 @@ func (ts *TagService) GetTagInfo(ctx context.Context, req *schema.GetTagInfoReq)
 	if !exist {
 		return nil, errors.NotFound(reason.TagNotFound)
 	}
+	canView, err := ts.tagCommonService.CanViewTag(ctx, tagInfo, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.NotFound(reason.TagNotFound)
+	}
 
 	resp = &schema.GetTagResp{}
 	// if tag is synonyms get original tag info
@@ -186,6 +197,20 @@ func (ts *TagService) GetTagInfo(ctx context.Context, req *schema.GetTagInfoReq)
 	resp.Reserved = tagInfo.Reserved
 	resp.IsFollower = ts.checkTagIsFollow(ctx, req.UserID, tagInfo.ID)
 	resp.Status = entity.TagStatusDisplayMapping[tagInfo.Status]
+	resp.ParentTag, resp.ChildTags, err = ts.tagCommonService.GetTagParentAndChildren(ctx, tagInfo)
+	if err != nil {
+		return nil, err
+	}
+	resp.Owners, err = ts.GetTagOwners(ctx, &schema.GetTagOwnersReq{TagID: tagInfo.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, owner := range resp.Owners {
+		if owner.ID == req.UserID {
+			resp.IsOwner = true
+			req.CanEdit = true
+		}
+	}
 	resp.MemberActions = permission.GetTagPermission(ctx, tagInfo.Status, req.CanEdit, req.CanDelete, req.CanMerge, req.CanRecover)
 	resp.GetExcerpt()
 	return resp, nil
@@ -404,6 +429,15 @@ func (ts *TagService) GetTagWithPage(ctx context.Context, req *schema.GetTagWith
 	tag := &entity.Tag{}
 	_ = copier.Copy(tag, req)
 	tag.UserID = ""
+	if req.SpaceID != entity.PublicSpaceID {
+		canView, err := ts.tagCommonService.CanViewTag(ctx, tag, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			return nil, errors.NotFound(reason.SpaceNotFound)
+		}
+	}
 
 	page := req.Page
 	pageSize := req.PageSize
@@ -505,3 +539,70 @@ func (ts *TagService) checkTagIsFollow(ctx context.Context, userID, tagID string
 	}
 	return followed
 }
+
+// UpdateTagParent update the parent of the tag
+func (ts *TagService) UpdateTagParent(ctx context.Context, req *schema.UpdateTagParentReq) (err error) {
+	return ts.tagCommonService.UpdateTagParent(ctx, req)
+}
+
+// GetTagOwners get the owners of the tag
+func (ts *TagService) GetTagOwners(ctx context.Context, req *schema.GetTagOwnersReq) (
+	resp []*schema.UserBasicInfo, err error) {
+	resp = make([]*schema.UserBasicInfo, 0)
+	userIDs, err := ts.tagCommonService.GetTagOwnerIDs(ctx, req.TagID)
+	if err != nil {
+		return nil, err
+	}
+	if len(userIDs) == 0 {
+		return resp, nil
+	}
+	userInfoMapping, err := ts.userCommon.BatchUserBasicInfoByID(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		if userInfo, ok := userInfoMapping[userID]; ok {
+			resp = append(resp, userInfo)
+		}
+	}
+	return resp, nil
+}
+
+// UpdateTagOwners replace the owners of the tag by usernames
+func (ts *TagService) UpdateTagOwners(ctx context.Context, req *schema.UpdateTagOwnersReq) (err error) {
+	usernames := converter.UniqueArray(req.Usernames)
+	if len(usernames) > tagcommonser.MaxTagOwnerCount {
+		return errors.BadRequest(reason.TagOwnerLimitExceeded)
+	}
+	userIDs := make([]string, 0, len(usernames))
+	if len(usernames) > 0 {
+		userInfoMapping, err := ts.userCommon.BatchGetUserBasicInfoByUserNames(ctx, usernames)
+		if err != nil {
+			return err
+		}
+		for _, username := range usernames {
+			userInfo, ok := userInfoMapping[username]
+			if !ok {
+				return errors.BadRequest(reason.UserNotFound)
+			}
+			userIDs = append(userIDs, userInfo.ID)
+		}
+	}
+	return ts.tagCommonService.ReplaceTagOwners(ctx, req.TagID, userIDs)
+}
+
+// GetTagSetting get the setting of the tag
+func (ts *TagService) GetTagSetting(ctx context.Context, req *schema.GetTagSettingReq) (
+	resp *schema.GetTagSettingResp, err error) {
+	return ts.tagCommonService.GetTagSetting(ctx, req.TagID)
+}
+
+// UpdateTagSetting update the setting of the tag
+func (ts *TagService) UpdateTagSetting(ctx context.Context, req *schema.UpdateTagSettingReq) (err error) {
+	return ts.tagCommonService.SaveTagSetting(ctx, req)
+}
+
+// IsTagOwner check whether the user is the owner of the tag
+func (ts *TagService) IsTagOwner(ctx context.Context, tagID, userID string) (isOwner bool, err error) {
+	return ts.tagCommonService.IsTagOwner(ctx, tagID, userID)
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/apache/answer/internal/service/activityqueue"
 	"github.com/apache/answer/internal/service/revision_common"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/space_common"
 	"github.com/apache/answer/pkg/converter"
 	"github.com/segmentfault/pacman/errors"
 	"github.com/segmentfault/pacman/log"
@@ -64,6 +65,19 @@ type TagRepo interface {
 	GetTagSynonymCount(ctx context.Context, tagID string) (count int64, err error)
 	GetIDsByMainTagId(ctx context.Context, mainTagID string) (tagIDs []string, err error)
 	GetTagList(ctx context.Context, tag *entity.Tag) (tagList []*entity.Tag, err error)
+	GetTagListByParentIDs(ctx context.Context, parentTagIDs []string) (tagList []*entity.Tag, err error)
+	UpdateTagParent(ctx context.Context, tagID string, parentTagID int64) (err error)
+}
+
+type TagOwnerRepo interface {
+	GetTagOwnerList(ctx context.Context, tagIDs []string) (owners []*entity.TagOwner, err error)
+	IsTagOwner(ctx context.Context, tagID, userID string) (isOwner bool, err error)
+	ReplaceTagOwners(ctx context.Context, tagID string, userIDs []string) (err error)
+}
+
+type TagSettingRepo interface {
+	GetTagSettingList(ctx context.Context, tagIDs []string) (settings []*entity.TagSetting, err error)
+	SaveTagSetting(ctx context.Context, setting *entity.TagSetting) (err error)
 }
 
 type TagRelRepo interface {
@@ -88,8 +102,11 @@ type TagCommonService struct {
 	tagCommonRepo        TagCommonRepo
 	tagRelRepo           TagRelRepo
 	tagRepo              TagRepo
+	tagOwnerRepo         TagOwnerRepo
+	tagSettingRepo       TagSettingRepo
 	siteInfoService      siteinfo_common.SiteInfoCommonService
 	activityQueueService activityqueue.Service
+	spaceCommon          *space_common.SpaceCommon
 }
 
 // NewTagCommonService new tag service
@@ -97,17 +114,23 @@ func NewTagCommonService(
 	tagCommonRepo TagCommonRepo,
 	tagRelRepo TagRelRepo,
 	tagRepo TagRepo,
+	tagOwnerRepo TagOwnerRepo,
+	tagSettingRepo TagSettingRepo,
 	revisionService *revision_common.RevisionService,
 	siteInfoService siteinfo_common.SiteInfoCommonService,
 	activityQueueService activityqueue.Service,
+	spaceCommon *space_common.SpaceCommon,
 ) *TagCommonService {
 	return &TagCommonService{
 		tagCommonRepo:        tagCommonRepo,
 		tagRelRepo:           tagRelRepo,
 		tagRepo:              tagRepo,
+		tagOwnerRepo:         tagOwnerRepo,
+		tagSettingRepo:       tagSettingRepo,
 		revisionService:      revisionService,
 		siteInfoService:      siteInfoService,
 		activityQueueService: activityQueueService,
+		spaceCommon:          spaceCommon,
 	}
 }
 
@@ -117,6 +140,10 @@ func (ts *TagCommonService) SearchTagLike(ctx context.Context, req *schema.Searc
 	if err != nil {
 		return
 	}
+	tags, err = ts.filterSpaceTags(ctx, tags, req.SpaceID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
 	ts.TagsFormatRecommendAndReserved(ctx, tags)
 	mainTagId := make([]string, 0)
 	for _, tag := range tags {
@@ -703,6 +730,7 @@ func (ts *TagCommonService) ObjectChangeTag(ctx context.Context, objectTagData *
 		item.ParsedText = tag.ParsedText
 		item.Status = entity.TagStatusAvailable
 		item.UserID = objectTagData.UserID
+		item.SpaceID = objectTagData.SpaceID
 		addTagList = append(addTagList, item)
 	}
 
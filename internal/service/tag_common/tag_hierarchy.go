@@ -0,0 +1,180 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tag_common
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/pkg/converter"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// maxTagHierarchyDepth the max depth of the tag hierarchy, the root tag is at depth 1
+const maxTagHierarchyDepth = 5
+
+// GetTagFamilyIDs get the ids of the tag, its synonyms and all its descendant tags with their synonyms,
+// so the questions of the child tags can also be matched by the parent tag.
+func (ts *TagCommonService) GetTagFamilyIDs(ctx context.Context, tag *entity.Tag) (tagIDs []string, err error) {
+	rootID := tag.ID
+	if tag.MainTagID > 0 {
+		rootID = converter.IntToString(tag.MainTagID)
+	}
+	tagIDs = []string{tag.ID}
+	visited := map[string]bool{}
+	level := []string{rootID}
+	for depth := 0; depth < maxTagHierarchyDepth && len(level) > 0; depth++ {
+		next := make([]string, 0)
+		for _, id := range level {
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			tagIDs = append(tagIDs, id)
+			synIDs, err := ts.tagRepo.GetIDsByMainTagId(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			tagIDs = append(tagIDs, synIDs...)
+			next = append(next, id)
+		}
+		if len(next) == 0 {
+			break
+		}
+		children, err := ts.tagRepo.GetTagListByParentIDs(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		level = make([]string, 0, len(children))
+		for _, child := range children {
+			level = append(level, child.ID)
+		}
+	}
+	return converter.UniqueArray(tagIDs), nil
+}
+
+// GetTagParentAndChildren get the parent tag and the child tags of the tag
+func (ts *TagCommonService) GetTagParentAndChildren(ctx context.Context, tag *entity.Tag) (
+	parent *schema.GetTagBasicResp, children []*schema.GetTagBasicResp, err error) {
+	children = make([]*schema.GetTagBasicResp, 0)
+	if tag.ParentTagID > 0 {
+		parentTag, exist, err := ts.GetTagByID(ctx, converter.IntToString(tag.ParentTagID))
+		if err != nil {
+			return nil, nil, err
+		}
+		if exist {
+			parent = convertTagBasicResp(parentTag)
+		}
+	}
+	childTags, err := ts.tagRepo.GetTagListByParentIDs(ctx, []string{tag.ID})
+	if err != nil {
+		return nil, nil, err
+	}
+	ts.TagsFormatRecommendAndReserved(ctx, childTags)
+	for _, child := range childTags {
+		children = append(children, convertTagBasicResp(child))
+	}
+	return parent, children, nil
+}
+
+// UpdateTagParent set the parent of the tag, the synonym tag can not be in the hierarchy
+// and the parent can not be the tag itself or one of its descendants.
+func (ts *TagCommonService) UpdateTagParent(ctx context.Context, req *schema.UpdateTagParentReq) (err error) {
+	tag, exist, err := ts.GetTagByID(ctx, req.TagID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.TagNotFound)
+	}
+	if tag.MainTagID > 0 {
+		return errors.BadRequest(reason.TagParentInvalid)
+	}
+	if len(req.ParentSlugName) == 0 {
+		return ts.tagRepo.UpdateTagParent(ctx, tag.ID, 0)
+	}
+
+	parent, exist, err := ts.GetTagBySlugName(ctx, req.ParentSlugName)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.TagNotFound)
+	}
+	if parent.MainTagID > 0 || parent.ID == tag.ID {
+		return errors.BadRequest(reason.TagParentInvalid)
+	}
+
+	// walk up from the parent, the tag itself must not be an ancestor of the parent
+	depth := 1
+	for ancestor := parent; ancestor.ParentTagID > 0; depth++ {
+		ancestorID := converter.IntToString(ancestor.ParentTagID)
+		if ancestorID == tag.ID {
+			return errors.BadRequest(reason.TagParentInvalid)
+		}
+		if depth >= maxTagHierarchyDepth {
+			return errors.BadRequest(reason.TagHierarchyTooDeep)
+		}
+		ancestor, exist, err = ts.tagCommonRepo.GetTagByID(ctx, ancestorID, true)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			break
+		}
+	}
+	subtreeDepth, err := ts.getSubtreeDepth(ctx, tag.ID)
+	if err != nil {
+		return err
+	}
+	if depth+subtreeDepth > maxTagHierarchyDepth {
+		return errors.BadRequest(reason.TagHierarchyTooDeep)
+	}
+	return ts.tagRepo.UpdateTagParent(ctx, tag.ID, converter.StringToInt64(parent.ID))
+}
+
+// getSubtreeDepth get the depth of the subtree whose root is the tag, a tag without children is 1
+func (ts *TagCommonService) getSubtreeDepth(ctx context.Context, tagID string) (depth int, err error) {
+	level := []string{tagID}
+	for len(level) > 0 && depth <= maxTagHierarchyDepth {
+		depth++
+		children, err := ts.tagRepo.GetTagListByParentIDs(ctx, level)
+		if err != nil {
+			return 0, err
+		}
+		level = make([]string, 0, len(children))
+		for _, child := range children {
+			level = append(level, child.ID)
+		}
+	}
+	return depth, nil
+}
+
+func convertTagBasicResp(tag *entity.Tag) *schema.GetTagBasicResp {
+	return &schema.GetTagBasicResp{
+		TagID:       tag.ID,
+		SlugName:    tag.SlugName,
+		DisplayName: tag.DisplayName,
+		Recommend:   tag.Recommend,
+		Reserved:    tag.Reserved,
+	}
+}
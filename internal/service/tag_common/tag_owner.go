@@ -0,0 +1,231 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tag_common
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/base/validator"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/pkg/converter"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// MaxTagOwnerCount the max count of the owners of a tag
+const MaxTagOwnerCount = 20
+
+// GetTagOwnerIDs get the user ids of the owners of the tag
+func (ts *TagCommonService) GetTagOwnerIDs(ctx context.Context, tagID string) (userIDs []string, err error) {
+	owners, err := ts.tagOwnerRepo.GetTagOwnerList(ctx, []string{tagID})
+	if err != nil {
+		return nil, err
+	}
+	userIDs = make([]string, 0, len(owners))
+	for _, owner := range owners {
+		userIDs = append(userIDs, owner.UserID)
+	}
+	return userIDs, nil
+}
+
+// GetTagOwnerIDsByTagIDs get the user ids of the owners of all the tags
+func (ts *TagCommonService) GetTagOwnerIDsByTagIDs(ctx context.Context, tagIDs []string) (userIDs []string, err error) {
+	if len(tagIDs) == 0 {
+		return []string{}, nil
+	}
+	owners, err := ts.tagOwnerRepo.GetTagOwnerList(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	userIDs = make([]string, 0, len(owners))
+	for _, owner := range owners {
+		userIDs = append(userIDs, owner.UserID)
+	}
+	return converter.UniqueArray(userIDs), nil
+}
+
+// IsTagOwner check whether the user is the owner of the tag, the owners of the main tag also own its synonyms
+func (ts *TagCommonService) IsTagOwner(ctx context.Context, tagID, userID string) (isOwner bool, err error) {
+	if len(userID) == 0 || len(tagID) == 0 {
+		return false, nil
+	}
+	tag, exist, err := ts.tagCommonRepo.GetTagByID(ctx, tagID, true)
+	if err != nil || !exist {
+		return false, err
+	}
+	if tag.MainTagID > 0 {
+		tagID = converter.IntToString(tag.MainTagID)
+	}
+	return ts.tagOwnerRepo.IsTagOwner(ctx, tagID, userID)
+}
+
+// ReplaceTagOwners replace the owners of the tag, the synonym tag can not have owners
+func (ts *TagCommonService) ReplaceTagOwners(ctx context.Context, tagID string, userIDs []string) (err error) {
+	userIDs = converter.UniqueArray(userIDs)
+	if len(userIDs) > MaxTagOwnerCount {
+		return errors.BadRequest(reason.TagOwnerLimitExceeded)
+	}
+	tag, exist, err := ts.GetTagByID(ctx, tagID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.TagNotFound)
+	}
+	if tag.MainTagID > 0 {
+		return errors.BadRequest(reason.TagCannotUpdate)
+	}
+	return ts.tagOwnerRepo.ReplaceTagOwners(ctx, tag.ID, userIDs)
+}
+
+// GetTagSetting get the setting of the tag, the synonym tag uses the setting of the main tag
+func (ts *TagCommonService) GetTagSetting(ctx context.Context, tagID string) (resp *schema.GetTagSettingResp, err error) {
+	tag, exist, err := ts.GetTagByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.NotFound(reason.TagNotFound)
+	}
+	if tag.MainTagID > 0 {
+		tagID = converter.IntToString(tag.MainTagID)
+	}
+	resp = &schema.GetTagSettingResp{TagID: tag.ID, CompanionTags: make([]string, 0)}
+	settings, err := ts.tagSettingRepo.GetTagSettingList(ctx, []string{tagID})
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) > 0 {
+		resp.QuestionTemplate = settings[0].QuestionTemplate
+		resp.CompanionTags = parseCompanionTags(settings[0].CompanionTags)
+	}
+	return resp, nil
+}
+
+// SaveTagSetting save the setting of the tag, the companion tags must exist
+func (ts *TagCommonService) SaveTagSetting(ctx context.Context, req *schema.UpdateTagSettingReq) (err error) {
+	tag, exist, err := ts.GetTagByID(ctx, req.TagID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.TagNotFound)
+	}
+	if tag.MainTagID > 0 {
+		return errors.BadRequest(reason.TagCannotUpdate)
+	}
+	companionTags := converter.UniqueArray(req.CompanionTags)
+	if len(companionTags) > 0 {
+		tagList, err := ts.tagCommonRepo.GetTagListByNames(ctx, companionTags)
+		if err != nil {
+			return err
+		}
+		if len(tagList) != len(companionTags) {
+			return errors.BadRequest(reason.TagNotFound)
+		}
+		for _, t := range tagList {
+			if t.MainTagID > 0 || t.ID == tag.ID {
+				return errors.BadRequest(reason.TagNotContainSynonym)
+			}
+		}
+	}
+	companionTagsJSON, _ := json.Marshal(companionTags)
+	return ts.tagSettingRepo.SaveTagSetting(ctx, &entity.TagSetting{
+		TagID:            tag.ID,
+		QuestionTemplate: req.QuestionTemplate,
+		CompanionTags:    string(companionTagsJSON),
+	})
+}
+
+// CheckCompanionTags check that the tags which require companion tags are used together with one of them
+func (ts *TagCommonService) CheckCompanionTags(ctx context.Context, tagNames []string) (
+	errorList []*validator.FormErrorField, err error) {
+	if len(tagNames) == 0 {
+		return nil, nil
+	}
+	tagList, err := ts.tagCommonRepo.GetTagListByNames(ctx, tagNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(tagList) == 0 {
+		return nil, nil
+	}
+	tagMapping := make(map[string]*entity.Tag, len(tagList))
+	tagIDs := make([]string, 0, len(tagList))
+	for _, tag := range tagList {
+		tagMapping[tag.ID] = tag
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	settings, err := ts.tagSettingRepo.GetTagSettingList(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	usedTags := make(map[string]bool, len(tagNames))
+	for _, name := range tagNames {
+		usedTags[strings.ToLower(name)] = true
+	}
+	for _, setting := range settings {
+		companions := parseCompanionTags(setting.CompanionTags)
+		if len(companions) == 0 {
+			continue
+		}
+		found := false
+		for _, companion := range companions {
+			if usedTags[companion] {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		tag := tagMapping[setting.TagID]
+		if tag == nil {
+			continue
+		}
+		errMsg := translator.TrWithData(handler.GetLangByCtx(ctx), reason.TagCompanionRequired, map[string]any{
+			"Tag":        tag.SlugName,
+			"Companions": strings.Join(companions, ", "),
+		})
+		errorList = append(errorList, &validator.FormErrorField{
+			ErrorField: "tags",
+			ErrorMsg:   errMsg,
+		})
+		return errorList, errors.BadRequest(reason.TagCompanionRequired).WithMsg(errMsg)
+	}
+	return nil, nil
+}
+
+func parseCompanionTags(companionTags string) (tags []string) {
+	tags = make([]string, 0)
+	if len(companionTags) == 0 {
+		return tags
+	}
+	if err := json.Unmarshal([]byte(companionTags), &tags); err != nil {
+		log.Errorf("parse companion tags failed: %v", err)
+	}
+	return tags
+}
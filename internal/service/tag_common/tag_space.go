@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tag_common
+
+import (
+	"context"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/base/validator"
+	"github.com/apache/answer/internal/entity"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// CheckSpaceTags check that the question does not use the tags of another space,
+// the public tags can be used in every space
+func (ts *TagCommonService) CheckSpaceTags(ctx context.Context, spaceID int, tagNames []string) (
+	errorList []*validator.FormErrorField, err error) {
+	if len(tagNames) == 0 {
+		return nil, nil
+	}
+	tagList, err := ts.tagCommonRepo.GetTagListByNames(ctx, tagNames)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tagList {
+		if tag.SpaceID == entity.PublicSpaceID || tag.SpaceID == spaceID {
+			continue
+		}
+		errMsg := translator.TrWithData(handler.GetLangByCtx(ctx), reason.TagNotInSpace, map[string]any{
+			"Tag": tag.SlugName,
+		})
+		errorList = append(errorList, &validator.FormErrorField{
+			ErrorField: "tags",
+			ErrorMsg:   errMsg,
+		})
+		return errorList, errors.BadRequest(reason.TagNotInSpace).WithMsg(errMsg)
+	}
+	return nil, nil
+}
+
+// CanViewTag whether the user can read the tag, the tags of the space are only visible to its readers
+func (ts *TagCommonService) CanViewTag(ctx context.Context, tag *entity.Tag, userID string) (bool, error) {
+	return ts.spaceCommon.CanViewSpace(ctx, tag.SpaceID, userID)
+}
+
+// filterSpaceTags keep the public tags and the tags of the space which the user can read
+func (ts *TagCommonService) filterSpaceTags(ctx context.Context, tags []*entity.Tag, spaceID int, userID string) (
+	[]*entity.Tag, error) {
+	if spaceID != entity.PublicSpaceID {
+		canView, err := ts.spaceCommon.CanViewSpace(ctx, spaceID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			spaceID = entity.PublicSpaceID
+		}
+	}
+	filtered := make([]*entity.Tag, 0, len(tags))
+	for _, tag := range tags {
+		if tag.SpaceID == entity.PublicSpaceID || tag.SpaceID == spaceID {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered, nil
+}
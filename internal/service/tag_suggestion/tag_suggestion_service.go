@@ -0,0 +1,371 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tag_suggestion
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"sync/atomic"
+
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/embedding"
+	"github.com/apache/answer/internal/service/tag_common"
+	"github.com/apache/answer/pkg/converter"
+	"github.com/apache/answer/pkg/htmltext"
+	"github.com/apache/answer/plugin"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	// trainingPageSize the count of the questions read at once when training
+	trainingPageSize = 200
+	// minStatCount the term and the term tag pair which appear in fewer questions are dropped
+	minStatCount = 2
+	// similarQuestionCount the count of the similar questions found by the vector search
+	similarQuestionCount = 20
+	// keywordWeight the weight of the term co-occurrence score, the rest is the weight of the vector similarity score
+	keywordWeight = 0.6
+)
+
+// TagSuggestionRepo tag suggestion repository
+type TagSuggestionRepo interface {
+	GetTrainingQuestions(ctx context.Context, afterID string, limit int) (questions []*entity.Question, err error)
+	GetTagTermStatList(ctx context.Context, terms []string) (stats []*entity.TagTermStat, err error)
+	ReplaceTagTermStats(ctx context.Context, stats []*entity.TagTermStat) (err error)
+}
+
+// TagSuggestionService suggest tags from the draft question by the term co-occurrence of the tagged questions,
+// and the tags of the similar questions found by the vector search plugin if it is enabled.
+type TagSuggestionService struct {
+	tagSuggestionRepo TagSuggestionRepo
+	tagCommonService  *tag_common.TagCommonService
+	embeddingService  *embedding.EmbeddingService
+	training          atomic.Bool
+}
+
+// NewTagSuggestionService new tag suggestion service
+func NewTagSuggestionService(
+	tagSuggestionRepo TagSuggestionRepo,
+	tagCommonService *tag_common.TagCommonService,
+	embeddingService *embedding.EmbeddingService,
+) *TagSuggestionService {
+	return &TagSuggestionService{
+		tagSuggestionRepo: tagSuggestionRepo,
+		tagCommonService:  tagCommonService,
+		embeddingService:  embeddingService,
+	}
+}
+
+// SuggestTags suggest tags for the draft question
+func (s *TagSuggestionService) SuggestTags(ctx context.Context, req *schema.SuggestTagsReq) (
+	resp []*schema.GetTagBasicResp, err error) {
+	resp = make([]*schema.GetTagBasicResp, 0)
+	text := req.Title + "\n" + htmltext.ClearText(converter.Markdown2HTML(req.Content))
+	terms := extractTerms(text)
+	if len(terms) == 0 {
+		return resp, nil
+	}
+
+	keywordScores, err := s.getTermScores(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
+	similarScores := s.getSimilarScores(ctx, text)
+	scores := make(map[string]float64)
+	weight := keywordWeight
+	if len(similarScores) == 0 {
+		weight = 1
+	}
+	for tagID, score := range normalizeScores(keywordScores) {
+		scores[tagID] += score * weight
+	}
+	for tagID, score := range normalizeScores(similarScores) {
+		scores[tagID] += score * (1 - weight)
+	}
+	if len(scores) == 0 {
+		return resp, nil
+	}
+
+	tags, err := s.mergeSynonymScores(ctx, scores)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool, len(req.Tags))
+	for _, slugName := range req.Tags {
+		selected[slugName] = true
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return scores[tags[i].ID] > scores[tags[j].ID]
+	})
+	for _, tag := range tags {
+		if selected[tag.SlugName] {
+			continue
+		}
+		resp = append(resp, &schema.GetTagBasicResp{
+			TagID:       tag.ID,
+			SlugName:    tag.SlugName,
+			DisplayName: tag.DisplayName,
+			Recommend:   tag.Recommend,
+			Reserved:    tag.Reserved,
+		})
+		if len(resp) >= req.Size {
+			break
+		}
+	}
+	return resp, nil
+}
+
+// getTermScores score the tags by the terms, each term votes for the tags by the probability
+// of the tag in the questions containing the term, weighted by the inverse document frequency of the term.
+func (s *TagSuggestionService) getTermScores(ctx context.Context, terms []string) (
+	scores map[string]float64, err error) {
+	scores = make(map[string]float64)
+	stats, err := s.tagSuggestionRepo.GetTagTermStatList(ctx, append(terms, ""))
+	if err != nil {
+		return nil, err
+	}
+	total := 0
+	termCount := make(map[string]int)
+	for _, stat := range stats {
+		if stat.TagID != "0" {
+			continue
+		}
+		if len(stat.Term) == 0 {
+			total = stat.Count
+		} else {
+			termCount[stat.Term] = stat.Count
+		}
+	}
+	if total == 0 {
+		return scores, nil
+	}
+	for _, stat := range stats {
+		count := termCount[stat.Term]
+		if stat.TagID == "0" || count == 0 {
+			continue
+		}
+		idf := math.Log(1 + float64(total)/float64(count))
+		scores[stat.TagID] += float64(stat.Count) / float64(count) * idf
+	}
+	return scores, nil
+}
+
+// getSimilarScores score the tags of the similar questions by the similarity,
+// it returns nothing if the vector search plugin is not enabled.
+func (s *TagSuggestionService) getSimilarScores(ctx context.Context, text string) (scores map[string]float64) {
+	scores = make(map[string]float64)
+	results, err := s.embeddingService.SearchSimilar(ctx, text, similarQuestionCount)
+	if err != nil {
+		log.Debugf("tag suggestion skip the vector search: %v", err)
+		return scores
+	}
+	questionScores := make(map[string]float64)
+	for _, result := range results {
+		questionID := result.ObjectID
+		if result.ObjectType != "question" {
+			meta := &plugin.VectorSearchMetadata{}
+			if err := json.Unmarshal([]byte(result.Metadata), meta); err != nil || len(meta.QuestionID) == 0 {
+				continue
+			}
+			questionID = meta.QuestionID
+		}
+		questionScores[questionID] = math.Max(questionScores[questionID], result.Score)
+	}
+	questionIDs := make([]string, 0, len(questionScores))
+	for questionID := range questionScores {
+		questionIDs = append(questionIDs, questionID)
+	}
+	objectTags, err := s.tagCommonService.BatchGetObjectTag(ctx, questionIDs)
+	if err != nil {
+		log.Errorf("get the tags of the similar questions failed: %v", err)
+		return scores
+	}
+	for questionID, tags := range objectTags {
+		for _, tag := range tags {
+			scores[tag.ID] += questionScores[questionID]
+		}
+	}
+	return scores
+}
+
+// mergeSynonymScores move the scores of the synonyms to their main tags and return the available tags
+func (s *TagSuggestionService) mergeSynonymScores(ctx context.Context, scores map[string]float64) (
+	tags []*entity.Tag, err error) {
+	tagIDs := make([]string, 0, len(scores))
+	for tagID := range scores {
+		tagIDs = append(tagIDs, tagID)
+	}
+	tagList, err := s.tagCommonService.GetTagListByIDs(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	tagMapping := make(map[string]*entity.Tag, len(tagList))
+	missingMainTagIDs := make([]string, 0)
+	for _, tag := range tagList {
+		if tag.MainTagID == 0 {
+			tagMapping[tag.ID] = tag
+			continue
+		}
+		mainTagID := converter.IntToString(tag.MainTagID)
+		if _, ok := scores[mainTagID]; !ok {
+			missingMainTagIDs = append(missingMainTagIDs, mainTagID)
+		}
+		scores[mainTagID] += scores[tag.ID]
+	}
+	if len(missingMainTagIDs) > 0 {
+		mainTagList, err := s.tagCommonService.GetTagListByIDs(ctx, missingMainTagIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range mainTagList {
+			tagMapping[tag.ID] = tag
+		}
+	}
+	tags = make([]*entity.Tag, 0, len(tagMapping))
+	for _, tag := range tagMapping {
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// Retrain rebuild the term statistics in the background
+func (s *TagSuggestionService) Retrain(ctx context.Context) (err error) {
+	if !s.training.CompareAndSwap(false, true) {
+		return errors.BadRequest(reason.TagSuggestionTraining)
+	}
+	// the request context is done soon, only the tenant is kept for the training
+	trainCtx := handler.WithTenantID(context.Background(), handler.GetTenantID(ctx))
+	go func() {
+		defer s.training.Store(false)
+		if err := s.train(trainCtx); err != nil {
+			log.Errorf("train tag suggestion failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// RetrainCron rebuild the term statistics, it is skipped if the training is running
+func (s *TagSuggestionService) RetrainCron(ctx context.Context) {
+	if !s.training.CompareAndSwap(false, true) {
+		return
+	}
+	defer s.training.Store(false)
+	if err := s.train(ctx); err != nil {
+		log.Errorf("train tag suggestion failed: %v", err)
+	}
+}
+
+// GetStatus get the status of the tag suggestion model
+func (s *TagSuggestionService) GetStatus(ctx context.Context) (resp *schema.GetTagSuggestionStatusResp, err error) {
+	resp = &schema.GetTagSuggestionStatusResp{Training: s.training.Load()}
+	stats, err := s.tagSuggestionRepo.GetTagTermStatList(ctx, []string{""})
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		if stat.TagID == "0" {
+			resp.QuestionCount = stat.Count
+		}
+	}
+	return resp, nil
+}
+
+// train count the terms and the term tag pairs of all the tagged questions
+func (s *TagSuggestionService) train(ctx context.Context) (err error) {
+	log.Infof("tag suggestion training start")
+	total := 0
+	termCount := make(map[string]int)
+	termTagCount := make(map[string]map[string]int)
+	afterID := ""
+	for {
+		questions, err := s.tagSuggestionRepo.GetTrainingQuestions(ctx, afterID, trainingPageSize)
+		if err != nil {
+			return err
+		}
+		if len(questions) == 0 {
+			break
+		}
+		questionIDs := make([]string, 0, len(questions))
+		for _, question := range questions {
+			questionIDs = append(questionIDs, question.ID)
+		}
+		objectTags, err := s.tagCommonService.BatchGetObjectTag(ctx, questionIDs)
+		if err != nil {
+			return err
+		}
+		for _, question := range questions {
+			tags := objectTags[question.ID]
+			if len(tags) == 0 {
+				continue
+			}
+			total++
+			for _, term := range extractTerms(question.Title + "\n" + htmltext.ClearText(question.ParsedText)) {
+				termCount[term]++
+				if termTagCount[term] == nil {
+					termTagCount[term] = make(map[string]int)
+				}
+				for _, tag := range tags {
+					termTagCount[term][tag.ID]++
+				}
+			}
+		}
+		afterID = questions[len(questions)-1].ID
+	}
+
+	stats := []*entity.TagTermStat{{Term: "", TagID: "0", Count: total}}
+	for term, count := range termCount {
+		if count < minStatCount {
+			continue
+		}
+		stats = append(stats, &entity.TagTermStat{Term: term, TagID: "0", Count: count})
+		for tagID, tagCount := range termTagCount[term] {
+			if tagCount >= minStatCount {
+				stats = append(stats, &entity.TagTermStat{Term: term, TagID: tagID, Count: tagCount})
+			}
+		}
+	}
+	if err = s.tagSuggestionRepo.ReplaceTagTermStats(ctx, stats); err != nil {
+		return err
+	}
+	log.Infof("tag suggestion training finished, %d questions, %d stats", total, len(stats))
+	return nil
+}
+
+// normalizeScores scale the scores so that the max score is 1
+func normalizeScores(scores map[string]float64) map[string]float64 {
+	maxScore := 0.0
+	for _, score := range scores {
+		maxScore = math.Max(maxScore, score)
+	}
+	if maxScore == 0 {
+		return scores
+	}
+	normalized := make(map[string]float64, len(scores))
+	for tagID, score := range scores {
+		normalized[tagID] = score / maxScore
+	}
+	return normalized
+}
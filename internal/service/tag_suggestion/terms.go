@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tag_suggestion
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxTermLength the max length of the term in bytes, it is the length of the term column
+	maxTermLength = 64
+	// maxTermsPerText the max count of the distinct terms extracted from a text
+	maxTermsPerText = 300
+)
+
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "but": true,
+	"by": true, "can": true, "do": true, "does": true, "for": true, "from": true, "get": true,
+	"has": true, "have": true, "how": true, "i": true, "if": true, "in": true, "is": true, "it": true,
+	"its": true, "me": true, "my": true, "no": true, "not": true, "of": true, "on": true, "or": true,
+	"so": true, "that": true, "the": true, "this": true, "to": true, "use": true, "using": true,
+	"was": true, "we": true, "what": true, "when": true, "where": true, "which": true, "why": true,
+	"will": true, "with": true, "you": true, "your": true,
+}
+
+// extractTerms split the text into distinct lowercase terms, the symbols used by the tag names
+// like c++, c#, node.js and vue-router are kept, the stopwords and the numbers are dropped.
+func extractTerms(text string) (terms []string) {
+	terms = make([]string, 0)
+	seen := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+#.-_", r)
+	})
+	for _, word := range words {
+		word = strings.Trim(word, ".-_")
+		if utf8.RuneCountInString(word) < 2 || len(word) > maxTermLength || stopwords[word] || seen[word] {
+			continue
+		}
+		if strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) >= maxTermsPerText {
+			break
+		}
+	}
+	return terms
+}
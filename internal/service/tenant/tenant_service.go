@@ -0,0 +1,258 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tenant
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/base/handler"
+	"github.com/apache/answer/internal/base/pager"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/migrations"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/feature_toggle"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+// tenantDisabledID the cached id of the host of the disabled tenant
+const tenantDisabledID = -1
+
+// TenantRepo the tenants are kept with the rows of the primary site
+type TenantRepo interface {
+	AddTenant(ctx context.Context, tenant *entity.Tenant) (err error)
+	UpdateTenant(ctx context.Context, tenant *entity.Tenant, cols []string) (err error)
+	GetTenant(ctx context.Context, id int) (tenant *entity.Tenant, exist bool, err error)
+	GetTenantByHost(ctx context.Context, host string) (tenant *entity.Tenant, exist bool, err error)
+	GetTenantPage(ctx context.Context, page, pageSize int) (tenants []*entity.Tenant, total int64, err error)
+	GetAvailableTenantList(ctx context.Context) (tenants []*entity.Tenant, err error)
+}
+
+// TenantService the communities hosted on the same deployment, each of them is resolved from the request host
+type TenantService struct {
+	data                 *data.Data
+	tenantRepo           TenantRepo
+	featureToggleService *feature_toggle.FeatureToggleService
+}
+
+// NewTenantService new tenant service
+func NewTenantService(
+	data *data.Data,
+	tenantRepo TenantRepo,
+	featureToggleService *feature_toggle.FeatureToggleService,
+) *TenantService {
+	return &TenantService{
+		data:                 data,
+		tenantRepo:           tenantRepo,
+		featureToggleService: featureToggleService,
+	}
+}
+
+// ResolveTenantID resolve the tenant from the request host, the primary site serves the hosts that are not registered.
+// The host of the disabled tenant is not found, and the request is unavailable if the tenant can not be looked up.
+func (ts *TenantService) ResolveTenantID(ctx context.Context, host string) (tenantID int, err error) {
+	enabled, err := ts.featureToggleService.IsEnabled(ctx, feature_toggle.FeatureCustomDomain)
+	if err != nil {
+		return entity.DefaultTenantID, errors.ServiceUnavailable(reason.TenantUnavailable).WithError(err).WithStack()
+	}
+	if !enabled {
+		return entity.DefaultTenantID, nil
+	}
+
+	host = formatHost(host)
+	cacheKey := constant.TenantHostCacheKey + host
+	cacheData, exist, err := ts.data.Cache.GetString(ctx, cacheKey)
+	if err != nil {
+		log.Error(err)
+	}
+	if exist {
+		tenantID, _ = strconv.Atoi(cacheData)
+	} else {
+		tenant, exist, err := ts.tenantRepo.GetTenantByHost(ctx, host)
+		if err != nil {
+			return entity.DefaultTenantID, errors.ServiceUnavailable(reason.TenantUnavailable).WithError(err).WithStack()
+		}
+		if exist {
+			tenantID = tenant.ID
+			if tenant.Status != entity.TenantStatusAvailable {
+				tenantID = tenantDisabledID
+			}
+		}
+		if err := ts.data.Cache.SetString(ctx, cacheKey, strconv.Itoa(tenantID), constant.TenantHostCacheTime); err != nil {
+			log.Error(err)
+		}
+	}
+	if tenantID == tenantDisabledID {
+		return entity.DefaultTenantID, errors.NotFound(reason.TenantNotFound)
+	}
+	return tenantID, nil
+}
+
+// GetAvailableTenantIDs get the tenants that are available
+func (ts *TenantService) GetAvailableTenantIDs(ctx context.Context) (tenantIDs []int, err error) {
+	tenants, err := ts.tenantRepo.GetAvailableTenantList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tenant := range tenants {
+		tenantIDs = append(tenantIDs, tenant.ID)
+	}
+	return tenantIDs, nil
+}
+
+// AddTenant add the tenant, its administrator, site info and default content are initialized in the shared database.
+// The tenant is available only after it is initialized.
+func (ts *TenantService) AddTenant(ctx context.Context, req *schema.AddTenantReq) (resp *schema.AddTenantResp, err error) {
+	if err = ts.checkPrimarySite(ctx); err != nil {
+		return nil, err
+	}
+	host := formatHost(req.Host)
+	_, exist, err := ts.tenantRepo.GetTenantByHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, errors.BadRequest(reason.TenantHostExists)
+	}
+
+	tenant := &entity.Tenant{
+		Host:   host,
+		Name:   req.Name,
+		Status: entity.TenantStatusDisabled,
+	}
+	if err = ts.tenantRepo.AddTenant(ctx, tenant); err != nil {
+		return nil, err
+	}
+	mentor := migrations.NewMentor(ctx, ts.data.DB.Engine, &migrations.InitNeedUserInputData{
+		Language:               req.Language,
+		SiteName:               req.Name,
+		SiteURL:                strings.TrimSuffix(req.SiteURL, "/"),
+		ContactEmail:           req.ContactEmail,
+		AdminName:              req.AdminName,
+		AdminPassword:          req.AdminPassword,
+		AdminEmail:             req.AdminEmail,
+		LoginRequired:          req.LoginRequired,
+		ExternalContentDisplay: "always_display",
+	})
+	if err = mentor.InitTenant(tenant.ID); err != nil {
+		return nil, errors.InternalServer(reason.DatabaseError).WithError(err).WithStack()
+	}
+
+	tenant.Status = entity.TenantStatusAvailable
+	if err = ts.tenantRepo.UpdateTenant(ctx, tenant, []string{"status"}); err != nil {
+		return nil, err
+	}
+	ts.clearHostCache(ctx, host)
+	return &schema.AddTenantResp{ID: tenant.ID}, nil
+}
+
+// UpdateTenant update the tenant, the host of the disabled tenant is not found
+func (ts *TenantService) UpdateTenant(ctx context.Context, req *schema.UpdateTenantReq) (err error) {
+	if err = ts.checkPrimarySite(ctx); err != nil {
+		return err
+	}
+	tenant, exist, err := ts.tenantRepo.GetTenant(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.TenantNotFound)
+	}
+	host := formatHost(req.Host)
+	if host != tenant.Host {
+		_, exist, err = ts.tenantRepo.GetTenantByHost(ctx, host)
+		if err != nil {
+			return err
+		}
+		if exist {
+			return errors.BadRequest(reason.TenantHostExists)
+		}
+	}
+
+	oldHost := tenant.Host
+	tenant.Host = host
+	tenant.Name = req.Name
+	tenant.Status = entity.TenantStatusAvailable
+	if req.Status == "disabled" {
+		tenant.Status = entity.TenantStatusDisabled
+	}
+	if err = ts.tenantRepo.UpdateTenant(ctx, tenant, []string{"host", "name", "status"}); err != nil {
+		return err
+	}
+	ts.clearHostCache(ctx, oldHost)
+	ts.clearHostCache(ctx, host)
+	return nil
+}
+
+// GetTenantPage get the tenants by page
+func (ts *TenantService) GetTenantPage(ctx context.Context, req *schema.GetTenantPageReq) (
+	pageModel *pager.PageModel, err error) {
+	if err = ts.checkPrimarySite(ctx); err != nil {
+		return nil, err
+	}
+	tenants, total, err := ts.tenantRepo.GetTenantPage(ctx, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]*schema.TenantResp, 0, len(tenants))
+	for _, tenant := range tenants {
+		status := "available"
+		if tenant.Status != entity.TenantStatusAvailable {
+			status = "disabled"
+		}
+		resp = append(resp, &schema.TenantResp{
+			ID:        tenant.ID,
+			Host:      tenant.Host,
+			Name:      tenant.Name,
+			Status:    status,
+			CreatedAt: tenant.CreatedAt.Unix(),
+		})
+	}
+	return pager.NewPageModel(total, resp), nil
+}
+
+// checkPrimarySite the tenants are only managed by the administrators of the primary site
+func (ts *TenantService) checkPrimarySite(ctx context.Context) error {
+	if !handler.IsPrimarySite(ctx) {
+		return errors.Forbidden(reason.TenantPrimarySiteOnly)
+	}
+	return ts.featureToggleService.EnsureEnabled(ctx, feature_toggle.FeatureCustomDomain)
+}
+
+func (ts *TenantService) clearHostCache(ctx context.Context, host string) {
+	if err := ts.data.Cache.Del(ctx, constant.TenantHostCacheKey+host); err != nil {
+		log.Error(err)
+	}
+}
+
+// formatHost the host is matched without the port and case insensitively
+func formatHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return host
+}
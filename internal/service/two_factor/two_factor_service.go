@@ -0,0 +1,827 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package two_factor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/pkg/token"
+	"github.com/apache/answer/pkg/totp"
+	"github.com/apache/answer/pkg/webauthn"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	// maxVerifyFailures the wrong second factors accepted for a user before the verification is refused for a while,
+	// so that the passcodes can not be guessed
+	maxVerifyFailures = 10
+
+	setupStateTOTP             = "totp:"
+	setupStateWebAuthn         = "webauthn:"
+	setupStateWebAuthnReauth   = "webauthn-reauth:"
+	setupStateVerifyFailures   = "failures:"
+	recoveryCodeAlphabet       = "abcdefghijkmnpqrstuvwxyz23456789"
+	recoveryCodeGroupSeparator = "-"
+)
+
+// TwoFactorRepo two factor repository
+type TwoFactorRepo interface {
+	GetUserTwoFactor(ctx context.Context, userID string) (info *entity.UserTwoFactor, exist bool, err error)
+	SaveUserTwoFactor(ctx context.Context, info *entity.UserTwoFactor) (err error)
+	UpdateTOTPLastStep(ctx context.Context, userID string, step int64) (updated bool, err error)
+	UpdateRecoveryCodes(ctx context.Context, userID, oldCodes, newCodes string) (updated bool, err error)
+	RemoveUserTwoFactor(ctx context.Context, userID string) (err error)
+	AddWebAuthnCredential(ctx context.Context, cred *entity.UserWebAuthnCredential) (err error)
+	GetWebAuthnCredentials(ctx context.Context, userID string) (creds []*entity.UserWebAuthnCredential, err error)
+	UpdateWebAuthnSignCount(ctx context.Context, cred *entity.UserWebAuthnCredential, oldSignCount int64) (
+		updated bool, err error)
+	RemoveWebAuthnCredential(ctx context.Context, userID string, id int) (removed bool, err error)
+	SetLoginChallenge(ctx context.Context, token string, challenge *schema.TwoFactorLoginChallenge) (err error)
+	GetLoginChallenge(ctx context.Context, token string) (challenge *schema.TwoFactorLoginChallenge, err error)
+	DeleteLoginChallenge(ctx context.Context, token string) (err error)
+	SetSetupState(ctx context.Context, key, value string) (err error)
+	GetSetupState(ctx context.Context, key string) (value string, exist bool, err error)
+	DeleteSetupState(ctx context.Context, key string) (err error)
+}
+
+// TwoFactorService the second factors of the users: the authenticator app (TOTP), the security keys (WebAuthn)
+// and the recovery codes which replace them when they are lost.
+type TwoFactorService struct {
+	twoFactorRepo   TwoFactorRepo
+	userRepo        usercommon.UserRepo
+	userRoleService *role.UserRoleRelService
+	siteInfoService siteinfo_common.SiteInfoCommonService
+}
+
+// NewTwoFactorService new two factor service
+func NewTwoFactorService(
+	twoFactorRepo TwoFactorRepo,
+	userRepo usercommon.UserRepo,
+	userRoleService *role.UserRoleRelService,
+	siteInfoService siteinfo_common.SiteInfoCommonService,
+) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo:   twoFactorRepo,
+		userRepo:        userRepo,
+		userRoleService: userRoleService,
+		siteInfoService: siteInfoService,
+	}
+}
+
+// factors the second factors of the user, the info is nil if the user has never set up one
+type factors struct {
+	info  *entity.UserTwoFactor
+	creds []*entity.UserWebAuthnCredential
+}
+
+func (f *factors) totpEnabled() bool {
+	return f.info != nil && f.info.TOTPEnabled
+}
+
+func (f *factors) enabled() bool {
+	return f.totpEnabled() || len(f.creds) > 0
+}
+
+func (f *factors) recoveryCodes() []string {
+	codes := make([]string, 0)
+	if f.info != nil && len(f.info.RecoveryCodes) > 0 {
+		_ = json.Unmarshal([]byte(f.info.RecoveryCodes), &codes)
+	}
+	return codes
+}
+
+func (ts *TwoFactorService) getFactors(ctx context.Context, userID string) (f *factors, err error) {
+	f = &factors{}
+	info, exist, err := ts.twoFactorRepo.GetUserTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		f.info = info
+	}
+	f.creds, err = ts.twoFactorRepo.GetWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// HasTwoFactor whether the user has set up a second factor
+func (ts *TwoFactorService) HasTwoFactor(ctx context.Context, userID string) (has bool, err error) {
+	f, err := ts.getFactors(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return f.enabled(), nil
+}
+
+// IsRequired whether the role of the user requires a second factor, as the site security settings say
+func (ts *TwoFactorService) IsRequired(ctx context.Context, userID string) (required bool, err error) {
+	security, err := ts.siteInfoService.GetSiteSecurity(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !security.RequireStaffTwoFactor {
+		return false, nil
+	}
+	roleID, err := ts.userRoleService.GetUserRole(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return roleID == role.RoleAdminID || roleID == role.RoleModeratorID, nil
+}
+
+// CheckCanRequireStaff the administrator must have a second factor before requiring it from the staff,
+// otherwise they would be locked out of their next login
+func (ts *TwoFactorService) CheckCanRequireStaff(ctx context.Context, loginUserID string) (err error) {
+	has, err := ts.HasTwoFactor(ctx, loginUserID)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return errors.BadRequest(reason.TwoFactorEnrollFirst)
+	}
+	return nil
+}
+
+// GetTwoFactor get the second factors of the user
+func (ts *TwoFactorService) GetTwoFactor(ctx context.Context, userID string) (resp *schema.GetTwoFactorResp, err error) {
+	f, err := ts.getFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = &schema.GetTwoFactorResp{
+		TOTPEnabled:            f.totpEnabled(),
+		RecoveryCodesRemaining: len(f.recoveryCodes()),
+		WebAuthnCredentials:    make([]*schema.WebAuthnCredentialResp, 0, len(f.creds)),
+	}
+	for _, cred := range f.creds {
+		item := &schema.WebAuthnCredentialResp{ID: cred.ID, Name: cred.Name, CreatedAt: cred.CreatedAt.Unix()}
+		if !cred.LastUsedAt.IsZero() {
+			item.LastUsedAt = cred.LastUsedAt.Unix()
+		}
+		resp.WebAuthnCredentials = append(resp.WebAuthnCredentials, item)
+	}
+	resp.Required, err = ts.IsRequired(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// NewLoginChallenge start the verification of the second factor after the password of the user is verified.
+// The challenge is nil if the user can sign in with the password alone.
+func (ts *TwoFactorService) NewLoginChallenge(ctx context.Context, userID, externalID string) (
+	resp *schema.TwoFactorChallengeResp, err error) {
+	f, err := ts.getFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	challenge := &schema.TwoFactorLoginChallenge{UserID: userID, ExternalID: externalID}
+	if !f.enabled() {
+		required, err := ts.IsRequired(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+		challenge.EnrollRequired = true
+	}
+	if len(f.creds) > 0 {
+		challenge.WebAuthnChallenge, err = webauthn.NewChallenge()
+		if err != nil {
+			return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		}
+	}
+	resp, err = ts.loginChallengeResp(ctx, token.GenerateToken(), challenge, f)
+	if err != nil {
+		return nil, err
+	}
+	if err = ts.twoFactorRepo.SetLoginChallenge(ctx, resp.ChallengeToken, challenge); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetLoginChallenge get the sign-in waiting for the second factor. The external logins redirect the user
+// with the challenge token only, the page of the challenge gets the second factors to ask for with it.
+func (ts *TwoFactorService) GetLoginChallenge(ctx context.Context, challengeToken string) (
+	resp *schema.TwoFactorChallengeResp, err error) {
+	challenge, err := ts.twoFactorRepo.GetLoginChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, errors.BadRequest(reason.TwoFactorChallengeInvalid)
+	}
+	f, err := ts.getFactors(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return ts.loginChallengeResp(ctx, challengeToken, challenge, f)
+}
+
+func (ts *TwoFactorService) loginChallengeResp(ctx context.Context, challengeToken string,
+	challenge *schema.TwoFactorLoginChallenge, f *factors) (resp *schema.TwoFactorChallengeResp, err error) {
+	resp = &schema.TwoFactorChallengeResp{
+		ChallengeToken: challengeToken,
+		Methods:        make([]string, 0),
+		EnrollRequired: challenge.EnrollRequired,
+	}
+	if f.totpEnabled() {
+		resp.Methods = append(resp.Methods, schema.TwoFactorMethodTOTP)
+	}
+	if len(f.creds) > 0 && len(challenge.WebAuthnChallenge) > 0 {
+		rp, err := ts.relyingParty(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp.Methods = append(resp.Methods, schema.TwoFactorMethodWebAuthn)
+		resp.WebAuthn = rp.RequestOptions(challenge.WebAuthnChallenge, credentialIDs(f.creds))
+	}
+	if len(f.recoveryCodes()) > 0 {
+		resp.Methods = append(resp.Methods, schema.TwoFactorMethodRecoveryCode)
+	}
+	return resp, nil
+}
+
+// VerifyLoginChallenge verify the second factor of the sign-in, and return the user who signed in
+func (ts *TwoFactorService) VerifyLoginChallenge(ctx context.Context, req *schema.TwoFactorLoginReq) (
+	challenge *schema.TwoFactorLoginChallenge, err error) {
+	challenge, err = ts.getLoginChallenge(ctx, req.ChallengeToken, false)
+	if err != nil {
+		return nil, err
+	}
+	if err = ts.verify(ctx, challenge.UserID, &req.TwoFactorVerifyReq, challenge.WebAuthnChallenge); err != nil {
+		return nil, err
+	}
+	if err = ts.twoFactorRepo.DeleteLoginChallenge(ctx, req.ChallengeToken); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// SetupTOTPForLogin set up the authenticator app of the user who must have a second factor to sign in
+func (ts *TwoFactorService) SetupTOTPForLogin(ctx context.Context, req *schema.TwoFactorEnrollSetupReq) (
+	resp *schema.TOTPSetupResp, err error) {
+	challenge, err := ts.getLoginChallenge(ctx, req.ChallengeToken, true)
+	if err != nil {
+		return nil, err
+	}
+	return ts.SetupTOTP(ctx, challenge.UserID)
+}
+
+// EnableTOTPForLogin enable the authenticator app of the user who must have a second factor to sign in.
+// The passcode proves the second factor, so the sign-in is finished.
+func (ts *TwoFactorService) EnableTOTPForLogin(ctx context.Context, req *schema.TwoFactorEnrollLoginReq) (
+	challenge *schema.TwoFactorLoginChallenge, codes *schema.RecoveryCodesResp, err error) {
+	challenge, err = ts.getLoginChallenge(ctx, req.ChallengeToken, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	codes, err = ts.EnableTOTP(ctx, &schema.EnableTOTPReq{Code: req.Code, UserID: challenge.UserID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = ts.twoFactorRepo.DeleteLoginChallenge(ctx, req.ChallengeToken); err != nil {
+		return nil, nil, err
+	}
+	return challenge, codes, nil
+}
+
+func (ts *TwoFactorService) getLoginChallenge(ctx context.Context, challengeToken string, enroll bool) (
+	challenge *schema.TwoFactorLoginChallenge, err error) {
+	challenge, err = ts.twoFactorRepo.GetLoginChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || challenge.EnrollRequired != enroll {
+		return nil, errors.BadRequest(reason.TwoFactorChallengeInvalid)
+	}
+	return challenge, nil
+}
+
+// Reauth verify the second factor of the signed in user, who confirms their identity before a sensitive action
+func (ts *TwoFactorService) Reauth(ctx context.Context, userID string, req *schema.TwoFactorVerifyReq) (err error) {
+	var webAuthnChallenge string
+	if req.Method == schema.TwoFactorMethodWebAuthn {
+		webAuthnChallenge, _, err = ts.twoFactorRepo.GetSetupState(ctx, setupStateWebAuthnReauth+userID)
+		if err != nil {
+			return err
+		}
+		if err = ts.twoFactorRepo.DeleteSetupState(ctx, setupStateWebAuthnReauth+userID); err != nil {
+			return err
+		}
+	}
+	return ts.verify(ctx, userID, req, webAuthnChallenge)
+}
+
+// BeginWebAuthnReauth the options for the browser to sign the challenge of the reauthentication
+func (ts *TwoFactorService) BeginWebAuthnReauth(ctx context.Context, userID string) (
+	resp *webauthn.RequestOptions, err error) {
+	creds, err := ts.twoFactorRepo.GetWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(creds) == 0 {
+		return nil, errors.BadRequest(reason.WebAuthnCredentialNotFound)
+	}
+	rp, err := ts.relyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	if err = ts.twoFactorRepo.SetSetupState(ctx, setupStateWebAuthnReauth+userID, challenge); err != nil {
+		return nil, err
+	}
+	return rp.RequestOptions(challenge, credentialIDs(creds)), nil
+}
+
+// verify the second factor of the user, each passcode, recovery code and signature is accepted only once
+func (ts *TwoFactorService) verify(ctx context.Context, userID string, req *schema.TwoFactorVerifyReq,
+	webAuthnChallenge string) (err error) {
+	failures, err := ts.getVerifyFailures(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if failures >= maxVerifyFailures {
+		log.Warnf("too many wrong second factors of user %s", userID)
+		return errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	f, err := ts.getFactors(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	switch req.Method {
+	case schema.TwoFactorMethodTOTP:
+		ok, err = ts.verifyTOTP(ctx, f, req.Code)
+	case schema.TwoFactorMethodRecoveryCode:
+		ok, err = ts.useRecoveryCode(ctx, f, req.Code)
+	case schema.TwoFactorMethodWebAuthn:
+		ok, err = ts.verifyWebAuthn(ctx, f, req.WebAuthn, webAuthnChallenge)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if err = ts.twoFactorRepo.SetSetupState(ctx, setupStateVerifyFailures+userID,
+			strconv.Itoa(failures+1)); err != nil {
+			log.Error(err)
+		}
+		if req.Method == schema.TwoFactorMethodWebAuthn {
+			return errors.BadRequest(reason.WebAuthnVerifyFailed)
+		}
+		return errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	if failures > 0 {
+		if err = ts.twoFactorRepo.DeleteSetupState(ctx, setupStateVerifyFailures+userID); err != nil {
+			log.Error(err)
+		}
+	}
+	return nil
+}
+
+func (ts *TwoFactorService) getVerifyFailures(ctx context.Context, userID string) (failures int, err error) {
+	value, exist, err := ts.twoFactorRepo.GetSetupState(ctx, setupStateVerifyFailures+userID)
+	if err != nil || !exist {
+		return 0, err
+	}
+	failures, _ = strconv.Atoi(value)
+	return failures, nil
+}
+
+func (ts *TwoFactorService) verifyTOTP(ctx context.Context, f *factors, code string) (ok bool, err error) {
+	if !f.totpEnabled() {
+		return false, nil
+	}
+	step, ok := totp.Validate(f.info.TOTPSecret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	return ts.twoFactorRepo.UpdateTOTPLastStep(ctx, f.info.UserID, step)
+}
+
+func (ts *TwoFactorService) useRecoveryCode(ctx context.Context, f *factors, code string) (ok bool, err error) {
+	if !f.enabled() {
+		return false, nil
+	}
+	hashed := hashRecoveryCode(code)
+	codes := f.recoveryCodes()
+	remaining := make([]string, 0, len(codes))
+	for _, c := range codes {
+		if c == hashed {
+			ok = true
+			continue
+		}
+		remaining = append(remaining, c)
+	}
+	if !ok {
+		return false, nil
+	}
+	newCodes, _ := json.Marshal(remaining)
+	return ts.twoFactorRepo.UpdateRecoveryCodes(ctx, f.info.UserID, f.info.RecoveryCodes, string(newCodes))
+}
+
+func (ts *TwoFactorService) verifyWebAuthn(ctx context.Context, f *factors, assertion *webauthn.AssertionResponse,
+	challenge string) (ok bool, err error) {
+	if assertion == nil || len(challenge) == 0 {
+		return false, nil
+	}
+	rp, err := ts.relyingParty(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, cred := range f.creds {
+		if cred.CredentialID != strings.TrimRight(assertion.RawID, "=") {
+			continue
+		}
+		credential, err := toCredential(cred)
+		if err != nil {
+			log.Errorf("decode webauthn credential %d failed: %v", cred.ID, err)
+			return false, nil
+		}
+		signCount, err := rp.VerifyAssertion(challenge, assertion, credential)
+		if err != nil {
+			log.Debugf("verify webauthn assertion failed: %v", err)
+			return false, nil
+		}
+		oldSignCount := cred.SignCount
+		cred.SignCount = int64(signCount)
+		cred.LastUsedAt = time.Now()
+		return ts.twoFactorRepo.UpdateWebAuthnSignCount(ctx, cred, oldSignCount)
+	}
+	return false, nil
+}
+
+// SetupTOTP generate the secret of the authenticator app, it is kept until the first passcode enables it
+func (ts *TwoFactorService) SetupTOTP(ctx context.Context, userID string) (resp *schema.TOTPSetupResp, err error) {
+	f, err := ts.getFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if f.totpEnabled() {
+		return nil, errors.BadRequest(reason.TwoFactorAlreadyEnabled)
+	}
+	userInfo, exist, err := ts.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	general, err := ts.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	if err = ts.twoFactorRepo.SetSetupState(ctx, setupStateTOTP+userID, secret); err != nil {
+		return nil, err
+	}
+	return &schema.TOTPSetupResp{Secret: secret, URL: totp.URL(general.Name, userInfo.EMail, secret)}, nil
+}
+
+// EnableTOTP enable the authenticator app set up by the user with its first passcode.
+// The recovery codes are generated with the first second factor of the user.
+func (ts *TwoFactorService) EnableTOTP(ctx context.Context, req *schema.EnableTOTPReq) (
+	resp *schema.RecoveryCodesResp, err error) {
+	secret, exist, err := ts.twoFactorRepo.GetSetupState(ctx, setupStateTOTP+req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TwoFactorSetupExpired)
+	}
+	step, ok := totp.Validate(secret, req.Code, time.Now())
+	if !ok {
+		return nil, errors.BadRequest(reason.TwoFactorCodeInvalid)
+	}
+	f, err := ts.getFactors(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if f.totpEnabled() {
+		return nil, errors.BadRequest(reason.TwoFactorAlreadyEnabled)
+	}
+	info := f.info
+	if info == nil {
+		info = &entity.UserTwoFactor{UserID: req.UserID}
+	}
+	resp = &schema.RecoveryCodesResp{RecoveryCodes: make([]string, 0)}
+	if !f.enabled() {
+		resp.RecoveryCodes, info.RecoveryCodes, err = generateRecoveryCodes()
+		if err != nil {
+			return nil, err
+		}
+	}
+	info.TOTPSecret = secret
+	info.TOTPEnabled = true
+	info.TOTPLastStep = step
+	if err = ts.twoFactorRepo.SaveUserTwoFactor(ctx, info); err != nil {
+		return nil, err
+	}
+	if err = ts.twoFactorRepo.DeleteSetupState(ctx, setupStateTOTP+req.UserID); err != nil {
+		log.Error(err)
+	}
+	return resp, nil
+}
+
+// DisableTOTP remove the authenticator app, the recovery codes are removed with the last second factor
+func (ts *TwoFactorService) DisableTOTP(ctx context.Context, userID string) (err error) {
+	f, err := ts.getFactors(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !f.totpEnabled() {
+		return errors.BadRequest(reason.TwoFactorNotEnabled)
+	}
+	if len(f.creds) == 0 {
+		if err = ts.checkRemovable(ctx, userID); err != nil {
+			return err
+		}
+		f.info.RecoveryCodes = ""
+	}
+	f.info.TOTPSecret = ""
+	f.info.TOTPEnabled = false
+	f.info.TOTPLastStep = 0
+	return ts.twoFactorRepo.SaveUserTwoFactor(ctx, f.info)
+}
+
+// RegenerateRecoveryCodes replace all the recovery codes of the user
+func (ts *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID string) (
+	resp *schema.RecoveryCodesResp, err error) {
+	f, err := ts.getFactors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !f.enabled() {
+		return nil, errors.BadRequest(reason.TwoFactorNotEnabled)
+	}
+	info := f.info
+	if info == nil {
+		info = &entity.UserTwoFactor{UserID: userID}
+	}
+	resp = &schema.RecoveryCodesResp{}
+	resp.RecoveryCodes, info.RecoveryCodes, err = generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = ts.twoFactorRepo.SaveUserTwoFactor(ctx, info); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BeginWebAuthnRegistration the options for the browser to create a new security key of the user
+func (ts *TwoFactorService) BeginWebAuthnRegistration(ctx context.Context, userID string) (
+	resp *webauthn.CreationOptions, err error) {
+	userInfo, exist, err := ts.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	creds, err := ts.twoFactorRepo.GetWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := ts.relyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	if err = ts.twoFactorRepo.SetSetupState(ctx, setupStateWebAuthn+userID, challenge); err != nil {
+		return nil, err
+	}
+	user := &webauthn.User{ID: []byte(userID), Name: userInfo.EMail, DisplayName: userInfo.DisplayName}
+	return rp.CreationOptions(challenge, user, credentialIDs(creds)), nil
+}
+
+// FinishWebAuthnRegistration verify and save the security key created by the browser.
+// The recovery codes are generated with the first second factor of the user.
+func (ts *TwoFactorService) FinishWebAuthnRegistration(ctx context.Context, req *schema.FinishWebAuthnRegistrationReq) (
+	resp *schema.RecoveryCodesResp, err error) {
+	challenge, exist, err := ts.twoFactorRepo.GetSetupState(ctx, setupStateWebAuthn+req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.TwoFactorSetupExpired)
+	}
+	if err = ts.twoFactorRepo.DeleteSetupState(ctx, setupStateWebAuthn+req.UserID); err != nil {
+		return nil, err
+	}
+	rp, err := ts.relyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := rp.VerifyRegistration(challenge, req.Credential)
+	if err != nil {
+		log.Debugf("verify webauthn registration failed: %v", err)
+		return nil, errors.BadRequest(reason.WebAuthnVerifyFailed)
+	}
+	f, err := ts.getFactors(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	credentialID := webauthn.EncodeID(credential.ID)
+	for _, cred := range f.creds {
+		if cred.CredentialID == credentialID {
+			return nil, errors.BadRequest(reason.WebAuthnVerifyFailed)
+		}
+	}
+
+	resp = &schema.RecoveryCodesResp{RecoveryCodes: make([]string, 0)}
+	if !f.enabled() {
+		info := f.info
+		if info == nil {
+			info = &entity.UserTwoFactor{UserID: req.UserID}
+		}
+		resp.RecoveryCodes, info.RecoveryCodes, err = generateRecoveryCodes()
+		if err != nil {
+			return nil, err
+		}
+		if err = ts.twoFactorRepo.SaveUserTwoFactor(ctx, info); err != nil {
+			return nil, err
+		}
+	}
+	err = ts.twoFactorRepo.AddWebAuthnCredential(ctx, &entity.UserWebAuthnCredential{
+		UserID:       req.UserID,
+		Name:         req.Name,
+		CredentialID: credentialID,
+		PublicKey:    webauthn.EncodeID(credential.PublicKey),
+		SignCount:    int64(credential.SignCount),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RemoveWebAuthnCredential remove the security key, the recovery codes are removed with the last second factor
+func (ts *TwoFactorService) RemoveWebAuthnCredential(ctx context.Context, req *schema.RemoveWebAuthnCredentialReq) (
+	err error) {
+	f, err := ts.getFactors(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	last := !f.totpEnabled() && len(f.creds) == 1
+	if last {
+		if err = ts.checkRemovable(ctx, req.UserID); err != nil {
+			return err
+		}
+	}
+	removed, err := ts.twoFactorRepo.RemoveWebAuthnCredential(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.NotFound(reason.WebAuthnCredentialNotFound)
+	}
+	if last && f.info != nil {
+		f.info.RecoveryCodes = ""
+		return ts.twoFactorRepo.SaveUserTwoFactor(ctx, f.info)
+	}
+	return nil
+}
+
+// ResetUserTwoFactor remove all the second factors of the user who lost them, by the administrator
+func (ts *TwoFactorService) ResetUserTwoFactor(ctx context.Context, req *schema.ResetUserTwoFactorReq) (err error) {
+	_, exist, err := ts.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.UserNotFound)
+	}
+	if req.UserID == req.LoginUserID {
+		return errors.BadRequest(reason.ForbiddenError)
+	}
+	return ts.twoFactorRepo.RemoveUserTwoFactor(ctx, req.UserID)
+}
+
+// checkRemovable the last second factor can not be removed if the role of the user requires one
+func (ts *TwoFactorService) checkRemovable(ctx context.Context, userID string) (err error) {
+	required, err := ts.IsRequired(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errors.BadRequest(reason.TwoFactorRequired)
+	}
+	return nil
+}
+
+// relyingParty the site is the relying party of the security keys, they are scoped to the host of the site url
+func (ts *TwoFactorService) relyingParty(ctx context.Context) (rp *webauthn.RelyingParty, err error) {
+	general, err := ts.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return nil, err
+	}
+	siteURL, err := url.Parse(general.SiteUrl)
+	if err != nil || len(siteURL.Host) == 0 {
+		return nil, errors.InternalServer(reason.UnknownError).WithMsg("invalid site url")
+	}
+	return &webauthn.RelyingParty{
+		ID:     siteURL.Hostname(),
+		Name:   general.Name,
+		Origin: siteURL.Scheme + "://" + siteURL.Host,
+	}, nil
+}
+
+func toCredential(cred *entity.UserWebAuthnCredential) (credential *webauthn.Credential, err error) {
+	credential = &webauthn.Credential{SignCount: uint32(cred.SignCount)}
+	if credential.ID, err = webauthn.DecodeID(cred.CredentialID); err != nil {
+		return nil, err
+	}
+	if credential.PublicKey, err = webauthn.DecodeID(cred.PublicKey); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+func credentialIDs(creds []*entity.UserWebAuthnCredential) (ids [][]byte) {
+	for _, cred := range creds {
+		if id, err := webauthn.DecodeID(cred.CredentialID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// generateRecoveryCodes generate the recovery codes shown to the user and the json of their hashes to be saved
+func generateRecoveryCodes() (codes []string, hashed string, err error) {
+	hashes := make([]string, 0, recoveryCodeCount)
+	buf := make([]byte, recoveryCodeLength)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err = rand.Read(buf); err != nil {
+			return nil, "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+		}
+		code := make([]byte, 0, recoveryCodeLength+1)
+		for j, b := range buf {
+			if j == recoveryCodeLength/2 {
+				code = append(code, recoveryCodeGroupSeparator...)
+			}
+			code = append(code, recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, string(code))
+		hashes = append(hashes, hashRecoveryCode(string(code)))
+	}
+	content, _ := json.Marshal(hashes)
+	return codes, string(content), nil
+}
+
+// hashRecoveryCode the recovery codes are random enough to be hashed without salt,
+// the case and the separator are ignored as users may type them either way
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, recoveryCodeGroupSeparator, "")
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
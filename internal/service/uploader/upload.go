@@ -21,6 +21,7 @@ package uploader
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -71,6 +72,7 @@ type UploaderService interface {
 	UploadPostAttachment(ctx *gin.Context, userID string) (url string, err error)
 	UploadBrandingFile(ctx *gin.Context, userID string) (url string, err error)
 	AvatarThumbFile(ctx *gin.Context, fileName string, size int) (url string, err error)
+	SaveImportedFile(ctx context.Context, userID, fileName string, data []byte) (url string, err error)
 }
 
 // uploaderService uploader service
@@ -141,19 +143,19 @@ func (us *uploaderService) AvatarThumbFile(ctx *gin.Context, fileName string, si
 	fileSuffix := path.Ext(fileName)
 	if _, ok := supportedThumbFileExtMapping[fileSuffix]; !ok {
 		// if file type is not supported, return original file
-		return path.Join(us.serviceConfig.UploadPath, constant.AvatarSubPath, fileName), nil
+		return path.Join(us.serviceConfig.GetUploadPath(ctx), constant.AvatarSubPath, fileName), nil
 	}
 	if size > 1024 {
 		size = 1024
 	}
 
 	thumbFileName := fmt.Sprintf("%d_%d@%s", size, size, fileName)
-	thumbFilePath := fmt.Sprintf("%s/%s/%s", us.serviceConfig.UploadPath, constant.AvatarThumbSubPath, thumbFileName)
+	thumbFilePath := fmt.Sprintf("%s/%s/%s", us.serviceConfig.GetUploadPath(ctx), constant.AvatarThumbSubPath, thumbFileName)
 	_, err = os.ReadFile(thumbFilePath)
 	if err == nil {
 		return thumbFilePath, nil
 	}
-	filePath := fmt.Sprintf("%s/%s/%s", us.serviceConfig.UploadPath, constant.AvatarSubPath, fileName)
+	filePath := fmt.Sprintf("%s/%s/%s", us.serviceConfig.GetUploadPath(ctx), constant.AvatarSubPath, fileName)
 	avatarFile, err := os.ReadFile(filePath)
 	if err != nil {
 		return "", errors.NotFound(reason.UnknownError).WithError(err)
@@ -170,12 +172,12 @@ func (us *uploaderService) AvatarThumbFile(ctx *gin.Context, fileName string, si
 		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
 
-	if err = dir.CreateDirIfNotExist(path.Join(us.serviceConfig.UploadPath, constant.AvatarThumbSubPath)); err != nil {
+	if err = dir.CreateDirIfNotExist(path.Join(us.serviceConfig.GetUploadPath(ctx), constant.AvatarThumbSubPath)); err != nil {
 		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
 
 	avatarFilePath := path.Join(constant.AvatarThumbSubPath, thumbFileName)
-	saveFilePath := path.Join(us.serviceConfig.UploadPath, avatarFilePath)
+	saveFilePath := path.Join(us.serviceConfig.GetUploadPath(ctx), avatarFilePath)
 	out, err := os.Create(saveFilePath)
 	if err != nil {
 		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
@@ -267,6 +269,47 @@ func (us *uploaderService) UploadPostAttachment(ctx *gin.Context, userID string)
 	return url, nil
 }
 
+// SaveImportedFile save the file of the imported post, the image is saved as the post image
+// and the other file is saved as the post attachment
+func (us *uploaderService) SaveImportedFile(ctx context.Context, userID, fileName string, data []byte) (
+	fileURL string, err error) {
+	siteGeneral, err := us.siteInfoService.GetSiteGeneral(ctx)
+	if err != nil {
+		return "", err
+	}
+	siteAdvanced, err := us.siteInfoService.GetSiteAdvanced(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	fileExt := strings.ToLower(path.Ext(fileName))
+	newFilename := fmt.Sprintf("%s%s", uid.IDStr12(), fileExt)
+	isImage := !checker.IsUnAuthorizedExtension(fileName, siteAdvanced.AuthorizedImageExtensions)
+	fileSubPath := path.Join(constant.FilesPostSubPath, newFilename)
+	source := plugin.UserPostAttachment
+	if isImage {
+		fileSubPath = path.Join(constant.PostSubPath, newFilename)
+		source = plugin.UserPost
+	}
+
+	filePath := path.Join(us.serviceConfig.GetUploadPath(ctx), fileSubPath)
+	if err = dir.CreateDirIfNotExist(path.Dir(filePath)); err != nil {
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+	if err = os.WriteFile(filePath, data, 0644); err != nil {
+		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
+	}
+
+	if isImage {
+		fileURL = fmt.Sprintf("%s/uploads/%s", siteGeneral.SiteUrl, fileSubPath)
+	} else {
+		downloadPath := strings.TrimSuffix(fileSubPath, filepath.Ext(fileSubPath)) + "/" + url.QueryEscape(fileName)
+		fileURL = fmt.Sprintf("%s/uploads/%s", siteGeneral.SiteUrl, downloadPath)
+	}
+	us.fileRecordService.AddFileRecord(ctx, userID, fileSubPath, fileURL, string(source))
+	return fileURL, nil
+}
+
 func (us *uploaderService) UploadBrandingFile(ctx *gin.Context, userID string) (
 	url string, err error) {
 	url, err = us.tryToUploadByPlugin(ctx, plugin.AdminBranding)
@@ -315,7 +358,7 @@ func (us *uploaderService) uploadImageFile(ctx *gin.Context, file *multipart.Fil
 	if err != nil {
 		return "", err
 	}
-	filePath := path.Join(us.serviceConfig.UploadPath, fileSubPath)
+	filePath := path.Join(us.serviceConfig.GetUploadPath(ctx), fileSubPath)
 	if err := ctx.SaveUploadedFile(file, filePath); err != nil {
 		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
@@ -346,7 +389,7 @@ func (us *uploaderService) uploadAttachmentFile(ctx *gin.Context, file *multipar
 	if err != nil {
 		return "", err
 	}
-	filePath := path.Join(us.serviceConfig.UploadPath, fileSubPath)
+	filePath := path.Join(us.serviceConfig.GetUploadPath(ctx), fileSubPath)
 	if err := ctx.SaveUploadedFile(file, filePath); err != nil {
 		return "", errors.InternalServer(reason.UnknownError).WithError(err).WithStack()
 	}
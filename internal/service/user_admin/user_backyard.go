@@ -70,12 +70,14 @@ type UserAdminRepo interface {
 	UpdateUserPassword(ctx context.Context, userID string, password string) (err error)
 	DeletePermanentlyUsers(ctx context.Context) (err error)
 	GetExpiredSuspendedUsers(ctx context.Context) (users []*entity.User, err error)
+	AnonymizeUser(ctx context.Context, user *entity.User) (err error)
 }
 
 // UserAdminService user service
 type UserAdminService struct {
 	userRepo              UserAdminRepo
 	userRoleRelService    *role.UserRoleRelService
+	roleService           *role.RoleService
 	authService           *auth.AuthService
 	userCommonService     *usercommon.UserCommon
 	userActivity          activity.UserActiveActivityRepo
@@ -95,6 +97,7 @@ type UserAdminService struct {
 func NewUserAdminService(
 	userRepo UserAdminRepo,
 	userRoleRelService *role.UserRoleRelService,
+	roleService *role.RoleService,
 	authService *auth.AuthService,
 	userCommonService *usercommon.UserCommon,
 	userActivity activity.UserActiveActivityRepo,
@@ -112,6 +115,7 @@ func NewUserAdminService(
 	return &UserAdminService{
 		userRepo:              userRepo,
 		userRoleRelService:    userRoleRelService,
+		roleService:           roleService,
 		authService:           authService,
 		userCommonService:     userCommonService,
 		userActivity:          userActivity,
@@ -231,6 +235,13 @@ func (us *UserAdminService) UpdateUserRole(ctx context.Context, req *schema.Upda
 	if req.UserID == req.LoginUserID {
 		return errors.BadRequest(reason.UserCannotUpdateYourRole)
 	}
+	_, exist, err := us.roleService.GetRole(ctx, req.RoleID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.RoleNotFound)
+	}
 
 	err = us.userRoleRelService.SaveUserRole(ctx, req.UserID, req.RoleID)
 	if err != nil {
@@ -244,6 +255,38 @@ func (us *UserAdminService) UpdateUserRole(ctx context.Context, req *schema.Upda
 	return
 }
 
+// EraseUser erase the personal data of the user who requested to delete the account,
+// the content created by the user is kept anonymously unless removeContent is set
+func (us *UserAdminService) EraseUser(ctx context.Context, userID string, removeContent bool) (err error) {
+	userInfo, exist, err := us.userRepo.GetUserInfo(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.BadRequest(reason.UserNotFound)
+	}
+	anonymousName := fmt.Sprintf("deleted_user_%s", userInfo.ID)
+	err = us.userRepo.AnonymizeUser(ctx, &entity.User{
+		ID:          userInfo.ID,
+		Username:    anonymousName,
+		DisplayName: anonymousName,
+		Status:      entity.UserStatusDeleted,
+		DeletedAt:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err = us.revokeUserAPIKeys(ctx, userInfo.ID); err != nil {
+		return err
+	}
+	if removeContent {
+		us.removeAllUserCreatedContent(ctx, userInfo.ID)
+	}
+	us.removeAllUserConfiguration(ctx, userInfo.ID)
+	us.authService.RemoveUserAllTokens(ctx, userInfo.ID)
+	return nil
+}
+
 func (us *UserAdminService) revokeUserAPIKeys(ctx context.Context, userID string) error {
 	return us.apiKeyRepo.DeleteAPIKeysByUserID(ctx, userID)
 }
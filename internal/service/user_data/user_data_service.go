@@ -0,0 +1,695 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package user_data
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/apache/answer/internal/base/constant"
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/activity_type"
+	"github.com/apache/answer/internal/service/config"
+	"github.com/apache/answer/internal/service/export"
+	"github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/service_config"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/user_admin"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/segmentfault/pacman/errors"
+	"github.com/segmentfault/pacman/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// exportExpireDays the days the data export can be downloaded after it is ready
+	exportExpireDays = 7
+	// exportInterval the min interval between two data exports of a user
+	exportInterval = 24 * time.Hour
+	// erasureCoolingOffDays the days the user can cancel the account deletion after requesting it
+	erasureCoolingOffDays = 14
+)
+
+var userDataRequestStatusNames = map[int]string{
+	entity.UserDataRequestStatusPending:    "pending",
+	entity.UserDataRequestStatusProcessing: "processing",
+	entity.UserDataRequestStatusCompleted:  "completed",
+	entity.UserDataRequestStatusFailed:     "failed",
+	entity.UserDataRequestStatusCancelled:  "cancelled",
+	entity.UserDataRequestStatusExpired:    "expired",
+}
+
+// voteActivityTypes the activities of the user as a voter
+var voteActivityTypes = []string{
+	activity_type.QuestionVoteUp,
+	activity_type.QuestionVoteDown,
+	activity_type.AnswerVoteUp,
+	activity_type.AnswerVoteDown,
+	activity_type.CommentVoteUp,
+}
+
+type UserDataRepo interface {
+	AddUserDataRequest(ctx context.Context, req *entity.UserDataRequest) (err error)
+	UpdateUserDataRequestStatus(ctx context.Context, id, fromStatus, toStatus int) (updated bool, err error)
+	UpdateUserDataRequest(ctx context.Context, req *entity.UserDataRequest) (err error)
+	GetLatestUserDataRequest(ctx context.Context, userID, requestType string) (
+		req *entity.UserDataRequest, exist bool, err error)
+	GetUserDataRequestByCode(ctx context.Context, code string) (req *entity.UserDataRequest, exist bool, err error)
+	GetUserDataRequests(ctx context.Context, userID string) (list []*entity.UserDataRequest, err error)
+	GetDueUserDataRequests(ctx context.Context, now time.Time) (list []*entity.UserDataRequest, err error)
+	GetExpiredUserDataExports(ctx context.Context, before time.Time) (list []*entity.UserDataRequest, err error)
+	GetUserQuestions(ctx context.Context, userID string) (list []*entity.Question, err error)
+	GetUserAnswers(ctx context.Context, userID string) (list []*entity.Answer, err error)
+	GetUserComments(ctx context.Context, userID string) (list []*entity.Comment, err error)
+	GetUserVotes(ctx context.Context, userID string, activityTypes []int) (list []*entity.Activity, err error)
+	GetUserCollections(ctx context.Context, userID string) (list []*entity.Collection, err error)
+	GetUserNotifications(ctx context.Context, userID string) (list []*entity.Notification, err error)
+	GetUserAIConversations(ctx context.Context, userID string) (
+		conversations []*entity.AIConversation, records []*entity.AIConversationRecord, err error)
+	GetUserFileRecords(ctx context.Context, userID string) (list []*entity.FileRecord, err error)
+}
+
+// UserDataService the self-service data export and account erasure of users
+type UserDataService struct {
+	userDataRepo          UserDataRepo
+	userRepo              usercommon.UserRepo
+	userAdminService      *user_admin.UserAdminService
+	userRoleRelService    *role.UserRoleRelService
+	configService         *config.ConfigService
+	emailService          *export.EmailService
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService
+	serviceConfig         *service_config.ServiceConfig
+}
+
+// NewUserDataService new user data service
+func NewUserDataService(
+	userDataRepo UserDataRepo,
+	userRepo usercommon.UserRepo,
+	userAdminService *user_admin.UserAdminService,
+	userRoleRelService *role.UserRoleRelService,
+	configService *config.ConfigService,
+	emailService *export.EmailService,
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+	serviceConfig *service_config.ServiceConfig,
+) *UserDataService {
+	return &UserDataService{
+		userDataRepo:          userDataRepo,
+		userRepo:              userRepo,
+		userAdminService:      userAdminService,
+		userRoleRelService:    userRoleRelService,
+		configService:         configService,
+		emailService:          emailService,
+		siteInfoCommonService: siteInfoCommonService,
+		serviceConfig:         serviceConfig,
+	}
+}
+
+// RequestUserDataExport request to export all the personal data of the user, the archive is prepared in background
+func (us *UserDataService) RequestUserDataExport(ctx context.Context, req *schema.RequestUserDataExportReq) (
+	resp *schema.UserDataRequestResp, err error) {
+	latest, exist, err := us.userDataRepo.GetLatestUserDataRequest(ctx, req.UserID, entity.UserDataRequestTypeExport)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		switch {
+		case latest.Status == entity.UserDataRequestStatusPending || latest.Status == entity.UserDataRequestStatusProcessing:
+			return nil, errors.BadRequest(reason.UserDataExportInProgress)
+		case latest.Status == entity.UserDataRequestStatusCompleted && time.Since(latest.CreatedAt) < exportInterval:
+			return nil, errors.BadRequest(reason.UserDataExportTooFrequent)
+		}
+	}
+	request := &entity.UserDataRequest{
+		UserID:      req.UserID,
+		RequestType: entity.UserDataRequestTypeExport,
+		Status:      entity.UserDataRequestStatusPending,
+		ScheduledAt: time.Now(),
+	}
+	if err = us.userDataRepo.AddUserDataRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return us.convertUserDataRequestResp(ctx, request), nil
+}
+
+// RequestUserErasure request to delete the account of the user, it is erased after the cooling-off period
+func (us *UserDataService) RequestUserErasure(ctx context.Context, req *schema.RequestUserErasureReq) (
+	resp *schema.UserDataRequestResp, err error) {
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.BadRequest(reason.UserNotFound)
+	}
+	roleID, err := us.userRoleRelService.GetUserRole(ctx, userInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if roleID == role.RoleAdminID {
+		return nil, errors.BadRequest(reason.UserErasureAdminForbidden)
+	}
+	if len(userInfo.Pass) > 0 && bcrypt.CompareHashAndPassword([]byte(userInfo.Pass), []byte(req.Pass)) != nil {
+		return nil, errors.BadRequest(reason.UserErasurePasswordWrong)
+	}
+
+	latest, exist, err := us.userDataRepo.GetLatestUserDataRequest(ctx, userInfo.ID, entity.UserDataRequestTypeErasure)
+	if err != nil {
+		return nil, err
+	}
+	if exist && latest.Status == entity.UserDataRequestStatusPending {
+		return nil, errors.BadRequest(reason.UserErasureAlreadyRequested)
+	}
+	request := &entity.UserDataRequest{
+		UserID:      userInfo.ID,
+		RequestType: entity.UserDataRequestTypeErasure,
+		Status:      entity.UserDataRequestStatusPending,
+		ErasureMode: req.Mode,
+		ScheduledAt: time.Now().AddDate(0, 0, erasureCoolingOffDays),
+	}
+	if err = us.userDataRepo.AddUserDataRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	title, body, err := us.emailService.UserErasureTemplate(ctx, request.ScheduledAt)
+	if err != nil {
+		log.Errorf("parse user erasure email template failed: %v", err)
+	} else {
+		go us.emailService.Send(ctx, userInfo.EMail, title, body)
+	}
+	return us.convertUserDataRequestResp(ctx, request), nil
+}
+
+// CancelUserErasure cancel the pending account deletion of the user during the cooling-off period
+func (us *UserDataService) CancelUserErasure(ctx context.Context, req *schema.CancelUserErasureReq) (err error) {
+	latest, exist, err := us.userDataRepo.GetLatestUserDataRequest(ctx, req.UserID, entity.UserDataRequestTypeErasure)
+	if err != nil {
+		return err
+	}
+	if !exist || latest.Status != entity.UserDataRequestStatusPending {
+		return errors.BadRequest(reason.UserErasureNotFound)
+	}
+	updated, err := us.userDataRepo.UpdateUserDataRequestStatus(ctx, latest.ID,
+		entity.UserDataRequestStatusPending, entity.UserDataRequestStatusCancelled)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.BadRequest(reason.UserErasureNotFound)
+	}
+	return nil
+}
+
+// GetUserDataRequests get the recent data export and erasure requests of the user
+func (us *UserDataService) GetUserDataRequests(ctx context.Context, req *schema.GetUserDataRequestsReq) (
+	resp []*schema.UserDataRequestResp, err error) {
+	list, err := us.userDataRepo.GetUserDataRequests(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.UserDataRequestResp, 0, len(list))
+	for _, request := range list {
+		resp = append(resp, us.convertUserDataRequestResp(ctx, request))
+	}
+	return resp, nil
+}
+
+// GetUserDataExportFile get the local path of the data export archive by the download code
+func (us *UserDataService) GetUserDataExportFile(ctx context.Context, req *schema.DownloadUserDataExportReq) (
+	filePath, fileName string, err error) {
+	request, exist, err := us.userDataRepo.GetUserDataRequestByCode(ctx, req.Code)
+	if err != nil {
+		return "", "", err
+	}
+	if !exist || request.Status != entity.UserDataRequestStatusCompleted ||
+		request.RequestType != entity.UserDataRequestTypeExport || exportExpiredAt(request).Before(time.Now()) {
+		return "", "", errors.NotFound(reason.UserDataExportNotFound)
+	}
+	filePath = us.exportFilePath(ctx, request.FileName)
+	if _, err = os.Stat(filePath); err != nil {
+		return "", "", errors.NotFound(reason.UserDataExportNotFound)
+	}
+	return filePath, request.FileName, nil
+}
+
+// ProcessUserDataRequestsCron prepare the requested data exports, erase the accounts after the cooling-off period
+// and remove the expired data exports
+func (us *UserDataService) ProcessUserDataRequestsCron(ctx context.Context) {
+	requests, err := us.userDataRepo.GetDueUserDataRequests(ctx, time.Now())
+	if err != nil {
+		log.Errorf("get due user data requests failed: %v", err)
+		return
+	}
+	for _, request := range requests {
+		updated, err := us.userDataRepo.UpdateUserDataRequestStatus(ctx, request.ID,
+			entity.UserDataRequestStatusPending, entity.UserDataRequestStatusProcessing)
+		if err != nil || !updated {
+			continue
+		}
+		switch request.RequestType {
+		case entity.UserDataRequestTypeExport:
+			err = us.exportUserData(ctx, request)
+		case entity.UserDataRequestTypeErasure:
+			err = us.eraseUser(ctx, request)
+		}
+		if err != nil {
+			log.Errorf("process %s request %d of user %s failed: %v", request.RequestType, request.ID, request.UserID, err)
+			_, _ = us.userDataRepo.UpdateUserDataRequestStatus(ctx, request.ID,
+				entity.UserDataRequestStatusProcessing, entity.UserDataRequestStatusFailed)
+		}
+	}
+
+	expired, err := us.userDataRepo.GetExpiredUserDataExports(ctx, time.Now().AddDate(0, 0, -exportExpireDays))
+	if err != nil {
+		log.Errorf("get expired user data exports failed: %v", err)
+		return
+	}
+	for _, request := range expired {
+		us.removeUserDataExport(ctx, request)
+	}
+}
+
+// exportUserData write the archive of the personal data and send the download link to the user
+func (us *UserDataService) exportUserData(ctx context.Context, request *entity.UserDataRequest) (err error) {
+	userInfo, exist, err := us.userRepo.GetByUserID(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("user not found")
+	}
+	request.FileName = fmt.Sprintf("answer_data_%s_%s.zip", userInfo.Username, time.Now().Format("20060102150405"))
+	if err = us.writeUserDataArchive(ctx, userInfo, us.exportFilePath(ctx, request.FileName)); err != nil {
+		return err
+	}
+	request.DownloadCode, err = newDownloadCode()
+	if err != nil {
+		return err
+	}
+	request.Status = entity.UserDataRequestStatusCompleted
+	request.CompletedAt = time.Now()
+	if err = us.userDataRepo.UpdateUserDataRequest(ctx, request); err != nil {
+		return err
+	}
+
+	downloadURL, err := us.downloadURL(ctx, request.DownloadCode)
+	if err != nil {
+		return err
+	}
+	title, body, err := us.emailService.UserDataExportTemplate(ctx, downloadURL, exportExpireDays)
+	if err != nil {
+		return err
+	}
+	us.emailService.Send(ctx, userInfo.EMail, title, body)
+	return nil
+}
+
+// eraseUser erase the account after the cooling-off period, the data exports of the user are removed as well
+func (us *UserDataService) eraseUser(ctx context.Context, request *entity.UserDataRequest) (err error) {
+	err = us.userAdminService.EraseUser(ctx, request.UserID, request.ErasureMode == entity.UserErasureModeDelete)
+	if err != nil {
+		return err
+	}
+	request.Status = entity.UserDataRequestStatusCompleted
+	request.CompletedAt = time.Now()
+	if err = us.userDataRepo.UpdateUserDataRequest(ctx, request); err != nil {
+		return err
+	}
+
+	list, err := us.userDataRepo.GetUserDataRequests(ctx, request.UserID)
+	if err != nil {
+		return err
+	}
+	for _, item := range list {
+		if item.RequestType == entity.UserDataRequestTypeExport && item.Status == entity.UserDataRequestStatusCompleted {
+			us.removeUserDataExport(ctx, item)
+		}
+	}
+	return nil
+}
+
+func (us *UserDataService) removeUserDataExport(ctx context.Context, request *entity.UserDataRequest) {
+	err := os.Remove(us.exportFilePath(ctx, request.FileName))
+	if err != nil && !os.IsNotExist(err) {
+		log.Errorf("remove user data export %s failed: %v", request.FileName, err)
+		return
+	}
+	_, err = us.userDataRepo.UpdateUserDataRequestStatus(ctx, request.ID,
+		entity.UserDataRequestStatusCompleted, entity.UserDataRequestStatusExpired)
+	if err != nil {
+		log.Errorf("expire user data export %d failed: %v", request.ID, err)
+	}
+}
+
+// writeUserDataArchive write the personal data of the user as JSON files and the uploaded files to the zip archive
+func (us *UserDataService) writeUserDataArchive(ctx context.Context, userInfo *entity.User, target string) (err error) {
+	if err = os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+		if err != nil {
+			_ = os.Remove(target)
+		}
+	}()
+	zw := zip.NewWriter(file)
+
+	if err = writeArchiveJSON(zw, "profile.json", convertUserDataProfile(userInfo)); err != nil {
+		return err
+	}
+	for _, item := range []struct {
+		name    string
+		collect func(ctx context.Context, userID string) (any, error)
+	}{
+		{"questions.json", us.collectQuestions},
+		{"answers.json", us.collectAnswers},
+		{"comments.json", us.collectComments},
+		{"votes.json", us.collectVotes},
+		{"collections.json", us.collectCollections},
+		{"notifications.json", us.collectNotifications},
+		{"ai_conversations.json", us.collectAIConversations},
+	} {
+		data, err := item.collect(ctx, userInfo.ID)
+		if err != nil {
+			return fmt.Errorf("collect %s failed: %w", item.name, err)
+		}
+		if err = writeArchiveJSON(zw, item.name, data); err != nil {
+			return err
+		}
+	}
+	if err = us.writeUserFiles(ctx, zw, userInfo.ID); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (us *UserDataService) collectQuestions(ctx context.Context, userID string) (any, error) {
+	list, err := us.userDataRepo.GetUserQuestions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	questions := make([]*schema.UserDataQuestion, 0, len(list))
+	for _, item := range list {
+		questions = append(questions, &schema.UserDataQuestion{
+			ID:          item.ID,
+			Title:       item.Title,
+			Content:     item.OriginalText,
+			Status:      item.Status,
+			ViewCount:   item.ViewCount,
+			VoteCount:   item.VoteCount,
+			AnswerCount: item.AnswerCount,
+			CreatedAt:   item.CreatedAt.Unix(),
+			UpdatedAt:   item.UpdatedAt.Unix(),
+		})
+	}
+	return questions, nil
+}
+
+func (us *UserDataService) collectAnswers(ctx context.Context, userID string) (any, error) {
+	list, err := us.userDataRepo.GetUserAnswers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]*schema.UserDataAnswer, 0, len(list))
+	for _, item := range list {
+		answers = append(answers, &schema.UserDataAnswer{
+			ID:         item.ID,
+			QuestionID: item.QuestionID,
+			Content:    item.OriginalText,
+			Status:     item.Status,
+			Accepted:   item.Accepted == schema.AnswerAcceptedEnable,
+			VoteCount:  item.VoteCount,
+			CreatedAt:  item.CreatedAt.Unix(),
+			UpdatedAt:  item.UpdatedAt.Unix(),
+		})
+	}
+	return answers, nil
+}
+
+func (us *UserDataService) collectComments(ctx context.Context, userID string) (any, error) {
+	list, err := us.userDataRepo.GetUserComments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]*schema.UserDataComment, 0, len(list))
+	for _, item := range list {
+		comments = append(comments, &schema.UserDataComment{
+			ID:        item.ID,
+			ObjectID:  item.ObjectID,
+			Content:   item.OriginalText,
+			Status:    item.Status,
+			VoteCount: item.VoteCount,
+			CreatedAt: item.CreatedAt.Unix(),
+		})
+	}
+	return comments, nil
+}
+
+func (us *UserDataService) collectVotes(ctx context.Context, userID string) (any, error) {
+	activityTypes := make([]int, 0, len(voteActivityTypes))
+	activityTypeKeys := make(map[int]string, len(voteActivityTypes))
+	for _, key := range voteActivityTypes {
+		id, err := us.configService.GetIDByKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		activityTypes = append(activityTypes, id)
+		activityTypeKeys[id] = key
+	}
+	list, err := us.userDataRepo.GetUserVotes(ctx, userID, activityTypes)
+	if err != nil {
+		return nil, err
+	}
+	votes := make([]*schema.UserDataVote, 0, len(list))
+	for _, item := range list {
+		votes = append(votes, &schema.UserDataVote{
+			ObjectID:  item.ObjectID,
+			VoteType:  activityTypeKeys[item.ActivityType],
+			CreatedAt: item.CreatedAt.Unix(),
+		})
+	}
+	return votes, nil
+}
+
+func (us *UserDataService) collectCollections(ctx context.Context, userID string) (any, error) {
+	list, err := us.userDataRepo.GetUserCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	collections := make([]*schema.UserDataCollection, 0, len(list))
+	for _, item := range list {
+		collections = append(collections, &schema.UserDataCollection{
+			ObjectID:  item.ObjectID,
+			CreatedAt: item.CreatedAt.Unix(),
+		})
+	}
+	return collections, nil
+}
+
+func (us *UserDataService) collectNotifications(ctx context.Context, userID string) (any, error) {
+	list, err := us.userDataRepo.GetUserNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	notifications := make([]*schema.UserDataNotification, 0, len(list))
+	for _, item := range list {
+		content := json.RawMessage(item.Content)
+		if !json.Valid(content) {
+			content, _ = json.Marshal(item.Content)
+		}
+		notifications = append(notifications, &schema.UserDataNotification{
+			ObjectID:  item.ObjectID,
+			Type:      item.Type,
+			Content:   content,
+			IsRead:    item.IsRead == schema.NotificationRead,
+			CreatedAt: item.CreatedAt.Unix(),
+		})
+	}
+	return notifications, nil
+}
+
+func (us *UserDataService) collectAIConversations(ctx context.Context, userID string) (any, error) {
+	conversations, records, err := us.userDataRepo.GetUserAIConversations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*schema.UserDataAIConversation, 0, len(conversations))
+	mapping := make(map[string]*schema.UserDataAIConversation, len(conversations))
+	for _, item := range conversations {
+		conversation := &schema.UserDataAIConversation{
+			ConversationID: item.ConversationID,
+			Topic:          item.Topic,
+			CreatedAt:      item.CreatedAt.Unix(),
+			Records:        make([]*schema.UserDataAIConversationRecord, 0),
+		}
+		mapping[item.ConversationID] = conversation
+		result = append(result, conversation)
+	}
+	for _, item := range records {
+		conversation, ok := mapping[item.ConversationID]
+		if !ok {
+			continue
+		}
+		conversation.Records = append(conversation.Records, &schema.UserDataAIConversationRecord{
+			Role:      item.Role,
+			Content:   item.Content,
+			CreatedAt: item.CreatedAt.Unix(),
+		})
+	}
+	return result, nil
+}
+
+// writeUserFiles add the files uploaded by the user, the files kept by the storage plugins are listed by url only
+func (us *UserDataService) writeUserFiles(ctx context.Context, zw *zip.Writer, userID string) (err error) {
+	list, err := us.userDataRepo.GetUserFileRecords(ctx, userID)
+	if err != nil {
+		return err
+	}
+	uploadPath := us.serviceConfig.GetUploadPath(ctx)
+	files := make([]*schema.UserDataFile, 0, len(list))
+	for _, item := range list {
+		file := &schema.UserDataFile{
+			FileURL:   item.FileURL,
+			Source:    item.Source,
+			CreatedAt: item.CreatedAt.Unix(),
+		}
+		localPath := filepath.Join(uploadPath, filepath.Clean("/"+item.FilePath))
+		if _, err := os.Stat(localPath); err == nil {
+			file.Path = path.Join("files", filepath.ToSlash(filepath.Clean("/"+item.FilePath)))
+			if err = writeArchiveFile(zw, file.Path, localPath); err != nil {
+				return err
+			}
+		}
+		files = append(files, file)
+	}
+	return writeArchiveJSON(zw, "files.json", files)
+}
+
+func (us *UserDataService) exportFilePath(ctx context.Context, fileName string) string {
+	return filepath.Join(us.serviceConfig.GetUploadPath(ctx), constant.UserDataSubPath, filepath.Base(fileName))
+}
+
+func (us *UserDataService) downloadURL(ctx context.Context, code string) (string, error) {
+	siteInfo, err := us.siteInfoCommonService.GetSiteGeneral(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/answer/api/v1/user/data/export/download?code=%s", siteInfo.SiteUrl, code), nil
+}
+
+func (us *UserDataService) convertUserDataRequestResp(ctx context.Context, request *entity.UserDataRequest) (
+	resp *schema.UserDataRequestResp) {
+	resp = &schema.UserDataRequestResp{
+		ID:          request.ID,
+		RequestType: request.RequestType,
+		Status:      userDataRequestStatusNames[request.Status],
+		ErasureMode: request.ErasureMode,
+		CreatedAt:   request.CreatedAt.Unix(),
+		ScheduledAt: request.ScheduledAt.Unix(),
+	}
+	if !request.CompletedAt.IsZero() {
+		resp.CompletedAt = request.CompletedAt.Unix()
+	}
+	if request.RequestType == entity.UserDataRequestTypeExport && request.Status == entity.UserDataRequestStatusCompleted {
+		downloadURL, err := us.downloadURL(ctx, request.DownloadCode)
+		if err != nil {
+			log.Error(err)
+		}
+		resp.DownloadURL = downloadURL
+		resp.ExpiredAt = exportExpiredAt(request).Unix()
+	}
+	return resp
+}
+
+func convertUserDataProfile(userInfo *entity.User) *schema.UserDataProfile {
+	profile := &schema.UserDataProfile{
+		ID:          userInfo.ID,
+		Username:    userInfo.Username,
+		DisplayName: userInfo.DisplayName,
+		Email:       userInfo.EMail,
+		Bio:         userInfo.Bio,
+		Website:     userInfo.Website,
+		Location:    userInfo.Location,
+		Avatar:      userInfo.Avatar,
+		Language:    userInfo.Language,
+		Rank:        userInfo.Rank,
+		IPInfo:      userInfo.IPInfo,
+		CreatedAt:   userInfo.CreatedAt.Unix(),
+	}
+	if !userInfo.LastLoginDate.IsZero() {
+		profile.LastLoginDate = userInfo.LastLoginDate.Unix()
+	}
+	return profile
+}
+
+func exportExpiredAt(request *entity.UserDataRequest) time.Time {
+	return request.CompletedAt.AddDate(0, 0, exportExpireDays)
+}
+
+func newDownloadCode() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func writeArchiveJSON(zw *zip.Writer, name string, data any) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func writeArchiveFile(zw *zip.Writer, name, localPath string) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
@@ -33,7 +33,9 @@ import (
 	"github.com/apache/answer/internal/schema"
 	"github.com/apache/answer/internal/service/activity"
 	"github.com/apache/answer/internal/service/export"
+	"github.com/apache/answer/internal/service/role"
 	"github.com/apache/answer/internal/service/siteinfo_common"
+	"github.com/apache/answer/internal/service/two_factor"
 	usercommon "github.com/apache/answer/internal/service/user_common"
 	"github.com/apache/answer/internal/service/user_notification_config"
 	"github.com/apache/answer/pkg/checker"
@@ -69,6 +71,8 @@ type UserExternalLoginService struct {
 	siteInfoCommonService         siteinfo_common.SiteInfoCommonService
 	userActivity                  activity.UserActiveActivityRepo
 	userNotificationConfigService *user_notification_config.UserNotificationConfigService
+	userRoleService               *role.UserRoleRelService
+	twoFactorService              *two_factor.TwoFactorService
 }
 
 // NewUserExternalLoginService new user external login service
@@ -80,6 +84,8 @@ func NewUserExternalLoginService(
 	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
 	userActivity activity.UserActiveActivityRepo,
 	userNotificationConfigService *user_notification_config.UserNotificationConfigService,
+	userRoleService *role.UserRoleRelService,
+	twoFactorService *two_factor.TwoFactorService,
 ) *UserExternalLoginService {
 	return &UserExternalLoginService{
 		userRepo:                      userRepo,
@@ -89,21 +95,29 @@ func NewUserExternalLoginService(
 		siteInfoCommonService:         siteInfoCommonService,
 		userActivity:                  userActivity,
 		userNotificationConfigService: userNotificationConfigService,
+		userRoleService:               userRoleService,
+		twoFactorService:              twoFactorService,
 	}
 }
 
 func (us *UserExternalLoginService) GenerateOAuthState(
 	ctx context.Context, provider, intent, userID string) (state string, err error) {
+	return us.SaveOAuthState(ctx, &schema.ExternalLoginOAuthState{
+		Provider: provider,
+		Intent:   intent,
+		UserID:   userID,
+	})
+}
+
+// SaveOAuthState save the state of the external login request, returns the state to be sent with the request
+func (us *UserExternalLoginService) SaveOAuthState(
+	ctx context.Context, info *schema.ExternalLoginOAuthState) (state string, err error) {
 	state = token.GenerateToken()
 	duration := constant.ConnectorOAuthStateCacheTime
-	if intent == schema.ExternalLoginOAuthStateBindIntent {
+	if info.Intent == schema.ExternalLoginOAuthStateBindIntent {
 		duration = constant.ConnectorOAuthBindStateCacheTime
 	}
-	err = us.userExternalLoginRepo.SetCacheOAuthState(ctx, state, &schema.ExternalLoginOAuthState{
-		Provider: provider,
-		Intent:   intent,
-		UserID:   userID,
-	}, duration)
+	err = us.userExternalLoginRepo.SetCacheOAuthState(ctx, state, info, duration)
 	return state, err
 }
 
@@ -157,9 +171,8 @@ func (us *UserExternalLoginService) ExternalLogin(
 			if err != nil {
 				log.Error(err)
 			}
-			accessToken, _, err := us.userCommonService.CacheLoginUserInfo(
-				ctx, oldUserInfo.ID, newMailStatus, oldUserInfo.Status, oldExternalLoginUserInfo.ExternalID)
-			return &schema.UserExternalLoginResp{AccessToken: accessToken}, err
+			return login(ctx, us.twoFactorService, us.userCommonService,
+				oldUserInfo.ID, newMailStatus, oldUserInfo.Status, oldExternalLoginUserInfo.ExternalID)
 		}
 	}
 
@@ -205,6 +218,13 @@ func (us *UserExternalLoginService) ExternalLogin(
 		return nil, err
 	}
 
+	// the role granted by the identity provider is applied before the user logs in
+	if externalUserInfo.RoleID > 0 && externalUserInfo.RoleID != role.RoleUserID {
+		if err = us.userRoleService.SaveUserRole(ctx, oldUserInfo.ID, externalUserInfo.RoleID); err != nil {
+			return nil, err
+		}
+	}
+
 	// If user login with external account and email is exist, active user directly.
 	newMailStatus, err := us.activeUser(ctx, oldUserInfo, externalUserInfo)
 	if err != nil {
@@ -216,9 +236,26 @@ func (us *UserExternalLoginService) ExternalLogin(
 		log.Errorf("set default user notification config failed, err: %v", err)
 	}
 
-	accessToken, _, err := us.userCommonService.CacheLoginUserInfo(
-		ctx, oldUserInfo.ID, newMailStatus, oldUserInfo.Status, externalUserInfo.ExternalID)
-	return &schema.UserExternalLoginResp{AccessToken: accessToken}, err
+	return login(ctx, us.twoFactorService, us.userCommonService,
+		oldUserInfo.ID, newMailStatus, oldUserInfo.Status, externalUserInfo.ExternalID)
+}
+
+// login sign in the user verified by the external login, the user who has a second factor or must have one
+// gets the challenge of the second factor instead of the access token.
+func login(ctx context.Context, twoFactorService *two_factor.TwoFactorService, userCommonService *usercommon.UserCommon,
+	userID string, userStatus, emailStatus int, externalID string) (resp *schema.UserExternalLoginResp, err error) {
+	challenge, err := twoFactorService.NewLoginChallenge(ctx, userID, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &schema.UserExternalLoginResp{TwoFactor: challenge}, nil
+	}
+	accessToken, _, err := userCommonService.CacheLoginUserInfo(ctx, userID, userStatus, emailStatus, externalID)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.UserExternalLoginResp{AccessToken: accessToken}, nil
 }
 
 func (us *UserExternalLoginService) BindExternalLoginToUser(ctx context.Context,
@@ -376,10 +413,12 @@ func (us *UserExternalLoginService) ExternalLoginBindingUserSendEmail(
 	if err != nil {
 		return nil, err
 	}
-	resp.AccessToken, _, err = us.userCommonService.CacheLoginUserInfo(
-		ctx, userInfo.ID, userInfo.MailStatus, userInfo.Status, externalLoginInfo.ExternalID)
+	loginResp, err := login(ctx, us.twoFactorService, us.userCommonService,
+		userInfo.ID, userInfo.MailStatus, userInfo.Status, externalLoginInfo.ExternalID)
 	if err != nil {
 		log.Error(err)
+	} else {
+		resp.AccessToken, resp.TwoFactor = loginResp.AccessToken, loginResp.TwoFactor
 	}
 	err = us.userExternalLoginRepo.SetCacheUserExternalLoginInfo(ctx, req.BindingKey, externalLoginInfo)
 	if err != nil {
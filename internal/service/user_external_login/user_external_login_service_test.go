@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package user_external_login_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/answer/internal/base/data"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/migrations"
+	"github.com/apache/answer/internal/repo/auth"
+	"github.com/apache/answer/internal/repo/role"
+	"github.com/apache/answer/internal/repo/site_info"
+	"github.com/apache/answer/internal/repo/two_factor"
+	"github.com/apache/answer/internal/repo/user"
+	"github.com/apache/answer/internal/repo/user_external_login"
+	"github.com/apache/answer/internal/schema"
+	auth2 "github.com/apache/answer/internal/service/auth"
+	role2 "github.com/apache/answer/internal/service/role"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	two_factor2 "github.com/apache/answer/internal/service/two_factor"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	user_external_login2 "github.com/apache/answer/internal/service/user_external_login"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm/schemas"
+)
+
+type userActiveActivityRepo struct{}
+
+func (userActiveActivityRepo) UserActive(context.Context, string) error {
+	return nil
+}
+
+func TestUserExternalLoginService_ExternalLoginTwoFactor(t *testing.T) {
+	ctx := context.TODO()
+	engine, err := data.NewDB(false, &data.Database{
+		Driver: string(schemas.SQLITE), Connection: filepath.Join(t.TempDir(), "answer.db")})
+	require.NoError(t, err)
+	require.NoError(t, migrations.NewMentor(ctx, engine, &migrations.InitNeedUserInputData{
+		Language:      "en_US",
+		SiteName:      "ANSWER",
+		SiteURL:       "http://127.0.0.1:8080/",
+		ContactEmail:  "answer@answer.com",
+		AdminName:     "admin",
+		AdminPassword: "admin",
+		AdminEmail:    "answer@answer.com",
+	}).InitDB())
+	newCache, _, err := data.NewCache(&data.CacheConf{})
+	require.NoError(t, err)
+	dataData, cleanup, err := data.NewData(engine, newCache, nil)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	userRepo := user.NewUserRepo(dataData)
+	siteInfoCommonService := siteinfo_common.NewSiteInfoCommonService(site_info.NewSiteInfo(dataData))
+	userRoleRelService := role2.NewUserRoleRelService(role.NewUserRoleRelRepo(dataData),
+		role2.NewRoleService(role.NewRoleRepo(dataData)))
+	authService := auth2.NewAuthService(auth.NewAuthRepo(dataData), nil)
+	userCommon := usercommon.NewUserCommon(userRepo, userRoleRelService, authService, siteInfoCommonService)
+	userExternalLoginRepo := user_external_login.NewUserExternalLoginRepo(dataData)
+	twoFactorRepo := two_factor.NewTwoFactorRepo(dataData)
+	twoFactorService := two_factor2.NewTwoFactorService(twoFactorRepo, userRepo, userRoleRelService, siteInfoCommonService)
+	userExternalLoginService := user_external_login2.NewUserExternalLoginService(userRepo, userCommon,
+		userExternalLoginRepo, nil, siteInfoCommonService, userActiveActivityRepo{}, nil, userRoleRelService,
+		twoFactorService)
+
+	// the administrator created by the installation signs in with the authenticator app
+	require.NoError(t, twoFactorRepo.SaveUserTwoFactor(ctx, &entity.UserTwoFactor{
+		UserID: "1", TOTPSecret: "ABCDEF", TOTPEnabled: true, RecoveryCodes: `["a"]`}))
+	require.NoError(t, userExternalLoginRepo.AddUserExternalLogin(ctx, &entity.UserExternalLogin{
+		UserID: "1", Provider: "github", ExternalID: "gh-admin"}))
+	_, err = engine.Insert(&entity.User{ID: "2", Username: "user", EMail: "user@answer.com",
+		Status: entity.UserStatusAvailable, MailStatus: entity.EmailStatusAvailable})
+	require.NoError(t, err)
+	require.NoError(t, userExternalLoginRepo.AddUserExternalLogin(ctx, &entity.UserExternalLogin{
+		UserID: "2", Provider: "github", ExternalID: "gh-user"}))
+
+	resp, err := userExternalLoginService.ExternalLogin(ctx, &schema.ExternalLoginUserInfoCache{
+		Provider: "github", ExternalID: "gh-admin"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.AccessToken)
+	require.NotNil(t, resp.TwoFactor)
+	assert.NotEmpty(t, resp.TwoFactor.ChallengeToken)
+	assert.Equal(t, []string{schema.TwoFactorMethodTOTP, schema.TwoFactorMethodRecoveryCode}, resp.TwoFactor.Methods)
+
+	// the page of the challenge gets the same second factors with the challenge token
+	challenge, err := twoFactorService.GetLoginChallenge(ctx, resp.TwoFactor.ChallengeToken)
+	require.NoError(t, err)
+	assert.Equal(t, resp.TwoFactor, challenge)
+
+	// the user without a second factor is signed in directly
+	resp, err = userExternalLoginService.ExternalLogin(ctx, &schema.ExternalLoginUserInfoCache{
+		Provider: "github", ExternalID: "gh-user"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.TwoFactor)
+	assert.NotEmpty(t, resp.AccessToken)
+}
@@ -0,0 +1,177 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package user_session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/apache/answer/internal/base/reason"
+	"github.com/apache/answer/internal/entity"
+	"github.com/apache/answer/internal/schema"
+	"github.com/apache/answer/internal/service/auth"
+	"github.com/apache/answer/internal/service/siteinfo_common"
+	usercommon "github.com/apache/answer/internal/service/user_common"
+	"github.com/apache/answer/pkg/useragent"
+	"github.com/segmentfault/pacman/errors"
+)
+
+// UserSessionService list and sign out the login sessions of the users
+type UserSessionService struct {
+	authService           *auth.AuthService
+	userRepo              usercommon.UserRepo
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService
+}
+
+// NewUserSessionService new user session service
+func NewUserSessionService(
+	authService *auth.AuthService,
+	userRepo usercommon.UserRepo,
+	siteInfoCommonService siteinfo_common.SiteInfoCommonService,
+) *UserSessionService {
+	return &UserSessionService{
+		authService:           authService,
+		userRepo:              userRepo,
+		siteInfoCommonService: siteInfoCommonService,
+	}
+}
+
+// GetUserSessions get the sessions of the user, the latest active one first
+func (us *UserSessionService) GetUserSessions(ctx context.Context, req *schema.GetUserSessionsReq) (
+	resp []*schema.UserSessionResp, err error) {
+	return us.getSessions(ctx, req.UserID, req.AccessToken)
+}
+
+// RemoveUserSession sign out the session of the user
+func (us *UserSessionService) RemoveUserSession(ctx context.Context, req *schema.RemoveUserSessionReq) (err error) {
+	return us.removeSession(ctx, req.UserID, req.ID)
+}
+
+// RemoveOtherUserSessions sign out all the sessions of the user but the current one
+func (us *UserSessionService) RemoveOtherUserSessions(ctx context.Context, req *schema.RemoveOtherUserSessionsReq) (err error) {
+	us.authService.RemoveTokensExceptCurrentUser(ctx, req.UserID, req.AccessToken)
+	return nil
+}
+
+// AdminGetUserSessions get the sessions of any user
+func (us *UserSessionService) AdminGetUserSessions(ctx context.Context, req *schema.AdminGetUserSessionsReq) (
+	resp []*schema.UserSessionResp, err error) {
+	if err = us.checkUserExist(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	return us.getSessions(ctx, req.UserID, "")
+}
+
+// AdminRemoveUserSession sign out the session of any user
+func (us *UserSessionService) AdminRemoveUserSession(ctx context.Context, req *schema.AdminRemoveUserSessionReq) (err error) {
+	if err = us.checkUserExist(ctx, req.UserID); err != nil {
+		return err
+	}
+	return us.removeSession(ctx, req.UserID, req.ID)
+}
+
+// AdminRemoveUserAllSessions sign out all the sessions of any user
+func (us *UserSessionService) AdminRemoveUserAllSessions(ctx context.Context, req *schema.AdminRemoveUserAllSessionsReq) (err error) {
+	if err = us.checkUserExist(ctx, req.UserID); err != nil {
+		return err
+	}
+	us.authService.RemoveUserAllTokens(ctx, req.UserID)
+	return nil
+}
+
+func (us *UserSessionService) getSessions(ctx context.Context, userID, currentToken string) (
+	resp []*schema.UserSessionResp, err error) {
+	sessions, err := us.getLiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp = make([]*schema.UserSessionResp, 0, len(sessions))
+	for token, info := range sessions {
+		resp = append(resp, buildSessionResp(token, info, len(currentToken) > 0 && token == currentToken))
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].LastSeenAt != resp[j].LastSeenAt {
+			return resp[i].LastSeenAt > resp[j].LastSeenAt
+		}
+		return resp[i].ID < resp[j].ID
+	})
+	return resp, nil
+}
+
+func (us *UserSessionService) removeSession(ctx context.Context, userID, sessionID string) (err error) {
+	sessions, err := us.getLiveSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for token := range sessions {
+		if sessionIDOf(token) == sessionID {
+			us.authService.RemoveUserSession(ctx, token)
+			return nil
+		}
+	}
+	return errors.NotFound(reason.UserSessionNotFound)
+}
+
+// getLiveSessions get the sessions within the timeouts of the site security settings
+func (us *UserSessionService) getLiveSessions(ctx context.Context, userID string) (
+	sessions map[string]*entity.UserSessionInfo, err error) {
+	siteSecurity, err := us.siteInfoCommonService.GetSiteSecurity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return us.authService.GetUserSessions(ctx, userID,
+		time.Duration(siteSecurity.SessionIdleTimeout)*time.Minute,
+		time.Duration(siteSecurity.SessionAbsoluteTimeout)*time.Minute)
+}
+
+func (us *UserSessionService) checkUserExist(ctx context.Context, userID string) (err error) {
+	_, exist, err := us.userRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NotFound(reason.UserNotFound)
+	}
+	return nil
+}
+
+func buildSessionResp(token string, info *entity.UserSessionInfo, current bool) *schema.UserSessionResp {
+	device := useragent.Parse(info.UserAgent)
+	return &schema.UserSessionResp{
+		ID:         sessionIDOf(token),
+		CreatedAt:  info.CreatedAt,
+		LastSeenAt: info.LastSeenAt,
+		IP:         info.IP,
+		UserAgent:  info.UserAgent,
+		Browser:    device.Browser,
+		OS:         device.OS,
+		DeviceType: device.DeviceType,
+		Current:    current,
+	}
+}
+
+// sessionIDOf the access token must not be exposed, so the session is identified by its hash
+func sessionIDOf(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:16])
+}
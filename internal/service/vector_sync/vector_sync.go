@@ -66,7 +66,7 @@ func handle(ctx context.Context, data *data.Data, msg *Task) error {
 		vectorSearch = vs
 		return nil
 	})
-	if vectorSearch == nil {
+	if vectorSearch == nil || !plugin.StatusManager.IsEnabledFor(ctx, vectorSearch.Info().SlugName) {
 		return nil
 	}
 
@@ -0,0 +1,236 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package diff
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// maxCells limit the size of the LCS table, if the changed part is too large,
+// it is treated as a whole deletion and insertion.
+const maxCells = 1 << 21
+
+// Segment is a piece of text with the operation to turn the old text into the new one
+type Segment struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Words compare two texts word by word, the whitespaces and punctuations are separate tokens,
+// so joining the equal and delete segments gets the old text and the equal and insert segments gets the new text.
+func Words(oldText, newText string) []*Segment {
+	return merge(compare(split(oldText), split(newText)))
+}
+
+// Strings compare two lists item by item, each segment is one item.
+func Strings(oldList, newList []string) []*Segment {
+	return compare(oldList, newList)
+}
+
+// split the text into words, whitespaces and punctuations, the CJK characters are split one by one
+func split(text string) (res []string) {
+	start := 0
+	for start < len(text) {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		end := start + size
+		switch {
+		case unicode.IsSpace(r):
+			for end < len(text) {
+				next, nextSize := utf8.DecodeRuneInString(text[end:])
+				if !unicode.IsSpace(next) {
+					break
+				}
+				end += nextSize
+			}
+		case isWordRune(r):
+			for end < len(text) {
+				next, nextSize := utf8.DecodeRuneInString(text[end:])
+				if !isWordRune(next) {
+					break
+				}
+				end += nextSize
+			}
+		}
+		res = append(res, text[start:end])
+		start = end
+	}
+	return res
+}
+
+func isWordRune(r rune) bool {
+	if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// compare the tokens by the longest common subsequence, the common prefix and suffix are trimmed first
+func compare(a, b []string) (res []*Segment) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for _, token := range a[:prefix] {
+		res = append(res, &Segment{Op: OpEqual, Text: token})
+	}
+	res = append(res, lcs(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, token := range a[len(a)-suffix:] {
+		res = append(res, &Segment{Op: OpEqual, Text: token})
+	}
+	return res
+}
+
+func lcs(a, b []string) (res []*Segment) {
+	n, m := len(a), len(b)
+	if n*m > maxCells {
+		for _, token := range a {
+			res = append(res, &Segment{Op: OpDelete, Text: token})
+		}
+		for _, token := range b {
+			res = append(res, &Segment{Op: OpInsert, Text: token})
+		}
+		return res
+	}
+
+	// table[i][j] is the length of LCS of a[i:] and b[j:]
+	width := m + 1
+	table := make([]int32, (n+1)*width)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i*width+j] = table[(i+1)*width+j+1] + 1
+			} else {
+				table[i*width+j] = max(table[(i+1)*width+j], table[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			res = append(res, &Segment{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case table[(i+1)*width+j] >= table[i*width+j+1]:
+			res = append(res, &Segment{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			res = append(res, &Segment{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		res = append(res, &Segment{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		res = append(res, &Segment{Op: OpInsert, Text: b[j]})
+	}
+	return res
+}
+
+// merge the adjacent segments with the same operation. The whitespace between two changed words
+// is also regarded as changed, so that the changed phrase is shown as a whole.
+func merge(segments []*Segment) (res []*Segment) {
+	runs := make([]*segmentRun, 0, len(segments))
+	for i := 0; i < len(segments); i++ {
+		seg := segments[i]
+		if seg.Op == OpEqual && isBlank(seg.Text) && i > 0 && i < len(segments)-1 &&
+			segments[i-1].Op != OpEqual && segments[i+1].Op != OpEqual {
+			runs = appendRun(runs, OpDelete, seg.Text)
+			runs = appendRun(runs, OpInsert, seg.Text)
+			continue
+		}
+		runs = appendRun(runs, seg.Op, seg.Text)
+	}
+	return reorder(runs)
+}
+
+// segmentRun the text of the adjacent words with the same operation, it is built once instead of
+// concatenating the words one by one, which is quadratic on the long unchanged parts
+type segmentRun struct {
+	op   Op
+	text strings.Builder
+}
+
+func appendRun(runs []*segmentRun, op Op, text string) []*segmentRun {
+	if len(runs) == 0 || runs[len(runs)-1].op != op {
+		runs = append(runs, &segmentRun{op: op})
+	}
+	runs[len(runs)-1].text.WriteString(text)
+	return runs
+}
+
+// reorder make the deletion in front of the insertion in each changed part
+func reorder(runs []*segmentRun) (res []*Segment) {
+	res = make([]*Segment, 0, len(runs))
+	var deleted, inserted strings.Builder
+	var hasDeleted, hasInserted bool
+	flush := func() {
+		if hasDeleted {
+			res = append(res, &Segment{Op: OpDelete, Text: deleted.String()})
+		}
+		if hasInserted {
+			res = append(res, &Segment{Op: OpInsert, Text: inserted.String()})
+		}
+		deleted.Reset()
+		inserted.Reset()
+		hasDeleted, hasInserted = false, false
+	}
+	for _, run := range runs {
+		switch run.op {
+		case OpDelete:
+			deleted.WriteString(run.text.String())
+			hasDeleted = true
+		case OpInsert:
+			inserted.WriteString(run.text.String())
+			hasInserted = true
+		default:
+			flush()
+			res = append(res, &Segment{Op: run.op, Text: run.text.String()})
+		}
+	}
+	flush()
+	return res
+}
+
+func isBlank(text string) bool {
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func join(segments []*Segment, skip Op) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		if seg.Op != skip {
+			b.WriteString(seg.Text)
+		}
+	}
+	return b.String()
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []*Segment
+	}{
+		{
+			name: "same",
+			old:  "hello world",
+			new:  "hello world",
+			want: []*Segment{{Op: OpEqual, Text: "hello world"}},
+		},
+		{
+			name: "replace word",
+			old:  "how to parse json in go",
+			new:  "how to decode json in golang",
+			want: []*Segment{
+				{Op: OpEqual, Text: "how to "},
+				{Op: OpDelete, Text: "parse"},
+				{Op: OpInsert, Text: "decode"},
+				{Op: OpEqual, Text: " json in "},
+				{Op: OpDelete, Text: "go"},
+				{Op: OpInsert, Text: "golang"},
+			},
+		},
+		{
+			name: "replace phrase",
+			old:  "use the old api now",
+			new:  "use a new function now",
+			want: []*Segment{
+				{Op: OpEqual, Text: "use "},
+				{Op: OpDelete, Text: "the old api"},
+				{Op: OpInsert, Text: "a new function"},
+				{Op: OpEqual, Text: " now"},
+			},
+		},
+		{
+			name: "insert",
+			old:  "",
+			new:  "new text",
+			want: []*Segment{{Op: OpInsert, Text: "new text"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Words(tt.old, tt.new)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.old, join(got, OpInsert))
+			assert.Equal(t, tt.new, join(got, OpDelete))
+		})
+	}
+}
+
+func TestStrings(t *testing.T) {
+	got := Strings([]string{"go", "json", "api"}, []string{"go", "api", "http"})
+	assert.Equal(t, []*Segment{
+		{Op: OpEqual, Text: "go"},
+		{Op: OpDelete, Text: "json"},
+		{Op: OpEqual, Text: "api"},
+		{Op: OpInsert, Text: "http"},
+	}, got)
+}
@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+}
+
+func newTestServer(t *testing.T) *testServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ts := &testServer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 ts.URL,
+			"authorization_endpoint": ts.URL + "/authorize",
+			"token_endpoint":         ts.URL + "/token",
+			"userinfo_endpoint":      ts.URL + "/userinfo",
+			"jwks_uri":               ts.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, _ := r.BasicAuth()
+		if clientID != "client" || secret != "secret" || r.FormValue("code") != "code" ||
+			r.FormValue("code_verifier") != "verifier" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     ts.sign(t, ts.claims()),
+		})
+	})
+	ts.Server = httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *testServer) claims() map[string]any {
+	return map[string]any{
+		"iss":   ts.URL,
+		"sub":   "alice",
+		"aud":   "client",
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": "nonce",
+	}
+}
+
+func (ts *testServer) sign(t *testing.T, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hashed := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, ts.key, crypto.SHA256, hashed[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestExchangeAndVerifyIDToken(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	p, err := Discover(ctx, ts.Client(), ts.URL)
+	require.NoError(t, err)
+
+	token, err := p.Exchange(ctx, "client", "secret", "https://answer.example.com/callback", "code", "verifier")
+	require.NoError(t, err)
+	claims, err := p.VerifyIDToken(ctx, token.IDToken, "client", "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", claims.String("sub"))
+
+	_, err = p.Exchange(ctx, "client", "secret", "https://answer.example.com/callback", "code", "wrong")
+	assert.Error(t, err)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	p, err := Discover(ctx, ts.Client(), ts.URL)
+	require.NoError(t, err)
+
+	t.Run("nonce mismatched", func(t *testing.T) {
+		_, err := p.VerifyIDToken(ctx, ts.sign(t, ts.claims()), "client", "other")
+		assert.Error(t, err)
+	})
+
+	t.Run("other audience", func(t *testing.T) {
+		claims := ts.claims()
+		claims["aud"] = []string{"other"}
+		_, err := p.VerifyIDToken(ctx, ts.sign(t, claims), "client", "nonce")
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		claims := ts.claims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err := p.VerifyIDToken(ctx, ts.sign(t, claims), "client", "nonce")
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		parts := strings.Split(ts.sign(t, ts.claims()), ".")
+		claims := ts.claims()
+		claims["sub"] = "admin"
+		payload, _ := json.Marshal(claims)
+		parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+		_, err := p.VerifyIDToken(ctx, strings.Join(parts, "."), "client", "nonce")
+		assert.Error(t, err)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+		payload, _ := json.Marshal(ts.claims())
+		raw := header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+		_, err := p.VerifyIDToken(ctx, raw, "client", "nonce")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,312 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package redis is a small client of the Redis serialization protocol (RESP2).
+// It only covers what the cache and the invalidation bus need: pooled commands and channel subscription.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Nil is returned when the reply is the null bulk string, e.g. GET a missing key
+var Nil = errors.New("redis: nil")
+
+// ErrClosed is returned when the client or the subscription is closed
+var ErrClosed = errors.New("redis: closed")
+
+// Error the error reply of the server
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// Options the options of the client
+type Options struct {
+	Addr         string
+	Username     string
+	Password     string
+	DB           int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// PoolSize the max idle connections kept in the pool
+	PoolSize int
+}
+
+// Client a goroutine safe redis client with a pool of connections
+type Client struct {
+	opt    Options
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+// NewClient new client, the connections are dialed lazily
+func NewClient(opt Options) *Client {
+	if opt.Addr == "" {
+		opt.Addr = "127.0.0.1:6379"
+	}
+	if opt.DialTimeout <= 0 {
+		opt.DialTimeout = 5 * time.Second
+	}
+	if opt.ReadTimeout <= 0 {
+		opt.ReadTimeout = 3 * time.Second
+	}
+	if opt.WriteTimeout <= 0 {
+		opt.WriteTimeout = opt.ReadTimeout
+	}
+	if opt.PoolSize <= 0 {
+		opt.PoolSize = 10
+	}
+	return &Client{opt: opt}
+}
+
+// Do send the command and read the reply.
+// The reply is a string, an int64, a []any of replies or nil, the null reply is returned as Nil error.
+func (c *Client) Do(ctx context.Context, args ...any) (reply any, err error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err = cn.do(ctx, c.opt, args...)
+	c.put(cn, err)
+	return reply, err
+}
+
+// Ping check the connection of the server
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Close close all idle connections, the client can not be used anymore
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for _, cn := range c.idle {
+		_ = cn.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+	return c.dial(ctx)
+}
+
+// put return the connection to the pool, the connection is dropped when the error is not a server reply
+func (c *Client) put(cn *conn, err error) {
+	var replyErr Error
+	if err != nil && !errors.Is(err, Nil) && !errors.As(err, &replyErr) {
+		_ = cn.Close()
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || len(c.idle) >= c.opt.PoolSize {
+		_ = cn.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	d := net.Dialer{Timeout: c.opt.DialTimeout}
+	nc, err := d.DialContext(ctx, "tcp", c.opt.Addr)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+	if len(c.opt.Password) > 0 {
+		args := []any{"AUTH", c.opt.Password}
+		if len(c.opt.Username) > 0 {
+			args = []any{"AUTH", c.opt.Username, c.opt.Password}
+		}
+		if _, err = cn.do(ctx, c.opt, args...); err != nil {
+			_ = cn.Close()
+			return nil, fmt.Errorf("redis auth failed: %w", err)
+		}
+	}
+	if c.opt.DB > 0 {
+		if _, err = cn.do(ctx, c.opt, "SELECT", c.opt.DB); err != nil {
+			_ = cn.Close()
+			return nil, fmt.Errorf("redis select db failed: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func (cn *conn) do(ctx context.Context, opt Options, args ...any) (any, error) {
+	if err := cn.SetWriteDeadline(deadline(ctx, opt.WriteTimeout)); err != nil {
+		return nil, err
+	}
+	if err := cn.writeCommand(args...); err != nil {
+		return nil, err
+	}
+	if err := cn.SetReadDeadline(deadline(ctx, opt.ReadTimeout)); err != nil {
+		return nil, err
+	}
+	return cn.readReply()
+}
+
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	d := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(d) {
+		return ctxDeadline
+	}
+	return d
+}
+
+func (cn *conn) writeCommand(args ...any) error {
+	_, _ = fmt.Fprintf(cn.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var s string
+		switch v := arg.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		case int:
+			s = strconv.Itoa(v)
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		default:
+			s = fmt.Sprint(v)
+		}
+		_, _ = fmt.Fprintf(cn.w, "$%d\r\n%s\r\n", len(s), s)
+	}
+	return cn.w.Flush()
+}
+
+func (cn *conn) readReply() (any, error) {
+	line, err := cn.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, Nil
+		}
+		buf := make([]byte, n+2)
+		if _, err = io.ReadFull(cn.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad array length %q", line)
+		}
+		if n < 0 {
+			return nil, Nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			items[i], err = cn.readReply()
+			if err != nil && !errors.Is(err, Nil) {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply %q", line)
+	}
+}
+
+func (cn *conn) readLine() (string, error) {
+	line, err := cn.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redis: bad line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
+
+// String convert the reply to string
+func String(reply any, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	switch v := reply.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	default:
+		return "", fmt.Errorf("redis: unexpected reply %T", reply)
+	}
+}
+
+// Int64 convert the reply to int64
+func Int64(reply any, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("redis: unexpected reply %T", reply)
+	}
+}
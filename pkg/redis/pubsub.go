@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Message the message published to the subscribed channel
+type Message struct {
+	Channel string
+	Payload string
+}
+
+// PubSub the subscription of channels on a dedicated connection
+type PubSub struct {
+	cn        *conn
+	closeOnce sync.Once
+}
+
+// Subscribe subscribe the channels on a new connection, the subscription must be closed by the caller
+func (c *Client) Subscribe(ctx context.Context, channels ...string) (*PubSub, error) {
+	cn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]any, 0, len(channels)+1)
+	args = append(args, "SUBSCRIBE")
+	for _, ch := range channels {
+		args = append(args, ch)
+	}
+	if err = cn.SetWriteDeadline(deadline(ctx, c.opt.WriteTimeout)); err == nil {
+		err = cn.writeCommand(args...)
+	}
+	if err == nil {
+		err = cn.SetReadDeadline(deadline(ctx, c.opt.ReadTimeout))
+	}
+	// the server confirms every channel with a subscribe reply
+	for i := 0; err == nil && i < len(channels); i++ {
+		var reply any
+		reply, err = cn.readReply()
+		if err == nil {
+			items, ok := reply.([]any)
+			if !ok || len(items) < 1 || items[0] != "subscribe" {
+				err = fmt.Errorf("redis: unexpected subscribe reply %v", reply)
+			}
+		}
+	}
+	if err != nil {
+		_ = cn.Close()
+		return nil, err
+	}
+	return &PubSub{cn: cn}, nil
+}
+
+// Receive wait for the next message, it blocks until a message arrives or the subscription is closed
+func (ps *PubSub) Receive() (*Message, error) {
+	if err := ps.cn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	for {
+		reply, err := ps.cn.readReply()
+		if err != nil {
+			return nil, err
+		}
+		items, ok := reply.([]any)
+		if !ok || len(items) != 3 || items[0] != "message" {
+			continue
+		}
+		channel, _ := items[1].(string)
+		payload, _ := items[2].(string)
+		return &Message{Channel: channel, Payload: payload}, nil
+	}
+}
+
+// Close close the subscription, the blocked Receive returns with an error
+func (ps *PubSub) Close() (err error) {
+	ps.closeOnce.Do(func() {
+		err = ps.cn.Close()
+	})
+	return err
+}
+
+// Publish publish the message to the channel, returns the number of the subscribers received it
+func (c *Client) Publish(ctx context.Context, channel, payload string) (int64, error) {
+	return Int64(c.Do(ctx, "PUBLISH", channel, payload))
+}
@@ -0,0 +1,330 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package redistest is an in-memory stand-in of the redis server for the tests.
+// It speaks RESP2 and implements the commands used by the cache and the invalidation bus.
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server the in-memory redis server listened on a random local port
+type Server struct {
+	ln       net.Listener
+	password string
+
+	mu          sync.Mutex
+	values      map[string]string
+	expires     map[string]time.Time
+	subscribers map[string]map[*client]bool
+	clients     map[*client]bool
+}
+
+type client struct {
+	net.Conn
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+// Run start a server, requirePass is the password of AUTH, empty means no authentication
+func Run(requirePass string) (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		ln:          ln,
+		password:    requirePass,
+		values:      make(map[string]string),
+		expires:     make(map[string]time.Time),
+		subscribers: make(map[string]map[*client]bool),
+		clients:     make(map[*client]bool),
+	}
+	go s.serve()
+	return s, nil
+}
+
+// Addr the listened address
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stop the server and close all connections
+func (s *Server) Close() {
+	_ = s.ln.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		_ = c.Close()
+	}
+}
+
+// Get the value of the key in the server
+func (s *Server) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.get(key)
+}
+
+// FastForward move the clock of the expiration forward
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, t := range s.expires {
+		s.expires[k] = t.Add(-d)
+	}
+}
+
+func (s *Server) serve() {
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		c := &client{Conn: nc, w: bufio.NewWriter(nc)}
+		s.mu.Lock()
+		s.clients[c] = true
+		s.mu.Unlock()
+		go s.handle(c)
+	}
+}
+
+func (s *Server) handle(c *client) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		for _, subs := range s.subscribers {
+			delete(subs, c)
+		}
+		s.mu.Unlock()
+		_ = c.Close()
+	}()
+	r := bufio.NewReader(c)
+	authed := len(s.password) == 0
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		cmd := strings.ToUpper(args[0])
+		if !authed && cmd != "AUTH" {
+			c.write("-NOAUTH Authentication required.\r\n")
+			continue
+		}
+		if cmd == "AUTH" {
+			if args[len(args)-1] != s.password {
+				c.write("-WRONGPASS invalid username-password pair\r\n")
+				continue
+			}
+			authed = true
+			c.write("+OK\r\n")
+			continue
+		}
+		c.write(s.exec(c, cmd, args[1:]))
+	}
+}
+
+func (s *Server) exec(c *client, cmd string, args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch cmd {
+	case "PING":
+		return "+PONG\r\n"
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		if len(args) != 1 {
+			return wrongArgs(cmd)
+		}
+		v, ok := s.get(args[0])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(v)
+	case "SET":
+		if len(args) < 2 {
+			return wrongArgs(cmd)
+		}
+		s.values[args[0]] = args[1]
+		delete(s.expires, args[0])
+		if len(args) == 4 {
+			n, err := strconv.ParseInt(args[3], 10, 64)
+			if err != nil || n <= 0 {
+				return "-ERR invalid expire time in 'set' command\r\n"
+			}
+			unit := time.Millisecond
+			if strings.ToUpper(args[2]) == "EX" {
+				unit = time.Second
+			}
+			s.expires[args[0]] = time.Now().Add(time.Duration(n) * unit)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		var n int
+		for _, k := range args {
+			if _, ok := s.get(k); ok {
+				n++
+			}
+			delete(s.values, k)
+			delete(s.expires, k)
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "INCRBY", "DECRBY":
+		if len(args) != 2 {
+			return wrongArgs(cmd)
+		}
+		delta, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		v, _ := s.get(args[0])
+		if v == "" {
+			v = "0"
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		if cmd == "DECRBY" {
+			delta = -delta
+		}
+		n += delta
+		s.values[args[0]] = strconv.FormatInt(n, 10)
+		return fmt.Sprintf(":%d\r\n", n)
+	case "SCAN":
+		// all matched keys are returned in one round
+		pattern := "*"
+		for i := 1; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+		keys := make([]string, 0)
+		for k := range s.values {
+			if _, ok := s.get(k); !ok {
+				continue
+			}
+			if ok, _ := path.Match(pattern, k); ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("*2\r\n" + bulk("0"))
+		fmt.Fprintf(&b, "*%d\r\n", len(keys))
+		for _, k := range keys {
+			b.WriteString(bulk(k))
+		}
+		return b.String()
+	case "FLUSHDB", "FLUSHALL":
+		s.values = make(map[string]string)
+		s.expires = make(map[string]time.Time)
+		return "+OK\r\n"
+	case "PUBLISH":
+		if len(args) != 2 {
+			return wrongArgs(cmd)
+		}
+		msg := "*3\r\n" + bulk("message") + bulk(args[0]) + bulk(args[1])
+		for sub := range s.subscribers[args[0]] {
+			sub.write(msg)
+		}
+		return fmt.Sprintf(":%d\r\n", len(s.subscribers[args[0]]))
+	case "SUBSCRIBE":
+		var b strings.Builder
+		for i, ch := range args {
+			if s.subscribers[ch] == nil {
+				s.subscribers[ch] = make(map[*client]bool)
+			}
+			s.subscribers[ch][c] = true
+			fmt.Fprintf(&b, "*3\r\n%s%s:%d\r\n", bulk("subscribe"), bulk(ch), i+1)
+		}
+		return b.String()
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", cmd)
+	}
+}
+
+func (s *Server) get(key string) (string, bool) {
+	if t, ok := s.expires[key]; ok && !time.Now().Before(t) {
+		delete(s.values, key)
+		delete(s.expires, key)
+	}
+	v, ok := s.values[key]
+	return v, ok
+}
+
+func (c *client) write(reply string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = c.w.WriteString(reply)
+	_ = c.w.Flush()
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func wrongArgs(cmd string) string {
+	return fmt.Sprintf("-ERR wrong number of arguments for '%s' command\r\n", strings.ToLower(cmd))
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err = r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimRight(line, "\r\n")[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
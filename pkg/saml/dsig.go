@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package saml
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	nsDSig       = "http://www.w3.org/2000/09/xmldsig#"
+	nsExcC14N    = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnveloped = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+)
+
+var signatureAlgorithms = map[string]crypto.Hash{
+	"http://www.w3.org/2000/09/xmldsig#rsa-sha1":        crypto.SHA1,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha256": crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha512": crypto.SHA512,
+}
+
+var digestAlgorithms = map[string]crypto.Hash{
+	"http://www.w3.org/2000/09/xmldsig#sha1":        crypto.SHA1,
+	"http://www.w3.org/2001/04/xmlenc#sha256":       crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#sha512": crypto.SHA512,
+	"http://www.w3.org/2001/04/xmlenc#sha512":       crypto.SHA512,
+}
+
+// hasSignature whether the element carries the enveloped signature
+func hasSignature(el *node) bool {
+	return el.Child(nsDSig, "Signature") != nil
+}
+
+// verifySignature verify the enveloped signature of the element with the certificates of the identity provider.
+// Only the element itself is covered, so the caller must read the data from this element and nothing else.
+func verifySignature(el *node, certs []*x509.Certificate) error {
+	signature := el.Child(nsDSig, "Signature")
+	if signature == nil {
+		return fmt.Errorf("the element %s is not signed", el.Local)
+	}
+	signedInfo := signature.Child(nsDSig, "SignedInfo")
+	if signedInfo == nil {
+		return fmt.Errorf("the signature has no SignedInfo")
+	}
+	c14nMethod := signedInfo.Child(nsDSig, "CanonicalizationMethod")
+	if c14nMethod == nil || c14nMethod.Attr("Algorithm") != nsExcC14N {
+		return fmt.Errorf("unsupported canonicalization method")
+	}
+	signatureMethod := signedInfo.Child(nsDSig, "SignatureMethod")
+	if signatureMethod == nil {
+		return fmt.Errorf("the signature has no SignatureMethod")
+	}
+	signatureHash, ok := signatureAlgorithms[signatureMethod.Attr("Algorithm")]
+	if !ok {
+		return fmt.Errorf("unsupported signature method %s", signatureMethod.Attr("Algorithm"))
+	}
+
+	references := signedInfo.ChildrenOf(nsDSig, "Reference")
+	if len(references) != 1 {
+		return fmt.Errorf("the signature must have exactly one reference")
+	}
+	reference := references[0]
+	id := el.Attr("ID")
+	if len(id) == 0 || reference.Attr("URI") != "#"+id {
+		return fmt.Errorf("the signature does not reference the element %s", el.Local)
+	}
+	var inclusivePrefixes []string
+	if transforms := reference.Child(nsDSig, "Transforms"); transforms != nil {
+		for _, transform := range transforms.ChildrenOf(nsDSig, "Transform") {
+			switch transform.Attr("Algorithm") {
+			case algEnveloped:
+			case nsExcC14N:
+				inclusivePrefixes = prefixList(transform)
+			default:
+				return fmt.Errorf("unsupported transform %s", transform.Attr("Algorithm"))
+			}
+		}
+	}
+	digestMethod := reference.Child(nsDSig, "DigestMethod")
+	if digestMethod == nil {
+		return fmt.Errorf("the reference has no DigestMethod")
+	}
+	digestHash, ok := digestAlgorithms[digestMethod.Attr("Algorithm")]
+	if !ok {
+		return fmt.Errorf("unsupported digest method %s", digestMethod.Attr("Algorithm"))
+	}
+	digestValue := reference.Child(nsDSig, "DigestValue")
+	if digestValue == nil {
+		return fmt.Errorf("the reference has no DigestValue")
+	}
+	expectedDigest, err := decodeBase64(digestValue.Text())
+	if err != nil {
+		return fmt.Errorf("malformed digest value: %w", err)
+	}
+	h := digestHash.New()
+	h.Write(canonicalize(el, inclusivePrefixes, signature))
+	if !bytes.Equal(h.Sum(nil), expectedDigest) {
+		return fmt.Errorf("the digest of the element %s mismatched", el.Local)
+	}
+
+	signatureValue := signature.Child(nsDSig, "SignatureValue")
+	if signatureValue == nil {
+		return fmt.Errorf("the signature has no SignatureValue")
+	}
+	sig, err := decodeBase64(signatureValue.Text())
+	if err != nil {
+		return fmt.Errorf("malformed signature value: %w", err)
+	}
+	h = signatureHash.New()
+	h.Write(canonicalize(signedInfo, prefixList(c14nMethod), nil))
+	digest := h.Sum(nil)
+	for _, cert := range certs {
+		pub, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		if rsa.VerifyPKCS1v15(pub, signatureHash, digest, sig) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("the signature is not made by the certificate of the identity provider")
+}
+
+func prefixList(transform *node) []string {
+	inclusive := transform.Child(nsExcC14N, "InclusiveNamespaces")
+	if inclusive == nil {
+		return nil
+	}
+	return strings.Fields(inclusive.Attr("PrefixList"))
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,308 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package saml is the service provider of SAML 2.0 web browser SSO.
+// The authentication request is sent with the HTTP-Redirect binding and the response is received with the HTTP-POST binding.
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+
+	statusSuccess        = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	bindingHTTPPost      = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	confirmationBearer   = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	nameIDFormatUnspec   = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	allowedClockSkew     = 2 * time.Minute
+	samlTimeFormatLayout = "2006-01-02T15:04:05Z"
+)
+
+// ServiceProvider the site as the service provider of the identity provider
+type ServiceProvider struct {
+	// EntityID the entity id of the site, it is the url of the metadata by convention
+	EntityID string
+	// ACSURL the assertion consumer service url receiving the response
+	ACSURL string
+	// IdPEntityID the issuer of the assertions
+	IdPEntityID string
+	// IdPSSOURL the single sign-on service url of the identity provider with the HTTP-Redirect binding
+	IdPSSOURL string
+	// IdPCertificates the certificates signing the responses or the assertions
+	IdPCertificates []*x509.Certificate
+}
+
+// Assertion the authenticated subject of the response
+type Assertion struct {
+	NameID       string
+	SessionIndex string
+	Attributes   map[string][]string
+}
+
+// ParseCertificates parse the certificates in PEM, or the base64 of DER as it is copied from the metadata
+func ParseCertificates(data string) (certs []*x509.Certificate, err error) {
+	rest := []byte(strings.TrimSpace(data))
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) > 0 {
+		return certs, nil
+	}
+	der, err := decodeBase64(data)
+	if err != nil {
+		return nil, fmt.Errorf("the certificate is neither PEM nor base64: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return []*x509.Certificate{cert}, nil
+}
+
+// NewRequestID the id of the authentication request, it must not start with a digit
+func NewRequestID() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return "id-" + hex.EncodeToString(b)
+}
+
+// AuthnRequestURL the url redirecting the user to the identity provider with the authentication request
+func (sp *ServiceProvider) AuthnRequestURL(requestID, relayState string) (string, error) {
+	request := fmt.Sprintf(`<samlp:AuthnRequest xmlns:samlp="%s" xmlns:saml="%s" ID="%s" Version="2.0" `+
+		`IssueInstant="%s" Destination="%s" AssertionConsumerServiceURL="%s" ProtocolBinding="%s">`+
+		`<saml:Issuer>%s</saml:Issuer><samlp:NameIDPolicy Format="%s" AllowCreate="true"/></samlp:AuthnRequest>`,
+		nsProtocol, nsAssertion, requestID, time.Now().UTC().Format(samlTimeFormatLayout),
+		html.EscapeString(sp.IdPSSOURL), html.EscapeString(sp.ACSURL), bindingHTTPPost,
+		html.EscapeString(sp.EntityID), nameIDFormatUnspec)
+
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	if _, err := w.Write([]byte(request)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	q.Set("SAMLRequest", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	q.Set("RelayState", relayState)
+	sep := "?"
+	if strings.Contains(sp.IdPSSOURL, "?") {
+		sep = "&"
+	}
+	return sp.IdPSSOURL + sep + q.Encode(), nil
+}
+
+// Metadata the metadata of the service provider for the identity provider
+func (sp *ServiceProvider) Metadata() []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="%s">
+  <md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="%s">
+    <md:NameIDFormat>%s</md:NameIDFormat>
+    <md:AssertionConsumerService Binding="%s" Location="%s" index="0" isDefault="true"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>
+`, html.EscapeString(sp.EntityID), nsProtocol, nameIDFormatUnspec, bindingHTTPPost, html.EscapeString(sp.ACSURL)))
+}
+
+// ParseResponse verify the base64 response posted to the assertion consumer service.
+// The response must answer the request of the id, the unsolicited response is not accepted.
+func (sp *ServiceProvider) ParseResponse(samlResponse, requestID string) (*Assertion, error) {
+	data, err := decodeBase64(samlResponse)
+	if err != nil {
+		return nil, fmt.Errorf("malformed response: %w", err)
+	}
+	response, err := parseXML(data)
+	if err != nil {
+		return nil, fmt.Errorf("malformed response: %w", err)
+	}
+	if !response.Is(nsProtocol, "Response") {
+		return nil, fmt.Errorf("the document is not a response")
+	}
+	if destination := response.Attr("Destination"); len(destination) > 0 && destination != sp.ACSURL {
+		return nil, fmt.Errorf("the response is sent to %s", destination)
+	}
+	if len(requestID) == 0 || response.Attr("InResponseTo") != requestID {
+		return nil, fmt.Errorf("the response does not answer the request")
+	}
+	status := response.Child(nsProtocol, "Status")
+	if status == nil || status.Child(nsProtocol, "StatusCode") == nil {
+		return nil, fmt.Errorf("the response has no status")
+	}
+	if code := status.Child(nsProtocol, "StatusCode").Attr("Value"); code != statusSuccess {
+		message := ""
+		if m := status.Child(nsProtocol, "StatusMessage"); m != nil {
+			message = m.Text()
+		}
+		return nil, fmt.Errorf("the identity provider failed: %s %s", code, message)
+	}
+	if issuer := response.Child(nsAssertion, "Issuer"); issuer != nil && strings.TrimSpace(issuer.Text()) != sp.IdPEntityID {
+		return nil, fmt.Errorf("the response is issued by %s", issuer.Text())
+	}
+	if response.Child(nsAssertion, "EncryptedAssertion") != nil {
+		return nil, fmt.Errorf("the encrypted assertion is not supported")
+	}
+	assertions := response.ChildrenOf(nsAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return nil, fmt.Errorf("the response must have exactly one assertion")
+	}
+	assertion := assertions[0]
+
+	// the signature of the response covers the assertion, otherwise the assertion itself must be signed
+	signed := false
+	for _, el := range []*node{response, assertion} {
+		if !hasSignature(el) {
+			continue
+		}
+		if err = verifySignature(el, sp.IdPCertificates); err != nil {
+			return nil, err
+		}
+		signed = true
+	}
+	if !signed {
+		return nil, fmt.Errorf("neither the response nor the assertion is signed")
+	}
+	return sp.parseAssertion(assertion, requestID, time.Now())
+}
+
+func (sp *ServiceProvider) parseAssertion(assertion *node, requestID string, now time.Time) (*Assertion, error) {
+	issuer := assertion.Child(nsAssertion, "Issuer")
+	if issuer == nil || strings.TrimSpace(issuer.Text()) != sp.IdPEntityID {
+		return nil, fmt.Errorf("the assertion is not issued by %s", sp.IdPEntityID)
+	}
+
+	if conditions := assertion.Child(nsAssertion, "Conditions"); conditions != nil {
+		if err := checkTimeRange(conditions, now); err != nil {
+			return nil, err
+		}
+		for _, restriction := range conditions.ChildrenOf(nsAssertion, "AudienceRestriction") {
+			matched := false
+			for _, audience := range restriction.ChildrenOf(nsAssertion, "Audience") {
+				matched = matched || strings.TrimSpace(audience.Text()) == sp.EntityID
+			}
+			if !matched {
+				return nil, fmt.Errorf("the assertion is not issued to %s", sp.EntityID)
+			}
+		}
+	}
+
+	subject := assertion.Child(nsAssertion, "Subject")
+	if subject == nil || subject.Child(nsAssertion, "NameID") == nil {
+		return nil, fmt.Errorf("the assertion has no subject")
+	}
+	confirmed := false
+	for _, confirmation := range subject.ChildrenOf(nsAssertion, "SubjectConfirmation") {
+		data := confirmation.Child(nsAssertion, "SubjectConfirmationData")
+		if confirmation.Attr("Method") != confirmationBearer || data == nil {
+			continue
+		}
+		if data.Attr("Recipient") != sp.ACSURL {
+			continue
+		}
+		if inResponseTo := data.Attr("InResponseTo"); len(inResponseTo) > 0 && inResponseTo != requestID {
+			continue
+		}
+		notOnOrAfter, err := parseTime(data.Attr("NotOnOrAfter"))
+		if err != nil || !now.Before(notOnOrAfter.Add(allowedClockSkew)) {
+			continue
+		}
+		confirmed = true
+	}
+	if !confirmed {
+		return nil, fmt.Errorf("the subject of the assertion is not confirmed")
+	}
+
+	result := &Assertion{
+		NameID:     strings.TrimSpace(subject.Child(nsAssertion, "NameID").Text()),
+		Attributes: make(map[string][]string),
+	}
+	if len(result.NameID) == 0 {
+		return nil, fmt.Errorf("the assertion has no subject")
+	}
+	if statement := assertion.Child(nsAssertion, "AuthnStatement"); statement != nil {
+		result.SessionIndex = statement.Attr("SessionIndex")
+	}
+	for _, statement := range assertion.ChildrenOf(nsAssertion, "AttributeStatement") {
+		for _, attribute := range statement.ChildrenOf(nsAssertion, "Attribute") {
+			var values []string
+			for _, value := range attribute.ChildrenOf(nsAssertion, "AttributeValue") {
+				values = append(values, strings.TrimSpace(value.Text()))
+			}
+			for _, name := range []string{attribute.Attr("Name"), attribute.Attr("FriendlyName")} {
+				if len(name) > 0 {
+					result.Attributes[name] = append(result.Attributes[name], values...)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func checkTimeRange(conditions *node, now time.Time) error {
+	if v := conditions.Attr("NotBefore"); len(v) > 0 {
+		notBefore, err := parseTime(v)
+		if err != nil {
+			return err
+		}
+		if now.Add(allowedClockSkew).Before(notBefore) {
+			return fmt.Errorf("the assertion is not yet valid")
+		}
+	}
+	if v := conditions.Attr("NotOnOrAfter"); len(v) > 0 {
+		notOnOrAfter, err := parseTime(v)
+		if err != nil {
+			return err
+		}
+		if !now.Before(notOnOrAfter.Add(allowedClockSkew)) {
+			return fmt.Errorf("the assertion is expired")
+		}
+	}
+	return nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed time %s", v)
+	}
+	return t, nil
+}
@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+package saml
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIdP       = "https://idp.example.com/metadata"
+	testEntityID  = "https://answer.example.com/answer/api/v1/sso/saml/corp/metadata"
+	testACSURL    = "https://answer.example.com/answer/api/v1/sso/saml/corp/acs"
+	testRequestID = "id-request"
+)
+
+type testIdentityProvider struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+	pem  string
+}
+
+func newTestIdentityProvider(t *testing.T) *testIdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testIdentityProvider{
+		key:  key,
+		cert: cert,
+		pem:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+func (idp *testIdentityProvider) serviceProvider(t *testing.T) *ServiceProvider {
+	certs, err := ParseCertificates(idp.pem)
+	require.NoError(t, err)
+	return &ServiceProvider{
+		EntityID:        testEntityID,
+		ACSURL:          testACSURL,
+		IdPEntityID:     testIdP,
+		IdPCertificates: certs,
+	}
+}
+
+// assertion the assertion with the placeholder {{signature}} after the issuer
+func (idp *testIdentityProvider) assertion(audience string) string {
+	now := time.Now().UTC()
+	return fmt.Sprintf(`<saml:Assertion xmlns:saml="%s" ID="id-assertion" Version="2.0" IssueInstant="%s">`+
+		`<saml:Issuer>%s</saml:Issuer>{{signature}}`+
+		`<saml:Subject><saml:NameID>alice</saml:NameID>`+
+		`<saml:SubjectConfirmation Method="%s"><saml:SubjectConfirmationData InResponseTo="%s" NotOnOrAfter="%s" Recipient="%s"/></saml:SubjectConfirmation></saml:Subject>`+
+		`<saml:Conditions NotBefore="%s" NotOnOrAfter="%s"><saml:AudienceRestriction><saml:Audience>%s</saml:Audience></saml:AudienceRestriction></saml:Conditions>`+
+		`<saml:AuthnStatement AuthnInstant="%s" SessionIndex="session-1"/>`+
+		`<saml:AttributeStatement><saml:Attribute Name="email"><saml:AttributeValue>alice@example.com</saml:AttributeValue></saml:Attribute>`+
+		`<saml:Attribute Name="groups"><saml:AttributeValue>staff</saml:AttributeValue><saml:AttributeValue>admins</saml:AttributeValue></saml:Attribute>`+
+		`</saml:AttributeStatement></saml:Assertion>`,
+		nsAssertion, now.Format(samlTimeFormatLayout), testIdP, confirmationBearer, testRequestID,
+		now.Add(5*time.Minute).Format(samlTimeFormatLayout), testACSURL,
+		now.Add(-time.Minute).Format(samlTimeFormatLayout), now.Add(5*time.Minute).Format(samlTimeFormatLayout),
+		audience, now.Format(samlTimeFormatLayout))
+}
+
+// sign replace the placeholder with the enveloped signature of the assertion
+func (idp *testIdentityProvider) sign(t *testing.T, assertion string) string {
+	el, err := parseXML([]byte(strings.Replace(assertion, "{{signature}}", "", 1)))
+	require.NoError(t, err)
+	digest := sha256.Sum256(canonicalize(el, nil, nil))
+	signedInfo := fmt.Sprintf(`<ds:SignedInfo><ds:CanonicalizationMethod Algorithm="%s"/>`+
+		`<ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"/>`+
+		`<ds:Reference URI="#%s"><ds:Transforms><ds:Transform Algorithm="%s"/><ds:Transform Algorithm="%s"/></ds:Transforms>`+
+		`<ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"/><ds:DigestValue>%s</ds:DigestValue></ds:Reference></ds:SignedInfo>`,
+		nsExcC14N, el.Attr("ID"), algEnveloped, nsExcC14N, base64.StdEncoding.EncodeToString(digest[:]))
+
+	signature, err := parseXML([]byte(fmt.Sprintf(`<ds:Signature xmlns:ds="%s">%s</ds:Signature>`, nsDSig, signedInfo)))
+	require.NoError(t, err)
+	hashed := sha256.Sum256(canonicalize(signature.Child(nsDSig, "SignedInfo"), nil, nil))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, hashed[:])
+	require.NoError(t, err)
+	return strings.Replace(assertion, "{{signature}}", fmt.Sprintf(
+		`<ds:Signature xmlns:ds="%s">%s<ds:SignatureValue>%s</ds:SignatureValue></ds:Signature>`,
+		nsDSig, signedInfo, base64.StdEncoding.EncodeToString(sig)), 1)
+}
+
+func response(assertion string) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(
+		`<samlp:Response xmlns:samlp="%s" ID="id-response" Version="2.0" Destination="%s" InResponseTo="%s">`+
+			`<saml:Issuer xmlns:saml="%s">%s</saml:Issuer><samlp:Status><samlp:StatusCode Value="%s"/></samlp:Status>%s</samlp:Response>`,
+		nsProtocol, testACSURL, testRequestID, nsAssertion, testIdP, statusSuccess, assertion)))
+}
+
+func TestParseResponse(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	sp := idp.serviceProvider(t)
+
+	t.Run("signed assertion", func(t *testing.T) {
+		assertion, err := sp.ParseResponse(response(idp.sign(t, idp.assertion(testEntityID))), testRequestID)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", assertion.NameID)
+		assert.Equal(t, "session-1", assertion.SessionIndex)
+		assert.Equal(t, []string{"alice@example.com"}, assertion.Attributes["email"])
+		assert.Equal(t, []string{"staff", "admins"}, assertion.Attributes["groups"])
+	})
+
+	t.Run("tampered assertion", func(t *testing.T) {
+		signed := strings.Replace(idp.sign(t, idp.assertion(testEntityID)), "<saml:NameID>alice", "<saml:NameID>admin", 1)
+		_, err := sp.ParseResponse(response(signed), testRequestID)
+		assert.Error(t, err)
+	})
+
+	t.Run("unsigned assertion", func(t *testing.T) {
+		unsigned := strings.Replace(idp.assertion(testEntityID), "{{signature}}", "", 1)
+		_, err := sp.ParseResponse(response(unsigned), testRequestID)
+		assert.Error(t, err)
+	})
+
+	t.Run("signed by another key", func(t *testing.T) {
+		other := newTestIdentityProvider(t)
+		_, err := sp.ParseResponse(response(other.sign(t, idp.assertion(testEntityID))), testRequestID)
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		_, err := sp.ParseResponse(response(idp.sign(t, idp.assertion("https://other.example.com"))), testRequestID)
+		assert.Error(t, err)
+	})
+
+	t.Run("unsolicited response", func(t *testing.T) {
+		_, err := sp.ParseResponse(response(idp.sign(t, idp.assertion(testEntityID))), "id-another-request")
+		assert.Error(t, err)
+	})
+}
+
+func TestCanonicalize(t *testing.T) {
+	root, err := parseXML([]byte(`<a:Root xmlns:a="urn:a" xmlns:b="urn:b" xmlns:c="urn:c">` +
+		`<a:Child z="2"  b:attr="1" y="3"><b:Leaf>x &amp; y</b:Leaf><a:Empty/></a:Child></a:Root>`))
+	require.NoError(t, err)
+	child := root.Child("urn:a", "Child")
+	require.NotNil(t, child)
+	assert.Equal(t, `<a:Child xmlns:a="urn:a" xmlns:b="urn:b" y="3" z="2" b:attr="1">`+
+		`<b:Leaf>x &amp; y</b:Leaf><a:Empty></a:Empty></a:Child>`, string(canonicalize(child, nil, nil)))
+	assert.Equal(t, `<a:Child xmlns:a="urn:a" xmlns:b="urn:b" xmlns:c="urn:c" y="3" z="2" b:attr="1">`+
+		`<b:Leaf>x &amp; y</b:Leaf><a:Empty></a:Empty></a:Child>`, string(canonicalize(child, []string{"c"}, nil)))
+}
+
+func TestParseXMLRejectsDoctype(t *testing.T) {
+	_, err := parseXML([]byte(`<!DOCTYPE r [<!ENTITY x "y">]><r>&x;</r>`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,292 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// node the element of the document, the prefixes and the namespace declarations are kept as they are written
+// because the canonicalization of the signature depends on them
+type node struct {
+	Prefix   string
+	Local    string
+	Attrs    []xml.Attr
+	Children []any
+	Parent   *node
+}
+
+type procInst struct {
+	Target string
+	Inst   string
+}
+
+// parseXML parse the document into the tree, the document type declaration is rejected
+func parseXML(data []byte) (*node, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var root, current *node
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{Prefix: t.Name.Space, Local: t.Name.Local, Attrs: t.Copy().Attr, Parent: current}
+			if current == nil {
+				if root != nil {
+					return nil, fmt.Errorf("more than one root element")
+				}
+				root = n
+			} else {
+				current.Children = append(current.Children, n)
+			}
+			current = n
+		case xml.EndElement:
+			if current == nil {
+				return nil, fmt.Errorf("unexpected end element %s", t.Name.Local)
+			}
+			current = current.Parent
+		case xml.CharData:
+			if current != nil {
+				current.Children = append(current.Children, string(t))
+			}
+		case xml.ProcInst:
+			if current != nil {
+				current.Children = append(current.Children, procInst{Target: t.Target, Inst: string(t.Inst)})
+			}
+		case xml.Directive:
+			return nil, fmt.Errorf("the document type declaration is not allowed")
+		}
+	}
+	if root == nil || current != nil {
+		return nil, fmt.Errorf("incomplete document")
+	}
+	return root, nil
+}
+
+// namespace resolve the namespace of the prefix in the scope of the node
+func (n *node) namespace(prefix string) string {
+	for e := n; e != nil; e = e.Parent {
+		for _, a := range e.Attrs {
+			if (prefix == "" && a.Name.Space == "" && a.Name.Local == "xmlns") ||
+				(prefix != "" && a.Name.Space == "xmlns" && a.Name.Local == prefix) {
+				return a.Value
+			}
+		}
+	}
+	if prefix == "xml" {
+		return "http://www.w3.org/XML/1998/namespace"
+	}
+	return ""
+}
+
+// Space the namespace of the element
+func (n *node) Space() string {
+	return n.namespace(n.Prefix)
+}
+
+// Is whether the element is the name in the namespace
+func (n *node) Is(space, local string) bool {
+	return n.Local == local && n.Space() == space
+}
+
+// Attr the value of the attribute without prefix
+func (n *node) Attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Child the first child element of the name
+func (n *node) Child(space, local string) *node {
+	for _, c := range n.Children {
+		if e, ok := c.(*node); ok && e.Is(space, local) {
+			return e
+		}
+	}
+	return nil
+}
+
+// ChildrenOf the child elements of the name
+func (n *node) ChildrenOf(space, local string) (elements []*node) {
+	for _, c := range n.Children {
+		if e, ok := c.(*node); ok && e.Is(space, local) {
+			elements = append(elements, e)
+		}
+	}
+	return elements
+}
+
+// Text the text content of the element and its descendants
+func (n *node) Text() string {
+	var b strings.Builder
+	for _, c := range n.Children {
+		switch v := c.(type) {
+		case string:
+			b.WriteString(v)
+		case *node:
+			b.WriteString(v.Text())
+		}
+	}
+	return b.String()
+}
+
+func isNamespaceDecl(a xml.Attr) bool {
+	return a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns")
+}
+
+// canonicalize the element with Exclusive XML Canonicalization 1.0 without comments.
+// The excluded element, the enveloped signature, is left out of the output.
+func canonicalize(n *node, inclusivePrefixes []string, excluded *node) []byte {
+	var b bytes.Buffer
+	inclusive := make(map[string]bool, len(inclusivePrefixes))
+	for _, p := range inclusivePrefixes {
+		if p == "#default" {
+			p = ""
+		}
+		inclusive[p] = true
+	}
+	writeCanonical(&b, n, map[string]string{}, inclusive, excluded)
+	return b.Bytes()
+}
+
+func writeCanonical(b *bytes.Buffer, n *node, rendered map[string]string, inclusive map[string]bool,
+	excluded *node) {
+	// the namespaces visibly utilized by the element and its attributes, and the inclusive ones in scope
+	utilized := map[string]bool{n.Prefix: true}
+	for _, a := range n.Attrs {
+		if !isNamespaceDecl(a) && a.Name.Space != "" && a.Name.Space != "xml" {
+			utilized[a.Name.Space] = true
+		}
+	}
+	for p := range inclusive {
+		if p == "" || n.namespace(p) != "" {
+			utilized[p] = true
+		}
+	}
+	prefixes := make([]string, 0, len(utilized))
+	for p := range utilized {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+
+	scope := rendered
+	var decls []string
+	for _, p := range prefixes {
+		uri := n.namespace(p)
+		if p == "" && uri == "" {
+			// the empty default namespace is only rendered to undeclare the one of the output ancestor
+			if rendered[""] == "" {
+				continue
+			}
+		} else if prev, ok := rendered[p]; ok && prev == uri {
+			continue
+		} else if p != "" && uri == "" {
+			continue
+		}
+		if len(decls) == 0 {
+			scope = make(map[string]string, len(rendered)+1)
+			for k, v := range rendered {
+				scope[k] = v
+			}
+		}
+		scope[p] = uri
+		if p == "" {
+			decls = append(decls, fmt.Sprintf(` xmlns="%s"`, escapeAttr(uri)))
+		} else {
+			decls = append(decls, fmt.Sprintf(` xmlns:%s="%s"`, p, escapeAttr(uri)))
+		}
+	}
+
+	type attr struct {
+		space, name, value string
+	}
+	attrs := make([]attr, 0, len(n.Attrs))
+	for _, a := range n.Attrs {
+		if isNamespaceDecl(a) {
+			continue
+		}
+		name, space := a.Name.Local, ""
+		if a.Name.Space != "" {
+			name = a.Name.Space + ":" + a.Name.Local
+			space = n.namespace(a.Name.Space)
+		}
+		attrs = append(attrs, attr{space: space + " " + a.Name.Local, name: name, value: a.Value})
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].space < attrs[j].space
+	})
+
+	qname := n.Local
+	if n.Prefix != "" {
+		qname = n.Prefix + ":" + n.Local
+	}
+	b.WriteString("<" + qname)
+	for _, d := range decls {
+		b.WriteString(d)
+	}
+	for _, a := range attrs {
+		b.WriteString(fmt.Sprintf(` %s="%s"`, a.name, escapeAttr(a.value)))
+	}
+	b.WriteString(">")
+	for _, c := range n.Children {
+		switch v := c.(type) {
+		case string:
+			b.WriteString(escapeText(v))
+		case procInst:
+			b.WriteString("<?" + v.Target)
+			if len(v.Inst) > 0 {
+				b.WriteString(" " + v.Inst)
+			}
+			b.WriteString("?>")
+		case *node:
+			if v != excluded {
+				writeCanonical(b, v, scope, inclusive, excluded)
+			}
+		}
+	}
+	b.WriteString("</" + qname + ">")
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;",
+		"\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
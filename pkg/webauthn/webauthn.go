@@ -0,0 +1,346 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package webauthn is the relying party of Web Authentication, for security keys and passkeys.
+// It verifies registrations with the "none" attestation conveyance: the attestation statement
+// is not checked, so the authenticator model is not trusted, only the possession of the key.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Timeout the time in milliseconds the browser waits for the user
+const Timeout = 120000
+
+const (
+	flagUserPresent      = 0x01
+	flagAttestedCredData = 0x40
+)
+
+// RelyingParty the site the credentials are scoped to
+type RelyingParty struct {
+	// ID the effective domain of the site, such as example.com
+	ID string
+	// Name the name shown by the browser
+	Name string
+	// Origin the origin of the site, such as https://example.com
+	Origin string
+}
+
+// User the account a credential is registered for
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+// Credential a registered public key credential
+type Credential struct {
+	ID        []byte
+	PublicKey []byte
+	SignCount uint32
+}
+
+// CredentialDescriptor refer to a registered credential in the options
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// CreationOptions the PublicKeyCredentialCreationOptions in its JSON form
+type CreationOptions struct {
+	RP struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Challenge        string `json:"challenge"`
+	PubKeyCredParams []struct {
+		Type string `json:"type"`
+		Alg  int    `json:"alg"`
+	} `json:"pubKeyCredParams"`
+	Timeout                int                     `json:"timeout"`
+	ExcludeCredentials     []*CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection struct {
+		ResidentKey      string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+	Attestation string `json:"attestation"`
+}
+
+// RequestOptions the PublicKeyCredentialRequestOptions in its JSON form
+type RequestOptions struct {
+	Challenge        string                  `json:"challenge"`
+	RPID             string                  `json:"rpId"`
+	Timeout          int                     `json:"timeout"`
+	AllowCredentials []*CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                  `json:"userVerification"`
+}
+
+// AttestationResponse the JSON form of the credential returned by navigator.credentials.create()
+type AttestationResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AttestationObject string `json:"attestationObject"`
+	} `json:"response"`
+}
+
+// AssertionResponse the JSON form of the credential returned by navigator.credentials.get()
+type AssertionResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+// NewChallenge generate a random challenge, encoded with base64url
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return EncodeID(b), nil
+}
+
+// EncodeID encode the binary id with base64url, as the JSON forms do
+func EncodeID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// DecodeID decode the base64url value, the padding is optional
+func DecodeID(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// CreationOptions the options to register a new credential of the user
+func (rp *RelyingParty) CreationOptions(challenge string, user *User, exclude [][]byte) *CreationOptions {
+	opts := &CreationOptions{Challenge: challenge, Timeout: Timeout, Attestation: "none"}
+	opts.RP.ID, opts.RP.Name = rp.ID, rp.Name
+	opts.User.ID, opts.User.Name, opts.User.DisplayName = EncodeID(user.ID), user.Name, user.DisplayName
+	for _, alg := range []int{AlgES256, AlgRS256} {
+		opts.PubKeyCredParams = append(opts.PubKeyCredParams, struct {
+			Type string `json:"type"`
+			Alg  int    `json:"alg"`
+		}{Type: "public-key", Alg: alg})
+	}
+	opts.ExcludeCredentials = descriptors(exclude)
+	opts.AuthenticatorSelection.ResidentKey = "preferred"
+	opts.AuthenticatorSelection.UserVerification = "preferred"
+	return opts
+}
+
+// RequestOptions the options to authenticate with one of the allowed credentials
+func (rp *RelyingParty) RequestOptions(challenge string, allow [][]byte) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		RPID:             rp.ID,
+		Timeout:          Timeout,
+		AllowCredentials: descriptors(allow),
+		UserVerification: "preferred",
+	}
+}
+
+// VerifyRegistration verify the new credential was created for this site in response to the challenge
+func (rp *RelyingParty) VerifyRegistration(challenge string, resp *AttestationResponse) (*Credential, error) {
+	if resp == nil || resp.Type != "public-key" {
+		return nil, errors.New("webauthn: invalid credential type")
+	}
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+	raw, err := DecodeID(resp.Response.AttestationObject)
+	if err != nil {
+		return nil, errors.New("webauthn: invalid attestation object encoding")
+	}
+	item, _, err := decodeCBOR(raw)
+	if err != nil {
+		return nil, err
+	}
+	attestation, ok := item.(map[any]any)
+	if !ok {
+		return nil, errors.New("webauthn: attestation object is not a map")
+	}
+	authDataRaw, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("webauthn: attestation object without authenticator data")
+	}
+	authData, err := rp.parseAuthenticatorData(authDataRaw)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCredData == 0 || len(authData.credentialID) == 0 {
+		return nil, errors.New("webauthn: no attested credential data")
+	}
+	if rawID, err := DecodeID(resp.RawID); err != nil || !bytes.Equal(rawID, authData.credentialID) {
+		return nil, errors.New("webauthn: credential id mismatch")
+	}
+	if _, err = parsePublicKey(authData.publicKey); err != nil {
+		return nil, err
+	}
+	return &Credential{
+		ID:        authData.credentialID,
+		PublicKey: authData.publicKey,
+		SignCount: authData.signCount,
+	}, nil
+}
+
+// VerifyAssertion verify the assertion was signed by the credential in response to the challenge,
+// and return the new signature counter to be saved.
+func (rp *RelyingParty) VerifyAssertion(challenge string, resp *AssertionResponse, cred *Credential) (
+	signCount uint32, err error) {
+	if resp == nil || resp.Type != "public-key" {
+		return 0, errors.New("webauthn: invalid credential type")
+	}
+	if rawID, err := DecodeID(resp.RawID); err != nil || !bytes.Equal(rawID, cred.ID) {
+		return 0, errors.New("webauthn: credential id mismatch")
+	}
+	if err = rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+	clientDataJSON, _ := DecodeID(resp.Response.ClientDataJSON)
+	authDataRaw, err := DecodeID(resp.Response.AuthenticatorData)
+	if err != nil {
+		return 0, errors.New("webauthn: invalid authenticator data encoding")
+	}
+	authData, err := rp.parseAuthenticatorData(authDataRaw)
+	if err != nil {
+		return 0, err
+	}
+	sig, err := DecodeID(resp.Response.Signature)
+	if err != nil {
+		return 0, errors.New("webauthn: invalid signature encoding")
+	}
+	key, err := parsePublicKey(cred.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err = key.verify(append(authDataRaw[:len(authDataRaw):len(authDataRaw)], clientDataHash[:]...), sig); err != nil {
+		return 0, err
+	}
+	// A counter which does not grow is the sign of a cloned authenticator.
+	// Authenticators without a counter always report zero.
+	if (authData.signCount != 0 || cred.SignCount != 0) && authData.signCount <= cred.SignCount {
+		return 0, errors.New("webauthn: signature counter did not increase")
+	}
+	return authData.signCount, nil
+}
+
+func (rp *RelyingParty) verifyClientData(encoded, typ, challenge string) error {
+	raw, err := DecodeID(encoded)
+	if err != nil {
+		return errors.New("webauthn: invalid client data encoding")
+	}
+	cd := &clientData{}
+	if err = json.Unmarshal(raw, cd); err != nil {
+		return errors.New("webauthn: invalid client data")
+	}
+	if cd.Type != typ {
+		return errors.New("webauthn: unexpected client data type")
+	}
+	if len(challenge) == 0 ||
+		subtle.ConstantTimeCompare([]byte(strings.TrimRight(cd.Challenge, "=")), []byte(challenge)) != 1 {
+		return errors.New("webauthn: challenge mismatch")
+	}
+	if cd.Origin != rp.Origin {
+		return errors.New("webauthn: origin mismatch")
+	}
+	return nil
+}
+
+func (rp *RelyingParty) parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, errors.New("webauthn: authenticator data too short")
+	}
+	ad := &authenticatorData{
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(ad.rpIDHash, rpIDHash[:]) != 1 {
+		return nil, errors.New("webauthn: relying party id mismatch")
+	}
+	if ad.flags&flagUserPresent == 0 {
+		return nil, errors.New("webauthn: user not present")
+	}
+	if ad.flags&flagAttestedCredData == 0 {
+		return ad, nil
+	}
+	rest := raw[37:]
+	if len(rest) < 18 {
+		return nil, errors.New("webauthn: attested credential data too short")
+	}
+	idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+		return nil, errors.New("webauthn: invalid credential id")
+	}
+	ad.credentialID = rest[:idLen]
+	rest = rest[idLen:]
+	_, extra, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, err
+	}
+	ad.publicKey = rest[:len(rest)-len(extra)]
+	return ad, nil
+}
+
+func descriptors(ids [][]byte) []*CredentialDescriptor {
+	list := make([]*CredentialDescriptor, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, &CredentialDescriptor{Type: "public-key", ID: EncodeID(id)})
+	}
+	return list
+}
@@ -0,0 +1,207 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRP = &RelyingParty{ID: "example.com", Name: "Example", Origin: "https://example.com"}
+
+// encodeCBOR the minimal encoder needed to build authenticator responses
+func encodeCBOR(v any) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 256:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			b := []byte{major<<5 | 25, 0, 0}
+			binary.BigEndian.PutUint16(b[1:], uint16(n))
+			return b
+		}
+	}
+	switch t := v.(type) {
+	case int:
+		if t < 0 {
+			return head(1, uint64(-1-t))
+		}
+		return head(0, uint64(t))
+	case []byte:
+		return append(head(2, uint64(len(t))), t...)
+	case string:
+		return append(head(3, uint64(len(t))), t...)
+	case [][2]any:
+		out := head(5, uint64(len(t)))
+		for _, kv := range t {
+			out = append(out, encodeCBOR(kv[0])...)
+			out = append(out, encodeCBOR(kv[1])...)
+		}
+		return out
+	}
+	panic("unsupported")
+}
+
+type authenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	signCount uint32
+}
+
+func newAuthenticator(t *testing.T) *authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &authenticator{key: key, id: []byte("credential-1")}
+}
+
+func (a *authenticator) coseKey() []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	return encodeCBOR([][2]any{{1, 2}, {3, -7}, {-1, 1}, {-2, x}, {-3, y}})
+}
+
+func (a *authenticator) authData(rpID string, attested bool) []byte {
+	hash := sha256.Sum256([]byte(rpID))
+	buf := bytes.NewBuffer(hash[:])
+	flags := byte(flagUserPresent)
+	if attested {
+		flags |= flagAttestedCredData
+	}
+	buf.WriteByte(flags)
+	_ = binary.Write(buf, binary.BigEndian, a.signCount)
+	if attested {
+		buf.Write(make([]byte, 16))
+		_ = binary.Write(buf, binary.BigEndian, uint16(len(a.id)))
+		buf.Write(a.id)
+		buf.Write(a.coseKey())
+	}
+	return buf.Bytes()
+}
+
+func clientDataJSON(typ, challenge, origin string) []byte {
+	b, _ := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": origin})
+	return b
+}
+
+func (a *authenticator) create(challenge, origin string) *AttestationResponse {
+	resp := &AttestationResponse{ID: EncodeID(a.id), RawID: EncodeID(a.id), Type: "public-key"}
+	resp.Response.ClientDataJSON = EncodeID(clientDataJSON("webauthn.create", challenge, origin))
+	resp.Response.AttestationObject = EncodeID(encodeCBOR([][2]any{
+		{"fmt", "none"}, {"attStmt", [][2]any{}}, {"authData", a.authData(testRP.ID, true)},
+	}))
+	return resp
+}
+
+func (a *authenticator) get(t *testing.T, challenge string) *AssertionResponse {
+	a.signCount++
+	authData := a.authData(testRP.ID, false)
+	cd := clientDataJSON("webauthn.get", challenge, testRP.Origin)
+	hash := sha256.Sum256(cd)
+	digest := sha256.Sum256(append(authData, hash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+	resp := &AssertionResponse{ID: EncodeID(a.id), RawID: EncodeID(a.id), Type: "public-key"}
+	resp.Response.ClientDataJSON = EncodeID(cd)
+	resp.Response.AuthenticatorData = EncodeID(authData)
+	resp.Response.Signature = EncodeID(sig)
+	return resp
+}
+
+func TestRegisterAndAssert(t *testing.T) {
+	a := newAuthenticator(t)
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	cred, err := testRP.VerifyRegistration(challenge, a.create(challenge, testRP.Origin))
+	require.NoError(t, err)
+	assert.Equal(t, a.id, cred.ID)
+
+	challenge, _ = NewChallenge()
+	assertion := a.get(t, challenge)
+	signCount, err := testRP.VerifyAssertion(challenge, assertion, cred)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), signCount)
+
+	// replaying the same assertion is refused by the counter
+	cred.SignCount = signCount
+	_, err = testRP.VerifyAssertion(challenge, assertion, cred)
+	assert.Error(t, err)
+
+	// the assertion of another challenge
+	other, _ := NewChallenge()
+	_, err = testRP.VerifyAssertion(other, a.get(t, challenge), cred)
+	assert.Error(t, err)
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	a := newAuthenticator(t)
+	challenge, _ := NewChallenge()
+
+	_, err := testRP.VerifyRegistration(challenge, a.create(challenge, "https://evil.example"))
+	assert.Error(t, err)
+
+	other, _ := NewChallenge()
+	_, err = testRP.VerifyRegistration(other, a.create(challenge, testRP.Origin))
+	assert.Error(t, err)
+
+	otherRP := &RelyingParty{ID: "other.com", Origin: testRP.Origin}
+	_, err = otherRP.VerifyRegistration(challenge, a.create(challenge, testRP.Origin))
+	assert.Error(t, err)
+}
+
+func TestVerifyAssertionTampered(t *testing.T) {
+	a := newAuthenticator(t)
+	challenge, _ := NewChallenge()
+	cred, err := testRP.VerifyRegistration(challenge, a.create(challenge, testRP.Origin))
+	require.NoError(t, err)
+
+	assertion := a.get(t, challenge)
+	authData, _ := DecodeID(assertion.Response.AuthenticatorData)
+	authData[36]++
+	assertion.Response.AuthenticatorData = EncodeID(authData)
+	_, err = testRP.VerifyAssertion(challenge, assertion, cred)
+	assert.Error(t, err)
+
+	stranger := newAuthenticator(t)
+	_, err = testRP.VerifyAssertion(challenge, stranger.get(t, challenge), cred)
+	assert.Error(t, err)
+}
+
+func TestDecodeCBORRejectsTruncated(t *testing.T) {
+	_, _, err := decodeCBOR([]byte{0x5a, 0xff, 0xff, 0xff, 0xff})
+	assert.Error(t, err)
+	_, _, err = decodeCBOR([]byte{0x9f})
+	assert.Error(t, err)
+	deep := bytes.Repeat([]byte{0x81}, 100)
+	_, _, err = decodeCBOR(append(deep, 0x00))
+	assert.Error(t, err)
+}
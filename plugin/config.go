@@ -19,6 +19,12 @@
 
 package plugin
 
+import (
+	"fmt"
+
+	"github.com/apache/answer/internal/entity"
+)
+
 type ConfigType string
 type InputType string
 
@@ -128,6 +134,43 @@ type Config interface {
 	ConfigReceiver(config []byte) error
 }
 
+// TenantConfig is an optional interface of the Config plugin, implement it to keep a config for each tenant.
+// The config of the primary site is still received by ConfigReceiver.
+type TenantConfig interface {
+	// TenantConfigFields returns the list of config fields with the values of the tenant
+	TenantConfigFields(tenantID int) []ConfigField
+
+	// TenantConfigReceiver receives the config data of the tenant, it calls when the config of the tenant is saved or initialized.
+	TenantConfigReceiver(tenantID int, config []byte) error
+}
+
+// ErrTenantConfigUnsupported is returned when the config of a tenant is used but the plugin does not implement TenantConfig
+var ErrTenantConfigUnsupported = fmt.Errorf("the plugin does not support the tenant config")
+
+// GetConfigFields returns the config fields of the plugin for the tenant
+func GetConfigFields(tenantID int, fn Config) ([]ConfigField, error) {
+	if tenantID == entity.DefaultTenantID {
+		return fn.ConfigFields(), nil
+	}
+	tc, ok := fn.(TenantConfig)
+	if !ok {
+		return nil, ErrTenantConfigUnsupported
+	}
+	return tc.TenantConfigFields(tenantID), nil
+}
+
+// ReceiveConfig passes the config of the tenant to the plugin
+func ReceiveConfig(tenantID int, fn Config, config []byte) error {
+	if tenantID == entity.DefaultTenantID {
+		return fn.ConfigReceiver(config)
+	}
+	tc, ok := fn.(TenantConfig)
+	if !ok {
+		return ErrTenantConfigUnsupported
+	}
+	return tc.TenantConfigReceiver(tenantID, config)
+}
+
 var (
 	// CallConfig is a function that calls all registered config plugins
 	CallConfig,
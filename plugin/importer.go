@@ -21,13 +21,110 @@ package plugin
 
 import (
 	"context"
+	"time"
 )
 
+// ImportUser is the author of the imported content. It is matched by the external id first and
+// then by the email, and a new user is created if none is found.
+type ImportUser struct {
+	ExternalID  string `json:"external_id"`
+	Email       string `json:"email"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	Website     string `json:"website"`
+	Location    string `json:"location"`
+	// Reputation is the reputation of the user in the source system, it is only used for new users
+	Reputation int `json:"reputation"`
+}
+
+type TagImporterInfo struct {
+	SlugName    string `json:"slug_name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+}
+
+// ImportRevision is a previous version of the imported post
+type ImportRevision struct {
+	Title     string      `json:"title"`
+	Content   string      `json:"content"`
+	Tags      []string    `json:"tags"`
+	Log       string      `json:"log"`
+	User      *ImportUser `json:"user"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// ImportAttachment is a file referenced by the imported post. The file is saved in the upload
+// path and the URL in the content is replaced by the new one.
+type ImportAttachment struct {
+	URL      string `json:"url"`
+	FileName string `json:"file_name"`
+	Data     []byte `json:"data"`
+}
+
 type QuestionImporterInfo struct {
-	Title     string   `json:"title"`
-	Content   string   `json:"content"`
-	Tags      []string `json:"tags"`
-	UserEmail string   `json:"user_email"`
+	// ExternalID is the id of the question in the source system, the question is imported only once
+	ExternalID string   `json:"external_id"`
+	Title      string   `json:"title"`
+	Content    string   `json:"content"`
+	HTML       string   `json:"html"`
+	Tags       []string `json:"tags"`
+	// Deprecated: use User instead
+	UserEmail string      `json:"user_email"`
+	User      *ImportUser `json:"user"`
+	ViewCount int         `json:"view_count"`
+	// VoteCount is the score of the question in the source system
+	VoteCount int `json:"vote_count"`
+	// LastEditUser is the editor of the current version, the author is used if it is empty
+	LastEditUser *ImportUser        `json:"last_edit_user"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	Revisions    []ImportRevision   `json:"revisions"`
+	Attachments  []ImportAttachment `json:"attachments"`
+}
+
+type AnswerImporterInfo struct {
+	ExternalID         string             `json:"external_id"`
+	QuestionExternalID string             `json:"question_external_id"`
+	Content            string             `json:"content"`
+	HTML               string             `json:"html"`
+	User               *ImportUser        `json:"user"`
+	LastEditUser       *ImportUser        `json:"last_edit_user"`
+	Accepted           bool               `json:"accepted"`
+	VoteCount          int                `json:"vote_count"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	Revisions          []ImportRevision   `json:"revisions"`
+	Attachments        []ImportAttachment `json:"attachments"`
+}
+
+// ImportObjectType the type of the imported post which is commented or voted
+type ImportObjectType string
+
+const (
+	ImportObjectTypeQuestion ImportObjectType = "question"
+	ImportObjectTypeAnswer   ImportObjectType = "answer"
+)
+
+type CommentImporterInfo struct {
+	ExternalID       string           `json:"external_id"`
+	ObjectExternalID string           `json:"object_external_id"`
+	ObjectType       ImportObjectType `json:"object_type"`
+	// ReplyExternalID is the external id of the comment which is replied to
+	ReplyExternalID string      `json:"reply_external_id"`
+	Content         string      `json:"content"`
+	User            *ImportUser `json:"user"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// VoteImporterInfo is a vote of the user, a user votes the same post only once
+// and the reputation of the users is not changed by the imported votes
+type VoteImporterInfo struct {
+	ObjectExternalID string           `json:"object_external_id"`
+	ObjectType       ImportObjectType `json:"object_type"`
+	User             *ImportUser      `json:"user"`
+	VoteUp           bool             `json:"vote_up"`
+	CreatedAt        time.Time        `json:"created_at"`
 }
 
 type Importer interface {
@@ -36,7 +133,12 @@ type Importer interface {
 }
 
 type ImporterFunc interface {
+	AddUser(ctx context.Context, userInfo ImportUser) (err error)
+	AddTag(ctx context.Context, tagInfo TagImporterInfo) (err error)
 	AddQuestion(ctx context.Context, questionInfo QuestionImporterInfo) (err error)
+	AddAnswer(ctx context.Context, answerInfo AnswerImporterInfo) (err error)
+	AddComment(ctx context.Context, commentInfo CommentImporterInfo) (err error)
+	AddVote(ctx context.Context, voteInfo VoteImporterInfo) (err error)
 }
 
 var (
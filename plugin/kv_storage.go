@@ -25,6 +25,7 @@ import (
 	"math/rand/v2"
 	"time"
 
+	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/entity"
 	"github.com/segmentfault/pacman/cache"
 	"github.com/segmentfault/pacman/log"
@@ -55,7 +56,8 @@ type KVParams struct {
 	PageSize int
 }
 
-// KVOperator provides methods to interact with the key-value storage system for plugins
+// KVOperator provides methods to interact with the key-value storage system for plugins,
+// the data is kept for the tenant of the context
 type KVOperator struct {
 	data           *Data
 	session        *xorm.Session
@@ -151,6 +153,7 @@ func (kv *KVOperator) Get(ctx context.Context, params KVParams) (string, error)
 	defer cleanup()
 
 	query.Where(builder.Eq{
+		"tenant_id":        handler.GetTenantID(ctx),
 		"plugin_slug_name": kv.pluginSlugName,
 		"`group`":          params.Group,
 		"`key`":            params.Key,
@@ -181,6 +184,7 @@ func (kv *KVOperator) Set(ctx context.Context, params KVParams) error {
 	defer cleanup()
 
 	data := &entity.PluginKVStorage{
+		TenantID:       handler.GetTenantID(ctx),
 		PluginSlugName: kv.pluginSlugName,
 		Group:          params.Group,
 		Key:            params.Key,
@@ -190,6 +194,7 @@ func (kv *KVOperator) Set(ctx context.Context, params KVParams) error {
 	kv.cleanCache(ctx, params)
 
 	affected, err := query.Where(builder.Eq{
+		"tenant_id":        data.TenantID,
 		"plugin_slug_name": kv.pluginSlugName,
 		"`group`":          params.Group,
 		"`key`":            params.Key,
@@ -222,6 +227,7 @@ func (kv *KVOperator) Del(ctx context.Context, params KVParams) error {
 	defer cleanup()
 
 	session.Where(builder.Eq{
+		"tenant_id":        handler.GetTenantID(ctx),
 		"plugin_slug_name": kv.pluginSlugName,
 	})
 	if params.Group != "" {
@@ -253,7 +259,7 @@ func (kv *KVOperator) GetByGroup(ctx context.Context, params KVParams) (map[stri
 	defer cleanup()
 
 	var items []entity.PluginKVStorage
-	err := query.Where(builder.Eq{"plugin_slug_name": kv.pluginSlugName, "`group`": params.Group}).
+	err := query.Where(builder.Eq{"tenant_id": handler.GetTenantID(ctx), "plugin_slug_name": kv.pluginSlugName, "`group`": params.Group}).
 		Limit(params.PageSize, (params.Page-1)*params.PageSize).
 		OrderBy("id ASC").
 		Find(&items)
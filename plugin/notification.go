@@ -43,6 +43,10 @@ const (
 	NotificationInvitedYouToAnswer     NotificationType = "notification.action.invited_you_to_answer"
 	NotificationNewQuestion            NotificationType = "notification.action.new_question"
 	NotificationNewQuestionFollowedTag NotificationType = "notification.action.new_question_followed_tag"
+	NotificationSavedSearchMatched     NotificationType = "notification.action.saved_search_matched"
+	NotificationNewQuestionInOwnedTag  NotificationType = "notification.action.new_question_in_owned_tag"
+	NotificationBountyAwarded          NotificationType = "notification.action.bounty_awarded"
+	NotificationYourBountyExpired      NotificationType = "notification.action.your_bounty_expired"
 )
 
 type Notification interface {
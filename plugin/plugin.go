@@ -20,6 +20,7 @@
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"sync"
 
@@ -29,6 +30,7 @@ import (
 
 	"github.com/apache/answer/internal/base/handler"
 	"github.com/apache/answer/internal/base/translator"
+	"github.com/apache/answer/internal/entity"
 	"github.com/gin-gonic/gin"
 )
 
@@ -51,6 +53,11 @@ var StatusManager = statusManager{
 	status: make(map[string]bool),
 }
 
+// GetTenantID returns the tenant of the context, the plugins keep the data of each tenant apart by it
+func GetTenantID(ctx context.Context) int {
+	return handler.GetTenantID(ctx)
+}
+
 // Register registers a plugin
 func Register(p Base) {
 	registerBase(p)
@@ -181,23 +188,45 @@ func MakePlugin[T Base](super bool) (CallFn[T], RegisterFn[T]) {
 type statusManager struct {
 	lock   sync.Mutex
 	status map[string]bool
+	// tenants the status of the plugins set by each tenant, a tenant can only use the plugins enabled by the primary site
+	tenants map[int]map[string]bool
 }
 
 func (m *statusManager) Enable(name string, enabled bool) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
+	enableStatus(m.status, name, enabled)
+}
+
+// EnableForTenant enable or disable the plugin for the tenant only
+func (m *statusManager) EnableForTenant(tenantID int, name string, enabled bool) {
+	if tenantID == entity.DefaultTenantID {
+		m.Enable(name, enabled)
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.tenants == nil {
+		m.tenants = make(map[int]map[string]bool)
+	}
+	if m.tenants[tenantID] == nil {
+		m.tenants[tenantID] = make(map[string]bool)
+	}
+	enableStatus(m.tenants[tenantID], name, enabled)
+}
+
+func enableStatus(status map[string]bool, name string, enabled bool) {
+	status[name] = enabled
 	if !enabled {
-		m.status[name] = enabled
 		return
 	}
-	m.status[name] = enabled
 
 	for _, slugName := range coordinatedCaptchaPlugins(name) {
-		m.status[slugName] = false
+		status[slugName] = false
 	}
 
 	for _, slugName := range coordinatedCDNPlugins(name) {
-		m.status[slugName] = false
+		status[slugName] = false
 	}
 }
 
@@ -208,16 +237,74 @@ func (m *statusManager) IsEnabled(name string) bool {
 	return false
 }
 
+// IsEnabledForTenant whether the plugin is enabled for the tenant,
+// the tenant uses the status of the primary site until it sets its own.
+func (m *statusManager) IsEnabledForTenant(tenantID int, name string) bool {
+	if !m.IsEnabled(name) {
+		return false
+	}
+	if tenantID == entity.DefaultTenantID {
+		return true
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if status, ok := m.tenants[tenantID][name]; ok {
+		return status
+	}
+	return true
+}
+
+// IsEnabledFor whether the plugin is enabled for the tenant of the context
+func (m *statusManager) IsEnabledFor(ctx context.Context, name string) bool {
+	return m.IsEnabledForTenant(handler.GetTenantID(ctx), name)
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (m *statusManager) MarshalJSON() ([]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	return json.Marshal(m.status)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (m *statusManager) UnmarshalJSON(data []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	return json.Unmarshal(data, &m.status)
 }
 
+// MarshalTenantJSON dump the status of the plugins set by the tenant
+func (m *statusManager) MarshalTenantJSON(tenantID int) ([]byte, error) {
+	if tenantID == entity.DefaultTenantID {
+		return m.MarshalJSON()
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	status := m.tenants[tenantID]
+	if status == nil {
+		status = make(map[string]bool)
+	}
+	return json.Marshal(status)
+}
+
+// UnmarshalTenantJSON load the status of the plugins set by the tenant
+func (m *statusManager) UnmarshalTenantJSON(tenantID int, data []byte) error {
+	if tenantID == entity.DefaultTenantID {
+		return m.UnmarshalJSON(data)
+	}
+	status := make(map[string]bool)
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.tenants == nil {
+		m.tenants = make(map[int]map[string]bool)
+	}
+	m.tenants[tenantID] = status
+	return nil
+}
+
 // Translate translates the key to the current language of the context
 func Translate(ctx *GinContext, key string) string {
 	return translator.Tr(handler.GetLangByCtx(ctx), key)
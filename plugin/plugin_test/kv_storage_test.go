@@ -114,7 +114,7 @@ func setupTestEnvironment() {
 	// Initialize plugin data, refer to plugin_common_service.go implementation
 	_ = plugin.CallKVStorage(func(k plugin.KVStorage) error {
 		k.SetOperator(plugin.NewKVOperator(
-			testDataSource.DB,
+			testDataSource.DB.Engine,
 			testDataSource.Cache,
 			k.Info().SlugName,
 		))
@@ -119,7 +119,7 @@ func initTestDataSource(dbSetting TestDBSetting) error {
 		return err
 	}
 
-	newData, dbCleanUp, err := data.NewData(dbEngine, newCache)
+	newData, dbCleanUp, err := data.NewData(dbEngine, newCache, nil)
 	if err != nil {
 		return err
 	}
@@ -21,6 +21,7 @@ package plugin
 
 import (
 	"context"
+	"time"
 )
 
 type SearchResult struct {
@@ -31,6 +32,8 @@ type SearchResult struct {
 }
 
 type SearchContent struct {
+	// TenantID the tenant of the object, the plugin keeps it with the content to match SearchBasicCond.TenantID
+	TenantID    int                 `json:"tenantID"`
 	ObjectID    string              `json:"objectID"`
 	Title       string              `json:"title"`
 	Type        string              `json:"type"`
@@ -48,6 +51,9 @@ type SearchContent struct {
 }
 
 type SearchBasicCond struct {
+	// TenantID only the contents of this tenant must be returned.
+	TenantID int
+
 	// From zero-based page number
 	Page int
 	// Page size
@@ -76,8 +82,107 @@ type SearchBasicCond struct {
 	ViewAmount int
 	// greater than or equal to the number of answers. Only support search question.
 	AnswerAmount int
+
+	// Expr is the boolean expression parsed from the search query, nil means no keyword or tag limit.
+	// Words and TagIDs are flattened from Expr for the plugins that do not support the expression,
+	// Words contains all positive terms and phrases, TagIDs contains the tags that must be matched.
+	Expr *SearchExpr
+	// NotTagIDs the object must not have any of these tags, each group is a tag ID and its synonym tag IDs.
+	NotTagIDs [][]string
+	// Weathers the question is closed or not. Only support search question.
+	QuestionClosed SearchClosedCond
+	// The time range of the object created.
+	Created SearchTimeRange
+	// The time range of the object last updated.
+	Updated SearchTimeRange
+	// Lang is the language of the fenced code block in the content, such as "go".
+	Lang string
+}
+
+// SearchExpr is a node of the boolean search expression. For example, the query
+// `(mysql OR postgres) "connection pool" -[deprecated]` is parsed as:
+//
+//	and
+//	├── or
+//	│   ├── term: mysql
+//	│   └── term: postgres
+//	├── phrase: connection pool
+//	└── not
+//	    └── tag: deprecated
+type SearchExpr struct {
+	Type SearchExprType `json:"type"`
+	// Children of the and/or/not node, the not node has exactly one child.
+	Children []*SearchExpr `json:"children,omitempty"`
+	// Value is the keyword of the term node, the text of the phrase node or the slug name of the tag node.
+	Value string `json:"value,omitempty"`
+	// TagIDs is the tag ID and its synonym tag IDs of the tag node.
+	TagIDs []string `json:"tag_ids,omitempty"`
+}
+
+// SearchTimeRange is a time range in unix seconds, From is inclusive and To is exclusive. Zero means no limit.
+type SearchTimeRange struct {
+	From int64
+	To   int64
+}
+
+// IsZero check if the time range has no limit
+func (r SearchTimeRange) IsZero() bool {
+	return r.From == 0 && r.To == 0
 }
 
+// SearchFacets is the aggregation of all the contents matched by the search condition.
+type SearchFacets struct {
+	// Total the count of matched contents
+	Total int64
+	// Accepted the count of contents whose question has an accepted answer
+	Accepted int64
+	// Answered the count of contents whose question has at least one answer
+	Answered int64
+	// Tags the top tags of matched contents ordered by count, the value is the tag ID
+	Tags []SearchFacetCount
+	// Users the top authors of matched contents ordered by count, the value is the user ID
+	Users []SearchFacetCount
+	// Created the count of contents created in each date bucket, the value is the SearchDateBucket
+	Created []SearchFacetCount
+}
+
+type SearchFacetCount struct {
+	Value string
+	Count int64
+}
+
+// SearchDateBucket is a relative date range ending now, it can be used in the query like created:1w
+type SearchDateBucket string
+
+const (
+	SearchDateBucketDay   SearchDateBucket = "1d"
+	SearchDateBucketWeek  SearchDateBucket = "1w"
+	SearchDateBucketMonth SearchDateBucket = "1m"
+	SearchDateBucketYear  SearchDateBucket = "1y"
+)
+
+// SearchDateBuckets all the date buckets of the created facet, from the shortest to the longest
+var SearchDateBuckets = []SearchDateBucket{
+	SearchDateBucketDay, SearchDateBucketWeek, SearchDateBucketMonth, SearchDateBucketYear,
+}
+
+// Since get the start time of the date bucket
+func (b SearchDateBucket) Since(now time.Time) time.Time {
+	switch b {
+	case SearchDateBucketDay:
+		return now.AddDate(0, 0, -1)
+	case SearchDateBucketWeek:
+		return now.AddDate(0, 0, -7)
+	case SearchDateBucketMonth:
+		return now.AddDate(0, -1, 0)
+	default:
+		return now.AddDate(-1, 0, 0)
+	}
+}
+
+type SearchExprType string
+type SearchClosedCond int
+
 type SearchAcceptedCond int
 type SearchContentStatus int
 type SearchOrderCond string
@@ -88,6 +193,21 @@ const (
 	AcceptedCondFalse
 )
 
+const (
+	ClosedCondAll SearchClosedCond = iota
+	ClosedCondTrue
+	ClosedCondFalse
+)
+
+const (
+	SearchExprAnd    SearchExprType = "and"
+	SearchExprOr     SearchExprType = "or"
+	SearchExprNot    SearchExprType = "not"
+	SearchExprTerm   SearchExprType = "term"
+	SearchExprPhrase SearchExprType = "phrase"
+	SearchExprTag    SearchExprType = "tag"
+)
+
 const (
 	SearchContentStatusAvailable = 1
 	SearchContentStatusDeleted   = 10
@@ -111,6 +231,13 @@ type Search interface {
 	DeleteContent(ctx context.Context, objectID string) (err error)
 }
 
+// SearchFacetFinder is an optional interface of the Search plugin, implement it to provide the search facets.
+type SearchFacetFinder interface {
+	// SearchFacets aggregate the contents of the object type matched by the condition,
+	// the object type is "question" or "answer", empty means all contents.
+	SearchFacets(ctx context.Context, objectType string, cond *SearchBasicCond) (facets *SearchFacets, err error)
+}
+
 type SearchDesc struct {
 	// A svg icon it wil be display in search result page. optional
 	Icon string `json:"icon"`
@@ -118,6 +245,7 @@ type SearchDesc struct {
 	Link string `json:"link"`
 }
 
+// SearchSyncer returns the contents of all tenants, each content has its tenant id
 type SearchSyncer interface {
 	GetAnswersPage(ctx context.Context, page, pageSize int) (answerList []*SearchContent, err error)
 	GetQuestionsPage(ctx context.Context, page, pageSize int) (questionList []*SearchContent, err error)
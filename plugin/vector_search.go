@@ -42,6 +42,8 @@ type VectorSearchResult struct {
 
 // VectorSearchContent is the document structure passed to plugins for indexing.
 type VectorSearchContent struct {
+	// TenantID is the tenant of the object, the plugin keeps it with the document to filter the search results.
+	TenantID int `json:"tenantID"`
 	// ObjectID is the unique identifier (question ID or answer ID).
 	ObjectID string `json:"objectID"`
 	// ObjectType is "question" or "answer".
@@ -65,6 +67,7 @@ type VectorSearchDesc struct {
 // VectorSearchMetadata holds IDs for URI composition and content retrieval at query time.
 // Shared between plugins and the core MCP controller.
 type VectorSearchMetadata struct {
+	TenantID   int                           `json:"tenant_id,omitempty"`
 	QuestionID string                        `json:"question_id"`
 	AnswerID   string                        `json:"answer_id,omitempty"`
 	Answers    []VectorSearchMetadataAnswer  `json:"answers,omitempty"`
@@ -98,6 +101,7 @@ type VectorSearch interface {
 
 	// SearchSimilar performs a semantic similarity search.
 	// The plugin is responsible for embedding the query text and searching its vector store.
+	// Only the documents of the tenant of the context (see GetTenantID) should be returned.
 	// Returns up to topK results sorted by similarity score (descending).
 	SearchSimilar(ctx context.Context, query string, topK int) ([]VectorSearchResult, error)
 
@@ -112,9 +116,9 @@ type VectorSearch interface {
 // VectorSearchSyncer is implemented by the core and provided to plugins via RegisterSyncer.
 // Plugins call these methods to pull all content for bulk indexing.
 type VectorSearchSyncer interface {
-	// GetQuestionsPage returns a page of questions with aggregated text (title + body + answers + comments).
+	// GetQuestionsPage returns a page of questions of all tenants with aggregated text (title + body + answers + comments).
 	GetQuestionsPage(ctx context.Context, page, pageSize int) ([]*VectorSearchContent, error)
-	// GetAnswersPage returns a page of answers with aggregated text (answer body + parent question title + comments).
+	// GetAnswersPage returns a page of answers of all tenants with aggregated text (answer body + parent question title + comments).
 	GetAnswersPage(ctx context.Context, page, pageSize int) ([]*VectorSearchContent, error)
 }
 